@@ -622,7 +622,7 @@ func (m *AISbp) GetObjReader(_ context.Context, lom *core.LOM, offset, length in
 }
 
 // TODO: retry upon 'unreachable' or timeout
-func (m *AISbp) PutObj(r io.ReadCloser, lom *core.LOM, _ *http.Request) (ecode int, err error) {
+func (m *AISbp) PutObj(r io.ReadCloser, lom *core.LOM, origReq *http.Request) (ecode int, err error) {
 	var (
 		oah       api.ObjAttrs
 		remAis    *remAis
@@ -642,6 +642,11 @@ func (m *AISbp) PutObj(r io.ReadCloser, lom *core.LOM, _ *http.Request) (ecode i
 		Reader:     r.(cos.ReadOpenCloser),
 		Size:       uint64(size),
 	}
+	if origReq != nil {
+		// active-active replication (see xs.XactRepl)
+		args.ReplVV = origReq.Header.Get(apc.HdrObjReplVV)
+		args.ReplWrite = origReq.Header.Get(apc.HdrObjReplWrite)
+	}
 	if oah, err = api.PutObject(&args); err != nil {
 		ecode, err = extractErrCode(err, remAis.uuid)
 		return
@@ -38,23 +38,27 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 )
 
 type (
 	s3bp struct {
-		t  core.TargetPut
-		mm *memsys.MMSA
+		t   core.TargetPut
+		mm  *memsys.MMSA
+		rgw *rgwAdmin // Ceph RGW admin-ops (optional)
 		base
 	}
 	sessConf struct {
-		bck    *cmn.Bck
-		region string
+		bck           *cmn.Bck
+		region        string
+		pathStyle     bool // feat.S3UsePathStyle
+		requesterPays bool // (see cmn.ExtraPropsAWS)
 	}
 )
 
 var (
 	// map[string]*s3.Client, with one s3.Client a.k.a. "svc"
-	// per (profile, region, endpoint) triplet
+	// per (profile, region, endpoint) triplet and addressing/requester-pays options
 	clients sync.Map
 
 	s3Endpoint string
@@ -62,7 +66,11 @@ var (
 )
 
 // interface guard
-var _ core.Backend = (*s3bp)(nil)
+var (
+	_ core.Backend    = (*s3bp)(nil)
+	_ core.TagBackend = (*s3bp)(nil)
+	_ core.SSEBackend = (*s3bp)(nil)
+)
 
 // environment variables => static defaults that can still be overridden via bck.Props.Extra.AWS
 // in addition to these two (below), default bucket region = env.AwsDefaultRegion()
@@ -74,6 +82,11 @@ func NewAWS(t core.TargetPut, tstats stats.Tracker, startingUp bool) (core.Backe
 		mm:   t.PageMM(),
 		base: base{provider: apc.AWS},
 	}
+	rgw, err := newRGWAdmin()
+	if err != nil {
+		return nil, err
+	}
+	bp.rgw = rgw
 	// register metrics
 	bp.base.init(t.Snode(), tstats, startingUp)
 	// reset clients map
@@ -89,6 +102,12 @@ func NewAWS(t core.TargetPut, tstats stats.Tracker, startingUp bool) (core.Backe
 
 const gotBucketLocation = "got_bucket_location"
 
+// requester-pays buckets
+const (
+	s3HdrRequestPayer = "X-Amz-Request-Payer"
+	s3RequestPayer    = "requester"
+)
+
 func (*s3bp) HeadBucket(_ context.Context, bck *meta.Bck) (bckProps cos.StrKVs, ecode int, _ error) {
 	var (
 		cloudBck = bck.RemoteBck()
@@ -403,11 +422,18 @@ func (*s3bp) ListObjects(bck *meta.Bck, msg *apc.LsoMsg, lst *cmn.LsoRes) (ecode
 // LIST BUCKETS
 //
 
-func (*s3bp) ListBuckets(cmn.QueryBcks) (bcks cmn.Bcks, ecode int, _ error) {
+func (s3bp *s3bp) ListBuckets(cmn.QueryBcks) (bcks cmn.Bcks, ecode int, _ error) {
 	var (
 		sessConf sessConf
 		result   *s3.ListBucketsOutput
 	)
+	if s3bp.rgw != nil {
+		bcks, err := s3bp.rgw.listBuckets()
+		if err == nil {
+			return bcks, 0, nil
+		}
+		nlog.Warningln("rgw admin-ops: failed to list buckets (falling back to S3 ListBuckets):", err)
+	}
 	svc, err := sessConf.s3client("")
 	if err != nil {
 		ecode, err = awsErrorToAISError(err, &cmn.Bck{Provider: apc.AWS}, "")
@@ -627,10 +653,20 @@ func _getCustom(lom *core.LOM, obj *s3.GetObjectOutput) (md5 *cos.Cksum) {
 // PUT OBJECT
 //
 
-func (*s3bp) PutObj(r io.ReadCloser, lom *core.LOM, oreq *http.Request) (ecode int, err error) {
+func (*s3bp) PutObj(r io.ReadCloser, lom *core.LOM, oreq *http.Request) (int, error) {
+	return putObj(r, lom, nil /*sse*/, oreq)
+}
+
+// (core.SSEBackend)
+func (*s3bp) PutObjSSE(r io.ReadCloser, lom *core.LOM, sse *apc.SSEMsg, oreq *http.Request) (int, error) {
+	return putObj(r, lom, sse, oreq)
+}
+
+func putObj(r io.ReadCloser, lom *core.LOM, sse *apc.SSEMsg, oreq *http.Request) (ecode int, err error) {
 	const tag = "[put_object]"
 	var (
 		svc                   *s3.Client
+		input                 *s3.PutObjectInput
 		uploader              *s3manager.Uploader
 		uploadOutput          *s3manager.UploadOutput
 		h                     = cmn.BackendHelpers.Amazon
@@ -639,7 +675,7 @@ func (*s3bp) PutObj(r io.ReadCloser, lom *core.LOM, oreq *http.Request) (ecode i
 		sessConf              = sessConf{bck: cloudBck}
 		md                    = make(map[string]string, 2)
 	)
-	if lom.IsFeatureSet(feat.S3PresignedRequest) && oreq != nil {
+	if lom.IsFeatureSet(feat.S3PresignedRequest) && oreq != nil && sse == nil {
 		q := oreq.URL.Query() // TODO: optimize-out
 		pts := aiss3.NewPresignedReq(oreq, lom, r, q)
 		resp, err := pts.Do(core.T.DataClient())
@@ -663,13 +699,20 @@ func (*s3bp) PutObj(r io.ReadCloser, lom *core.LOM, oreq *http.Request) (ecode i
 	md[cos.S3MetadataChecksumType] = cksumType
 	md[cos.S3MetadataChecksumVal] = cksumValue
 
-	uploader = s3manager.NewUploader(svc)
-	uploadOutput, err = uploader.Upload(context.Background(), &s3.PutObjectInput{
+	input = &s3.PutObjectInput{
 		Bucket:   aws.String(cloudBck.Name),
 		Key:      aws.String(lom.ObjName),
 		Body:     r,
 		Metadata: md,
-	})
+	}
+	if sse != nil {
+		input.ServerSideEncryption = types.ServerSideEncryption(sse.Algorithm())
+		if sse.KMSKey != "" {
+			input.SSEKMSKeyId = aws.String(sse.KMSKey)
+		}
+	}
+	uploader = s3manager.NewUploader(svc)
+	uploadOutput, err = uploader.Upload(context.Background(), input)
 	if err != nil {
 		ecode, err = awsErrorToAISError(err, cloudBck, lom.ObjName)
 		cos.Close(r)
@@ -729,11 +772,76 @@ func (*s3bp) DeleteObj(lom *core.LOM) (ecode int, err error) {
 	return
 }
 
+//
+// object tags (core.TagBackend)
+//
+
+func (*s3bp) GetObjTags(lom *core.LOM) (tags cos.StrKVs, ecode int, err error) {
+	const tag = "[get_object_tagging]"
+	var (
+		svc      *s3.Client
+		out      *s3.GetObjectTaggingOutput
+		cloudBck = lom.Bck().RemoteBck()
+		sessConf = sessConf{bck: cloudBck}
+	)
+	svc, err = sessConf.s3client(tag)
+	if err != nil {
+		return nil, 0, err
+	}
+	out, err = svc.GetObjectTagging(context.Background(), &s3.GetObjectTaggingInput{
+		Bucket: aws.String(cloudBck.Name),
+		Key:    aws.String(lom.ObjName),
+	})
+	if err != nil {
+		ecode, err = awsErrorToAISError(err, cloudBck, lom.ObjName)
+		return nil, ecode, err
+	}
+	if len(out.TagSet) == 0 {
+		return nil, 0, nil
+	}
+	tags = make(cos.StrKVs, len(out.TagSet))
+	for _, t := range out.TagSet {
+		tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	return tags, 0, nil
+}
+
+func (*s3bp) PutObjTags(lom *core.LOM, tags cos.StrKVs) (ecode int, err error) {
+	const tag = "[put_object_tagging]"
+	var (
+		svc      *s3.Client
+		cloudBck = lom.Bck().RemoteBck()
+		sessConf = sessConf{bck: cloudBck}
+		tagSet   = make([]types.Tag, 0, len(tags))
+	)
+	svc, err = sessConf.s3client(tag)
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range tags {
+		tagSet = append(tagSet, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	_, err = svc.PutObjectTagging(context.Background(), &s3.PutObjectTaggingInput{
+		Bucket:  aws.String(cloudBck.Name),
+		Key:     aws.String(lom.ObjName),
+		Tagging: &types.Tagging{TagSet: tagSet},
+	})
+	if err != nil {
+		ecode, err = awsErrorToAISError(err, cloudBck, lom.ObjName)
+		return ecode, err
+	}
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infoln(tag, lom.String(), len(tags))
+	}
+	return 0, nil
+}
+
 //
 // static helpers
 //
 
-// s3client creates or loads an existing S3 client for each triplet of profile/region/endpoint.
+// s3client creates or loads an existing S3 client for each triplet of profile/region/endpoint
+// (and, separately, path-style addressing and requester-pays).
 // Note that each property is configurable per-bucket.
 // From S3 SDK:
 // "S3 methods are safe to use concurrently. It is not safe to modify mutate
@@ -744,6 +852,7 @@ func (sessConf *sessConf) s3client(tag string) (*s3.Client, error) {
 		endpoint = s3Endpoint
 		profile  = awsProfile
 	)
+	sessConf.pathStyle = cmn.Rom.Features().IsSet(feat.S3UsePathStyle)
 	if sessConf.bck != nil && sessConf.bck.Props != nil {
 		if sessConf.region == "" {
 			sessConf.region = sessConf.bck.Props.Extra.AWS.CloudRegion
@@ -754,9 +863,11 @@ func (sessConf *sessConf) s3client(tag string) (*s3.Client, error) {
 		if sessConf.bck.Props.Extra.AWS.Profile != "" {
 			profile = sessConf.bck.Props.Extra.AWS.Profile
 		}
+		sessConf.pathStyle = sessConf.bck.Props.Features.IsSet(feat.S3UsePathStyle)
+		sessConf.requesterPays = sessConf.bck.Props.Extra.AWS.RequesterPays
 	}
 
-	cid := _cid(profile, sessConf.region, endpoint)
+	cid := sessConf.cid(profile, endpoint)
 	asvc, loaded := clients.Load(cid)
 	if loaded {
 		svc, ok := asvc.(*s3.Client)
@@ -782,9 +893,9 @@ func (sessConf *sessConf) s3client(tag string) (*s3.Client, error) {
 		return svc, nil
 	}
 
-	// cache (without recomputing _cid and possibly an empty region)
+	// cache (without recomputing cid and possibly an empty region)
 	if cmn.Rom.FastV(4, cos.SmoduleBackend) {
-		nlog.Infoln("add s3client for tuple (profile, region, endpoint):", cid)
+		nlog.Infoln("add s3client for tuple (profile, region, endpoint, options):", cid)
 	}
 	clients.Store(cid, svc) // race or no race, no particular reason to do LoadOrStore
 	return svc, nil
@@ -796,19 +907,17 @@ func (sessConf *sessConf) options(options *s3.Options) {
 	} else {
 		sessConf.region = options.Region
 	}
-	if bck := sessConf.bck; bck != nil {
-		if bck.Props != nil {
-			options.UsePathStyle = bck.Props.Features.IsSet(feat.S3UsePathStyle)
-		} else {
-			options.UsePathStyle = cmn.Rom.Features().IsSet(feat.S3UsePathStyle)
-		}
+	options.UsePathStyle = sessConf.pathStyle
+	if sessConf.requesterPays {
+		options.APIOptions = append(options.APIOptions, smithyhttp.SetHeaderValue(s3HdrRequestPayer, s3RequestPayer))
 	}
 }
 
-func _cid(profile, region, endpoint string) string {
+func (sessConf *sessConf) cid(profile, endpoint string) string {
 	var (
-		sb strings.Builder
-		l  = len(profile) + 1 + len(region) + 1 + len(endpoint)
+		sb     strings.Builder
+		region = sessConf.region
+		l      = len(profile) + 1 + len(region) + 1 + len(endpoint) + 3
 	)
 	sb.Grow(l)
 	if profile != "" {
@@ -822,6 +931,13 @@ func _cid(profile, region, endpoint string) string {
 	if endpoint != "" {
 		sb.WriteString(endpoint)
 	}
+	sb.WriteByte('#')
+	if sessConf.pathStyle {
+		sb.WriteByte('p')
+	}
+	if sessConf.requesterPays {
+		sb.WriteByte('r')
+	}
 	return sb.String()
 }
 
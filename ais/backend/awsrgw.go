@@ -0,0 +1,187 @@
+//go:build aws
+
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/api/env"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Ceph RGW admin-ops API (optional; enabled via env.RGW.AdminURL):
+// - list buckets: all RGW buckets (across users) rather than those owned by the S3 credentials;
+// - bucket summary: RGW-side object count, size, and quota - no need to list remote objects.
+// Requests are SigV4-signed; admin user must have "buckets=read" capability.
+// Ref: https://docs.ceph.com/en/latest/radosgw/adminops
+
+const (
+	rgwTimeout = 30 * time.Second
+
+	// sha256("")
+	rgwEmptyPayload = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+type (
+	rgwAdmin struct {
+		creds  aws.CredentialsProvider
+		signer *v4.Signer
+		client *http.Client
+		url    string
+		region string
+	}
+
+	// GET /admin/bucket?bucket=...&stats=true
+	rgwBucketStats struct {
+		Bucket string `json:"bucket"`
+		Usage  map[string]struct {
+			Size       uint64 `json:"size"`
+			NumObjects uint64 `json:"num_objects"`
+		} `json:"usage"`
+		Quota struct {
+			Enabled    bool  `json:"enabled"`
+			MaxSize    int64 `json:"max_size"`
+			MaxSizeKB  int64 `json:"max_size_kb"` // (older releases)
+			MaxObjects int64 `json:"max_objects"`
+		} `json:"bucket_quota"`
+	}
+)
+
+// interface guard
+var _ core.UsageBackend = (*s3bp)(nil)
+
+func newRGWAdmin() (*rgwAdmin, error) {
+	adminURL := os.Getenv(env.RGW.AdminURL)
+	if adminURL == "" {
+		return nil, nil
+	}
+	if _, err := url.ParseRequestURI(adminURL); err != nil {
+		return nil, fmt.Errorf("invalid %s=%q: %v", env.RGW.AdminURL, adminURL, err)
+	}
+	a := &rgwAdmin{
+		url:    strings.TrimSuffix(adminURL, "/"),
+		region: env.AwsDefaultRegion(),
+		signer: v4.NewSigner(),
+		client: cmn.NewClient(cmn.TransportArgs{Timeout: rgwTimeout}),
+	}
+	if ak, sk := os.Getenv(env.RGW.AccessKey), os.Getenv(env.RGW.SecretKey); ak != "" && sk != "" {
+		a.creds = credentials.NewStaticCredentialsProvider(ak, sk, "")
+	} else {
+		cfg, err := loadConfig(s3Endpoint, awsProfile)
+		if err != nil {
+			return nil, err
+		}
+		a.creds = cfg.Credentials
+	}
+	return a, nil
+}
+
+func (a *rgwAdmin) get(ctx context.Context, query url.Values, out any) (int, error) {
+	query.Set("format", "json")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url+"/bucket?"+query.Encode(), http.NoBody)
+	if err != nil {
+		return 0, err
+	}
+	creds, err := a.creds.Retrieve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("X-Amz-Content-Sha256", rgwEmptyPayload)
+	if err := a.signer.SignHTTP(ctx, creds, req, rgwEmptyPayload, "s3", a.region, time.Now()); err != nil {
+		return 0, err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, cos.KiB))
+		return resp.StatusCode, fmt.Errorf("rgw admin-ops: %s (status %d)", strings.TrimSpace(string(b)), resp.StatusCode)
+	}
+	return 0, jsoniter.NewDecoder(resp.Body).Decode(out)
+}
+
+func (a *rgwAdmin) listBuckets() (cmn.Bcks, error) {
+	var names []string
+	if _, err := a.get(context.Background(), url.Values{}, &names); err != nil {
+		return nil, err
+	}
+	bcks := make(cmn.Bcks, 0, len(names))
+	for _, name := range names {
+		if strings.IndexByte(name, '/') >= 0 {
+			continue // tenant-qualified ("tenant/bucket") - not addressable via S3 endpoint
+		}
+		bcks = append(bcks, cmn.Bck{Name: name, Provider: apc.AWS})
+	}
+	return bcks, nil
+}
+
+func (a *rgwAdmin) bucketUsage(name string) (*core.BckUsage, int, error) {
+	var (
+		stats rgwBucketStats
+		query = url.Values{"bucket": []string{name}, "stats": []string{"true"}}
+	)
+	if ecode, err := a.get(context.Background(), query, &stats); err != nil {
+		return nil, ecode, err
+	}
+	usage := &core.BckUsage{}
+	for _, u := range stats.Usage { // "rgw.main", "rgw.multimeta", ...
+		usage.ObjCount += u.NumObjects
+		usage.Size += u.Size
+	}
+	if q := &stats.Quota; q.Enabled {
+		switch {
+		case q.MaxSize > 0:
+			usage.QuotaSize = uint64(q.MaxSize)
+		case q.MaxSizeKB > 0:
+			usage.QuotaSize = uint64(q.MaxSizeKB) * cos.KiB
+		}
+		if q.MaxObjects > 0 {
+			usage.QuotaObjs = uint64(q.MaxObjects)
+		}
+	}
+	return usage, 0, nil
+}
+
+// as core.UsageBackend (only buckets accessed via the default S3 endpoint)
+func (s3bp *s3bp) BucketUsage(bck *meta.Bck) (*core.BckUsage, int, error) {
+	if s3bp.rgw == nil {
+		return nil, 0, nil
+	}
+	cloudBck := bck.RemoteBck()
+	if cloudBck.Props != nil && cloudBck.Props.Extra.AWS.Endpoint != "" && cloudBck.Props.Extra.AWS.Endpoint != s3Endpoint {
+		return nil, 0, nil
+	}
+	usage, ecode, err := s3bp.rgw.bucketUsage(cloudBck.Name)
+	if err != nil {
+		if ecode == http.StatusNotFound {
+			err = cmn.NewErrRemoteBckNotFound(cloudBck)
+		}
+		return nil, ecode, err
+	}
+	if cmn.Rom.FastV(4, cos.SmoduleBackend) {
+		nlog.Infoln("[rgw_bucket_usage]", cloudBck.Name, usage.ObjCount, usage.Size)
+	}
+	return usage, 0, nil
+}
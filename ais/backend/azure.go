@@ -19,6 +19,7 @@ import (
 	"context"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
@@ -44,9 +45,12 @@ import (
 
 type (
 	azbp struct {
-		t     core.TargetPut
-		creds *azblob.SharedKeyCredential
-		u     string
+		t             core.TargetPut
+		creds         *azblob.SharedKeyCredential
+		putTier       *blob.AccessTier        // default access tier of uploaded blobs
+		rehydrate     *blob.AccessTier        // cold GET of Archive-tier blob: rehydrate to this tier (nil: don't)
+		rehydratePrio *blob.RehydratePriority // Standard (default) or High
+		u             string
 		base
 	}
 )
@@ -61,6 +65,11 @@ const (
 	// ais
 	azURLEnvVar   = "AIS_AZURE_URL"
 	azProtoEnvVar = "AIS_AZURE_PROTO"
+
+	// access tiers
+	azTierEnvVar          = "AIS_AZURE_ACCESS_TIER"        // Hot | Cool | Cold | Archive (default: account's default tier)
+	azRehydrateEnvVar     = "AIS_AZURE_REHYDRATE"          // Hot | Cool | Cold (default: none - fail cold GET of Archive-tier blobs)
+	azRehydratePrioEnvVar = "AIS_AZURE_REHYDRATE_PRIORITY" // Standard | High
 )
 
 const (
@@ -73,7 +82,13 @@ var (
 )
 
 // interface guard
-var _ core.Backend = (*azbp)(nil)
+var (
+	_ core.Backend     = (*azbp)(nil)
+	_ core.TierBackend = (*azbp)(nil)
+)
+
+// (premium page blob tiers P4...P80 not included)
+var azTiers = []blob.AccessTier{blob.AccessTierHot, blob.AccessTierCool, blob.AccessTierCold, blob.AccessTierArchive}
 
 func azProto() string {
 	return cos.Right(azDefaultProto, os.Getenv(azProtoEnvVar))
@@ -112,12 +127,50 @@ func NewAzure(t core.TargetPut, tstats stats.Tracker, startingUp bool) (core.Bac
 		u:     blurl,
 		base:  base{provider: apc.Azure},
 	}
+	if err := bp.initTiers(); err != nil {
+		return nil, err
+	}
 	// register metrics
 	bp.base.init(t.Snode(), tstats, startingUp)
 
 	return bp, nil
 }
 
+func (azbp *azbp) initTiers() (err error) {
+	if v := os.Getenv(azTierEnvVar); v != "" {
+		if azbp.putTier, err = azTier(v); err != nil {
+			return fmt.Errorf("invalid %s: %w", azTierEnvVar, err)
+		}
+	}
+	if v := os.Getenv(azRehydrateEnvVar); v != "" {
+		if azbp.rehydrate, err = azTier(v); err != nil {
+			return fmt.Errorf("invalid %s: %w", azRehydrateEnvVar, err)
+		}
+		if *azbp.rehydrate == blob.AccessTierArchive {
+			return fmt.Errorf("invalid %s=%q (expecting online tier to rehydrate to)", azRehydrateEnvVar, v)
+		}
+	}
+	if v := os.Getenv(azRehydratePrioEnvVar); v != "" {
+		for _, prio := range blob.PossibleRehydratePriorityValues() {
+			if strings.EqualFold(v, string(prio)) {
+				azbp.rehydratePrio = &prio
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid %s=%q (expecting one of: %v)", azRehydratePrioEnvVar, v, blob.PossibleRehydratePriorityValues())
+	}
+	return nil
+}
+
+func azTier(s string) (*blob.AccessTier, error) {
+	for _, tier := range azTiers {
+		if strings.EqualFold(s, string(tier)) {
+			return &tier, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid access tier %q (expecting one of: %v)", s, azTiers)
+}
+
 // (compare w/ cmn/backend)
 func azEncodeEtag(etag azcore.ETag) string { return cmn.UnquoteCEV(string(etag)) }
 
@@ -308,6 +361,12 @@ func (azbp *azbp) ListObjects(bck *meta.Bck, msg *apc.LsoMsg, lst *cmn.LsoRes) (
 			if blob.VersionID != nil {
 				custom = append(custom, cmn.VersionObjMD, *blob.VersionID)
 			}
+			if tier := blob.Properties.AccessTier; tier != nil {
+				custom = append(custom, cmn.AccessTierObjMD, string(*tier))
+			}
+			if status := blob.Properties.ArchiveStatus; status != nil {
+				custom = append(custom, cmn.ArchiveStatusObjMD, string(*status))
+			}
 			en.Custom = cmn.CustomProps2S(custom...)
 		}
 		lst.Entries = append(lst.Entries, &en)
@@ -395,6 +454,12 @@ func (azbp *azbp) HeadObj(ctx context.Context, lom *core.LOM, _ *http.Request) (
 		// - only shown via list-objects and HEAD when not present
 		oa.SetCustomKey(cos.HdrContentType, *v)
 	}
+	if v := resp.AccessTier; v != nil {
+		oa.SetCustomKey(cmn.AccessTierObjMD, *v)
+	}
+	if v := resp.ArchiveStatus; v != nil {
+		oa.SetCustomKey(cmn.ArchiveStatusObjMD, *v)
+	}
 	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
 		nlog.Infof("[head_object] %s", lom)
 	}
@@ -430,12 +495,16 @@ func (azbp *azbp) GetObjReader(ctx context.Context, lom *core.LOM, offset, lengt
 		return
 	}
 
-	// Get checksum
+	// Get checksum (and access tier)
 	respProps, err := client.GetProperties(ctx, nil)
 	if err != nil {
 		res.ErrCode, res.Err = azureErrorToAISError(err, cloudBck, lom.ObjName)
 		return
 	}
+	if tier := respProps.AccessTier; tier != nil && blob.AccessTier(*tier) == blob.AccessTierArchive {
+		res.ErrCode, res.Err = azbp.archived(ctx, client, cloudBck, lom.ObjName, respProps.ArchiveStatus)
+		return res
+	}
 
 	// (0, 0) range indicates "whole object"
 	var opts blob.DownloadStreamOptions
@@ -465,12 +534,63 @@ func (azbp *azbp) GetObjReader(ctx context.Context, lom *core.LOM, offset, lengt
 			lom.SetCustomKey(cmn.MD5ObjMD, md5)
 			res.ExpCksum = cos.NewCksum(cos.ChecksumMD5, md5)
 		}
+		if tier := respProps.AccessTier; tier != nil {
+			lom.SetCustomKey(cmn.AccessTierObjMD, *tier)
+		}
 	}
 
 	res.R = resp.Body
 	return res
 }
 
+// Archive-tier blob cannot be read until rehydrated to an online tier, which may take hours;
+// cold GET either fails right away (skip) or, if configured, also starts rehydration (async)
+func (azbp *azbp) archived(ctx context.Context, client *blockblob.Client, cloudBck *cmn.Bck, objName string, status *string) (int, error) {
+	cname := cloudBck.Cname(objName)
+	switch {
+	case status != nil && *status != "":
+		return http.StatusConflict, fmt.Errorf("%sBlobArchived: %s is in %s tier, rehydration in progress (%s)]",
+			azErrPrefix, cname, blob.AccessTierArchive, *status)
+	case azbp.rehydrate == nil:
+		return http.StatusConflict, fmt.Errorf("%sBlobArchived: %s is in %s tier (see %s to rehydrate on GET)]",
+			azErrPrefix, cname, blob.AccessTierArchive, azRehydrateEnvVar)
+	}
+	opts := &blob.SetTierOptions{RehydratePriority: azbp.rehydratePrio}
+	if _, err := client.SetTier(ctx, *azbp.rehydrate, opts); err != nil {
+		return azureErrorToAISError(err, cloudBck, objName)
+	}
+	nlog.Infoln("rehydrating", cname, "to", *azbp.rehydrate, "tier")
+	return http.StatusConflict, fmt.Errorf("%sBlobArchived: %s is in %s tier, started rehydration to %s - retry later]",
+		azErrPrefix, cname, blob.AccessTierArchive, *azbp.rehydrate)
+}
+
+//
+// SET TIER
+//
+
+func (azbp *azbp) SetObjTier(lom *core.LOM, tier string) (int, error) {
+	atier, err := azTier(tier)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	var (
+		cloudBck = lom.Bucket().RemoteBck()
+		blURL    = azbp.u + "/" + cloudBck.Name + "/" + lom.ObjName
+	)
+	client, err := blockblob.NewClientWithSharedKeyCredential(blURL, azbp.creds, nil)
+	if err != nil {
+		return azureErrorToAISError(err, cloudBck, lom.ObjName)
+	}
+	opts := &blob.SetTierOptions{RehydratePriority: azbp.rehydratePrio}
+	if _, err := client.SetTier(context.Background(), *atier, opts); err != nil {
+		return azureErrorToAISError(err, cloudBck, lom.ObjName)
+	}
+	if cmn.Rom.FastV(4, cos.SmoduleBackend) {
+		nlog.Infoln("[set_tier]", lom.String(), *atier)
+	}
+	return 0, nil
+}
+
 //
 // PUT OBJECT
 //
@@ -484,7 +604,7 @@ func (azbp *azbp) PutObj(r io.ReadCloser, lom *core.LOM, _ *http.Request) (int,
 	}
 	cloudBck := lom.Bck().RemoteBck()
 
-	opts := azblob.UploadStreamOptions{}
+	opts := azblob.UploadStreamOptions{AccessTier: azbp.putTier}
 	if size := lom.Lsize(true); size > cos.MiB {
 		opts.Concurrency = int(min((size+cos.MiB-1)/cos.MiB, 8))
 	}
@@ -502,6 +622,9 @@ func (azbp *azbp) PutObj(r io.ReadCloser, lom *core.LOM, _ *http.Request) (int,
 	if v := resp.LastModified; v != nil {
 		lom.SetCustomKey(cmn.LastModified, fmtTime(*v))
 	}
+	if azbp.putTier != nil {
+		lom.SetCustomKey(cmn.AccessTierObjMD, string(*azbp.putTier))
+	}
 	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
 		nlog.Infof("[put_object] %s", lom)
 	}
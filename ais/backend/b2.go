@@ -0,0 +1,948 @@
+//go:build b2
+
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1" //nolint:gosec // B2 content integrity (required by the API)
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/api/env"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/stats"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Backblaze B2 via native API (v2):
+// - account authorization (b2_authorize_account) is cached and renewed upon expiration;
+// - bucket IDs (required by most APIs) are resolved by name and cached;
+// - upload URLs (b2_get_upload_url) are pooled per bucket and reused; a URL that fails
+//   is discarded, as per B2 integration checklist;
+// - objects of size >= large-file threshold are uploaded in parts (b2_start_large_file,
+//   b2_get_upload_part_url, b2_upload_part, b2_finish_large_file);
+// - SHA1 is computed on the fly and sent at the end of the body ("hex_digits_at_end");
+// - version: file ID; checksums: MD5 (when known, e.g. S3-compatible uploads) and SHA1;
+// - delete: b2_hide_file (prior versions are retained as per bucket's lifecycle rules).
+// Ref: https://www.backblaze.com/apidocs/introduction-to-the-b2-native-api
+
+const (
+	b2AuthURL = "https://api.backblazeb2.com"
+	b2APIPath = "/b2api/v2/"
+
+	b2LargeThreshold = 200 * cos.MiB
+	b2MinPartSize    = 5 * cos.MiB
+	b2MaxPartSize    = 5 * cos.GiB // (also, max single-upload size)
+	b2MaxParts       = 10000
+
+	b2MaxUploadURLs = 16 // max pooled upload URLs per bucket
+
+	b2ShaAtEnd    = "hex_digits_at_end"
+	b2ContentType = "b2/x-auto"
+	b2Folder      = "folder" // list-file-names action: virtual subdirectory
+
+	// file info: AIS checksum (see also gcpChecksumType and friends)
+	b2CksumType = "ais_cksum_type"
+	b2CksumVal  = "ais_cksum_val"
+	b2LargeSha1 = "large_file_sha1"
+)
+
+const (
+	b2HdrFileName = "X-Bz-File-Name"
+	b2HdrFileID   = "X-Bz-File-Id"
+	b2HdrSha1     = "X-Bz-Content-Sha1"
+	b2HdrPartNum  = "X-Bz-Part-Number"
+	b2HdrUploadTs = "X-Bz-Upload-Timestamp"
+	b2HdrInfo     = "X-Bz-Info-"
+)
+
+// B2 error codes
+const (
+	b2ErrExpiredToken = "expired_auth_token"
+	b2ErrBadToken     = "bad_auth_token"
+	b2ErrBadBucketID  = "bad_bucket_id"
+	b2ErrNotFound     = "not_found"
+	b2ErrNoSuchFile   = "no_such_file"
+	b2ErrNotPresent   = "file_not_present"
+)
+
+type (
+	b2bp struct {
+		t       core.TargetPut
+		client  *http.Client
+		keyID   string
+		key     string
+		authURL string
+		acc     b2acc
+		bids    sync.Map // bucket name => bucket ID
+		upools  sync.Map // bucket ID => *b2upool
+		large   int64    // large-file threshold
+		partSz  int64    // 0: as recommended by b2_authorize_account
+		base
+		mu sync.RWMutex
+	}
+	b2acc struct {
+		AccountID   string `json:"accountId"`
+		APIURL      string `json:"apiUrl"`
+		DownloadURL string `json:"downloadUrl"`
+		Token       string `json:"authorizationToken"`
+		Allowed     struct {
+			BucketID   string `json:"bucketId"`
+			BucketName string `json:"bucketName"`
+		} `json:"allowed"`
+		RecommendedPartSize int64 `json:"recommendedPartSize"`
+	}
+	b2upload struct {
+		URL   string `json:"uploadUrl"`
+		Token string `json:"authorizationToken"`
+	}
+	b2upool struct {
+		urls []*b2upload
+		mu   sync.Mutex
+	}
+	b2fid struct {
+		FileID string `json:"fileId"`
+	}
+	b2bucket struct {
+		BucketID   string `json:"bucketId"`
+		BucketName string `json:"bucketName"`
+	}
+	b2file struct {
+		FileInfo        map[string]string `json:"fileInfo"`
+		FileID          string            `json:"fileId"`
+		FileName        string            `json:"fileName"`
+		Action          string            `json:"action"`
+		ContentType     string            `json:"contentType"`
+		ContentSha1     string            `json:"contentSha1"`
+		ContentMd5      string            `json:"contentMd5"`
+		ContentLength   int64             `json:"contentLength"`
+		UploadTimestamp int64             `json:"uploadTimestamp"` // Unix milliseconds
+	}
+	b2err struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Status  int    `json:"status"`
+	}
+	// SHA1 of the preceding content (see "hex_digits_at_end")
+	b2trailer struct {
+		h   hash.Hash
+		sum string
+		b   []byte
+	}
+)
+
+// interface guard
+var _ core.Backend = (*b2bp)(nil)
+
+func NewB2(t core.TargetPut, tstats stats.Tracker, startingUp bool) (core.Backend, error) {
+	bp := &b2bp{
+		t:       t,
+		keyID:   os.Getenv(env.B2.KeyID),
+		key:     os.Getenv(env.B2.Key),
+		authURL: b2AuthURL,
+		large:   b2LargeThreshold,
+		base:    base{provider: apc.B2},
+	}
+	if bp.keyID == "" || bp.key == "" {
+		return nil, fmt.Errorf("%s: missing application key (%s, %s)", apc.DisplayProvider(apc.B2), env.B2.KeyID, env.B2.Key)
+	}
+	if s := os.Getenv(env.B2.Endpoint); s != "" {
+		bp.authURL = strings.TrimSuffix(s, "/")
+	}
+	if err := b2size(env.B2.LargeFileThreshold, &bp.large); err != nil {
+		return nil, err
+	}
+	if err := b2size(env.B2.PartSize, &bp.partSz); err != nil {
+		return nil, err
+	}
+	// no overall client timeout (large objects); requests are bounded by their respective contexts
+	bp.client = cmn.NewClientTLS(cmn.TransportArgs{}, cmn.TLSArgs{}, false /*intra-cluster*/)
+
+	// register metrics
+	bp.base.init(t.Snode(), tstats, startingUp)
+
+	if err := bp.authorize(); err != nil {
+		return nil, err
+	}
+	return bp, nil
+}
+
+func b2size(name string, val *int64) error {
+	s := os.Getenv(name)
+	if s == "" {
+		return nil
+	}
+	n, err := cos.ParseSize(s, cos.UnitsIEC)
+	if err != nil {
+		return fmt.Errorf("invalid %s=%q: %v", name, s, err)
+	}
+	if n < b2MinPartSize || n > b2MaxPartSize {
+		return fmt.Errorf("invalid %s=%q: expecting (%s, %s) range", name, s,
+			cos.ToSizeIEC(b2MinPartSize, 0), cos.ToSizeIEC(b2MaxPartSize, 0))
+	}
+	*val = n
+	return nil
+}
+
+//
+// account authorization
+//
+
+func (bp *b2bp) authorize() error {
+	req, err := http.NewRequest(http.MethodGet, bp.authURL+b2APIPath+"b2_authorize_account", http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(bp.keyID, bp.key)
+	resp, err := bp.client.Do(req)
+	if err != nil {
+		return cmn.NewErrFailedTo(nil, "b2-backend: authorize", "account", err)
+	}
+	var acc b2acc
+	if _, err := b2resp(resp, &acc); err != nil {
+		return err
+	}
+	bp.mu.Lock()
+	bp.acc = acc
+	bp.mu.Unlock()
+	if cmn.Rom.FastV(4, cos.SmoduleBackend) {
+		nlog.Infoln("[b2_authorize_account]", acc.AccountID, acc.APIURL)
+	}
+	return nil
+}
+
+func (bp *b2bp) account() (b2acc, error) {
+	bp.mu.RLock()
+	acc := bp.acc
+	bp.mu.RUnlock()
+	if acc.Token != "" {
+		return acc, nil
+	}
+	if err := bp.authorize(); err != nil {
+		return acc, err
+	}
+	bp.mu.RLock()
+	acc = bp.acc
+	bp.mu.RUnlock()
+	return acc, nil
+}
+
+// returns true if the (failed) request should be retried with a new authorization token
+func (bp *b2bp) expired(err error, token string) bool {
+	e, ok := err.(*b2err)
+	if !ok || (e.Code != b2ErrExpiredToken && e.Code != b2ErrBadToken) {
+		return false
+	}
+	bp.mu.Lock()
+	if bp.acc.Token == token {
+		bp.acc.Token = ""
+	}
+	bp.mu.Unlock()
+	return true
+}
+
+// POST JSON to a given B2 API; re-authorize and retry once upon token expiration
+func (bp *b2bp) call(ctx context.Context, api string, in, out any) (int, error) {
+	body := cos.MustMarshal(in)
+	for retry := false; ; retry = true {
+		acc, err := bp.account()
+		if err != nil {
+			return http.StatusUnauthorized, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, acc.APIURL+b2APIPath+api, bytes.NewReader(body))
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set(apc.HdrAuthorization, acc.Token)
+		req.Header.Set(cos.HdrContentType, cos.ContentJSON)
+		resp, err := bp.client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		ecode, err := b2resp(resp, out)
+		if err == nil || retry || !bp.expired(err, acc.Token) {
+			return ecode, err
+		}
+	}
+}
+
+// decode JSON response or B2 error
+func b2resp(resp *http.Response, out any) (int, error) {
+	defer b2close(resp)
+	if resp.StatusCode == http.StatusOK {
+		if out == nil {
+			return 0, nil
+		}
+		if err := jsoniter.NewDecoder(resp.Body).Decode(out); err != nil {
+			return 0, fmt.Errorf("b2-error[failed to decode response: %v]", err)
+		}
+		return 0, nil
+	}
+	e := &b2err{Status: resp.StatusCode}
+	if resp.Request == nil || resp.Request.Method != http.MethodHead {
+		_ = jsoniter.NewDecoder(resp.Body).Decode(e)
+	}
+	switch {
+	case e.Code == "" && e.Status == http.StatusUnauthorized:
+		e.Code = b2ErrExpiredToken // (HEAD: no body)
+	case e.Code == "":
+		e.Code = strings.ToLower(strings.ReplaceAll(http.StatusText(resp.StatusCode), " ", "_"))
+	}
+	switch e.Code {
+	case b2ErrNoSuchFile, b2ErrNotPresent:
+		e.Status = http.StatusNotFound
+	}
+	return e.Status, e
+}
+
+func b2close(resp *http.Response) {
+	cos.DrainReader(resp.Body)
+	resp.Body.Close()
+}
+
+func (e *b2err) Error() string {
+	if e.Message == "" {
+		return "b2-error[" + e.Code + "]"
+	}
+	return "b2-error[" + e.Code + ": " + e.Message + "]"
+}
+
+// convert bucket-related errors; drop cached bucket ID if need be
+func (bp *b2bp) bckErr(cloudBck *cmn.Bck, ecode int, err error) (int, error) {
+	if e, ok := err.(*b2err); ok && (e.Code == b2ErrBadBucketID || (e.Code == b2ErrNotFound && e.Status == http.StatusBadRequest)) {
+		bp.bids.Delete(cloudBck.Name)
+		return http.StatusNotFound, cmn.NewErrRemoteBckNotFound(cloudBck)
+	}
+	return ecode, err
+}
+
+//
+// bucket ID (by name)
+//
+
+func (bp *b2bp) bucketID(ctx context.Context, cloudBck *cmn.Bck) (string, int, error) {
+	if v, ok := bp.bids.Load(cloudBck.Name); ok {
+		return v.(string), 0, nil
+	}
+	acc, err := bp.account()
+	if err != nil {
+		return "", http.StatusUnauthorized, err
+	}
+	var (
+		in = struct {
+			AccountID  string `json:"accountId"`
+			BucketName string `json:"bucketName"`
+		}{acc.AccountID, cloudBck.Name}
+		out struct {
+			Buckets []b2bucket `json:"buckets"`
+		}
+	)
+	if ecode, err := bp.call(ctx, "b2_list_buckets", &in, &out); err != nil {
+		return "", ecode, err
+	}
+	if len(out.Buckets) == 0 {
+		return "", http.StatusNotFound, cmn.NewErrRemoteBckNotFound(cloudBck)
+	}
+	bid := out.Buckets[0].BucketID
+	bp.bids.Store(cloudBck.Name, bid)
+	return bid, 0, nil
+}
+
+// as core.Backend --------------------------------------------------------------
+
+//
+// HEAD BUCKET
+//
+
+func (bp *b2bp) HeadBucket(ctx context.Context, bck *meta.Bck) (cos.StrKVs, int, error) {
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infof("head_bucket %s", bck.Name)
+	}
+	cloudBck := bck.RemoteBck()
+	bp.bids.Delete(cloudBck.Name) // (re)validate
+	if _, ecode, err := bp.bucketID(ctx, cloudBck); err != nil {
+		return nil, ecode, err
+	}
+	bckProps := make(cos.StrKVs, 2)
+	bckProps[apc.HdrBackendProvider] = apc.B2
+	// every upload generates a new file ID; previous versions are retained
+	// (subject to bucket's lifecycle rules)
+	bckProps[apc.HdrBucketVerEnabled] = "true"
+	return bckProps, 0, nil
+}
+
+//
+// LIST OBJECTS
+//
+
+func (bp *b2bp) ListObjects(bck *meta.Bck, msg *apc.LsoMsg, lst *cmn.LsoRes) (int, error) {
+	var (
+		ctx      = context.Background()
+		h        = cmn.BackendHelpers.B2
+		cloudBck = bck.RemoteBck()
+	)
+	msg.PageSize = calcPageSize(msg.PageSize, bck.MaxPageSize())
+
+	bid, ecode, err := bp.bucketID(ctx, cloudBck)
+	if err != nil {
+		return ecode, err
+	}
+	var (
+		in = struct {
+			BucketID      string `json:"bucketId"`
+			StartFileName string `json:"startFileName,omitempty"`
+			Prefix        string `json:"prefix,omitempty"`
+			Delimiter     string `json:"delimiter,omitempty"`
+			MaxFileCount  int64  `json:"maxFileCount"`
+		}{BucketID: bid, StartFileName: msg.ContinuationToken, Prefix: msg.Prefix, MaxFileCount: msg.PageSize}
+		out struct {
+			NextFileName *string   `json:"nextFileName"`
+			Files        []*b2file `json:"files"`
+		}
+	)
+	if msg.IsFlagSet(apc.LsNoRecursion) {
+		in.Delimiter = "/"
+	}
+	if ecode, err := bp.call(ctx, "b2_list_file_names", &in, &out); err != nil {
+		if cmn.Rom.FastV(4, cos.SmoduleBackend) {
+			nlog.Infof("list_objects %s: %v", cloudBck.Name, err)
+		}
+		return bp.bckErr(cloudBck, ecode, err)
+	}
+
+	lst.ContinuationToken = ""
+	if out.NextFileName != nil {
+		lst.ContinuationToken = *out.NextFileName
+	}
+
+	wantCustom := msg.WantProp(apc.GetPropsCustom)
+	lst.Entries = lst.Entries[:0]
+	for _, f := range out.Files {
+		en := cmn.LsoEnt{Name: f.FileName, Size: f.ContentLength}
+		if f.Action == b2Folder {
+			if msg.IsFlagSet(apc.LsNoDirs) { // do not return virtual subdirectories
+				continue
+			}
+			en.Flags = apc.EntryIsDir
+		} else if !msg.IsFlagSet(apc.LsNameOnly) && !msg.IsFlagSet(apc.LsNameSize) {
+			md5, _ := h.EncodeCksum(f.ContentMd5)
+			sha, _ := f.sha1()
+			if md5 != "" {
+				en.Checksum = md5
+			} else {
+				en.Checksum = sha
+			}
+			if v, ok := h.EncodeVersion(f.FileID); ok {
+				en.Version = v
+			}
+			if wantCustom {
+				en.Custom = cmn.CustomProps2S(cmn.SHA1ObjMD, sha, cmn.LastModified, f.mtime(),
+					cos.HdrContentType, f.ContentType)
+			}
+		}
+		lst.Entries = append(lst.Entries, &en)
+	}
+
+	if cmn.Rom.FastV(4, cos.SmoduleBackend) {
+		nlog.Infof("[list_objects] count %d", len(lst.Entries))
+	}
+	return 0, nil
+}
+
+//
+// LIST BUCKETS
+//
+
+func (bp *b2bp) ListBuckets(cmn.QueryBcks) (bcks cmn.Bcks, ecode int, err error) {
+	acc, err := bp.account()
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+	var (
+		// NOTE: keys restricted to a single bucket can only list that bucket
+		in = struct {
+			AccountID  string `json:"accountId"`
+			BucketName string `json:"bucketName,omitempty"`
+		}{acc.AccountID, acc.Allowed.BucketName}
+		out struct {
+			Buckets []b2bucket `json:"buckets"`
+		}
+	)
+	if ecode, err = bp.call(context.Background(), "b2_list_buckets", &in, &out); err != nil {
+		return nil, ecode, err
+	}
+	bcks = make(cmn.Bcks, 0, len(out.Buckets))
+	for _, b := range out.Buckets {
+		bcks = append(bcks, cmn.Bck{Name: b.BucketName, Provider: apc.B2})
+		bp.bids.Store(b.BucketName, b.BucketID)
+	}
+	return bcks, 0, nil
+}
+
+//
+// HEAD OBJECT
+//
+
+func (bp *b2bp) HeadObj(ctx context.Context, lom *core.LOM, _ *http.Request) (*cmn.ObjAttrs, int, error) {
+	resp, ecode, err := bp.download(ctx, http.MethodHead, lom, 0, 0)
+	if err != nil {
+		return nil, ecode, err
+	}
+	b2close(resp)
+
+	f := b2fileHdr(resp.Header)
+	oa := &cmn.ObjAttrs{}
+	oa.CustomMD = make(cos.StrKVs, 6)
+	oa.Size = resp.ContentLength
+	f.setCustom(oa)
+	if ty, val := f.FileInfo[b2CksumType], f.FileInfo[b2CksumVal]; ty != "" && val != "" {
+		oa.SetCksum(ty, val)
+	}
+	// (not stored w/ LOM - see gsbp.HeadObj)
+	oa.SetCustomKey(cos.HdrContentType, f.ContentType)
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infof("[head_object] %s", lom.Bck().RemoteBck().Cname(lom.ObjName))
+	}
+	return oa, 0, nil
+}
+
+//
+// GET OBJECT
+//
+
+func (bp *b2bp) GetObj(ctx context.Context, lom *core.LOM, owt cmn.OWT, _ *http.Request) (int, error) {
+	res := bp.GetObjReader(ctx, lom, 0, 0)
+	if res.Err != nil {
+		return res.ErrCode, res.Err
+	}
+	params := allocPutParams(res, owt)
+	err := bp.t.PutObject(lom, params)
+	core.FreePutParams(params)
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infoln("[get_object]", lom.String(), err)
+	}
+	return 0, err
+}
+
+func (bp *b2bp) GetObjReader(ctx context.Context, lom *core.LOM, offset, length int64) (res core.GetReaderResult) {
+	resp, ecode, err := bp.download(ctx, http.MethodGet, lom, offset, length)
+	if err != nil {
+		res.ErrCode, res.Err = ecode, err
+		return res
+	}
+	if length == 0 {
+		// custom metadata
+		f := b2fileHdr(resp.Header)
+		if ty, val := f.FileInfo[b2CksumType], f.FileInfo[b2CksumVal]; ty != "" && val != "" {
+			lom.SetCksum(cos.NewCksum(ty, val))
+		}
+		res.ExpCksum = f.setCustom(lom.ObjAttrs())
+	}
+	res.Size = resp.ContentLength
+	res.R = resp.Body
+	return res
+}
+
+// download (GET or HEAD) by name
+func (bp *b2bp) download(ctx context.Context, method string, lom *core.LOM, offset, length int64) (*http.Response, int, error) {
+	cloudBck := lom.Bck().RemoteBck()
+	for retry := false; ; retry = true {
+		acc, err := bp.account()
+		if err != nil {
+			return nil, http.StatusUnauthorized, err
+		}
+		u := acc.DownloadURL + "/file/" + cloudBck.Name + "/" + b2Escape(lom.ObjName)
+		req, err := http.NewRequestWithContext(ctx, method, u, http.NoBody)
+		if err != nil {
+			return nil, 0, err
+		}
+		req.Header.Set(apc.HdrAuthorization, acc.Token)
+		if length > 0 {
+			req.Header.Set(cos.HdrRange, cmn.MakeRangeHdr(offset, length))
+		}
+		resp, err := bp.client.Do(req)
+		if err != nil {
+			return nil, 0, err
+		}
+		if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusPartialContent {
+			return resp, 0, nil
+		}
+		ecode, err := b2resp(resp, nil)
+		if !retry && bp.expired(err, acc.Token) {
+			continue
+		}
+		switch ecode {
+		case http.StatusNotFound:
+			// object or bucket?
+			if _, ec, errB := bp.bucketID(ctx, cloudBck); errB != nil {
+				return nil, ec, errB
+			}
+		case http.StatusRequestedRangeNotSatisfiable:
+			err = cmn.NewErrRangeNotSatisfiable(err, nil, 0)
+		}
+		return nil, ecode, err
+	}
+}
+
+//
+// PUT OBJECT
+//
+
+func (bp *b2bp) PutObj(r io.ReadCloser, lom *core.LOM, _ *http.Request) (int, error) {
+	var (
+		f        *b2file
+		ctx      = context.Background()
+		cloudBck = lom.Bck().RemoteBck()
+		size     = lom.Lsize(true) // (special)
+	)
+	bid, ecode, err := bp.bucketID(ctx, cloudBck)
+	if err != nil {
+		cos.Close(r)
+		return ecode, err
+	}
+	if size >= bp.large {
+		f, ecode, err = bp.putLarge(ctx, bid, r, lom, size)
+	} else {
+		f, ecode, err = bp.put(ctx, bid, r, lom, size)
+	}
+	cos.Close(r)
+	if err != nil {
+		return bp.bckErr(cloudBck, ecode, err)
+	}
+	f.setCustom(lom.ObjAttrs())
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infof("[put_object] %s, size %d, file ID %s", lom, size, f.FileID)
+	}
+	return 0, nil
+}
+
+// single upload; retry once with a new upload URL if the reader can be reopened
+func (bp *b2bp) put(ctx context.Context, bid string, r io.ReadCloser, lom *core.LOM, size int64) (*b2file, int, error) {
+	hdr := make(http.Header, 6)
+	hdr.Set(b2HdrFileName, b2Escape(lom.ObjName))
+	hdr.Set(cos.HdrContentType, b2ContentType)
+	for k, v := range b2info(lom) {
+		hdr.Set(b2HdrInfo+k, v)
+	}
+	var (
+		rr     = r
+		reopen bool
+	)
+	for retry := false; ; retry = true {
+		u, ecode, err := bp.getUploadURL(ctx, bid)
+		if err != nil {
+			return nil, ecode, err
+		}
+		f := &b2file{}
+		_, ecode, err = bp.upload(ctx, u, hdr, rr, size, f)
+		if reopen {
+			cos.Close(rr)
+		}
+		if err == nil {
+			bp.putUploadURL(bid, u)
+			return f, 0, nil
+		}
+		roc, ok := r.(cos.ReadOpenCloser)
+		if retry || !ok || !b2retriable(ecode) {
+			return nil, ecode, err
+		}
+		if rr, err = roc.Open(); err != nil {
+			return nil, 0, err
+		}
+		reopen = true
+		nlog.Warningln("b2: retrying upload", lom.String(), "[", err, "]")
+	}
+}
+
+// large file: sequential parts
+func (bp *b2bp) putLarge(ctx context.Context, bid string, r io.Reader, lom *core.LOM, size int64) (*b2file, int, error) {
+	partSize := bp.partSz
+	if partSize == 0 {
+		acc, err := bp.account()
+		if err != nil {
+			return nil, http.StatusUnauthorized, err
+		}
+		partSize = max(acc.RecommendedPartSize, b2MinPartSize)
+	}
+	if cos.DivCeil(size, partSize) > b2MaxParts {
+		partSize = cos.DivCeil(size, b2MaxParts)
+	}
+
+	var (
+		start = &b2file{}
+		in    = struct {
+			FileInfo    map[string]string `json:"fileInfo,omitempty"`
+			BucketID    string            `json:"bucketId"`
+			FileName    string            `json:"fileName"`
+			ContentType string            `json:"contentType"`
+		}{b2info(lom), bid, lom.ObjName, b2ContentType}
+	)
+	if ecode, err := bp.call(ctx, "b2_start_large_file", &in, start); err != nil {
+		return nil, ecode, err
+	}
+	fid := &b2fid{start.FileID}
+
+	f, ecode, err := bp._large(ctx, fid, r, size, partSize)
+	if err != nil {
+		// best effort (otherwise, unfinished large files do count toward storage)
+		if _, errC := bp.call(ctx, "b2_cancel_large_file", fid, nil); errC != nil {
+			nlog.Warningln("b2: failed to cancel large file", lom.String(), fid.FileID, "[", errC, "]")
+		}
+	}
+	return f, ecode, err
+}
+
+func (bp *b2bp) _large(ctx context.Context, fid *b2fid, r io.Reader, size, partSize int64) (*b2file, int, error) {
+	u := &b2upload{}
+	if ecode, err := bp.call(ctx, "b2_get_upload_part_url", fid, u); err != nil {
+		return nil, ecode, err
+	}
+	shas := make([]string, 0, cos.DivCeil(size, partSize))
+	for num, off := 1, int64(0); off < size; num++ {
+		n := min(partSize, size-off)
+		hdr := make(http.Header, 2)
+		hdr.Set(b2HdrPartNum, strconv.Itoa(num))
+		sha, ecode, err := bp.upload(ctx, u, hdr, r, n, nil)
+		if err != nil {
+			return nil, ecode, err
+		}
+		shas = append(shas, sha)
+		off += n
+	}
+	var (
+		f  = &b2file{}
+		in = struct {
+			FileID string   `json:"fileId"`
+			Shas   []string `json:"partSha1Array"`
+		}{fid.FileID, shas}
+	)
+	ecode, err := bp.call(ctx, "b2_finish_large_file", &in, f)
+	return f, ecode, err
+}
+
+// upload file or part; returns computed SHA1
+func (bp *b2bp) upload(ctx context.Context, u *b2upload, hdr http.Header, r io.Reader, size int64, out any) (string, int, error) {
+	var (
+		h    = sha1.New() //nolint:gosec // ditto
+		tr   = &b2trailer{h: h}
+		body = io.MultiReader(io.TeeReader(io.LimitReader(r, size), h), tr)
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.URL, body)
+	if err != nil {
+		return "", 0, err
+	}
+	req.ContentLength = size + int64(hex.EncodedLen(sha1.Size))
+	req.Header = hdr
+	req.Header.Set(apc.HdrAuthorization, u.Token)
+	req.Header.Set(b2HdrSha1, b2ShaAtEnd)
+	resp, err := bp.client.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	ecode, err := b2resp(resp, out)
+	return tr.sum, ecode, err
+}
+
+// B2: "If a call fails [...] with 401 Unauthorized or 503 Service Unavailable [...]
+// call b2_get_upload_url to get a new upload URL and token"
+func b2retriable(ecode int) bool {
+	switch ecode {
+	case 0, http.StatusUnauthorized, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	default:
+		return ecode >= http.StatusInternalServerError
+	}
+}
+
+// AIS checksum => file info
+func b2info(lom *core.LOM) map[string]string {
+	cksum := lom.Checksum()
+	if cksum == nil || cksum.IsEmpty() {
+		return nil
+	}
+	ty, val := cksum.Get()
+	return map[string]string{b2CksumType: ty, b2CksumVal: val}
+}
+
+//
+// upload URL pool
+//
+
+func (bp *b2bp) getUploadURL(ctx context.Context, bid string) (*b2upload, int, error) {
+	if v, ok := bp.upools.Load(bid); ok {
+		if u := v.(*b2upool).get(); u != nil {
+			return u, 0, nil
+		}
+	}
+	var (
+		u  = &b2upload{}
+		in = struct {
+			BucketID string `json:"bucketId"`
+		}{bid}
+	)
+	ecode, err := bp.call(ctx, "b2_get_upload_url", &in, u)
+	return u, ecode, err
+}
+
+func (bp *b2bp) putUploadURL(bid string, u *b2upload) {
+	v, _ := bp.upools.LoadOrStore(bid, &b2upool{})
+	v.(*b2upool).put(u)
+}
+
+func (p *b2upool) get() (u *b2upload) {
+	p.mu.Lock()
+	if l := len(p.urls); l > 0 {
+		u = p.urls[l-1]
+		p.urls = p.urls[:l-1]
+	}
+	p.mu.Unlock()
+	return u
+}
+
+func (p *b2upool) put(u *b2upload) {
+	p.mu.Lock()
+	if len(p.urls) < b2MaxUploadURLs {
+		p.urls = append(p.urls, u)
+	}
+	p.mu.Unlock()
+}
+
+//
+// DELETE OBJECT
+//
+
+func (bp *b2bp) DeleteObj(lom *core.LOM) (int, error) {
+	var (
+		ctx      = context.Background()
+		cloudBck = lom.Bck().RemoteBck()
+	)
+	bid, ecode, err := bp.bucketID(ctx, cloudBck)
+	if err != nil {
+		return ecode, err
+	}
+	in := struct {
+		BucketID string `json:"bucketId"`
+		FileName string `json:"fileName"`
+	}{bid, lom.ObjName}
+	if ecode, err := bp.call(ctx, "b2_hide_file", &in, nil); err != nil {
+		return bp.bckErr(cloudBck, ecode, err)
+	}
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infof("[delete_object] %s", lom)
+	}
+	return 0, nil
+}
+
+////////////
+// b2file //
+////////////
+
+// from download (GET, HEAD) response headers
+func b2fileHdr(hdr http.Header) *b2file {
+	f := &b2file{
+		FileID:      hdr.Get(b2HdrFileID),
+		ContentType: hdr.Get(cos.HdrContentType),
+		ContentSha1: hdr.Get(b2HdrSha1),
+		FileInfo:    make(map[string]string, 4),
+	}
+	f.UploadTimestamp, _ = strconv.ParseInt(hdr.Get(b2HdrUploadTs), 10, 64)
+	for k, v := range hdr {
+		// (canonicalized, e.g. "X-Bz-Info-Ais_cksum_type")
+		if strings.HasPrefix(k, b2HdrInfo) && len(v) > 0 {
+			f.FileInfo[strings.ToLower(k[len(b2HdrInfo):])] = v[0]
+		}
+	}
+	return f
+}
+
+// SHA1 of the entire content: large files carry it (if at all) in file info
+func (f *b2file) sha1() (string, bool) {
+	h := cmn.BackendHelpers.B2
+	if v, ok := h.EncodeCksum(f.ContentSha1); ok {
+		return v, true
+	}
+	if v := f.FileInfo[b2LargeSha1]; v != "" {
+		return h.EncodeCksum(v)
+	}
+	return "", false
+}
+
+func (f *b2file) mtime() string {
+	if f.UploadTimestamp == 0 {
+		return ""
+	}
+	return fmtTime(time.UnixMilli(f.UploadTimestamp))
+}
+
+func (f *b2file) setCustom(oa *cmn.ObjAttrs) (expCksum *cos.Cksum) {
+	h := cmn.BackendHelpers.B2
+	oa.SetCustomKey(cmn.SourceObjMD, apc.B2)
+	if v, ok := h.EncodeVersion(f.FileID); ok {
+		oa.SetVersion(v)
+		oa.SetCustomKey(cmn.VersionObjMD, v)
+	}
+	if v, ok := h.EncodeCksum(f.ContentMd5); ok {
+		oa.SetCustomKey(cmn.MD5ObjMD, v)
+		expCksum = cos.NewCksum(cos.ChecksumMD5, v)
+	}
+	if v, ok := f.sha1(); ok {
+		oa.SetCustomKey(cmn.SHA1ObjMD, v)
+	}
+	if v := f.mtime(); v != "" {
+		oa.SetCustomKey(cmn.LastModified, v)
+	}
+	return expCksum
+}
+
+///////////////
+// b2trailer //
+///////////////
+
+func (tr *b2trailer) Read(p []byte) (int, error) {
+	if tr.b == nil {
+		tr.sum = hex.EncodeToString(tr.h.Sum(nil))
+		tr.b = []byte(tr.sum)
+	}
+	if len(tr.b) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, tr.b)
+	tr.b = tr.b[n:]
+	return n, nil
+}
+
+// percent-encode file name (B2: all except unreserved characters and '/')
+func b2Escape(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + 8)
+	for i := range len(s) {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '-', c == '.', c == '_', c == '~', c == '/':
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte('%')
+			sb.WriteString(strings.ToUpper(hex.EncodeToString([]byte{c})))
+		}
+	}
+	return sb.String()
+}
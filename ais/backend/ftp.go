@@ -0,0 +1,457 @@
+//go:build ftp
+
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/api/env"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// SFTP and FTP(S) servers (e.g., legacy vendor drop sites) as read-only remote storage:
+// - env.FTP.URL selects the protocol (sftp://, ftps:// - explicit TLS, or ftp://) and the root
+//   directory; top-level directories under the root are buckets, nested ones - virtual subdirectories;
+// - connections are pooled and reused, with at most env.FTP.MaxConns open at any given time;
+// - range reads (SFTP: pipelined READ requests, FTP: REST) enable parallel chunked
+//   downloads (see blob downloader);
+// - no versioning: synthetic ETag (modification time and size) serves to detect remote changes;
+// - intended usage: ingestion via prefetch, copy-bucket, and cold GET - no PUT, no DELETE.
+
+const ftpMaxConns = 8
+
+type (
+	ftpbp struct {
+		t    core.TargetPut
+		pool *ftpPool
+		root string // root directory ("": login directory)
+		base
+	}
+	// protocol-specific (SFTP or FTP(S)) connection
+	ftpConn interface {
+		stat(path string) (*ftpEntry, error)
+		readdir(path string) ([]ftpEntry, error)
+		// reads [offset, offset+length) or, if length is zero, till the end;
+		// the connection remains busy until the returned reader is closed
+		read(path string, offset, length int64) (io.ReadCloser, error)
+		close()
+	}
+	ftpEntry struct {
+		mtime time.Time
+		name  string // base name
+		size  int64
+		isDir bool
+	}
+	ftpPool struct {
+		dial func() (ftpConn, error)
+		sema chan struct{}
+		idle []ftpConn
+		mu   sync.Mutex
+	}
+	// returns connection to the pool upon Close
+	ftpReader struct {
+		io.ReadCloser
+		pool *ftpPool
+		conn ftpConn
+	}
+)
+
+// interface guard
+var _ core.Backend = (*ftpbp)(nil)
+
+func NewFTP(t core.TargetPut, tstats stats.Tracker, startingUp bool) (core.Backend, error) {
+	s := os.Getenv(env.FTP.URL)
+	if s == "" {
+		return nil, fmt.Errorf("%s: missing %s", apc.DisplayProvider(apc.FTP), env.FTP.URL)
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("%s: invalid %s=%q", apc.DisplayProvider(apc.FTP), env.FTP.URL, s)
+	}
+	maxConns := ftpMaxConns
+	if v := os.Getenv(env.FTP.MaxConns); v != "" {
+		if maxConns, err = strconv.Atoi(v); err != nil || maxConns <= 0 {
+			return nil, fmt.Errorf("%s: invalid %s=%q", apc.DisplayProvider(apc.FTP), env.FTP.MaxConns, v)
+		}
+	}
+
+	user := u.User.Username()
+	pass, _ := u.User.Password()
+	if v := os.Getenv(env.FTP.Username); v != "" {
+		user = v
+	}
+	if v := os.Getenv(env.FTP.Password); v != "" {
+		pass = v
+	}
+	skipVerify := cos.IsParseBool(os.Getenv(env.FTP.SkipVerify))
+
+	var dial func() (ftpConn, error)
+	switch u.Scheme {
+	case "sftp":
+		cfg, err := sftpConfig(user, pass, skipVerify)
+		if err != nil {
+			return nil, err
+		}
+		addr := ftpHostPort(u, "22")
+		dial = func() (ftpConn, error) { return sftpDial(addr, cfg) }
+	case "ftps", "ftp":
+		var tlsConf *tls.Config
+		if u.Scheme == "ftps" {
+			tlsConf = &tls.Config{
+				ServerName:         u.Hostname(),
+				InsecureSkipVerify: skipVerify, //nolint:gosec // (user's choice)
+				// data connections (typically) must resume control connection's TLS session
+				ClientSessionCache: tls.NewLRUClientSessionCache(0),
+				MinVersion:         tls.VersionTLS12,
+			}
+		}
+		addr := ftpHostPort(u, "21")
+		dial = func() (ftpConn, error) { return ftpDial(addr, user, pass, tlsConf) }
+	default:
+		return nil, fmt.Errorf("%s: unsupported scheme %q (expecting sftp, ftps, or ftp)", apc.DisplayProvider(apc.FTP), u.Scheme)
+	}
+
+	bp := &ftpbp{
+		t:    t,
+		pool: &ftpPool{dial: dial, sema: make(chan struct{}, maxConns)},
+		root: u.Path,
+		base: base{provider: apc.FTP},
+	}
+	// register metrics
+	bp.base.init(t.Snode(), tstats, startingUp)
+
+	nlog.Infoln(apc.DisplayProvider(apc.FTP), "root:", u.Redacted(), "max-conns:", maxConns)
+	return bp, nil
+}
+
+func ftpHostPort(u *url.URL, port string) string {
+	if p := u.Port(); p != "" {
+		port = p
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// given path relative to the root directory
+func (bp *ftpbp) path(rel string) string {
+	switch bp.root {
+	case "":
+		return rel
+	case "/":
+		return "/" + rel
+	default:
+		return strings.TrimSuffix(bp.root, "/") + "/" + rel
+	}
+}
+
+// execute with a pooled connection; retry once if a reused (idle) connection fails
+func (bp *ftpbp) do(cb func(ftpConn) error) error {
+	for retry := false; ; retry = true {
+		c, reused, err := bp.pool.get()
+		if err != nil {
+			return err
+		}
+		err = cb(c)
+		bp.pool.put(c, err)
+		if err == nil || !reused || retry || cos.IsNotExist(err, 0) {
+			return err
+		}
+	}
+}
+
+func ftpErr(err error) (int, error) {
+	if cos.IsNotExist(err, 0) {
+		return http.StatusNotFound, err
+	}
+	return 0, err
+}
+
+// object not found => bucket not found?
+func (bp *ftpbp) objErr(cloudBck *cmn.Bck, err error) (int, error) {
+	ecode, err := ftpErr(err)
+	if ecode == http.StatusNotFound {
+		errB := bp.do(func(c ftpConn) (err error) { _, err = c.stat(bp.path(cloudBck.Name)); return err })
+		if cos.IsNotExist(errB, 0) {
+			return ecode, cmn.NewErrRemoteBckNotFound(cloudBck)
+		}
+	}
+	return ecode, err
+}
+
+// nginx/Apache style: "<mtime>-<size>" (hex)
+func ftpETag(en *ftpEntry) string {
+	return strconv.FormatInt(en.mtime.Unix(), 16) + "-" + strconv.FormatInt(en.size, 16)
+}
+
+func ftpSetCustom(oa *cmn.ObjAttrs, en *ftpEntry) {
+	oa.SetCustomKey(cmn.SourceObjMD, apc.FTP)
+	oa.SetCustomKey(cmn.ETag, ftpETag(en))
+	if !en.mtime.IsZero() {
+		oa.SetCustomKey(cmn.LastModified, fmtTime(en.mtime))
+	}
+}
+
+/////////////
+// ftpPool //
+/////////////
+
+func (p *ftpPool) get() (ftpConn, bool, error) {
+	p.sema <- struct{}{}
+	p.mu.Lock()
+	if l := len(p.idle); l > 0 {
+		c := p.idle[l-1]
+		p.idle = p.idle[:l-1]
+		p.mu.Unlock()
+		return c, true, nil
+	}
+	p.mu.Unlock()
+	c, err := p.dial()
+	if err != nil {
+		<-p.sema
+		return nil, false, err
+	}
+	return c, false, nil
+}
+
+// keep the connection unless it failed (and may be unusable)
+func (p *ftpPool) put(c ftpConn, err error) {
+	if err == nil || cos.IsNotExist(err, 0) {
+		p.mu.Lock()
+		p.idle = append(p.idle, c)
+		p.mu.Unlock()
+	} else {
+		c.close()
+	}
+	<-p.sema
+}
+
+func (r *ftpReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.pool.put(r.conn, err)
+	return err
+}
+
+// as core.Backend --------------------------------------------------------------
+
+//
+// HEAD BUCKET
+//
+
+func (bp *ftpbp) HeadBucket(_ context.Context, bck *meta.Bck) (cos.StrKVs, int, error) {
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infof("head_bucket %s", bck.Name)
+	}
+	var (
+		en       *ftpEntry
+		cloudBck = bck.RemoteBck()
+	)
+	err := bp.do(func(c ftpConn) (err error) { en, err = c.stat(bp.path(cloudBck.Name)); return err })
+	if err != nil {
+		ecode, err := ftpErr(err)
+		if ecode == http.StatusNotFound {
+			err = cmn.NewErrRemoteBckNotFound(cloudBck)
+		}
+		return nil, ecode, err
+	}
+	if !en.isDir {
+		return nil, http.StatusBadRequest, fmt.Errorf("%s: %q is not a directory", apc.DisplayProvider(apc.FTP), cloudBck.Name)
+	}
+	bckProps := make(cos.StrKVs, 1)
+	bckProps[apc.HdrBackendProvider] = apc.FTP
+	return bckProps, 0, nil
+}
+
+//
+// LIST OBJECTS
+//
+
+func (bp *ftpbp) ListObjects(bck *meta.Bck, msg *apc.LsoMsg, lst *cmn.LsoRes) (int, error) {
+	var (
+		cloudBck = bck.RemoteBck()
+		bckDir   = cloudBck.Name + "/"
+	)
+	msg.PageSize = calcPageSize(msg.PageSize, bck.MaxPageSize())
+
+	readdir := func(dir string) ([]lsoDirent, int, error) {
+		var entries []ftpEntry
+		err := bp.do(func(c ftpConn) (err error) {
+			entries, err = c.readdir(bp.path(bckDir + dir))
+			return err
+		})
+		if err != nil {
+			ecode, err := ftpErr(err)
+			return nil, ecode, err
+		}
+		dents := make([]lsoDirent, 0, len(entries))
+		for i := range entries {
+			en := &entries[i]
+			if en.isDir {
+				dents = append(dents, lsoDirent{name: dir + en.name + "/"})
+				continue
+			}
+			dents = append(dents, lsoDirent{
+				name:   dir + en.name,
+				size:   en.size,
+				custom: cmn.CustomProps2S(cmn.ETag, ftpETag(en), cmn.LastModified, fmtTime(en.mtime)),
+			})
+		}
+		sort.Slice(dents, func(i, j int) bool { return dents[i].name < dents[j].name })
+		return dents, 0, nil
+	}
+	if ecode, err := walkPage(readdir, msg, lst); err != nil {
+		if ecode == http.StatusNotFound {
+			err = cmn.NewErrRemoteBckNotFound(cloudBck)
+		}
+		return ecode, err
+	}
+
+	if cmn.Rom.FastV(4, cos.SmoduleBackend) {
+		nlog.Infof("[list_objects] count %d", len(lst.Entries))
+	}
+	return 0, nil
+}
+
+//
+// LIST BUCKETS
+//
+
+func (bp *ftpbp) ListBuckets(cmn.QueryBcks) (bcks cmn.Bcks, ecode int, err error) {
+	var entries []ftpEntry
+	err = bp.do(func(c ftpConn) (err error) {
+		dir := bp.root
+		if dir == "" {
+			dir = "."
+		}
+		entries, err = c.readdir(dir)
+		return err
+	})
+	if err != nil {
+		ecode, err = ftpErr(err)
+		return nil, ecode, err
+	}
+	bcks = make(cmn.Bcks, 0, len(entries))
+	for i := range entries {
+		if entries[i].isDir {
+			bcks = append(bcks, cmn.Bck{Name: entries[i].name, Provider: apc.FTP})
+		}
+	}
+	return bcks, 0, nil
+}
+
+//
+// HEAD OBJECT
+//
+
+func (bp *ftpbp) HeadObj(_ context.Context, lom *core.LOM, _ *http.Request) (*cmn.ObjAttrs, int, error) {
+	cloudBck := lom.Bck().RemoteBck()
+	en, ecode, err := bp.stat(cloudBck, lom.ObjName)
+	if err != nil {
+		return nil, ecode, err
+	}
+	oa := &cmn.ObjAttrs{}
+	oa.CustomMD = make(cos.StrKVs, 3)
+	oa.Size = en.size
+	ftpSetCustom(oa, en)
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infof("[head_object] %s", cloudBck.Cname(lom.ObjName))
+	}
+	return oa, 0, nil
+}
+
+func (bp *ftpbp) stat(cloudBck *cmn.Bck, objName string) (en *ftpEntry, ecode int, err error) {
+	err = bp.do(func(c ftpConn) (err error) { en, err = c.stat(bp.path(cloudBck.Name + "/" + objName)); return err })
+	if err == nil && en.isDir {
+		err = cos.NewErrNotFound(cloudBck, "object "+objName) // (directory)
+	}
+	if err != nil {
+		ecode, err = bp.objErr(cloudBck, err)
+	}
+	return en, ecode, err
+}
+
+//
+// GET OBJECT
+//
+
+func (bp *ftpbp) GetObj(ctx context.Context, lom *core.LOM, owt cmn.OWT, _ *http.Request) (int, error) {
+	res := bp.GetObjReader(ctx, lom, 0, 0)
+	if res.Err != nil {
+		return res.ErrCode, res.Err
+	}
+	params := allocPutParams(res, owt)
+	err := bp.t.PutObject(lom, params)
+	core.FreePutParams(params)
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infoln("[get_object]", lom.String(), err)
+	}
+	return 0, err
+}
+
+func (bp *ftpbp) GetObjReader(_ context.Context, lom *core.LOM, offset, length int64) (res core.GetReaderResult) {
+	var (
+		cloudBck = lom.Bck().RemoteBck()
+		path     = bp.path(cloudBck.Name + "/" + lom.ObjName)
+	)
+	res.Size = length
+	if length == 0 {
+		en, ecode, err := bp.stat(cloudBck, lom.ObjName)
+		if err != nil {
+			res.ErrCode, res.Err = ecode, err
+			return res
+		}
+		// custom metadata
+		ftpSetCustom(lom.ObjAttrs(), en)
+		res.Size = en.size - offset
+	}
+	for retry := false; ; retry = true {
+		c, reused, err := bp.pool.get()
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		r, err := c.read(path, offset, length)
+		if err == nil {
+			res.R = &ftpReader{ReadCloser: r, pool: bp.pool, conn: c}
+			return res
+		}
+		bp.pool.put(c, err)
+		if !reused || retry || cos.IsNotExist(err, 0) {
+			res.ErrCode, res.Err = bp.objErr(cloudBck, err)
+			return res
+		}
+	}
+}
+
+//
+// PUT and DELETE: not supported
+//
+
+func (*ftpbp) PutObj(r io.ReadCloser, _ *core.LOM, _ *http.Request) (int, error) {
+	cos.Close(r)
+	return http.StatusBadRequest, cmn.NewErrUnsupp("PUT", " objects => (S)FTP backend")
+}
+
+func (*ftpbp) DeleteObj(*core.LOM) (int, error) {
+	return http.StatusBadRequest, cmn.NewErrUnsupp("DELETE", " objects from (S)FTP backend")
+}
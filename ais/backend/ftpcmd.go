@@ -0,0 +1,424 @@
+//go:build ftp
+
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+)
+
+// Minimal FTP client: passive mode, binary, read-only; optionally, explicit TLS (FTPS).
+// Listing and stat use MLSD/MLST when available (RFC 3659) and fall back to Unix-style LIST
+// and SIZE/MDTM otherwise.
+// Ref: https://www.rfc-editor.org/rfc/rfc959, rfc4217 (FTPS), rfc2428 (EPSV), rfc3659
+
+const (
+	ftpCodeNoSuch = 550
+	ftpMdtm       = "20060102150405"
+)
+
+type (
+	ftpcConn struct {
+		conn   net.Conn
+		tp     *textproto.Conn
+		tls    *tls.Config // nil: plain FTP
+		host   string
+		home   string // login directory
+		mlst   bool   // RFC 3659
+		noEPSV bool
+	}
+	ftpcReader struct {
+		c     *ftpcConn
+		dconn net.Conn
+		r     io.Reader
+		eof   bool
+	}
+)
+
+// interface guard
+var _ ftpConn = (*ftpcConn)(nil)
+
+func ftpDial(addr, user, pass string, tlsConf *tls.Config) (ftpConn, error) {
+	conn, err := net.DialTimeout("tcp", addr, cmn.GCO.Get().Client.Timeout.D())
+	if err != nil {
+		return nil, err
+	}
+	c := &ftpcConn{conn: conn, tp: textproto.NewConn(conn), tls: tlsConf}
+	c.host, _, _ = net.SplitHostPort(addr)
+	if err := c.login(user, pass); err != nil {
+		c.conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *ftpcConn) login(user, pass string) error {
+	c.deadline()
+	if _, _, err := c.tp.ReadResponse(220); err != nil {
+		return err
+	}
+	if c.tls != nil {
+		if _, _, err := c.cmd(234, "AUTH TLS"); err != nil {
+			return err
+		}
+		tconn := tls.Client(c.conn, c.tls)
+		if err := tconn.Handshake(); err != nil {
+			return err
+		}
+		c.conn, c.tp = tconn, textproto.NewConn(tconn)
+	}
+	if user == "" {
+		user, pass = "anonymous", "anonymous@"
+	}
+	code, _, err := c.cmd(0, "USER %s", user)
+	switch {
+	case err != nil:
+		return err
+	case code == 331:
+		if _, _, err := c.cmd(230, "PASS %s", pass); err != nil {
+			return err
+		}
+	case code != 230:
+		return fmt.Errorf("ftp: login failed (%d)", code)
+	}
+	if c.tls != nil {
+		if _, _, err := c.cmd(200, "PBSZ 0"); err != nil {
+			return err
+		}
+		if _, _, err := c.cmd(200, "PROT P"); err != nil {
+			return err
+		}
+	}
+	if _, _, err := c.cmd(200, "TYPE I"); err != nil {
+		return err
+	}
+	if _, msg, err := c.cmd(257, "PWD"); err == nil {
+		if i, j := strings.IndexByte(msg, '"'), strings.LastIndexByte(msg, '"'); i >= 0 && j > i {
+			c.home = msg[i+1 : j]
+		}
+	}
+	if _, msg, err := c.cmd(211, "FEAT"); err == nil {
+		c.mlst = strings.Contains(strings.ToUpper(msg), "MLST")
+	}
+	return nil
+}
+
+// control connection: bounded by client timeout (and note that data transfers are not)
+func (c *ftpcConn) deadline() {
+	c.conn.SetDeadline(time.Now().Add(cmn.GCO.Get().Client.Timeout.D()))
+}
+
+// expect: 0 - any code; 1, 2, ... - any 1xx, 2xx, ...
+func (c *ftpcConn) cmd(expect int, format string, args ...any) (int, string, error) {
+	c.deadline()
+	id, err := c.tp.Cmd(format, args...)
+	if err != nil {
+		return 0, "", err
+	}
+	c.tp.StartResponse(id)
+	defer c.tp.EndResponse(id)
+	return c.tp.ReadResponse(expect)
+}
+
+func (c *ftpcConn) close() {
+	c.deadline()
+	c.tp.Cmd("QUIT") //nolint:errcheck // best effort
+	c.conn.Close()
+}
+
+// passive mode data connection
+func (c *ftpcConn) dial() (net.Conn, error) {
+	var port string
+	if !c.noEPSV {
+		// "229 Entering Extended Passive Mode (|||port|)"
+		_, msg, err := c.cmd(229, "EPSV")
+		if err == nil {
+			i, j := strings.Index(msg, "(|||"), strings.LastIndex(msg, "|)")
+			if i < 0 || j < i+4 {
+				return nil, fmt.Errorf("ftp: invalid EPSV response %q", msg)
+			}
+			port = msg[i+4 : j]
+		} else {
+			c.noEPSV = true
+		}
+	}
+	if port == "" {
+		// "227 Entering Passive Mode (h1,h2,h3,h4,p1,p2)" - using control connection's host
+		// (and ignoring h1-h4, which are often wrong behind NAT)
+		_, msg, err := c.cmd(227, "PASV")
+		if err != nil {
+			return nil, err
+		}
+		i, j := strings.IndexByte(msg, '('), strings.IndexByte(msg, ')')
+		if i < 0 || j < i {
+			return nil, fmt.Errorf("ftp: invalid PASV response %q", msg)
+		}
+		parts := strings.Split(msg[i+1:j], ",")
+		if len(parts) != 6 {
+			return nil, fmt.Errorf("ftp: invalid PASV response %q", msg)
+		}
+		p1, err1 := strconv.Atoi(strings.TrimSpace(parts[4]))
+		p2, err2 := strconv.Atoi(strings.TrimSpace(parts[5]))
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("ftp: invalid PASV response %q", msg)
+		}
+		port = strconv.Itoa(p1<<8 | p2)
+	}
+	dconn, err := net.DialTimeout("tcp", net.JoinHostPort(c.host, port), cmn.GCO.Get().Client.Timeout.D())
+	if err != nil {
+		return nil, err
+	}
+	if c.tls != nil {
+		dconn = tls.Client(dconn, c.tls)
+	}
+	return dconn, nil
+}
+
+// open data connection and start transfer
+func (c *ftpcConn) transfer(format string, args ...any) (net.Conn, error) {
+	dconn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := c.cmd(1, format, args...); err != nil {
+		dconn.Close()
+		return nil, err
+	}
+	return dconn, nil
+}
+
+// read transfer's final reply
+func (c *ftpcConn) done() error {
+	c.deadline()
+	_, _, err := c.tp.ReadResponse(2)
+	return err
+}
+
+func ftpcErr(err error, p string) error {
+	var e *textproto.Error
+	if errors.As(err, &e) && e.Code == ftpCodeNoSuch {
+		return cos.NewErrNotFound(nil, "ftp: "+p)
+	}
+	return err
+}
+
+//
+// ftpConn interface
+//
+
+func (c *ftpcConn) stat(p string) (*ftpEntry, error) {
+	if c.mlst {
+		// "250-Listing p\r\n type=file;size=123;modify=20240101000000; p\r\n250 End"
+		_, msg, err := c.cmd(250, "MLST %s", p)
+		if err != nil {
+			return nil, ftpcErr(err, p)
+		}
+		for _, line := range strings.Split(msg, "\n") {
+			if en, ok := parseMLSx(strings.TrimSpace(line)); ok {
+				en.name = path.Base(p)
+				return en, nil
+			}
+		}
+		return nil, fmt.Errorf("ftp: invalid MLST response %q", msg)
+	}
+
+	en := &ftpEntry{name: path.Base(p)}
+	_, msg, err := c.cmd(213, "SIZE %s", p)
+	if err != nil {
+		// directory?
+		if _, _, errD := c.cmd(250, "CWD %s", p); errD != nil {
+			return nil, ftpcErr(err, p)
+		}
+		if _, _, err := c.cmd(250, "CWD %s", c.home); err != nil {
+			return nil, err
+		}
+		en.isDir = true
+		return en, nil
+	}
+	if en.size, err = strconv.ParseInt(strings.TrimSpace(msg), 10, 64); err != nil {
+		return nil, fmt.Errorf("ftp: invalid SIZE response %q", msg)
+	}
+	if _, msg, err := c.cmd(213, "MDTM %s", p); err == nil {
+		s := strings.TrimSpace(msg) // (may include fractional seconds)
+		en.mtime, _ = time.Parse(ftpMdtm, s[:min(len(ftpMdtm), len(s))])
+	}
+	return en, nil
+}
+
+func (c *ftpcConn) readdir(p string) ([]ftpEntry, error) {
+	command := "LIST %s"
+	if c.mlst {
+		command = "MLSD %s"
+	}
+	dconn, err := c.transfer(command, p)
+	if err != nil {
+		return nil, ftpcErr(err, p)
+	}
+	var (
+		entries []ftpEntry
+		now     = time.Now()
+		scanner = bufio.NewScanner(dconn)
+	)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		var (
+			en *ftpEntry
+			ok bool
+		)
+		if c.mlst {
+			en, ok = parseMLSx(line)
+		} else {
+			en, ok = parseLIST(line, now)
+		}
+		if ok && en.name != "." && en.name != ".." {
+			entries = append(entries, *en)
+		}
+	}
+	err = scanner.Err()
+	dconn.Close()
+	if errD := c.done(); err == nil {
+		err = errD
+	}
+	return entries, err
+}
+
+func (c *ftpcConn) read(p string, offset, length int64) (io.ReadCloser, error) {
+	if offset > 0 {
+		if _, _, err := c.cmd(350, "REST %d", offset); err != nil {
+			return nil, err
+		}
+	}
+	dconn, err := c.transfer("RETR %s", p)
+	if err != nil {
+		return nil, ftpcErr(err, p)
+	}
+	r := &ftpcReader{c: c, dconn: dconn, r: dconn}
+	if length > 0 {
+		r.r = io.LimitReader(dconn, length)
+	}
+	return r, nil
+}
+
+////////////////
+// ftpcReader //
+////////////////
+
+func (r *ftpcReader) Read(p []byte) (n int, err error) {
+	n, err = r.r.Read(p)
+	if err == io.EOF {
+		r.eof = true
+	}
+	return n, err
+}
+
+var errFtpAborted = errors.New("ftp: transfer aborted")
+
+// a transfer that was not read till the end leaves control connection
+// in an uncertain state (ABOR handling varies) - not to reuse
+func (r *ftpcReader) Close() error {
+	r.dconn.Close()
+	if !r.eof {
+		return errFtpAborted
+	}
+	if _, isLimited := r.r.(*io.LimitedReader); isLimited {
+		return errFtpAborted // (ditto: the server may have more to send)
+	}
+	return r.c.done()
+}
+
+//
+// parsing
+//
+
+// RFC 3659 facts: "type=file;size=123;modify=20240101000000; name"
+func parseMLSx(line string) (*ftpEntry, bool) {
+	facts, name, ok := strings.Cut(line, " ")
+	if !ok || name == "" {
+		return nil, false
+	}
+	var (
+		en    = &ftpEntry{name: name}
+		typed bool
+	)
+	for _, fact := range strings.Split(facts, ";") {
+		k, v, ok := strings.Cut(fact, "=")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(k) {
+		case "type":
+			typed = true
+			switch strings.ToLower(v) {
+			case "file":
+			case "dir":
+				en.isDir = true
+			default:
+				return nil, false // cdir, pdir, and OS-specific types
+			}
+		case "size":
+			en.size, _ = strconv.ParseInt(v, 10, 64)
+		case "modify":
+			en.mtime, _ = time.Parse(ftpMdtm, v[:min(len(ftpMdtm), len(v))])
+		}
+	}
+	return en, typed
+}
+
+// Unix-style: "-rw-r--r--   1 owner group   1234 Jan 02 15:04 name" (or "Jan 02  2006")
+func parseLIST(line string, now time.Time) (*ftpEntry, bool) {
+	var (
+		fields []string
+		rest   = line
+	)
+	for len(fields) < 8 {
+		rest = strings.TrimLeft(rest, " ")
+		i := strings.IndexByte(rest, ' ')
+		if i < 0 {
+			return nil, false
+		}
+		fields = append(fields, rest[:i])
+		rest = rest[i:]
+	}
+	name := strings.TrimLeft(rest, " ")
+	if name == "" || len(fields[0]) < 10 {
+		return nil, false
+	}
+	en := &ftpEntry{name: name}
+	switch fields[0][0] {
+	case 'd':
+		en.isDir = true
+	case 'l':
+		en.name, _, _ = strings.Cut(name, " -> ")
+	case '-':
+	default:
+		return nil, false
+	}
+	en.size, _ = strconv.ParseInt(fields[4], 10, 64)
+	stamp := fields[5] + " " + fields[6] + " " + fields[7]
+	if t, err := time.Parse("Jan 2 2006", stamp); err == nil {
+		en.mtime = t
+	} else if t, err := time.Parse("Jan 2 15:04", stamp); err == nil {
+		// (no year: within the past 6 months)
+		en.mtime = t.AddDate(now.Year(), 0, 0)
+		if en.mtime.After(now) {
+			en.mtime = en.mtime.AddDate(-1, 0, 0)
+		}
+	}
+	return en, true
+}
@@ -0,0 +1,604 @@
+//go:build ftp
+
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/env"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Minimal SFTP client: protocol version 3, read-only subset (stat, readdir, read).
+// Requests are multiplexed over a single SSH session - in particular, reads are pipelined
+// (up to sftpWindow outstanding READ requests per reader).
+// Ref: https://datatracker.ietf.org/doc/html/draft-ietf-secsh-filexfer-02
+
+const (
+	sftpProtoVersion = 3
+
+	sftpInit     = 1
+	sftpVersion  = 2
+	sftpOpen     = 3
+	sftpClose    = 4
+	sftpRead     = 5
+	sftpOpendir  = 11
+	sftpReaddir  = 12
+	sftpStat     = 17
+	sftpStatus   = 101
+	sftpHandle   = 102
+	sftpData     = 103
+	sftpName     = 104
+	sftpAttrs    = 105
+	sftpFxfRead  = 0x1
+	sftpEOF      = 1
+	sftpNoSuch   = 2
+	sftpAttrSize = 0x1
+	sftpAttrUID  = 0x2
+	sftpAttrPerm = 0x4
+	sftpAttrTime = 0x8
+	sftpAttrExt  = 0x80000000
+
+	sftpChunk  = 32 * cos.KiB // max READ (that all servers support)
+	sftpWindow = 16
+	sftpMaxPkt = 256 * cos.KiB
+)
+
+type (
+	sftpConn struct {
+		ssh     *ssh.Client
+		sess    *ssh.Session
+		w       io.WriteCloser
+		r       io.Reader
+		pending map[uint32]chan []byte
+		err     error // receive loop's exit error
+		id      atomic.Uint32
+		wmu     sync.Mutex
+		pmu     sync.Mutex
+	}
+	sftpReader struct {
+		c      *sftpConn
+		err    error
+		handle string
+		queue  []sftpReq
+		buf    []byte
+		off    int64 // next offset to request
+		end    int64 // -1: till EOF
+		eof    bool
+	}
+	sftpReq struct {
+		ch  chan []byte
+		off int64
+		n   int64
+	}
+	// request encoding
+	sftpBuf []byte
+	// response decoding
+	sftpDec struct {
+		b   []byte
+		err error
+	}
+)
+
+// interface guard
+var _ ftpConn = (*sftpConn)(nil)
+
+func sftpConfig(user, pass string, skipVerify bool) (*ssh.ClientConfig, error) {
+	if user == "" {
+		return nil, fmt.Errorf("sftp: missing username (%s)", env.FTP.Username)
+	}
+	var auths []ssh.AuthMethod
+	if keyFile := os.Getenv(env.FTP.SSHKey); keyFile != "" {
+		b, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("sftp: failed to read SSH key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(b)
+		if _, ok := err.(*ssh.PassphraseMissingError); ok && pass != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(b, []byte(pass))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sftp: invalid SSH key %q: %w", keyFile, err)
+		}
+		auths = append(auths, ssh.PublicKeys(signer))
+	}
+	if pass != "" {
+		auths = append(auths, ssh.Password(pass))
+	}
+	if len(auths) == 0 {
+		return nil, fmt.Errorf("sftp: missing credentials (%s and/or %s)", env.FTP.Password, env.FTP.SSHKey)
+	}
+
+	cfg := &ssh.ClientConfig{User: user, Auth: auths, Timeout: cmn.GCO.Get().Client.Timeout.D()}
+	if skipVerify {
+		cfg.HostKeyCallback = ssh.InsecureIgnoreHostKey() //nolint:gosec // (user's choice)
+		return cfg, nil
+	}
+	kh := os.Getenv(env.FTP.KnownHosts)
+	if kh == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("sftp: %w (hint: %s or %s)", err, env.FTP.KnownHosts, env.FTP.SkipVerify)
+		}
+		kh = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	cb, err := knownhosts.New(kh)
+	if err != nil {
+		return nil, fmt.Errorf("sftp: %w (hint: %s or %s)", err, env.FTP.KnownHosts, env.FTP.SkipVerify)
+	}
+	cfg.HostKeyCallback = cb
+	return cfg, nil
+}
+
+func sftpDial(addr string, cfg *ssh.ClientConfig) (ftpConn, error) {
+	client, err := ssh.Dial("tcp", addr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	c := &sftpConn{ssh: client, pending: make(map[uint32]chan []byte, sftpWindow)}
+	if err := c.init(); err != nil {
+		c.close()
+		return nil, err
+	}
+	go c.recv()
+	return c, nil
+}
+
+func (c *sftpConn) init() (err error) {
+	if c.sess, err = c.ssh.NewSession(); err != nil {
+		return err
+	}
+	if c.w, err = c.sess.StdinPipe(); err != nil {
+		return err
+	}
+	if c.r, err = c.sess.StdoutPipe(); err != nil {
+		return err
+	}
+	if err = c.sess.RequestSubsystem("sftp"); err != nil {
+		return err
+	}
+	// handshake (note: INIT and VERSION carry no request ID)
+	b := sftpBuf{0, 0, 0, 0}
+	b.u8(sftpInit)
+	b.u32(sftpProtoVersion)
+	if err = c.write(b); err != nil {
+		return err
+	}
+	pkt, err := c.readPacket()
+	if err != nil {
+		return err
+	}
+	if len(pkt) < 5 || pkt[0] != sftpVersion {
+		return errors.New("sftp: protocol error (expecting VERSION)")
+	}
+	if v := binary.BigEndian.Uint32(pkt[1:5]); v < sftpProtoVersion {
+		return fmt.Errorf("sftp: unsupported protocol version %d", v)
+	}
+	return nil
+}
+
+func (c *sftpConn) close() {
+	if c.sess != nil {
+		c.sess.Close()
+	}
+	c.ssh.Close()
+}
+
+func (c *sftpConn) write(b sftpBuf) error {
+	binary.BigEndian.PutUint32(b[:4], uint32(len(b)-4))
+	c.wmu.Lock()
+	_, err := c.w.Write(b)
+	c.wmu.Unlock()
+	return err
+}
+
+func (c *sftpConn) readPacket() ([]byte, error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(c.r, hdr[:]); err != nil {
+		return nil, err
+	}
+	l := binary.BigEndian.Uint32(hdr[:])
+	if l == 0 || l > sftpMaxPkt {
+		return nil, fmt.Errorf("sftp: invalid packet length %d", l)
+	}
+	pkt := make([]byte, l)
+	_, err := io.ReadFull(c.r, pkt)
+	return pkt, err
+}
+
+// receive loop: dispatch responses by request ID
+func (c *sftpConn) recv() {
+	var err error
+	for {
+		var pkt []byte
+		if pkt, err = c.readPacket(); err != nil {
+			break
+		}
+		if len(pkt) < 5 {
+			err = errors.New("sftp: protocol error (short packet)")
+			break
+		}
+		id := binary.BigEndian.Uint32(pkt[1:5])
+		c.pmu.Lock()
+		ch, ok := c.pending[id]
+		delete(c.pending, id)
+		c.pmu.Unlock()
+		if ok {
+			ch <- pkt
+		}
+	}
+	c.pmu.Lock()
+	c.err = err
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+	c.pmu.Unlock()
+}
+
+func (c *sftpConn) send(typ byte, fill func(*sftpBuf)) (chan []byte, error) {
+	var (
+		id = c.id.Add(1)
+		ch = make(chan []byte, 1)
+		b  = make(sftpBuf, 4, 64)
+	)
+	c.pmu.Lock()
+	if c.err != nil {
+		c.pmu.Unlock()
+		return nil, c.err
+	}
+	c.pending[id] = ch
+	c.pmu.Unlock()
+
+	b.u8(typ)
+	b.u32(id)
+	fill(&b)
+	if err := c.write(b); err != nil {
+		c.pmu.Lock()
+		delete(c.pending, id)
+		c.pmu.Unlock()
+		return nil, err
+	}
+	return ch, nil
+}
+
+// returns response type and decoder (positioned past request ID)
+func (c *sftpConn) wait(ch chan []byte) (byte, *sftpDec, error) {
+	pkt, ok := <-ch
+	if !ok {
+		c.pmu.Lock()
+		err := c.err
+		c.pmu.Unlock()
+		if err == nil || err == io.EOF {
+			err = errors.New("sftp: connection closed")
+		}
+		return 0, nil, err
+	}
+	return pkt[0], &sftpDec{b: pkt[5:]}, nil
+}
+
+func (c *sftpConn) call(typ byte, fill func(*sftpBuf)) (byte, *sftpDec, error) {
+	ch, err := c.send(typ, fill)
+	if err != nil {
+		return 0, nil, err
+	}
+	return c.wait(ch)
+}
+
+func (c *sftpConn) handle(typ byte, p string, fill func(*sftpBuf)) (string, error) {
+	rtyp, d, err := c.call(typ, fill)
+	if err != nil {
+		return "", err
+	}
+	switch rtyp {
+	case sftpHandle:
+		h := d.str()
+		return h, d.err
+	case sftpStatus:
+		return "", d.status(p)
+	default:
+		return "", fmt.Errorf("sftp: protocol error (unexpected response %d)", rtyp)
+	}
+}
+
+func (c *sftpConn) closeHandle(h string) error {
+	rtyp, d, err := c.call(sftpClose, func(b *sftpBuf) { b.str(h) })
+	if err != nil {
+		return err
+	}
+	if rtyp != sftpStatus {
+		return fmt.Errorf("sftp: protocol error (unexpected response %d)", rtyp)
+	}
+	return d.status("")
+}
+
+//
+// ftpConn interface
+//
+
+func (c *sftpConn) stat(p string) (*ftpEntry, error) {
+	rtyp, d, err := c.call(sftpStat, func(b *sftpBuf) { b.str(p) })
+	if err != nil {
+		return nil, err
+	}
+	switch rtyp {
+	case sftpAttrs:
+		en := &ftpEntry{name: path.Base(p)}
+		d.attrs(en)
+		return en, d.err
+	case sftpStatus:
+		return nil, d.status(p)
+	default:
+		return nil, fmt.Errorf("sftp: protocol error (unexpected response %d)", rtyp)
+	}
+}
+
+func (c *sftpConn) readdir(p string) ([]ftpEntry, error) {
+	h, err := c.handle(sftpOpendir, p, func(b *sftpBuf) { b.str(p) })
+	if err != nil {
+		return nil, err
+	}
+	entries, err := c._readdir(h, p)
+	if errC := c.closeHandle(h); err == nil {
+		err = errC
+	}
+	return entries, err
+}
+
+func (c *sftpConn) _readdir(h, p string) (entries []ftpEntry, _ error) {
+	for {
+		rtyp, d, err := c.call(sftpReaddir, func(b *sftpBuf) { b.str(h) })
+		if err != nil {
+			return nil, err
+		}
+		switch rtyp {
+		case sftpName:
+			for n := d.u32(); n > 0 && d.err == nil; n-- {
+				en := ftpEntry{name: d.str()}
+				_ = d.str() // longname
+				d.attrs(&en)
+				if en.name != "." && en.name != ".." {
+					entries = append(entries, en)
+				}
+			}
+			if d.err != nil {
+				return nil, d.err
+			}
+		case sftpStatus:
+			if err := d.status(p); err != io.EOF {
+				return nil, err
+			}
+			return entries, nil
+		default:
+			return nil, fmt.Errorf("sftp: protocol error (unexpected response %d)", rtyp)
+		}
+	}
+}
+
+func (c *sftpConn) read(p string, offset, length int64) (io.ReadCloser, error) {
+	h, err := c.handle(sftpOpen, p, func(b *sftpBuf) {
+		b.str(p)
+		b.u32(sftpFxfRead)
+		b.u32(0) // (no attributes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := &sftpReader{c: c, handle: h, off: offset, end: -1}
+	if length > 0 {
+		r.end = offset + length
+	}
+	return r, nil
+}
+
+////////////////
+// sftpReader //
+////////////////
+
+func (r *sftpReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.eof {
+			return 0, io.EOF
+		}
+		r.fill()
+		if len(r.queue) == 0 {
+			r.eof = true
+			continue
+		}
+		req := r.queue[0]
+		r.queue = r.queue[1:]
+		if err := r.recv(&req); err != nil {
+			r.err = err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// keep up to sftpWindow requests in flight
+func (r *sftpReader) fill() {
+	for len(r.queue) < sftpWindow && r.err == nil && (r.end < 0 || r.off < r.end) {
+		n := int64(sftpChunk)
+		if r.end >= 0 {
+			n = min(n, r.end-r.off)
+		}
+		if err := r.request(r.off, n, false); err != nil {
+			r.err = err
+			return
+		}
+		r.off += n
+	}
+}
+
+func (r *sftpReader) request(off, n int64, front bool) error {
+	ch, err := r.c.send(sftpRead, func(b *sftpBuf) {
+		b.str(r.handle)
+		b.u64(uint64(off))
+		b.u32(uint32(n))
+	})
+	if err != nil {
+		return err
+	}
+	req := sftpReq{ch: ch, off: off, n: n}
+	if front {
+		r.queue = append([]sftpReq{req}, r.queue...)
+	} else {
+		r.queue = append(r.queue, req)
+	}
+	return nil
+}
+
+func (r *sftpReader) recv(req *sftpReq) error {
+	rtyp, d, err := r.c.wait(req.ch)
+	if err != nil {
+		return err
+	}
+	switch rtyp {
+	case sftpData:
+		data := d.bytes()
+		if d.err != nil {
+			return d.err
+		}
+		if len(data) == 0 {
+			r.eof = true
+			return nil
+		}
+		r.buf = data
+		// short read: request the remainder ahead of everything else in flight
+		if short := req.n - int64(len(data)); short > 0 {
+			return r.request(req.off+int64(len(data)), short, true)
+		}
+		return nil
+	case sftpStatus:
+		err := d.status("")
+		if err == io.EOF {
+			r.eof = true
+			return nil
+		}
+		return err
+	default:
+		return fmt.Errorf("sftp: protocol error (unexpected response %d)", rtyp)
+	}
+}
+
+// responses to (abandoned) requests still in flight get dispatched and dropped
+func (r *sftpReader) Close() error {
+	err := r.c.closeHandle(r.handle)
+	if r.err != nil {
+		err = r.err
+	}
+	return err
+}
+
+/////////////////////
+// sftpBuf/sftpDec //
+/////////////////////
+
+func (b *sftpBuf) u8(v byte)    { *b = append(*b, v) }
+func (b *sftpBuf) u32(v uint32) { *b = binary.BigEndian.AppendUint32(*b, v) }
+func (b *sftpBuf) u64(v uint64) { *b = binary.BigEndian.AppendUint64(*b, v) }
+
+func (b *sftpBuf) str(s string) {
+	b.u32(uint32(len(s)))
+	*b = append(*b, s...)
+}
+
+func (d *sftpDec) u32() uint32 {
+	if d.err != nil || len(d.b) < 4 {
+		d.fail()
+		return 0
+	}
+	v := binary.BigEndian.Uint32(d.b)
+	d.b = d.b[4:]
+	return v
+}
+
+func (d *sftpDec) u64() uint64 {
+	if d.err != nil || len(d.b) < 8 {
+		d.fail()
+		return 0
+	}
+	v := binary.BigEndian.Uint64(d.b)
+	d.b = d.b[8:]
+	return v
+}
+
+func (d *sftpDec) bytes() []byte {
+	l := d.u32()
+	if d.err != nil || uint32(len(d.b)) < l {
+		d.fail()
+		return nil
+	}
+	v := d.b[:l]
+	d.b = d.b[l:]
+	return v
+}
+
+func (d *sftpDec) str() string { return string(d.bytes()) }
+
+func (d *sftpDec) fail() {
+	if d.err == nil {
+		d.err = errors.New("sftp: protocol error (malformed packet)")
+	}
+}
+
+func (d *sftpDec) attrs(en *ftpEntry) {
+	flags := d.u32()
+	if flags&sftpAttrSize != 0 {
+		en.size = int64(d.u64())
+	}
+	if flags&sftpAttrUID != 0 {
+		d.u32()
+		d.u32()
+	}
+	if flags&sftpAttrPerm != 0 {
+		en.isDir = d.u32()&0o170000 == 0o040000
+	}
+	if flags&sftpAttrTime != 0 {
+		d.u32() // atime
+		en.mtime = time.Unix(int64(d.u32()), 0)
+	}
+	if flags&sftpAttrExt != 0 {
+		for n := d.u32(); n > 0 && d.err == nil; n-- {
+			d.str()
+			d.str()
+		}
+	}
+}
+
+// STATUS => error (nil if OK; io.EOF)
+func (d *sftpDec) status(p string) error {
+	code, msg := d.u32(), d.str()
+	switch {
+	case d.err != nil:
+		return d.err
+	case code == 0:
+		return nil
+	case code == sftpEOF:
+		return io.EOF
+	case code == sftpNoSuch:
+		return cos.NewErrNotFound(nil, "sftp: "+p)
+	default:
+		return fmt.Errorf("sftp-error[%s: %s (%d)]", p, msg, code)
+	}
+}
@@ -8,12 +8,14 @@ package backend
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 
 	"cloud.google.com/go/storage"
 	"github.com/NVIDIA/aistore/api/apc"
@@ -39,6 +41,8 @@ const (
 	projectIDField  = "project_id"
 	projectIDEnvVar = "GOOGLE_CLOUD_PROJECT"
 	credPathEnvVar  = "GOOGLE_APPLICATION_CREDENTIALS" //nolint:gosec // false positive G101
+
+	gcpCSEKLen = 32 // AES-256
 )
 
 type (
@@ -59,6 +63,9 @@ var (
 	// context placeholder
 	gctx context.Context
 
+	// customer-supplied encryption keys: bucket prop `extra.gcp.csek_file` => decoded key
+	gcpCSEKs sync.Map
+
 	// interface guard
 	_ core.Backend = (*gsbp)(nil)
 )
@@ -273,7 +280,11 @@ func (*gsbp) HeadObj(ctx context.Context, lom *core.LOM, _ *http.Request) (oa *c
 		h        = cmn.BackendHelpers.Google
 		cloudBck = lom.Bck().RemoteBck()
 	)
-	attrs, err = gcpClient.Bucket(cloudBck.Name).Object(lom.ObjName).Attrs(ctx)
+	o, err := gcpObject(cloudBck, lom.ObjName)
+	if err != nil {
+		return nil, 0, err
+	}
+	attrs, err = o.Attrs(ctx)
 	if err != nil {
 		ecode, err = handleObjectError(ctx, gcpClient, err, cloudBck)
 		return
@@ -335,8 +346,12 @@ func (*gsbp) GetObjReader(ctx context.Context, lom *core.LOM, offset, length int
 		attrs    *storage.ObjectAttrs
 		rc       *storage.Reader
 		cloudBck = lom.Bck().RemoteBck()
-		o        = gcpClient.Bucket(cloudBck.Name).Object(lom.ObjName)
 	)
+	o, err := gcpObject(cloudBck, lom.ObjName)
+	if err != nil {
+		res.Err = err
+		return res
+	}
 	attrs, res.Err = o.Attrs(ctx)
 	if res.Err != nil {
 		res.ErrCode, res.Err = gcpErrorToAISError(res.Err, cloudBck)
@@ -403,12 +418,19 @@ func (gsbp *gsbp) PutObj(r io.ReadCloser, lom *core.LOM, _ *http.Request) (ecode
 		written  int64
 		cloudBck = lom.Bck().RemoteBck()
 		md       = make(cos.StrKVs, 2)
-		gcpObj   = gcpClient.Bucket(cloudBck.Name).Object(lom.ObjName)
-		wc       = gcpObj.NewWriter(gctx)
 	)
+	gcpObj, err := gcpObject(cloudBck, lom.ObjName)
+	if err != nil {
+		cos.Close(r)
+		return 0, err
+	}
+	wc := gcpObj.NewWriter(gctx)
 	md[gcpChecksumType], md[gcpChecksumVal] = lom.Checksum().Get()
 
 	wc.Metadata = md
+	if cloudBck.Props != nil && cloudBck.Props.Extra.GCP.KMSKey != "" {
+		wc.KMSKeyName = cloudBck.Props.Extra.GCP.KMSKey // CMEK (otherwise, bucket's default key, if any)
+	}
 	buf, slab := gsbp.t.PageMM().Alloc()
 	written, err = io.CopyBuffer(wc, r, buf)
 	slab.Free(buf)
@@ -455,6 +477,40 @@ func (*gsbp) DeleteObj(lom *core.LOM) (ecode int, err error) {
 // static helpers
 //
 
+// object handle that carries customer-supplied encryption key (CSEK), if configured;
+// GCS requires the same key to read object's data and checksums, and to overwrite it
+func gcpObject(cloudBck *cmn.Bck, objName string) (*storage.ObjectHandle, error) {
+	o := gcpClient.Bucket(cloudBck.Name).Object(objName)
+	if cloudBck.Props == nil || cloudBck.Props.Extra.GCP.CSEKFile == "" {
+		return o, nil
+	}
+	key, err := loadCSEK(cloudBck.Props.Extra.GCP.CSEKFile)
+	if err != nil {
+		return nil, cmn.NewErrFailedTo(nil, "load customer-supplied encryption key for", cloudBck.Cname(""), err)
+	}
+	return o.Key(key), nil
+}
+
+// NOTE: cached upon first use - to rotate, write the new key to a new file and update the bucket
+func loadCSEK(fqn string) ([]byte, error) {
+	if v, ok := gcpCSEKs.Load(fqn); ok {
+		return v.([]byte), nil
+	}
+	b, err := os.ReadFile(fqn)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid base64: %v", fqn, err)
+	}
+	if len(key) != gcpCSEKLen {
+		return nil, fmt.Errorf("%s: invalid key length %d (expecting %d-byte AES-256 key)", fqn, len(key), gcpCSEKLen)
+	}
+	gcpCSEKs.Store(fqn, key)
+	return key, nil
+}
+
 func readCredFile() (projectID string) {
 	credFile, err := os.Open(os.Getenv(credPathEnvVar))
 	if err != nil {
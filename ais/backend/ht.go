@@ -24,9 +24,10 @@ import (
 
 type (
 	htbp struct {
-		t      core.TargetPut
-		cliH   *http.Client
-		cliTLS *http.Client
+		t         core.TargetPut
+		cliH      *http.Client
+		cliTLS    *http.Client
+		manifests htManifests // buckets defined by manifest (see htmanifest.go)
 		base
 	}
 )
@@ -54,6 +55,15 @@ func (htbp *htbp) client(u string) *http.Client {
 func (htbp *htbp) HeadBucket(ctx context.Context, bck *meta.Bck) (bckProps cos.StrKVs, ecode int, err error) {
 	// TODO: we should use `bck.RemoteBck()`.
 
+	if murl := bckManifest(ctx, bck); murl != "" {
+		if _, ecode, err = htbp.manifest(murl); err != nil {
+			return nil, ecode, err
+		}
+		bckProps = make(cos.StrKVs, 1)
+		bckProps[apc.HdrBackendProvider] = apc.HT
+		return bckProps, 0, nil
+	}
+
 	origURL, err := getOriginalURL(ctx, bck, "")
 	if err != nil {
 		return nil, http.StatusBadRequest, err
@@ -85,9 +95,23 @@ func (htbp *htbp) HeadBucket(ctx context.Context, bck *meta.Bck) (bckProps cos.S
 	return
 }
 
-func (*htbp) ListObjects(*meta.Bck, *apc.LsoMsg, *cmn.LsoRes) (ecode int, err error) {
-	debug.Assert(false)
-	return
+// (only buckets defined by manifest are listable - see proxy's listObjects)
+func (htbp *htbp) ListObjects(bck *meta.Bck, msg *apc.LsoMsg, lst *cmn.LsoRes) (int, error) {
+	murl := bckManifest(context.Background(), bck)
+	if murl == "" {
+		return http.StatusBadRequest, cmn.NewErrUnsupp("list", " objects in HTTP bucket with no manifest")
+	}
+	ents, ecode, err := htbp.manifest(murl)
+	if err != nil {
+		return ecode, err
+	}
+	msg.PageSize = calcPageSize(msg.PageSize, bck.MaxPageSize())
+	htListPage(ents, msg, lst)
+
+	if cmn.Rom.FastV(4, cos.SmoduleBackend) {
+		nlog.Infof("[list_objects] count %d", len(lst.Entries))
+	}
+	return 0, nil
 }
 
 func (*htbp) ListBuckets(cmn.QueryBcks) (bcks cmn.Bcks, ecode int, err error) {
@@ -95,6 +119,37 @@ func (*htbp) ListBuckets(cmn.QueryBcks) (bcks cmn.Bcks, ecode int, err error) {
 	return
 }
 
+// manifest applies unless the request carries its own original URL
+func bckManifest(ctx context.Context, bck *meta.Bck) string {
+	if origURL, ok := ctx.Value(cos.CtxOriginalURL).(string); ok && origURL != "" {
+		return ""
+	}
+	if bck.Props == nil {
+		return ""
+	}
+	return bck.Props.Extra.HTTP.Manifest
+}
+
+// returns object's URL and, when the bucket is defined by manifest, the corresponding entry
+func (htbp *htbp) objURL(ctx context.Context, lom *core.LOM) (string, *htEntry, int, error) {
+	bck := lom.Bck() // TODO: This should be `cloudBck = lom.Bck().RemoteBck()`
+	murl := bckManifest(ctx, bck)
+	if murl == "" {
+		origURL, err := getOriginalURL(ctx, bck, lom.ObjName)
+		debug.AssertNoErr(err)
+		return origURL, nil, 0, nil
+	}
+	ents, ecode, err := htbp.manifest(murl)
+	if err != nil {
+		return "", nil, ecode, err
+	}
+	en := findHtEntry(ents, lom.ObjName)
+	if en == nil {
+		return "", nil, http.StatusNotFound, cos.NewErrNotFound(bck, "object "+lom.ObjName+" (not in manifest)")
+	}
+	return en.url, en, 0, nil
+}
+
 func getOriginalURL(ctx context.Context, bck *meta.Bck, objName string) (string, error) {
 	origURL, ok := ctx.Value(cos.CtxOriginalURL).(string)
 	if !ok || origURL == "" {
@@ -111,12 +166,11 @@ func getOriginalURL(ctx context.Context, bck *meta.Bck, objName string) (string,
 }
 
 func (htbp *htbp) HeadObj(ctx context.Context, lom *core.LOM, _ *http.Request) (oa *cmn.ObjAttrs, ecode int, err error) {
-	var (
-		h   = cmn.BackendHelpers.HTTP
-		bck = lom.Bck() // TODO: This should be `cloudBck = lom.Bck().RemoteBck()`
-	)
-	origURL, err := getOriginalURL(ctx, bck, lom.ObjName)
-	debug.AssertNoErr(err)
+	h := cmn.BackendHelpers.HTTP
+	origURL, en, ecode, err := htbp.objURL(ctx, lom)
+	if err != nil {
+		return nil, ecode, err
+	}
 
 	if cmn.Rom.FastV(4, cos.SmoduleBackend) {
 		nlog.Infof("[head_object] original_url: %q", origURL)
@@ -137,6 +191,10 @@ func (htbp *htbp) HeadObj(ctx context.Context, lom *core.LOM, _ *http.Request) (
 	if v, ok := h.EncodeETag(resp.Header.Get(cos.HdrETag)); ok {
 		oa.SetCustomKey(cmn.ETag, v)
 	}
+	if en != nil {
+		en.setCustom(oa)
+		oa.Cksum = en.cksum()
+	}
 	if cmn.Rom.FastV(4, cos.SmoduleBackend) {
 		nlog.Infof("[head_object] %s", lom)
 	}
@@ -165,18 +223,20 @@ func (htbp *htbp) GetObjReader(ctx context.Context, lom *core.LOM, offset, lengt
 		req  *http.Request
 		resp *http.Response
 		h    = cmn.BackendHelpers.HTTP
-		bck  = lom.Bck() // TODO: This should be `cloudBck = lom.Bck().RemoteBck()`
 	)
 
-	origURL, err := getOriginalURL(ctx, bck, lom.ObjName)
-	debug.AssertNoErr(err)
+	origURL, en, ecode, err := htbp.objURL(ctx, lom)
+	if err != nil {
+		res.ErrCode, res.Err = ecode, err
+		return res
+	}
 
 	if cmn.Rom.FastV(4, cos.SmoduleBackend) {
 		nlog.Infof("[HTTP CLOUD][GET] original_url: %q", origURL)
 	}
 
 	req, res.Err = http.NewRequest(http.MethodGet, origURL, http.NoBody)
-	if err != nil {
+	if res.Err != nil {
 		res.ErrCode = http.StatusInternalServerError
 		return res
 	}
@@ -188,7 +248,8 @@ func (htbp *htbp) GetObjReader(ctx context.Context, lom *core.LOM, offset, lengt
 	if res.Err != nil {
 		return res
 	}
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
 		res.ErrCode = resp.StatusCode
 		res.Err = fmt.Errorf("error occurred: %v", resp.StatusCode)
 		return res
@@ -203,6 +264,12 @@ func (htbp *htbp) GetObjReader(ctx context.Context, lom *core.LOM, offset, lengt
 	if v, ok := h.EncodeETag(resp.Header.Get(cos.HdrETag)); ok {
 		lom.SetCustomKey(cmn.ETag, v)
 	}
+	if en != nil {
+		en.setCustom(lom.ObjAttrs())
+		if length == 0 {
+			res.ExpCksum = en.cksum()
+		}
+	}
 	res.Size = resp.ContentLength
 	res.R = resp.Body
 	return res
@@ -0,0 +1,368 @@
+//go:build ht
+
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// ht:// bucket defined by manifest (bucket property `extra.http.manifest`):
+// a JSON array or CSV list of object URLs with (optional) names, sizes, and checksums:
+//
+//	[{"url": "https://example.com/data/shard-000.tar", "size": 1048576, "checksum": "md5:1f3870be274f6c49b3e31a0c6728957f"}, ...]
+//
+//	# url,size,checksum,name (header and comment lines are optional)
+//	https://example.com/data/shard-000.tar,1048576,md5:1f3870be274f6c49b3e31a0c6728957f
+//
+// - object name defaults to the URL path relative to the manifest's location or, if
+//   the URL is elsewhere, to "<host>/<path>";
+// - checksum is "<type>:<value>"; md5, crc32c, sha512, and xxhash are validated
+//   upon cold GET (given `validate_cold_get`), other types are only recorded
+//   as custom metadata;
+// - loaded manifests are cached and conditionally revalidated every htManifestTTL.
+
+const (
+	htManifestTTL = time.Minute
+
+	htHdrIfNoneMatch     = "If-None-Match"
+	htHdrIfModifiedSince = "If-Modified-Since"
+	htHdrLastModified    = "Last-Modified"
+)
+
+type (
+	htEntry struct {
+		name  string
+		url   string
+		ckty  string
+		ckval string
+		size  int64
+	}
+	htManifest struct {
+		etag    string
+		lastMod string
+		ents    []htEntry // sorted by name
+		checked int64     // mono.NanoTime
+		mu      sync.Mutex
+	}
+	htManifests struct {
+		m  map[string]*htManifest
+		mu sync.Mutex
+	}
+	// JSON manifest entry
+	htJSONEntry struct {
+		Name     string `json:"name"`
+		URL      string `json:"url"`
+		Checksum string `json:"checksum"`
+		Size     int64  `json:"size"`
+	}
+)
+
+func (bp *htbp) manifest(murl string) ([]htEntry, int, error) {
+	mfs := &bp.manifests
+	mfs.mu.Lock()
+	if mfs.m == nil {
+		mfs.m = make(map[string]*htManifest, 4)
+	}
+	mf, ok := mfs.m[murl]
+	if !ok {
+		mf = &htManifest{}
+		mfs.m[murl] = mf
+	}
+	mfs.mu.Unlock()
+
+	mf.mu.Lock()
+	defer mf.mu.Unlock()
+	if mf.ents != nil && mono.Since(mf.checked) < htManifestTTL {
+		return mf.ents, 0, nil
+	}
+	if ecode, err := bp.loadManifest(murl, mf); err != nil {
+		if mf.ents == nil {
+			return nil, ecode, err
+		}
+		// keep serving the last loaded version
+		nlog.Warningln("failed to revalidate manifest", murl, "err:", err)
+	}
+	mf.checked = mono.NanoTime()
+	return mf.ents, 0, nil
+}
+
+func (bp *htbp) loadManifest(murl string, mf *htManifest) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, murl, http.NoBody)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	if mf.ents != nil {
+		if mf.etag != "" {
+			req.Header.Set(htHdrIfNoneMatch, mf.etag)
+		}
+		if mf.lastMod != "" {
+			req.Header.Set(htHdrIfModifiedSince, mf.lastMod)
+		}
+	}
+	resp, err := bp.client(murl).Do(req)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	defer cos.DrainReader(resp.Body)
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return 0, nil
+	default:
+		return resp.StatusCode, fmt.Errorf("GET manifest %q failed, status %d", murl, resp.StatusCode)
+	}
+
+	base, err := url.Parse(murl)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	ents, err := parseManifest(bufio.NewReader(resp.Body), base)
+	if err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid manifest %q: %w", murl, err)
+	}
+	mf.ents = ents
+	mf.etag = resp.Header.Get(cos.HdrETag)
+	mf.lastMod = resp.Header.Get(htHdrLastModified)
+	if cmn.Rom.FastV(4, cos.SmoduleBackend) {
+		nlog.Infof("[manifest] %q: %d entries", murl, len(ents))
+	}
+	return 0, nil
+}
+
+// JSON array, CSV otherwise
+func parseManifest(br *bufio.Reader, base *url.URL) (ents []htEntry, err error) {
+	var b byte
+	for {
+		if b, err = br.ReadByte(); err != nil {
+			if err == io.EOF {
+				err = errors.New("empty")
+			}
+			return nil, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			break
+		}
+	}
+	if err = br.UnreadByte(); err != nil {
+		return nil, err
+	}
+
+	if b == '[' {
+		ents, err = parseManifestJSON(br, base)
+	} else {
+		ents, err = parseManifestCSV(br, base)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(ents, func(i, j int) bool { return ents[i].name < ents[j].name })
+	for i := 1; i < len(ents); i++ {
+		if ents[i].name == ents[i-1].name {
+			return nil, fmt.Errorf("duplicate object name %q", ents[i].name)
+		}
+	}
+	return ents, nil
+}
+
+func parseManifestJSON(r io.Reader, base *url.URL) ([]htEntry, error) {
+	var jents []htJSONEntry
+	if err := jsoniter.NewDecoder(r).Decode(&jents); err != nil {
+		return nil, err
+	}
+	ents := make([]htEntry, 0, len(jents))
+	for i := range jents {
+		je := &jents[i]
+		en, err := newHtEntry(base, je.URL, je.Name, je.Checksum, je.Size)
+		if err != nil {
+			return nil, fmt.Errorf("entry #%d: %w", i, err)
+		}
+		ents = append(ents, en)
+	}
+	return ents, nil
+}
+
+// columns: url[,size[,checksum[,name]]]
+func parseManifestCSV(r io.Reader, base *url.URL) ([]htEntry, error) {
+	var (
+		ents []htEntry
+		cr   = csv.NewReader(r)
+	)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.ReuseRecord = true
+	for first := true; ; first = false {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if first && strings.EqualFold(rec[0], "url") {
+			continue // header
+		}
+		line, _ := cr.FieldPos(0)
+		var (
+			size        int64
+			cksum, name string
+		)
+		if len(rec) > 1 && rec[1] != "" {
+			if size, err = strconv.ParseInt(rec[1], 10, 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid size %q", line, rec[1])
+			}
+		}
+		if len(rec) > 2 {
+			cksum = rec[2]
+		}
+		if len(rec) > 3 {
+			name = rec[3]
+		}
+		en, err := newHtEntry(base, rec[0], name, cksum, size)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		ents = append(ents, en)
+	}
+	return ents, nil
+}
+
+func newHtEntry(base *url.URL, rawURL, name, cksum string, size int64) (en htEntry, err error) {
+	u, err := base.Parse(rawURL) // (relative URLs are relative to the manifest)
+	if err != nil {
+		return en, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return en, fmt.Errorf("invalid URL %q (expecting http(s)://)", rawURL)
+	}
+	if size < 0 {
+		return en, fmt.Errorf("%q: negative size %d", rawURL, size)
+	}
+	if name == "" {
+		dir := path.Dir(base.Path)
+		if dir != "/" {
+			dir += "/"
+		}
+		if u.Host == base.Host && strings.HasPrefix(u.Path, dir) {
+			name = strings.TrimPrefix(u.Path, dir)
+		} else {
+			name = u.Host + u.Path
+		}
+	}
+	name = strings.TrimLeft(name, "/")
+	if name == "" || cos.IsLastB(name, '/') {
+		return en, fmt.Errorf("%q: invalid object name %q", rawURL, name)
+	}
+	en = htEntry{name: name, url: u.String(), size: size}
+	if cksum != "" {
+		ty, val, ok := strings.Cut(cksum, ":")
+		if !ok || ty == "" || val == "" {
+			return en, fmt.Errorf("%q: invalid checksum %q (expecting <type>:<value>)", rawURL, cksum)
+		}
+		en.ckty, en.ckval = strings.ToLower(ty), val
+	}
+	return en, nil
+}
+
+func findHtEntry(ents []htEntry, name string) *htEntry {
+	i := sort.Search(len(ents), func(i int) bool { return ents[i].name >= name })
+	if i < len(ents) && ents[i].name == name {
+		return &ents[i]
+	}
+	return nil
+}
+
+// checksum to validate cold GET (sha256 excluded - AIS "sha256" is SHA-512/256)
+func (en *htEntry) cksum() *cos.Cksum {
+	switch en.ckty {
+	case cos.ChecksumMD5, cos.ChecksumCRC32C, cos.ChecksumSHA512, cos.ChecksumXXHash:
+		return cos.NewCksum(en.ckty, en.ckval)
+	default:
+		return nil
+	}
+}
+
+func (en *htEntry) setCustom(oa *cmn.ObjAttrs) {
+	if en.ckty != "" {
+		oa.SetCustomKey(en.ckty, en.ckval)
+	}
+}
+
+func htListPage(ents []htEntry, msg *apc.LsoMsg, lst *cmn.LsoRes) {
+	var (
+		prefix, token = msg.Prefix, msg.ContinuationToken
+		noRecurs      = msg.IsFlagSet(apc.LsNoRecursion)
+		wantCustom    = !msg.IsFlagSet(apc.LsNameOnly) && !msg.IsFlagSet(apc.LsNameSize) && msg.WantProp(apc.GetPropsCustom)
+		wantCksum     = !msg.IsFlagSet(apc.LsNameOnly) && !msg.IsFlagSet(apc.LsNameSize) && msg.WantProp(apc.GetPropsChecksum)
+		start         = max(prefix, token)
+	)
+	lst.Entries = lst.Entries[:0]
+	lst.ContinuationToken = ""
+
+	i := sort.Search(len(ents), func(i int) bool { return ents[i].name >= start })
+	for i < len(ents) && strings.HasPrefix(ents[i].name, prefix) {
+		en := &ents[i]
+		var e *cmn.LsoEnt
+		if j := strings.IndexByte(en.name[len(prefix):], '/'); noRecurs && j >= 0 {
+			// virtual directory: emit once and skip its contents
+			dir := en.name[:len(prefix)+j+1]
+			for i < len(ents) && strings.HasPrefix(ents[i].name, dir) {
+				i++
+			}
+			if dir <= token || msg.IsFlagSet(apc.LsNoDirs) {
+				continue
+			}
+			e = &cmn.LsoEnt{Name: dir, Flags: apc.EntryIsDir}
+		} else {
+			i++
+			if en.name <= token {
+				continue
+			}
+			e = &cmn.LsoEnt{Name: en.name, Size: en.size}
+			if wantCksum {
+				if ck := en.cksum(); ck != nil {
+					e.Checksum = ck.Value()
+				}
+			}
+			if wantCustom {
+				if en.ckty != "" {
+					e.Custom = cmn.CustomProps2S(cmn.OrigURLObjMD, en.url, en.ckty, en.ckval)
+				} else {
+					e.Custom = cmn.CustomProps2S(cmn.OrigURLObjMD, en.url)
+				}
+			}
+		}
+		lst.Entries = append(lst.Entries, e)
+		if int64(len(lst.Entries)) >= msg.PageSize {
+			if i < len(ents) && strings.HasPrefix(ents[i].name, prefix) {
+				lst.ContinuationToken = e.Name
+			}
+			break
+		}
+	}
+}
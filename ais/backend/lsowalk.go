@@ -0,0 +1,108 @@
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+)
+
+// List objects (one page at a time) in directory-based remote namespaces (WebDAV, (S)FTP):
+// - depth-first, one directory at a time, in lexicographic order - the order is global
+//   since all names in a given directory share the directory's name as a prefix;
+// - the last returned name is the continuation token;
+// - directories are virtual subdirectories (LsNoRecursion, LsNoDirs).
+
+type (
+	// bucket-relative name (directories: trailing '/')
+	lsoDirent struct {
+		name   string
+		custom string // (see cmn.CustomProps2S)
+		size   int64
+	}
+	// returns directory's entries sorted by name
+	lsoReaddir func(dir string) ([]lsoDirent, int, error)
+
+	lsoWalk struct {
+		readdir lsoReaddir
+		msg     *apc.LsoMsg
+		lst     *cmn.LsoRes
+		full    bool
+	}
+)
+
+// is called with msg.PageSize already adjusted (see calcPageSize)
+func walkPage(readdir lsoReaddir, msg *apc.LsoMsg, lst *cmn.LsoRes) (int, error) {
+	w := &lsoWalk{readdir: readdir, msg: msg, lst: lst}
+	lst.Entries = lst.Entries[:0]
+	lst.ContinuationToken = ""
+
+	// start from the innermost directory that contains the prefix
+	dir := msg.Prefix[:strings.LastIndexByte(msg.Prefix, '/')+1]
+	ecode, err := w.walk(dir)
+	if err != nil && ecode == http.StatusNotFound && dir != "" {
+		err = nil // no such (virtual) directory
+	}
+	return ecode, err
+}
+
+func (w *lsoWalk) walk(dir string) (int, error) {
+	entries, ecode, err := w.readdir(dir)
+	if err != nil {
+		return ecode, err
+	}
+	var (
+		msg      = w.msg
+		token    = msg.ContinuationToken
+		noRecurs = msg.IsFlagSet(apc.LsNoRecursion)
+		wantProp = !msg.IsFlagSet(apc.LsNameOnly) && !msg.IsFlagSet(apc.LsNameSize) && msg.WantProp(apc.GetPropsCustom)
+	)
+	for i := range entries {
+		en := &entries[i]
+		switch {
+		case !cos.IsLastB(en.name, '/'):
+			if en.name > token && strings.HasPrefix(en.name, msg.Prefix) {
+				e := &cmn.LsoEnt{Name: en.name, Size: en.size}
+				if wantProp {
+					e.Custom = en.custom
+				}
+				w.add(e)
+			}
+		case noRecurs:
+			if en.name > token && strings.HasPrefix(en.name, msg.Prefix) && !msg.IsFlagSet(apc.LsNoDirs) {
+				w.add(&cmn.LsoEnt{Name: en.name, Flags: apc.EntryIsDir})
+			}
+		case w.descend(en.name):
+			if ecode, err := w.walk(en.name); err != nil {
+				return ecode, err
+			}
+		}
+		if w.full {
+			break
+		}
+	}
+	return 0, nil
+}
+
+// skip directories that are outside prefix or entirely precede continuation token
+func (w *lsoWalk) descend(dir string) bool {
+	prefix, token := w.msg.Prefix, w.msg.ContinuationToken
+	if !strings.HasPrefix(dir, prefix) && !strings.HasPrefix(prefix, dir) {
+		return false
+	}
+	return dir > token || strings.HasPrefix(token, dir)
+}
+
+func (w *lsoWalk) add(en *cmn.LsoEnt) {
+	w.lst.Entries = append(w.lst.Entries, en)
+	if int64(len(w.lst.Entries)) >= w.msg.PageSize {
+		w.lst.ContinuationToken = en.Name
+		w.full = true
+	}
+}
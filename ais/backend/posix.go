@@ -0,0 +1,406 @@
+//go:build posix
+
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/api/env"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// POSIX directory tree - NFS, Lustre, GPFS, or any other file system mounted
+// at the same path on all targets - as read-through ("cold") remote storage:
+// - env.POSIX.Root is the root directory; top-level directories under the root are buckets,
+//   nested ones - virtual subdirectories;
+// - symbolic links are followed as long as they resolve within the root directory;
+//   other non-regular files are skipped;
+// - range reads enable parallel chunked downloads (see blob downloader);
+// - no versioning: synthetic ETag (modification time and size) serves to detect changes;
+// - intended usage: caching file system datasets via prefetch, copy-bucket, and cold GET
+//   (without copying them first) - no PUT, no DELETE.
+
+const posixMaxReaders = 64
+
+type (
+	posixbp struct {
+		t    core.TargetPut
+		sema chan struct{} // bounds the number of open files (remote file systems are often rate-limited)
+		root string        // absolute, with no symlinks
+		base
+	}
+	// releases sema upon Close
+	posixReader struct {
+		io.Reader
+		fh   *os.File
+		sema chan struct{}
+	}
+)
+
+// interface guard
+var _ core.Backend = (*posixbp)(nil)
+
+func NewPOSIX(t core.TargetPut, tstats stats.Tracker, startingUp bool) (core.Backend, error) {
+	root := os.Getenv(env.POSIX.Root)
+	if root == "" {
+		return nil, fmt.Errorf("%s: missing %s", apc.DisplayProvider(apc.POSIX), env.POSIX.Root)
+	}
+	root, err := filepath.Abs(root)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid %s: %v", apc.DisplayProvider(apc.POSIX), env.POSIX.Root, err)
+	}
+	finfo, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", apc.DisplayProvider(apc.POSIX), err)
+	}
+	if !finfo.IsDir() {
+		return nil, fmt.Errorf("%s: %q is not a directory", apc.DisplayProvider(apc.POSIX), root)
+	}
+	maxReaders := posixMaxReaders
+	if v := os.Getenv(env.POSIX.MaxReaders); v != "" {
+		if maxReaders, err = strconv.Atoi(v); err != nil || maxReaders <= 0 {
+			return nil, fmt.Errorf("%s: invalid %s=%q", apc.DisplayProvider(apc.POSIX), env.POSIX.MaxReaders, v)
+		}
+	}
+
+	bp := &posixbp{
+		t:    t,
+		sema: make(chan struct{}, maxReaders),
+		root: root,
+		base: base{provider: apc.POSIX},
+	}
+	// register metrics
+	bp.base.init(t.Snode(), tstats, startingUp)
+
+	nlog.Infoln(apc.DisplayProvider(apc.POSIX), "root:", root, "max-readers:", maxReaders)
+	return bp, nil
+}
+
+// given bucket and (bucket-relative) name; must resolve inside the bucket's directory
+func (bp *posixbp) path(cloudBck *cmn.Bck, name string) (string, error) {
+	dir := filepath.Join(bp.root, cloudBck.Name)
+	if cloudBck.Name == "" || filepath.Dir(dir) != bp.root {
+		return "", fmt.Errorf("%s: invalid bucket name %q", apc.DisplayProvider(apc.POSIX), cloudBck.Name)
+	}
+	if name == "" {
+		return dir, nil
+	}
+	p := filepath.Join(dir, name)
+	if !strings.HasPrefix(p, dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s: invalid object name %q", apc.DisplayProvider(apc.POSIX), name)
+	}
+	return p, nil
+}
+
+// resolve symlinks (if any) and make sure the result is still under the root
+func (bp *posixbp) resolve(p string) (string, error) {
+	real, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return "", err
+	}
+	if real != p && !strings.HasPrefix(real, bp.root+string(filepath.Separator)) {
+		return "", &fs.PathError{Op: "resolve", Path: p, Err: fs.ErrNotExist}
+	}
+	return real, nil
+}
+
+func posixErr(err error) (int, error) {
+	switch {
+	case os.IsNotExist(err):
+		return http.StatusNotFound, err
+	case os.IsPermission(err):
+		return http.StatusForbidden, err
+	default:
+		return 0, err
+	}
+}
+
+// object not found => bucket not found?
+func (bp *posixbp) objErr(cloudBck *cmn.Bck, err error) (int, error) {
+	ecode, err := posixErr(err)
+	if ecode == http.StatusNotFound {
+		if dir, errP := bp.path(cloudBck, ""); errP == nil {
+			if _, errB := os.Stat(dir); os.IsNotExist(errB) {
+				return ecode, cmn.NewErrRemoteBckNotFound(cloudBck)
+			}
+		}
+	}
+	return ecode, err
+}
+
+// same as nginx and Apache: "<mtime>-<size>" (hex)
+func posixETag(finfo fs.FileInfo) string {
+	return strconv.FormatInt(finfo.ModTime().Unix(), 16) + "-" + strconv.FormatInt(finfo.Size(), 16)
+}
+
+func posixSetCustom(oa *cmn.ObjAttrs, finfo fs.FileInfo) {
+	oa.SetCustomKey(cmn.SourceObjMD, apc.POSIX)
+	oa.SetCustomKey(cmn.ETag, posixETag(finfo))
+	oa.SetCustomKey(cmn.LastModified, fmtTime(finfo.ModTime()))
+}
+
+func (r *posixReader) Close() error {
+	err := r.fh.Close()
+	<-r.sema
+	return err
+}
+
+// as core.Backend --------------------------------------------------------------
+
+//
+// HEAD BUCKET
+//
+
+func (bp *posixbp) HeadBucket(_ context.Context, bck *meta.Bck) (cos.StrKVs, int, error) {
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infof("head_bucket %s", bck.Name)
+	}
+	cloudBck := bck.RemoteBck()
+	dir, err := bp.path(cloudBck, "")
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	var finfo fs.FileInfo
+	if dir, err = bp.resolve(dir); err == nil {
+		finfo, err = os.Stat(dir)
+	}
+	if err != nil {
+		ecode, err := posixErr(err)
+		if ecode == http.StatusNotFound {
+			err = cmn.NewErrRemoteBckNotFound(cloudBck)
+		}
+		return nil, ecode, err
+	}
+	if !finfo.IsDir() {
+		return nil, http.StatusBadRequest, fmt.Errorf("%s: %q is not a directory", apc.DisplayProvider(apc.POSIX), cloudBck.Name)
+	}
+	bckProps := make(cos.StrKVs, 1)
+	bckProps[apc.HdrBackendProvider] = apc.POSIX
+	return bckProps, 0, nil
+}
+
+//
+// LIST OBJECTS
+//
+
+func (bp *posixbp) ListObjects(bck *meta.Bck, msg *apc.LsoMsg, lst *cmn.LsoRes) (int, error) {
+	cloudBck := bck.RemoteBck()
+	bckDir, err := bp.path(cloudBck, "")
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	if bckDir, err = bp.resolve(bckDir); err != nil {
+		return http.StatusNotFound, cmn.NewErrRemoteBckNotFound(cloudBck)
+	}
+	msg.PageSize = calcPageSize(msg.PageSize, bck.MaxPageSize())
+
+	readdir := func(dir string) ([]lsoDirent, int, error) {
+		entries, err := os.ReadDir(filepath.Join(bckDir, dir))
+		if err != nil {
+			ecode, err := posixErr(err)
+			return nil, ecode, err
+		}
+		dents := make([]lsoDirent, 0, len(entries))
+		for _, en := range entries {
+			finfo, err := bp.finfo(bckDir, dir, en)
+			if err != nil {
+				continue // (e.g., dangling symlink, or removed in the meantime)
+			}
+			switch {
+			case finfo.IsDir():
+				dents = append(dents, lsoDirent{name: dir + en.Name() + "/"})
+			case finfo.Mode().IsRegular():
+				dents = append(dents, lsoDirent{
+					name:   dir + en.Name(),
+					size:   finfo.Size(),
+					custom: cmn.CustomProps2S(cmn.ETag, posixETag(finfo), cmn.LastModified, fmtTime(finfo.ModTime())),
+				})
+			}
+		}
+		// (os.ReadDir sorts by file name - not the same as sorting by the name with trailing '/')
+		sort.Slice(dents, func(i, j int) bool { return dents[i].name < dents[j].name })
+		return dents, 0, nil
+	}
+	if ecode, err := walkPage(readdir, msg, lst); err != nil {
+		if ecode == http.StatusNotFound {
+			err = cmn.NewErrRemoteBckNotFound(cloudBck)
+		}
+		return ecode, err
+	}
+
+	if cmn.Rom.FastV(4, cos.SmoduleBackend) {
+		nlog.Infof("[list_objects] count %d", len(lst.Entries))
+	}
+	return 0, nil
+}
+
+// follow symlinks (see resolve)
+func (bp *posixbp) finfo(bckDir, dir string, en fs.DirEntry) (fs.FileInfo, error) {
+	if en.Type()&fs.ModeSymlink == 0 {
+		return en.Info()
+	}
+	real, err := bp.resolve(filepath.Join(bckDir, dir, en.Name()))
+	if err != nil {
+		return nil, err
+	}
+	return os.Stat(real)
+}
+
+//
+// LIST BUCKETS
+//
+
+func (bp *posixbp) ListBuckets(cmn.QueryBcks) (bcks cmn.Bcks, ecode int, err error) {
+	entries, err := os.ReadDir(bp.root)
+	if err != nil {
+		ecode, err = posixErr(err)
+		return nil, ecode, err
+	}
+	bcks = make(cmn.Bcks, 0, len(entries))
+	for _, en := range entries {
+		if finfo, err := bp.finfo(bp.root, "", en); err == nil && finfo.IsDir() {
+			bcks = append(bcks, cmn.Bck{Name: en.Name(), Provider: apc.POSIX})
+		}
+	}
+	return bcks, 0, nil
+}
+
+//
+// HEAD OBJECT
+//
+
+func (bp *posixbp) HeadObj(_ context.Context, lom *core.LOM, _ *http.Request) (*cmn.ObjAttrs, int, error) {
+	cloudBck := lom.Bck().RemoteBck()
+	finfo, ecode, err := bp.stat(cloudBck, lom.ObjName)
+	if err != nil {
+		return nil, ecode, err
+	}
+	oa := &cmn.ObjAttrs{}
+	oa.CustomMD = make(cos.StrKVs, 3)
+	oa.Size = finfo.Size()
+	posixSetCustom(oa, finfo)
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infof("[head_object] %s", cloudBck.Cname(lom.ObjName))
+	}
+	return oa, 0, nil
+}
+
+func (bp *posixbp) stat(cloudBck *cmn.Bck, objName string) (fs.FileInfo, int, error) {
+	p, err := bp.path(cloudBck, objName)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	p, err = bp.resolve(p)
+	if err != nil {
+		ecode, err := bp.objErr(cloudBck, err)
+		return nil, ecode, err
+	}
+	finfo, err := os.Stat(p)
+	if err == nil && !finfo.Mode().IsRegular() {
+		err = cos.NewErrNotFound(cloudBck, "object "+objName) // (directory or special file)
+		return nil, http.StatusNotFound, err
+	}
+	if err != nil {
+		ecode, err := bp.objErr(cloudBck, err)
+		return nil, ecode, err
+	}
+	return finfo, 0, nil
+}
+
+//
+// GET OBJECT
+//
+
+func (bp *posixbp) GetObj(ctx context.Context, lom *core.LOM, owt cmn.OWT, _ *http.Request) (int, error) {
+	res := bp.GetObjReader(ctx, lom, 0, 0)
+	if res.Err != nil {
+		return res.ErrCode, res.Err
+	}
+	params := allocPutParams(res, owt)
+	err := bp.t.PutObject(lom, params)
+	core.FreePutParams(params)
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infoln("[get_object]", lom.String(), err)
+	}
+	return 0, err
+}
+
+func (bp *posixbp) GetObjReader(_ context.Context, lom *core.LOM, offset, length int64) (res core.GetReaderResult) {
+	cloudBck := lom.Bck().RemoteBck()
+	p, err := bp.path(cloudBck, lom.ObjName)
+	if err != nil {
+		res.ErrCode, res.Err = http.StatusBadRequest, err
+		return res
+	}
+	if p, err = bp.resolve(p); err != nil {
+		res.ErrCode, res.Err = bp.objErr(cloudBck, err)
+		return res
+	}
+
+	bp.sema <- struct{}{}
+	fh, err := os.Open(p)
+	if err != nil {
+		<-bp.sema
+		res.ErrCode, res.Err = bp.objErr(cloudBck, err)
+		return res
+	}
+	finfo, err := fh.Stat()
+	if err == nil && !finfo.Mode().IsRegular() {
+		err = cos.NewErrNotFound(cloudBck, "object "+lom.ObjName)
+		res.ErrCode = http.StatusNotFound
+	}
+	if err != nil {
+		fh.Close()
+		<-bp.sema
+		res.Err = err
+		return res
+	}
+
+	res.Size = length
+	if length == 0 {
+		// custom metadata
+		posixSetCustom(lom.ObjAttrs(), finfo)
+		res.Size = finfo.Size() - offset
+	}
+	r := &posixReader{Reader: fh, fh: fh, sema: bp.sema}
+	if offset > 0 || length > 0 {
+		r.Reader = io.NewSectionReader(fh, offset, res.Size)
+	}
+	res.R = r
+	return res
+}
+
+//
+// PUT and DELETE: not supported
+//
+
+func (*posixbp) PutObj(r io.ReadCloser, _ *core.LOM, _ *http.Request) (int, error) {
+	cos.Close(r)
+	return http.StatusBadRequest, cmn.NewErrUnsupp("PUT", " objects => POSIX backend")
+}
+
+func (*posixbp) DeleteObj(*core.LOM) (int, error) {
+	return http.StatusBadRequest, cmn.NewErrUnsupp("DELETE", " objects from POSIX backend")
+}
@@ -0,0 +1,548 @@
+//go:build webdav
+
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/api/env"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// WebDAV server (e.g., Nextcloud, Apache mod_dav) as remote storage:
+// - env.WebDAV.URL is the root collection; its top-level collections are buckets;
+// - list: PROPFIND (Depth: 1), collection by collection (see walkPage);
+//   collections are virtual directories;
+// - GET: range reads; PUT: chunked transfer encoding, with missing parent collections
+//   created (MKCOL) on demand;
+// - no versioning: ETag and Last-Modified serve to detect remote changes.
+// Ref: https://www.rfc-editor.org/rfc/rfc4918
+
+const (
+	davPropfind = "PROPFIND"
+	davMkcol    = "MKCOL"
+
+	davHdrDepth        = "Depth"
+	davHdrLastModified = "Last-Modified"
+	davHdrExpectedLen  = "X-Expected-Entity-Length" // chunked PUT: total size (quota checks, progress)
+
+	davPropfindBody = `<?xml version="1.0" encoding="utf-8"?>` +
+		`<d:propfind xmlns:d="DAV:"><d:prop>` +
+		`<d:resourcetype/><d:getcontentlength/><d:getetag/><d:getlastmodified/><d:getcontenttype/>` +
+		`</d:prop></d:propfind>`
+)
+
+type (
+	davbp struct {
+		t      core.TargetPut
+		client *http.Client
+		root   *url.URL
+		user   string
+		pass   string
+		dirs   sync.Map // existing collections (to skip MKCOL)
+		base
+	}
+	davMultistatus struct {
+		Responses []davResponse `xml:"DAV: response"`
+	}
+	davResponse struct {
+		Href      string        `xml:"DAV: href"`
+		Propstats []davPropstat `xml:"DAV: propstat"`
+	}
+	davPropstat struct {
+		Status string  `xml:"DAV: status"`
+		Prop   davProp `xml:"DAV: prop"`
+	}
+	davProp struct {
+		ResourceType struct {
+			Collection *struct{} `xml:"DAV: collection"`
+		} `xml:"DAV: resourcetype"`
+		ContentType   string `xml:"DAV: getcontenttype"`
+		ETag          string `xml:"DAV: getetag"`
+		LastModified  string `xml:"DAV: getlastmodified"`
+		ContentLength int64  `xml:"DAV: getcontentlength"`
+	}
+	// PROPFIND result: file or collection (with trailing '/'), relative to the root
+	davEntry struct {
+		prop *davProp
+		name string
+	}
+)
+
+// interface guard
+var _ core.Backend = (*davbp)(nil)
+
+func NewWebDAV(t core.TargetPut, tstats stats.Tracker, startingUp bool) (core.Backend, error) {
+	s := os.Getenv(env.WebDAV.URL)
+	if s == "" {
+		return nil, fmt.Errorf("%s: missing %s", apc.DisplayProvider(apc.WebDAV), env.WebDAV.URL)
+	}
+	root, err := url.Parse(strings.TrimSuffix(s, "/"))
+	if err != nil || root.Scheme == "" || root.Host == "" {
+		return nil, fmt.Errorf("%s: invalid %s=%q", apc.DisplayProvider(apc.WebDAV), env.WebDAV.URL, s)
+	}
+	root.RawPath = ""
+	bp := &davbp{
+		t:    t,
+		root: root,
+		user: os.Getenv(env.WebDAV.Username),
+		pass: os.Getenv(env.WebDAV.Password),
+		base: base{provider: apc.WebDAV},
+	}
+	sargs := cmn.TLSArgs{SkipVerify: cos.IsParseBool(os.Getenv(env.WebDAV.SkipVerify))}
+	// no overall client timeout (large objects); requests are bounded by their respective contexts
+	bp.client = cmn.NewClientTLS(cmn.TransportArgs{}, sargs, false /*intra-cluster*/)
+
+	// register metrics
+	bp.base.init(t.Snode(), tstats, startingUp)
+
+	nlog.Infoln(apc.DisplayProvider(apc.WebDAV), "root:", root.Redacted())
+	return bp, nil
+}
+
+// given path relative to the root collection
+func (bp *davbp) url(rel string) string {
+	u := *bp.root
+	u.Path = bp.root.Path + "/" + rel
+	return u.String()
+}
+
+func (bp *davbp) newReq(ctx context.Context, method, u string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if bp.user != "" {
+		req.SetBasicAuth(bp.user, bp.pass)
+	}
+	return req, nil
+}
+
+func davClose(resp *http.Response) {
+	cos.DrainReader(resp.Body)
+	resp.Body.Close()
+}
+
+func davErr(resp *http.Response) (int, error) {
+	return resp.StatusCode, fmt.Errorf("webdav-error[%s %s: %s]", resp.Request.Method, resp.Request.URL.Path, resp.Status)
+}
+
+//
+// PROPFIND
+//
+
+func (bp *davbp) propfind(ctx context.Context, rel, depth string) ([]davResponse, int, error) {
+	req, err := bp.newReq(ctx, davPropfind, bp.url(rel), strings.NewReader(davPropfindBody))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set(davHdrDepth, depth)
+	req.Header.Set(cos.HdrContentType, "application/xml; charset=utf-8")
+	resp, err := bp.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer davClose(resp)
+	if resp.StatusCode != http.StatusMultiStatus {
+		ecode, err := davErr(resp)
+		return nil, ecode, err
+	}
+	var ms davMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, 0, fmt.Errorf("webdav-error[PROPFIND %s: %v]", rel, err)
+	}
+	return ms.Responses, 0, nil
+}
+
+// collection's members, sorted by name
+func (bp *davbp) readdir(ctx context.Context, dir string) ([]davEntry, int, error) {
+	resps, ecode, err := bp.propfind(ctx, dir, "1")
+	if err != nil {
+		return nil, ecode, err
+	}
+	entries := make([]davEntry, 0, len(resps))
+	for i := range resps {
+		en, ok := bp.toEntry(&resps[i])
+		if !ok || en.name == dir {
+			continue // (the collection itself)
+		}
+		entries = append(entries, en)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+	return entries, 0, nil
+}
+
+func (bp *davbp) toEntry(r *davResponse) (en davEntry, ok bool) {
+	u, err := url.Parse(r.Href)
+	if err != nil {
+		return en, false
+	}
+	en.name, ok = strings.CutPrefix(u.Path, bp.root.Path+"/")
+	if !ok || en.name == "" {
+		return en, false
+	}
+	for i := range r.Propstats {
+		if strings.Contains(r.Propstats[i].Status, " 200 ") {
+			en.prop = &r.Propstats[i].Prop
+			break
+		}
+	}
+	if en.prop == nil {
+		return en, false
+	}
+	if en.prop.ResourceType.Collection != nil && !cos.IsLastB(en.name, '/') {
+		en.name += "/"
+	}
+	return en, true
+}
+
+// 404 (object) => bucket not found?
+func (bp *davbp) objErr(ctx context.Context, cloudBck *cmn.Bck, ecode int, err error) (int, error) {
+	if ecode != http.StatusNotFound {
+		return ecode, err
+	}
+	if _, ec, _ := bp.propfind(ctx, cloudBck.Name+"/", "0"); ec == http.StatusNotFound {
+		return ec, cmn.NewErrRemoteBckNotFound(cloudBck)
+	}
+	return ecode, err
+}
+
+// as core.Backend --------------------------------------------------------------
+
+//
+// HEAD BUCKET
+//
+
+func (bp *davbp) HeadBucket(ctx context.Context, bck *meta.Bck) (cos.StrKVs, int, error) {
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infof("head_bucket %s", bck.Name)
+	}
+	cloudBck := bck.RemoteBck()
+	resps, ecode, err := bp.propfind(ctx, cloudBck.Name+"/", "0")
+	if err != nil {
+		if ecode == http.StatusNotFound {
+			err = cmn.NewErrRemoteBckNotFound(cloudBck)
+		}
+		return nil, ecode, err
+	}
+	if len(resps) == 0 {
+		return nil, http.StatusNotFound, cmn.NewErrRemoteBckNotFound(cloudBck)
+	}
+	if en, ok := bp.toEntry(&resps[0]); !ok || !cos.IsLastB(en.name, '/') {
+		return nil, http.StatusBadRequest, fmt.Errorf("%s: %q is not a collection", apc.DisplayProvider(apc.WebDAV), cloudBck.Name)
+	}
+	bckProps := make(cos.StrKVs, 1)
+	bckProps[apc.HdrBackendProvider] = apc.WebDAV
+	return bckProps, 0, nil
+}
+
+//
+// LIST OBJECTS
+//
+
+func (bp *davbp) ListObjects(bck *meta.Bck, msg *apc.LsoMsg, lst *cmn.LsoRes) (int, error) {
+	var (
+		ctx      = context.Background()
+		cloudBck = bck.RemoteBck()
+		bckDir   = cloudBck.Name + "/"
+	)
+	msg.PageSize = calcPageSize(msg.PageSize, bck.MaxPageSize())
+
+	readdir := func(dir string) ([]lsoDirent, int, error) {
+		entries, ecode, err := bp.readdir(ctx, bckDir+dir)
+		if err != nil {
+			return nil, ecode, err
+		}
+		dents := make([]lsoDirent, 0, len(entries))
+		for i := range entries {
+			en := &entries[i]
+			name, ok := strings.CutPrefix(en.name, bckDir)
+			if !ok {
+				continue
+			}
+			etag, _ := cmn.BackendHelpers.WebDAV.EncodeETag(en.prop.ETag)
+			dents = append(dents, lsoDirent{
+				name:   name,
+				size:   en.prop.ContentLength,
+				custom: cmn.CustomProps2S(cmn.ETag, etag, cmn.LastModified, davTime(en.prop.LastModified), cos.HdrContentType, en.prop.ContentType),
+			})
+		}
+		return dents, 0, nil
+	}
+	if ecode, err := walkPage(readdir, msg, lst); err != nil {
+		if ecode == http.StatusNotFound {
+			err = cmn.NewErrRemoteBckNotFound(cloudBck)
+		}
+		return ecode, err
+	}
+
+	if cmn.Rom.FastV(4, cos.SmoduleBackend) {
+		nlog.Infof("[list_objects] count %d", len(lst.Entries))
+	}
+	return 0, nil
+}
+
+//
+// LIST BUCKETS
+//
+
+func (bp *davbp) ListBuckets(cmn.QueryBcks) (bcks cmn.Bcks, ecode int, err error) {
+	entries, ecode, err := bp.readdir(context.Background(), "")
+	if err != nil {
+		return nil, ecode, err
+	}
+	bcks = make(cmn.Bcks, 0, len(entries))
+	for i := range entries {
+		if name, ok := strings.CutSuffix(entries[i].name, "/"); ok {
+			bcks = append(bcks, cmn.Bck{Name: name, Provider: apc.WebDAV})
+		}
+	}
+	return bcks, 0, nil
+}
+
+//
+// HEAD OBJECT
+//
+
+func (bp *davbp) HeadObj(ctx context.Context, lom *core.LOM, _ *http.Request) (*cmn.ObjAttrs, int, error) {
+	cloudBck := lom.Bck().RemoteBck()
+	req, err := bp.newReq(ctx, http.MethodHead, bp.url(cloudBck.Name+"/"+lom.ObjName), http.NoBody)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := bp.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	davClose(resp)
+	if resp.StatusCode != http.StatusOK {
+		ecode, err := davErr(resp)
+		ecode, err = bp.objErr(ctx, cloudBck, ecode, err)
+		return nil, ecode, err
+	}
+	oa := &cmn.ObjAttrs{}
+	oa.CustomMD = make(cos.StrKVs, 4)
+	if resp.ContentLength >= 0 {
+		oa.Size = resp.ContentLength
+	}
+	davSetCustom(oa, resp.Header)
+	// (not stored w/ LOM - see gsbp.HeadObj)
+	oa.SetCustomKey(cos.HdrContentType, resp.Header.Get(cos.HdrContentType))
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infof("[head_object] %s", cloudBck.Cname(lom.ObjName))
+	}
+	return oa, 0, nil
+}
+
+//
+// GET OBJECT
+//
+
+func (bp *davbp) GetObj(ctx context.Context, lom *core.LOM, owt cmn.OWT, _ *http.Request) (int, error) {
+	res := bp.GetObjReader(ctx, lom, 0, 0)
+	if res.Err != nil {
+		return res.ErrCode, res.Err
+	}
+	params := allocPutParams(res, owt)
+	err := bp.t.PutObject(lom, params)
+	core.FreePutParams(params)
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infoln("[get_object]", lom.String(), err)
+	}
+	return 0, err
+}
+
+func (bp *davbp) GetObjReader(ctx context.Context, lom *core.LOM, offset, length int64) (res core.GetReaderResult) {
+	cloudBck := lom.Bck().RemoteBck()
+	req, err := bp.newReq(ctx, http.MethodGet, bp.url(cloudBck.Name+"/"+lom.ObjName), http.NoBody)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	if length > 0 {
+		req.Header.Set(cos.HdrRange, cmn.MakeRangeHdr(offset, length))
+	}
+	resp, err := bp.client.Do(req)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+	default:
+		davClose(resp)
+		res.ErrCode, res.Err = davErr(resp)
+		if res.ErrCode == http.StatusRequestedRangeNotSatisfiable {
+			res.Err = cmn.NewErrRangeNotSatisfiable(res.Err, nil, 0)
+		} else {
+			res.ErrCode, res.Err = bp.objErr(ctx, cloudBck, res.ErrCode, res.Err)
+		}
+		return res
+	}
+	if length == 0 {
+		// custom metadata
+		davSetCustom(lom.ObjAttrs(), resp.Header)
+	}
+	res.Size = resp.ContentLength
+	res.R = resp.Body
+	return res
+}
+
+//
+// PUT OBJECT
+//
+
+func (bp *davbp) PutObj(r io.ReadCloser, lom *core.LOM, _ *http.Request) (int, error) {
+	var (
+		ctx      = context.Background()
+		cloudBck = lom.Bck().RemoteBck()
+		objName  = cloudBck.Name + "/" + lom.ObjName
+	)
+	if ecode, err := bp.mkcolAll(ctx, objName); err != nil {
+		cos.Close(r)
+		return ecode, err
+	}
+	req, err := bp.newReq(ctx, http.MethodPut, bp.url(objName), r)
+	if err != nil {
+		cos.Close(r)
+		return 0, err
+	}
+	req.TransferEncoding = []string{"chunked"}
+	req.Header.Set(davHdrExpectedLen, strconv.FormatInt(lom.Lsize(true), 10))
+	req.Header.Set(cos.HdrContentType, cos.ContentBinary)
+
+	resp, err := bp.client.Do(req) // (closes the reader)
+	if err != nil {
+		return 0, err
+	}
+	davClose(resp)
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+	case http.StatusConflict:
+		// parent collection removed in the meantime
+		bp.forgetDirs(objName)
+		return davErr(resp)
+	default:
+		ecode, err := davErr(resp)
+		return bp.objErr(ctx, cloudBck, ecode, err)
+	}
+	davSetCustom(lom.ObjAttrs(), resp.Header)
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infof("[put_object] %s", lom)
+	}
+	return 0, nil
+}
+
+// create missing parent collections (excluding the bucket itself)
+func (bp *davbp) mkcolAll(ctx context.Context, objName string) (int, error) {
+	for i := strings.IndexByte(objName, '/') + 1; ; i++ {
+		j := strings.IndexByte(objName[i:], '/')
+		if j < 0 {
+			return 0, nil
+		}
+		i += j
+		dir := objName[:i+1]
+		if _, ok := bp.dirs.Load(dir); ok {
+			continue
+		}
+		req, err := bp.newReq(ctx, davMkcol, bp.url(dir), http.NoBody)
+		if err != nil {
+			return 0, err
+		}
+		resp, err := bp.client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		davClose(resp)
+		switch resp.StatusCode {
+		case http.StatusCreated, http.StatusMethodNotAllowed: // (405: already exists)
+			bp.dirs.Store(dir, struct{}{})
+		default:
+			return davErr(resp)
+		}
+	}
+}
+
+func (bp *davbp) forgetDirs(objName string) {
+	for i := len(objName) - 1; i > 0; i-- {
+		if objName[i] == '/' {
+			bp.dirs.Delete(objName[:i+1])
+		}
+	}
+}
+
+//
+// DELETE OBJECT
+//
+
+func (bp *davbp) DeleteObj(lom *core.LOM) (int, error) {
+	var (
+		ctx      = context.Background()
+		cloudBck = lom.Bck().RemoteBck()
+	)
+	req, err := bp.newReq(ctx, http.MethodDelete, bp.url(cloudBck.Name+"/"+lom.ObjName), http.NoBody)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := bp.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	davClose(resp)
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusAccepted:
+	default:
+		ecode, err := davErr(resp)
+		return bp.objErr(ctx, cloudBck, ecode, err)
+	}
+	if cmn.Rom.FastV(5, cos.SmoduleBackend) {
+		nlog.Infof("[delete_object] %s", lom)
+	}
+	return 0, nil
+}
+
+//
+// misc. helpers
+//
+
+func davSetCustom(oa *cmn.ObjAttrs, hdr http.Header) {
+	oa.SetCustomKey(cmn.SourceObjMD, apc.WebDAV)
+	if v, ok := cmn.BackendHelpers.WebDAV.EncodeETag(hdr.Get(cos.HdrETag)); ok {
+		oa.SetCustomKey(cmn.ETag, v)
+	}
+	if v := davTime(hdr.Get(davHdrLastModified)); v != "" {
+		oa.SetCustomKey(cmn.LastModified, v)
+	}
+}
+
+// RFC 1123 (getlastmodified and Last-Modified) => RFC 3339
+func davTime(s string) string {
+	if s == "" {
+		return ""
+	}
+	t, err := http.ParseTime(s)
+	if err != nil {
+		return ""
+	}
+	return fmtTime(t)
+}
@@ -254,7 +254,7 @@ func initDaemon(version, buildTime string) cos.Runner {
 	t.init(config)
 
 	// reg xaction factories
-	xs.Treg(t)
+	xs.Treg(t, t.statsT)
 	space.Xreg()
 
 	title := _loghdr2(t.si, loghdr)
@@ -30,6 +30,12 @@ type dpq struct {
 	arch struct {
 		path, mime, regx, mmode string // QparamArchpath et al. (plus archmode below)
 	}
+	presign struct {
+		expires, sig string // QparamPresignExpires, QparamPresignSig
+	}
+	quota struct {
+		objs, size string // QparamQuotaObjs, QparamQuotaSize
+	}
 
 	ptime       string // req timestamp at calling/redirecting proxy (QparamUnixTime)
 	uuid        string // xaction
@@ -37,6 +43,7 @@ type dpq struct {
 	owt         string // object write transaction { OwtPut, ... }
 	fltPresence string // QparamFltPresence
 	etlName     string // QparamETLName
+	snapshot    string // QparamSnapshot
 	binfo       string // bucket info, with or without requirement to summarize remote obj-s
 
 	skipVC        bool // QparamSkipVC (skip loading existing object's metadata)
@@ -147,6 +154,20 @@ func (dpq *dpq) parse(rawQuery string) (err error) {
 
 		case apc.QparamETLName:
 			dpq.etlName = value
+		case apc.QparamSnapshot:
+			dpq.snapshot = value
+		case apc.QparamPresignExpires:
+			dpq.presign.expires = value
+		case apc.QparamPresignSig:
+			dpq.presign.sig = value
+		case apc.QparamQuotaObjs:
+			if dpq.quota.objs, err = url.QueryUnescape(value); err != nil {
+				return
+			}
+		case apc.QparamQuotaSize:
+			if dpq.quota.size, err = url.QueryUnescape(value); err != nil {
+				return
+			}
 		case apc.QparamSilent:
 			dpq.silent = cos.IsParseBool(value)
 		case apc.QparamLatestVer:
@@ -0,0 +1,251 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmd/authn/tok"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/audit"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/core/meta"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Audit log: selected (see `networkHandler.audit`) handlers get wrapped to record
+// client requests - intra-cluster requests are never recorded.
+// A request is recorded when either:
+// - audit is enabled cluster-wide (config.Audit.Enabled), or
+// - the request targets a bucket with audit enabled (Bprops.Audit.Enabled).
+// Data-plane requests redirected by a proxy end up recorded twice: by the proxy
+// (status 307, authenticated user) and by the target that serves the request (final status and size).
+
+const maxAuditBody = 64 * cos.KiB // control-plane (JSON) request to parse for apc.ActMsg action
+
+type auditWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+// interface guard
+var _ io.ReaderFrom = (*auditWriter)(nil)
+
+func (aw *auditWriter) WriteHeader(code int) {
+	if aw.status == 0 {
+		aw.status = code
+	}
+	aw.ResponseWriter.WriteHeader(code)
+}
+
+func (aw *auditWriter) Write(b []byte) (int, error) {
+	if aw.status == 0 {
+		aw.status = http.StatusOK
+	}
+	n, err := aw.ResponseWriter.Write(b)
+	aw.size += int64(n)
+	return n, err
+}
+
+// keep sendfile and friends (see net/http response.ReadFrom)
+func (aw *auditWriter) ReadFrom(src io.Reader) (int64, error) {
+	if aw.status == 0 {
+		aw.status = http.StatusOK
+	}
+	n, err := io.Copy(aw.ResponseWriter, src)
+	aw.size += n
+	return n, err
+}
+
+func (aw *auditWriter) Flush() {
+	if f, ok := aw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (aw *auditWriter) Unwrap() http.ResponseWriter { return aw.ResponseWriter }
+
+///////////
+// htrun //
+///////////
+
+func (h *htrun) auditHandler(resource string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(apc.HdrCallerID) != "" {
+			handler(w, r)
+			return
+		}
+		// cluster-level reads (stats, status, etc.) are not interesting
+		if resource == apc.Cluster && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+			handler(w, r)
+			return
+		}
+		config := cmn.GCO.Get()
+		bck, objName := h.auditBck(r, resource)
+		if !config.Audit.Enabled && (bck == nil || bck.Props == nil || !bck.Props.Audit.Enabled) {
+			handler(w, r)
+			return
+		}
+
+		var (
+			ev = &audit.Event{
+				Time:   time.Now(),
+				Client: r.RemoteAddr,
+				Method: r.Method,
+				Path:   r.URL.Path,
+				Object: objName,
+			}
+			aw      = &auditWriter{ResponseWriter: w}
+			started = mono.NanoTime()
+		)
+		if bck != nil {
+			ev.Bucket = bck.Cname("")
+		}
+		ev.Action = auditAction(r, resource)
+		ev.User = h.reqUser(r.Header)
+
+		handler(aw, r)
+
+		ev.Status = cos.NonZero(aw.status, http.StatusOK)
+		ev.Size = aw.size
+		ev.Latency = mono.SinceNano(started)
+		h.audit.Record(ev, &config.Audit)
+	}
+}
+
+// bucket (and object) from the URL path:
+// - /v1/buckets/<bucket> and /v1/objects/<bucket>/<object> (with provider and namespace in the query)
+// - /s3/<bucket>/<object>
+// returned bucket has its props when present in the BMD
+func (h *htrun) auditBck(r *http.Request, resource string) (bck *meta.Bck, objName string) {
+	items := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 4)
+	switch resource {
+	case apc.Buckets, apc.Objects:
+		if len(items) < 3 || items[2] == "" || strings.HasPrefix(items[2], apc.BckProviderSeparator) {
+			return nil, ""
+		}
+		if len(items) > 3 {
+			objName = items[3]
+		}
+		q := r.URL.Query()
+		bck = meta.NewBck(items[2], apc.NormalizeProvider(q.Get(apc.QparamProvider)), cmn.ParseNsUname(q.Get(apc.QparamNamespace)))
+		if props, ok := h.owner.bmd.get().Get(bck); ok {
+			bck.Props = props
+		}
+	case "/" + apc.S3:
+		if len(items) < 2 || items[1] == "" {
+			return nil, ""
+		}
+		if len(items) > 2 {
+			objName = strings.Join(items[2:], "/")
+		}
+		var err error
+		if bck, err, _ = meta.InitByNameOnly(items[1], h.owner.bmd); err != nil {
+			bck = meta.NewBck(items[1], apc.AWS, cmn.NsGlobal)
+		}
+	}
+	return bck, objName
+}
+
+// control-plane requests: apc.ActMsg action (the body is read and then restored for the handler)
+func auditAction(r *http.Request, resource string) string {
+	switch {
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return ""
+	case r.Method == http.MethodPut && (resource == apc.Objects || resource == "/"+apc.S3):
+		return "" // object payload
+	case r.ContentLength <= 0 || r.ContentLength > maxAuditBody:
+		return ""
+	case !strings.HasPrefix(r.Header.Get(cos.HdrContentType), cos.ContentJSON):
+		return ""
+	}
+	b, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return ""
+	}
+	var msg struct {
+		Action string `json:"action"`
+	}
+	if jsoniter.Unmarshal(b, &msg) != nil {
+		return ""
+	}
+	return msg.Action
+}
+
+// AuthN user (if any) for the audit log and object ownership (cmn.OwnerObjMD);
+// targets decrypt the token with the cluster secret,
+// proxies use their cache of validated tokens - see proxy.tokenUser
+func (h *htrun) reqUser(hdr http.Header) string {
+	if h.tokenUserFn != nil {
+		return h.tokenUserFn(hdr)
+	}
+	config := cmn.GCO.Get()
+	if !config.Auth.Enabled {
+		return ""
+	}
+	token, err := tok.ExtractToken(hdr)
+	if err != nil {
+		return ""
+	}
+	tk, err := tok.DecryptToken(token, authSecret(config))
+	if err != nil {
+		return ""
+	}
+	return tk.UserID
+}
+
+// GET /v1/daemon?what=audit
+func (h *htrun) sendAudit(w http.ResponseWriter, r *http.Request, query url.Values) {
+	var count int
+	if s := query.Get(apc.QparamAuditCount); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			h.writeErrf(w, r, "invalid %s=%q", apc.QparamAuditCount, s)
+			return
+		}
+		count = n
+	}
+	events := h.audit.Tail(count, query.Get(apc.QparamAuditBucket))
+	h.writeJSON(w, r, events, "audit")
+}
+
+///////////
+// proxy //
+///////////
+
+func (p *proxy) tokenUser(hdr http.Header) string {
+	if tk := p.reqToken(hdr); tk != nil {
+		return tk.UserID
+	}
+	return ""
+}
+
+// valid token (if any) that comes with the request; nil when AuthN is disabled
+func (p *proxy) reqToken(hdr http.Header) *tok.Token {
+	if !cmn.GCO.Get().Auth.Enabled {
+		return nil
+	}
+	token, err := tok.ExtractToken(hdr)
+	if err != nil {
+		return nil
+	}
+	tk, err := p.authn.validateToken(token)
+	if err != nil {
+		return nil
+	}
+	return tk
+}
@@ -134,9 +134,10 @@ type (
 	}
 
 	networkHandler struct {
-		h   http.HandlerFunc // handler
-		r   string           // resource
-		net netAccess        // handler's network access
+		h     http.HandlerFunc // handler
+		r     string           // resource
+		net   netAccess        // handler's network access
+		audit bool             // record client requests in the audit log (see htaudit)
 	}
 
 	nodeRegPool []cluMeta
@@ -402,6 +403,9 @@ type (
 
 	cresLso   struct{} // -> cmn.LsoRes
 	cresBsumm struct{} // -> cmn.AllBsummResults
+	cresTrash struct{} // -> apc.TrashEntries
+	cresSnaps struct{} // -> cmn.Snapshots
+	cresSnapO struct{} // -> cmn.LsoEntries (snapshotted objects)
 )
 
 var (
@@ -416,6 +420,9 @@ var (
 	_ cresv = cresIC{}
 	_ cresv = cresBM{}
 	_ cresv = cresBsumm{}
+	_ cresv = cresTrash{}
+	_ cresv = cresSnaps{}
+	_ cresv = cresSnapO{}
 )
 
 func (res *callResult) read(body io.Reader, size int64) {
@@ -467,6 +474,15 @@ func (c cresBM) read(res *callResult, body io.Reader) { res.v = c.newV(); res.jr
 func (cresBsumm) newV() any                              { return &cmn.AllBsummResults{} }
 func (c cresBsumm) read(res *callResult, body io.Reader) { res.v = c.newV(); res.jread(body) }
 
+func (cresTrash) newV() any                              { return &apc.TrashEntries{} }
+func (c cresTrash) read(res *callResult, body io.Reader) { res.v = c.newV(); res.jread(body) }
+
+func (cresSnaps) newV() any                              { return &cmn.Snapshots{} }
+func (c cresSnaps) read(res *callResult, body io.Reader) { res.v = c.newV(); res.jread(body) }
+
+func (cresSnapO) newV() any                              { return &cmn.LsoEntries{} }
+func (c cresSnapO) read(res *callResult, body io.Reader) { res.v = c.newV(); res.jread(body) }
+
 ////////////////
 // nlogWriter //
 ////////////////
@@ -585,6 +601,13 @@ func newTLS(conf *cmn.HTTPConf) (tlsConf *tls.Config, err error) {
 		caCert     []byte
 		clientAuth = tls.ClientAuthType(conf.ClientAuthTLS)
 	)
+	if (conf.Certificate != "" && conf.CertKey != "") || conf.ACMEDomains != "" || conf.VaultAddr != "" || conf.SPIFFESocket != "" {
+		opts := &certloader.TLSOpts{ClientAuth: clientAuth, CRLFile: conf.ClientCRL, OCSP: conf.ClientOCSP}
+		if clientAuth > tls.RequestClientCert {
+			opts.CAFile = conf.ClientCA // (reloaded along with the certificate)
+		}
+		return certloader.BuildServerTLSConfig(opts)
+	}
 	tlsConf = &tls.Config{
 		ClientAuth: clientAuth,
 	}
@@ -598,10 +621,7 @@ func newTLS(conf *cmn.HTTPConf) (tlsConf *tls.Config, err error) {
 		}
 		tlsConf.ClientCAs = pool
 	}
-	if conf.Certificate != "" && conf.CertKey != "" {
-		tlsConf.GetCertificate, err = certloader.GetCert()
-	}
-	return tlsConf, err
+	return tlsConf, nil
 }
 
 func (server *netServer) connStateListener(c net.Conn, cs http.ConnState) {
@@ -27,6 +27,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/archive"
 	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/audit"
 	"github.com/NVIDIA/aistore/cmn/certloader"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
@@ -39,6 +40,7 @@ import (
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/tracing"
+	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/xact/xreg"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/tinylib/msgp/msgp"
@@ -75,9 +77,12 @@ type htrun struct {
 	keepalive keepaliver
 	statsT    stats.Tracker
 	si        *meta.Snode
-	gmm       *memsys.MMSA // system pagesize-based memory manager and slab allocator
-	smm       *memsys.MMSA // small-size allocator (up to 4K)
-	startup   struct {
+	audit     *audit.Logger
+	// AuthN user from the request's token (proxy only; see reqUser)
+	tokenUserFn func(hdr http.Header) string
+	gmm         *memsys.MMSA // system pagesize-based memory manager and slab allocator
+	smm         *memsys.MMSA // small-size allocator (up to 4K)
+	startup     struct {
 		cluster atomic.Int64 // mono.NanoTime() since cluster startup, zero prior to that
 		node    atomic.Int64 // ditto - for this node
 	}
@@ -217,9 +222,13 @@ func (h *htrun) regNetHandlers(networkHandlers []networkHandler) {
 	for r, nh := range debug.Handlers() {
 		handlePub(r, nh)
 	}
+	h.audit = audit.New(config.LogDir, h.SID())
 	// node type specific
 	for _, nh := range networkHandlers {
 		var reg bool
+		if nh.audit {
+			nh.h = h.auditHandler(nh.r, nh.h)
+		}
 		if nh.r[0] == '/' { // absolute path
 			path = nh.r
 		} else {
@@ -266,9 +275,47 @@ func (h *htrun) regNetHandlers(networkHandlers []networkHandler) {
 func (h *htrun) init(config *cmn.Config) {
 	// before newTLS() below & before intra-cluster clients
 	if config.Net.HTTP.UseHTTPS {
-		if err := certloader.Init(config.Net.HTTP.Certificate, config.Net.HTTP.CertKey, h.statsT); err != nil {
+		c := &config.Net.HTTP
+		policy, err := certloader.NewKeyPolicy(c.KeyTypes, c.KeyCurves, c.MinRSABits)
+		if err != nil {
+			cos.ExitLog(err)
+		}
+		switch {
+		case c.SPIFFESocket != "":
+			err = certloader.InitSPIFFE(&certloader.SPIFFEOpts{Socket: c.SPIFFESocket, ID: c.SPIFFEID}, policy, h.statsT)
+		case c.VaultAddr != "":
+			opts := &certloader.VaultOpts{
+				Addr:       c.VaultAddr,
+				Path:       c.VaultPKIPath,
+				TokenFile:  c.VaultTokenFile,
+				CommonName: c.VaultCommonName,
+				AltNames:   c.VaultAltNames,
+				CAFile:     c.VaultCACert,
+				TTL:        c.VaultTTL.D(),
+			}
+			err = certloader.InitVault(opts, policy, h.statsT)
+		case c.ACMEDomains != "":
+			opts := &certloader.ACMEOpts{
+				Domains:   strings.Split(c.ACMEDomains, ","),
+				Directory: c.ACMEDirectory,
+				Email:     c.ACMEEmail,
+				CacheDir:  cos.Left(c.ACMECacheDir, filepath.Join(config.ConfigDir, "acme")),
+			}
+			err = certloader.InitACME(opts, policy, h.statsT)
+		default:
+			err = certloader.Init(c.Certificate, c.CertKey, c.CertGenFile, policy, h.statsT)
+		}
+		if err != nil {
 			cos.ExitLog(err)
 		}
+		for i := range c.SNICerts {
+			if err := certloader.AddSNI(c.SNICerts[i].Certificate, c.SNICerts[i].CertKey); err != nil {
+				cos.ExitLog(err)
+			}
+		}
+		if c.OCSPStaple {
+			certloader.StapleOCSP()
+		}
 	}
 
 	initCtrlClient(config)
@@ -539,6 +586,12 @@ func (h *htrun) run(config *cmn.Config) error {
 			_ = g.netServ.data.listen(h.si.DataNet.TCPEndpoint(), logger, tlsConf, config)
 		}()
 	}
+	if config.Transport.QUIC && h.si.IsTarget() {
+		// QUIC (HTTP/3) streams: UDP, same address and port as the intra-cluster data network
+		go func() {
+			_ = transport.ListenQUIC(h.si.DataNet.TCPEndpoint(), tlsConf)
+		}()
+	}
 
 	ep := h.si.PubNet.TCPEndpoint()
 	if h.pubAddrAny(config) {
@@ -1096,6 +1149,9 @@ func (h *htrun) httpdaeget(w http.ResponseWriter, r *http.Request, query url.Val
 		// hide secret
 		out = *config
 		out.Auth.Secret = "**********"
+		if out.Transport.Secret != "" {
+			out.Transport.Secret = "**********"
+		}
 		body = &out
 	case apc.WhatSmap:
 		body = h.owner.smap.get()
@@ -1120,6 +1176,9 @@ func (h *htrun) httpdaeget(w http.ResponseWriter, r *http.Request, query url.Val
 			h.sendOneLog(w, r, query)
 		}
 		return
+	case apc.WhatAudit:
+		h.sendAudit(w, r, query)
+		return
 	case apc.WhatNodeStats:
 		statsNode := h.statsT.GetStats()
 		statsNode.Snode = h.si
@@ -10,6 +10,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/tracing"
+	"github.com/NVIDIA/aistore/transport"
 )
 
 type global struct {
@@ -115,4 +116,7 @@ func shuthttp() {
 	if config.HostNet.UseIntraData {
 		g.netServ.data.shutdown(config)
 	}
+	if config.Transport.QUIC {
+		transport.ShutdownQUIC()
+	}
 }
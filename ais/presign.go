@@ -0,0 +1,162 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/core/meta"
+)
+
+// Presigned URLs: time-limited, HMAC-signed URLs to GET or PUT a given object without AuthN token.
+// - issued by proxy upon apc.ActPresign request (the requester must have the corresponding permission);
+// - signed with the key derived from the cluster-wide secret (see authSecret, presignKey) over: method,
+//   bucket, object name, and the entire (canonical) query that includes expiration time;
+// - any query parameter that wasn't signed (e.g. apc.QparamETLName or apc.QparamArchpath) fails the verification,
+//   with the exception of those added by the redirecting proxy (see presignUnsigned);
+// - verified by proxy (in lieu of the token) and, again, by the target the request gets redirected to;
+// - with AuthN enabled, cannot outlive the requester's token (expiration gets capped accordingly).
+
+var (
+	errPresignNoSecret = errors.New("presigned URLs require cluster-wide 'auth.secret' (or its environment override) to be configured")
+	errPresignExpired  = errors.New("presigned URL has expired")
+	errPresignInvalid  = errors.New("invalid presigned URL signature")
+
+	errPresignTokenExpired = errors.New("cannot presign: token has expired")
+)
+
+// query parameters the redirecting proxy adds to an already verified presigned request (see redirectURL, checkPutQuota)
+var presignUnsigned = [...]string{apc.QparamProxyID, apc.QparamUnixTime, apc.QparamQuotaObjs, apc.QparamQuotaSize}
+
+// domain separation: the same cluster-wide secret is used to sign AuthN tokens
+func presignKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("presign"))
+	return mac.Sum(nil)
+}
+
+// signs method, bucket, object, and canonical query (sorted by key) less the signature itself
+func presignSig(key []byte, method string, bck *cmn.Bck, objName string, query url.Values) string {
+	canon := make(url.Values, len(query))
+	for k, v := range query {
+		if k != apc.QparamPresignSig {
+			canon[k] = v
+		}
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(method + "\n" + bck.Cname(objName) + "\n" + canon.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// returns URL path and query
+// - notAfter: requester's token expiration time (zero when AuthN is disabled)
+func presign(secret string, bck *cmn.Bck, objName string, args *apc.PresignArgs, now, notAfter time.Time) (string, error) {
+	if secret == "" {
+		return "", errPresignNoSecret
+	}
+	if args.Method != http.MethodGet && args.Method != http.MethodPut {
+		return "", fmt.Errorf("invalid presign method %q (expecting %s or %s)", args.Method, http.MethodGet, http.MethodPut)
+	}
+	expires := time.Duration(args.Expires)
+	switch {
+	case expires == 0:
+		expires = apc.PresignDfltExpires
+	case expires < 0 || expires > apc.PresignMaxExpires:
+		return "", fmt.Errorf("invalid presign expiration %v (expecting positive duration up to %v)",
+			expires, apc.PresignMaxExpires)
+	}
+	if !notAfter.IsZero() {
+		if remaining := notAfter.Sub(now); remaining < expires {
+			if remaining <= 0 {
+				return "", errPresignTokenExpired
+			}
+			expires = remaining
+		}
+	}
+	var (
+		exp   = strconv.FormatInt(now.Add(expires).Unix(), 10)
+		query = bck.NewQuery()
+		u     = url.URL{Path: apc.URLPathObjects.Join(bck.Name, objName)}
+	)
+	query.Set(apc.QparamPresignExpires, exp)
+	query.Set(apc.QparamPresignSig, presignSig(presignKey(secret), args.Method, bck, objName, query))
+	return u.EscapedPath() + "?" + query.Encode(), nil
+}
+
+// - query: the request's query as is
+// - redirected: the request was redirected by proxy (and was therefore verified by the latter)
+func verifyPresigned(secret, method string, bck *cmn.Bck, objName string, query url.Values, redirected bool) error {
+	if secret == "" {
+		return errPresignNoSecret
+	}
+	expires := query.Get(apc.QparamPresignExpires)
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return errPresignInvalid
+	}
+	if redirected {
+		signed := make(url.Values, len(query))
+		for k, v := range query {
+			signed[k] = v
+		}
+		for _, k := range presignUnsigned {
+			delete(signed, k)
+		}
+		query = signed
+	}
+	expected := presignSig(presignKey(secret), method, bck, objName, query)
+	if !hmac.Equal([]byte(expected), []byte(query.Get(apc.QparamPresignSig))) {
+		return errPresignInvalid
+	}
+	if time.Now().Unix() > exp {
+		return errPresignExpired
+	}
+	return nil
+}
+
+// POST /v1/objects/bucket-name/object-name (apc.ActPresign)
+func (p *proxy) presignObj(w http.ResponseWriter, r *http.Request, bck *meta.Bck, objName string, msg *apc.ActMsg) {
+	args := &apc.PresignArgs{}
+	if err := cos.MorphMarshal(msg.Value, args); err != nil {
+		p.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, p.si, msg.Action, msg.Value, err)
+		return
+	}
+	perms := apc.AceGET
+	if args.Method == http.MethodPut {
+		perms = apc.AcePUT
+	}
+	if err := p.checkObjAccess(w, r, bck, objName, perms); err != nil {
+		return
+	}
+	if err := cmn.ValidOname(objName); err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	var notAfter time.Time
+	if cmn.Rom.AuthEnabled() {
+		if tk, err := p.validateToken(r.Header); err == nil {
+			notAfter = tk.Expires
+		}
+	}
+	s, err := presign(authSecret(cmn.GCO.Get()), bck.Bucket(), objName, args, time.Now(), notAfter)
+	if err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	u := p.si.URL(cmn.NetPublic) + s
+	w.Header().Set(cos.HdrContentLength, strconv.Itoa(len(u)))
+	w.Write(cos.UnsafeB(u))
+}
@@ -0,0 +1,152 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+)
+
+func TestPresign(t *testing.T) {
+	const (
+		secret  = "presign-secret"
+		objName = "dir/obj name+1"
+	)
+	bck := &cmn.Bck{Name: "bck", Provider: apc.AIS}
+
+	parse := func(s string) (string, url.Values) {
+		u, err := url.Parse("http://localhost" + s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		items := strings.SplitN(strings.TrimPrefix(u.Path, apc.URLPathObjects.S+"/"), "/", 2)
+		if items[0] != bck.Name || items[1] != objName {
+			t.Fatalf("unexpected path %q", u.Path)
+		}
+		return items[1], u.Query()
+	}
+
+	s, err := presign(secret, bck, objName, &apc.PresignArgs{Method: http.MethodGet}, time.Now(), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	oname, query := parse(s)
+	if err := verifyPresigned(secret, http.MethodGet, bck, oname, query, false); err != nil {
+		t.Fatal(err)
+	}
+	// method, object, bucket, and secret are all signed
+	if err := verifyPresigned(secret, http.MethodPut, bck, oname, query, false); err != errPresignInvalid {
+		t.Fatalf("expected %v, got %v", errPresignInvalid, err)
+	}
+	if err := verifyPresigned(secret, http.MethodGet, bck, oname+"x", query, false); err != errPresignInvalid {
+		t.Fatalf("expected %v, got %v", errPresignInvalid, err)
+	}
+	if err := verifyPresigned(secret, http.MethodGet, &cmn.Bck{Name: "bck", Provider: apc.AWS}, oname, query, false); err != errPresignInvalid {
+		t.Fatalf("expected %v, got %v", errPresignInvalid, err)
+	}
+	if err := verifyPresigned(secret+"x", http.MethodGet, bck, oname, query, false); err != errPresignInvalid {
+		t.Fatalf("expected %v, got %v", errPresignInvalid, err)
+	}
+	// the key is derived from (and is not) the secret
+	if sig := query.Get(apc.QparamPresignSig); sig == presignSig([]byte(secret), http.MethodGet, bck, oname, query) {
+		t.Fatal("expected derived signing key")
+	}
+
+	// unsigned query parameters
+	for _, qparam := range []string{apc.QparamETLName, apc.QparamArchpath, apc.QparamSnapshot, apc.QparamProxyID} {
+		q := url.Values{}
+		for k, v := range query {
+			q[k] = v
+		}
+		q.Set(qparam, "x")
+		if err := verifyPresigned(secret, http.MethodGet, bck, oname, q, false); err != errPresignInvalid {
+			t.Fatalf("%s: expected %v, got %v", qparam, errPresignInvalid, err)
+		}
+	}
+	// except those added by the redirecting proxy
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set(apc.QparamProxyID, "p1")
+	q.Set(apc.QparamUnixTime, cos.UnixNano2S(time.Now().UnixNano()))
+	q.Set(apc.QparamQuotaSize, makeQuotaHint(quotaHintBck, cos.MiB, 0))
+	if err := verifyPresigned(secret, http.MethodGet, bck, oname, q, true); err != nil {
+		t.Fatal(err)
+	}
+	q.Set(apc.QparamETLName, "x")
+	if err := verifyPresigned(secret, http.MethodGet, bck, oname, q, true); err != errPresignInvalid {
+		t.Fatalf("expected %v, got %v", errPresignInvalid, err)
+	}
+
+	// tampered expiration
+	query.Set(apc.QparamPresignExpires, query.Get(apc.QparamPresignExpires)+"0")
+	if err := verifyPresigned(secret, http.MethodGet, bck, oname, query, false); err != errPresignInvalid {
+		t.Fatalf("expected %v, got %v", errPresignInvalid, err)
+	}
+
+	// expired
+	s, err = presign(secret, bck, objName, &apc.PresignArgs{Method: http.MethodPut, Expires: cos.Duration(time.Minute)},
+		time.Now().Add(-time.Hour), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	oname, query = parse(s)
+	if err := verifyPresigned(secret, http.MethodPut, bck, oname, query, false); err != errPresignExpired {
+		t.Fatalf("expected %v, got %v", errPresignExpired, err)
+	}
+
+	// invalid args
+	for _, args := range []*apc.PresignArgs{
+		{Method: http.MethodDelete},
+		{Method: http.MethodGet, Expires: cos.Duration(-time.Second)},
+		{Method: http.MethodGet, Expires: cos.Duration(apc.PresignMaxExpires + time.Second)},
+	} {
+		if _, err := presign(secret, bck, objName, args, time.Now(), time.Time{}); err == nil {
+			t.Fatalf("expected error for %+v", *args)
+		}
+	}
+	if _, err := presign("", bck, objName, &apc.PresignArgs{Method: http.MethodGet}, time.Now(), time.Time{}); err != errPresignNoSecret {
+		t.Fatalf("expected %v, got %v", errPresignNoSecret, err)
+	}
+
+	// capped by the requester's token expiration
+	var (
+		now      = time.Now()
+		notAfter = now.Add(10 * time.Minute)
+	)
+	s, err = presign(secret, bck, objName, &apc.PresignArgs{Method: http.MethodGet, Expires: cos.Duration(apc.PresignMaxExpires)},
+		now, notAfter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oname, query = parse(s)
+	if exp := strconv.FormatInt(notAfter.Unix(), 10); query.Get(apc.QparamPresignExpires) != exp {
+		t.Fatalf("expected expiration capped at %s (token), got %s", exp, query.Get(apc.QparamPresignExpires))
+	}
+	if err := verifyPresigned(secret, http.MethodGet, bck, oname, query, false); err != nil {
+		t.Fatal(err)
+	}
+	// not capped when the token outlives the requested expiration
+	s, err = presign(secret, bck, objName, &apc.PresignArgs{Method: http.MethodGet, Expires: cos.Duration(time.Minute)},
+		now, notAfter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, query = parse(s); query.Get(apc.QparamPresignExpires) != strconv.FormatInt(now.Add(time.Minute).Unix(), 10) {
+		t.Fatalf("unexpected expiration %s", query.Get(apc.QparamPresignExpires))
+	}
+	if _, err := presign(secret, bck, objName, &apc.PresignArgs{Method: http.MethodGet}, now, now.Add(-time.Second)); err != errPresignTokenExpired {
+		t.Fatalf("expected %v, got %v", errPresignTokenExpired, err)
+	}
+}
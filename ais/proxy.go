@@ -72,7 +72,8 @@ type (
 			mu sync.RWMutex
 			in atomic.Bool
 		}
-		ec struct {
+		quota quotaCache // cluster-wide usage to enforce bucket and user quotas
+		ec    struct {
 			last atomic.Int64 // last active EC via apc.HdrActiveEC (mono time)
 			rust int64        // same as above
 		}
@@ -205,20 +206,21 @@ func (p *proxy) Run() error {
 	//
 	// REST API: register proxy handlers and start listening
 	//
+	p.tokenUserFn = p.tokenUser
 	networkHandlers := []networkHandler{
 		{r: apc.Reverse, h: p.reverseHandler, net: accessNetPublic},
 
 		// pubnet handlers: cluster must be started
-		{r: apc.Buckets, h: p.bucketHandler, net: accessNetPublic},
-		{r: apc.Objects, h: p.objectHandler, net: accessNetPublic},
-		{r: apc.Download, h: p.dloadHandler, net: accessNetPublic},
-		{r: apc.ETL, h: p.etlHandler, net: accessNetPublic},
-		{r: apc.Sort, h: p.dsortHandler, net: accessNetPublic},
+		{r: apc.Buckets, h: p.bucketHandler, net: accessNetPublic, audit: true},
+		{r: apc.Objects, h: p.objectHandler, net: accessNetPublic, audit: true},
+		{r: apc.Download, h: p.dloadHandler, net: accessNetPublic, audit: true},
+		{r: apc.ETL, h: p.etlHandler, net: accessNetPublic, audit: true},
+		{r: apc.Sort, h: p.dsortHandler, net: accessNetPublic, audit: true},
 
 		{r: apc.IC, h: p.ic.handler, net: accessNetIntraControl},
 		{r: apc.Daemon, h: p.daemonHandler, net: accessNetPublicControl},
-		{r: apc.Cluster, h: p.clusterHandler, net: accessNetPublicControl},
-		{r: apc.Tokens, h: p.tokenHandler, net: accessNetPublic},
+		{r: apc.Cluster, h: p.clusterHandler, net: accessNetPublicControl, audit: true},
+		{r: apc.Tokens, h: p.tokenHandler, net: accessNetPublic, audit: true},
 
 		{r: apc.Metasync, h: p.metasyncHandler, net: accessNetIntraControl},
 		{r: apc.Health, h: p.healthHandler, net: accessNetPublicControl},
@@ -228,7 +230,7 @@ func (p *proxy) Run() error {
 		{r: apc.EC, h: p.ecHandler, net: accessNetIntraControl},
 
 		// S3 compatibility
-		{r: "/" + apc.S3, h: p.s3Handler, net: accessNetPublic},
+		{r: "/" + apc.S3, h: p.s3Handler, net: accessNetPublic, audit: true},
 
 		// "easy URL"
 		{r: "/" + apc.GSScheme, h: p.easyURLHandler, net: accessNetPublic},
@@ -425,7 +427,7 @@ func (p *proxy) _parseReqTry(w http.ResponseWriter, r *http.Request, bckArgs *bc
 		apiReqFree(apireq)
 		return
 	}
-	bckArgs.bck, bckArgs.query = apireq.bck, apireq.query
+	bckArgs.bck, bckArgs.query, bckArgs.objName = apireq.bck, apireq.query, apireq.items[1]
 	bck, err = bckArgs.initAndTry()
 	objName = apireq.items[1]
 
@@ -638,6 +640,44 @@ func (p *proxy) httpbckget(w http.ResponseWriter, r *http.Request, dpq *dpq) {
 		return
 	}
 
+	// (I-b) list soft-deleted objects
+	if msg.Action == apc.ActListTrash {
+		if !qbck.IsBucket() {
+			p.writeErrf(w, r, "invalid action %q: missing bucket name", msg.Action)
+			return
+		}
+		bck := (*meta.Bck)(qbck)
+		bckArgs := bctx{p: p, w: w, r: r, msg: msg, perms: apc.AceObjLIST, bck: bck, dpq: dpq}
+		bckArgs.createAIS = false
+		bckArgs.dontHeadRemote = true
+		if _, err := bckArgs.initAndTry(); err != nil {
+			return
+		}
+		p.listTrash(w, r, bck, msg)
+		return
+	}
+
+	// (I-c) bucket snapshots
+	if msg.Action == apc.ActListSnapshots || msg.Action == apc.ActBrowseSnapshot {
+		if !qbck.IsBucket() {
+			p.writeErrf(w, r, "invalid action %q: missing bucket name", msg.Action)
+			return
+		}
+		perms := apc.AceBckHEAD
+		if msg.Action == apc.ActBrowseSnapshot {
+			perms = apc.AceObjLIST
+		}
+		bck := (*meta.Bck)(qbck)
+		bckArgs := bctx{p: p, w: w, r: r, msg: msg, perms: perms, bck: bck, dpq: dpq}
+		bckArgs.createAIS = false
+		bckArgs.dontHeadRemote = true
+		if _, err := bckArgs.initAndTry(); err != nil {
+			return
+		}
+		p.listSnapshots(w, r, bck, msg)
+		return
+	}
+
 	// (II) invalid action
 	if msg.Action != apc.ActList {
 		p.writeErrAct(w, r, msg.Action)
@@ -720,9 +760,19 @@ func (p *proxy) httpobjget(w http.ResponseWriter, r *http.Request, origURLBck ..
 		bckArgs.r = r
 		bckArgs.bck = apireq.bck
 		bckArgs.dpq = apireq.dpq
+		bckArgs.objName = apireq.items[1]
 		bckArgs.perms = apc.AceGET
 		bckArgs.createAIS = false
 	}
+	if apireq.dpq.presign.sig != "" {
+		if err := verifyPresigned(authSecret(cmn.GCO.Get()), r.Method, apireq.bck.Bucket(), apireq.items[1], r.URL.Query(), false); err != nil {
+			freeBctx(bckArgs)
+			apiReqFree(apireq)
+			p.writeErr(w, r, err, http.StatusForbidden)
+			return
+		}
+		bckArgs.presigned = true
+	}
 	if len(origURLBck) > 0 {
 		bckArgs.origURLBck = origURLBck[0]
 	}
@@ -802,7 +852,15 @@ func (p *proxy) httpobjput(w http.ResponseWriter, r *http.Request, apireq *apiRe
 		bckArgs.perms = perms
 		bckArgs.createAIS = false
 	}
-	bckArgs.bck, bckArgs.dpq = apireq.bck, apireq.dpq
+	if apireq.dpq.presign.sig != "" {
+		if err := verifyPresigned(authSecret(cmn.GCO.Get()), r.Method, apireq.bck.Bucket(), apireq.items[1], r.URL.Query(), false); err != nil {
+			freeBctx(bckArgs)
+			p.writeErr(w, r, err, http.StatusForbidden)
+			return
+		}
+		bckArgs.presigned = !appendTyProvided
+	}
+	bckArgs.bck, bckArgs.dpq, bckArgs.objName = apireq.bck, apireq.dpq, apireq.items[1]
 	bck, err := bckArgs.initAndTry()
 	freeBctx(bckArgs)
 	if err != nil {
@@ -810,6 +868,17 @@ func (p *proxy) httpobjput(w http.ResponseWriter, r *http.Request, apireq *apiRe
 	}
 	vlabs[stats.VarlabBucket] = bck.Cname("")
 
+	// quotas: appending adds to an existing object
+	var objects int64 = 1
+	if appendTyProvided {
+		objects = 0
+	}
+	if err := p.checkPutQuota(r, bck, r.ContentLength, objects); err != nil {
+		p.statsT.IncWith(errcnt, vlabs)
+		p.writeErr(w, r, err, err.Status())
+		return
+	}
+
 	// 3. redirect
 	var (
 		tsi     *meta.Snode
@@ -874,6 +943,13 @@ func (p *proxy) httpobjdelete(w http.ResponseWriter, r *http.Request) {
 		p.writeErr(w, r, err)
 		return
 	}
+	// object lock: bypassing governance-mode retention requires admin permission
+	if cos.IsParseBool(r.URL.Query().Get(apc.QparamBypassGovernance)) {
+		if err := p.checkAccess(w, r, bck, apc.AceAdmin); err != nil {
+			p.statsT.IncBck(stats.ErrDeleteCount, bck.Bucket())
+			return
+		}
+	}
 	smap := p.owner.smap.get()
 	tsi, err := smap.HrwName2T(bck.MakeUname(objName))
 	if err != nil {
@@ -1336,20 +1412,35 @@ func (p *proxy) _bckpost(w http.ResponseWriter, r *http.Request, msg *apc.ActMsg
 				p.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, p.si, msg.Action, msg.Value, err)
 				return
 			}
-			if err := tcbmsg.Validate(true); err != nil {
-				p.writeErr(w, r, err)
-				return
-			}
 		case apc.ActCopyBck:
 			if err = cos.MorphMarshal(msg.Value, &tcbmsg.CopyBckMsg); err != nil {
 				p.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, p.si, msg.Action, msg.Value, err)
 				return
 			}
 		}
+		if err := tcbmsg.Validate(msg.Action == apc.ActETLBck); err != nil {
+			p.writeErr(w, r, err)
+			return
+		}
+		if len(tcbmsg.Transform.Chain) > 0 {
+			etlMD := p.owner.etl.get()
+			for _, name := range tcbmsg.Transform.Chain {
+				if etlMD.get(name) == nil {
+					p.writeErr(w, r, cos.NewErrNotFound(p, "etl job "+name), http.StatusNotFound)
+					return
+				}
+			}
+		}
 		if tcbmsg.Sync && tcbmsg.Prepend != "" {
 			p.writeErrf(w, r, errPrependSync, tcbmsg.Prepend)
 			return
 		}
+		if tcbmsg.Arch != nil {
+			if _, err := archive.Mime(tcbmsg.Arch.Mime, tcbmsg.Arch.Template); err != nil {
+				p.writeErr(w, r, err)
+				return
+			}
+		}
 		bckTo, err = newBckFromQuname(query, true /*required*/)
 		if err != nil {
 			p.writeErr(w, r, err)
@@ -1362,6 +1453,29 @@ func (p *proxy) _bckpost(w http.ResponseWriter, r *http.Request, msg *apc.ActMsg
 				return
 			}
 			nlog.Infoln("proceeding to copy remote", bckFrom.String())
+		} else if tcbmsg.Flush {
+			p.writeErrf(w, r, "%s: flush requires copying remote bucket onto itself (have %s => %s)", p, bckFrom, bckTo)
+			return
+		}
+		if tcbmsg.Diff && !bckTo.IsRemote() {
+			p.writeErrf(w, r, "%s: differential copy requires remote destination (have %s => %s)", p, bckFrom, bckTo)
+			return
+		}
+		if tcbmsg.Snapshot {
+			now := time.Now().UnixNano()
+			if tcbmsg.AsOf > now {
+				p.writeErrf(w, r, "%s: point-in-time (as-of %v) cannot be in the future", p, time.Unix(0, tcbmsg.AsOf))
+				return
+			}
+			if tcbmsg.AsOf == 0 {
+				// the same instant for all targets
+				tcbmsg.AsOf = now
+				if msg.Action == apc.ActETLBck {
+					msg.Value = tcbmsg
+				} else {
+					msg.Value = &tcbmsg.CopyBckMsg
+				}
+			}
 		}
 
 		bckTo, ecode, err = p.initBckTo(w, r, query, bckTo)
@@ -1391,6 +1505,11 @@ func (p *proxy) _bckpost(w http.ResponseWriter, r *http.Request, msg *apc.ActMsg
 		//         this one does not
 
 		if !apc.IsFltPresent(fltPresence) && (bckFrom.IsCloud() || bckFrom.IsRemoteAIS()) {
+			if opt := tcbOnlyOpt(tcbmsg); opt != "" {
+				p.writeErrf(w, r, "%s: copying remote %s with %s requires (source) objects to be present in the cluster",
+					p, bckFrom.Cname(""), opt)
+				return
+			}
 			lstcx := &lstcx{
 				p:       p,
 				bckFrom: bckFrom,
@@ -1477,6 +1596,9 @@ func (p *proxy) _bckpost(w http.ResponseWriter, r *http.Request, msg *apc.ActMsg
 	case apc.ActInvalListCache:
 		p.qm.c.invalidate(bck.Bucket())
 		return
+	case apc.ActCreateSnapshot, apc.ActDestroySnapshot, apc.ActRestoreSnapshot:
+		p.snapshotBck(w, r, bck, msg)
+		return
 	case apc.ActMakeNCopies:
 		if xid, err = p.makeNCopies(msg, bck); err != nil {
 			p.writeErr(w, r, err)
@@ -1502,6 +1624,53 @@ func (p *proxy) _bckpost(w http.ResponseWriter, r *http.Request, msg *apc.ActMsg
 	writeXid(w, xid)
 }
 
+// x-tcb options that are not supported when "entire bucket" x-tcb becomes multi-object x-tco
+// (see lstcx)
+func tcbOnlyOpt(msg *apc.TCBMsg) string {
+	switch {
+	case msg.Arch != nil:
+		return "destination archives"
+	case msg.IfNoneMatch:
+		return "conditional copy (if-none-match)"
+	case msg.StartAfter != "":
+		return "start-after"
+	case msg.Regex != "":
+		return "regex"
+	case msg.Template != "":
+		return "template"
+	case msg.LocalOnly:
+		return "local-only"
+	case msg.Flush:
+		return "flush"
+	case msg.Manifest != "":
+		return "destination manifest"
+	case msg.OnSrcChange != "":
+		return "on-source-change"
+	case msg.Diff:
+		return "differential copy"
+	case msg.Priority != "" && msg.Priority != apc.TCBPriorityNormal:
+		return "priority"
+	case msg.PreserveTags:
+		return "preserve-tags"
+	case msg.PreserveOrder:
+		return "preserve-order"
+	case msg.VerifyAfter:
+		return "verify-after"
+	case msg.Snapshot:
+		return "point-in-time snapshot"
+	case msg.SSE != nil:
+		return "server-side encryption"
+	case msg.Deadline != 0:
+		return "deadline"
+	case msg.RateLimit != 0:
+		return "rate limit"
+	case msg.Checkpoint:
+		return "checkpoint"
+	default:
+		return ""
+	}
+}
+
 // init existing or create remote
 // not calling `initAndTry` - delegating ais:from// props cloning to the separate method
 func (p *proxy) initBckTo(w http.ResponseWriter, r *http.Request, query url.Values, bckTo *meta.Bck) (*meta.Bck, int, error) {
@@ -1575,7 +1744,12 @@ func (p *proxy) _bcr(w http.ResponseWriter, r *http.Request, query url.Values, m
 		}
 
 		// remote: check existence and get (cloud) props
-		rhdr, code, err := p.headRemoteBck(bck.RemoteBck(), nil)
+		// (ht:// bucket can be created explicitly only given its manifest)
+		var q url.Values
+		if manifest := htManifestURL(bck, msg); manifest != "" {
+			q = url.Values{apc.QparamOrigURL: []string{manifest}}
+		}
+		rhdr, code, err := p.headRemoteBck(bck.RemoteBck(), q)
 		if err != nil {
 			if msg.Action == apc.ActCreateBck && (code == http.StatusNotFound || code == http.StatusBadRequest) {
 				code = http.StatusNotImplemented
@@ -1629,6 +1803,20 @@ func (p *proxy) _bcr(w http.ResponseWriter, r *http.Request, query url.Values, m
 	}
 }
 
+func htManifestURL(bck *meta.Bck, msg *apc.ActMsg) string {
+	if !bck.IsHT() || msg.Value == nil {
+		return ""
+	}
+	var props cmn.BpropsToSet
+	if err := cos.MorphMarshal(msg.Value, &props); err != nil {
+		return ""
+	}
+	if props.Extra == nil || props.Extra.HTTP == nil || props.Extra.HTTP.Manifest == nil {
+		return ""
+	}
+	return *props.Extra.HTTP.Manifest
+}
+
 func crerrStatus(err error) (ecode int) {
 	switch err.(type) {
 	case *cmn.ErrBucketAlreadyExists:
@@ -1664,7 +1852,8 @@ func (p *proxy) listObjects(w http.ResponseWriter, r *http.Request, bck *meta.Bc
 	case lsmsg.Props == apc.GetPropsNameSize:
 		lsmsg.SetFlag(apc.LsNameSize)
 	}
-	if bck.IsHT() || lsmsg.IsFlagSet(apc.LsArchDir) {
+	// (ht:// bucket is listable only when defined by manifest)
+	if (bck.IsHT() && bck.Props.Extra.HTTP.Manifest == "") || lsmsg.IsFlagSet(apc.LsArchDir) {
 		lsmsg.SetFlag(apc.LsObjCached)
 	}
 
@@ -1831,7 +2020,8 @@ func (p *proxy) httpobjpost(w http.ResponseWriter, r *http.Request, apireq *apiR
 	if err != nil {
 		return
 	}
-	if msg.Action == apc.ActRenameObject {
+	switch msg.Action {
+	case apc.ActRenameObject, apc.ActPresign, apc.ActExtendRetain, apc.ActUndelete:
 		apireq.after = 2
 	}
 	if err := p.parseReq(w, r, apireq); err != nil {
@@ -1848,7 +2038,12 @@ func (p *proxy) httpobjpost(w http.ResponseWriter, r *http.Request, apireq *apiR
 
 	switch msg.Action {
 	case apc.ActRenameObject:
-		if err := p.checkAccess(w, r, bck, apc.AceObjMOVE); err != nil {
+		// both source and destination (see AuthN prefix-scoped ACLs)
+		if err := p.checkObjAccess(w, r, bck, apireq.items[1], apc.AceObjMOVE); err != nil {
+			p.statsT.IncBck(stats.ErrRenameCount, bck.Bucket())
+			return
+		}
+		if err := p.checkObjAccess(w, r, bck, msg.Name, apc.AceObjMOVE); err != nil {
 			p.statsT.IncBck(stats.ErrRenameCount, bck.Bucket())
 			return
 		}
@@ -1877,6 +2072,11 @@ func (p *proxy) httpobjpost(w http.ResponseWriter, r *http.Request, apireq *apiR
 			p.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, p.si, msg.Action, msg.Value, err)
 			return
 		}
+		// (the size of the promoted content is not known in advance)
+		if err := p.checkQuota(r, bck, 0, 1); err != nil {
+			p.writeErr(w, r, err, err.Status())
+			return
+		}
 		var tsi *meta.Snode
 		if args.DaemonID != "" {
 			smap := p.owner.smap.get()
@@ -1894,9 +2094,29 @@ func (p *proxy) httpobjpost(w http.ResponseWriter, r *http.Request, apireq *apiR
 		if xid != "" {
 			writeXid(w, xid)
 		}
+	case apc.ActPresign:
+		p.presignObj(w, r, bck, apireq.items[1], msg)
+	case apc.ActExtendRetain:
+		if err := p.checkObjAccess(w, r, bck, apireq.items[1], apc.AceObjUpdate); err != nil {
+			return
+		}
+		if bck.Props.ObjLock.Mode == "" {
+			p.writeErrf(w, r, "invalid action %q: bucket %s does not have object lock enabled", msg.Action, bck)
+			return
+		}
+		p.redirectAction(w, r, bck, apireq.items[1], msg)
+	case apc.ActUndelete:
+		if err := p.checkObjAccess(w, r, bck, apireq.items[1], apc.AcePUT); err != nil {
+			return
+		}
+		if bck.IsRemote() {
+			p.writeErrf(w, r, "invalid action %q: soft-delete is not supported for remote buckets (%s)", msg.Action, bck)
+			return
+		}
+		p.redirectAction(w, r, bck, apireq.items[1], msg)
 	case apc.ActBlobDl:
 		// TODO: add stats.GetBlobCount and *ErrCount
-		if err := p.checkAccess(w, r, bck, apc.AccessRW); err != nil {
+		if err := p.checkObjAccess(w, r, bck, msg.Name, apc.AccessRW); err != nil {
 			return
 		}
 		if err := cmn.ValidateRemoteBck(apc.ActBlobDl, bck.Bucket()); err != nil {
@@ -2120,6 +2340,19 @@ func (p *proxy) httpbckpatch(w http.ResponseWriter, r *http.Request, apireq *api
 			return
 		}
 	}
+	if repl := &nprops.Replication; repl.Enabled {
+		if !bck.IsAIS() {
+			p.writeErr(w, r, cmn.NewErrUnsupp("replicate", bck.Cname("")+" (not an ais bucket)"))
+			return
+		}
+		// destination must exist (and will be added to BMD if need be)
+		dst := meta.CloneBck(&repl.Bck)
+		args := bctx{p: p, w: w, r: r, bck: dst, msg: msg, dpq: apireq.dpq, query: apireq.query}
+		args.createAIS = false
+		if _, err = args.initAndTry(); err != nil {
+			return
+		}
+	}
 	if xid, err = p.setBprops(msg, bck, nprops); err != nil {
 		p.writeErr(w, r, err)
 		return
@@ -2691,7 +2924,7 @@ func (p *proxy) httpdaeget(w http.ResponseWriter, r *http.Request) {
 		fallthrough // fallthrough
 	case apc.WhatNodeConfig, apc.WhatSmapVote, apc.WhatSnode, apc.WhatLog,
 		apc.WhatNodeStats, apc.WhatNodeStatsV322, apc.WhatMetricNames,
-		apc.WhatNodeStatsAndStatusV322:
+		apc.WhatNodeStatsAndStatusV322, apc.WhatAudit:
 		p.htrun.httpdaeget(w, r, query, nil /*htext*/)
 
 	case apc.WhatNodeStatsAndStatus:
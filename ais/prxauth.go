@@ -7,13 +7,11 @@ package ais
 import (
 	"fmt"
 	"net/http"
-	"os"
 	"sync"
 	"time"
 
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/api/authn"
-	"github.com/NVIDIA/aistore/api/env"
 	"github.com/NVIDIA/aistore/cmd/authn/tok"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
@@ -32,7 +30,9 @@ type (
 		// list of invalid tokens(revoked or of deleted users)
 		// Authn sends these tokens to primary for broadcasting
 		revokedTokens map[string]bool
-		version       int64
+		// IDs of the revoked tokens - to also reject scoped tokens derived from them
+		revokedIDs map[string]struct{}
+		version    int64
 		// signing key secret
 		secret string
 		// lock
@@ -48,8 +48,9 @@ func newAuthManager(config *cmn.Config) *authManager {
 	return &authManager{
 		tkList:        make(tkList),
 		revokedTokens: make(map[string]bool), // TODO: preallocate
+		revokedIDs:    make(map[string]struct{}),
 		version:       1,
-		secret:        cos.Right(config.Auth.Secret, os.Getenv(env.AuthN.SecretKey)), // environment override
+		secret:        authSecret(config),
 	}
 }
 
@@ -80,16 +81,21 @@ func (a *authManager) updateRevokedList(newRevoked *tokenList) (allRevoked *toke
 	}
 
 	// Clean up expired tokens from the revoked list.
+	// (scoped tokens never outlive their parents - ok to forget the IDs of the expired ones as well)
 	now := time.Now()
 
+	clear(a.revokedIDs)
 	for token := range a.revokedTokens {
 		tk, err := tok.DecryptToken(token, a.secret)
 		debug.AssertNoErr(err)
 		if tk.Expires.Before(now) {
 			delete(a.revokedTokens, token)
-		} else {
-			allRevoked.Tokens = append(allRevoked.Tokens, token)
+			continue
 		}
+		if tk.ID != "" {
+			a.revokedIDs[tk.ID] = struct{}{}
+		}
+		allRevoked.Tokens = append(allRevoked.Tokens, token)
 	}
 	if len(allRevoked.Tokens) == 0 {
 		allRevoked = nil
@@ -113,7 +119,7 @@ func (a *authManager) revokedTokenList() (allRevoked *tokenList) {
 }
 
 // Checks if a token is valid:
-//   - must not be revoked one
+//   - must not be revoked one (nor derived from a revoked one)
 //   - must not be expired
 //   - must have all mandatory fields: userID, creds, issued, expires
 //
@@ -122,13 +128,25 @@ func (a *authManager) validateToken(token string) (tk *tok.Token, err error) {
 	a.Lock()
 	if _, ok := a.revokedTokens[token]; ok {
 		tk, err = nil, fmt.Errorf("%v: %s", tok.ErrTokenRevoked, tk)
-	} else {
-		tk, err = a.validateAddRm(token, time.Now())
+	} else if tk, err = a.validateAddRm(token, time.Now()); err == nil && a.isRevokedID(tk) {
+		tk, err = nil, fmt.Errorf("%v: %s", tok.ErrTokenRevoked, tk)
 	}
 	a.Unlock()
 	return
 }
 
+// must be called under lock
+func (a *authManager) isRevokedID(tk *tok.Token) bool {
+	if len(a.revokedIDs) == 0 {
+		return false
+	}
+	if _, ok := a.revokedIDs[tk.ID]; ok && tk.ID != "" {
+		return true
+	}
+	_, ok := a.revokedIDs[tk.Parent]
+	return ok && tk.Parent != ""
+}
+
 // Decrypts and validates token. Adds it to authManager.token if not found. Removes if expired.
 // Must be called under lock.
 func (a *authManager) validateAddRm(token string, now time.Time) (*tok.Token, error) {
@@ -256,7 +274,20 @@ func aceErrToCode(err error) (status int) {
 	return status
 }
 
-func (p *proxy) access(hdr http.Header, bck *meta.Bck, ace apc.AccessAttrs) (err error) {
+// same as above for a given object (prefix-scoped ACLs)
+func (p *proxy) checkObjAccess(w http.ResponseWriter, r *http.Request, bck *meta.Bck, objName string, ace apc.AccessAttrs) (err error) {
+	if err = p.accessObj(r.Header, bck, objName, ace); err != nil {
+		p.writeErr(w, r, err, aceErrToCode(err))
+	}
+	return
+}
+
+func (p *proxy) access(hdr http.Header, bck *meta.Bck, ace apc.AccessAttrs) error {
+	return p.accessObj(hdr, bck, "" /*objName*/, ace)
+}
+
+// NOTE: empty `objName` - bucket-level access; otherwise, AuthN ACLs scoped to object name prefixes apply
+func (p *proxy) accessObj(hdr http.Header, bck *meta.Bck, objName string, ace apc.AccessAttrs) (err error) {
 	var (
 		tk     *tok.Token
 		bucket *cmn.Bck
@@ -277,7 +308,7 @@ func (p *proxy) access(hdr http.Header, bck *meta.Bck, ace apc.AccessAttrs) (err
 		if bck != nil {
 			bucket = bck.Bucket()
 		}
-		if err := tk.CheckPermissions(uid, bucket, ace); err != nil {
+		if err := tk.CheckObjPermissions(uid, bucket, objName, ace); err != nil {
 			return err
 		}
 	}
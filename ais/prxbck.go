@@ -35,16 +35,18 @@ type bctx struct {
 
 	origURLBck string
 
+	objName string          // when accessing an object (see AuthN prefix-scoped ACLs)
 	reqBody []byte          // request body of original request
 	perms   apc.AccessAttrs // apc.AceGET, apc.AcePATCH etc.
 
-	// 5 user or caller-provided control flags followed by
+	// 6 user or caller-provided control flags followed by
 	// 3 result flags
 	skipBackend    bool // initialize bucket via `bck.InitNoBackend`
 	createAIS      bool // create ais bucket on the fly
 	dontAddRemote  bool // do not create (ie., add -> BMD) remote bucket on the fly
 	dontHeadRemote bool // do not HEAD remote bucket (to find out whether it exists and/or get properties)
 	tryHeadRemote  bool // when listing objects anonymously (via ListObjsMsg.Flags LsTryHeadRemote)
+	presigned      bool // presigned URL verified by the caller (in lieu of AuthN token)
 	isPresent      bool // the bucket is confirmed to be present (in the cluster's BMD)
 	exists         bool // remote bucket is confirmed to exist
 	modified       bool // bucket-defining control structure got modified
@@ -176,7 +178,12 @@ rerr:
 
 // (compare w/ accessSupported)
 func (bctx *bctx) accessAllowed(bck *meta.Bck) (ecode int, err error) {
-	err = bctx.p.access(bctx.r.Header, bck, bctx.perms)
+	if bctx.presigned {
+		// bucket ACL only (see presign.go)
+		err = bck.Allow(bctx.perms)
+		return aceErrToCode(err), err
+	}
+	err = bctx.p.accessObj(bctx.r.Header, bck, bctx.objName, bctx.perms)
 	ecode = aceErrToCode(err)
 	return ecode, err
 }
@@ -23,8 +23,10 @@ import (
 	"github.com/NVIDIA/aistore/cmn/nlog"
 	"github.com/NVIDIA/aistore/core"
 	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/nl"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/xact"
+	"github.com/NVIDIA/aistore/xact/xreg"
 	jsoniter "github.com/json-iterator/go"
 )
 
@@ -81,6 +83,8 @@ func (p *proxy) httpcluget(w http.ResponseWriter, r *http.Request) {
 		p.qcluSysinfo(w, r, what, query)
 	case apc.WhatMountpaths:
 		p.qcluMountpaths(w, r, what, query)
+	case apc.WhatQuotaUsage:
+		p.qcluQuotaUsage(w, r, what)
 	case apc.WhatBackends:
 		config := cmn.GCO.Get()
 		out := make([]string, 0, len(config.Backend.Providers))
@@ -120,7 +124,19 @@ func (p *proxy) httpcluget(w http.ResponseWriter, r *http.Request) {
 		// hide secret
 		c := config.ClusterConfig
 		c.Auth.Secret = "**********"
+		if c.Transport.Secret != "" {
+			c.Transport.Secret = "**********"
+		}
 		p.writeJSON(w, r, &c, what)
+	case apc.WhatCertificate:
+		p.qcluX509(w, r, what, query)
+	case apc.WhatRebWeights:
+		weights, err := p.owner.rmd.get().Weights()
+		if err != nil {
+			p.writeErr(w, r, err)
+			return
+		}
+		p.writeJSON(w, r, weights, what)
 	case apc.WhatBMD, apc.WhatSmapVote, apc.WhatSnode, apc.WhatSmap:
 		p.htrun.httpdaeget(w, r, query, nil /*htext*/)
 	default:
@@ -978,7 +994,8 @@ func (p *proxy) cluputMsg(w http.ResponseWriter, r *http.Request) {
 
 	// not just 'cluster-started' - must be ready to rebalance as well
 	// with two distinct exceptions
-	withRR := (msg.Action != apc.ActShutdownCluster && msg.Action != apc.ActXactStop)
+	withRR := (msg.Action != apc.ActShutdownCluster && msg.Action != apc.ActXactStop &&
+		msg.Action != apc.ActXactPause && msg.Action != apc.ActXactResume)
 	if err := p.pready(nil, withRR); err != nil {
 		p.writeErr(w, r, err, http.StatusServiceUnavailable)
 		return
@@ -1051,6 +1068,12 @@ func (p *proxy) cluputMsg(w http.ResponseWriter, r *http.Request) {
 		p.xstart(w, r, msg)
 	case apc.ActXactStop:
 		p.xstop(w, r, msg)
+	case apc.ActXactPause, apc.ActXactResume:
+		p.xpause(w, r, msg)
+	case apc.ActXactRateLimit:
+		p.xtcbctl(w, r, msg)
+	case apc.ActSetRebWeights:
+		p.setRebWeights(w, r, msg)
 
 	case apc.ActReloadBackendCreds:
 		if msg.Name != "" {
@@ -1195,6 +1218,18 @@ func _setConfPre(ctx *configModifier, clone *globalConfig) (updated bool, err er
 	if err = clone.Apply(ctx.toUpdate, apc.Cluster); err != nil {
 		return
 	}
+	if ctx.toUpdate.Sched != nil {
+		if err = xreg.CheckSched(&clone.Sched); err != nil {
+			return
+		}
+	}
+	if ctx.toUpdate.Governor != nil {
+		for kind := range clone.Governor.Weights {
+			if err = xact.CheckValidKind(kind); err != nil {
+				return
+			}
+		}
+	}
 	updated = true
 	return
 }
@@ -1238,7 +1273,11 @@ func (p *proxy) xstart(w http.ResponseWriter, r *http.Request, msg *apc.ActMsg)
 		return
 	}
 
-	args := allocBcArgs()
+	var (
+		srcs  meta.NodeMap // notifying targets (nil: all active)
+		args  = allocBcArgs()
+		drain *_drainfin
+	)
 	args.req = cmn.HreqArgs{Method: http.MethodPut, Path: apc.URLPathXactions.S}
 
 	switch {
@@ -1263,6 +1302,23 @@ func (p *proxy) xstart(w http.ResponseWriter, r *http.Request, msg *apc.ActMsg)
 		}
 		args._selected(tsi)
 		args.req.Body = cos.MustMarshal(apc.ActMsg{Action: msg.Action, Value: xargs})
+	case xargs.Kind == apc.ActDrain:
+		// the target in maintenance mode and all active targets
+		args.smap = p.owner.smap.get()
+		tsi, err := p.drainTarget(args.smap, &xargs)
+		if err != nil {
+			freeBcArgs(args)
+			p.writeErr(w, r, err)
+			return
+		}
+		srcs = args.smap.Tmap.ActiveMap()
+		srcs[tsi.ID()] = tsi
+		args.nodes = []meta.NodeMap{srcs}
+		args.to = core.SelectedNodes
+		args.ignoreMaintenance = true
+		xargs.ID = cos.GenUUID()
+		args.req.Body = cos.MustMarshal(apc.ActMsg{Action: msg.Action, Value: xargs})
+		drain = &_drainfin{p: p, tid: tsi.ID()}
 	default:
 		// all targets, one common UUID for all
 		args.to = core.Targets
@@ -1290,12 +1346,46 @@ func (p *proxy) xstart(w http.ResponseWriter, r *http.Request, msg *apc.ActMsg)
 
 	if xargs.ID != "" {
 		smap := p.owner.smap.get()
-		nl := xact.NewXactNL(xargs.ID, xargs.Kind, &smap.Smap, nil)
+		nl := xact.NewXactNL(xargs.ID, xargs.Kind, &smap.Smap, srcs)
+		if drain != nil {
+			nl.F = drain.cb
+		}
 		p.ic.registerEqual(regIC{smap: smap, nl: nl})
 		writeXid(w, xargs.ID)
 	}
 }
 
+// the target to drain: specified or else the one (in maintenance mode) recorded in RMD.TargetIDs
+func (p *proxy) drainTarget(smap *smapX, xargs *xact.ArgsMsg) (*meta.Snode, error) {
+	if xargs.DaemonID == "" {
+		rmd := p.owner.rmd.get()
+		for _, tid := range rmd.TargetIDs {
+			if tsi := smap.GetTarget(tid); tsi != nil && tsi.InMaint() {
+				if xargs.DaemonID != "" {
+					return nil, fmt.Errorf("%s: multiple targets in maintenance mode (%s, %s) - please specify",
+						apc.ActDrain, meta.Tname(xargs.DaemonID), meta.Tname(tid))
+				}
+				xargs.DaemonID = tid
+			}
+		}
+		if xargs.DaemonID == "" {
+			return nil, fmt.Errorf("%s: no target in maintenance mode in %s - please specify", apc.ActDrain, rmd)
+		}
+	}
+	tsi := smap.GetTarget(xargs.DaemonID)
+	switch {
+	case tsi == nil:
+		return nil, &errNodeNotFound{p.si, smap, "cannot " + apc.ActDrain, xargs.DaemonID}
+	case !tsi.InMaint() || tsi.Flags.IsSet(meta.SnodeDecomm):
+		return nil, fmt.Errorf("%s: %s is not in maintenance mode", apc.ActDrain, tsi.StringEx())
+	case tsi.InMaintPostReb():
+		return nil, fmt.Errorf("%s: %s is already safe to decommission - nothing to do", apc.ActDrain, tsi.StringEx())
+	case smap.CountActiveTs() == 0:
+		return nil, cmn.NewErrNoNodes(apc.Target, smap.CountTargets())
+	}
+	return tsi, nil
+}
+
 func (a *bcastArgs) _selected(tsi *meta.Snode) {
 	nmap := make(meta.NodeMap, 1)
 	nmap[tsi.ID()] = tsi
@@ -1344,6 +1434,38 @@ func (p *proxy) xstop(w http.ResponseWriter, r *http.Request, msg *apc.ActMsg) {
 		}
 	}
 
+	body := cos.MustMarshal(apc.ActMsg{Action: msg.Action, Name: msg.Name /*abort reason*/, Value: xargs})
+	args := allocBcArgs()
+	args.req = cmn.HreqArgs{Method: http.MethodPut, Path: apc.URLPathXactions.S, Body: body}
+	args.to = core.Targets
+	results := p.bcastGroup(args)
+	freeBcArgs(args)
+
+	for _, res := range results {
+		if res.err != nil {
+			p.writeErr(w, r, res.toErr())
+			break
+		}
+	}
+	freeBcastRes(results)
+}
+
+// pause or resume (e.g., copy-bucket or rebalance during peak hours); see xact.Descriptor.Pausable
+func (p *proxy) xpause(w http.ResponseWriter, r *http.Request, msg *apc.ActMsg) {
+	var xargs xact.ArgsMsg
+	if err := cos.MorphMarshal(msg.Value, &xargs); err != nil {
+		p.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, p.si, msg.Action, msg.Value, err)
+		return
+	}
+	xargs.Kind, _ = xact.GetKindName(xargs.Kind) // display name => kind
+	if xargs.Kind == "" && xact.IsValidRebID(xargs.ID) {
+		xargs.Kind = apc.ActRebalance
+	}
+	if xargs.Kind != "" && !xact.Table[xargs.Kind].Pausable {
+		p.writeErrf(w, r, "cannot %s %q: not supported for this xaction kind", msg.Action, xargs.Kind)
+		return
+	}
+
 	body := cos.MustMarshal(apc.ActMsg{Action: msg.Action, Value: xargs})
 	args := allocBcArgs()
 	args.req = cmn.HreqArgs{Method: http.MethodPut, Path: apc.URLPathXactions.S, Body: body}
@@ -1360,6 +1482,38 @@ func (p *proxy) xstop(w http.ResponseWriter, r *http.Request, msg *apc.ActMsg) {
 	freeBcastRes(results)
 }
 
+// control running x-tcb (copy or transform bucket) on all targets
+func (p *proxy) xtcbctl(w http.ResponseWriter, r *http.Request, msg *apc.ActMsg) {
+	var ctl xact.TCBCtlMsg
+	if err := cos.MorphMarshal(msg.Value, &ctl); err != nil {
+		p.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, p.si, msg.Action, msg.Value, err)
+		return
+	}
+	if err := xact.CheckValidUUID(ctl.ID); err != nil {
+		p.writeErrf(w, r, "cannot %s: %v", msg.Action, err)
+		return
+	}
+	if ctl.RateLimit < 0 {
+		p.writeErrf(w, r, "cannot %s: invalid rate limit %d", msg.Action, ctl.RateLimit)
+		return
+	}
+
+	body := cos.MustMarshal(apc.ActMsg{Action: msg.Action, Value: ctl})
+	args := allocBcArgs()
+	args.req = cmn.HreqArgs{Method: http.MethodPut, Path: apc.URLPathXactions.S, Body: body}
+	args.to = core.Targets
+	results := p.bcastGroup(args)
+	freeBcArgs(args)
+
+	for _, res := range results {
+		if res.err != nil {
+			p.writeErr(w, r, res.toErr())
+			break
+		}
+	}
+	freeBcastRes(results)
+}
+
 func (p *proxy) _checkMaint(xargs *xact.ArgsMsg) error {
 	smap := p.owner.smap.get()
 	for _, tsi := range smap.Tmap {
@@ -1435,6 +1589,50 @@ func (p *proxy) rebalanceCluster(w http.ResponseWriter, r *http.Request, msg *ap
 	writeXid(w, rmdCtx.rebID)
 }
 
+// replace per-target rebalance weights (empty - reset) and rebalance accordingly
+func (p *proxy) setRebWeights(w http.ResponseWriter, r *http.Request, msg *apc.ActMsg) {
+	var weights apc.RebWeights
+	if err := cos.MorphMarshal(msg.Value, &weights); err != nil {
+		p.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, p.si, msg.Action, msg.Value, err)
+		return
+	}
+	if err := weights.Validate(); err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	smap := p.owner.smap.get()
+	for tid := range weights {
+		if smap.GetTarget(tid) == nil {
+			p.writeErr(w, r, cos.NewErrNotFound(p, "target "+tid), http.StatusNotFound)
+			return
+		}
+	}
+	if err := p.canRebalance(); err != nil && err != errRebalanceDisabled {
+		p.writeErr(w, r, err)
+		return
+	}
+	if err := p.owner.rmd.get().clone().SetWeights(weights); err != nil { // (unlikely)
+		p.writeErr(w, r, err)
+		return
+	}
+	rmdCtx := &rmdModifier{
+		pre: func(_ *rmdModifier, clone *rebMD) {
+			clone.inc()
+			err := clone.SetWeights(weights)
+			debug.AssertNoErr(err)
+		},
+		final:   rmdSync,
+		p:       p,
+		smapCtx: &smapModifier{smap: smap, msg: msg},
+	}
+	if _, err := p.owner.rmd.modify(rmdCtx); err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	nlog.Infoln(p.String()+": set rebalance weights", weights, "-", rmdCtx.cur.String())
+	writeXid(w, rmdCtx.rebID)
+}
+
 // gracefully remove node via apc.ActStartMaintenance, apc.ActDecommission, apc.ActShutdownNode
 func (p *proxy) rmNode(w http.ResponseWriter, r *http.Request, msg *apc.ActMsg) {
 	var (
@@ -2185,3 +2383,46 @@ func mustRebalance(ctx *smapModifier, cur *smapX) bool {
 	}
 	return false
 }
+
+///////////////
+// _drainfin //
+///////////////
+
+type _drainfin struct {
+	p   *proxy
+	tid string
+}
+
+// upon successful drain: mark the target safe to decommission
+// (compare with rmNodeFinal => mcastMaint(maintPostReb) when rebalance is done)
+func (r *_drainfin) cb(nl nl.Listener) {
+	var (
+		p    = r.p
+		smap = p.owner.smap.get()
+		tsi  = smap.GetTarget(r.tid)
+		name = apc.ActDrain + "[" + nl.UUID() + "]"
+	)
+	switch {
+	case nl.ErrCnt() > 0 || nl.Aborted():
+		nlog.Errorln(name, "failed:", nl.Err())
+		return
+	case !smap.IsPrimary(p.si):
+		return
+	case tsi == nil || !tsi.InMaint() || tsi.InMaintPostReb():
+		nlog.Warningln(name, "done, target", meta.Tname(r.tid), "is no longer draining in", smap.StringEx())
+		return
+	}
+	ctx := &smapModifier{
+		pre:     p._markMaint,
+		final:   p._syncFinal,
+		sid:     r.tid,
+		flags:   meta.SnodeMaint | meta.SnodeMaintPostReb,
+		msg:     &apc.ActMsg{Action: apc.ActStartMaintenance, Name: r.tid},
+		skipReb: true,
+	}
+	if err := p.owner.smap.modify(ctx); err != nil {
+		nlog.Errorln(name, "done but failed to mark", tsi.StringEx(), "safe to decommission:", err)
+		return
+	}
+	nlog.Infoln(name, "done:", tsi.StringEx(), "is safe to decommission")
+}
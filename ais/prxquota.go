@@ -0,0 +1,265 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Quota enforcement (see cmn.QuotaConf):
+// - bucket quota is part of the bucket props; user quota comes with the user's token;
+// - the proxy keeps a cached copy of the cluster-wide usage - the sum of the usage
+//   reported by all targets (apc.WhatQuotaUsage) - and refreshes it every so often;
+// - in-between refreshes, admitted writes are added to the cached usage;
+// - enforcement is, therefore, approximate: concurrent writes via multiple proxies
+//   may briefly overshoot the limit by up to (quotaRefresh) worth of writes.
+
+const quotaRefresh = 10 * time.Second
+
+// quota hint (see checkPutQuota): "<tag>,<limit>,<usage>"
+const (
+	quotaHintBck  = "b"
+	quotaHintUser = "u"
+)
+
+type quotaCache struct {
+	usage      *apc.QuotaUsage
+	mu         sync.Mutex
+	last       int64 // mono time of the last refresh
+	refreshing atomic.Bool
+}
+
+// check bucket and user quotas prior to writing (size, objects) into a given bucket
+// (the caller writes the resulting error, if any, with err.Status())
+func (p *proxy) checkQuota(r *http.Request, bck *meta.Bck, size, objects int64) *cmn.ErrQuotaExceeded {
+	return p._quota(r, bck, size, objects, nil)
+}
+
+// same as above for a single PUT that gets redirected to the target; in addition:
+//   - object count limit that is reached does not fail the request - overwrites are still fine
+//     and only the target knows whether the object exists;
+//   - object of unknown size (size < 0, e.g. chunked transfer encoding) must fit into the remaining capacity;
+//
+// in both cases, the limit and the current usage are passed to the target (apc.QparamQuotaObjs, apc.QparamQuotaSize)
+func (p *proxy) checkPutQuota(r *http.Request, bck *meta.Bck, size, objects int64) *cmn.ErrQuotaExceeded {
+	hints := url.Values{}
+	if err := p._quota(r, bck, size, objects, hints); err != nil {
+		return err
+	}
+	if len(hints) > 0 {
+		if r.URL.RawQuery != "" {
+			r.URL.RawQuery += "&"
+		}
+		r.URL.RawQuery += hints.Encode()
+	}
+	return nil
+}
+
+func (p *proxy) _quota(r *http.Request, bck *meta.Bck, size, objects int64, hints url.Values) *cmn.ErrQuotaExceeded {
+	var (
+		bq   = &bck.Props.Quota
+		uq   *cmn.QuotaConf
+		user string
+	)
+	if tk := p.reqToken(r.Header); tk != nil && !tk.IsAdmin && tk.Quota.IsSet() {
+		uq, user = tk.Quota, tk.UserID
+	}
+	if !bq.IsSet() && uq == nil {
+		return nil
+	}
+	if err := p.quota.sync(p); err != nil {
+		// failing to get the usage must not block writes
+		nlog.Warningln(p.String(), "failed to get quota usage:", err)
+		return nil
+	}
+
+	qc := &p.quota
+	qc.mu.Lock()
+	var (
+		bu  = _usage(qc.usage.Buckets, bck.Cname(""))
+		uu  *apc.Usage
+		err *cmn.ErrQuotaExceeded
+	)
+	if bq.IsSet() {
+		err = _checkQuota(bq, bu, size, objects, hints, quotaHintBck, func(what string, limit, usage int64) *cmn.ErrQuotaExceeded {
+			return cmn.NewErrBckQuotaExceeded(bck.Cname(""), what, limit, usage)
+		})
+	}
+	if err == nil && uq != nil {
+		uu = _usage(qc.usage.Users, user)
+		err = _checkQuota(uq, uu, size, objects, hints, quotaHintUser, func(what string, limit, usage int64) *cmn.ErrQuotaExceeded {
+			return cmn.NewErrUserQuotaExceeded(user, what, limit, usage)
+		})
+	}
+	if err == nil {
+		// optimistically, until the next refresh
+		bu.Add(max(size, 0), objects)
+		if uu != nil {
+			uu.Add(max(size, 0), objects)
+		}
+	}
+	qc.mu.Unlock()
+	return err
+}
+
+func _usage(m map[string]*apc.Usage, key string) *apc.Usage {
+	u, ok := m[key]
+	if !ok {
+		u = &apc.Usage{}
+		m[key] = u
+	}
+	return u
+}
+
+func _checkQuota(q *cmn.QuotaConf, u *apc.Usage, size, objects int64, hints url.Values, tag string,
+	newErr func(what string, limit, usage int64) *cmn.ErrQuotaExceeded) *cmn.ErrQuotaExceeded {
+	if q.Size > 0 {
+		limit := int64(q.Size)
+		if u.Size >= limit || (size > 0 && u.Size+size > limit) {
+			return newErr(cmn.QuotaSize, limit, u.Size)
+		}
+		if size < 0 && hints != nil {
+			// keep the one with less room
+			if _, l, n, err := parseQuotaHint(hints.Get(apc.QparamQuotaSize)); err != nil || limit-u.Size < l-n {
+				hints.Set(apc.QparamQuotaSize, makeQuotaHint(tag, limit, u.Size))
+			}
+		}
+	}
+	if q.Objects > 0 && objects > 0 && u.Objects+objects > q.Objects {
+		if hints == nil {
+			return newErr(cmn.QuotaObjects, q.Objects, u.Objects)
+		}
+		if !hints.Has(apc.QparamQuotaObjs) {
+			hints.Set(apc.QparamQuotaObjs, makeQuotaHint(tag, q.Objects, u.Objects))
+		}
+	}
+	return nil
+}
+
+// cluster-wide usage (apc.WhatQuotaUsage)
+func (p *proxy) qcluQuotaUsage(w http.ResponseWriter, r *http.Request, what string) {
+	usage, err := p.bcastQuotaUsage()
+	if err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	// the cached usage is updated in place by admitted writes (see _quota) - serialize a copy
+	p.quota.set(usage)
+	p.writeJSON(w, r, p.quota.clone(), what)
+}
+
+func (p *proxy) bcastQuotaUsage() (*apc.QuotaUsage, error) {
+	args := allocBcArgs()
+	args.req = cmn.HreqArgs{
+		Method: http.MethodGet,
+		Path:   apc.URLPathDae.S,
+		Query:  url.Values{apc.QparamWhat: []string{apc.WhatQuotaUsage}},
+	}
+	args.to = core.Targets
+	results := p.bcastGroup(args)
+	freeBcArgs(args)
+
+	usage := apc.NewQuotaUsage()
+	for _, res := range results {
+		if res.err != nil {
+			err := res.toErr()
+			freeBcastRes(results)
+			return nil, err
+		}
+		tusage := &apc.QuotaUsage{}
+		if err := jsoniter.Unmarshal(res.bytes, tusage); err != nil {
+			freeBcastRes(results)
+			return nil, err
+		}
+		usage.Merge(tusage)
+	}
+	freeBcastRes(results)
+	return usage, nil
+}
+
+////////////////
+// quotaCache //
+////////////////
+
+// make sure the cached usage is there and is reasonably fresh:
+// the very first time - synchronously, otherwise - in the background
+func (qc *quotaCache) sync(p *proxy) error {
+	qc.mu.Lock()
+	usage, last := qc.usage, qc.last
+	qc.mu.Unlock()
+	if usage == nil {
+		cluUsage, err := p.bcastQuotaUsage()
+		if err != nil {
+			return err
+		}
+		qc.set(cluUsage)
+		return nil
+	}
+	if mono.Since(last) > quotaRefresh && qc.refreshing.CAS(false, true) {
+		go qc.refresh(p)
+	}
+	return nil
+}
+
+func (qc *quotaCache) refresh(p *proxy) {
+	usage, err := p.bcastQuotaUsage()
+	if err == nil {
+		qc.set(usage)
+	} else if cmn.Rom.FastV(4, cos.SmoduleAIS) {
+		nlog.Warningln(p.String(), "failed to refresh quota usage:", err)
+	}
+	qc.refreshing.Store(false)
+}
+
+func (qc *quotaCache) set(usage *apc.QuotaUsage) {
+	qc.mu.Lock()
+	qc.usage, qc.last = usage, mono.NanoTime()
+	qc.mu.Unlock()
+}
+
+func (qc *quotaCache) clone() *apc.QuotaUsage {
+	qc.mu.Lock()
+	usage := qc.usage.Clone()
+	qc.mu.Unlock()
+	return usage
+}
+
+/////////////////
+// quota hints //
+/////////////////
+
+func makeQuotaHint(tag string, limit, usage int64) string {
+	return tag + "," + strconv.FormatInt(limit, 10) + "," + strconv.FormatInt(usage, 10)
+}
+
+func parseQuotaHint(s string) (tag string, limit, usage int64, err error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 || (parts[0] != quotaHintBck && parts[0] != quotaHintUser) {
+		return "", 0, 0, errors.New("invalid quota hint " + strconv.Quote(s))
+	}
+	if limit, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+		return "", 0, 0, err
+	}
+	if usage, err = strconv.ParseInt(parts[2], 10, 64); err != nil {
+		return "", 0, 0, err
+	}
+	return parts[0], limit, usage, nil
+}
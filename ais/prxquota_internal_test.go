@@ -0,0 +1,114 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+func TestQuotaHints(t *testing.T) {
+	var (
+		q      = &cmn.QuotaConf{Size: 10 * cos.KiB, Objects: 10}
+		newErr = func(what string, limit, usage int64) *cmn.ErrQuotaExceeded {
+			return cmn.NewErrBckQuotaExceeded("ais://q", what, limit, usage)
+		}
+	)
+
+	// object count limit reached: fails when not redirecting, passes the limit to the target otherwise
+	u := &apc.Usage{Size: cos.KiB, Objects: 10}
+	if err := _checkQuota(q, u, 100, 1, nil, quotaHintBck, newErr); err == nil || err.What != cmn.QuotaObjects {
+		t.Fatalf("expected %s quota error, got %v", cmn.QuotaObjects, err)
+	}
+	hints := url.Values{}
+	if err := _checkQuota(q, u, 100, 1, hints, quotaHintBck, newErr); err != nil {
+		t.Fatal(err)
+	}
+	tag, limit, usage, err := parseQuotaHint(hints.Get(apc.QparamQuotaObjs))
+	if err != nil || tag != quotaHintBck || limit != 10 || usage != 10 {
+		t.Fatalf("unexpected %s hint %q (%v)", apc.QparamQuotaObjs, hints.Get(apc.QparamQuotaObjs), err)
+	}
+	if hints.Has(apc.QparamQuotaSize) {
+		t.Fatalf("unexpected %s hint for known size", apc.QparamQuotaSize)
+	}
+
+	// unknown size: the one with less room wins
+	u = &apc.Usage{Size: 8 * cos.KiB}
+	hints = url.Values{}
+	hints.Set(apc.QparamQuotaSize, makeQuotaHint(quotaHintUser, 100*cos.KiB, 10*cos.KiB))
+	if err := _checkQuota(q, u, -1, 1, hints, quotaHintBck, newErr); err != nil {
+		t.Fatal(err)
+	}
+	if tag, limit, usage, _ = parseQuotaHint(hints.Get(apc.QparamQuotaSize)); tag != quotaHintBck || limit-usage != 2*cos.KiB {
+		t.Fatalf("unexpected %s hint %q", apc.QparamQuotaSize, hints.Get(apc.QparamQuotaSize))
+	}
+
+	// size limit reached: fails regardless
+	u = &apc.Usage{Size: 10 * cos.KiB}
+	if err := _checkQuota(q, u, -1, 0, url.Values{}, quotaHintBck, newErr); err == nil || err.What != cmn.QuotaSize {
+		t.Fatalf("expected %s quota error, got %v", cmn.QuotaSize, err)
+	}
+
+	for _, s := range []string{"", "b,1", "x,1,2", "u,one,2"} {
+		if _, _, _, err := parseQuotaHint(s); err == nil {
+			t.Fatalf("expected %q to fail", s)
+		}
+	}
+
+	// target: remaining capacity
+	qerr := newErr(cmn.QuotaSize, 10, 6)
+	qr := &quotaReader{ReadCloser: io.NopCloser(bytes.NewReader(make([]byte, 4))), room: 4, err: qerr}
+	if _, err := io.ReadAll(qr); err != nil {
+		t.Fatal(err)
+	}
+	qr = &quotaReader{ReadCloser: io.NopCloser(bytes.NewReader(make([]byte, 5))), room: 4, err: qerr}
+	if _, err := io.ReadAll(qr); err != qerr {
+		t.Fatalf("expected %v, got %v", qerr, err)
+	}
+}
+
+// the cached usage gets updated in place by admitted writes while being reported (run with -race)
+func TestQuotaUsageClone(t *testing.T) {
+	var (
+		qc quotaCache
+		wg sync.WaitGroup
+	)
+	qc.set(apc.NewQuotaUsage())
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 1000 {
+			qc.mu.Lock()
+			_usage(qc.usage.Buckets, "ais://q"+strconv.Itoa(i%10)).Add(cos.KiB, 1)
+			_usage(qc.usage.Users, "u"+strconv.Itoa(i%10)).Add(cos.KiB, 1)
+			qc.mu.Unlock()
+		}
+	}()
+	for range 100 {
+		if _, err := jsoniter.Marshal(qc.clone()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+
+	usage := qc.clone()
+	if len(usage.Buckets) != 10 || usage.Buckets["ais://q0"].Objects != 100 || usage.Users["u9"].Size != 100*cos.KiB {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+	usage.Buckets["ais://q0"].Add(cos.KiB, 1)
+	if qc.clone().Buckets["ais://q0"].Objects != 100 {
+		t.Fatal("expected deep copy")
+	}
+}
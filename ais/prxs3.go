@@ -227,15 +227,24 @@ func (p *proxy) handleMptUpload(w http.ResponseWriter, r *http.Request, parts []
 	if bck == nil {
 		return
 	}
-	if err := p.access(r.Header, bck, apc.AcePUT); err != nil {
-		s3.WriteErr(w, r, err, http.StatusForbidden)
-		return
-	}
 	objName := s3.ObjName(parts)
 	if err := cmn.ValidOname(objName); err != nil {
 		s3.WriteErr(w, r, err, 0)
 		return
 	}
+	if err := p.accessObj(r.Header, bck, objName, apc.AcePUT); err != nil {
+		s3.WriteErr(w, r, err, http.StatusForbidden)
+		return
+	}
+	// quotas: multipart upload part is not (yet) an object
+	var objects int64 = 1
+	if r.URL.Query().Has(s3.QparamMptUploadID) {
+		objects = 0
+	}
+	if err := p.checkPutQuota(r, bck, r.ContentLength, objects); err != nil {
+		s3.WriteErr(w, r, err, err.Status())
+		return
+	}
 
 	smap := p.owner.smap.get()
 	si, netPub, err := smap.HrwMultiHome(bck.MakeUname(objName))
@@ -255,10 +264,6 @@ func (p *proxy) delMultipleObjs(w http.ResponseWriter, r *http.Request, bucket s
 	if bck == nil {
 		return
 	}
-	if err := p.access(r.Header, bck, apc.AceObjDELETE); err != nil {
-		s3.WriteErr(w, r, err, http.StatusForbidden)
-		return
-	}
 	decoder := xml.NewDecoder(r.Body)
 	lst := &s3.Delete{}
 	if err := decoder.Decode(lst); err != nil {
@@ -274,6 +279,11 @@ func (p *proxy) delMultipleObjs(w http.ResponseWriter, r *http.Request, bucket s
 		lrMsg = &apc.ListRange{ObjNames: make([]string, 0, len(lst.Object))}
 	)
 	for _, obj := range lst.Object {
+		// per object (see AuthN prefix-scoped ACLs)
+		if err := p.accessObj(r.Header, bck, obj.Key, apc.AceObjDELETE); err != nil {
+			s3.WriteErr(w, r, err, http.StatusForbidden)
+			return
+		}
 		lrMsg.ObjNames = append(lrMsg.ObjNames, obj.Key)
 	}
 	msg.Value = lrMsg
@@ -462,7 +472,8 @@ func (p *proxy) copyObjS3(w http.ResponseWriter, r *http.Request, items []string
 	if bckSrc == nil {
 		return
 	}
-	if err := p.access(r.Header, bckSrc, apc.AceGET); err != nil {
+	objName := strings.Trim(parts[1], "/")
+	if err := p.accessObj(r.Header, bckSrc, objName, apc.AceGET); err != nil {
 		s3.WriteErr(w, r, err, http.StatusForbidden)
 		return
 	}
@@ -471,12 +482,15 @@ func (p *proxy) copyObjS3(w http.ResponseWriter, r *http.Request, items []string
 	if bckDst == nil {
 		return
 	}
-	if err := bckDst.Allow(apc.AcePUT); err != nil {
+	if err := p.accessObj(r.Header, bckDst, s3.ObjName(items), apc.AcePUT); err != nil {
 		s3.WriteErr(w, r, err, http.StatusForbidden)
 		return
 	}
+	if err := p.checkQuota(r, bckDst, 0, 1); err != nil {
+		s3.WriteErr(w, r, err, err.Status())
+		return
+	}
 
-	objName := strings.Trim(parts[1], "/")
 	smap := p.owner.smap.get()
 	si, err := smap.HrwName2T(bckSrc.MakeUname(objName))
 	if err != nil {
@@ -498,10 +512,6 @@ func (p *proxy) directPutObjS3(w http.ResponseWriter, r *http.Request, items []s
 	if bck == nil {
 		return
 	}
-	if err := p.access(r.Header, bck, apc.AcePUT); err != nil {
-		s3.WriteErr(w, r, err, http.StatusForbidden)
-		return
-	}
 	if len(items) < 2 {
 		s3.WriteErr(w, r, errS3Obj, 0)
 		return
@@ -511,6 +521,10 @@ func (p *proxy) directPutObjS3(w http.ResponseWriter, r *http.Request, items []s
 		s3.WriteErr(w, r, err, 0)
 		return
 	}
+	if err := p.accessObj(r.Header, bck, objName, apc.AcePUT); err != nil {
+		s3.WriteErr(w, r, err, http.StatusForbidden)
+		return
+	}
 
 	smap := p.owner.smap.get()
 	si, netPub, err := smap.HrwMultiHome(bck.MakeUname(objName))
@@ -533,11 +547,11 @@ func (p *proxy) getObjS3(w http.ResponseWriter, r *http.Request, items []string,
 	if bck == nil {
 		return
 	}
-	if err := p.access(r.Header, bck, apc.AceGET); err != nil {
-		s3.WriteErr(w, r, err, http.StatusForbidden)
-		return
-	}
 	if listMultipart {
+		if err := p.access(r.Header, bck, apc.AceGET); err != nil {
+			s3.WriteErr(w, r, err, http.StatusForbidden)
+			return
+		}
 		p.listMultipart(w, r, bck, q)
 		return
 	}
@@ -550,6 +564,10 @@ func (p *proxy) getObjS3(w http.ResponseWriter, r *http.Request, items []string,
 		s3.WriteErr(w, r, err, 0)
 		return
 	}
+	if err := p.accessObj(r.Header, bck, objName, apc.AceGET); err != nil {
+		s3.WriteErr(w, r, err, http.StatusForbidden)
+		return
+	}
 
 	smap := p.owner.smap.get()
 	si, netPub, err := smap.HrwMultiHome(bck.MakeUname(objName))
@@ -623,15 +641,15 @@ func (p *proxy) headObjS3(w http.ResponseWriter, r *http.Request, items []string
 	if bck == nil {
 		return
 	}
-	if err := p.access(r.Header, bck, apc.AceObjHEAD); err != nil {
-		s3.WriteErr(w, r, err, http.StatusForbidden)
-		return
-	}
 	objName := s3.ObjName(items)
 	if err := cmn.ValidOname(objName); err != nil {
 		s3.WriteErr(w, r, err, 0)
 		return
 	}
+	if err := p.accessObj(r.Header, bck, objName, apc.AceObjHEAD); err != nil {
+		s3.WriteErr(w, r, err, http.StatusForbidden)
+		return
+	}
 	smap := p.owner.smap.get()
 	si, err := smap.HrwName2T(bck.MakeUname(objName))
 	if err != nil {
@@ -655,15 +673,15 @@ func (p *proxy) delObjS3(w http.ResponseWriter, r *http.Request, items []string)
 	if bck == nil {
 		return
 	}
-	if err := p.access(r.Header, bck, apc.AceObjDELETE); err != nil {
-		s3.WriteErr(w, r, err, http.StatusForbidden)
-		return
-	}
 	objName := s3.ObjName(items)
 	if err := cmn.ValidOname(objName); err != nil {
 		s3.WriteErr(w, r, err, 0)
 		return
 	}
+	if err := p.accessObj(r.Header, bck, objName, apc.AceObjDELETE); err != nil {
+		s3.WriteErr(w, r, err, http.StatusForbidden)
+		return
+	}
 
 	smap := p.owner.smap.get()
 	si, err := smap.HrwName2T(bck.MakeUname(objName))
@@ -0,0 +1,244 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core/meta"
+)
+
+// Bucket snapshots (see space/snapshot):
+// - created, destroyed, and restored by all targets in parallel;
+// - node-local: restoring a snapshot taken prior to cluster membership changes is not supported;
+// - browsed via apc.ActBrowseSnapshot and GET(object) with apc.QparamSnapshot (read-only).
+
+// POST /v1/buckets/bucket-name (apc.ActCreateSnapshot, apc.ActDestroySnapshot, apc.ActRestoreSnapshot)
+func (p *proxy) snapshotBck(w http.ResponseWriter, r *http.Request, bck *meta.Bck, msg *apc.ActMsg) {
+	var smsg apc.SnapshotMsg
+	if err := cos.MorphMarshal(msg.Value, &smsg); err != nil {
+		p.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, p.si, msg.Action, msg.Value, err)
+		return
+	}
+	if err := smsg.Validate(); err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	perms := apc.AcePATCH
+	if msg.Action == apc.ActRestoreSnapshot {
+		perms = apc.AceAdmin
+	}
+	if err := p.checkAccess(w, r, bck, perms); err != nil {
+		return
+	}
+	if err := validateSnapBck(bck); err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+
+	var snap *cmn.SnapshotInfo
+	switch msg.Action {
+	case apc.ActCreateSnapshot:
+		snaps, err := p.bcastSnaps(bck, smsg.Name)
+		if err != nil {
+			p.writeErr(w, r, err)
+			return
+		}
+		if len(snaps) > 0 {
+			p.writeErr(w, r, fmt.Errorf("%s: snapshot %q of %s already exists", p, smsg.Name, bck.Cname("")),
+				http.StatusConflict)
+			return
+		}
+	case apc.ActRestoreSnapshot:
+		if bck.Props.ObjLock.Mode != "" {
+			p.writeErr(w, r, cmn.NewErrUnsupp("restore snapshot of", "object-locked bucket "+bck.Cname("")))
+			return
+		}
+		if smsg.Props && p.forwardCP(w, r, msg, bck.Name) {
+			return // (only the primary can update BMD)
+		}
+		snaps, err := p.bcastSnaps(bck, smsg.Name)
+		if err != nil {
+			p.writeErr(w, r, err)
+			return
+		}
+		if len(snaps) == 0 {
+			p.writeErr(w, r, cos.NewErrNotFound(p, "snapshot "+smsg.Name+" of "+bck.Cname("")), http.StatusNotFound)
+			return
+		}
+		snap = snaps[0]
+	}
+
+	notFound, err := p._bcastSnap(bck, msg.Action, &smsg)
+	switch {
+	case err != nil:
+		if msg.Action == apc.ActCreateSnapshot {
+			// best effort cleanup
+			if _, errV := p._bcastSnap(bck, apc.ActDestroySnapshot, &smsg); errV != nil {
+				nlog.Warningln(p.String(), "failed to cleanup snapshot", smsg.Name, "of", bck.Cname("")+":", errV)
+			}
+		}
+		p.writeErr(w, r, err)
+		return
+	case notFound:
+		p.writeErr(w, r, cos.NewErrNotFound(p, "snapshot "+smsg.Name+" of "+bck.Cname("")), http.StatusNotFound)
+		return
+	}
+
+	if msg.Action != apc.ActRestoreSnapshot {
+		return
+	}
+	p.qm.c.invalidate(bck.Bucket())
+	if smsg.Props && snap.Props != nil {
+		if err := p.restoreBprops(bck, snap.Props); err != nil {
+			p.writeErr(w, r, err)
+		}
+	}
+}
+
+// returns true when none of the targets has the snapshot (and the only errors are "not found")
+func (p *proxy) _bcastSnap(bck *meta.Bck, action string, smsg *apc.SnapshotMsg) (notFound bool, err error) {
+	args := allocBcArgs()
+	args.req = cmn.HreqArgs{
+		Method: http.MethodPost,
+		Path:   apc.URLPathBuckets.Join(bck.Name),
+		Query:  bck.NewQuery(),
+		Body:   cos.MustMarshal(p.newAmsgActVal(action, smsg)),
+	}
+	args.smap = p.owner.smap.get()
+	args.timeout = apc.LongTimeout
+	if cnt := args.smap.CountActiveTs(); cnt < 1 {
+		freeBcArgs(args)
+		return false, cmn.NewErrNoNodes(apc.Target, args.smap.CountTargets())
+	}
+	results := p.bcastGroup(args)
+	freeBcArgs(args)
+
+	var cnt int
+	for _, res := range results {
+		if res.err == nil {
+			continue
+		}
+		if res.status == http.StatusNotFound && action == apc.ActDestroySnapshot {
+			cnt++ // (e.g., target joined after the snapshot was taken)
+			continue
+		}
+		err = res.toErr()
+		break
+	}
+	notFound = err == nil && cnt == len(results)
+	freeBcastRes(results)
+	return notFound, err
+}
+
+// restore bucket props as of the time of snapshot, except identity and (excluded) EC and object lock
+func (p *proxy) restoreBprops(bck *meta.Bck, props *cmn.Bprops) error {
+	nprops := props.Clone()
+	{
+		cur := bck.Props
+		nprops.BackendBck, nprops.Provider, nprops.Renamed = cur.BackendBck, cur.Provider, cur.Renamed
+		nprops.BID, nprops.Created = cur.BID, cur.Created
+		nprops.EC, nprops.ObjLock = cur.EC, cur.ObjLock
+	}
+	if err := nprops.Validate(p.owner.smap.get().CountActiveTs()); err != nil && !cmn.IsErrWarning(err) {
+		return err
+	}
+	_, err := p.setBprops(&apc.ActMsg{Action: apc.ActSetBprops}, bck, nprops)
+	return err
+}
+
+// all bucket snapshots or the named one, aggregated across targets
+func (p *proxy) bcastSnaps(bck *meta.Bck, name string) (cmn.Snapshots, error) {
+	args := allocBcArgs()
+	args.req = cmn.HreqArgs{
+		Method: http.MethodGet,
+		Path:   apc.URLPathBuckets.Join(bck.Name),
+		Query:  bck.NewQuery(),
+		Body:   cos.MustMarshal(p.newAmsgActVal(apc.ActListSnapshots, &apc.SnapshotMsg{Name: name})),
+	}
+	args.smap = p.owner.smap.get()
+	if cnt := args.smap.CountActiveTs(); cnt < 1 {
+		freeBcArgs(args)
+		return nil, cmn.NewErrNoNodes(apc.Target, args.smap.CountTargets())
+	}
+	args.cresv = cresSnaps{} // -> cmn.Snapshots
+	results := p.bcastGroup(args)
+	freeBcArgs(args)
+
+	snaps := make(cmn.Snapshots, 0, 4)
+	for _, res := range results {
+		if res.err != nil {
+			err := res.toErr()
+			freeBcastRes(results)
+			return nil, err
+		}
+		for _, info := range *res.v.(*cmn.Snapshots) {
+			snaps = snaps.Add(info)
+		}
+	}
+	freeBcastRes(results)
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Name < snaps[j].Name })
+	return snaps, nil
+}
+
+// GET /v1/buckets/bucket-name (apc.ActListSnapshots, apc.ActBrowseSnapshot)
+func (p *proxy) listSnapshots(w http.ResponseWriter, r *http.Request, bck *meta.Bck, msg *apc.ActMsg) {
+	var smsg apc.SnapshotMsg
+	if err := cos.MorphMarshal(msg.Value, &smsg); err != nil {
+		p.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, p.si, msg.Action, msg.Value, err)
+		return
+	}
+	if msg.Action == apc.ActListSnapshots {
+		snaps, err := p.bcastSnaps(bck, smsg.Name)
+		if err != nil {
+			p.writeErr(w, r, err)
+			return
+		}
+		p.writeJSON(w, r, snaps, msg.Action)
+		return
+	}
+
+	// browse
+	if err := smsg.Validate(); err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	args := allocBcArgs()
+	args.req = cmn.HreqArgs{
+		Method: http.MethodGet,
+		Path:   apc.URLPathBuckets.Join(bck.Name),
+		Query:  bck.NewQuery(),
+		Body:   cos.MustMarshal(p.newAmsgActVal(apc.ActBrowseSnapshot, &smsg)),
+	}
+	args.smap = p.owner.smap.get()
+	if cnt := args.smap.CountActiveTs(); cnt < 1 {
+		freeBcArgs(args)
+		p.writeErr(w, r, cmn.NewErrNoNodes(apc.Target, args.smap.CountTargets()))
+		return
+	}
+	args.cresv = cresSnapO{} // -> cmn.LsoEntries
+	results := p.bcastGroup(args)
+	freeBcArgs(args)
+
+	entries := make(cmn.LsoEntries, 0, 64)
+	for _, res := range results {
+		if res.err != nil {
+			err := res.toErr()
+			freeBcastRes(results)
+			p.writeErr(w, r, err)
+			return
+		}
+		entries = append(entries, *res.v.(*cmn.LsoEntries)...)
+	}
+	freeBcastRes(results)
+	cmn.SortLso(entries)
+	p.writeJSON(w, r, entries, msg.Action)
+}
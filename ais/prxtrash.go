@@ -0,0 +1,61 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/core/meta"
+)
+
+// GET /v1/buckets/bucket-name (apc.ActListTrash)
+// (soft-deleted objects are listed by name and, within the same name, most recently deleted first)
+func (p *proxy) listTrash(w http.ResponseWriter, r *http.Request, bck *meta.Bck, msg *apc.ActMsg) {
+	var ltmsg apc.ListTrashMsg
+	if err := cos.MorphMarshal(msg.Value, &ltmsg); err != nil {
+		p.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, p.si, msg.Action, msg.Value, err)
+		return
+	}
+	args := allocBcArgs()
+	args.req = cmn.HreqArgs{
+		Method: http.MethodGet,
+		Path:   apc.URLPathBuckets.Join(bck.Name),
+		Query:  bck.NewQuery(),
+		Body:   cos.MustMarshal(p.newAmsgActVal(apc.ActListTrash, &ltmsg)),
+	}
+	args.smap = p.owner.smap.get()
+	if cnt := args.smap.CountActiveTs(); cnt < 1 {
+		freeBcArgs(args)
+		p.writeErr(w, r, cmn.NewErrNoNodes(apc.Target, args.smap.CountTargets()))
+		return
+	}
+	args.cresv = cresTrash{} // -> apc.TrashEntries
+	results := p.bcastGroup(args)
+	freeBcArgs(args)
+
+	entries := make(apc.TrashEntries, 0, 16)
+	for _, res := range results {
+		if res.err != nil {
+			err := res.toErr()
+			freeBcastRes(results)
+			p.writeErr(w, r, err)
+			return
+		}
+		entries = append(entries, *res.v.(*apc.TrashEntries)...)
+	}
+	freeBcastRes(results)
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].Deleted > entries[j].Deleted
+	})
+	p.writeJSON(w, r, entries, apc.ActListTrash)
+}
@@ -1119,6 +1119,10 @@ func (p *proxy) makeNewBckProps(bck *meta.Bck, propsToUpdate *cmn.BpropsToSet, c
 			nprops.EC.ParitySlices = 1
 		}
 	}
+	if bprops.ObjLock.Mode == apc.ObjLockCompliance && nprops.ObjLock.Mode != apc.ObjLockCompliance {
+		err = fmt.Errorf("%s: once enabled, compliance-mode object lock cannot be disabled or changed (%s)", p.si, bck)
+		return
+	}
 	if !bprops.Mirror.Enabled && nprops.Mirror.Enabled {
 		if nprops.Mirror.Copies == 1 {
 			nprops.Mirror.Copies = max(cfg.Mirror.Copies, 2)
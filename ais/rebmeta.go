@@ -36,6 +36,9 @@ import (
 //    2. rebalance must be started to redistribute the objects to the targets
 //       depending on HRW;
 // - when requested by user (`ais start rebalance` or REST API);
+// - when user sets per-target rebalance weights (apc.ActSetRebWeights) - the weights
+//   then stay with RMD (within its meta-version extension) and apply to object placement
+//   (HRW) on all nodes as soon as the latter receive the new RMD;
 // - upon target node powercycle (and more).
 
 type (
@@ -132,8 +135,17 @@ func (r *rmdOwner) load() {
 	}
 }
 
-func (r *rmdOwner) put(rmd *rebMD) { r.rmd.Store(rmd) }
-func (r *rmdOwner) get() *rebMD    { return r.rmd.Load() }
+func (r *rmdOwner) put(rmd *rebMD) {
+	r.rmd.Store(rmd)
+	weights, err := rmd.Weights()
+	if err != nil {
+		nlog.Errorln("failed to decode", rmd.String(), "weights:", err)
+		return
+	}
+	meta.InstallWeights(weights)
+}
+
+func (r *rmdOwner) get() *rebMD { return r.rmd.Load() }
 
 func (r *rmdOwner) synch(rmd *rebMD, locked bool) (err error) {
 	if !locked {
@@ -0,0 +1,210 @@
+// Package s3 provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"hash/crc32"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3 "additional" checksums of the multipart upload parts
+// (https://docs.aws.amazon.com/AmazonS3/latest/userguide/checking-object-integrity.html):
+// - the algorithm is specified at CreateMultipartUpload time and/or with each part;
+// - values are base64-encoded big-endian digests;
+// - the checksum of the completed object is the checksum of the concatenated (binary) part checksums,
+//   followed by "-<number of parts>" - same as the multipart ETag (see CompositeETag).
+
+const (
+	CksumCRC32  = "CRC32"
+	CksumCRC32C = "CRC32C"
+	CksumSHA1   = "SHA1"
+	CksumSHA256 = "SHA256"
+)
+
+// (computed when uploading a part)
+type PartCksum struct {
+	H        hash.Hash
+	Algo     string
+	expected string // as per request header; empty when not specified (or trailing)
+}
+
+// NewPartCksum returns nil when neither the request nor the upload (see InitUpload) specify
+// an additional checksum.
+func NewPartCksum(id string, hdr http.Header) (*PartCksum, error) {
+	var pc PartCksum
+	for _, algo := range []string{CksumCRC32, CksumCRC32C, CksumSHA1, CksumSHA256} {
+		if v := hdr.Get(cksumHdr(algo)); v != "" {
+			pc.Algo, pc.expected = algo, v
+			break
+		}
+	}
+	if pc.Algo == "" {
+		pc.Algo = strings.ToUpper(hdr.Get(cos.S3HdrSdkChecksumAlgo))
+	}
+	var algo string
+	mu.RLock()
+	if mpt, ok := ups[id]; ok {
+		algo = mpt.algo
+	}
+	mu.RUnlock()
+	switch {
+	case pc.Algo == "":
+		if algo == "" {
+			return nil, nil
+		}
+		pc.Algo = algo
+	case algo != "" && algo != pc.Algo:
+		return nil, NewErr("InvalidRequest", "upload %q: checksum algorithm %q differs from %q specified at creation",
+			id, pc.Algo, algo)
+	}
+	h, err := newCksumHash(pc.Algo)
+	if err != nil {
+		return nil, err
+	}
+	pc.H = h
+	return &pc, nil
+}
+
+// HdrName returns the (response) header that carries the checksum value
+func (pc *PartCksum) HdrName() string { return cksumHdr(pc.Algo) }
+
+// Finalize returns the part's checksum value or BadDigest error
+// when it does not match the one specified by the client.
+func (pc *PartCksum) Finalize(id string, partNum int32) (string, error) {
+	val := base64.StdEncoding.EncodeToString(pc.H.Sum(nil))
+	if pc.expected != "" && pc.expected != val {
+		return "", NewErr("BadDigest", "upload %q, part %d: %s checksum mismatch (%q vs %q)",
+			id, partNum, pc.Algo, pc.expected, val)
+	}
+	return val, nil
+}
+
+func ValidateCksumAlgo(algo string) error {
+	if algo == "" {
+		return nil
+	}
+	_, err := newCksumHash(algo)
+	return err
+}
+
+func newCksumHash(algo string) (hash.Hash, error) {
+	switch algo {
+	case CksumCRC32:
+		return crc32.NewIEEE(), nil
+	case CksumCRC32C:
+		return cos.NewCRC32C(), nil
+	case CksumSHA1:
+		return sha1.New(), nil
+	case CksumSHA256:
+		return sha256.New(), nil
+	default:
+		return nil, NewErr("InvalidRequest", "invalid checksum algorithm %q", algo)
+	}
+}
+
+func cksumHdr(algo string) string {
+	switch algo {
+	case CksumCRC32:
+		return cos.S3ChecksumCRC32
+	case CksumCRC32C:
+		return cos.S3ChecksumCRC32C
+	case CksumSHA1:
+		return cos.S3ChecksumSHA1
+	case CksumSHA256:
+		return cos.S3ChecksumSHA256
+	}
+	return ""
+}
+
+// (the one that's specified for the given algorithm)
+func completedCksum(part *types.CompletedPart, algo string) (v *string) {
+	switch algo {
+	case CksumCRC32:
+		v = part.ChecksumCRC32
+	case CksumCRC32C:
+		v = part.ChecksumCRC32C
+	case CksumSHA1:
+		v = part.ChecksumSHA1
+	case CksumSHA256:
+		v = part.ChecksumSHA256
+	}
+	return v
+}
+
+func setCompletedCksum(part *types.CompletedPart, algo, val string) {
+	if val == "" {
+		return
+	}
+	switch algo {
+	case CksumCRC32:
+		part.ChecksumCRC32 = &val
+	case CksumCRC32C:
+		part.ChecksumCRC32C = &val
+	case CksumSHA1:
+		part.ChecksumSHA1 = &val
+	case CksumSHA256:
+		part.ChecksumSHA256 = &val
+	}
+}
+
+// CompositeETag computes multipart ETag exactly like S3 does: MD5 of the concatenated binary
+// MD5 digests of the parts followed by "-<number of parts>" (quoted)
+func CompositeETag(nparts []*MptPart) (string, error) {
+	h := md5.New()
+	for _, part := range nparts {
+		b, err := hex.DecodeString(part.MD5)
+		if err != nil {
+			return "", NewErr("InvalidPart", "part %d: invalid MD5 %q: %v", part.Num, part.MD5, err)
+		}
+		h.Write(b)
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)) + cmn.AwsMultipartDelim + strconv.Itoa(len(nparts)) + `"`, nil
+}
+
+// CompositeCksum computes the checksum of the completed multipart upload (see above);
+// returns empty when the upload has no additional checksum
+func CompositeCksum(algo string, nparts []*MptPart) (string, error) {
+	if algo == "" {
+		return "", nil
+	}
+	h, err := newCksumHash(algo)
+	if err != nil {
+		return "", err
+	}
+	for _, part := range nparts {
+		b, err := base64.StdEncoding.DecodeString(part.Cksum)
+		if err != nil || part.Cksum == "" {
+			return "", NewErr("InvalidPart", "part %d: invalid or missing %s checksum %q", part.Num, algo, part.Cksum)
+		}
+		h.Write(b)
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)) + cmn.AwsMultipartDelim + strconv.Itoa(len(nparts)), nil
+}
+
+// SetCompositeCksum sets the corresponding field of the CompleteMultipartUpload response
+func (r *CompleteMptUploadResult) SetCompositeCksum(algo, val string) {
+	switch algo {
+	case CksumCRC32:
+		r.ChecksumCRC32 = val
+	case CksumCRC32C:
+		r.ChecksumCRC32C = val
+	case CksumSHA1:
+		r.ChecksumSHA1 = val
+	case CksumSHA256:
+		r.ChecksumSHA256 = val
+	}
+}
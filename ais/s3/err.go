@@ -33,6 +33,12 @@ func (e *Error) mustMarshal(sgl *memsys.SGL) {
 	debug.AssertNoErr(err)
 }
 
+// NewErr returns error with the specified S3 error code (e.g., "InvalidPart", "BadDigest")
+// to be reported by WriteErr (see below)
+func NewErr(code, format string, a ...any) error {
+	return fmt.Errorf(ErrPrefix+"["+code+": "+format+"]", a...)
+}
+
 // with user-friendly tip
 func WriteMptErr(w http.ResponseWriter, r *http.Request, err error, ecode int, lom *core.LOM, uploadID string) {
 	// specifically, for s3cmd example
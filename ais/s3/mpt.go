@@ -13,8 +13,8 @@ import (
 	"time"
 
 	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
-	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/cmn/nlog"
 	"github.com/NVIDIA/aistore/core"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
@@ -23,15 +23,18 @@ import (
 // NOTE: xattr stores only the (*) marked attributes
 type (
 	MptPart struct {
-		MD5  string // MD5 of the part (*)
-		FQN  string // FQN of the corresponding workfile
-		Size int64  // part size in bytes (*)
-		Num  int32  // part number (*)
+		MD5       string // MD5 of the part (*)
+		Cksum     string // additional checksum of the part, base64 (*) - see cksum.go
+		CksumAlgo string // additional checksum algorithm (stored once per upload - see mpt.algo)
+		FQN       string // FQN of the corresponding workfile
+		Size      int64  // part size in bytes (*)
+		Num       int32  // part number (*)
 	}
 	mpt struct {
 		ctime   time.Time // InitUpload time
 		bckName string
 		objName string
+		algo    string     // additional checksum algorithm, if any (*)
 		parts   []*MptPart // by part number
 	}
 	uploads map[string]*mpt // by upload ID
@@ -43,7 +46,8 @@ var (
 )
 
 // Start miltipart upload
-func InitUpload(id, bckName, objName string) {
+// (algo: additional checksum algorithm, if specified - see cksum.go)
+func InitUpload(id, bckName, objName, algo string) {
 	mu.Lock()
 	if ups == nil {
 		ups = make(uploads, 8)
@@ -51,6 +55,7 @@ func InitUpload(id, bckName, objName string) {
 	ups[id] = &mpt{
 		bckName: bckName,
 		objName: objName,
+		algo:    algo,
 		parts:   make([]*MptPart, 0, iniCapParts),
 		ctime:   time.Now(),
 	}
@@ -60,42 +65,82 @@ func InitUpload(id, bckName, objName string) {
 // Add part to an active upload.
 // Some clients may omit size and md5. Only partNum is must-have.
 // md5 and fqn is filled by a target after successful saving the data to a workfile.
-func AddPart(id string, npart *MptPart) (err error) {
+// Re-uploading a part with the same number replaces the previous one
+// (returned, so that the caller could remove its workfile).
+func AddPart(id string, npart *MptPart) (prev *MptPart, err error) {
 	mu.Lock()
 	mpt, ok := ups[id]
-	if !ok {
+	switch {
+	case !ok:
 		err = fmt.Errorf("upload %q not found (%s, %d)", id, npart.FQN, npart.Num)
-	} else {
-		mpt.parts = append(mpt.parts, npart)
+	default:
+		for i, part := range mpt.parts {
+			if part.Num == npart.Num {
+				prev, mpt.parts[i] = part, npart
+				break
+			}
+		}
+		if prev == nil {
+			mpt.parts = append(mpt.parts, npart)
+		}
 	}
 	mu.Unlock()
-	return
+	return prev, err
 }
 
-// TODO: compare non-zero sizes (note: s3cmd sends 0) and part.ETag as well, if specified
-func CheckParts(id string, parts []types.CompletedPart) ([]*MptPart, error) {
-	mu.RLock()
-	defer mu.RUnlock()
+// Check that all the specified (completed) parts are present and, if specified,
+// their ETags and additional checksums match the uploaded ones.
+// Returns the parts in the specified order, and the upload's checksum algorithm.
+// TODO: compare non-zero sizes (note: s3cmd sends 0)
+func CheckParts(id string, parts []types.CompletedPart) ([]*MptPart, string, error) {
+	mu.Lock()
+	defer mu.Unlock()
 	mpt, ok := ups[id]
 	if !ok {
-		return nil, fmt.Errorf("upload %q not found", id)
-	}
-	// first, check that all parts are present
-	var prev = int32(-1)
-	for _, part := range parts {
+		return nil, "", NewErr("NoSuchUpload", "upload %q not found", id)
+	}
+	var (
+		prev   = int32(-1)
+		nparts = make([]*MptPart, 0, len(parts))
+		algo   = mpt.algo
+	)
+	if algo == "" && len(mpt.parts) > 0 {
+		// not specified at InitUpload time - use the one specified with the parts
+		algo = mpt.parts[0].CksumAlgo
+	}
+	for i := range parts {
+		part := &parts[i]
+		if part.PartNumber == nil {
+			return nil, "", NewErr("InvalidPart", "upload %q: missing part number", id)
+		}
 		curr := *part.PartNumber
-		debug.Assert(curr > prev) // must ascend
-		if mpt.getPart(curr) == nil {
-			return nil, fmt.Errorf("upload %q: part %d not found", id, curr)
+		if curr <= prev {
+			return nil, "", NewErr("InvalidPartOrder", "upload %q: part numbers must ascend (%d, %d)", id, prev, curr)
+		}
+		mp := mpt.getPart(curr)
+		if mp == nil {
+			return nil, "", NewErr("InvalidPart", "upload %q: part %d not found", id, curr)
 		}
+		if part.ETag != nil && *part.ETag != "" {
+			if etag := cmn.UnquoteCEV(*part.ETag); etag != mp.MD5 {
+				return nil, "", NewErr("InvalidPart", "upload %q: part %d ETag mismatch (%q vs %q)", id, curr, etag, mp.MD5)
+			}
+		}
+		if algo != "" {
+			if mp.CksumAlgo != algo {
+				return nil, "", NewErr("InvalidPart", "upload %q: part %d checksum algorithm %q differs from %q",
+					id, curr, mp.CksumAlgo, algo)
+			}
+			if v := completedCksum(part, algo); v != nil && *v != mp.Cksum {
+				return nil, "", NewErr("BadDigest", "upload %q: part %d %s checksum mismatch (%q vs %q)",
+					id, curr, algo, *v, mp.Cksum)
+			}
+		}
+		nparts = append(nparts, mp)
 		prev = curr
 	}
-	// copy (to work on it with no locks)
-	nparts := make([]*MptPart, 0, len(parts))
-	for _, part := range parts {
-		nparts = append(nparts, mpt.getPart(*part.PartNumber))
-	}
-	return nparts, nil
+	mpt.algo = algo // (to store - see CleanupUpload)
+	return nparts, algo, nil
 }
 
 func ParsePartNum(s string) (int32, error) {
@@ -194,10 +239,12 @@ func ListParts(id string, lom *core.LOM) (parts []types.CompletedPart, ecode int
 	}
 	parts = make([]types.CompletedPart, 0, len(mpt.parts))
 	for _, part := range mpt.parts {
-		parts = append(parts, types.CompletedPart{
+		cp := types.CompletedPart{
 			ETag:       apc.Ptr(part.MD5),
 			PartNumber: apc.Ptr(part.Num),
-		})
+		}
+		setCompletedCksum(&cp, mpt.algo, part.Cksum)
+		parts = append(parts, cp)
 	}
 	mu.RUnlock()
 	return parts, ecode, err
@@ -156,6 +156,8 @@ func (pts *PresignedReq) DoReader(client *http.Client) (*PresignedResp, error) {
 	// S3 checks every single query param
 	pts.query.Del(apc.QparamProxyID)
 	pts.query.Del(apc.QparamUnixTime)
+	pts.query.Del(apc.QparamQuotaObjs)
+	pts.query.Del(apc.QparamQuotaSize)
 	queryEncoded := pts.query.Encode()
 
 	signedRequestStyle := pts.oreq.Header.Get(apc.HdrSignedRequestStyle)
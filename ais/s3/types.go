@@ -17,6 +17,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/fs/sse"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
@@ -69,9 +70,13 @@ type (
 
 	// Multipart upload completion response
 	CompleteMptUploadResult struct {
-		Bucket string `xml:"Bucket"`
-		Key    string `xml:"Key"`
-		ETag   string `xml:"ETag"`
+		Bucket         string `xml:"Bucket"`
+		Key            string `xml:"Key"`
+		ETag           string `xml:"ETag"`
+		ChecksumCRC32  string `xml:"ChecksumCRC32,omitempty"`
+		ChecksumCRC32C string `xml:"ChecksumCRC32C,omitempty"`
+		ChecksumSHA1   string `xml:"ChecksumSHA1,omitempty"`
+		ChecksumSHA256 string `xml:"ChecksumSHA256,omitempty"`
 	}
 
 	// Multipart uploaded parts response
@@ -211,6 +216,11 @@ func SetS3Headers(hdr http.Header, lom *core.LOM) {
 			hdr.Set(cos.S3VersionHeader, v)
 		}
 	}
+
+	// encrypted with client-supplied key
+	if sse.IsCustomer(lom.SSERef()) {
+		hdr.Set(cos.S3HdrSSECustomerAlgo, sse.CustomerAlgo)
+	}
 }
 
 func (r *CopyObjectResult) MustMarshal(sgl *memsys.SGL) {
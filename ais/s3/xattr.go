@@ -71,27 +71,45 @@ func (mpt *mpt) _offSorted(name string, num int32) (off, size int64, err error)
 	return 0, 0, fmt.Errorf("invalid part number %d (%s has %d)", num, name, prev)
 }
 
+// packed format:
+// - v1 (legacy): sequence of (num, md5, size) - one per part;
+// - v2: (0, version, algo) header followed by (num, md5, size, cksum) parts;
+// distinguishable since part numbers are never zero
+const (
+	mptPackMarker = int32(0)
+	mptPackV2     = "v2"
+)
+
 func (mpt *mpt) packedSize() (size int) {
+	size = cos.SizeofI32 + cos.SizeofLen + len(mptPackV2) + cos.SizeofLen + len(mpt.algo)
 	for _, part := range mpt.parts {
-		size += cos.SizeofI64 // num
+		size += cos.SizeofI32 // num
 		size += cos.SizeofLen + len(part.MD5)
 		size += cos.SizeofI64 // part.Size
+		size += cos.SizeofLen + len(part.Cksum)
 	}
 	return
 }
 
 func (mpt *mpt) pack() []byte {
 	packer := cos.NewPacker(nil, mpt.packedSize())
+	packer.WriteInt32(mptPackMarker)
+	packer.WriteString(mptPackV2)
+	packer.WriteString(mpt.algo)
 	for _, part := range mpt.parts {
 		packer.WriteInt32(part.Num)
 		packer.WriteString(part.MD5)
 		packer.WriteInt64(part.Size)
+		packer.WriteString(part.Cksum)
 	}
 	return packer.Bytes()
 }
 
 func (mpt *mpt) unpack(b []byte) (err error) {
-	unpacker := cos.NewUnpacker(b)
+	var (
+		unpacker = cos.NewUnpacker(b)
+		v2       bool
+	)
 	debug.Assert(mpt.parts == nil)
 	mpt.parts = make([]*MptPart, 0, iniCapParts)
 	for unpacker.Len() > 0 {
@@ -99,12 +117,32 @@ func (mpt *mpt) unpack(b []byte) (err error) {
 		if part.Num, err = unpacker.ReadInt32(); err != nil {
 			break
 		}
+		if part.Num == mptPackMarker && !v2 && len(mpt.parts) == 0 {
+			var ver string
+			if ver, err = unpacker.ReadString(); err != nil {
+				break
+			}
+			if ver != mptPackV2 {
+				return fmt.Errorf("unknown multipart state version %q", ver)
+			}
+			if mpt.algo, err = unpacker.ReadString(); err != nil {
+				break
+			}
+			v2 = true
+			continue
+		}
 		if part.MD5, err = unpacker.ReadString(); err != nil {
 			break
 		}
 		if part.Size, err = unpacker.ReadInt64(); err != nil {
 			break
 		}
+		if v2 {
+			if part.Cksum, err = unpacker.ReadString(); err != nil {
+				break
+			}
+			part.CksumAlgo = mpt.algo
+		}
 		mpt.parts = append(mpt.parts, part)
 	}
 	return
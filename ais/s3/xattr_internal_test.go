@@ -5,24 +5,35 @@
 package s3
 
 import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"hash/crc32"
 	"testing"
 
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/tools/trand"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 func TestPackUnpack(t *testing.T) {
 	const nump = 10
 	var (
-		in  = mpt{parts: make([]*MptPart, 0)}
+		in  = mpt{algo: CksumCRC32C, parts: make([]*MptPart, 0)}
 		out = &mpt{}
 	)
 	for i := range nump {
-		in.parts = append(in.parts, &MptPart{Num: int32(111 + i*i), MD5: trand.String(8), Size: 1024 + int64(i)})
+		in.parts = append(in.parts, &MptPart{Num: int32(111 + i*i), MD5: trand.String(8), Size: 1024 + int64(i),
+			Cksum: trand.String(6), CksumAlgo: CksumCRC32C})
 	}
 	b := in.pack()
 	if err := out.unpack(b); err != nil {
 		t.Fatal(err)
 	}
+	if out.algo != in.algo {
+		t.Fatalf("algo: in %q != out %q", in.algo, out.algo)
+	}
 	if len(in.parts) != len(out.parts) {
 		t.Fatalf("in != out: %d, %d", len(in.parts), len(out.parts))
 	}
@@ -32,3 +43,111 @@ func TestPackUnpack(t *testing.T) {
 		}
 	}
 }
+
+// multipart state stored prior to v2
+func TestUnpackV1(t *testing.T) {
+	const nump = 5
+	var (
+		packer = cos.NewPacker(nil, 1024)
+		out    = &mpt{}
+	)
+	for i := range nump {
+		packer.WriteInt32(int32(i + 1))
+		packer.WriteString("md5-" + string(rune('a'+i)))
+		packer.WriteInt64(int64(1000 + i))
+	}
+	if err := out.unpack(packer.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.parts) != nump || out.algo != "" {
+		t.Fatalf("expected %d parts (no checksum), got %d (%q)", nump, len(out.parts), out.algo)
+	}
+	for i, part := range out.parts {
+		if part.Num != int32(i+1) || part.MD5 != "md5-"+string(rune('a'+i)) || part.Size != int64(1000+i) || part.Cksum != "" {
+			t.Fatalf("unexpected part %+v", *part)
+		}
+	}
+}
+
+func TestCompositeETagCksum(t *testing.T) {
+	var (
+		data   = [][]byte{[]byte(trand.String(100)), []byte(trand.String(33)), []byte(trand.String(1))}
+		nparts = make([]*MptPart, 0, len(data))
+		md5s   []byte
+		crcs   []byte
+	)
+	for i, b := range data {
+		m := md5.Sum(b)
+		c := crc32.NewIEEE()
+		c.Write(b)
+		sum := c.Sum(nil)
+		md5s = append(md5s, m[:]...)
+		crcs = append(crcs, sum...)
+		nparts = append(nparts, &MptPart{Num: int32(i + 1), MD5: hex.EncodeToString(m[:]),
+			Cksum: base64.StdEncoding.EncodeToString(sum), CksumAlgo: CksumCRC32})
+	}
+
+	etag, err := CompositeETag(nparts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := md5.Sum(md5s)
+	if expected := `"` + hex.EncodeToString(m[:]) + `-3"`; etag != expected {
+		t.Fatalf("ETag: expected %s, got %s", expected, etag)
+	}
+
+	cksum, err := CompositeCksum(CksumCRC32, nparts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := crc32.NewIEEE()
+	c.Write(crcs)
+	if expected := base64.StdEncoding.EncodeToString(c.Sum(nil)) + "-3"; cksum != expected {
+		t.Fatalf("checksum: expected %s, got %s", expected, cksum)
+	}
+}
+
+func TestCheckParts(t *testing.T) {
+	const id = "test-upload-id"
+	InitUpload(id, "bck", "obj", CksumSHA256)
+	defer CleanupUpload(id, "", true /*aborted*/)
+
+	for i := range 3 {
+		part := &MptPart{Num: int32(i + 1), MD5: "md5", Cksum: "cksum", CksumAlgo: CksumSHA256, FQN: "/none"}
+		if _, err := AddPart(id, part); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// re-upload
+	prev, err := AddPart(id, &MptPart{Num: 2, MD5: "md5-2", Cksum: "cksum-2", CksumAlgo: CksumSHA256, FQN: "/none"})
+	if err != nil || prev == nil || prev.MD5 != "md5" {
+		t.Fatalf("expected part 2 to be replaced: %v, %v", prev, err)
+	}
+
+	parts := []types.CompletedPart{
+		{PartNumber: apc.Ptr(int32(1)), ETag: apc.Ptr(`"md5"`)},
+		{PartNumber: apc.Ptr(int32(2)), ETag: apc.Ptr("md5-2"), ChecksumSHA256: apc.Ptr("cksum-2")},
+		{PartNumber: apc.Ptr(int32(3))},
+	}
+	nparts, algo, err := CheckParts(id, parts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nparts) != 3 || algo != CksumSHA256 || nparts[1].MD5 != "md5-2" {
+		t.Fatalf("unexpected: %d parts, algo %q", len(nparts), algo)
+	}
+
+	parts[1].ETag = apc.Ptr("md5")
+	if _, _, err := CheckParts(id, parts); err == nil {
+		t.Fatal("expected ETag mismatch")
+	}
+	parts[1].ETag, parts[1].ChecksumSHA256 = nil, apc.Ptr("cksum")
+	if _, _, err := CheckParts(id, parts); err == nil {
+		t.Fatal("expected checksum mismatch")
+	}
+	parts[0], parts[1] = parts[1], parts[0]
+	parts[0].ChecksumSHA256 = nil
+	if _, _, err := CheckParts(id, parts); err == nil {
+		t.Fatal("expected out-of-order error")
+	}
+}
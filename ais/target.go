@@ -38,6 +38,8 @@ import (
 	"github.com/NVIDIA/aistore/ext/etl"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/fs/health"
+	"github.com/NVIDIA/aistore/fs/sse"
+	"github.com/NVIDIA/aistore/hk"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/mirror"
 	"github.com/NVIDIA/aistore/reb"
@@ -86,6 +88,8 @@ var (
 	_ htext       = (*target)(nil)
 	_ core.Target = (*target)(nil)
 	_ fs.HC       = (*target)(nil)
+	_ xs.COI      = (*target)(nil)
+	_ xs.ECR      = (*target)(nil)
 )
 
 func (*target) Name() string { return apc.Target } // as cos.Runner
@@ -138,6 +142,14 @@ func (t *target) initBuiltTagged(tstats *stats.Trunner, config *cmn.Config, star
 			add, err = backend.NewAzure(t, tstats, startingUp)
 		case apc.OCI:
 			add, err = backend.NewOCI(t, tstats, startingUp)
+		case apc.B2:
+			add, err = backend.NewB2(t, tstats, startingUp)
+		case apc.WebDAV:
+			add, err = backend.NewWebDAV(t, tstats, startingUp)
+		case apc.FTP:
+			add, err = backend.NewFTP(t, tstats, startingUp)
+		case apc.POSIX:
+			add, err = backend.NewPOSIX(t, tstats, startingUp)
 		case apc.HT:
 			add, err = backend.NewHT(t, config, tstats, startingUp)
 		case apc.AIS:
@@ -250,6 +262,11 @@ func (t *target) init(config *cmn.Config) {
 	if err := ts.InitCDF(config); err != nil {
 		cos.ExitLog(err)
 	}
+
+	// server-side encryption at rest (optional)
+	if err := sse.Init(); err != nil {
+		cos.ExitLog(err)
+	}
 }
 
 func (t *target) initHostIP(config *cmn.Config) {
@@ -334,9 +351,14 @@ func (t *target) Run() error {
 		nlog.Errorln("")
 	}
 
-	// register object type and workfile type
+	// (before joining - see t.xorphans)
+	xreg.LoadJournal(config.ConfigDir)
+
+	// register object type, workfile type, trash (soft-deleted objects), and bucket snapshots
 	fs.CSM.Reg(fs.ObjectType, &fs.ObjectContentResolver{})
 	fs.CSM.Reg(fs.WorkfileType, &fs.WorkfileContentResolver{})
+	fs.CSM.Reg(fs.TrashType, &fs.TrashContentResolver{})
+	fs.CSM.Reg(fs.SnapType, &fs.SnapContentResolver{})
 
 	// Init meta-owners and load local instances
 	if prev := t.owner.bmd.init(); prev {
@@ -403,6 +425,12 @@ func (t *target) Run() error {
 	mirror.Init()
 
 	xreg.RegWithHK()
+	xreg.RegSched(t.schedStart)
+	hk.Reg(apc.ActExpireObjs+hk.NameSuffix, t.expireHK, minAutoDetectInterval)
+	hk.Reg(apc.ActQuotaUsage+hk.NameSuffix, t.quotaHK, minAutoDetectInterval)
+	hk.Reg("trash"+hk.NameSuffix, t.trashHK, minAutoDetectInterval)
+	hk.Reg(apc.ActReplicate+hk.NameSuffix, t.replHK, replResyncIval)
+	xreg.RegJournal()
 
 	marked := xreg.GetResilverMarked()
 	if marked.Interrupted || daemon.resilver.required {
@@ -441,6 +469,7 @@ func (t *target) gojoin(config *cmn.Config) {
 		}
 	}
 	t.markClusterStarted()
+	t.xorphans()
 
 	if t.fsprg.newVol && !config.TestingEnv() {
 		config := cmn.GCO.BeginUpdate()
@@ -496,7 +525,7 @@ func (t *target) endStartupStandby() (err error) {
 func (t *target) initRecvHandlers() {
 	networkHandlers := []networkHandler{
 		{r: apc.Buckets, h: t.bucketHandler, net: accessNetAll},
-		{r: apc.Objects, h: t.objectHandler, net: accessNetAll},
+		{r: apc.Objects, h: t.objectHandler, net: accessNetAll, audit: true},
 		{r: apc.Daemon, h: t.daemonHandler, net: accessNetPublicControl},
 		{r: apc.Metasync, h: t.metasyncHandler, net: accessNetIntraControl},
 		{r: apc.Health, h: t.healthHandler, net: accessNetPublicControl},
@@ -510,7 +539,7 @@ func (t *target) initRecvHandlers() {
 		{r: apc.Sort, h: dsort.TargetHandler, net: accessControlData},
 		{r: apc.ETL, h: t.etlHandler, net: accessNetAll},
 
-		{r: "/" + apc.S3, h: t.s3Handler, net: accessNetPublicData},
+		{r: "/" + apc.S3, h: t.s3Handler, net: accessNetPublicData, audit: true},
 		{r: "/", h: t.errURL, net: accessNetAll},
 	}
 	t.regNetHandlers(networkHandlers)
@@ -678,6 +707,12 @@ func (t *target) httpobjget(w http.ResponseWriter, r *http.Request, apireq *apiR
 		t.writeErr(w, r, err)
 		return
 	}
+	if apireq.dpq.presign.sig != "" {
+		if err := verifyPresigned(authSecret(cmn.GCO.Get()), r.Method, apireq.bck.Bucket(), apireq.items[1], r.URL.Query(), true); err != nil {
+			t.writeErr(w, r, err, http.StatusForbidden)
+			return
+		}
+	}
 	if cmn.Rom.Features().IsSet(feat.EnforceIntraClusterAccess) {
 		if apireq.dpq.ptime == "" /*isRedirect*/ && t.checkIntraCall(r.Header, false /*from primary*/) != nil {
 			t.writeErrf(w, r, "%s: %s(obj) is expected to be redirected (remaddr=%s)",
@@ -705,11 +740,14 @@ func (t *target) getObject(w http.ResponseWriter, r *http.Request, dpq *dpq, bck
 		}
 	}
 
-	// two special flows
+	// special flows
 	if dpq.etlName != "" {
 		t.getETL(w, r, dpq.etlName, lom)
 		return lom, nil
 	}
+	if dpq.snapshot != "" {
+		return lom, t.getSnapObj(w, lom, dpq.snapshot)
+	}
 	if cos.IsParseBool(r.Header.Get(apc.HdrBlobDownload)) {
 		var msg apc.BlobMsg
 		if err := msg.FromHeader(r.Header); err != nil {
@@ -731,9 +769,16 @@ func (t *target) getObject(w http.ResponseWriter, r *http.Request, dpq *dpq, bck
 		return lom, err
 	}
 
+	// client-supplied encryption key (if any)
+	ssec, err := parseSSEC(r)
+	if err != nil {
+		return lom, err
+	}
+
 	// GET: regular | archive | range
 	goi := allocGOI()
 	{
+		goi.ssec = ssec
 		goi.atime = time.Now().UnixNano()
 		goi.ltime = mono.NanoTime()
 		if dpq.ptime != "" {
@@ -832,6 +877,12 @@ func (t *target) httpobjput(w http.ResponseWriter, r *http.Request, apireq *apiR
 		t.writeErrf(w, r, "%s: %s(obj) is expected to be redirected or replicated", t.si, r.Method)
 		return
 	}
+	if apireq.dpq.presign.sig != "" {
+		if err := verifyPresigned(authSecret(config), r.Method, apireq.bck.Bucket(), lom.ObjName, r.URL.Query(), true); err != nil {
+			t.writeErr(w, r, err, http.StatusForbidden)
+			return
+		}
+	}
 	cs := fs.Cap()
 	if errCap := cs.Err(); errCap != nil || cs.PctMax > int32(config.Space.CleanupWM) {
 		cs = t.oos(config)
@@ -855,9 +906,18 @@ func (t *target) httpobjput(w http.ResponseWriter, r *http.Request, apireq *apiR
 	}
 
 	// load (maybe)
+	var prevSize int64 = -1 // (quota usage: unknown or new object)
 	skipVC := lom.IsFeatureSet(feat.SkipVC) || apireq.dpq.skipVC
 	if !skipVC {
-		_ = lom.Load(true, false)
+		if lom.Load(true, false) == nil {
+			prevSize = lom.Lsize()
+		}
+	}
+
+	// object lock (WORM), including intra-cluster copies (see coi.put)
+	if err := checkOverwrite(lom); err != nil {
+		t.writeErr(w, r, err, http.StatusForbidden)
+		return
 	}
 
 	// do
@@ -868,7 +928,16 @@ func (t *target) httpobjput(w http.ResponseWriter, r *http.Request, apireq *apiR
 	)
 	switch {
 	case apireq.dpq.arch.path != "": // apc.QparamArchpath
-		apireq.dpq.arch.mime, err = archive.MimeFQN(t.smm, apireq.dpq.arch.mime, lom.FQN)
+		if lom.SSERef() == "" {
+			apireq.dpq.arch.mime, err = archive.MimeFQN(t.smm, apireq.dpq.arch.mime, lom.FQN)
+		} else {
+			// encrypted at rest: detect (decrypted) file magic
+			var lmfh cos.LomReader
+			if lmfh, err = lom.Open(); err == nil {
+				apireq.dpq.arch.mime, err = archive.MimeFile(lmfh, t.smm, apireq.dpq.arch.mime, lom.ObjName)
+				cos.Close(lmfh)
+			}
+		}
 		if err != nil {
 			break
 		}
@@ -883,6 +952,7 @@ func (t *target) httpobjput(w http.ResponseWriter, r *http.Request, apireq *apiR
 			config:  config,
 			lom:     lom,
 			r:       r.Body,
+			resphdr: w.Header(),
 			op:      apireq.dpq.apnd.ty, // apc.QparamAppendType
 		}
 		if err := a.parse(apireq.dpq.apnd.hdl /*apc.QparamAppendHandle*/); err != nil {
@@ -890,8 +960,13 @@ func (t *target) httpobjput(w http.ResponseWriter, r *http.Request, apireq *apiR
 			return
 		}
 		handle, ecode, err = a.do(r)
-		if err == nil && handle != "" {
-			w.Header().Set(apc.HdrAppendHandle, handle)
+		if err == nil {
+			if handle != "" {
+				w.Header().Set(apc.HdrAppendHandle, handle)
+			}
+			if a.op != apc.AppendOp { // (not until flushed)
+				t.quotaPut(lom, prevSize)
+			}
 			return
 		}
 		vlabs := map[string]string{stats.VarlabBucket: lom.Bck().Cname("")}
@@ -920,6 +995,23 @@ func (t *target) httpobjput(w http.ResponseWriter, r *http.Request, apireq *apiR
 	if err != nil {
 		t.FSHC(err, lom.Mountpath(), "") // TODO -- FIXME: removed from the place where happened, fqn missing...
 		t.writeErr(w, r, err, ecode)
+		return
+	}
+	if !t2tput {
+		t.quotaPut(lom, prevSize)
+	}
+}
+
+// update bucket and per-user usage (see xs.QuotaAdd); prevSize < 0 when the object did not exist
+// (or was not loaded, in which case the usage gets corrected by the next apc.ActQuotaUsage)
+func (t *target) quotaPut(lom *core.LOM, prevSize int64) {
+	if err := lom.Load(false /*cache it*/, false /*locked*/); err != nil {
+		return
+	}
+	if prevSize < 0 {
+		xs.QuotaAdd(lom, lom.Lsize(), 1)
+	} else {
+		xs.QuotaAdd(lom, lom.Lsize()-prevSize, 0)
 	}
 }
 
@@ -946,7 +1038,8 @@ func (t *target) httpobjdelete(w http.ResponseWriter, r *http.Request, apireq *a
 		return
 	}
 
-	ecode, err := t.DeleteObject(lom, evict)
+	bypass := cos.IsParseBool(apireq.query.Get(apc.QparamBypassGovernance))
+	ecode, err := t.deleteObject(lom, evict, bypass)
 	if err == nil && ecode == 0 {
 		// EC cleanup if EC is enabled
 		ec.ECM.CleanupObject(lom)
@@ -993,6 +1086,28 @@ func (t *target) httpobjpost(w http.ResponseWriter, r *http.Request, apireq *api
 				cos.NamedVal64{Name: stats.ErrRenameCount, Value: 1, VarLabs: vlabs},
 			)
 		}
+	case apc.ActExtendRetain:
+		var ecode int
+		lom = core.AllocLOM(apireq.items[1])
+		if err = lom.InitBck(apireq.bck.Bucket()); err != nil {
+			break
+		}
+		if ecode, err = t.extendRetention(lom, msg); err != nil {
+			t.writeErr(w, r, err, ecode)
+		}
+		core.FreeLOM(lom)
+		return
+	case apc.ActUndelete:
+		var ecode int
+		lom = core.AllocLOM(apireq.items[1])
+		if err = lom.InitBck(apireq.bck.Bucket()); err != nil {
+			break
+		}
+		if ecode, err = t.undelete(lom, msg); err != nil {
+			t.writeErr(w, r, err, ecode)
+		}
+		core.FreeLOM(lom)
+		return
 	case apc.ActBlobDl:
 		// TODO: add stats.GetBlobCount and *ErrCount
 		var (
@@ -1097,6 +1212,9 @@ func (t *target) objHead(r *http.Request, whdr http.Header, q url.Values, bck *m
 	// props
 	op := cmn.ObjectProps{Name: lom.ObjName, Bck: *lom.Bucket(), Present: exists}
 	if exists {
+		if ecode, err = headSSEC(r, whdr, lom, false /*S3*/); err != nil {
+			return ecode, err
+		}
 		op.ObjAttrs = *lom.ObjAttrs()
 		op.Location = lom.Location()
 		op.Mirror.Copies = lom.NumCopies()
@@ -1212,16 +1330,41 @@ func (t *target) httpobjpatch(w http.ResponseWriter, r *http.Request, apireq *ap
 		t.writeErr(w, r, err)
 		return
 	}
+	tier, setTier := custom[cmn.AccessTierObjMD]
+	setTier = setTier && lom.Bck().IsRemote()
 	if err := lom.Load(true /*cache it*/, false /*locked*/); err != nil {
-		if cos.IsNotExist(err, 0) {
-			t.writeErr(w, r, err, http.StatusNotFound)
-		} else {
+		switch {
+		case !cos.IsNotExist(err, 0):
 			t.writeErr(w, r, err)
+		case setTier && len(custom) == 1:
+			// remote object that is not present in the cluster: change its tier, nothing else
+			if ecode, err := t.setObjTier(lom, tier); err != nil {
+				t.writeErr(w, r, err, ecode)
+			}
+		default:
+			t.writeErr(w, r, err, http.StatusNotFound)
 		}
 		return
 	}
+	if _, ok := custom[cmn.OwnerObjMD]; ok {
+		t.writeErr(w, r, errReservedCustomKey(cmn.OwnerObjMD))
+		return
+	}
 	delOldSetNew := cos.IsParseBool(apireq.query.Get(apc.QparamNewCustom))
+	if ecode, err := checkPatchRetention(lom, custom, delOldSetNew); err != nil {
+		t.writeErr(w, r, err, ecode)
+		return
+	}
+	if setTier {
+		if ecode, err := t.setObjTier(lom, tier); err != nil {
+			t.writeErr(w, r, err, ecode)
+			return
+		}
+	}
 	if delOldSetNew {
+		if owner, ok := lom.GetCustomKey(cmn.OwnerObjMD); ok {
+			custom[cmn.OwnerObjMD] = owner // (keep)
+		}
 		lom.SetCustomMD(custom)
 	} else {
 		for key, val := range custom {
@@ -1231,6 +1374,15 @@ func (t *target) httpobjpatch(w http.ResponseWriter, r *http.Request, apireq *ap
 	lom.Persist()
 }
 
+// (see cmn.AccessTierObjMD)
+func (t *target) setObjTier(lom *core.LOM, tier string) (int, error) {
+	tb, ok := t.Backend(lom.Bck()).(core.TierBackend)
+	if !ok {
+		return http.StatusBadRequest, cmn.NewErrUnsupp("set access tier of", lom.Cname())
+	}
+	return tb.SetObjTier(lom, tier)
+}
+
 // called under lock
 func (t *target) putApndArch(r *http.Request, lom *core.LOM, started int64, dpq *dpq) (int, error) {
 	var (
@@ -1282,10 +1434,14 @@ func (t *target) putApndArch(r *http.Request, lom *core.LOM, started int64, dpq
 	return a.do()
 }
 
-func (t *target) DeleteObject(lom *core.LOM, evict bool) (code int, err error) {
+func (t *target) DeleteObject(lom *core.LOM, evict bool) (int, error) {
+	return t.deleteObject(lom, evict, false /*bypass governance*/)
+}
+
+func (t *target) deleteObject(lom *core.LOM, evict, bypass bool) (code int, err error) {
 	var isback bool
 	lom.Lock(true)
-	code, err, isback = t.delobj(lom, evict)
+	code, err, isback = t.delobj(lom, evict, bypass)
 	lom.Unlock(true)
 
 	// special corner-case retry (quote):
@@ -1306,7 +1462,7 @@ func (t *target) DeleteObject(lom *core.LOM, evict bool) (code int, err error) {
 		t.statsT.AddWith(
 			cos.NamedVal64{Name: stats.DeleteCount, Value: 1, VarLabs: vlabs},
 		)
-	case cos.IsNotExist(err, code) || cmn.IsErrObjNought(err):
+	case cos.IsNotExist(err, code) || cmn.IsErrObjNought(err) || cmn.IsErrObjLocked(err):
 		if !evict {
 			t.statsT.AddWith(
 				cos.NamedVal64{Name: stats.ErrDeleteCount, Value: 1, VarLabs: vlabs},
@@ -1330,7 +1486,7 @@ func (t *target) DeleteObject(lom *core.LOM, evict bool) (code int, err error) {
 }
 
 // NOTE: s3 will return err=nil with OK status to indicate (not deleting) non-existing object (see also aws.go)
-func (t *target) delobj(lom *core.LOM, evict bool) (int, error, bool) {
+func (t *target) delobj(lom *core.LOM, evict, bypass bool) (int, error, bool) {
 	var (
 		aisErr, backendErr         error
 		aisErrCode, backendErrCode int
@@ -1353,6 +1509,12 @@ func (t *target) delobj(lom *core.LOM, evict bool) (int, error, bool) {
 			return http.StatusNotFound, err, false
 		}
 	} else {
+		// object lock (WORM): eviction removes in-cluster copy only
+		if !evict {
+			if err := checkRetention(lom, bypass); err != nil {
+				return http.StatusForbidden, err, false
+			}
+		}
 		delFromAIS = true
 	}
 
@@ -1362,7 +1524,14 @@ func (t *target) delobj(lom *core.LOM, evict bool) (int, error, bool) {
 	}
 	if delFromAIS {
 		size := lom.Lsize()
-		aisErr = lom.RemoveObj()
+		if !evict && softDelete(lom, cmn.GCO.Get()) {
+			aisErr = lom.MoveToTrash(time.Now().UnixNano())
+		} else {
+			aisErr = lom.RemoveObj()
+		}
+		if aisErr == nil {
+			xs.QuotaAdd(lom, -size, -1)
+		}
 		if aisErr != nil {
 			if !os.IsNotExist(aisErr) {
 				if backendErr != nil {
@@ -1396,6 +1565,9 @@ func (t *target) objMv(lom *core.LOM, msg *apc.ActMsg) (err error) {
 	if msg.Name == lom.ObjName {
 		return fmt.Errorf("%s: cannot rename/move object %s onto itself", t.si, lom)
 	}
+	if err := checkOverwrite(lom); err != nil {
+		return err
+	}
 
 	buf, slab := t.gmm.Alloc()
 	coiParams := xs.AllocCOI()
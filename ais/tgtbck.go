@@ -160,6 +160,25 @@ func (t *target) httpbckget(w http.ResponseWriter, r *http.Request, dpq *dpq) {
 			}
 		}
 		t.bsumm(w, r, phase, bck, &bsumMsg, dpq)
+	case apc.ActListTrash, apc.ActListSnapshots, apc.ActBrowseSnapshot:
+		if len(apiItems) == 0 {
+			t.writeErrURL(w, r)
+			return
+		}
+		bck, err := newBckFromQ(apiItems[0], nil, dpq)
+		if err != nil {
+			t.writeErr(w, r, err)
+			return
+		}
+		if err := bck.Init(t.owner.bmd); err != nil {
+			t.writeErr(w, r, err)
+			return
+		}
+		if msg.Action == apc.ActListTrash {
+			t.listTrash(w, r, bck, &msg.ActMsg)
+		} else {
+			t.listSnapshots(w, r, bck, &msg.ActMsg)
+		}
 	default:
 		t.writeErrAct(w, r, msg.Action)
 	}
@@ -380,6 +399,10 @@ func (t *target) httpbckdelete(w http.ResponseWriter, r *http.Request, apireq *a
 		defer nlp.Unlock()
 		defer wg.Wait()
 
+		if err := checkBckRetention(apireq.bck); err != nil {
+			t.writeErr(w, r, err)
+			return
+		}
 		core.UncacheBcks(wg, apireq.bck)
 		err := fs.DestroyBucket(msg.Action, apireq.bck.Bucket(), apireq.bck.Props.BID)
 		if err != nil {
@@ -428,7 +451,9 @@ func (t *target) httpbckpost(w http.ResponseWriter, r *http.Request, apireq *api
 	if err != nil {
 		return
 	}
-	if msg.Action != apc.ActPrefetchObjects {
+	switch msg.Action {
+	case apc.ActPrefetchObjects, apc.ActCreateSnapshot, apc.ActDestroySnapshot, apc.ActRestoreSnapshot:
+	default:
 		t.writeErrAct(w, r, msg.Action)
 		return
 	}
@@ -441,6 +466,10 @@ func (t *target) httpbckpost(w http.ResponseWriter, r *http.Request, apireq *api
 		t.writeErr(w, r, err)
 		return
 	}
+	if msg.Action != apc.ActPrefetchObjects {
+		t.snapshotBck(w, r, apireq.bck, &msg.ActMsg)
+		return
+	}
 
 	prfMsg := &apc.PrefetchMsg{}
 	if err := cos.MorphMarshal(msg.Value, prfMsg); err != nil {
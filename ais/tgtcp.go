@@ -32,6 +32,7 @@ import (
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/xact"
 	"github.com/NVIDIA/aistore/xact/xreg"
+	"github.com/NVIDIA/aistore/xact/xs"
 	jsoniter "github.com/json-iterator/go"
 )
 
@@ -205,6 +206,14 @@ func (t *target) daeputMsg(w http.ResponseWriter, r *http.Request) {
 			add, err = backend.NewAzure(t, tstats, false)
 		case apc.OCI:
 			add, err = backend.NewOCI(t, tstats, false)
+		case apc.B2:
+			add, err = backend.NewB2(t, tstats, false)
+		case apc.WebDAV:
+			add, err = backend.NewWebDAV(t, tstats, false)
+		case apc.FTP:
+			add, err = backend.NewFTP(t, tstats, false)
+		case apc.POSIX:
+			add, err = backend.NewPOSIX(t, tstats, false)
 		}
 		if err != nil {
 			t.writeErr(w, r, err)
@@ -308,6 +317,14 @@ func (t *target) enableBackend(w http.ResponseWriter, r *http.Request, items []s
 			bp, err = backend.NewAzure(t, t.statsT, false /*starting up*/)
 		case apc.OCI:
 			bp, err = backend.NewOCI(t, t.statsT, false /*starting up*/)
+		case apc.B2:
+			bp, err = backend.NewB2(t, t.statsT, false /*starting up*/)
+		case apc.WebDAV:
+			bp, err = backend.NewWebDAV(t, t.statsT, false /*starting up*/)
+		case apc.FTP:
+			bp, err = backend.NewFTP(t, t.statsT, false /*starting up*/)
+		case apc.POSIX:
+			bp, err = backend.NewPOSIX(t, t.statsT, false /*starting up*/)
 		}
 		if err != nil {
 			debug.AssertNoErr(err) // (unlikely)
@@ -398,11 +415,13 @@ func (t *target) httpdaeget(w http.ResponseWriter, r *http.Request) {
 	)
 	switch what {
 	case apc.WhatNodeConfig, apc.WhatSmap, apc.WhatBMD, apc.WhatSmapVote,
-		apc.WhatSnode, apc.WhatLog, apc.WhatMetricNames:
+		apc.WhatSnode, apc.WhatLog, apc.WhatMetricNames, apc.WhatAudit:
 		t.htrun.httpdaeget(w, r, query, t /*htext*/)
 	case apc.WhatSysInfo:
 		tsysinfo := apc.TSysInfo{MemCPUInfo: apc.GetMemCPU(), CapacityInfo: fs.CapStatusGetWhat()}
 		t.writeJSON(w, r, tsysinfo, httpdaeWhat)
+	case apc.WhatQuotaUsage:
+		t.writeJSON(w, r, xs.QuotaUsage(), httpdaeWhat)
 	case apc.WhatNodeStats:
 		ds := t.statsAndStatus()
 		daeStats := t.statsT.GetStats()
@@ -847,6 +866,15 @@ func (f *delb) do(nbck *meta.Bck) bool {
 		flt := xreg.Flt{Kind: apc.ActECEncode, Bck: nbck}
 		xreg.DoAbort(flt, errors.New("apply-bmd"))
 	}
+	if orepl, nrepl := &f.obck.Props.Replication, &nbck.Props.Replication; *orepl != *nrepl {
+		if orepl.Enabled {
+			flt := xreg.Flt{Kind: apc.ActReplicate, Bck: nbck}
+			xreg.DoAbort(flt, errors.New("apply-bmd"))
+		}
+		if nrepl.Enabled {
+			go replResync(nbck)
+		}
+	}
 	return true // break
 }
 
@@ -858,6 +886,9 @@ func (t *target) _postBMD(newBMD *bucketMD, tag string, rmbcks []*meta.Bck) {
 
 		errV := fmt.Errorf("[post-bmd] %s %s: remove bucket%s", tag, newBMD, cos.Plural(len(rmbcks)))
 		xreg.AbortAllBuckets(errV, rmbcks...)
+		for _, bck := range rmbcks {
+			xs.QuotaDelBck(bck)
+		}
 
 		defer wg.Wait()
 	}
@@ -1333,9 +1364,10 @@ func (t *target) healthHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// checks with a given target to see if it has the object.
+// checks with a given target to see if it has the object;
+// when requested (non-nil `oa`), fills in the object's attributes, including custom metadata.
 // target acts as a client - compare with api.HeadObject
-func (t *target) headt2t(lom *core.LOM, tsi *meta.Snode, smap *smapX) (ok bool) {
+func (t *target) headt2t(lom *core.LOM, tsi *meta.Snode, smap *smapX, oa *cmn.ObjAttrs) (ok bool) {
 	q := lom.Bck().NewQuery()
 	q.Set(apc.QparamSilent, "true")
 	q.Set(apc.QparamFltPresence, strconv.Itoa(apc.FltPresent))
@@ -1352,6 +1384,9 @@ func (t *target) headt2t(lom *core.LOM, tsi *meta.Snode, smap *smapX) (ok bool)
 	}
 	res := t.call(cargs, smap)
 	ok = res.err == nil
+	if ok && oa != nil {
+		oa.Cksum = oa.FromHeader(res.header)
+	}
 	freeCargs(cargs)
 	freeCR(res)
 	return
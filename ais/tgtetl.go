@@ -6,8 +6,10 @@ package ais
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn"
@@ -19,14 +21,12 @@ import (
 	"github.com/NVIDIA/aistore/core/meta"
 	"github.com/NVIDIA/aistore/ext/etl"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/memsys"
 )
 
 // [METHOD] /v1/etl
+// (all but in-process ETLs - see etl.InitWasmMsg - require Kubernetes)
 func (t *target) etlHandler(w http.ResponseWriter, r *http.Request) {
-	if !k8s.IsK8s() {
-		t.writeErr(w, r, k8s.ErrK8sRequired, 0, Silent)
-		return
-	}
 	switch {
 	case r.Method == http.MethodPut:
 		t.handleETLPut(w, r)
@@ -68,11 +68,17 @@ func (t *target) handleETLPut(w http.ResponseWriter, r *http.Request) {
 	}
 	xid := r.URL.Query().Get(apc.QparamUUID)
 
+	if _, ok := initMsg.(*etl.InitWasmMsg); !ok && !k8s.IsK8s() {
+		t.writeErr(w, r, k8s.ErrK8sRequired, 0, Silent)
+		return
+	}
 	switch msg := initMsg.(type) {
 	case *etl.InitSpecMsg:
 		err = etl.InitSpec(msg, xid, etl.StartOpts{})
 	case *etl.InitCodeMsg:
 		err = etl.InitCode(msg, xid)
+	case *etl.InitWasmMsg:
+		err = etl.InitWasm(msg, xid)
 	default:
 		debug.Assert(false, initMsg.String())
 	}
@@ -110,6 +116,10 @@ func (t *target) handleETLGet(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// /v1/etl/<etl-name>/logs or /v1/etl/<etl-name>/health or /v1/etl/<etl-name>/metrics
+	if !k8s.IsK8s() {
+		t.writeErr(w, r, k8s.ErrK8sRequired, 0, Silent)
+		return
+	}
 	switch apiItems[1] {
 	case apc.ETLLogs:
 		t.logsETL(w, r, apiItems[0])
@@ -164,12 +174,22 @@ func (t *target) getETL(w http.ResponseWriter, r *http.Request, etlName string,
 		return
 	}
 
-	if err := comm.InlineTransform(w, r, lom); err != nil {
+	var ecode int
+	if rangeHdr := r.Header.Get(cos.HdrRange); rangeHdr != "" {
+		ecode, err = t.rangeETL(w, comm, lom, rangeHdr)
+	} else {
+		err = comm.InlineTransform(w, r, lom)
+	}
+	if err != nil {
 		errV := cmn.NewErrETL(&cmn.ETLErrCtx{ETLName: etlName, PodName: comm.PodName(), SvcName: comm.SvcName()},
 			err.Error())
 		xetl := comm.Xact()
 		xetl.AddErr(errV)
-		t.writeErr(w, r, errV)
+		if ecode != 0 {
+			t.writeErr(w, r, errV, ecode)
+		} else {
+			t.writeErr(w, r, errV)
+		}
 		return
 	}
 
@@ -177,6 +197,57 @@ func (t *target) getETL(w http.ResponseWriter, r *http.Request, etlName string,
 	xetl.ObjsAdd(1, lom.Lsize())
 }
 
+// range read of the transformed content - requires the ETL's capability (see etl.TransformRange);
+// same response headers and limitations as non-transformed range reads (see rngToHeader)
+func (t *target) rangeETL(w http.ResponseWriter, comm etl.Communicator, lom *core.LOM, rangeHdr string) (ecode int, _ error) {
+	var (
+		hdr     = w.Header()
+		partial bool
+	)
+	rng := func(size int64) (int64, int64, error) {
+		ranges, err := parseMultiRange(rangeHdr, size)
+		if err != nil {
+			if cmn.IsErrRangeNotSatisfiable(err) {
+				hdr.Set(cos.HdrContentRange, fmt.Sprintf("%s*/%d", cos.HdrContentRangeValPrefix, size))
+			}
+			ecode = http.StatusRequestedRangeNotSatisfiable
+			return 0, 0, err
+		}
+		switch len(ranges) {
+		case 0:
+			return 0, size, nil
+		case 1:
+			partial = true
+			hdr.Set(cos.HdrAcceptRanges, "bytes")
+			hdr.Set(cos.HdrContentRange, ranges[0].contentRange(size))
+			return ranges[0].Start, ranges[0].Length, nil
+		default:
+			ecode = http.StatusRequestedRangeNotSatisfiable
+			return 0, 0, cmn.NewErrUnsupp("multi-range read", lom.Cname())
+		}
+	}
+	reader, err := etl.TransformRange(comm, lom, rng)
+	if err != nil {
+		if partial {
+			hdr.Del(cos.HdrAcceptRanges)
+			hdr.Del(cos.HdrContentRange)
+		}
+		return ecode, err
+	}
+	size := reader.Size()
+	if size >= 0 {
+		hdr.Set(cos.HdrContentLength, strconv.FormatInt(size, 10))
+	}
+	if partial {
+		w.WriteHeader(http.StatusPartialContent)
+	}
+	buf, slab := t.gmm.AllocSize(min(max(size, memsys.DefaultBufSize), memsys.DefaultBuf2Size))
+	_, err = io.CopyBuffer(w, reader, buf)
+	slab.Free(buf)
+	reader.Close()
+	return 0, err
+}
+
 func (t *target) logsETL(w http.ResponseWriter, r *http.Request, etlName string) {
 	logs, err := etl.PodLogs(etlName)
 	if err != nil {
@@ -21,7 +21,9 @@ import (
 	"github.com/NVIDIA/aistore/cmn/nlog"
 	"github.com/NVIDIA/aistore/core"
 	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/sse"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/transport/bundle"
 	"github.com/NVIDIA/aistore/xact/xreg"
@@ -61,6 +63,13 @@ func (t *target) Backend(bck *meta.Bck) core.Backend {
 
 func (t *target) PutObject(lom *core.LOM, params *core.PutParams) error {
 	debug.Assert(params.WorkTag != "" && !params.Atime.IsZero())
+	// object lock (WORM): all writes except rebalance and migration (that move objects as they are)
+	if params.OWT != cmn.OwtRebalance {
+		if err := checkOverwriteDst(lom); err != nil {
+			cos.Close(params.Reader)
+			return err
+		}
+	}
 	workFQN := fs.CSM.Gen(lom, fs.WorkfileType, params.WorkTag)
 	poi := allocPOI()
 	{
@@ -75,13 +84,24 @@ func (t *target) PutObject(lom *core.LOM, params *core.PutParams) error {
 		poi.owt = params.OWT
 		poi.skipEC = params.SkipEC
 		poi.coldGET = params.ColdGET
+		poi.sse = params.SSE
 	}
 	if poi.owt != cmn.OwtPut {
 		poi.cksumToUse = params.Cksum
 	}
+	var prevSize int64 = -1
+	if params.OWT < cmn.OwtRebalance {
+		if size, _, _, err := lom.Fstat(false); err == nil {
+			prevSize = size
+		}
+	}
 	_, err := poi.putObject()
 	freePOI(poi)
-	debug.Assert(err != nil || params.Size <= 0 || params.Size == lom.Lsize(true), lom.String(), params.Size, lom.Lsize(true))
+	if err == nil && params.OWT < cmn.OwtRebalance {
+		t.quotaPut(lom, prevSize)
+	}
+	debug.Assert(err != nil || params.Size <= 0 || params.Size == lom.Lsize(true) || sse.IsCustomer(lom.SSERef()),
+		lom.String(), params.Size, lom.Lsize(true))
 	return err
 }
 
@@ -109,7 +129,15 @@ func (t *target) EvictObject(lom *core.LOM) (ecode int, err error) {
 }
 
 func (t *target) HeadObjT2T(lom *core.LOM, si *meta.Snode) bool {
-	return t.headt2t(lom, si, t.owner.smap.get())
+	return t.headt2t(lom, si, t.owner.smap.get(), nil)
+}
+
+func (t *target) HeadObjAttrsT2T(lom *core.LOM, si *meta.Snode) *cmn.ObjAttrs {
+	oa := &cmn.ObjAttrs{}
+	if !t.headt2t(lom, si, t.owner.smap.get(), oa) {
+		return nil
+	}
+	return oa
 }
 
 // CopyObject:
@@ -135,6 +163,9 @@ func (t *target) CopyObject(lom *core.LOM, dm *bundle.DataMover, params *xs.CoiP
 	return size, err
 }
 
+// restore object from EC slices (see xs.ECR)
+func (*target) ECRecover(lom *core.LOM) error { return ec.ECM.Recover(lom) }
+
 // use `backend.GetObj` (compare w/ other instances calling `backend.GetObjReader`)
 func (t *target) GetCold(ctx context.Context, lom *core.LOM, owt cmn.OWT) (ecode int, err error) {
 	// 1. lock
@@ -268,8 +299,14 @@ func (t *target) _promLocal(params *core.PromoteParams, lom *core.LOM) (fileSize
 	)
 	fileSize = -1
 
-	if err = lom.Load(true /*cache it*/, false /*locked*/); err == nil && !params.OverwriteDst {
-		return
+	if err = lom.Load(true /*cache it*/, false /*locked*/); err == nil {
+		if !params.OverwriteDst {
+			return
+		}
+		// object lock (WORM)
+		if err = checkRetention(lom, false); err != nil {
+			return
+		}
 	}
 	if params.DeleteSrc {
 		// To use `params.SrcFQN` as `workFQN`, make sure both are
@@ -344,7 +381,7 @@ func (t *target) _promRemote(params *core.PromoteParams, lom *core.LOM, tsi *met
 	lom.FQN = params.SrcFQN
 
 	// when not overwriting check w/ remote target first (and separately)
-	if !params.OverwriteDst && t.headt2t(lom, tsi, smap) {
+	if !params.OverwriteDst && t.headt2t(lom, tsi, smap, nil) {
 		return -1, nil
 	}
 
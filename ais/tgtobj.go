@@ -9,6 +9,7 @@ import (
 	"context"
 	"encoding"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -33,6 +34,7 @@ import (
 	"github.com/NVIDIA/aistore/core/meta"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/sse"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/mirror"
 	"github.com/NVIDIA/aistore/reb"
@@ -55,6 +57,9 @@ type (
 		t          *target       // this
 		lom        *core.LOM     // obj
 		cksumToUse *cos.Cksum    // if available (not `none`), can be validated and will be stored
+		sse        *apc.SSEMsg   // (optional) server-side encryption by remote backend
+		ssec       []byte        // (optional) client-supplied encryption key (never stored - see fs/sse)
+		repl       *replIn       // (optional) active-active replicated write from the peer cluster
 		config     *cmn.Config   // (during this request)
 		resphdr    http.Header   // as implied
 		workFQN    string        // temp fqn to be renamed
@@ -79,6 +84,7 @@ type (
 		lom        *core.LOM       // obj
 		dpq        *dpq
 		ranges     byteRanges // range read (see https://www.rfc-editor.org/rfc/rfc7233#section-2.1)
+		ssec       []byte     // (optional) client-supplied encryption key (see fs/sse)
 		atime      int64      // access time.Now()
 		ltime      int64      // mono.NanoTime, to measure latency
 		rstarttime int64      // mono.NanoTime, mark start of remote GET to measure latency
@@ -109,9 +115,10 @@ type (
 		t       *target       // this
 		config  *cmn.Config   // (during this request)
 		lom     *core.LOM     // append to or _as_
-		cksum   *cos.Cksum    // checksum expected once Flush-ed
+		cksum   *cos.Cksum    // checksum expected once Flush-ed (or extended)
+		resphdr http.Header   // (apc.ExtendOp)
 		hdl     aoHdl         // (packed)
-		op      string        // enum {apc.AppendOp, apc.FlushOp}
+		op      string        // enum {apc.AppendOp, apc.FlushOp, apc.ExtendOp}
 		started int64         // start time (nanoseconds)
 		size    int64         // Content-Length
 	}
@@ -174,13 +181,131 @@ func (poi *putOI) do(resphdr http.Header, r *http.Request, dpq *dpq) (int, error
 			poi.size = size
 		}
 	}
+	if err := poi.setExpires(r.Header.Get(apc.HdrObjTTL)); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if !poi.t2t {
+		if err := poi.setRetention(r.Header.Get(apc.HdrObjRetainUntil)); err != nil {
+			return http.StatusBadRequest, err
+		}
+		if err := poi.setReplIn(r.Header); err != nil {
+			return http.StatusBadRequest, err
+		}
+		if err := poi.setSSEC(r); err != nil {
+			return http.StatusBadRequest, err
+		}
+		// (ownership is system-assigned)
+		if hdrHasCustomKey(r.Header, cmn.OwnerObjMD) {
+			return http.StatusBadRequest, errReservedCustomKey(cmn.OwnerObjMD)
+		}
+		if user := poi.t.reqUser(r.Header); user != "" {
+			poi.lom.SetCustomKey(cmn.OwnerObjMD, user)
+		}
+		if err := poi.setQuota(dpq, r.Header); err != nil {
+			if qerr, ok := err.(*cmn.ErrQuotaExceeded); ok {
+				return qerr.Status(), qerr
+			}
+			return http.StatusBadRequest, err
+		}
+	}
 	return poi.putObject()
 }
 
+// encrypt with client-supplied key: ais buckets only (no remote backend), no erasure coding
+func (poi *putOI) setSSEC(r *http.Request) (err error) {
+	if poi.ssec, err = parseSSEC(r); err != nil || poi.ssec == nil {
+		return err
+	}
+	switch {
+	case poi.lom.Bck().IsRemote():
+		err = cmn.NewErrUnsupp("use client-supplied encryption key with", "remote bucket "+poi.lom.Bck().Cname(""))
+	case poi.lom.ECEnabled():
+		err = cmn.NewErrUnsupp("use client-supplied encryption key with", "erasure-coded bucket "+poi.lom.Bck().Cname(""))
+	}
+	return err
+}
+
+// HEAD: validate client-supplied key (if provided) and indicate the encryption
+func headSSEC(r *http.Request, whdr http.Header, lom *core.LOM, isS3 bool) (int, error) {
+	key, err := parseSSEC(r)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	if key != nil {
+		if _, err := sse.CustomerKey(lom.SSERef(), key); err != nil {
+			if errors.Is(err, sse.ErrCustomerKeyMismatch) {
+				return http.StatusForbidden, err
+			}
+			return http.StatusBadRequest, err
+		}
+	}
+	if sse.IsCustomer(lom.SSERef()) && !isS3 { // (S3: see s3.SetS3Headers)
+		whdr.Set(apc.HdrSSECustomerAlgo, sse.CustomerAlgo)
+	}
+	return 0, nil
+}
+
+// native or S3 (SSE-C) headers; returns nil key when none is provided
+// - the key must never travel in the clear (compare with Amazon S3 that rejects SSE-C requests over HTTP)
+func parseSSEC(r *http.Request) (key []byte, err error) {
+	hdr := r.Header
+	if hdr.Get(cos.S3HdrSSECustomerAlgo) != "" || hdr.Get(cos.S3HdrSSECustomerKey) != "" {
+		key, err = sse.ParseCustomerKey(hdr.Get(cos.S3HdrSSECustomerAlgo), hdr.Get(cos.S3HdrSSECustomerKey),
+			hdr.Get(cos.S3HdrSSECustomerKeyMD5))
+	} else {
+		key, err = sse.ParseCustomerKey(hdr.Get(apc.HdrSSECustomerAlgo), hdr.Get(apc.HdrSSECustomerKey),
+			hdr.Get(apc.HdrSSECustomerKeyMD5))
+	}
+	if err == nil && key != nil && r.TLS == nil {
+		clear(key)
+		return nil, sse.ErrCustomerKeyInsecure
+	}
+	return key, err
+}
+
+// object expiration: either TTL (converted to absolute time) or custom "expires" (validated)
+func (poi *putOI) setExpires(ttl string) error {
+	if ttl == "" {
+		_, err := poi.lom.ObjAttrs().Expires()
+		return err
+	}
+	d, err := time.ParseDuration(ttl)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("invalid %s %q (expecting positive duration, e.g. \"24h\")", apc.HdrObjTTL, ttl)
+	}
+	poi.lom.SetCustomKey(cmn.ExpiresObjMD, strconv.FormatInt(time.Now().Add(d).Unix(), 10))
+	return nil
+}
+
+// object lock: either explicit retain-until time or the bucket's default retention
+func (poi *putOI) setRetention(v string) error {
+	lock := &poi.lom.Bprops().ObjLock
+	if v == "" {
+		if lock.Mode == "" || lock.Retention <= 0 {
+			_, err := poi.lom.ObjAttrs().RetainUntil()
+			return err
+		}
+		until := time.Now().Add(lock.Retention.D()).Unix()
+		poi.lom.SetCustomKey(cmn.RetainUntilObjMD, strconv.FormatInt(until, 10))
+		return nil
+	}
+	if lock.Mode == "" {
+		return fmt.Errorf("cannot set %s: bucket %s does not have object lock enabled",
+			apc.HdrObjRetainUntil, poi.lom.Bck().Cname(""))
+	}
+	until, err := cmn.ParseRetainUntil(v)
+	if err != nil {
+		return err
+	}
+	poi.lom.SetCustomKey(cmn.RetainUntilObjMD, strconv.FormatInt(until, 10))
+	return nil
+}
+
 func (poi *putOI) putObject() (ecode int, err error) {
 	poi.ltime = mono.NanoTime()
 	// PUT is a no-op if the checksums do match
-	if !poi.skipVC && !poi.coldGET {
+	// (unless encrypting with client-supplied key or overwriting the one that was)
+	if !poi.skipVC && !poi.coldGET && poi.repl == nil && poi.ssec == nil && !sse.IsCustomer(poi.lom.SSERef()) {
 		if poi.lom.EqCksum(poi.cksumToUse) {
 			if cmn.Rom.FastV(4, cos.SmoduleAIS) {
 				nlog.Infoln(poi.lom.String(), "has identical", poi.cksumToUse.String(), "- PUT is a no-op")
@@ -194,10 +319,17 @@ func (poi *putOI) putObject() (ecode int, err error) {
 	poi._cleanup(buf, slab, lmfh, erw)
 	if erw != nil {
 		err, ecode = erw, http.StatusInternalServerError
+		if qerr := (*cmn.ErrQuotaExceeded)(nil); errors.As(erw, &qerr) {
+			ecode = qerr.Status()
+		}
 		goto rerr
 	}
 
 	if ecode, err = poi.finalize(); err != nil {
+		if err == cmn.ErrSkip && poi.repl != nil {
+			poi.replDiscarded()
+			return 0, nil
+		}
 		goto rerr
 	}
 
@@ -214,6 +346,9 @@ func (poi *putOI) putObject() (ecode int, err error) {
 		// response header
 		if poi.resphdr != nil {
 			cmn.ToHeader(poi.lom.ObjAttrs(), poi.resphdr, 0 /*skip setting content-length*/)
+			if poi.ssec != nil {
+				poi.resphdr.Set(apc.HdrSSECustomerAlgo, sse.CustomerAlgo)
+			}
 		}
 	}
 
@@ -330,6 +465,9 @@ func (poi *putOI) finalize() (ecode int, err error) {
 		}
 	}
 	poi.t.putMirror(poi.lom)
+	if poi.owt < cmn.OwtRebalance {
+		poi.t.replicate(poi.lom)
+	}
 	return 0, nil
 }
 
@@ -377,6 +515,17 @@ func (poi *putOI) fini() (ecode int, err error) {
 		lom.SetAtimeUnix(poi.atime)
 	}
 
+	// active-active replication
+	if poi.repl != nil {
+		accept, err := poi.replResolve()
+		if err != nil {
+			return 0, err
+		}
+		if !accept {
+			return 0, cmn.ErrSkip
+		}
+	}
+
 	// ais versioning
 	if bck.IsAIS() && lom.VersionConf().Enabled {
 		if poi.owt < cmn.OwtRebalance {
@@ -395,6 +544,11 @@ func (poi *putOI) fini() (ecode int, err error) {
 	if err = lom.RenameFinalize(poi.workFQN); err != nil {
 		return 0, err
 	}
+	if poi.repl != nil {
+		if _, _, mtime, err := lom.Fstat(false /*get atime*/); err == nil {
+			xs.SetReplState(lom, mtime, poi.repl.fwd)
+		}
+	}
 	if lom.HasCopies() {
 		if errdc := lom.DelAllCopies(); errdc != nil {
 			nlog.Errorf("PUT (%s): failed to delete old copies [%v], proceeding anyway...", poi.loghdr(), errdc)
@@ -423,7 +577,11 @@ func (poi *putOI) putRemote() (int, error) {
 		ecode   int
 		backend = poi.t.Backend(lom.Bck())
 	)
-	ecode, err = backend.PutObj(lmfh, lom, poi.oreq)
+	if sb, ok := backend.(core.SSEBackend); ok && poi.sse != nil {
+		ecode, err = sb.PutObjSSE(lmfh, lom, poi.sse, poi.oreq)
+	} else {
+		ecode, err = backend.PutObj(lmfh, lom, poi.oreq) // (no SSE: the caller's responsibility to check)
+	}
 	if err == nil {
 		if !lom.Bck().IsRemoteAIS() {
 			lom.SetCustomKey(cmn.SourceObjMD, backend.Provider())
@@ -448,7 +606,21 @@ func (poi *putOI) write() (buf []byte, slab *memsys.Slab, lmfh cos.LomWriter, er
 		}{}
 		ckconf = poi.lom.CksumConf()
 	)
-	if lmfh, err = poi.lom.CreateWork(poi.workFQN); err != nil {
+	// rebalance (decommission) migrates objects encrypted with client-supplied keys as is
+	ref, raw := "", false
+	if poi.owt == cmn.OwtRebalance {
+		ref, raw = poi.lom.GetCustomKey(cmn.SSECustomerObjMD)
+	}
+	switch {
+	case raw:
+		delete(poi.lom.ObjAttrs().CustomMD, cmn.SSECustomerObjMD)
+		lmfh, err = poi.lom.CreateWorkRaw(poi.workFQN, ref)
+	case poi.ssec != nil:
+		lmfh, err = poi.lom.CreateWorkSSEC(poi.workFQN, poi.ssec)
+	default:
+		lmfh, err = poi.lom.CreateWork(poi.workFQN)
+	}
+	if err != nil {
 		return
 	}
 	if poi.size <= 0 {
@@ -458,6 +630,14 @@ func (poi *putOI) write() (buf []byte, slab *memsys.Slab, lmfh cos.LomWriter, er
 	}
 
 	switch {
+	case raw:
+		// (ciphertext: keeping plaintext checksum as is)
+		if poi.cksumToUse.IsEmpty() {
+			poi.lom.SetCksum(cos.NoneCksum)
+		} else {
+			poi.lom.SetCksum(poi.cksumToUse)
+		}
+		written, err = cos.CopyBuffer(lmfh, poi.r, buf)
 	case ckconf.Type == cos.ChecksumNone:
 		poi.lom.SetCksum(cos.NoneCksum)
 		// not using `ReadFrom` of the `*os.File` -
@@ -509,9 +689,18 @@ func (poi *putOI) write() (buf []byte, slab *memsys.Slab, lmfh cos.LomWriter, er
 		debug.AssertNoErr(err)
 	}
 
-	cos.Close(lmfh)
+	err = lmfh.Close() // (encrypted: seals the last chunk)
 	lmfh = nil
+	if err != nil {
+		return
+	}
 
+	if raw {
+		if written = sse.PlainSize(written); written < 0 {
+			err = fmt.Errorf("%s: invalid size of the migrated encrypted content", poi.loghdr())
+			return
+		}
+	}
 	poi.lom.SetSize(written) // TODO: compare with non-zero lom.Lsize() that may have been set via oa.FromHeader()
 	if cksums.store != nil {
 		if !cksums.finalized {
@@ -534,8 +723,10 @@ func (poi *putOI) _cleanup(buf []byte, slab *memsys.Slab, lmfh cos.LomWriter, er
 
 	// not ok
 	poi.r.Close()
-	if nerr := lmfh.Close(); nerr != nil {
-		nlog.Errorf(fmtNested, poi.t, err, "close", poi.workFQN, nerr)
+	if lmfh != nil {
+		if nerr := lmfh.Close(); nerr != nil {
+			nlog.Errorf(fmtNested, poi.t, err, "close", poi.workFQN, nerr)
+		}
 	}
 	if nerr := cos.RemoveFile(poi.workFQN); nerr != nil && !os.IsNotExist(nerr) {
 		nlog.Errorf(fmtNested, poi.t, err, "remove", poi.workFQN, nerr)
@@ -544,7 +735,7 @@ func (poi *putOI) _cleanup(buf []byte, slab *memsys.Slab, lmfh cos.LomWriter, er
 
 func (poi *putOI) validateCksum(c *cmn.CksumConf) (v bool) {
 	switch poi.owt {
-	case cmn.OwtRebalance, cmn.OwtCopy, cmn.OwtCopySameBucket:
+	case cmn.OwtRebalance, cmn.OwtCopy, cmn.OwtCopySameBucket, cmn.OwtCopyLocal:
 		v = c.ValidateObjMove
 	case cmn.OwtPut:
 		v = true
@@ -787,7 +978,7 @@ func (goi *getOI) validateRecover() (coldGet bool, code int, err error) {
 	)
 validate:
 	err = lom.ValidateMetaChecksum()
-	if err == nil {
+	if err == nil && !sse.IsCustomer(lom.SSERef()) { // (client-supplied key: content gets authenticated when read)
 		err = lom.ValidateContentChecksum()
 	}
 	if err == nil {
@@ -900,7 +1091,7 @@ func (goi *getOI) restoreFromAny(skipLomRestore bool) (doubleCheck bool, ecode i
 		doubleCheck = true
 	}
 	if running && tsi.ID() != goi.t.SID() {
-		if goi.t.headt2t(goi.lom, tsi, smap) {
+		if goi.t.headt2t(goi.lom, tsi, smap, nil) {
 			gfnNode = tsi
 			goto gfn
 		}
@@ -1001,7 +1192,7 @@ func (goi *getOI) getFromNeighbor(lom *core.LOM, tsi *meta.Snode) bool {
 
 func (goi *getOI) txfini() (ecode int, err error) {
 	var (
-		lmfh *os.File
+		lmfh cos.LomReader
 		hrng *htrange
 		fqn  = goi.lom.FQN
 		dpq  = goi.dpq
@@ -1009,16 +1200,27 @@ func (goi *getOI) txfini() (ecode int, err error) {
 	if !goi.cold && !dpq.isGFN && !goi.lom.IsChunked() {
 		fqn = goi.lom.LBGet() // best-effort GET load balancing (see also mirror.findLeastUtilized())
 	}
-	// open
-	// TODO -- FIXME: use lom.Open() instead of os.Open(); TestECChecksum
-	lmfh, err = os.Open(fqn)
+	// open (and decrypt, if encrypted at rest)
+	// TODO -- FIXME: use lom.Open() instead; TestECChecksum
+	if goi.ssec != nil {
+		lmfh, err = goi.lom.OpenSSEC(fqn, goi.ssec)
+	} else {
+		lmfh, err = goi.lom.OpenCopy(fqn)
+	}
 	if err != nil {
-		if os.IsNotExist(err) {
+		switch {
+		case os.IsNotExist(err):
 			// NOTE: retry only once and only when ec-enabled - see goi.restoreFromAny()
 			ecode = http.StatusNotFound
 			goi.retry = goi.lom.ECEnabled()
-		} else {
+		case errors.Is(err, sse.ErrCustomerKeyMismatch):
+			ecode = http.StatusForbidden
+		case errors.Is(err, sse.ErrCustomerKeyRequired), errors.Is(err, sse.ErrCustomerKeyNotApplicable):
+			ecode = http.StatusBadRequest
+		case cos.IsPathErr(err):
 			goi.t.FSHC(err, goi.lom.Mountpath(), fqn)
+			fallthrough
+		default: // (e.g., failed to unwrap data key)
 			ecode = http.StatusInternalServerError
 			err = cmn.NewErrFailedTo(goi.t, "goi-finalize", goi.lom.Cname(), err, ecode)
 		}
@@ -1049,7 +1251,7 @@ func (goi *getOI) txfini() (ecode int, err error) {
 	return ecode, err
 }
 
-func (goi *getOI) _txrng(fqn string, lmfh *os.File, whdr http.Header, hrng *htrange) (err error) {
+func (goi *getOI) _txrng(fqn string, lmfh cos.LomReader, whdr http.Header, hrng *htrange) (err error) {
 	var (
 		r     io.Reader
 		lom   = goi.lom
@@ -1080,6 +1282,8 @@ func (goi *getOI) _txrng(fqn string, lmfh *os.File, whdr http.Header, hrng *htra
 	cmn.ToHeader(lom.ObjAttrs(), whdr, size, cksum)
 	if goi.dpq.isS3 {
 		s3.SetS3Headers(whdr, lom)
+	} else if goi.ssec != nil {
+		whdr.Set(apc.HdrSSECustomerAlgo, sse.CustomerAlgo)
 	}
 
 	buf, slab := goi.t.gmm.AllocSize(min(size, memsys.DefaultBuf2Size))
@@ -1092,7 +1296,7 @@ func (goi *getOI) _txrng(fqn string, lmfh *os.File, whdr http.Header, hrng *htra
 }
 
 // in particular, setup reader and writer and set headers
-func (goi *getOI) _txreg(fqn string, lmfh *os.File, whdr http.Header) (err error) {
+func (goi *getOI) _txreg(fqn string, lmfh cos.LomReader, whdr http.Header) (err error) {
 	var (
 		dpq   = goi.dpq
 		lom   = goi.lom
@@ -1105,6 +1309,8 @@ func (goi *getOI) _txreg(fqn string, lmfh *os.File, whdr http.Header) (err error
 	if dpq.isS3 {
 		// (expecting user to set bucket checksum = md5)
 		s3.SetS3Headers(whdr, lom)
+	} else if goi.ssec != nil {
+		whdr.Set(apc.HdrSSECustomerAlgo, sse.CustomerAlgo)
 	}
 
 	buf, slab := goi.t.gmm.AllocSize(min(size, memsys.DefaultBuf2Size))
@@ -1114,7 +1320,7 @@ func (goi *getOI) _txreg(fqn string, lmfh *os.File, whdr http.Header) (err error
 }
 
 // TODO: checksum
-func (goi *getOI) _txarch(fqn string, lmfh *os.File, whdr http.Header) error {
+func (goi *getOI) _txarch(fqn string, lmfh cos.LomReader, whdr http.Header) error {
 	var (
 		ar  archive.Reader
 		dpq = goi.dpq
@@ -1291,6 +1497,16 @@ func (a *apndOI) do(r *http.Request) (packedHdl string, ecode int, err error) {
 	if cksumValue != "" {
 		a.cksum = cos.NewCksum(cksumType, cksumValue)
 	}
+	if a.lom.EncryptionConf().Enabled {
+		return "", http.StatusBadRequest, cmn.NewErrUnsupp("append to", "encrypted bucket "+a.lom.Bck().Cname(""))
+	}
+	// (e.g., encrypted with client-supplied key)
+	a.lom.Lock(false)
+	if a.lom.Load(false /*cache it*/, true /*locked*/) == nil && a.lom.SSERef() != "" {
+		a.lom.Unlock(false)
+		return "", http.StatusBadRequest, cmn.NewErrUnsupp("append to", "encrypted object "+a.lom.Cname())
+	}
+	a.lom.Unlock(false)
 
 	switch a.op {
 	case apc.AppendOp:
@@ -1299,9 +1515,13 @@ func (a *apndOI) do(r *http.Request) (packedHdl string, ecode int, err error) {
 		slab.Free(buf)
 	case apc.FlushOp:
 		ecode, err = a.flush()
+	case apc.ExtendOp:
+		buf, slab := a.t.gmm.Alloc()
+		ecode, err = a.extend(buf)
+		slab.Free(buf)
 	default:
-		err = fmt.Errorf("invalid operation %q (expecting either %q or %q) - check %q query",
-			a.op, apc.AppendOp, apc.FlushOp, apc.QparamAppendType)
+		err = fmt.Errorf("invalid operation %q (expecting one of: %q, %q, %q) - check %q query",
+			a.op, apc.AppendOp, apc.FlushOp, apc.ExtendOp, apc.QparamAppendType)
 	}
 
 	return packedHdl, ecode, err
@@ -1387,6 +1607,164 @@ func (a *apndOI) flush() (int, error) {
 	return a.t.Promote(&params)
 }
 
+// APPEND to an existing object in place, in a single request (apc.ExtendOp):
+//   - under write lock, the object is renamed to a workfile, appended, and renamed back (compare with putA2I.fast) -
+//     readers see either the previous or the extended content; upon failure, the object is restored;
+//   - the checksum is computed incrementally, from the hash state recorded with the object (cmn.AppendCksumObjMD)
+//     and validated against the object's current checksum (when missing or invalid, the existing content is hashed);
+//   - existing local copies receive only the appended delta (see lom.AppendToCopies); EC re-encodes the object;
+//   - non-existing object gets created.
+func (a *apndOI) extend(buf []byte) (int, error) {
+	var (
+		lom     = a.lom
+		exists  bool
+		off     int64
+		fh      cos.LomWriter
+		workFQN = fs.CSM.Gen(lom, fs.WorkfileType, fs.WorkfileAppend)
+	)
+	if !lom.Bck().IsAIS() {
+		return http.StatusBadRequest, cmn.NewErrUnsupp("extend (append to)", lom.Bck().Cname("")+" object")
+	}
+	lom.Lock(true)
+	defer lom.Unlock(true)
+
+	switch err := lom.Load(false /*cache it*/, true /*locked*/); {
+	case err == nil:
+		exists, off = true, lom.Lsize()
+	case cos.IsNotExist(err, 0):
+		lom.SetCustomMD(nil)
+	default:
+		return http.StatusInternalServerError, err
+	}
+	cksum, err := a.cksumState(exists, buf)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	// append
+	if exists {
+		if err := lom.DetachMainTo(workFQN, buf); err != nil { // (copy-on-write when snapshotted)
+			return http.StatusInternalServerError, err
+		}
+		fh, err = lom.AppendWork(workFQN)
+	} else {
+		fh, err = lom.CreateWork(workFQN)
+	}
+	if err != nil {
+		a.restore(err, exists, workFQN, off)
+		return http.StatusInternalServerError, err
+	}
+	w := cos.NewWriterMulti(fh, cksum.H)
+	n, err := cos.CopyBuffer(w, a.r, buf)
+	if err == nil {
+		err = fh.Sync()
+	}
+	cos.Close(fh)
+	if err == nil {
+		cksum.Finalize()
+		if !a.cksum.IsEmpty() && !cksum.Equal(a.cksum) {
+			err = cos.NewErrDataCksum(cksum.Clone(), a.cksum)
+		}
+	}
+	if err != nil {
+		a.restore(err, exists, workFQN, off)
+		return http.StatusInternalServerError, err
+	}
+
+	// finalize
+	if err := lom.RenameFinalize(workFQN); err != nil {
+		a.restore(err, exists, workFQN, off)
+		return http.StatusInternalServerError, err
+	}
+	if lom.VersionConf().Enabled {
+		if err := lom.IncVersion(); err != nil {
+			nlog.Errorln(err) // (unlikely)
+		}
+	}
+	lom.SetSize(off + n)
+	lom.SetCksum(cksum.Clone())
+	if m, ok := cksum.H.(encoding.BinaryMarshaler); ok && cksum.Type() != cos.ChecksumNone {
+		if state, err := m.MarshalBinary(); err == nil {
+			lom.SetCustomKey(cmn.AppendCksumObjMD, base64.StdEncoding.EncodeToString(state))
+		}
+	}
+	lom.SetAtimeUnix(a.started)
+	if err := lom.Persist(); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if err := lom.AppendToCopies(off, buf); err != nil {
+		nlog.Errorln(err)
+	}
+	if lom.ECEnabled() {
+		if err := ec.ECM.EncodeObject(lom, nil); err != nil && err != ec.ErrorECDisabled {
+			return http.StatusInternalServerError, err
+		}
+	}
+	a.t.putMirror(lom)
+	a.t.replicate(lom)
+
+	if a.resphdr != nil {
+		a.resphdr.Set(apc.HdrAppendOffset, strconv.FormatInt(off, 10))
+		a.resphdr.Set(apc.HdrObjCksumType, cksum.Type())
+		a.resphdr.Set(apc.HdrObjCksumVal, cksum.Value())
+	}
+	lat := time.Now().UnixNano() - a.started
+	vlabs := map[string]string{stats.VarlabBucket: lom.Bck().Cname("")}
+	a.t.statsT.AddWith(
+		cos.NamedVal64{Name: stats.AppendCount, Value: 1, VarLabs: vlabs},
+		cos.NamedVal64{Name: stats.AppendLatency, Value: lat, VarLabs: vlabs},
+	)
+	if cmn.Rom.FastV(4, cos.SmoduleAIS) {
+		nlog.Infoln("APPEND (extend)", lom.String(), off, "+", n, time.Duration(lat))
+	}
+	return 0, nil
+}
+
+// continue computing the object's checksum - from the recorded (and validated) state if possible
+func (a *apndOI) cksumState(exists bool, buf []byte) (*cos.CksumHash, error) {
+	var (
+		lom   = a.lom
+		cksum = cos.NewCksumHash(lom.CksumType())
+	)
+	if !exists || cksum.Type() == cos.ChecksumNone {
+		return cksum, nil
+	}
+	if um, ok := cksum.H.(encoding.BinaryUnmarshaler); ok {
+		if state, ok := lom.GetCustomKey(cmn.AppendCksumObjMD); ok {
+			if b, err := base64.StdEncoding.DecodeString(state); err == nil && um.UnmarshalBinary(b) == nil {
+				if cur := lom.Checksum(); cur.Ty() == cksum.Type() && cur.Val() == hex.EncodeToString(cksum.H.Sum(nil)) {
+					return cksum, nil
+				}
+			}
+			cksum = cos.NewCksumHash(lom.CksumType())
+		}
+	}
+	// hash existing content
+	fh, err := lom.Open()
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.CopyBuffer(cksum.H, fh, buf)
+	cos.Close(fh)
+	return cksum, err
+}
+
+// undo partial append
+func (a *apndOI) restore(err error, exists bool, workFQN string, off int64) {
+	if !exists {
+		if errV := cos.RemoveFile(workFQN); errV != nil && !os.IsNotExist(errV) {
+			nlog.Errorf(fmtNested, a.t, err, "remove", workFQN, errV)
+		}
+		return
+	}
+	if errV := os.Truncate(workFQN, off); errV != nil {
+		nlog.Errorf(fmtNested, a.t, err, "truncate", workFQN, errV)
+	}
+	if errV := a.lom.RenameToMain(workFQN); errV != nil {
+		nlog.Errorf(fmtNested, a.t, err, "rename back", workFQN, errV)
+	}
+}
+
 func (a *apndOI) parse(packedHdl string) error {
 	if packedHdl == "" {
 		return nil
@@ -1457,6 +1835,12 @@ func (coi *coi) do(t *target, dm *bundle.DataMover, lom *core.LOM) (size int64,
 		}
 		return
 	}
+	// object lock (WORM) - unless copying (e.g., mirroring) the object onto itself
+	if lom.Uname() != dst.Uname() {
+		if err := checkOverwriteDst(dst); err != nil {
+			return 0, err
+		}
+	}
 	// do
 	if coi.DP != nil {
 		var ecode int
@@ -1530,6 +1914,9 @@ func (coi *coi) _reader(t *target, dm *bundle.DataMover, lom, dst *core.LOM) (si
 	if errN != nil {
 		return 0, 0, errN
 	}
+	if coi.RStats != nil {
+		reader = coi.RStats.Reader(reader)
+	}
 	poi := allocPOI()
 	{
 		poi.t = t
@@ -1540,28 +1927,33 @@ func (coi *coi) _reader(t *target, dm *bundle.DataMover, lom, dst *core.LOM) (si
 		poi.workFQN = fs.CSM.Gen(dst, fs.WorkfileType, "copy-dp")
 		poi.atime = oah.AtimeUnix()
 		poi.cksumToUse = oah.Checksum()
+		poi.sse = coi.SSE
 
 		poi.owt = coi.OWT
 		if dm != nil {
 			poi.owt = dm.OWT() // (precedence; cmn.OwtCopy, cmn.OwtTransform - what else?)
 		}
 	}
-	if poi.owt == cmn.OwtCopy {
+	if poi.owt == cmn.OwtCopy || poi.owt == cmn.OwtCopyLocal {
 		// preserve src metadata when copying (vs. transforming)
 		dst.CopyVersion(lom)
 		dst.SetCustomMD(lom.GetCustomMD())
 
-		// [special] when src == dst (`ais cp s3://data s3://data --all`)
-		if backend := lom.Bck().RemoteBck(); backend != nil && backend.Equal(coi.BckTo.Bucket()) {
+		// [special] when src == dst (`ais cp s3://data s3://data --all`), unless flushing
+		if backend := lom.Bck().RemoteBck(); backend != nil && backend.Equal(coi.BckTo.Bucket()) && !coi.Flush {
 			poi.owt = cmn.OwtCopySameBucket
 		}
 	}
+	coi.addCustom(dst)
 
 	ecode, err := poi.putObject()
 	freePOI(poi)
 	if err == nil {
 		// xaction stats: inc locally processed (and see data mover for in and out objs)
 		size = oah.Lsize()
+		if size < 0 {
+			size = dst.Lsize() // post-transform
+		}
 	}
 	return size, ecode, err
 }
@@ -1596,10 +1988,15 @@ func (coi *coi) _regular(t *target, lom, dst *core.LOM) (size int64, _ error) {
 
 	// TODO: add a metric to count and size local copying
 	dst2, err := lom.Copy2FQN(dst.FQN, coi.Buf)
+	if err == nil && len(coi.CustomMD) > 0 {
+		coi.addCustom(dst2)
+		err = dst2.Persist()
+	}
 	if err == nil {
 		size = lom.Lsize()
 		if coi.Finalize {
 			t.putMirror(dst2)
+			t.replicate(dst2)
 		}
 	}
 	if dst2 != nil {
@@ -1608,6 +2005,23 @@ func (coi *coi) _regular(t *target, lom, dst *core.LOM) (size int64, _ error) {
 	return size, err
 }
 
+// add CoiParams.CustomMD to the destination's own copy of custom metadata
+// (that may otherwise be shared with the source - see above)
+func (coi *coi) addCustom(dst *core.LOM) {
+	if len(coi.CustomMD) == 0 {
+		return
+	}
+	md := dst.GetCustomMD()
+	custom := make(cos.StrKVs, len(md)+len(coi.CustomMD))
+	for k, v := range md {
+		custom[k] = v
+	}
+	for k, v := range coi.CustomMD {
+		custom[k] = v
+	}
+	dst.SetCustomMD(custom)
+}
+
 // send object => designated target
 // * source is a LOM or a reader (that may be reading from remote)
 // * one of the two equivalent transmission mechanisms: PUT or transport Send
@@ -1677,11 +2091,16 @@ func (coi *coi) _send(t *target, lom *core.LOM, sargs *sendArgs) (size int64, _
 		if err != nil {
 			return
 		}
-		// returns cos.ContentLengthUnknown (-1) if post-transform size is unknown
+		// returns cos.ContentLengthUnknown (-1) if post-transform size is unknown -
+		// in which case the receiver reports it back (see bundle.DataMover.AckSize)
 		size = oah.Lsize()
 		sargs.reader, sargs.objAttrs = reader, oah
 	}
 
+	if coi.RStats != nil && coi.OWT != cmn.OwtPromote {
+		sargs.reader = coi.RStats.Reader(sargs.reader)
+	}
+
 	// do
 	var err error
 	sargs.bckTo = coi.BckTo
@@ -1704,6 +2123,10 @@ func (coi *coi) _dm(lom *core.LOM, sargs *sendArgs) error {
 		hdr.Bck.Copy(sargs.bckTo.Bucket())
 		hdr.ObjName = sargs.objNameTo
 		hdr.ObjAttrs.CopyFrom(oa, false /*skip cksum*/)
+		for k, v := range coi.CustomMD {
+			hdr.ObjAttrs.SetCustomKey(k, v)
+		}
+		hdr.Opaque = coi.Opaque
 	}
 	o.Callback = func(_ *transport.ObjHdr, _ io.ReadCloser, _ any, _ error) {
 		core.FreeLOM(lom)
@@ -1719,6 +2142,9 @@ func (coi *coi) put(t *target, sargs *sendArgs) error {
 		query = sargs.bckTo.NewQuery()
 	)
 	cmn.ToHeader(sargs.objAttrs, hdr, sargs.objAttrs.Lsize(true))
+	for k, v := range coi.CustomMD {
+		hdr.Add(apc.HdrObjCustomMD, k+"="+v)
+	}
 	hdr.Set(apc.HdrT2TPutterID, t.SID())
 	query.Set(apc.QparamOWT, sargs.owt.ToS())
 	if coi.Xact != nil {
@@ -1742,9 +2168,18 @@ func (coi *coi) put(t *target, sargs *sendArgs) error {
 	if err != nil {
 		return cmn.NewErrFailedTo(t, "coi.put "+sargs.bckTo.Name+"/"+sargs.objNameTo, sargs.tsi, err)
 	}
-	cos.DrainReader(resp.Body)
+	// e.g., destination under retention (see checkOverwrite)
+	if resp.StatusCode >= http.StatusBadRequest {
+		res := &callResult{si: sargs.tsi, status: resp.StatusCode}
+		b := cmn.NewBuffer()
+		b.ReadFrom(resp.Body)
+		err = res.herr(req, b.String())
+		cmn.FreeBuffer(b)
+	} else {
+		cos.DrainReader(resp.Body)
+	}
 	resp.Body.Close()
-	return nil
+	return err
 }
 
 func (coi *coi) stats(size int64, err error) {
@@ -1763,7 +2198,8 @@ func (a *putA2I) do() (int, error) {
 	}
 	// standard library does not support appending to tgz, zip, and such;
 	// for TAR there is an optimizing workaround not requiring a full copy
-	if a.mime == archive.ExtTar && !a.put /*append*/ && !a.lom.IsChunked() {
+	// (but not when encrypted at rest)
+	if a.mime == archive.ExtTar && !a.put /*append*/ && !a.lom.IsChunked() && a.lom.SSERef() == "" {
 		var (
 			err       error
 			fh        *os.File
@@ -1772,7 +2208,7 @@ func (a *putA2I) do() (int, error) {
 			tarFormat tar.Format
 			workFQN   = fs.CSM.Gen(a.lom, fs.WorkfileType, fs.WorkfileAppendToArch)
 		)
-		if err = a.lom.RenameMainTo(workFQN); err != nil {
+		if err = a.lom.DetachMainTo(workFQN, nil); err != nil {
 			return http.StatusInternalServerError, err
 		}
 		fh, tarFormat, offset, err = archive.OpenTarForAppend(a.lom.Cname(), workFQN)
@@ -1801,15 +2237,24 @@ func (a *putA2I) do() (int, error) {
 cpap: // copy + append
 	var (
 		err     error
-		wfh     *os.File
+		wfh     cos.LomWriter
 		lmfh    cos.LomReader
 		workFQN string
 		cksum   cos.CksumHashSize
 		aw      archive.Writer
 	)
+	if !a.put {
+		// open first (encrypted: creating work file sets new data key - see CreateWork)
+		if lmfh, err = a.lom.Open(); err != nil {
+			return http.StatusNotFound, err
+		}
+	}
 	workFQN = fs.CSM.Gen(a.lom, fs.WorkfileType, fs.WorkfileAppendToArch)
-	wfh, err = os.OpenFile(workFQN, os.O_CREATE|os.O_WRONLY, cos.PermRWR)
+	wfh, err = a.lom.CreateWork(workFQN)
 	if err != nil {
+		if lmfh != nil {
+			cos.Close(lmfh)
+		}
 		return http.StatusInternalServerError, err
 	}
 	// currently, arch writers only use size and time but it may change
@@ -1822,11 +2267,6 @@ cpap: // copy + append
 		aw.Fini()
 	} else {
 		// copy + append
-		lmfh, err = a.lom.Open()
-		if err != nil {
-			cos.Close(wfh)
-			return http.StatusNotFound, err
-		}
 		cksum.Init(a.lom.CksumType())
 		aw = archive.NewWriter(a.mime, wfh, &cksum, nil)
 		err = aw.Copy(lmfh, a.lom.Lsize())
@@ -1838,7 +2278,9 @@ cpap: // copy + append
 	}
 
 	// finalize
-	cos.Close(wfh)
+	if errC := wfh.Close(); err == nil {
+		err = errC
+	}
 	if err == nil {
 		cksum.Finalize()
 		err = a.finalize(cksum.Size, cksum.Clone(), workFQN)
@@ -1886,7 +2328,7 @@ func (a *putA2I) finalize(size int64, cksum *cos.Cksum, fqn string) error {
 	debug.Func(func() {
 		finfo, err := os.Stat(fqn)
 		debug.AssertNoErr(err)
-		debug.Assertf(finfo.Size() == size, "%d != %d", finfo.Size(), size)
+		debug.Assertf(finfo.Size() == size || a.lom.SSERef() != "", "%d != %d", finfo.Size(), size)
 	})
 	// done
 	if err := a.lom.RenameFinalize(fqn); err != nil {
@@ -1904,6 +2346,7 @@ func (a *putA2I) finalize(size int64, cksum *cos.Cksum, fqn string) error {
 		}
 	}
 	a.t.putMirror(a.lom)
+	a.t.replicate(a.lom)
 	return nil
 }
 
@@ -5,11 +5,16 @@
 package ais
 
 import (
+	"bytes"
+	cryptorand "crypto/rand"
+	"encoding/base64"
 	"flag"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
+	"strconv"
 	"testing"
 	"time"
 
@@ -20,12 +25,16 @@ import (
 	"github.com/NVIDIA/aistore/core/meta"
 	"github.com/NVIDIA/aistore/core/mock"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/sse"
 	"github.com/NVIDIA/aistore/tools/readers"
+	"github.com/NVIDIA/aistore/xact/xs"
 )
 
 const (
-	testMountpath = "/tmp/ais-test-mpath" // mpath is created and deleted during the test
-	testBucket    = "bck"
+	testMountpath   = "/tmp/ais-test-mpath" // mpath is created and deleted during the test
+	testBucket      = "bck"
+	testCksumBucket = "bck-cksum"
+	testLockBucket  = "bck-lock"
 )
 
 var (
@@ -84,12 +93,263 @@ func TestMain(m *testing.M) {
 			Type: cos.ChecksumNone,
 		},
 	})
+	bckCksum := meta.NewBck(testCksumBucket, apc.AIS, cmn.NsGlobal)
+	bmd.add(bckCksum, &cmn.Bprops{
+		Cksum: cmn.CksumConf{
+			Type: cos.ChecksumXXHash,
+		},
+	})
+	bckLock := meta.NewBck(testLockBucket, apc.AIS, cmn.NsGlobal)
+	bmd.add(bckLock, &cmn.Bprops{
+		Cksum:   cmn.CksumConf{Type: cos.ChecksumXXHash},
+		ObjLock: cmn.ObjLockConf{Mode: apc.ObjLockCompliance},
+	})
 	t.owner.bmd.putPersist(bmd, nil)
 	fs.CreateBucket(bck.Bucket(), false /*nilbmd*/)
+	fs.CreateBucket(bckCksum.Bucket(), false /*nilbmd*/)
+	fs.CreateBucket(bckLock.Bucket(), false /*nilbmd*/)
+
+	smap := newSmap()
+	smap.addTarget(t.si)
+	t.owner.smap.put(smap)
 
 	m.Run()
 }
 
+func TestObjExtend(tt *testing.T) {
+	var (
+		content []byte
+		bck     = cmn.Bck{Name: testCksumBucket, Provider: apc.AIS, Ns: cmn.NsGlobal}
+		buf     = make([]byte, 16*cos.KiB)
+	)
+	extend := func(size int64) {
+		tt.Helper()
+		lom := core.AllocLOM("log")
+		defer core.FreeLOM(lom)
+		if err := lom.InitBck(&bck); err != nil {
+			tt.Fatal(err)
+		}
+		b := make([]byte, size)
+		cryptorand.Read(b)
+		a := &apndOI{
+			started: time.Now().UnixNano(),
+			t:       t,
+			lom:     lom,
+			r:       io.NopCloser(bytes.NewReader(b)),
+			resphdr: make(http.Header),
+			op:      apc.ExtendOp,
+		}
+		if _, err := a.extend(buf); err != nil {
+			tt.Fatal(err)
+		}
+		if off := a.resphdr.Get(apc.HdrAppendOffset); off != strconv.Itoa(len(content)) {
+			tt.Fatalf("expected offset %d, got %s", len(content), off)
+		}
+		content = append(content, b...)
+
+		// validate
+		if err := lom.Load(false, false); err != nil {
+			tt.Fatal(err)
+		}
+		data, err := os.ReadFile(lom.FQN)
+		if err != nil {
+			tt.Fatal(err)
+		}
+		if !bytes.Equal(data, content) || lom.Lsize() != int64(len(content)) {
+			tt.Fatalf("content mismatch: size %d vs %d", lom.Lsize(), len(content))
+		}
+		cksum := cos.NewCksumHash(cos.ChecksumXXHash)
+		cksum.H.Write(content)
+		cksum.Finalize()
+		if !lom.Checksum().Equal(cksum.Clone()) {
+			tt.Fatalf("checksum mismatch: %s vs %s", lom.Checksum(), cksum.Clone())
+		}
+		if _, ok := lom.GetCustomKey(cmn.AppendCksumObjMD); !ok {
+			tt.Fatalf("%s is missing", cmn.AppendCksumObjMD)
+		}
+	}
+
+	extend(100)           // new
+	extend(cos.KiB)       // incremental
+	extend(3*cos.MiB + 1) // ditto
+
+	// invalid checksum state: falls back to hashing existing content
+	lom := core.AllocLOM("log")
+	defer core.FreeLOM(lom)
+	if err := lom.InitBck(&bck); err != nil {
+		tt.Fatal(err)
+	}
+	lom.Lock(true)
+	if err := lom.Load(false, true); err != nil {
+		tt.Fatal(err)
+	}
+	lom.SetCustomKey(cmn.AppendCksumObjMD, "garbage")
+	if err := lom.Persist(); err != nil {
+		tt.Fatal(err)
+	}
+	lom.Unlock(true)
+	extend(10)
+
+	lom.RemoveMain()
+}
+
+// none of the append operations is supported for objects encrypted with client-supplied keys
+func TestObjAppendSSEC(tt *testing.T) {
+	bck := cmn.Bck{Name: testBucket, Provider: apc.AIS, Ns: cmn.NsGlobal}
+	lom := core.AllocLOM("ssec")
+	defer core.FreeLOM(lom)
+	if err := lom.InitBck(&bck); err != nil {
+		tt.Fatal(err)
+	}
+	key := make([]byte, 32)
+	cryptorand.Read(key)
+	fh, err := lom.CreateWorkSSEC(lom.FQN, key)
+	if err != nil {
+		tt.Fatal(err)
+	}
+	if _, err := fh.Write([]byte("secret")); err != nil {
+		tt.Fatal(err)
+	}
+	cos.Close(fh)
+	lom.SetSize(6)
+	lom.SetAtimeUnix(time.Now().UnixNano())
+	if err := lom.Persist(); err != nil {
+		tt.Fatal(err)
+	}
+	defer lom.RemoveMain()
+	if !sse.IsCustomer(lom.SSERef()) {
+		tt.Fatalf("expected %s to be encrypted with client-supplied key", lom.Cname())
+	}
+
+	for _, op := range []string{apc.AppendOp, apc.FlushOp, apc.ExtendOp} {
+		r, err := http.NewRequest(http.MethodPut, "/", http.NoBody)
+		if err != nil {
+			tt.Fatal(err)
+		}
+		a := &apndOI{
+			started: time.Now().UnixNano(),
+			t:       t,
+			lom:     lom,
+			r:       io.NopCloser(bytes.NewReader([]byte("more"))),
+			resphdr: make(http.Header),
+			op:      op,
+		}
+		if _, ecode, err := a.do(r); err == nil || ecode != http.StatusBadRequest {
+			tt.Fatalf("%s(%s): expected %d error, got %d (%v)", op, lom.Cname(), http.StatusBadRequest, ecode, err)
+		}
+	}
+}
+
+// object lock (WORM): copies, transforms, and other PUT-like writes onto a locked destination are rejected
+func TestObjCopyLocked(tt *testing.T) {
+	bck := meta.NewBck(testLockBucket, apc.AIS, cmn.NsGlobal)
+	if err := bck.Init(t.owner.bmd); err != nil {
+		tt.Fatal(err)
+	}
+	put := func(objName, content string, retainUntil int64) *core.LOM {
+		tt.Helper()
+		lom := core.AllocLOM(objName)
+		if err := lom.InitBck(bck.Bucket()); err != nil {
+			tt.Fatal(err)
+		}
+		if retainUntil > 0 {
+			lom.SetCustomKey(cmn.RetainUntilObjMD, strconv.FormatInt(retainUntil, 10))
+		}
+		params := core.AllocPutParams()
+		{
+			params.WorkTag = fs.WorkfilePut
+			params.Reader = io.NopCloser(bytes.NewReader([]byte(content)))
+			params.OWT = cmn.OwtPut
+			params.Atime = time.Now()
+		}
+		err := t.PutObject(lom, params)
+		core.FreePutParams(params)
+		if err != nil {
+			tt.Fatal(err)
+		}
+		return lom
+	}
+	var (
+		src = put("src", "new content", 0)
+		dst = put("dst", "locked content", time.Now().Add(time.Hour).Unix())
+	)
+	defer func() {
+		src.RemoveMain()
+		dst.RemoveMain()
+		core.FreeLOM(src)
+		core.FreeLOM(dst)
+	}()
+
+	// copy (fast path)
+	buf := make([]byte, 32*cos.KiB)
+	coiParams := &xs.CoiParams{BckTo: bck, ObjnameTo: dst.ObjName, OWT: cmn.OwtCopy, Config: cmn.GCO.Get(), Buf: buf}
+	if _, err := (*coi)(coiParams).do(t, nil /*DM*/, src); !cmn.IsErrObjLocked(err) {
+		tt.Fatalf("copy %s => %s: expected object locked, got %v", src.Cname(), dst.Cname(), err)
+	}
+	// copy via data provider
+	coiParams.DP = &core.LDP{}
+	if _, err := (*coi)(coiParams).do(t, nil /*DM*/, src); !cmn.IsErrObjLocked(err) {
+		tt.Fatalf("copy(DP) %s => %s: expected object locked, got %v", src.Cname(), dst.Cname(), err)
+	}
+
+	// same via PutObject on behalf of x-tcb, ETL, promote, ... (but not rebalance)
+	for _, owt := range []cmn.OWT{cmn.OwtCopy, cmn.OwtTransform, cmn.OwtPromote} {
+		lom := core.AllocLOM(dst.ObjName)
+		if err := lom.InitBck(bck.Bucket()); err != nil {
+			tt.Fatal(err)
+		}
+		params := core.AllocPutParams()
+		{
+			params.WorkTag = fs.WorkfilePut
+			params.Reader = io.NopCloser(bytes.NewReader([]byte("overwrite")))
+			params.OWT = owt
+			params.Atime = time.Now()
+		}
+		err := t.PutObject(lom, params)
+		core.FreePutParams(params)
+		core.FreeLOM(lom)
+		if !cmn.IsErrObjLocked(err) {
+			tt.Fatalf("%s %s: expected object locked, got %v", owt, dst.Cname(), err)
+		}
+	}
+
+	// the destination is intact
+	lom := core.AllocLOM(dst.ObjName)
+	defer core.FreeLOM(lom)
+	if err := lom.InitBck(bck.Bucket()); err != nil {
+		tt.Fatal(err)
+	}
+	if err := lom.Load(false, false); err != nil {
+		tt.Fatal(err)
+	}
+	if lom.Lsize() != int64(len("locked content")) {
+		tt.Fatalf("%s: expected intact content, got size %d", lom.Cname(), lom.Lsize())
+	}
+}
+
+// client-supplied keys are accepted over HTTPS only
+func TestParseSSEC(tt *testing.T) {
+	key := make([]byte, 32)
+	cryptorand.Read(key)
+	for _, scheme := range []string{"http", "https"} {
+		r := httptest.NewRequest(http.MethodGet, scheme+"://localhost/v1/objects/bck/obj", http.NoBody)
+		r.Header.Set(apc.HdrSSECustomerAlgo, sse.CustomerAlgo)
+		r.Header.Set(apc.HdrSSECustomerKey, base64.StdEncoding.EncodeToString(key))
+		k, err := parseSSEC(r)
+		switch {
+		case scheme == "http" && err != sse.ErrCustomerKeyInsecure:
+			tt.Fatalf("%s: expected %v, got %v", scheme, sse.ErrCustomerKeyInsecure, err)
+		case scheme == "https" && (err != nil || !bytes.Equal(k, key)):
+			tt.Fatalf("%s: expected valid key, got %v", scheme, err)
+		}
+		// no key, no problem
+		r = httptest.NewRequest(http.MethodGet, scheme+"://localhost/v1/objects/bck/obj", http.NoBody)
+		if k, err := parseSSEC(r); k != nil || err != nil {
+			tt.Fatalf("%s: expected no key, got %v", scheme, err)
+		}
+	}
+}
+
 func BenchmarkObjPut(b *testing.B) {
 	benches := []struct {
 		fileSize int64
@@ -0,0 +1,161 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Object lock (WORM), see cmn.ObjLockConf:
+// - locked objects cannot be overwritten, appended, renamed, or deleted until their retention expires;
+// - in governance mode, deletion can be forced (apc.QparamBypassGovernance) by a user with admin permission;
+// - retention can be extended (apc.ActExtendRetain) but not shortened, in either mode;
+// - buckets that contain objects under retention cannot be destroyed, evicted, or renamed
+//   (in governance mode, object lock can be disabled first);
+// - remote buckets: enforced for in-cluster objects only.
+
+// given loaded lom, returns cmn.ErrObjLocked when under retention
+func checkRetention(lom *core.LOM, bypass bool) error {
+	mode := lom.Bprops().ObjLock.Mode
+	if mode == "" {
+		return nil
+	}
+	until, err := lom.ObjAttrs().RetainUntil()
+	if err != nil {
+		return err
+	}
+	if until <= time.Now().Unix() {
+		return nil
+	}
+	if bypass && mode == apc.ObjLockGovernance {
+		return nil
+	}
+	return cmn.NewErrObjLocked(lom.Cname(), mode, until)
+}
+
+// overwrite (PUT, APPEND, rename) of a possibly existing object
+func checkOverwrite(lom *core.LOM) error {
+	if lom.Bprops().ObjLock.Mode == "" {
+		return nil
+	}
+	if err := lom.Load(true /*cache it*/, false /*locked*/); err != nil {
+		return nil // doesn't exist (or can't be loaded) - nothing to protect
+	}
+	return checkRetention(lom, false)
+}
+
+// same as above without loading the lom that may already carry the new content's metadata
+// (copy, transform, promote, and other PUT-like writes - see PutObject and coi.do)
+func checkOverwriteDst(lom *core.LOM) error {
+	if lom.Bprops().ObjLock.Mode == "" {
+		return nil
+	}
+	prev := core.AllocLOM(lom.ObjName)
+	defer core.FreeLOM(prev)
+	if err := prev.InitBck(lom.Bucket()); err != nil {
+		return nil
+	}
+	return checkOverwrite(prev)
+}
+
+// destroy, evict, or rename a bucket: returns cmn.ErrObjLocked for the first
+// (in-cluster) object under retention
+func checkBckRetention(bck *meta.Bck) error {
+	if bck.Props == nil || bck.Props.ObjLock.Mode == "" {
+		return nil
+	}
+	cb := func(fqn string, de fs.DirEntry) error {
+		if de.IsDir() {
+			return nil
+		}
+		lom := core.AllocLOM("")
+		defer core.FreeLOM(lom)
+		if err := lom.InitFQN(fqn, bck.Bucket()); err != nil {
+			return nil
+		}
+		if err := lom.Load(false /*cache it*/, false /*locked*/); err != nil {
+			return nil
+		}
+		return checkRetention(lom, false)
+	}
+	for _, mi := range fs.GetAvail() {
+		opts := &fs.WalkOpts{Mi: mi, Bck: *bck.Bucket(), CTs: []string{fs.ObjectType}, Callback: cb}
+		if err := fs.Walk(opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// custom metadata update (PATCH) must not remove or shorten active retention
+func checkPatchRetention(lom *core.LOM, custom cos.StrKVs, replace bool) (int, error) {
+	mode := lom.Bprops().ObjLock.Mode
+	if mode == "" {
+		return 0, nil
+	}
+	until, _ := lom.ObjAttrs().RetainUntil()
+	if until <= time.Now().Unix() {
+		return 0, nil
+	}
+	v, ok := custom[cmn.RetainUntilObjMD]
+	if !ok {
+		if replace {
+			return http.StatusForbidden, cmn.NewErrObjLocked(lom.Cname(), mode, until)
+		}
+		return 0, nil
+	}
+	n, err := cmn.ParseRetainUntil(v)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	if n < until {
+		return http.StatusForbidden, cmn.NewErrObjLocked(lom.Cname(), mode, until)
+	}
+	return 0, nil
+}
+
+// POST /v1/objects/bucket-name/object-name (apc.ActExtendRetain)
+func (t *target) extendRetention(lom *core.LOM, msg *apc.ActMsg) (int, error) {
+	var rmsg apc.RetentionMsg
+	if err := cos.MorphMarshal(msg.Value, &rmsg); err != nil {
+		return http.StatusBadRequest, fmt.Errorf(cmn.FmtErrMorphUnmarshal, t, msg.Action, msg.Value, err)
+	}
+	if lom.Bprops().ObjLock.Mode == "" {
+		return http.StatusBadRequest, fmt.Errorf("%s: bucket %s does not have object lock enabled", t, lom.Bck().Cname(""))
+	}
+	if rmsg.RetainUntil <= time.Now().Unix() {
+		return http.StatusBadRequest, fmt.Errorf("%s: retention of %s must be extended into the future (have %s)",
+			t, lom.Cname(), time.Unix(rmsg.RetainUntil, 0).UTC().Format(time.RFC3339))
+	}
+
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		if cos.IsNotExist(err, 0) {
+			return http.StatusNotFound, err
+		}
+		return 0, err
+	}
+	until, err := lom.ObjAttrs().RetainUntil()
+	if err != nil {
+		return 0, err
+	}
+	if rmsg.RetainUntil < until {
+		return http.StatusForbidden, fmt.Errorf("%s: cannot shorten retention of %s (from %s to %s)", t, lom.Cname(),
+			time.Unix(until, 0).UTC().Format(time.RFC3339), time.Unix(rmsg.RetainUntil, 0).UTC().Format(time.RFC3339))
+	}
+	lom.SetCustomKey(cmn.RetainUntilObjMD, strconv.FormatInt(rmsg.RetainUntil, 10))
+	return 0, lom.Persist()
+}
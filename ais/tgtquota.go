@@ -0,0 +1,85 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/NVIDIA/aistore/api/apc"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// custom metadata keys that are maintained by the system and cannot be set by users
+func errReservedCustomKey(key string) error {
+	return fmt.Errorf("custom property %q is reserved (and cannot be set or modified)", key)
+}
+
+// user-provided custom metadata (apc.HdrObjCustomMD)
+func hdrHasCustomKey(hdr http.Header, key string) bool {
+	for _, v := range hdr[http.CanonicalHeaderKey(apc.HdrObjCustomMD)] {
+		if k, _, _ := strings.Cut(v, "="); k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// quota limits passed by the redirecting proxy (see proxy.checkPutQuota)
+func (poi *putOI) setQuota(dpq *dpq, hdr http.Header) error {
+	if dpq.quota.objs == "" && dpq.quota.size == "" {
+		return nil
+	}
+	size, _, _, errFs := poi.lom.Fstat(false)
+	exists := errFs == nil
+
+	// object count limit reached: overwrite only
+	if dpq.quota.objs != "" && !exists {
+		tag, limit, usage, err := parseQuotaHint(dpq.quota.objs)
+		if err != nil {
+			return err
+		}
+		return poi.quotaErr(tag, cmn.QuotaObjects, limit, usage, hdr)
+	}
+
+	// unknown size: limit the content to the remaining capacity (plus the size of the object that gets overwritten)
+	if dpq.quota.size != "" && poi.oreq.ContentLength < 0 {
+		tag, limit, usage, err := parseQuotaHint(dpq.quota.size)
+		if err != nil {
+			return err
+		}
+		room := limit - usage
+		if exists {
+			room += size
+		}
+		poi.r = &quotaReader{ReadCloser: poi.r, room: room, err: poi.quotaErr(tag, cmn.QuotaSize, limit, usage, hdr)}
+	}
+	return nil
+}
+
+func (poi *putOI) quotaErr(tag, what string, limit, usage int64, hdr http.Header) *cmn.ErrQuotaExceeded {
+	if tag == quotaHintBck {
+		return cmn.NewErrBckQuotaExceeded(poi.lom.Bck().Cname(""), what, limit, usage)
+	}
+	return cmn.NewErrUserQuotaExceeded(poi.t.reqUser(hdr), what, limit, usage)
+}
+
+// fails reading past the remaining capacity
+type quotaReader struct {
+	io.ReadCloser
+	err  *cmn.ErrQuotaExceeded
+	room int64
+}
+
+func (qr *quotaReader) Read(b []byte) (n int, err error) {
+	n, err = qr.ReadCloser.Read(b)
+	if qr.room -= int64(n); qr.room < 0 {
+		return n, qr.err
+	}
+	return n, err
+}
@@ -0,0 +1,164 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/xact/xreg"
+	"github.com/NVIDIA/aistore/xact/xs"
+)
+
+// cross-cluster bucket replication (see cmn.ReplicationConf and xs.XactRepl)
+
+const replResyncIval = 10 * time.Minute
+
+// active-active: replicated write from the peer cluster (apc.HdrObjReplVV)
+type replIn struct {
+	vv  cmn.VersionVector
+	w   cmn.ReplWrite
+	fwd bool // resolved: replicate back to the peer (see xs.SetReplState)
+}
+
+// replicate new or updated object (compare with putMirror)
+func (t *target) replicate(lom *core.LOM) {
+	if !lom.Bprops().Replication.Enabled {
+		return
+	}
+	rns := xreg.RenewReplicate(lom.Bck())
+	if rns.Err != nil {
+		nlog.Errorln(t.String(), lom.Cname(), rns.Err)
+		return
+	}
+	xrepl := rns.Entry.Get().(*xs.XactRepl)
+	xrepl.Repl(lom)
+}
+
+// replicate objects that were not replicated yet or modified since
+// (e.g., upon restart, when replication gets enabled, and periodically - to retry failures)
+func replResync(bck *meta.Bck) {
+	rns := xreg.RenewReplicate(bck)
+	if rns.Err != nil {
+		nlog.Errorln(bck.Cname(""), "resync:", rns.Err)
+		return
+	}
+	xrepl := rns.Entry.Get().(*xs.XactRepl)
+	xrepl.Resync()
+}
+
+// housekeeping callback
+func (t *target) replHK(int64) time.Duration {
+	if smap := t.owner.smap.get(); !smap.isValid() || smap.InMaintOrDecomm(t.si) {
+		return replResyncIval
+	}
+	provider := apc.AIS
+	t.owner.bmd.get().Range(&provider, nil, func(bck *meta.Bck) bool {
+		if bck.Props.Replication.Enabled {
+			replResync(bck)
+		}
+		return false
+	})
+	return replResyncIval
+}
+
+//
+// active-active: receiving side
+//
+
+func (poi *putOI) setReplIn(hdr http.Header) error {
+	vvs := hdr.Get(apc.HdrObjReplVV)
+	if vvs == "" || !poi.lom.Bprops().Replication.ActiveActive {
+		return nil // (not replicating back: plain PUT)
+	}
+	vv, w, err := cmn.ParseReplHdr(vvs, hdr.Get(apc.HdrObjReplWrite))
+	if err != nil {
+		return err
+	}
+	poi.repl = &replIn{vv: vv, w: w}
+	return nil
+}
+
+// under wlock: compare the incoming version vector with the local one and decide;
+// returns false when the incoming write is to be discarded
+func (poi *putOI) replResolve() (bool, error) {
+	var (
+		lom = poi.lom
+		in  = poi.repl
+		cur = core.AllocLOM(lom.ObjName)
+	)
+	defer core.FreeLOM(cur)
+	if err := cur.InitBck(lom.Bucket()); err != nil {
+		return false, err
+	}
+	if err := cur.Load(true /*cache it*/, true /*locked*/); err != nil {
+		if !cos.IsNotExist(err, 0) {
+			return false, err
+		}
+		poi.replAccept(in.vv) // new
+		return true, nil
+	}
+	_, _, mtime, err := cur.Fstat(false /*get atime*/)
+	if err != nil {
+		return false, err
+	}
+	vv, w, err := xs.ReplLocalVV(cur, mtime, poi.t.owner.smap.get().UUID)
+	if err != nil {
+		return false, err
+	}
+	switch vv.Compare(in.vv) {
+	case cmn.VVOlder:
+		poi.replAccept(in.vv)
+		return true, nil
+	case cmn.VVEqual, cmn.VVNewer:
+		return false, nil // (the local version, if newer, gets replicated on its own)
+	}
+
+	// concurrent updates
+	var (
+		conf   = &lom.Bprops().Replication
+		merged = vv.Merge(in.vv)
+		vlabs  = map[string]string{stats.VarlabBucket: lom.Bck().Cname("")}
+	)
+	poi.t.statsT.AddWith(cos.NamedVal64{Name: stats.ReplConflictCount, Value: 1, VarLabs: vlabs})
+	in.fwd = true // either way, the peer needs the merged version vector
+	if conf.IncomingWins(w, in.w) {
+		if cmn.Rom.FastV(4, cos.SmoduleAIS) {
+			nlog.Infoln(poi.t.String(), "conflict:", lom.Cname(), "incoming wins [", vv.String(), in.vv.String(), "]")
+		}
+		poi.replAccept(merged)
+		return true, nil
+	}
+	if cmn.Rom.FastV(4, cos.SmoduleAIS) {
+		nlog.Infoln(poi.t.String(), "conflict:", lom.Cname(), "local wins [", vv.String(), in.vv.String(), "]")
+	}
+	cur.SetCustomKey(cmn.ReplVVObjMD, merged.String())
+	cur.SetCustomKey(cmn.ReplWriteObjMD, w.String())
+	xs.SetReplState(cur, mtime, true /*fwd*/)
+	return false, cur.Persist()
+}
+
+func (poi *putOI) replAccept(vv cmn.VersionVector) {
+	poi.lom.SetCustomKey(cmn.ReplVVObjMD, vv.String())
+	poi.lom.SetCustomKey(cmn.ReplWriteObjMD, poi.repl.w.String())
+}
+
+// incoming write discarded: the local version stays; when resolved in its favor,
+// replicate it back (with merged version vector)
+func (poi *putOI) replDiscarded() {
+	if cmn.Rom.FastV(5, cos.SmoduleAIS) {
+		nlog.Infoln(poi.loghdr(), "replicated write discarded [ fwd:", poi.repl.fwd, "]")
+	}
+	if poi.repl.fwd {
+		poi.t.replicate(poi.lom)
+	}
+}
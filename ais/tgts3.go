@@ -191,6 +191,14 @@ func (t *target) putObjS3(w http.ResponseWriter, r *http.Request, bck *meta.Bck,
 			return
 		}
 	}
+	if err := checkOverwrite(lom); err != nil {
+		s3.WriteErr(w, r, err, http.StatusForbidden)
+		return
+	}
+	var prevSize int64 = -1
+	if size, _, _, err := lom.Fstat(false); err == nil {
+		prevSize = size
+	}
 	started := time.Now()
 	lom.SetAtimeUnix(started.UnixNano())
 
@@ -217,6 +225,7 @@ func (t *target) putObjS3(w http.ResponseWriter, r *http.Request, bck *meta.Bck,
 		t.FSHC(err, lom.Mountpath(), lom.FQN)
 		s3.WriteErr(w, r, err, ecode)
 	} else {
+		t.quotaPut(lom, prevSize)
 		s3.SetS3Headers(w.Header(), lom)
 	}
 	dpqFree(dpq)
@@ -313,6 +322,10 @@ func (t *target) headObjS3(w http.ResponseWriter, r *http.Request, items []strin
 		op  cmn.ObjectProps
 	)
 	if exists {
+		if ecode, err := headSSEC(r, hdr, lom, true /*S3*/); err != nil {
+			s3.WriteErr(w, r, err, ecode)
+			return
+		}
 		op.ObjAttrs = *lom.ObjAttrs()
 	} else {
 		// cold HEAD
@@ -12,8 +12,8 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/NVIDIA/aistore/ais/backend"
@@ -64,6 +64,17 @@ func (t *target) startMpt(w http.ResponseWriter, r *http.Request, items []string
 		s3.WriteErr(w, r, err, 0)
 		return
 	}
+	// additional checksum algorithm, if any
+	algo := strings.ToUpper(r.Header.Get(cos.S3HdrChecksumAlgo))
+	if err := s3.ValidateCksumAlgo(algo); err != nil {
+		s3.WriteErr(w, r, err, http.StatusBadRequest)
+		return
+	}
+	if r.Header.Get(cos.S3HdrSSECustomerAlgo) != "" {
+		err := cmn.NewErrUnsupp("multipart-upload", "with client-supplied encryption key (SSE-C)")
+		s3.WriteErr(w, r, err, http.StatusBadRequest)
+		return
+	}
 	if bck.IsRemoteS3() {
 		uploadID, ecode, err = backend.StartMpt(lom, r, q)
 		if err != nil {
@@ -74,12 +85,15 @@ func (t *target) startMpt(w http.ResponseWriter, r *http.Request, items []string
 		uploadID = cos.GenUUID()
 	}
 
-	s3.InitUpload(uploadID, bck.Name, objName)
+	s3.InitUpload(uploadID, bck.Name, objName, algo)
 	result := &s3.InitiateMptUploadResult{Bucket: bck.Name, Key: objName, UploadID: uploadID}
 
 	sgl := t.gmm.NewSGL(0)
 	result.MustMarshal(sgl)
 	w.Header().Set(cos.HdrContentType, cos.ContentXML)
+	if algo != "" {
+		w.Header().Set(cos.S3HdrChecksumAlgo, algo)
+	}
 	sgl.WriteTo2(w)
 	sgl.Free()
 }
@@ -90,6 +104,9 @@ func (t *target) startMpt(w http.ResponseWriter, r *http.Request, items []string
 // "Content-MD5" in the part headers seems be to be deprecated:
 // either not present (s3cmd) or cannot be trusted (aws s3api).
 //
+// Additional checksum (x-amz-checksum-*), if specified for the part or the upload, is computed,
+// validated, and returned - see s3/cksum.go.
+//
 // https://docs.aws.amazon.com/AmazonS3/latest/API/API_UploadPart.html
 func (t *target) putMptPart(w http.ResponseWriter, r *http.Request, items []string, q url.Values, bck *meta.Bck) {
 	var (
@@ -119,6 +136,13 @@ func (t *target) putMptPart(w http.ResponseWriter, r *http.Request, items []stri
 		return
 	}
 
+	// additional checksum
+	pcksum, err := s3.NewPartCksum(uploadID, r.Header)
+	if err != nil {
+		s3.WriteErr(w, r, err, http.StatusBadRequest)
+		return
+	}
+
 	// 2. init lom, create part file
 	objName := s3.ObjName(items)
 	lom := &core.LOM{ObjName: objName}
@@ -153,7 +177,11 @@ func (t *target) putMptPart(w http.ResponseWriter, r *http.Request, items []stri
 	}
 
 	// 3. write
-	mw := multiWriter(cksumMD5.H, cksumSHA.H, partFh)
+	var hpart io.Writer
+	if pcksum != nil {
+		hpart = pcksum.H
+	}
+	mw := multiWriter(cksumMD5.H, cksumSHA.H, hpart, partFh)
 
 	if !remote {
 		// write locally
@@ -180,7 +208,7 @@ func (t *target) putMptPart(w http.ResponseWriter, r *http.Request, items []stri
 	}
 
 	// 4. finalize the part (expecting the part's remote etag to be md5 checksum)
-	md5 := etag
+	md5 := cmn.UnquoteCEV(etag)
 	if cksumMD5.H != nil {
 		debug.Assert(etag == "")
 		cksumMD5.Finalize()
@@ -202,11 +230,31 @@ func (t *target) putMptPart(w http.ResponseWriter, r *http.Request, items []stri
 		Size: size,
 		Num:  partNum,
 	}
-	if err := s3.AddPart(uploadID, npart); err != nil {
+	if pcksum != nil {
+		if npart.Cksum, err = pcksum.Finalize(uploadID, partNum); err != nil {
+			if nerr := cos.RemoveFile(wfqn); nerr != nil && !os.IsNotExist(nerr) {
+				nlog.Errorf(fmtNested, t, err, "remove", wfqn, nerr)
+			}
+			s3.WriteMptErr(w, r, err, http.StatusBadRequest, lom, uploadID)
+			return
+		}
+		npart.CksumAlgo = pcksum.Algo
+	}
+	prev, err := s3.AddPart(uploadID, npart)
+	if err != nil {
 		s3.WriteMptErr(w, r, err, 0, lom, uploadID)
 		return
 	}
+	if prev != nil {
+		// replaced (re-uploaded) part
+		if nerr := cos.RemoveFile(prev.FQN); nerr != nil && !os.IsNotExist(nerr) {
+			nlog.Errorln("failed to remove replaced part [", prev.FQN, uploadID, nerr, "]")
+		}
+	}
 	w.Header().Set(cos.S3CksumHeader, md5) // s3cmd checks this one
+	if pcksum != nil {
+		w.Header().Set(pcksum.HdrName(), npart.Cksum)
+	}
 
 	delta := mono.SinceNano(startTime)
 	vlabs := map[string]string{stats.VarlabBucket: bck.Cname(""), stats.VarlabXactKind: "", stats.VarlabXactID: ""}
@@ -227,8 +275,8 @@ func (t *target) putMptPart(w http.ResponseWriter, r *http.Request, items []stri
 
 // Complete multipart upload.
 // Body contains XML with the list of parts that must be on the storage already.
-// 1. Check that all parts from request body present
-// 2. Merge all parts into a single file and calculate its ETag
+// 1. Check that all parts from request body present (and, if specified, their ETags and checksums match)
+// 2. Merge all parts into a single file and calculate its ETag (and composite checksum, if any)
 // 3. Return ETag to a caller
 // https://docs.aws.amazon.com/AmazonS3/latest/API/API_CompleteMultipartUpload.html
 func (t *target) completeMpt(w http.ResponseWriter, r *http.Request, items []string, q url.Values, bck *meta.Bck) {
@@ -259,12 +307,30 @@ func (t *target) completeMpt(w http.ResponseWriter, r *http.Request, items []str
 		s3.WriteErr(w, r, err, 0)
 		return
 	}
+	// object lock (WORM): check the existing object, if any, without loading it into the new one
+	if err := checkOverwriteDst(lom); err != nil {
+		s3.WriteErr(w, r, err, http.StatusForbidden)
+		return
+	}
 	size, errN := s3.ObjSize(uploadID)
 	if errN != nil {
 		s3.WriteMptErr(w, r, errN, 0, lom, uploadID)
 		return
 	}
 
+	// check parts: presence, order, ETags and additional checksums (if specified);
+	// compute composite checksum, if any
+	nparts, algo, err := s3.CheckParts(uploadID, partList.Parts)
+	if err != nil {
+		s3.WriteMptErr(w, r, err, http.StatusBadRequest, lom, uploadID)
+		return
+	}
+	compositeCksum, err := s3.CompositeCksum(algo, nparts)
+	if err != nil {
+		s3.WriteMptErr(w, r, err, http.StatusBadRequest, lom, uploadID)
+		return
+	}
+
 	// call s3
 	var (
 		version string
@@ -285,19 +351,9 @@ func (t *target) completeMpt(w http.ResponseWriter, r *http.Request, items []str
 	// append parts and finalize locally
 	var (
 		mw          io.Writer
-		concatMD5   string // => ETag
 		actualCksum = &cos.CksumHash{}
 	)
-	// .1 sort and check parts
-	sort.Slice(partList.Parts, func(i, j int) bool {
-		return *partList.Parts[i].PartNumber < *partList.Parts[j].PartNumber
-	})
-	nparts, err := s3.CheckParts(uploadID, partList.Parts)
-	if err != nil {
-		s3.WriteMptErr(w, r, err, 0, lom, uploadID)
-		return
-	}
-	// 2. <upload-id>.complete.<obj-name>
+	// .1 <upload-id>.complete.<obj-name>
 	prefix := uploadID + ".complete"
 	wfqn := fs.CSM.Gen(lom, fs.WorkfileType, prefix)
 	wfh, errC := lom.CreateWork(wfqn)
@@ -312,16 +368,18 @@ func (t *target) completeMpt(w http.ResponseWriter, r *http.Request, items []str
 	}
 	mw = multiWriter(actualCksum.H, wfh)
 
-	// .3 write
+	// .2 write
 	buf, slab := t.gmm.Alloc()
-	concatMD5, written, errA := _appendMpt(nparts, buf, mw)
+	written, errA := _appendMpt(nparts, buf, mw)
 	slab.Free(buf)
 
 	if lom.IsFeatureSet(feat.FsyncPUT) {
 		errS := wfh.Sync()
 		debug.AssertNoErr(errS)
 	}
-	cos.Close(wfh)
+	if errF := wfh.Close(); errA == nil {
+		errA = errF
+	}
 
 	if errA == nil && written != size {
 		errA = fmt.Errorf("upload %q %q: expected full size=%d, got %d", uploadID, lom.Cname(), size, written)
@@ -334,22 +392,23 @@ func (t *target) completeMpt(w http.ResponseWriter, r *http.Request, items []str
 		return
 	}
 
-	// .4 (s3 client => ais://) compute resulting MD5 and, optionally, ETag
+	// .3 (s3 client => ais://) compute resulting MD5 and, optionally, ETag
 	if actualCksum.H != nil {
 		actualCksum.Finalize()
 		lom.SetCksum(actualCksum.Cksum.Clone())
 	}
 	if etag == "" {
 		debug.Assert(!remote)
-		debug.Assert(concatMD5 != "")
-		resMD5 := cos.NewCksumHash(cos.ChecksumMD5)
-		_, err = resMD5.H.Write([]byte(concatMD5))
-		debug.AssertNoErr(err)
-		resMD5.Finalize()
-		etag = `"` + resMD5.Value() + cmn.AwsMultipartDelim + strconv.Itoa(len(partList.Parts)) + `"`
+		if etag, err = s3.CompositeETag(nparts); err != nil {
+			if nerr := cos.RemoveFile(wfqn); nerr != nil && !os.IsNotExist(nerr) {
+				nlog.Errorf(fmtNested, t, err, "remove", wfqn, nerr)
+			}
+			s3.WriteMptErr(w, r, err, http.StatusBadRequest, lom, uploadID)
+			return
+		}
 	}
 
-	// .5 finalize
+	// .4 finalize
 	lom.SetSize(size)
 	if remote {
 		lom.SetCustomKey(cmn.SourceObjMD, apc.AWS)
@@ -370,7 +429,7 @@ func (t *target) completeMpt(w http.ResponseWriter, r *http.Request, items []str
 	ecode, errF := poi.finalize()
 	freePOI(poi)
 
-	// .6 cleanup parts - unconditionally
+	// .5 cleanup parts - unconditionally
 	exists := s3.CleanupUpload(uploadID, lom.FQN, false /*aborted*/)
 	debug.Assert(exists)
 
@@ -383,8 +442,9 @@ func (t *target) completeMpt(w http.ResponseWriter, r *http.Request, items []str
 		nlog.Errorf("upload %q: failed to complete %s locally: %v(%d)", uploadID, lom.Cname(), err, ecode)
 	}
 
-	// .7 respond
+	// .6 respond
 	result := &s3.CompleteMptUploadResult{Bucket: bck.Name, Key: objName, ETag: etag}
+	result.SetCompositeCksum(algo, compositeCksum)
 	sgl := t.gmm.NewSGL(0)
 	result.MustMarshal(sgl)
 	w.Header().Set(cos.HdrContentType, cos.ContentXML)
@@ -400,24 +460,23 @@ func (t *target) completeMpt(w http.ResponseWriter, r *http.Request, items []str
 	}
 }
 
-func _appendMpt(nparts []*s3.MptPart, buf []byte, mw io.Writer) (concatMD5 string, written int64, err error) {
+func _appendMpt(nparts []*s3.MptPart, buf []byte, mw io.Writer) (written int64, err error) {
 	for _, partInfo := range nparts {
 		var (
 			partFh   *os.File
 			partSize int64
 		)
-		concatMD5 += partInfo.MD5
 		if partFh, err = os.Open(partInfo.FQN); err != nil {
-			return "", 0, err
+			return 0, err
 		}
 		partSize, err = io.CopyBuffer(mw, partFh, buf)
 		cos.Close(partFh)
 		if err != nil {
-			return "", 0, err
+			return 0, err
 		}
 		written += partSize
 	}
-	return concatMD5, written, nil
+	return written, nil
 }
 
 // Abort an active multipart upload.
@@ -0,0 +1,143 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/space"
+)
+
+// bucket snapshots (see space/snapshot)
+
+// snapshots apply to ais buckets, excluding erasure-coded
+func validateSnapBck(bck *meta.Bck) error {
+	if !bck.IsAIS() {
+		return cmn.NewErrUnsupp("snapshot", bck.Cname("")+" (not an ais bucket)")
+	}
+	if bck.Props.EC.Enabled {
+		return cmn.NewErrUnsupp("snapshot", "erasure-coded bucket "+bck.Cname(""))
+	}
+	return nil
+}
+
+// POST /v1/buckets/bucket-name (apc.ActCreateSnapshot, et al.)
+func (t *target) snapshotBck(w http.ResponseWriter, r *http.Request, bck *meta.Bck, msg *apc.ActMsg) {
+	var smsg apc.SnapshotMsg
+	if err := cos.MorphMarshal(msg.Value, &smsg); err != nil {
+		t.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, t.si, msg.Action, msg.Value, err)
+		return
+	}
+	if err := smsg.Validate(); err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	if err := validateSnapBck(bck); err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	switch msg.Action {
+	case apc.ActCreateSnapshot:
+		if space.SnapExists(bck, smsg.Name) {
+			err := fmt.Errorf("%s: snapshot %q of %s already exists", t, smsg.Name, bck.Cname(""))
+			t.writeErr(w, r, err, http.StatusConflict)
+			return
+		}
+		snaps, err := space.CreateSnapshot(bck, smsg.Name, time.Now().UnixNano())
+		if err != nil {
+			t.writeErr(w, r, err)
+			return
+		}
+		if len(snaps) > 0 {
+			nlog.Infoln(t.String(), "created snapshot", smsg.Name, "of", bck.Cname(""), "[", snaps[0].NumObjs,
+				cos.ToSizeIEC(snaps[0].Size, 2), "]")
+		}
+	case apc.ActDestroySnapshot:
+		if !space.DestroySnapshot(bck, smsg.Name) {
+			t.writeErr(w, r, cos.NewErrNotFound(t, "snapshot "+smsg.Name+" of "+bck.Cname("")), http.StatusNotFound)
+			return
+		}
+		nlog.Infoln(t.String(), "destroyed snapshot", smsg.Name, "of", bck.Cname(""))
+	case apc.ActRestoreSnapshot:
+		if !space.SnapExists(bck, smsg.Name) {
+			t.writeErr(w, r, cos.NewErrNotFound(t, "snapshot "+smsg.Name+" of "+bck.Cname("")), http.StatusNotFound)
+			return
+		}
+		restored, removed, err := space.RestoreSnapshot(bck, smsg.Name, t.putMirror)
+		if err != nil {
+			t.writeErr(w, r, err)
+			return
+		}
+		nlog.Infoln(t.String(), "restored", bck.Cname(""), "from snapshot", smsg.Name, "[ restored:", restored,
+			"removed:", removed, "]")
+	default:
+		t.writeErrAct(w, r, msg.Action)
+	}
+}
+
+// GET /v1/buckets/bucket-name (apc.ActListSnapshots, apc.ActBrowseSnapshot)
+func (t *target) listSnapshots(w http.ResponseWriter, r *http.Request, bck *meta.Bck, msg *apc.ActMsg) {
+	var smsg apc.SnapshotMsg
+	if err := cos.MorphMarshal(msg.Value, &smsg); err != nil {
+		t.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, t.si, msg.Action, msg.Value, err)
+		return
+	}
+	if msg.Action == apc.ActListSnapshots {
+		snaps, err := space.ListSnapshots(bck, smsg.Name)
+		if err != nil {
+			t.writeErr(w, r, err)
+			return
+		}
+		t.writeJSON(w, r, snaps, msg.Action)
+		return
+	}
+	if err := smsg.Validate(); err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	entries, err := space.BrowseSnapshot(bck, smsg.Name, smsg.Prefix)
+	if err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	t.writeJSON(w, r, entries, msg.Action)
+}
+
+// GET /v1/objects/bucket-name/object-name?snapshot=name
+// (snapshotted objects are immutable - see lom.DetachMainTo - and don't require locking)
+func (t *target) getSnapObj(w http.ResponseWriter, lom *core.LOM, name string) error {
+	sfqn := space.FindSnap(lom, name)
+	if sfqn == "" {
+		return cos.NewErrNotFound(t, lom.Cname()+" in snapshot "+name)
+	}
+	oa, err := core.SnapAttrs(sfqn)
+	if err != nil {
+		return err
+	}
+	fh, err := os.Open(sfqn)
+	if err != nil {
+		return err
+	}
+	cmn.ToHeader(oa, w.Header(), oa.Size)
+	buf, slab := t.gmm.AllocSize(oa.Size)
+	_, err = io.CopyBuffer(w, fh, buf)
+	slab.Free(buf)
+	cos.Close(fh)
+	if err != nil {
+		// (response already started)
+		nlog.Warningln("GET", lom.Cname(), "from snapshot", name+":", err)
+	}
+	return nil
+}
@@ -174,3 +174,68 @@ func (t *target) runSpaceCleanup(xargs *xact.ArgsMsg, wg *sync.WaitGroup) fs.Cap
 	})
 	return space.RunCleanup(&ini)
 }
+
+func (t *target) runExpire(xargs *xact.ArgsMsg, wg *sync.WaitGroup) {
+	var (
+		ctlmsg  string
+		regToIC = xargs.ID != ""
+	)
+	if !regToIC {
+		xargs.ID = cos.GenUUID()
+	}
+	if len(xargs.Buckets) > 0 {
+		ctlmsg = fmt.Sprintf("%v", xargs.Buckets)
+	}
+	rns := xreg.RenewExpireObjs(xargs.ID, ctlmsg)
+	if rns.Err != nil || rns.IsRunning() {
+		debug.Assert(rns.Err == nil || cmn.IsErrXactUsePrev(rns.Err))
+		if wg != nil {
+			wg.Done()
+		}
+		return
+	}
+	xexp := rns.Entry.Get()
+	if regToIC && xexp.ID() == xargs.ID {
+		// pre-existing UUID: notify IC members
+		regMsg := xactRegMsg{UUID: xargs.ID, Kind: apc.ActExpireObjs, Srcs: []string{t.SID()}}
+		msg := t.newAmsgActVal(apc.ActRegGlobalXaction, regMsg)
+		t.bcastAsyncIC(msg)
+	}
+	ini := space.IniExp{
+		Xaction: xexp.(*space.XactExp),
+		WG:      wg,
+		Args:    xargs,
+	}
+	xexp.AddNotif(&xact.NotifXact{
+		Base: nl.Base{When: core.UponTerm, Dsts: []string{equalIC}, F: t.notifyTerm},
+		Xact: xexp,
+	})
+	space.RunExpire(&ini)
+}
+
+// periodically remove expired objects and enforce bucket lifecycle rules (housekeeping callback)
+func (t *target) expireHK(int64) time.Duration {
+	config := cmn.GCO.Get()
+	if config.Space.ExpireTime <= 0 {
+		return minAutoDetectInterval // disabled - recheck later
+	}
+	if smap := t.owner.smap.get(); !smap.isValid() || smap.InMaintOrDecomm(t.si) {
+		return minAutoDetectInterval
+	}
+	go t.runExpire(&xact.ArgsMsg{}, nil)
+	go xreg.RenewLifecycle()
+	return config.Space.ExpireTime.D()
+}
+
+// periodically recompute bucket and per-user usage (housekeeping callback)
+func (t *target) quotaHK(int64) time.Duration {
+	config := cmn.GCO.Get()
+	if config.Space.QuotaTime <= 0 {
+		return minAutoDetectInterval // disabled - recheck later
+	}
+	if smap := t.owner.smap.get(); !smap.isValid() || smap.InMaintOrDecomm(t.si) {
+		return minAutoDetectInterval
+	}
+	go xreg.RenewQuotaUsage(config)
+	return config.Space.QuotaTime.D()
+}
@@ -0,0 +1,102 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/space"
+)
+
+// soft-delete (see config.Space.TrashTime and space/trash)
+
+var trashPurging atomic.Bool
+
+// soft-delete applies to objects stored in ais buckets, excluding erasure-coded
+func softDelete(lom *core.LOM, config *cmn.Config) bool {
+	return config.Space.TrashTime > 0 && !lom.Bck().IsRemote() && !lom.ECEnabled()
+}
+
+// periodically purge soft-deleted objects (housekeeping callback)
+func (t *target) trashHK(int64) time.Duration {
+	config := cmn.GCO.Get()
+	trashTime := config.Space.TrashTime.D()
+	if trashTime <= 0 {
+		return minAutoDetectInterval // disabled (see also space.RunCleanup) - recheck later
+	}
+	ival := max(trashTime/4, minAutoDetectInterval)
+	if smap := t.owner.smap.get(); !smap.isValid() || smap.InMaintOrDecomm(t.si) {
+		return ival
+	}
+	if !trashPurging.CAS(false, true) {
+		return ival
+	}
+	go func() {
+		cnt, size := space.PurgeTrash(time.Now().Add(-trashTime).UnixNano())
+		if cnt > 0 {
+			nlog.Infoln(t.String(), "purged", cnt, "soft-deleted object(s),", cos.ToSizeIEC(size, 2))
+		}
+		trashPurging.Store(false)
+	}()
+	return ival
+}
+
+// POST /v1/objects/bucket-name/object-name (apc.ActUndelete)
+func (t *target) undelete(lom *core.LOM, msg *apc.ActMsg) (int, error) {
+	var umsg apc.UndeleteMsg
+	if msg.Value != nil {
+		if err := cos.MorphMarshal(msg.Value, &umsg); err != nil {
+			return http.StatusBadRequest, fmt.Errorf(cmn.FmtErrMorphUnmarshal, t, msg.Action, msg.Value, err)
+		}
+	}
+
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	err := lom.Load(false /*cache it*/, true /*locked*/)
+	switch {
+	case err == nil:
+		return http.StatusConflict, fmt.Errorf("%s: cannot undelete %s - the object exists", t, lom.Cname())
+	case !cos.IsNotExist(err, 0):
+		return 0, err
+	}
+	tfqn, deleted := space.FindTrash(lom, umsg.Deleted)
+	if tfqn == "" {
+		return http.StatusNotFound, cos.NewErrNotFound(t, "soft-deleted "+lom.Cname())
+	}
+	buf, slab := t.gmm.Alloc()
+	err = lom.RestoreFromTrash(tfqn, buf)
+	slab.Free(buf)
+	if err != nil {
+		return 0, err
+	}
+	if cmn.Rom.FastV(4, cos.SmoduleAIS) {
+		nlog.Infoln(t.String(), "undeleted", lom.Cname(), "deleted at", time.Unix(0, deleted))
+	}
+	return 0, nil
+}
+
+// GET /v1/buckets/bucket-name (apc.ActListTrash)
+func (t *target) listTrash(w http.ResponseWriter, r *http.Request, bck *meta.Bck, msg *apc.ActMsg) {
+	var ltmsg apc.ListTrashMsg
+	if err := cos.MorphMarshal(msg.Value, &ltmsg); err != nil {
+		t.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, t.si, msg.Action, msg.Value, err)
+		return
+	}
+	entries, err := space.ListTrash(bck, ltmsg.Prefix, cmn.GCO.Get().Space.TrashTime.D())
+	if err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	t.writeJSON(w, r, entries, apc.ActListTrash)
+}
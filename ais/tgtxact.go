@@ -144,7 +144,7 @@ func (t *target) httpxput(w http.ResponseWriter, r *http.Request) {
 		if xid != "" {
 			writeXid(w, xid)
 		}
-	case apc.ActXactStop:
+	case apc.ActXactStop, apc.ActXactPause, apc.ActXactResume:
 		if xargs.Kind != "" {
 			if err := xact.CheckValidKind(xargs.Kind); err != nil {
 				t.writeErrf(w, r, "%v: %s", err, xargs.String())
@@ -158,21 +158,126 @@ func (t *target) httpxput(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 		if xargs.Kind == "" && xargs.ID == "" {
-			t.writeErrf(w, r, "cannot stop xaction given '%s' - expecting a valid kind and/or UUID", xargs.String())
+			t.writeErrf(w, r, "cannot %s xaction given '%s' - expecting a valid kind and/or UUID", msg.Action, xargs.String())
+			return
+		}
+		flt := xreg.Flt{ID: xargs.ID, Kind: xargs.Kind, Bck: bck}
+		if msg.Action != apc.ActXactStop {
+			t.xpause(w, r, msg.Action, flt)
 			return
 		}
 
 		err := cmn.ErrXactUserAbort
-		if msg.Name == cmn.ErrXactICNotifAbort.Error() {
+		switch msg.Name {
+		case cmn.ErrXactICNotifAbort.Error():
 			err = cmn.ErrXactICNotifAbort
+		case cmn.ErrXactOrphaned.Error():
+			err = cmn.ErrXactOrphaned
 		}
-		flt := xreg.Flt{ID: xargs.ID, Kind: xargs.Kind, Bck: bck}
 		xreg.DoAbort(flt, err)
+	case apc.ActXactRateLimit:
+		var ctl xact.TCBCtlMsg
+		if err := cos.MorphMarshal(msg.Value, &ctl); err != nil {
+			t.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, t.si, msg.Action, msg.Value, err)
+			return
+		}
+		t.xtcbctl(w, r, msg.Action, &ctl)
 	default:
 		t.writeErrAct(w, r, msg.Action)
 	}
 }
 
+// control running x-tcb; finished (on this target) is not an error
+func (t *target) xtcbctl(w http.ResponseWriter, r *http.Request, action string, ctl *xact.TCBCtlMsg) {
+	xctn, err := xreg.GetXact(ctl.ID)
+	if err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	if xctn == nil {
+		t.writeErr(w, r, cmn.NewErrXactNotFoundError("["+ctl.ID+"]"), http.StatusNotFound)
+		return
+	}
+	xtcb, ok := xctn.(*xs.XactTCB)
+	if !ok {
+		t.writeErrf(w, r, "cannot %s %s: not supported for this xaction kind", action, xctn.Name())
+		return
+	}
+	if xctn.Finished() {
+		return
+	}
+	switch action {
+	case apc.ActXactRateLimit:
+		xtcb.SetRateLimit(ctl.RateLimit)
+	}
+	if cmn.Rom.FastV(4, cos.SmoduleAIS) {
+		nlog.Infoln(t.String(), action, xctn.Name())
+	}
+}
+
+// pause or resume; not finding any matching (running and pausable) xaction is not an error
+func (t *target) xpause(w http.ResponseWriter, r *http.Request, action string, flt xreg.Flt) {
+	var (
+		n   int
+		err error
+	)
+	if action == apc.ActXactPause {
+		n, err = xreg.DoPause(flt)
+	} else {
+		n, err = xreg.DoResume(flt)
+	}
+	if err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	if cmn.Rom.FastV(4, cos.SmoduleAIS) {
+		nlog.Infoln(t.String(), action, flt.String(), "- num xactions:", n)
+	}
+}
+
+// (scheduled start - see xreg/sched.go)
+func (t *target) schedStart(kind string) error {
+	args := &xact.ArgsMsg{ID: cos.GenUUID(), Kind: kind}
+	_, err := t.xstart(args, nil, &apc.ActMsg{Action: apc.ActXactStart})
+	return err
+}
+
+// upon restart: request cluster-wide abort of the xactions this target no longer runs (see xreg/journal)
+// - via primary that broadcasts to all targets (compare w/ abortReq)
+func (t *target) xorphans() {
+	orphans := xreg.TakeOrphans()
+	for i := range orphans {
+		var (
+			e    = &orphans[i]
+			smap = t.owner.smap.get()
+			msg  = apc.ActMsg{
+				Action: apc.ActXactStop,
+				Name:   cmn.ErrXactOrphaned.Error(),
+				Value:  xact.ArgsMsg{ID: e.ID, Kind: e.Kind},
+			}
+			cargs = allocCargs()
+		)
+		{
+			cargs.si = smap.Primary
+			cargs.req = cmn.HreqArgs{
+				Method: http.MethodPut,
+				Base:   smap.Primary.URL(cmn.NetIntraControl),
+				Path:   apc.URLPathClu.S,
+				Body:   cos.MustMarshal(msg),
+			}
+			cargs.timeout = cmn.Rom.CplaneOperation()
+		}
+		res := t.call(cargs, smap)
+		if res.err != nil {
+			nlog.Errorln(t.String()+": failed to abort orphaned", e.Kind, e.ID, "err:", res.err)
+		} else {
+			nlog.Infoln(t.String()+": aborted orphaned", e.Kind, e.ID, "cluster-wide")
+		}
+		freeCargs(cargs)
+		freeCR(res)
+	}
+}
+
 func (t *target) xget(w http.ResponseWriter, r *http.Request, what, uuid string) {
 	if what != apc.WhatXactStats {
 		t.writeErrf(w, r, fmtUnknownQue, what)
@@ -236,6 +341,14 @@ func (t *target) xstart(args *xact.ArgsMsg, bck *meta.Bck, msg *apc.ActMsg) (xid
 		}
 		go t.runSpaceCleanup(args, wg)
 		wg.Wait()
+	case apc.ActExpireObjs:
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		if len(args.Buckets) == 0 && !args.Bck.IsEmpty() {
+			args.Buckets = []cmn.Bck{args.Bck}
+		}
+		go t.runExpire(args, wg)
+		wg.Wait()
 	case apc.ActResilver:
 		if bck != nil {
 			nlog.Errorf(erfmb, args.Kind, bck)
@@ -255,9 +368,38 @@ func (t *target) xstart(args *xact.ArgsMsg, bck *meta.Bck, msg *apc.ActMsg) (xid
 		}
 		go t.runResilver(res.Args{UUID: args.ID, Notif: notif}, wg)
 		wg.Wait()
+	case apc.ActDrain:
+		if bck != nil {
+			nlog.Errorf(erfmb, args.Kind, bck)
+		}
+		rns := xreg.RenewDrain(args.ID, args.DaemonID)
+		if rns.Err != nil {
+			return xid, rns.Err
+		}
+		if rns.IsRunning() {
+			return xid, nil
+		}
+		xctn := rns.Entry.Get()
+		xctn.AddNotif(&xact.NotifXact{
+			Base: nl.Base{When: core.UponTerm, Dsts: []string{equalIC}, F: t.notifyTerm},
+			Xact: xctn,
+		})
+		go xctn.Run(nil)
 	case apc.ActLoadLomCache:
 		rns := xreg.RenewBckLoadLomCache(args.ID, bck)
 		return xid, rns.Err
+	case apc.ActLifecycle:
+		rns := xreg.RenewBckLifecycle(args.ID, bck)
+		return xid, rns.Err
+	case apc.ActRotateKey:
+		if !bck.IsAIS() {
+			return xid, cmn.NewErrUnsupp("rotate data key of", bck.Cname("")+" (not an ais bucket)")
+		}
+		rns := xreg.RenewBckRotateKey(args.ID, bck)
+		return xid, rns.Err
+	case apc.ActQuotaUsage:
+		rns := xreg.RenewBckQuotaUsage(args.ID, bck)
+		return xid, rns.Err
 	case apc.ActBlobDl:
 		debug.Assert(msg.Name != "")
 		lom := core.AllocLOM(msg.Name)
@@ -276,6 +418,8 @@ func (t *target) xstart(args *xact.ArgsMsg, bck *meta.Bck, msg *apc.ActMsg) (xid
 	// 3. cannot start
 	case apc.ActPutCopies:
 		return xid, fmt.Errorf("cannot start %q (is driven by PUTs into a mirrored bucket)", args)
+	case apc.ActReplicate:
+		return xid, fmt.Errorf("cannot start %q (is driven by writes into a replicated bucket and periodic resync)", args)
 	case apc.ActDownload, apc.ActEvictObjects, apc.ActDeleteObjects, apc.ActMakeNCopies, apc.ActECEncode:
 		return xid, fmt.Errorf("initiating %q must be done via a separate documented API", args)
 	// 4. unknown
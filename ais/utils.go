@@ -403,6 +403,11 @@ func deploymentType() string {
 	}
 }
 
+// cluster-wide secret to validate AuthN tokens and sign presigned URLs (environment override takes precedence)
+func authSecret(config *cmn.Config) string {
+	return cos.Right(config.Auth.Secret, os.Getenv(env.AuthN.SecretKey))
+}
+
 // for AIS metadata filenames (constants), see `cmn/fname` package
 func cleanupConfigDir(name string, keepInitialConfig bool) {
 	if !keepInitialConfig {
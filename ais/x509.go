@@ -6,13 +6,17 @@ package ais
 
 import (
 	"net/http"
+	"net/url"
 
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/certloader"
+	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/nlog"
 	"github.com/NVIDIA/aistore/core"
 	"github.com/NVIDIA/aistore/core/meta"
+
+	jsoniter "github.com/json-iterator/go"
 )
 
 //
@@ -52,6 +56,31 @@ func (p *proxy) cluLoadX509(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// all nodes' certificates (see certloader.Props), including those that fail to respond
+func (p *proxy) qcluX509(w http.ResponseWriter, r *http.Request, what string, query url.Values) {
+	args := allocBcArgs()
+	args.req = cmn.HreqArgs{Method: http.MethodGet, Path: apc.URLPathDae.S, Query: query}
+	args.timeout = cmn.Rom.MaxKeepalive()
+	args.to = core.AllNodes
+	results := p.bcastGroup(args)
+	freeBcArgs(args)
+
+	out := make(map[string]cos.StrKVs, len(results)+1)
+	out[p.SID()] = certloader.Props()
+	for _, res := range results {
+		var props cos.StrKVs
+		if res.err == nil {
+			res.err = jsoniter.Unmarshal(res.bytes, &props)
+		}
+		if res.err != nil {
+			props = cos.StrKVs{"error": res.toErr().Error()}
+		}
+		out[res.si.ID()] = props
+	}
+	freeBcastRes(results)
+	p.writeJSON(w, r, out, what)
+}
+
 func (p *proxy) callLoadX509(w http.ResponseWriter, r *http.Request, node *meta.Snode, smap *smapX) {
 	cargs := allocCargs()
 	cargs.si = node
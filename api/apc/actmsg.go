@@ -5,6 +5,7 @@
 package apc
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/NVIDIA/aistore/cmn/cos"
@@ -21,6 +22,13 @@ const (
 
 	ActSummaryBck = "summary-bck"
 
+	// bucket snapshots (see SnapshotMsg)
+	ActCreateSnapshot  = "create-snapshot"
+	ActDestroySnapshot = "destroy-snapshot"
+	ActRestoreSnapshot = "restore-snapshot"
+	ActListSnapshots   = "list-snapshots"
+	ActBrowseSnapshot  = "browse-snapshot" // list snapshotted objects
+
 	ActECEncode  = "ec-encode" // erasure code a bucket
 	ActECGet     = "ec-get"    // read erasure coded objects
 	ActECPut     = "ec-put"    // erasure code objects
@@ -38,24 +46,36 @@ const (
 
 	ActMakeNCopies = "make-n-copies"
 	ActPutCopies   = "put-copies"
+	ActReplicate   = "replicate" // cross-cluster bucket replication (see cmn.ReplicationConf)
 
-	ActRebalance = "rebalance"
-	ActMoveBck   = "move-bck"
+	ActRebalance     = "rebalance"
+	ActSetRebWeights = "set-reb-weights" // see RebWeights
+	ActMoveBck       = "move-bck"
 
 	ActResilver = "resilver"
 
+	ActDrain = "drain" // migrate the data of the target in maintenance mode (see ActStartMaintenance)
+
 	ActElection = "election"
 
 	ActLRU          = "lru"
 	ActStoreCleanup = "cleanup-store"
+	ActExpireObjs   = "expire-objects" // remove expired objects (see cmn.ExpiresObjMD)
+	ActLifecycle    = "lifecycle"      // enforce bucket lifecycle rules (see cmn.LifecycleConf)
+	ActRotateKey    = "rotate-key"     // rotate data key and re-encrypt bucket's objects (see cmn.EncryptionConf)
+	ActQuotaUsage   = "quota-usage"    // recompute bucket and per-user usage (see cmn.QuotaConf)
 
 	ActEvictRemoteBck = "evict-remote-bck" // evict remote bucket's data
+	ActExtendRetain   = "extend-retention" // object lock: extend object's retention (see RetentionMsg)
 	ActInvalListCache = "inval-listobj-cache"
 	ActList           = "list"
+	ActListTrash      = "list-trash" // list soft-deleted objects (see TrashEntry)
 	ActLoadLomCache   = "load-lom-cache"
 	ActNewPrimary     = "new-primary"
 	ActPromote        = "promote"
+	ActPresign        = "presign"
 	ActRenameObject   = "rename-obj"
+	ActUndelete       = "undelete" // restore soft-deleted object (see UndeleteMsg)
 
 	// cp (reverse)
 	ActResetStats  = "reset-stats"
@@ -124,8 +144,13 @@ const (
 	ActMountpathFSHC   = "fshc-mp"
 
 	// Actions on xactions
-	ActXactStop  = Stop
-	ActXactStart = Start
+	ActXactStop   = Stop
+	ActXactStart  = Start
+	ActXactPause  = "pause"
+	ActXactResume = "resume"
+
+	// runtime control of a running copy (transform) bucket xaction (see xact.TCBCtlMsg)
+	ActXactRateLimit = "rate-limit"
 
 	// auxiliary
 	ActTransient = "transient" // transient - in-memory only
@@ -158,6 +183,11 @@ type (
 		Action string `json:"action"` // ActShutdown, ActRebalance, and many more (see apc/const.go)
 		Name   string `json:"name"`   // action-specific info of any kind (not necessarily "name")
 	}
+	// per-target rebalance (placement) weights: target ID => percentage of the target's default
+	// (HRW) share of the data, in the range [MinRebWeight, MaxRebWeight]; absent - MaxRebWeight;
+	// e.g., 50 for near-full (or slower) target to receive about half as much
+	RebWeights map[string]int
+
 	ActValRmNode struct {
 		DaemonID          string `json:"sid"`
 		SkipRebalance     bool   `json:"skip_rebalance"`
@@ -167,6 +197,11 @@ type (
 	}
 )
 
+const (
+	MinRebWeight = 1
+	MaxRebWeight = 100
+)
+
 type (
 	JoinNodeResult struct {
 		DaemonID    string `json:"daemon_id"`
@@ -231,3 +266,17 @@ func (msg *ActMsg) _string(wval bool) string {
 	s += ", val=" + strings.ReplaceAll(string(vs), ",", ", ") + "]"
 	return s
 }
+
+////////////////
+// RebWeights //
+////////////////
+
+func (w RebWeights) Validate() error {
+	for tid, weight := range w {
+		if weight < MinRebWeight || weight > MaxRebWeight {
+			return fmt.Errorf("invalid rebalance weight %d for target %q (expecting [%d, %d] range)",
+				weight, tid, MinRebWeight, MaxRebWeight)
+		}
+	}
+	return nil
+}
@@ -33,6 +33,11 @@ type (
 			RemoteObjs  uint64 `json:"size_all_remote_objs,string"`  // sum(all object sizes in a remote bucket)
 			Disks       uint64 `json:"total_disks_size,string"`
 		}
+		// quota enforced by the remote storage (e.g., Ceph RGW), if reported; zero: no quota
+		Quota struct {
+			Size    uint64 `json:"quota_size,string,omitempty"`
+			Objects uint64 `json:"quota_objs,string,omitempty"`
+		}
 		UsedPct      uint64 `json:"used_pct"`
 		IsBckPresent bool   `json:"is_present"` // in BMD
 	}
@@ -13,12 +13,28 @@ const (
 	CompressNever  = "never"
 )
 
-// sent via req.Header.Set(apc.HdrCompress, LZ4Compression)
-// (alternative to lz4 compressions upon popular request)
-const LZ4Compression = "lz4"
+// sent via req.Header.Set(apc.HdrCompress, ...) to let the receiving side know
+// which decompressor to use: lz4 by default, zstd when the sending bundle is configured
+// with a non-zero zstd level (e.g., rebalance.zstd_level)
+const (
+	LZ4Compression  = "lz4"
+	ZstdCompression = "zstd"
+)
+
+// sent via req.Header.Set(apc.HdrEncrypt, ...) when intra-cluster streams are encrypted
+// with the cluster-shared key (see transport.secret)
+const AES256GCM = "aes-256-gcm"
+
+// stream-bundle multiplier: max number of streams to a given destination
+const MaxSbundleMult = 16
+
+// zstd compression levels: 0 (zero) - use lz4; otherwise, the standard [1, 22] range
+const MaxZstdLevel = 22
 
 var SupportedCompression = [...]string{CompressNever, CompressAlways}
 
 func IsValidCompression(c string) bool {
 	return c == "" || c == SupportedCompression[0] || c == SupportedCompression[1]
 }
+
+func IsValidZstdLevel(level int) bool { return level >= 0 && level <= MaxZstdLevel }
@@ -55,14 +55,27 @@ const (
 	HdrRemoteOffline = aisPrefix + "Remote-Offline" // When accessing cached remote bucket with no backend connectivity.
 
 	// Object props headers
-	HdrObjCksumType = aisPrefix + "Checksum-Type"  // Checksum type, one of SupportedChecksums().
-	HdrObjCksumVal  = aisPrefix + "Checksum-Value" // Checksum value.
-	HdrObjAtime     = aisPrefix + "Atime"          // Object access time.
-	HdrObjCustomMD  = aisPrefix + "Custom-Md"      // Object custom metadata.
-	HdrObjVersion   = aisPrefix + "Version"        // Object version/generation - ais or cloud.
-
-	// Append object header
+	HdrObjCksumType   = aisPrefix + "Checksum-Type"  // Checksum type, one of SupportedChecksums().
+	HdrObjCksumVal    = aisPrefix + "Checksum-Value" // Checksum value.
+	HdrObjAtime       = aisPrefix + "Atime"          // Object access time.
+	HdrObjCustomMD    = aisPrefix + "Custom-Md"      // Object custom metadata.
+	HdrObjVersion     = aisPrefix + "Version"        // Object version/generation - ais or cloud.
+	HdrObjTTL         = aisPrefix + "Ttl"            // Object time-to-live, e.g. "24h" (PUT; see cmn.ExpiresObjMD)
+	HdrObjRetainUntil = aisPrefix + "Retain-Until"   // Object lock retention: Unix seconds or RFC3339 (PUT; see cmn.RetainUntilObjMD)
+
+	// client-supplied encryption key (PUT, GET, HEAD): algorithm ("AES256"), base64-encoded 256-bit key,
+	// and (optionally) base64-encoded MD5 of the key; the key is never stored (see fs/sse)
+	HdrSSECustomerAlgo   = aisPrefix + "Sse-Customer-Algorithm"
+	HdrSSECustomerKey    = aisPrefix + "Sse-Customer-Key"
+	HdrSSECustomerKeyMD5 = aisPrefix + "Sse-Customer-Key-Md5"
+
+	// active-active replication (PUT from the peer cluster; see cmn.ReplVVObjMD and cmn.ReplWriteObjMD)
+	HdrObjReplVV    = aisPrefix + "Repl-Version-Vector" // e.g. "uuid-A:3,uuid-B:1"
+	HdrObjReplWrite = aisPrefix + "Repl-Write"          // origin write: "<Unix nanoseconds>,<cluster UUID>"
+
+	// Append object headers
 	HdrAppendHandle = aisPrefix + "Append-Handle"
+	HdrAppendOffset = aisPrefix + "Append-Offset" // where the appended content starts (ExtendOp)
 
 	// api.PutApndArchArgs message flags
 	HdrPutApndArchFlags = aisPrefix + "Pine"
@@ -111,6 +124,7 @@ const (
 	// intra-cluster streams
 	HdrSessID   = aisPrefix + "Session-Id"
 	HdrCompress = aisPrefix + "Compress" // LZ4
+	HdrEncrypt  = aisPrefix + "Encrypt"  // AES256GCM
 
 	// Promote(dir)
 	HdrPromoteNamesHash = aisPrefix + "Promote-Names-Hash"
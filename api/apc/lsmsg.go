@@ -88,6 +88,7 @@ const (
 	MaxPageSizeGCP   = 1000
 	MaxPageSizeAzure = 5000
 	MaxPageSizeOCI   = 1000
+	MaxPageSizeB2    = 1000 // (up to 10000 - billed as multiple transactions)
 )
 
 const (
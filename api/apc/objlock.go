@@ -0,0 +1,18 @@
+// Package apc: API control messages and constants
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package apc
+
+// object lock (WORM) modes (see cmn.ObjLockConf)
+const (
+	// retention can be bypassed (QparamBypassGovernance) by a user with admin permission
+	ObjLockGovernance = "governance"
+	// retention cannot be bypassed or shortened by anyone; the bucket cannot be switched to another mode
+	ObjLockCompliance = "compliance"
+)
+
+// (see api.ExtendObjectRetention)
+type RetentionMsg struct {
+	RetainUntil int64 `json:"retain_until"` // Unix seconds
+}
@@ -0,0 +1,22 @@
+// Package apc: API control messages and constants
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package apc
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+)
+
+const (
+	PresignDfltExpires = time.Hour
+	PresignMaxExpires  = 7 * 24 * time.Hour
+)
+
+// (see api.PresignObject)
+type PresignArgs struct {
+	Method  string       `json:"method"`            // http.MethodGet or http.MethodPut
+	Expires cos.Duration `json:"expires,omitempty"` // e.g. "30m"; default: PresignDfltExpires
+}
@@ -10,14 +10,18 @@ import (
 
 // Backend Provider enum
 const (
-	AIS   = "ais"
-	AWS   = "aws"
-	Azure = "azure"
-	GCP   = "gcp"
-	OCI   = "oci"
-	HT    = "ht"
+	AIS    = "ais"
+	AWS    = "aws"
+	Azure  = "azure"
+	GCP    = "gcp"
+	OCI    = "oci"
+	B2     = "b2"
+	WebDAV = "webdav"
+	FTP    = "ftp" // SFTP and FTP(S)
+	POSIX  = "posix"
+	HT     = "ht"
 
-	AllProviders = "ais, aws (s3://), gcp (gs://), azure (az://), oci (oc://), ht://" // NOTE: must include all
+	AllProviders = "ais, aws (s3://), gcp (gs://), azure (az://), oci (oc://), b2 (b2://), webdav (webdav://), ftp (ftp://), posix (posix://), ht://" // NOTE: must include all
 
 	NsUUIDPrefix = '@' // BEWARE: used by on-disk layout
 	NsNamePrefix = '#' // BEWARE: used by on-disk layout
@@ -36,12 +40,12 @@ const (
 
 const RemAIS = "remais" // to differentiate ais vs "remote" ais; also, default (remote ais cluster) alias
 
-var Providers = cos.NewStrSet(AIS, GCP, AWS, Azure, OCI, HT)
+var Providers = cos.NewStrSet(AIS, GCP, AWS, Azure, OCI, B2, WebDAV, FTP, POSIX, HT)
 
 func IsProvider(p string) bool { return Providers.Contains(p) }
 
 func IsCloudProvider(p string) bool {
-	return p == AWS || p == GCP || p == Azure || p == OCI
+	return p == AWS || p == GCP || p == Azure || p == OCI || p == B2 || p == WebDAV || p == FTP || p == POSIX
 }
 
 // NOTE: not to confuse w/ bck.IsRemote() which also includes remote AIS
@@ -96,6 +100,14 @@ func DisplayProvider(p string) string {
 		return "GCP"
 	case OCI, OCIScheme:
 		return "OCI"
+	case B2:
+		return "B2"
+	case WebDAV:
+		return "WebDAV"
+	case FTP:
+		return "(S)FTP"
+	case POSIX:
+		return "POSIX"
 	case HT:
 		return "HTTP(S)"
 	default:
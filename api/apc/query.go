@@ -70,6 +70,10 @@ const (
 	QparamLogOff  = "offset"
 	QparamAllLogs = "all"
 
+	// Get recent audit events
+	QparamAuditCount  = "count"  // max number of events
+	QparamAuditBucket = "bucket" // only events that pertain to this bucket (cname, e.g. "ais://abc")
+
 	// The following 4 (four) QparamArch* parameters are all intended for usage with sharded datasets,
 	// whereby the shards are (.tar, .tgz (or .tar.gz), .zip, and/or .tar.lz4) formatted objects.
 	//
@@ -133,6 +137,21 @@ const (
 	// (to opt-out logging too many messages and/or benign warnings)
 	QparamSilent = "sln"
 
+	// presigned URL (see api.PresignObject): expiration time (Unix seconds) and signature
+	QparamPresignExpires = "presign_expires"
+	QparamPresignSig     = "presign_sig"
+
+	// object lock (WORM): delete object that's still under governance-mode retention (requires admin permission)
+	QparamBypassGovernance = "bypass_governance"
+
+	// GET (or HEAD) object: read its version captured by a given bucket snapshot (see ActCreateSnapshot)
+	QparamSnapshot = "snapshot"
+
+	// quotas (see cmn.QuotaConf): redirecting proxy to target, when the latter must enforce a given limit -
+	// object count (overwrite only) or remaining capacity (object of unknown size)
+	QparamQuotaObjs = "quota_objs"
+	QparamQuotaSize = "quota_size"
+
 	// (see api.AttachMountpath vs. LocalConfig.FSP)
 	QparamMpathLabel = "mountpath_label"
 
@@ -170,6 +189,7 @@ func IsFltNoProps(v int) bool {
 const (
 	AppendOp = "append"
 	FlushOp  = "flush"
+	ExtendOp = "extend" // atomically append to an existing (or new) object in a single request
 )
 
 // health
@@ -216,8 +236,9 @@ const (
 // QparamWhat enum.
 const (
 	// cluster metadata
-	WhatSmap = "smap"
-	WhatBMD  = "bmd"
+	WhatSmap       = "smap"
+	WhatBMD        = "bmd"
+	WhatRebWeights = "reb_weights" // see RebWeights
 
 	// config
 	WhatNodeConfig    = "config"         // query specific node for (cluster config + overrides, local config)
@@ -244,7 +265,11 @@ const (
 	WhatTargetIPs  = "target_ips" // comma-separated list of all target IPs (compare w/ GetWhatSnode)
 
 	// log
-	WhatLog = "log"
+	WhatLog   = "log"
+	WhatAudit = "audit" // recent audit events (see cmn/audit)
+
+	// quota
+	WhatQuotaUsage = "quota_usage" // current bucket and per-user usage (see QuotaUsage)
 
 	// xactions
 	WhatOneXactStatus   = "status"      // IC status by uuid (returns a single matching xaction or none)
@@ -0,0 +1,50 @@
+// Package apc: API control messages and constants
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package apc
+
+// (see cmn.QuotaConf and WhatQuotaUsage)
+type (
+	Usage struct {
+		Size    int64 `json:"size"`
+		Objects int64 `json:"objects"`
+	}
+	// usage as tracked by a given target or, cluster-wide, by proxy (sum over all targets)
+	QuotaUsage struct {
+		Buckets map[string]*Usage `json:"buckets"` // by bucket cname, e.g. "ais://abc"
+		Users   map[string]*Usage `json:"users"`   // by AuthN user ID (owner of the objects)
+	}
+)
+
+func NewQuotaUsage() *QuotaUsage {
+	return &QuotaUsage{Buckets: make(map[string]*Usage), Users: make(map[string]*Usage)}
+}
+
+// deep copy
+func (qu *QuotaUsage) Clone() *QuotaUsage {
+	clone := NewQuotaUsage()
+	clone.Merge(qu)
+	return clone
+}
+
+// add other's usage to this one
+func (qu *QuotaUsage) Merge(other *QuotaUsage) {
+	mergeUsage(qu.Buckets, other.Buckets)
+	mergeUsage(qu.Users, other.Users)
+}
+
+func (u *Usage) Add(size, objects int64) {
+	u.Size = max(u.Size+size, 0)
+	u.Objects = max(u.Objects+objects, 0)
+}
+
+func mergeUsage(dst, src map[string]*Usage) {
+	for k, u := range src {
+		if d, ok := dst[k]; ok {
+			d.Add(u.Size, u.Objects)
+		} else {
+			dst[k] = &Usage{Size: u.Size, Objects: u.Objects}
+		}
+	}
+}
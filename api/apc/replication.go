@@ -0,0 +1,28 @@
+// Package apc: API control messages and constants
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package apc
+
+// cross-cluster bucket replication: conflict policies (see cmn.ReplicationConf)
+// conflict: destination object was created or modified in the remote cluster
+// since it was last replicated
+const (
+	// source wins: overwrite destination (default)
+	ReplConflictOverwrite = "overwrite"
+	// destination wins: keep the destination's version; subsequent updates of the source will be replicated
+	ReplConflictSkip = "skip"
+)
+
+// active-active replication: resolving concurrent updates, i.e., the case when both clusters
+// updated the same object independently (see cmn.VersionVector)
+// the policy must be configured identically on both sides
+const (
+	// the most recent write wins (default)
+	ReplResolveLWW = "last-writer-wins"
+	// the earliest write wins
+	ReplResolveFWW = "first-writer-wins"
+	// writes that originated in the preferred cluster (cmn.ReplicationConf.Prefer) win;
+	// otherwise, last writer wins
+	ReplResolvePrefer = "prefer-cluster"
+)
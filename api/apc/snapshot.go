@@ -0,0 +1,32 @@
+// Package apc: API control messages and constants
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package apc
+
+import (
+	"errors"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+)
+
+// bucket snapshots (see also cmn.SnapshotInfo)
+type (
+	// ActCreateSnapshot, ActDestroySnapshot, ActRestoreSnapshot, ActListSnapshots, and ActBrowseSnapshot
+	SnapshotMsg struct {
+		Name   string `json:"name"`             // snapshot name (optional when listing snapshots)
+		Prefix string `json:"prefix,omitempty"` // ActBrowseSnapshot: object name prefix
+		// ActRestoreSnapshot: in addition to the content, restore bucket props as of the time of snapshot
+		Props bool `json:"props,omitempty"`
+	}
+)
+
+func (msg *SnapshotMsg) Validate() error {
+	if msg.Name == "" {
+		return errors.New("snapshot name cannot be empty")
+	}
+	if msg.Name[0] == '.' {
+		return errors.New("snapshot name cannot start with a dot (.)")
+	}
+	return cos.CheckAlphaPlus(msg.Name, "snapshot name")
+}
@@ -6,6 +6,9 @@ package apc
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
 
 	"github.com/NVIDIA/aistore/cmn/cos"
@@ -16,14 +19,200 @@ type (
 	CopyBckMsg struct {
 		Prepend   string `json:"prepend"`     // destination naming, as in: dest-obj-name = Prepend + source-obj-name
 		Prefix    string `json:"prefix"`      // prefix to select matching _source_ objects or virtual directories
-		DryRun    bool   `json:"dry_run"`     // visit all source objects, don't make any modifications
+		DryRun    bool   `json:"dry_run"`     // visit all source objects, don't make any modifications (see xs.TCBEstimate)
 		Force     bool   `json:"force"`       // force running in presence of "limited coexistence" type conflicts
 		LatestVer bool   `json:"latest-ver"`  // see also: QparamLatestVer, 'versioning.validate_warm_get', PrefetchMsg
 		Sync      bool   `json:"synchronize"` // see also: 'versioning.synchronize'
+
+		// conditional copy: copy only if the destination object is absent or differs from the source:
+		// - checksums are compared when both are computed with the same checksum type; otherwise,
+		// - size, and the source version and modification time recorded by the previous copy
+		//   (see cmn.SrcVerObjMD and cmn.SrcMtimeObjMD)
+		// combined with Sync (above) makes for cheap repeated (incremental) synchronization
+		IfNoneMatch bool `json:"if_none_match,omitempty"`
+
+		// skip source objects with names lexicographically less than or equal to this one
+		// - composes with Prefix (above) - the two conditions must both hold;
+		// - note that the (source) objects are visited in no particular order
+		//   (the objects are walked concurrently, one mountpath at a time, and there is no sorted pass),
+		//   which is why this is a filter, not a starting point
+		StartAfter string `json:"start_after,omitempty"`
+
+		// select source objects by name - in addition to Prefix (above):
+		// - Regex: regular expression (Go RE2 syntax) that the name must match;
+		// - Template: the name must be one of the names generated by the range template,
+		//   e.g. "shards/train-{0000..9999}.tar" (see cos.ParsedTemplate); template with no ranges is a prefix
+		Regex    string `json:"regex,omitempty"`
+		Template string `json:"template,omitempty"`
+
+		// when the destination is a remote bucket: store the copies in the cluster only, without writing
+		// them through to the remote backend (the default)
+		// - durability: until flushed (below), the copies exist only in the cluster and are subject
+		//   to the destination bucket's (in-cluster) redundancy - mirroring and/or EC - if any;
+		// - evicting the destination bucket (or losing the respective targets) prior to flushing
+		//   loses the data
+		LocalOnly bool `json:"local_only,omitempty"`
+
+		// when copying remote bucket onto itself: write in-cluster objects that differ from their
+		// remote counterparts (or don't exist remotely) through to the remote backend;
+		// use it to flush objects previously copied with LocalOnly (above)
+		Flush bool `json:"flush,omitempty"`
+
+		// what to do when two or more source objects map onto the same destination name
+		// (see TCBMsg.ToName and enumerated TCBCollision* policies below)
+		OnCollision string `json:"on_collision,omitempty"`
+
+		// when specified, each target records all destination objects it stores (writes)
+		// in a newline-delimited JSON report (see TCBManifestEntry) named as follows:
+		// Manifest + <target ID> + "-" + <number> + TCBManifestExt, e.g. "reports/t[abc]-0.ndjson"
+		// - the report is stored in the destination bucket upon xaction completion;
+		// - the <number> is chosen so that each target can store its own report locally
+		Manifest string `json:"manifest,omitempty"`
+
+		// when specified (http or https URL), each target that stores (writes) a destination object
+		// POSTs a JSON-encoded TCBWebhookEvent to this URL - e.g., to enqueue the object for indexing
+		// - best effort: events are delivered asynchronously; the ones that fail are logged and counted,
+		//   and those that don't fit the (bounded) queue get dropped - the copy never waits
+		Webhook string `json:"webhook,omitempty"`
+
+		// detect source objects that get modified (overwritten) while being copied,
+		// and handle them according to one of the enumerated TCBSrcChange* policies (below);
+		// empty (default) - no detection
+		OnSrcChange string `json:"on_src_change,omitempty"`
+
+		// prepend a short deterministic hash of the source name to the destination name, as in:
+		// dest-obj-name = hash + "/" + Prepend + source-obj-name (e.g. "a1/images/cat.jpg")
+		// - to spread writes across the destination backend's key-prefix partitions ("hot partitions");
+		// - NOTE: changes destination names - the same source name always maps to the same hash
+		HashPrefix *HashPrefixMsg `json:"hash_prefix,omitempty"`
+
+		// differential copy: list (this target's) source objects and the (remote) destination bucket,
+		// compare the two sorted listings by name, size, and checksum (when comparable),
+		// and copy only the delta - objects missing or differing at the destination
+		// - the delta is computed (and logged) prior to copying;
+		// - requires remote destination; does not support renaming other than Prepend
+		Diff bool `json:"diff,omitempty"`
+
+		// copy object tags (see core.TagBackend) from remote source to tag-capable remote destination;
+		// skipped (and counted) where tags are not supported
+		PreserveTags bool `json:"preserve_tags,omitempty"`
+
+		// carry the source object's sequence marker (creation order) over to the destination, where it is
+		// stored as custom metadata (see cmn.SeqObjMD) - for consumers that replay objects in ingestion order:
+		// - the marker is the source's own (custom) "seq", if present, or else the time the source object
+		//   was written in the cluster (Unix nanoseconds, zero-padded to sort lexicographically)
+		PreserveOrder bool `json:"preserve_order,omitempty"`
+
+		// relative to other disk IO (client, rebalance, other copies): one of the enumerated
+		// TCBPriority* values (below); empty - normal
+		Priority string `json:"priority,omitempty"`
+
+		// upon successful completion, re-read each destination object stored by a given target,
+		// recompute its checksum, and compare it with the one that was stored (a separate verification pass)
+		// - reports mismatched objects by name, and re-copies those whose (local) source can be determined;
+		// - catches post-write corruption (compare with on-the-fly checksum validation upon receive)
+		VerifyAfter bool `json:"verify_after,omitempty"`
+
+		// point-in-time copy: copy the source bucket as of a single instant and skip source objects
+		// written (created or modified) afterwards - a consistent copy in presence of concurrent writes
+		// - the instant is AsOf (below) or, if unspecified, the time the copy starts;
+		// - the cutoff applies to the time the object was written in the cluster; the content
+		//   of a source object overwritten after the instant is no longer available - the object
+		//   gets skipped (and counted);
+		// - best-effort with remote backends, especially those that lack versioning: the cutoff is the time
+		//   the (source) object was fetched into the cluster, not its last modification time at the backend
+		Snapshot bool `json:"snapshot,omitempty"`
+
+		// (see Snapshot) Unix time in nanoseconds; zero - the time the copy starts (set by the cluster)
+		AsOf int64 `json:"as_of,omitempty"`
+
+		// request server-side encryption (at rest) of the destination objects by the destination's
+		// remote backend (see core.SSEBackend); skipped (and counted) where not supported
+		// - NOTE: applies to the objects stored by the remote backend - not to their in-cluster replicas
+		SSE *SSEMsg `json:"sse,omitempty"`
+
+		// wall-clock deadline (Unix time in nanoseconds): stop copying when reached
+		// - objects being copied at the time complete, the rest are not copied;
+		// - the copy finishes (without error) in a "deadline reached" state;
+		// - to resume, re-run the same copy with IfNoneMatch (skips the objects already copied)
+		Deadline int64 `json:"deadline,omitempty"`
+
+		// bandwidth limit: bytes per second, per target (zero - unlimited)
+		// - the limit can be changed at runtime (see xs.XactTCB.SetRateLimit)
+		RateLimit int64 `json:"rate_limit,omitempty"`
+
+		// persist per-mountpath progress (the last visited source object) so that an interrupted copy
+		// (aborted, or target restarted) can be resumed - see Resume below - rather than started over
+		// - requires visiting source objects in order: sequentially, one object at a time per mountpath
+		//   (overrides Priority-based parallelism);
+		// - checkpoints are removed upon successful completion
+		Checkpoint bool `json:"checkpoint,omitempty"`
+
+		// resume the interrupted copy with the given xaction ID: skip source objects visited prior
+		// to its persisted checkpoints, and keep checkpointing under the same ID (requires Checkpoint)
+		Resume string `json:"resume,omitempty"`
+
+		// when specified, append copied (transformed) objects to destination archives ("shards")
+		// instead of writing them out one by one
+		Arch *ArchTCBMsg `json:"arch,omitempty"`
+
+		// per-request overrides of the intra-cluster streams that carry objects between targets
+		// (compare with config 'tcb' section): compression (one of SupportedCompression) and
+		// stream-bundle multiplier (num streams to each destination, up to MaxSbundleMult);
+		// empty (zero) - as configured
+		Compression string `json:"compression,omitempty"`
+		SbundleMult int    `json:"bundle_multiplier,omitempty"`
+	}
+	// destination archive (a.k.a. shard) - see CopyBckMsg.Arch
+	// - each target produces its own shards (from the objects it stores locally), and
+	// - names the shards by substituting TCBArchTid and TCBArchSeq in the Template
+	// - entry names (in-archive names) are the destination object names (see TCBMsg.ToName)
+	// - the last shard is finalized upon xaction completion
+	ArchTCBMsg struct {
+		Mime       string `json:"mime,omitempty"`        // archive format, e.g. ".tar" (default: by Template extension)
+		Template   string `json:"template,omitempty"`    // shard name template (default: TCBArchDefaultTemplate + Mime)
+		MaxEntries int    `json:"max_entries,omitempty"` // roll over to the next shard upon reaching (0 - unlimited)
+		MaxSize    int64  `json:"max_size,omitempty"`    // ditto, in bytes
+	}
+	// see CopyBckMsg.SSE
+	SSEMsg struct {
+		Algo   string `json:"algo,omitempty"`    // one of the enumerated SSEAlgo* (below); default: by KMSKey
+		KMSKey string `json:"kms_key,omitempty"` // KMS key ID (or ARN); empty - the backend's default key
+	}
+	// see CopyBckMsg.HashPrefix
+	HashPrefixMsg struct {
+		Alphabet string `json:"alphabet,omitempty"` // default: HashPrefixDefaultAlphabet
+		Len      int    `json:"len,omitempty"`      // number of hash characters (default: HashPrefixDefaultLen)
+	}
+
+	// one line (record) in the destination manifest (see CopyBckMsg.Manifest)
+	TCBManifestEntry struct {
+		Custom     cos.StrKVs `json:"custom,omitempty"`
+		Name       string     `json:"name"`
+		CksumType  string     `json:"cksum_type,omitempty"`
+		CksumValue string     `json:"cksum_value,omitempty"`
+		Size       int64      `json:"size"`
+	}
+	// per-object notification (see CopyBckMsg.Webhook)
+	TCBWebhookEvent struct {
+		XactID     string `json:"xaction_id"`
+		SrcName    string `json:"src_name,omitempty"` // empty when cannot be determined
+		Name       string `json:"name"`
+		CksumType  string `json:"cksum_type,omitempty"`
+		CksumValue string `json:"cksum_value,omitempty"`
+		Size       int64  `json:"size"`
 	}
 	Transform struct {
 		Name    string       `json:"id,omitempty"`
 		Timeout cos.Duration `json:"request_timeout,omitempty"`
+		// pipeline: ETLs to stream each (Name-)transformed object through, in order -
+		// e.g., decode => resize => re-encode without intermediate buckets
+		// (each subsequent stage must be able to receive the object in the request body - see etl.OfflineDP)
+		Chain []string `json:"chain,omitempty"`
+		// transform result caching: skip source objects that were already transformed into the destination
+		// by the same ETL(s) - same name(s) and init parameters (code, spec, etc.) - and have not changed since
+		// (see cmn.ETLCacheObjMD); turns repeated runs over the same bucket into incremental jobs
+		Cache bool `json:"cache,omitempty"`
 	}
 	TCBMsg struct {
 		// NOTE: objname extension ----------------------------------------------------------------------
@@ -38,19 +227,240 @@ type (
 	}
 )
 
+// CopyBckMsg.OnCollision policies
+// - the detection is per target: each target keeps track of the destination names it produces;
+// - colliding source objects stored on different targets are not detected;
+// - empty policy (default) disables the detection - the last writer wins
+const (
+	TCBCollisionErr    = "error"  // abort the job
+	TCBCollisionSkip   = "skip"   // keep the first, skip all subsequent sources
+	TCBCollisionSuffix = "suffix" // add numeric suffix to subsequent destination names (e.g. "a.jpg" => "a-1.jpg")
+)
+
+const TCBManifestExt = ".ndjson"
+
+// HashPrefixMsg defaults and limits
+const (
+	HashPrefixDefaultAlphabet = "0123456789abcdef"
+	HashPrefixDefaultLen      = 2
+	HashPrefixMaxLen          = 8
+)
+
+// CopyBckMsg.OnSrcChange policies
+// - detection: source object's size, mtime, version, and checksum - before and after copying;
+// - when detected, the destination may already contain (torn) content that does not correspond to any
+// (single) version of the source
+const (
+	TCBSrcChangeRetry  = "retry"  // copy again (up to a few times), and record if still changing
+	TCBSrcChangeRecord = "record" // record (count and report the error) without retrying
+)
+
+// CopyBckMsg.Priority
+// - low: single worker per mountpath that yields aggressively to other IO;
+// - high: more workers per mountpath that throttle only when the disk is fully utilized
+const (
+	TCBPriorityLow    = "low"
+	TCBPriorityNormal = "normal"
+	TCBPriorityHigh   = "high"
+)
+
+// SSEMsg algorithms
+const (
+	SSEAlgoAES256 = "AES256"  // backend-managed keys (default when KMSKey is not specified)
+	SSEAlgoKMS    = "aws:kms" // KMS-managed keys (default when KMSKey is specified)
+)
+
+// ArchTCBMsg template
+const (
+	TCBArchTid = "{tid}" // target ID
+	TCBArchSeq = "{seq}" // zero-padded shard sequence number (per target)
+
+	TCBArchDefaultTemplate = "shard-" + TCBArchTid + "-" + TCBArchSeq
+)
+
 ////////////
 // TCBMsg //
 ////////////
 
 func (msg *TCBMsg) Validate(isEtl bool) (err error) {
 	if isEtl && msg.Transform.Name == "" {
-		err = errors.New("ETL name can't be empty")
+		return errors.New("ETL name can't be empty")
+	}
+	if len(msg.Transform.Chain) > 0 && !isEtl {
+		return errors.New("transformer chain is only supported when transforming (ETL) buckets")
+	}
+	for i, name := range msg.Transform.Chain {
+		if name == "" {
+			return fmt.Errorf("ETL name can't be empty (chain stage %d)", i+1)
+		}
+	}
+	if msg.Transform.Cache {
+		switch {
+		case !isEtl:
+			return errors.New("transform result caching is only supported when transforming (ETL) buckets")
+		case msg.Arch != nil:
+			return errors.New("transform result caching is not supported when transforming into destination archives")
+		}
+	}
+	if msg.HashPrefix != nil {
+		if msg.Sync {
+			return errors.New("hash prefix (destination naming) is incompatible with synchronizing (--sync) buckets")
+		}
+		if err := msg.HashPrefix.Validate(); err != nil {
+			return err
+		}
+	}
+	if msg.StartAfter != "" && msg.Sync {
+		// would otherwise remove destination objects that correspond to the skipped ones
+		return fmt.Errorf("start-after (%q) is incompatible with synchronizing (--sync) buckets", msg.StartAfter)
+	}
+	if msg.Regex != "" {
+		if msg.Sync {
+			return fmt.Errorf("regex (%q) is incompatible with synchronizing (--sync) buckets", msg.Regex)
+		}
+		if _, err := regexp.Compile(msg.Regex); err != nil {
+			return fmt.Errorf("invalid regex %q: %w", msg.Regex, err)
+		}
+	}
+	if msg.Template != "" {
+		if msg.Sync {
+			return fmt.Errorf("template (%q) is incompatible with synchronizing (--sync) buckets", msg.Template)
+		}
+		pt, err := cos.NewParsedTemplate(msg.Template)
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(pt.Prefix, msg.Prefix) && !strings.HasPrefix(msg.Prefix, pt.Prefix) {
+			return fmt.Errorf("template %q does not match prefix %q", msg.Template, msg.Prefix)
+		}
+	}
+	if !IsValidCompression(msg.Compression) {
+		return fmt.Errorf("invalid compression %q (expecting one of: %v)", msg.Compression, SupportedCompression)
+	}
+	if msg.SbundleMult < 0 || msg.SbundleMult > MaxSbundleMult {
+		return fmt.Errorf("invalid bundle multiplier %d (expected range [0, %d])", msg.SbundleMult, MaxSbundleMult)
+	}
+	switch msg.OnCollision {
+	case "", TCBCollisionErr, TCBCollisionSkip, TCBCollisionSuffix:
+	default:
+		return fmt.Errorf("invalid on-collision policy %q (expecting one of: %q, %q, %q)",
+			msg.OnCollision, TCBCollisionErr, TCBCollisionSkip, TCBCollisionSuffix)
+	}
+	switch msg.Priority {
+	case "", TCBPriorityLow, TCBPriorityNormal, TCBPriorityHigh:
+	default:
+		return fmt.Errorf("invalid priority %q (expecting one of: %q, %q, %q)",
+			msg.Priority, TCBPriorityLow, TCBPriorityNormal, TCBPriorityHigh)
+	}
+	switch msg.OnSrcChange {
+	case "", TCBSrcChangeRetry, TCBSrcChangeRecord:
+	default:
+		return fmt.Errorf("invalid on-source-change policy %q (expecting %q or %q)",
+			msg.OnSrcChange, TCBSrcChangeRetry, TCBSrcChangeRecord)
+	}
+	if isEtl && (msg.LocalOnly || msg.Flush) {
+		return errors.New("local-only and flush options are not supported with ETL")
+	}
+	if msg.LocalOnly && msg.Flush {
+		return errors.New("local-only and flush options are mutually exclusive")
+	}
+	if msg.Manifest != "" {
+		switch {
+		case msg.DryRun:
+			return errors.New("destination manifest is not supported with dry-run")
+		case msg.Arch != nil:
+			return errors.New("destination manifest is not supported when copying into destination archives")
+		}
+	}
+	if msg.IfNoneMatch {
+		switch {
+		case isEtl:
+			return errors.New("conditional copy (if-none-match) is not supported with ETL")
+		case msg.Arch != nil:
+			return errors.New("conditional copy (if-none-match) is not supported when copying into destination archives")
+		}
+	}
+	if msg.PreserveTags && msg.Arch != nil {
+		return errors.New("preserving object tags is not supported when copying into destination archives")
+	}
+	if msg.PreserveOrder && msg.Arch != nil {
+		return errors.New("preserving sequence markers is not supported when copying into destination archives")
+	}
+	if msg.SSE != nil {
+		switch {
+		case msg.LocalOnly:
+			return errors.New("server-side encryption (by remote backend) is incompatible with local-only copy")
+		case msg.Arch != nil:
+			return errors.New("server-side encryption is not supported when copying into destination archives")
+		}
+		if err := msg.SSE.Validate(); err != nil {
+			return err
+		}
+	}
+	if msg.Snapshot {
+		switch {
+		case msg.LatestVer:
+			return errors.New("point-in-time copy (snapshot) is incompatible with copying latest versions")
+		case msg.Sync:
+			return errors.New("point-in-time copy (snapshot) is incompatible with synchronizing (--sync) buckets")
+		case msg.AsOf < 0:
+			return fmt.Errorf("invalid point-in-time (as-of) %d", msg.AsOf)
+		}
+	} else if msg.AsOf != 0 {
+		return errors.New("point-in-time (as-of) requires snapshot option")
+	}
+	if msg.Deadline < 0 {
+		return fmt.Errorf("invalid deadline %d", msg.Deadline)
+	}
+	if msg.RateLimit < 0 {
+		return fmt.Errorf("invalid rate limit %d", msg.RateLimit)
+	}
+	if msg.Webhook != "" {
+		if u, err := url.Parse(msg.Webhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook URL %q (expecting http or https)", msg.Webhook)
+		}
+	}
+	if msg.Checkpoint && msg.Diff {
+		return errors.New("checkpointing is incompatible with differential copy")
+	}
+	if msg.Resume != "" {
+		if !msg.Checkpoint {
+			return errors.New("resuming interrupted copy requires checkpoint option")
+		}
+		if !cos.IsAlphaNice(msg.Resume) {
+			return fmt.Errorf("invalid (resume) xaction ID %q", msg.Resume)
+		}
+	}
+	if msg.VerifyAfter {
+		switch {
+		case msg.DryRun:
+			return errors.New("verification pass (verify-after) is not supported with dry-run")
+		case msg.Arch != nil:
+			return errors.New("verification pass (verify-after) is not supported when copying into destination archives")
+		}
+	}
+	if msg.Diff {
+		switch {
+		case isEtl:
+			return errors.New("differential copy is not supported with ETL")
+		case msg.Arch != nil:
+			return errors.New("differential copy is not supported when copying into destination archives")
+		case msg.Sync:
+			return errors.New("differential copy is incompatible with synchronizing (--sync) buckets")
+		case msg.Ext != nil || msg.HashPrefix != nil || msg.OnCollision != "":
+			// would break the correspondence (and sorting order) of source and destination names
+			return errors.New("differential copy does not support destination naming other than prepend")
+		}
+	}
+	if msg.Arch != nil {
+		err = msg.Arch.Validate()
 	}
 	return
 }
 
 // Replace extension and add suffix if provided.
 func (msg *TCBMsg) ToName(name string) string {
+	srcName := name
 	if msg.Ext != nil {
 		if idx := strings.LastIndexByte(name, '.'); idx >= 0 {
 			ext := name[idx+1:]
@@ -62,6 +472,9 @@ func (msg *TCBMsg) ToName(name string) string {
 	if msg.Prepend != "" {
 		name = msg.Prepend + name
 	}
+	if msg.HashPrefix != nil {
+		name = msg.HashPrefix.Hash(srcName) + "/" + name
+	}
 	return name
 }
 
@@ -69,6 +482,11 @@ func (msg *TCBMsg) ToName(name string) string {
 // CopyBckMsg //
 ////////////////
 
+// returns true if the (source) object is to be skipped (see StartAfter)
+func (msg *CopyBckMsg) Skip(objName string) bool {
+	return msg.StartAfter != "" && objName <= msg.StartAfter
+}
+
 func (msg *CopyBckMsg) Str(sb *strings.Builder, fromCname, toCname string) {
 	sb.WriteString(fromCname)
 	sb.WriteString("=>")
@@ -79,4 +497,174 @@ func (msg *CopyBckMsg) Str(sb *strings.Builder, fromCname, toCname string) {
 	if msg.Sync {
 		sb.WriteString(", sync")
 	}
+	if msg.Arch != nil {
+		sb.WriteString(", arch")
+	}
+	if msg.IfNoneMatch {
+		sb.WriteString(", if-none-match")
+	}
+	if msg.StartAfter != "" {
+		sb.WriteString(", start-after:")
+		sb.WriteString(msg.StartAfter)
+	}
+	if msg.Regex != "" {
+		sb.WriteString(", regex:")
+		sb.WriteString(msg.Regex)
+	}
+	if msg.Template != "" {
+		sb.WriteString(", template:")
+		sb.WriteString(msg.Template)
+	}
+	if msg.LocalOnly {
+		sb.WriteString(", local-only")
+	}
+	if msg.Flush {
+		sb.WriteString(", flush")
+	}
+	if msg.OnCollision != "" {
+		sb.WriteString(", on-collision:")
+		sb.WriteString(msg.OnCollision)
+	}
+	if msg.Manifest != "" {
+		sb.WriteString(", manifest")
+	}
+	if msg.HashPrefix != nil {
+		sb.WriteString(", hash-prefix")
+	}
+	if msg.OnSrcChange != "" {
+		sb.WriteString(", on-src-change:")
+		sb.WriteString(msg.OnSrcChange)
+	}
+	if msg.Diff {
+		sb.WriteString(", diff")
+	}
+	if msg.PreserveTags {
+		sb.WriteString(", tags")
+	}
+	if msg.PreserveOrder {
+		sb.WriteString(", order")
+	}
+	if msg.VerifyAfter {
+		sb.WriteString(", verify")
+	}
+	if msg.Snapshot {
+		sb.WriteString(", snapshot")
+	}
+	if msg.SSE != nil {
+		sb.WriteString(", sse")
+	}
+	if msg.Deadline != 0 {
+		sb.WriteString(", deadline")
+	}
+	if msg.RateLimit != 0 {
+		sb.WriteString(", rate-limit")
+	}
+	if msg.Webhook != "" {
+		sb.WriteString(", webhook")
+	}
+	if msg.Resume != "" {
+		sb.WriteString(", resume:")
+		sb.WriteString(msg.Resume)
+	} else if msg.Checkpoint {
+		sb.WriteString(", checkpoint")
+	}
+	if msg.Priority != "" && msg.Priority != TCBPriorityNormal {
+		sb.WriteString(", priority:")
+		sb.WriteString(msg.Priority)
+	}
+}
+
+///////////////////
+// HashPrefixMsg //
+///////////////////
+
+func (msg *HashPrefixMsg) Validate() error {
+	if msg.Len < 0 || msg.Len > HashPrefixMaxLen {
+		return fmt.Errorf("invalid hash prefix length %d (expecting up to %d)", msg.Len, HashPrefixMaxLen)
+	}
+	if msg.Alphabet == "" {
+		return nil
+	}
+	if len(msg.Alphabet) < 2 {
+		return fmt.Errorf("hash prefix alphabet %q is too short", msg.Alphabet)
+	}
+	for i := range len(msg.Alphabet) {
+		c := msg.Alphabet[i]
+		if c == '/' || c < ' ' || c > '~' || strings.IndexByte(msg.Alphabet[i+1:], c) >= 0 {
+			return fmt.Errorf("invalid hash prefix alphabet %q (expecting unique printable ASCII characters other than '/')",
+				msg.Alphabet)
+		}
+	}
+	return nil
+}
+
+func (msg *HashPrefixMsg) Hash(name string) string {
+	var (
+		l   = cos.NonZero(msg.Len, HashPrefixDefaultLen)
+		abc = msg.Alphabet
+	)
+	if abc == "" {
+		abc = HashPrefixDefaultAlphabet
+	}
+	return cos.HashPrefix(name, l, abc)
+}
+
+////////////
+// SSEMsg //
+////////////
+
+func (msg *SSEMsg) Validate() error {
+	switch msg.Algo {
+	case "", SSEAlgoKMS:
+	case SSEAlgoAES256:
+		if msg.KMSKey != "" {
+			return fmt.Errorf("server-side encryption: KMS key is not supported with %q", SSEAlgoAES256)
+		}
+	default:
+		return fmt.Errorf("invalid server-side encryption algorithm %q (expecting %q or %q)", msg.Algo, SSEAlgoAES256, SSEAlgoKMS)
+	}
+	return nil
+}
+
+// effective algorithm
+func (msg *SSEMsg) Algorithm() string {
+	switch {
+	case msg.Algo != "":
+		return msg.Algo
+	case msg.KMSKey != "":
+		return SSEAlgoKMS
+	default:
+		return SSEAlgoAES256
+	}
+}
+
+////////////////
+// ArchTCBMsg //
+////////////////
+
+// NOTE: archive format (Mime) is validated elsewhere (see cmn/archive)
+func (msg *ArchTCBMsg) Validate() error {
+	if msg.MaxEntries < 0 || msg.MaxSize < 0 {
+		return fmt.Errorf("invalid shard roll-over limits (max-entries %d, max-size %d)", msg.MaxEntries, msg.MaxSize)
+	}
+	if msg.Template == "" {
+		return nil
+	}
+	if !strings.Contains(msg.Template, TCBArchTid) || !strings.Contains(msg.Template, TCBArchSeq) {
+		return fmt.Errorf("shard name template %q must contain %s and %s", msg.Template, TCBArchTid, TCBArchSeq)
+	}
+	return nil
+}
+
+// returns the name of the seq-th shard produced by a given target
+func (msg *ArchTCBMsg) ShardName(tid string, seq int, ext string) string {
+	tmpl := msg.Template
+	if tmpl == "" {
+		tmpl = TCBArchDefaultTemplate
+	}
+	name := strings.NewReplacer(TCBArchTid, tid, TCBArchSeq, fmt.Sprintf("%06d", seq)).Replace(tmpl)
+	if !strings.HasSuffix(name, ext) {
+		name += ext
+	}
+	return name
 }
@@ -0,0 +1,26 @@
+// Package apc: API control messages and constants
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package apc
+
+// soft-deleted objects (see config.Space.TrashTime)
+type (
+	// ActListTrash
+	ListTrashMsg struct {
+		Prefix string `json:"prefix"` // object name prefix
+	}
+	TrashEntry struct {
+		Name    string `json:"name"`           // object name
+		Target  string `json:"target"`         // node ID
+		Size    int64  `json:"size,string"`    // bytes
+		Deleted int64  `json:"deleted,string"` // Unix nanoseconds (identifies a given soft-deleted version)
+		Expires int64  `json:"expires,string"` // Unix nanoseconds (when it gets purged)
+	}
+	TrashEntries []*TrashEntry
+
+	// ActUndelete
+	UndeleteMsg struct {
+		Deleted int64 `json:"deleted,string,omitempty"` // zero: the most recently deleted version
+	}
+)
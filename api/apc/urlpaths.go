@@ -31,6 +31,7 @@ const (
 	Users     = "users"    // AuthN
 	Clusters  = "clusters" // AuthN
 	Roles     = "roles"    // AuthN
+	OIDC      = "oidc"     // AuthN: OpenID Connect login
 	IC        = "ic"       // information center
 
 	// l3 ---
@@ -80,6 +81,11 @@ const (
 	ETLStart   = Start
 	ETLHealth  = "health"
 	ETLMetrics = "metrics"
+
+	// AuthN: OpenID Connect
+	OIDCLogin    = "login"
+	OIDCCallback = "callback"
+	OIDCToken    = "token"
 )
 
 // RESTful l3, internal use
@@ -166,6 +172,7 @@ var (
 	URLPathUsers    = urlpath(Version, Users)
 	URLPathClusters = urlpath(Version, Clusters)
 	URLPathRoles    = urlpath(Version, Roles)
+	URLPathOIDC     = urlpath(Version, OIDC)
 )
 
 func (u URLPath) Join(words ...string) string {
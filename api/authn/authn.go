@@ -81,6 +81,28 @@ func LoginUser(bp api.BaseParams, userID, pass string, expire *time.Duration) (t
 	return token, nil
 }
 
+// Exchange OpenID Connect ID token (issued by the identity provider configured with AuthN)
+// for AuthN token; see also LoginUser
+func LoginOIDC(bp api.BaseParams, idToken string, expire *time.Duration) (token *TokenMsg, err error) {
+	bp.Method = http.MethodPost
+	rec := OIDCLoginMsg{IDToken: idToken, ExpiresIn: expire}
+	reqParams := api.AllocRp()
+	defer api.FreeRp(reqParams)
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathOIDC.Join(apc.OIDCToken)
+		reqParams.Body = cos.MustMarshal(rec)
+		reqParams.Header = http.Header{cos.HdrContentType: []string{cos.ContentJSON}}
+	}
+	if _, err = reqParams.DoReqAny(&token); err != nil {
+		return nil, err
+	}
+	if token.Token == "" {
+		return nil, errors.New("login failed: empty response from AuthN server")
+	}
+	return token, nil
+}
+
 func RegisterCluster(bp api.BaseParams, cluSpec CluACL) error {
 	msg := cos.MustMarshal(cluSpec)
 	bp.Method = http.MethodPost
@@ -272,6 +294,25 @@ func RevokeToken(bp api.BaseParams, token string) error {
 	return reqParams.DoRequest()
 }
 
+// Derive short-lived token restricted to a single bucket (and, optionally, object name prefix)
+// and a subset of permissions from the caller's token (bp.Token); see ScopedTokenMsg
+func IssueScopedToken(bp api.BaseParams, scope *BckACL, expire *time.Duration) (token *TokenMsg, err error) {
+	bp.Method = http.MethodPost
+	msg := &ScopedTokenMsg{Scope: *scope, ExpiresIn: expire}
+	reqParams := api.AllocRp()
+	defer api.FreeRp(reqParams)
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathTokens.S
+		reqParams.Body = cos.MustMarshal(msg)
+		reqParams.Header = http.Header{cos.HdrContentType: []string{cos.ContentJSON}}
+	}
+	if _, err = reqParams.DoReqAny(&token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
 func GetConfig(bp api.BaseParams) (*Config, error) {
 	bp.Method = http.MethodGet
 	reqParams := api.AllocRp()
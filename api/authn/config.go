@@ -22,6 +22,8 @@ type (
 		Net     NetConf     `json:"net"`
 		Server  ServerConf  `json:"auth"`
 		Timeout TimeoutConf `json:"timeout"`
+		OIDC    OIDCConf    `json:"oidc"`
+		LDAP    LDAPConf    `json:"ldap"`
 		// private
 		mu sync.RWMutex `json:"-"`
 	}
@@ -48,6 +50,45 @@ type (
 	TimeoutConf struct {
 		Default cos.Duration `json:"default_timeout"`
 	}
+	// OpenID Connect: AuthN as a relying party (client) of an external identity provider
+	// (Okta, Keycloak, Google, etc.); users that log in via OIDC are not stored locally -
+	// their roles are derived from the ID token claims (see RoleMap)
+	OIDCConf struct {
+		Issuer       string   `json:"issuer"` // e.g. "https://accounts.google.com"; empty = OIDC disabled
+		ClientID     string   `json:"client_id"`
+		ClientSecret string   `json:"client_secret"`
+		RedirectURL  string   `json:"redirect_url"` // as registered with the provider: "<AuthN URL>/v1/oidc/callback"
+		Scopes       []string `json:"scopes,omitempty"`
+		UserClaim    string   `json:"user_claim,omitempty"`   // default: "email"
+		GroupsClaim  string   `json:"groups_claim,omitempty"` // default: "groups"
+		// maps ID token claims to AuthN roles:
+		// - key "<group>" matches (any of) the values of the GroupsClaim;
+		// - key "<claim>=<value>" matches a given (string) claim, e.g. "hd=example.com"
+		RoleMap      map[string][]string `json:"role_map,omitempty"`
+		DefaultRoles []string            `json:"default_roles,omitempty"` // assigned to all OIDC users
+	}
+	// LDAP (Active Directory) connector:
+	// - users that are not registered locally authenticate via LDAP bind;
+	// - their roles are derived from group memberships (GroupAttr, e.g. "memberOf") via RoleMap;
+	// - LDAP users and their roles are periodically synchronized into AuthN database,
+	//   so that users automatically gain (lose) permissions when added to (removed from) groups
+	LDAPConf struct {
+		URL          string `json:"url"`       // "ldap://host:389" or "ldaps://host:636"; empty = LDAP disabled
+		StartTLS     bool   `json:"start_tls"` // upgrade ldap:// connection to TLS
+		SkipVerify   bool   `json:"skip_verify"`
+		BindDN       string `json:"bind_dn"` // service account to search the directory
+		BindPassword string `json:"bind_password"`
+		UserBase     string `json:"user_base"` // e.g. "ou=people,dc=example,dc=com"
+		// default: "(&(objectClass=person)(<UserAttr>={username}))";
+		// AD example: "(&(objectClass=user)(sAMAccountName={username}))"
+		UserFilter string `json:"user_filter,omitempty"`
+		UserAttr   string `json:"user_attr,omitempty"`  // default: "uid" (AD: "sAMAccountName")
+		GroupAttr  string `json:"group_attr,omitempty"` // default: "memberOf"
+		// maps groups (full DN or common name, case-insensitive) to AuthN roles
+		RoleMap      map[string][]string `json:"role_map,omitempty"`
+		DefaultRoles []string            `json:"default_roles,omitempty"` // assigned to all LDAP users
+		SyncInterval cos.Duration        `json:"sync_interval"`           // 0 (zero): no periodic sync
+	}
 	ConfigToUpdate struct {
 		Server *ServerConfToSet `json:"auth"`
 	}
@@ -85,6 +126,10 @@ func (c *Config) Verbose() bool {
 	return level > 3
 }
 
+func (c *OIDCConf) Enabled() bool { return c.Issuer != "" }
+
+func (c *LDAPConf) Enabled() bool { return c.URL != "" }
+
 func (c *Config) Secret() string        { return *c.Server.psecret }
 func (c *Config) Expire() time.Duration { return time.Duration(*c.Server.pexpire) }
 
@@ -93,6 +138,9 @@ func (c *Config) SetSecret(val *string) {
 	c.Server.psecret = val
 }
 
+// use a given (e.g., KMS-decrypted) secret while keeping the configured value as is
+func (c *Config) UseSecret(val string) { c.Server.psecret = &val }
+
 func (c *Config) ApplyUpdate(cu *ConfigToUpdate) error {
 	if cu.Server == nil {
 		return errors.New("configuration is empty")
@@ -5,6 +5,7 @@
 package authn
 
 import (
+	"strings"
 	"time"
 
 	"github.com/NVIDIA/aistore/api/apc"
@@ -16,11 +17,24 @@ const (
 	AdminRole = "Admin"
 )
 
+// scoped tokens (see ScopedTokenMsg)
+const (
+	ScopedTokenDfltExpire = 15 * time.Minute
+)
+
+// user source (see User.Source)
+const (
+	UserLDAP = "ldap"
+)
+
 type (
 	User struct {
 		ID       string  `json:"id"`
 		Password string  `json:"pass,omitempty"`
 		Roles    []*Role `json:"roles"`
+		Source   string  `json:"source,omitempty"` // empty for local users; UserLDAP for users synchronized from LDAP
+		// optional capacity and object-count limits applied to all objects owned by the user
+		Quota *cmn.QuotaConf `json:"quota,omitempty"`
 	}
 
 	CluACL struct {
@@ -30,9 +44,12 @@ type (
 		URLs   []string        `json:"urls,omitempty"`
 	}
 
+	// Bucket ACL, optionally scoped to a subset of the bucket's objects (see BckACL.Match)
 	BckACL struct {
 		Bck    cmn.Bck         `json:"bck"`
+		Prefix string          `json:"prefix,omitempty"` // object name prefix or wildcard pattern
 		Access apc.AccessAttrs `json:"perm,string"`
+		Deny   bool            `json:"deny,omitempty"` // true: deny `Access` (takes precedence over all allow rules)
 	}
 
 	TokenMsg struct {
@@ -44,6 +61,21 @@ type (
 		ExpiresIn *time.Duration `json:"expires_in"`
 	}
 
+	// Request to derive a short-lived scoped token from the caller's (bearer) token:
+	// - scope: single bucket (with cluster UUID in the bucket's namespace, or any cluster when omitted),
+	//   optional object name prefix or pattern, and permissions (default: apc.AccessRO);
+	// - expiration: ScopedTokenDfltExpire when not specified; never exceeds the caller's token expiration
+	ScopedTokenMsg struct {
+		Scope     BckACL         `json:"scope"`
+		ExpiresIn *time.Duration `json:"expires_in"`
+	}
+
+	// ID token obtained by the client from the OIDC provider (with AuthN's client ID as the audience)
+	OIDCLoginMsg struct {
+		IDToken   string         `json:"id_token"`
+		ExpiresIn *time.Duration `json:"expires_in"`
+	}
+
 	RegisteredClusters struct {
 		Clusters map[string]*CluACL `json:"clusters,omitempty"`
 	}
@@ -87,6 +119,67 @@ func (clu *CluACL) String() string {
 	return uuid
 }
 
+////////////
+// BckACL //
+////////////
+
+// Match returns true if the object is within the ACL's scope:
+//   - empty prefix matches all objects in the bucket;
+//   - prefix without wildcards matches all object names that start with it, e.g. "raw/";
+//   - otherwise, the entire object name must match the pattern where
+//     '*' matches any sequence of characters (including '/') and '?' matches any single character,
+//     e.g. "raw/*", "user-*/tmp/*", "*.tar"
+func (acl *BckACL) Match(objName string) bool {
+	if acl.Prefix == "" {
+		return true
+	}
+	if !strings.ContainsAny(acl.Prefix, "*?") {
+		return strings.HasPrefix(objName, acl.Prefix)
+	}
+	return wildcardMatch(acl.Prefix, objName)
+}
+
+// Specificity is the number of literal (non-wildcard) characters in the prefix:
+// of several matching rules the most specific one applies
+func (acl *BckACL) Specificity() int {
+	return len(acl.Prefix) - strings.Count(acl.Prefix, "*") - strings.Count(acl.Prefix, "?")
+}
+
+func (acl *BckACL) String() string {
+	s := acl.Bck.Cname(acl.Prefix)
+	if acl.Deny {
+		return "deny " + s
+	}
+	return s
+}
+
+// iterative glob with single-star backtracking
+func wildcardMatch(pattern, name string) bool {
+	var (
+		p, n        int
+		star, match = -1, 0
+	)
+	for n < len(name) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == name[n]):
+			p++
+			n++
+		case p < len(pattern) && pattern[p] == '*':
+			star, match = p, n
+			p++
+		case star >= 0:
+			match++
+			p, n = star+1, match
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
 //////////////
 // TokenMsg //
 //////////////
@@ -297,3 +297,89 @@ func ECEncodeBucket(bp BaseParams, bck cmn.Bck, data, parity int, checkAndRecove
 	FreeRp(reqParams)
 	return
 }
+
+// List soft-deleted objects in a given bucket (see also UndeleteObject).
+// Soft-delete is enabled via cluster configuration (`space.trash_time`).
+func ListTrash(bp BaseParams, bck cmn.Bck, prefix string) (apc.TrashEntries, error) {
+	bp.Method = http.MethodGet
+	reqParams := AllocRp()
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathBuckets.Join(bck.Name)
+		reqParams.Body = cos.MustMarshal(apc.ActMsg{Action: apc.ActListTrash, Value: apc.ListTrashMsg{Prefix: prefix}})
+		reqParams.Header = http.Header{cos.HdrContentType: []string{cos.ContentJSON}}
+		reqParams.Query = bck.NewQuery()
+	}
+	entries := apc.TrashEntries{}
+	_, err := reqParams.DoReqAny(&entries)
+	FreeRp(reqParams)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+//
+// bucket snapshots
+//
+
+// Create named point-in-time snapshot of a given (ais) bucket.
+// Snapshots are copy-on-write: creation does not copy the objects; see also RestoreSnapshot.
+func CreateSnapshot(bp BaseParams, bck cmn.Bck, name string) error {
+	return _snapshot(bp, bck, apc.ActCreateSnapshot, &apc.SnapshotMsg{Name: name})
+}
+
+func DestroySnapshot(bp BaseParams, bck cmn.Bck, name string) error {
+	return _snapshot(bp, bck, apc.ActDestroySnapshot, &apc.SnapshotMsg{Name: name})
+}
+
+// Restore bucket content - and optionally, its props - as of the time of snapshot.
+// Objects created after the snapshot get removed. Requires admin permission.
+func RestoreSnapshot(bp BaseParams, bck cmn.Bck, name string, props bool) error {
+	return _snapshot(bp, bck, apc.ActRestoreSnapshot, &apc.SnapshotMsg{Name: name, Props: props})
+}
+
+func _snapshot(bp BaseParams, bck cmn.Bck, action string, msg *apc.SnapshotMsg) error {
+	bp.Method = http.MethodPost
+	reqParams := AllocRp()
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathBuckets.Join(bck.Name)
+		reqParams.Body = cos.MustMarshal(apc.ActMsg{Action: action, Value: msg})
+		reqParams.Header = http.Header{cos.HdrContentType: []string{cos.ContentJSON}}
+		reqParams.Query = bck.NewQuery()
+	}
+	err := reqParams.DoRequest()
+	FreeRp(reqParams)
+	return err
+}
+
+// List bucket snapshots (all of them when `name` is empty).
+func ListSnapshots(bp BaseParams, bck cmn.Bck, name string) (cmn.Snapshots, error) {
+	snaps := cmn.Snapshots{}
+	err := _lsnap(bp, bck, apc.ActListSnapshots, &apc.SnapshotMsg{Name: name}, &snaps)
+	return snaps, err
+}
+
+// List objects captured by a given snapshot; to read any of them, use GetObject
+// with `apc.QparamSnapshot` query.
+func BrowseSnapshot(bp BaseParams, bck cmn.Bck, name, prefix string) (cmn.LsoEntries, error) {
+	entries := cmn.LsoEntries{}
+	err := _lsnap(bp, bck, apc.ActBrowseSnapshot, &apc.SnapshotMsg{Name: name, Prefix: prefix}, &entries)
+	return entries, err
+}
+
+func _lsnap(bp BaseParams, bck cmn.Bck, action string, msg *apc.SnapshotMsg, v any) error {
+	bp.Method = http.MethodGet
+	reqParams := AllocRp()
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathBuckets.Join(bck.Name)
+		reqParams.Body = cos.MustMarshal(apc.ActMsg{Action: action, Value: msg})
+		reqParams.Header = http.Header{cos.HdrContentType: []string{cos.ContentJSON}}
+		reqParams.Query = bck.NewQuery()
+	}
+	_, err := reqParams.DoReqAny(v)
+	FreeRp(reqParams)
+	return err
+}
@@ -170,6 +170,21 @@ func GetRemoteAIS(bp BaseParams) (remais meta.RemAisVec, err error) {
 	return
 }
 
+// cluster-wide usage by bucket and by user (see cmn.QuotaConf)
+func GetQuotaUsage(bp BaseParams) (usage *apc.QuotaUsage, err error) {
+	bp.Method = http.MethodGet
+	reqParams := AllocRp()
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathClu.S
+		reqParams.Query = url.Values{apc.QparamWhat: []string{apc.WhatQuotaUsage}}
+	}
+	usage = &apc.QuotaUsage{}
+	_, err = reqParams.DoReqAny(usage)
+	FreeRp(reqParams)
+	return
+}
+
 // (see also enable/disable backend below)
 func GetConfiguredBackends(bp BaseParams) (out []string, err error) {
 	bp.Method = http.MethodGet
@@ -537,3 +552,33 @@ func RemoveNodeUnsafe(bp BaseParams, sid string) error {
 	FreeRp(reqParams)
 	return err
 }
+
+// SetRebWeights replaces per-target rebalance (placement) weights (empty - resets all to default)
+// and starts rebalance that moves the data accordingly; returns rebalance ID
+func SetRebWeights(bp BaseParams, weights apc.RebWeights) (xid string, err error) {
+	msg := apc.ActMsg{Action: apc.ActSetRebWeights, Value: weights}
+	bp.Method = http.MethodPut
+	reqParams := AllocRp()
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathClu.S
+		reqParams.Body = cos.MustMarshal(msg)
+		reqParams.Header = http.Header{cos.HdrContentType: []string{cos.ContentJSON}}
+	}
+	_, err = reqParams.doReqStr(&xid)
+	FreeRp(reqParams)
+	return
+}
+
+func GetRebWeights(bp BaseParams) (weights apc.RebWeights, err error) {
+	bp.Method = http.MethodGet
+	reqParams := AllocRp()
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathClu.S
+		reqParams.Query = url.Values{apc.QparamWhat: []string{apc.WhatRebWeights}}
+	}
+	_, err = reqParams.DoReqAny(&weights)
+	FreeRp(reqParams)
+	return
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/audit"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/core/meta"
 )
@@ -166,6 +167,30 @@ func GetDaemonLog(bp BaseParams, node *meta.Snode, args GetLogInput) (int64, err
 	return 0, err
 }
 
+// Returns up to `count` most recent audit events recorded by a given node (oldest first);
+// zero count means all events the node keeps in memory; optionally, filter by bucket.
+func GetAuditLog(bp BaseParams, node *meta.Snode, count int, bck *cmn.Bck) (events []audit.Event, err error) {
+	q := make(url.Values, 3)
+	q.Set(apc.QparamWhat, apc.WhatAudit)
+	if count > 0 {
+		q.Set(apc.QparamAuditCount, strconv.Itoa(count))
+	}
+	if bck != nil {
+		q.Set(apc.QparamAuditBucket, bck.Cname(""))
+	}
+	bp.Method = http.MethodGet
+	reqParams := AllocRp()
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathReverseDae.S
+		reqParams.Query = q
+		reqParams.Header = http.Header{apc.HdrNodeID: []string{node.ID()}}
+	}
+	_, err = reqParams.DoReqAny(&events)
+	FreeRp(reqParams)
+	return events, err
+}
+
 // SetDaemonConfig, given key value pairs, sets the configuration accordingly for a specific node.
 func SetDaemonConfig(bp BaseParams, nodeID string, nvs cos.StrKVs, transient ...bool) error {
 	bp.Method = http.MethodPut
@@ -24,6 +24,9 @@ var (
 		AdminPassword string
 		AdminUsername string
 		SecretKey     string
+		SecretKMSKey  string
+		OIDCSecret    string
+		LDAPPassword  string
 	}{
 		Enabled:       "AIS_AUTHN_ENABLED",
 		URL:           "AIS_AUTHN_URL",
@@ -38,6 +41,9 @@ var (
 		ServerCrt:     "AIS_SERVER_CRT",
 		ServerKey:     "AIS_SERVER_KEY",
 		SecretKey:     "AIS_AUTHN_SECRET_KEY",
+		SecretKMSKey:  "AIS_AUTHN_SECRET_KMS_KEY",     // KMS key ID: the secret (above or configured) is KMS-encrypted (see env.KMS)
+		OIDCSecret:    "AIS_AUTHN_OIDC_CLIENT_SECRET", // overrides oidc.client_secret
+		LDAPPassword:  "AIS_AUTHN_LDAP_BIND_PASSWORD", // overrides ldap.bind_password
 		AdminUsername: "AIS_AUTHN_SU_NAME",
 		AdminPassword: "AIS_AUTHN_SU_PASS",
 	}
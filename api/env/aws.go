@@ -32,4 +32,16 @@ var (
 		Region:   "AWS_REGION",
 		Profile:  "AWS_PROFILE",
 	}
+
+	// Ceph RGW admin-ops API (optional) to enumerate buckets and report RGW-side usage and quotas;
+	// admin credentials default to the S3 ones (and require "buckets=read" capability)
+	RGW = struct {
+		AdminURL  string
+		AccessKey string
+		SecretKey string
+	}{
+		AdminURL:  "AIS_RGW_ADMIN_URL", // e.g. http://rgw.example.com:8080/admin
+		AccessKey: "AIS_RGW_ADMIN_ACCESS_KEY",
+		SecretKey: "AIS_RGW_ADMIN_SECRET_KEY",
+	}
 )
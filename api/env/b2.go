@@ -0,0 +1,28 @@
+// Package env contains environment variables
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package env
+
+// Backblaze B2 application key (the same names as in the `b2` command-line tool)
+// and optional overrides:
+// - endpoint: authorization (b2_authorize_account) URL, e.g. for testing
+// - large file threshold: objects of this size and larger are uploaded as B2 large files
+//   (multiple parts; default 200MiB)
+// - part size: large file part size (default: as recommended by b2_authorize_account)
+
+var (
+	B2 = struct {
+		KeyID              string
+		Key                string
+		Endpoint           string
+		LargeFileThreshold string
+		PartSize           string
+	}{
+		KeyID:              "B2_APPLICATION_KEY_ID",
+		Key:                "B2_APPLICATION_KEY",
+		Endpoint:           "B2_ENDPOINT",
+		LargeFileThreshold: "B2_LARGE_FILE_THRESHOLD",
+		PartSize:           "B2_PART_SIZE",
+	}
+)
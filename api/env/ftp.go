@@ -0,0 +1,34 @@
+// Package env contains environment variables
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package env
+
+// SFTP and FTP(S) servers:
+// - URL: protocol and root directory, e.g. sftp://user@host:22/outgoing or ftps://host/drop
+//   (ftps: explicit TLS, a.k.a. AUTH TLS; plain ftp:// is also supported)
+// - username and password (take precedence over URL's userinfo); anonymous FTP when none
+// - SSH key: SFTP private key file (public key authentication)
+// - known hosts: SFTP host key verification (default: ~/.ssh/known_hosts)
+// - skip-verify: do not verify server's host key (SFTP) or TLS certificate (FTPS)
+// - max connections: max number of concurrently open connections (default 8)
+
+var (
+	FTP = struct {
+		URL        string
+		Username   string
+		Password   string
+		SSHKey     string
+		KnownHosts string
+		SkipVerify string
+		MaxConns   string
+	}{
+		URL:        "AIS_FTP_URL",
+		Username:   "AIS_FTP_USERNAME",
+		Password:   "AIS_FTP_PASSWORD",
+		SSHKey:     "AIS_FTP_SSH_KEY",
+		KnownHosts: "AIS_FTP_KNOWN_HOSTS",
+		SkipVerify: "AIS_FTP_SKIP_VERIFY",
+		MaxConns:   "AIS_FTP_MAX_CONNS",
+	}
+)
@@ -0,0 +1,28 @@
+// Package env contains environment variables
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package env
+
+// Key management service (KMS) for server-side encryption at rest (see cmn.EncryptionConf)
+// and KMS-encrypted AuthN secret (see AuthN.SecretKMSKey):
+// - provider: "keyfile", "vault", or "aws" (the latter requires `aws` build tag);
+// - keyfile: directory containing base64-encoded AES-256 master keys, one file per key ID;
+// - vault: HashiCorp Vault transit secrets engine (address, token, and mount path - default "transit");
+// - aws: AWS KMS in the default region (see AwsDefaultRegion) with the default credentials chain.
+
+var (
+	KMS = struct {
+		Provider   string
+		KeyDir     string
+		VaultAddr  string
+		VaultToken string
+		VaultMount string
+	}{
+		Provider:   "AIS_KMS",
+		KeyDir:     "AIS_KMS_KEYDIR",
+		VaultAddr:  "VAULT_ADDR",
+		VaultToken: "VAULT_TOKEN",
+		VaultMount: "AIS_KMS_VAULT_MOUNT",
+	}
+)
@@ -0,0 +1,19 @@
+// Package env contains environment variables
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package env
+
+// POSIX file system mounted on every target (NFS, Lustre, GPFS, etc.):
+// - root: mount point (or any directory under it); each top-level directory is a bucket
+// - max readers: max number of concurrently open files (default 64)
+
+var (
+	POSIX = struct {
+		Root       string
+		MaxReaders string
+	}{
+		Root:       "AIS_POSIX_ROOT",
+		MaxReaders: "AIS_POSIX_MAX_READERS",
+	}
+)
@@ -0,0 +1,24 @@
+// Package env contains environment variables
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package env
+
+// WebDAV server (e.g., Nextcloud: https://<host>/remote.php/dav/files/<user>):
+// - URL: root collection; each top-level (sub)collection is a bucket
+// - username and password: basic authentication (optional)
+// - skip-verify: do not verify server's TLS certificate (e.g., self-signed)
+
+var (
+	WebDAV = struct {
+		URL        string
+		Username   string
+		Password   string
+		SkipVerify string
+	}{
+		URL:        "AIS_WEBDAV_URL",
+		Username:   "AIS_WEBDAV_USERNAME",
+		Password:   "AIS_WEBDAV_PASSWORD",
+		SkipVerify: "AIS_WEBDAV_SKIP_VERIFY",
+	}
+)
@@ -5,6 +5,8 @@
 package api
 
 import (
+	"crypto/md5"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -40,6 +42,7 @@ type (
 		// - `apc.QparamOrigURL`: GET from a vanilla http(s) location (`ht://` bucket with the corresponding `OrigURLBck`)
 		// - `apc.QparamSilent`: do not log errors
 		// - `apc.QparamLatestVer`: get latest version from the associated Cloud bucket; see also: `ValidateWarmGet`
+		// - `apc.QparamSnapshot`: get the object's version captured by a given bucket snapshot (see CreateSnapshot)
 		// - and also a group of parameters used to read aistore-supported serialized archives ("shards"),
 		//   namely:
 		//   - `apc.QparamArchpath`
@@ -55,6 +58,8 @@ type (
 		//   For range formatting, see https://www.rfc-editor.org/rfc/rfc7233#section-2.1
 		// E.g. blob download:
 		// * Header.Set(apc.HdrBlobDownload, "true")
+		// E.g. object encrypted with client-supplied key:
+		// * SetSSECustomerKey(Header, key)
 		Header http.Header
 	}
 
@@ -92,6 +97,23 @@ type (
 		// - we massively write a new content into a bucket, and/or
 		// - we simply don't care.
 		SkipVC bool
+
+		// optional time-to-live: the object expires (and gets eventually removed) TTL from now
+		// (see apc.HdrObjTTL)
+		TTL time.Duration
+
+		// optional object lock retention (buckets with object lock enabled only);
+		// zero: bucket's default (see apc.HdrObjRetainUntil)
+		RetainUntil time.Time
+
+		// optional client-supplied 256-bit encryption key: the object gets encrypted with it
+		// and subsequently cannot be read without it (see SetSSECustomerKey)
+		SSECustomerKey []byte
+
+		// active-active replication between clusters (internal use):
+		// version vector and origin write (see apc.HdrObjReplVV and apc.HdrObjReplWrite)
+		ReplVV    string
+		ReplWrite string
 	}
 )
 
@@ -282,10 +304,31 @@ func (args *PutArgs) put(reqArgs *cmn.HreqArgs) (*http.Request, error) {
 	if args.Size != 0 {
 		req.ContentLength = int64(args.Size) // as per https://tools.ietf.org/html/rfc7230#section-3.3.2
 	}
+	if args.TTL > 0 {
+		req.Header.Set(apc.HdrObjTTL, args.TTL.String())
+	}
+	if !args.RetainUntil.IsZero() {
+		req.Header.Set(apc.HdrObjRetainUntil, strconv.FormatInt(args.RetainUntil.Unix(), 10))
+	}
+	if args.SSECustomerKey != nil {
+		SetSSECustomerKey(req.Header, args.SSECustomerKey)
+	}
+	if args.ReplVV != "" {
+		req.Header.Set(apc.HdrObjReplVV, args.ReplVV)
+		req.Header.Set(apc.HdrObjReplWrite, args.ReplWrite)
+	}
 	SetAuxHeaders(req, &args.BaseParams)
 	return req, nil
 }
 
+// client-supplied encryption key: algorithm, the key, and its MD5 (the key is never stored)
+func SetSSECustomerKey(hdr http.Header, key []byte) {
+	sum := md5.Sum(key)
+	hdr.Set(apc.HdrSSECustomerAlgo, "AES256")
+	hdr.Set(apc.HdrSSECustomerKey, base64.StdEncoding.EncodeToString(key))
+	hdr.Set(apc.HdrSSECustomerKeyMD5, base64.StdEncoding.EncodeToString(sum[:]))
+}
+
 func PutObject(args *PutArgs) (oah ObjAttrs, err error) {
 	var (
 		resp  *http.Response
@@ -415,6 +458,56 @@ func DeleteObject(bp BaseParams, bck cmn.Bck, objName string) error {
 	return err
 }
 
+// bypass governance-mode object lock retention (requires admin permission)
+func DeleteLockedObject(bp BaseParams, bck cmn.Bck, objName string) error {
+	bp.Method = http.MethodDelete
+	q := bck.NewQuery()
+	q.Set(apc.QparamBypassGovernance, "true")
+	reqParams := AllocRp()
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathObjects.Join(bck.Name, objName)
+		reqParams.Query = q
+	}
+	err := reqParams.DoRequest()
+	FreeRp(reqParams)
+	return err
+}
+
+// restore soft-deleted object: the version deleted at a given time (see apc.TrashEntry),
+// or the most recently deleted one when `deleted` is zero
+func UndeleteObject(bp BaseParams, bck cmn.Bck, objName string, deleted int64) error {
+	bp.Method = http.MethodPost
+	reqParams := AllocRp()
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathObjects.Join(bck.Name, objName)
+		reqParams.Body = cos.MustMarshal(apc.ActMsg{Action: apc.ActUndelete, Value: apc.UndeleteMsg{Deleted: deleted}})
+		reqParams.Header = http.Header{cos.HdrContentType: []string{cos.ContentJSON}}
+		reqParams.Query = bck.NewQuery()
+	}
+	err := reqParams.DoRequest()
+	FreeRp(reqParams)
+	return err
+}
+
+// object lock: retention can be extended but not shortened
+func ExtendObjectRetention(bp BaseParams, bck cmn.Bck, objName string, until time.Time) error {
+	bp.Method = http.MethodPost
+	msg := apc.RetentionMsg{RetainUntil: until.Unix()}
+	reqParams := AllocRp()
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathObjects.Join(bck.Name, objName)
+		reqParams.Body = cos.MustMarshal(apc.ActMsg{Action: apc.ActExtendRetain, Value: msg})
+		reqParams.Header = http.Header{cos.HdrContentType: []string{cos.ContentJSON}}
+		reqParams.Query = bck.NewQuery()
+	}
+	err := reqParams.DoRequest()
+	FreeRp(reqParams)
+	return err
+}
+
 // Evict(object) ======================================================================================
 
 func EvictObject(bp BaseParams, bck cmn.Bck, objName string) error {
@@ -526,6 +619,30 @@ func AppendObject(args *AppendArgs) (string /*handle*/, error) {
 	return wresp.Header.Get(apc.HdrAppendHandle), err
 }
 
+// ExtendObject atomically appends the content (`args.Reader`) to an existing object
+// (or creates a new one) in a single call - no handles and no flushing (`args.Handle` is ignored).
+// Returns the offset at which the appended content starts, i.e., the object's previous size.
+func ExtendObject(args *AppendArgs) (int64, error) {
+	q := make(url.Values, 4)
+	q.Set(apc.QparamAppendType, apc.ExtendOp)
+	q = args.Bck.AddToQuery(q)
+
+	reqArgs := cmn.AllocHra()
+	{
+		reqArgs.Method = http.MethodPut
+		reqArgs.Base = args.BaseParams.URL
+		reqArgs.Path = apc.URLPathObjects.Join(args.Bck.Name, args.Object)
+		reqArgs.Query = q
+		reqArgs.BodyR = args.Reader
+	}
+	wresp, err := DoWithRetry(args.BaseParams.Client, args._append, reqArgs) //nolint:bodyclose // it's closed inside
+	cmn.FreeHra(reqArgs)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(wresp.Header.Get(apc.HdrAppendOffset), 10, 64)
+}
+
 // FlushObject must be called after all the appends (via `api.AppendObject`).
 // To "flush", it uses the handle returned by `api.AppendObject`.
 // This call will create a fully operational and accessible object.
@@ -576,6 +693,23 @@ func RenameObject(bp BaseParams, bck cmn.Bck, oldName, newName string) error {
 	return err
 }
 
+// PresignObject returns time-limited URL to GET or PUT the specified object without AuthN token
+// (see apc.PresignArgs for defaults and limits)
+func PresignObject(bp BaseParams, bck cmn.Bck, objName string, args *apc.PresignArgs) (url string, err error) {
+	bp.Method = http.MethodPost
+	reqParams := AllocRp()
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathObjects.Join(bck.Name, objName)
+		reqParams.Body = cos.MustMarshal(apc.ActMsg{Action: apc.ActPresign, Value: args})
+		reqParams.Header = http.Header{cos.HdrContentType: []string{cos.ContentJSON}}
+		reqParams.Query = bck.NewQuery()
+	}
+	_, err = reqParams.doReqStr(&url)
+	FreeRp(reqParams)
+	return url, err
+}
+
 // Promote =========================================================================================
 // promote POSIX files and/or directories to (become) in-cluster objects.
 
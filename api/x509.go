@@ -12,6 +12,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn/cos"
 )
 
+// LoadX509Cert (re)loads the certificate on the specified node or, otherwise, on all nodes
 func LoadX509Cert(bp BaseParams, nodeID ...string) error {
 	bp.Method = http.MethodPut
 	reqParams := AllocRp()
@@ -28,6 +29,8 @@ func LoadX509Cert(bp BaseParams, nodeID ...string) error {
 	return err
 }
 
+// GetX509Info returns the certificate of the node (when specified) or the one it is called upon
+// (see also GetClusterX509Info)
 func GetX509Info(bp BaseParams, nodeID ...string) (info cos.StrKVs, err error) {
 	bp.Method = http.MethodGet
 	reqParams := AllocRp()
@@ -47,3 +50,18 @@ func GetX509Info(bp BaseParams, nodeID ...string) (info cos.StrKVs, err error) {
 	FreeRp(reqParams)
 	return
 }
+
+// GetClusterX509Info returns all nodes' certificates: subject, SANs, validity bounds, fingerprint, and more;
+// to force-reload, see LoadX509Cert
+func GetClusterX509Info(bp BaseParams) (info map[string]cos.StrKVs, err error) {
+	bp.Method = http.MethodGet
+	reqParams := AllocRp()
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathClu.S
+		reqParams.Query = url.Values{apc.QparamWhat: []string{apc.WhatCertificate}}
+	}
+	_, err = reqParams.DoReqAny(&info)
+	FreeRp(reqParams)
+	return
+}
@@ -58,6 +58,54 @@ func AbortXaction(bp BaseParams, args *xact.ArgsMsg) (err error) {
 	return
 }
 
+// pause running xaction(s) - e.g., copy-bucket or rebalance - without losing progress
+// (see `xact.Descriptor.Pausable` for supported kinds)
+func PauseXaction(bp BaseParams, args *xact.ArgsMsg) error {
+	return _pauseResume(bp, args, apc.ActXactPause)
+}
+
+func ResumeXaction(bp BaseParams, args *xact.ArgsMsg) error {
+	return _pauseResume(bp, args, apc.ActXactResume)
+}
+
+func _pauseResume(bp BaseParams, args *xact.ArgsMsg, action string) (err error) {
+	msg := apc.ActMsg{Action: action, Value: args}
+	bp.Method = http.MethodPut
+	reqParams := AllocRp()
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathClu.S
+		reqParams.Body = cos.MustMarshal(msg)
+		reqParams.Header = http.Header{cos.HdrContentType: []string{cos.ContentJSON}}
+		reqParams.Query = args.Bck.NewQuery()
+	}
+	err = reqParams.DoRequest()
+	FreeRp(reqParams)
+	return
+}
+
+// change bandwidth limit (bytes per second, per target; zero: unlimited) of a running
+// copy (transform) bucket job (see apc.CopyBckMsg.RateLimit)
+func SetXactionRateLimit(bp BaseParams, xid string, bps int64) error {
+	msg := &xact.TCBCtlMsg{ArgsMsg: xact.ArgsMsg{ID: xid}, RateLimit: bps}
+	return _tcbCtl(bp, apc.ActXactRateLimit, msg)
+}
+
+func _tcbCtl(bp BaseParams, action string, ctl *xact.TCBCtlMsg) (err error) {
+	msg := apc.ActMsg{Action: action, Value: ctl}
+	bp.Method = http.MethodPut
+	reqParams := AllocRp()
+	{
+		reqParams.BaseParams = bp
+		reqParams.Path = apc.URLPathClu.S
+		reqParams.Body = cos.MustMarshal(msg)
+		reqParams.Header = http.Header{cos.HdrContentType: []string{cos.ContentJSON}}
+	}
+	err = reqParams.DoRequest()
+	FreeRp(reqParams)
+	return
+}
+
 //
 // querying and waiting
 //
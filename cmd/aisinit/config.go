@@ -115,10 +115,11 @@ var (
 	}
 
 	defaultSpace = aiscmn.SpaceConf{
-		CleanupWM: 65,
-		LowWM:     75,
-		HighWM:    90,
-		OOS:       95,
+		CleanupWM:  65,
+		LowWM:      75,
+		HighWM:     90,
+		OOS:        95,
+		ExpireTime: cos.Duration(time.Hour),
 	}
 
 	defaultMemsys = aiscmn.MemsysConf{
@@ -5,16 +5,27 @@
 package main
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/NVIDIA/aistore/api/authn"
+	"github.com/NVIDIA/aistore/api/env"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/jsp"
+	"github.com/NVIDIA/aistore/cmn/kms"
 	jsoniter "github.com/json-iterator/go"
 )
 
 var Conf = &authn.Config{}
 
+// KMS-encrypted secret (see env.AuthN.SecretKMSKey)
+var secretKMS struct {
+	k     kms.KMS
+	keyID string
+}
+
 func configHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
@@ -45,8 +56,21 @@ func httpConfigPut(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// with KMS, the new secret is expected to be encrypted as well
+	var secret string
+	if secretKMS.k != nil && updateCfg.Server != nil && updateCfg.Server.Secret != nil {
+		var err error
+		if secret, err = decryptSecret(*updateCfg.Server.Secret); err != nil {
+			cmn.WriteErr(w, r, err)
+			return
+		}
+	}
+
 	Conf.Lock()
 	err := Conf.ApplyUpdate(updateCfg)
+	if err == nil && secret != "" {
+		Conf.UseSecret(secret)
+	}
 	Conf.Unlock()
 	if err != nil {
 		cmn.WriteErr(w, r, err)
@@ -57,3 +81,35 @@ func httpConfigPut(w http.ResponseWriter, r *http.Request) {
 		cmn.WriteErr(w, r, err)
 	}
 }
+
+// the configured secret is a base64-encoded ciphertext produced by KMS with the key `keyID`
+func initSecretKMS(keyID string) (err error) {
+	if secretKMS.k, err = kms.New(); err != nil {
+		return err
+	}
+	if secretKMS.k == nil {
+		return fmt.Errorf("%s is set but KMS is not configured (see %s)", env.AuthN.SecretKMSKey, env.KMS.Provider)
+	}
+	secretKMS.keyID = keyID
+	secret, err := decryptSecret(Conf.Secret())
+	if err != nil {
+		return err
+	}
+	Conf.UseSecret(secret)
+	return nil
+}
+
+func decryptSecret(b64 string) (string, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return "", fmt.Errorf("invalid KMS-encrypted secret (expecting base64): %v", err)
+	}
+	plain, err := secretKMS.k.Decrypt(secretKMS.keyID, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("%s KMS: failed to decrypt secret (key ID %q): %w", secretKMS.k.Name(), secretKMS.keyID, err)
+	}
+	if len(plain) == 0 {
+		return "", errors.New("KMS-decrypted secret is empty")
+	}
+	return string(plain), nil
+}
@@ -23,14 +23,18 @@ import (
 const svcName = "AuthN"
 
 type hserv struct {
-	mux *http.ServeMux
-	s   *http.Server
-	mgr *mgr
+	mux  *http.ServeMux
+	s    *http.Server
+	mgr  *mgr
+	oidc *oidcRP // nil when OIDC is not configured
 }
 
 func newServer(mgr *mgr) *hserv {
 	srv := &hserv{mgr: mgr}
 	srv.mux = http.NewServeMux()
+	if Conf.OIDC.Enabled() {
+		srv.oidc = newOIDC(mgr, &Conf.OIDC)
+	}
 
 	return srv
 }
@@ -112,6 +116,9 @@ func (h *hserv) registerPublicHandlers() {
 	h.registerHandler(apc.URLPathClusters.S, h.clusterHandler)
 	h.registerHandler(apc.URLPathRoles.S, h.roleHandler)
 	h.registerHandler(apc.URLPathDae.S, configHandler)
+	if h.oidc != nil {
+		h.registerHandler(apc.URLPathOIDC.S, h.oidc.handler)
+	}
 }
 
 func (h *hserv) userHandler(w http.ResponseWriter, r *http.Request) {
@@ -133,8 +140,10 @@ func (h *hserv) tokenHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodDelete:
 		h.httpRevokeToken(w, r)
+	case http.MethodPost:
+		h.httpScopedToken(w, r)
 	default:
-		cmn.WriteErr405(w, r, http.MethodDelete)
+		cmn.WriteErr405(w, r, http.MethodDelete, http.MethodPost)
 	}
 }
 
@@ -174,6 +183,30 @@ func (h *hserv) httpRevokeToken(w http.ResponseWriter, r *http.Request) {
 	h.mgr.revokeToken(msg.Token)
 }
 
+// Derives short-lived scoped token from the caller's one (see authn.ScopedTokenMsg)
+func (h *hserv) httpScopedToken(w http.ResponseWriter, r *http.Request) {
+	if _, err := parseURL(w, r, 0, apc.URLPathTokens.L); err != nil {
+		return
+	}
+	parent, err := tok.ExtractToken(r.Header)
+	if err != nil {
+		cmn.WriteErr(w, r, err, http.StatusUnauthorized)
+		return
+	}
+	msg := &authn.ScopedTokenMsg{}
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		return
+	}
+	token, err := h.mgr.issueScopedToken(parent, msg)
+	if err != nil {
+		nlog.Errorf("failed to generate scoped token: %v", err)
+		cmn.WriteErr(w, r, err, http.StatusUnauthorized)
+		return
+	}
+	repl := fmt.Sprintf(`{"token": %q}`, token)
+	writeBytes(w, cos.UnsafeB(repl), "scoped-token")
+}
+
 func (h *hserv) httpUserDel(w http.ResponseWriter, r *http.Request) {
 	apiItems, err := parseURL(w, r, 1, apc.URLPathUsers.L)
 	if err != nil {
@@ -290,6 +323,10 @@ func getToken(r *http.Request) (*tok.Token, error) {
 	if tk.Expires.Before(time.Now()) {
 		return nil, fmt.Errorf("not authorized (token expired): %s", tk)
 	}
+	if tk.Scope != nil {
+		// scoped tokens are for data access only
+		return nil, fmt.Errorf("not authorized (%v): %s", tok.ErrScopedToken, tk)
+	}
 	return tk, nil
 }
 
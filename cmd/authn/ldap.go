@@ -0,0 +1,260 @@
+// Package authn is authentication server for AIStore.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/authn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+)
+
+// LDAP (Active Directory) connector (see authn.LDAPConf):
+// - login: search the user with the service account, bind as the user to verify the password,
+//   map user's groups to AuthN roles, and store (update) the user in the DB;
+// - sync: periodically read all users (and their groups) from the directory, add/update LDAP
+//   users in the DB and remove those that are gone or no longer belong to any mapped group.
+// Local users take precedence: LDAP never overrides a locally registered user with the same name.
+
+const (
+	ldapDfltUserAttr  = "uid"
+	ldapDfltGroupAttr = "memberOf"
+	ldapDfltTimeout   = 30 * time.Second
+
+	ldapUsernamePlaceholder = "{username}"
+)
+
+type ldapSync struct {
+	m    *mgr
+	conf *authn.LDAPConf
+	mu   sync.Mutex // serializes directory => DB updates
+}
+
+var errLDAPNoRoles = errors.New("LDAP: no AuthN roles mapped to the user")
+
+func newLDAP(m *mgr, conf *authn.LDAPConf) *ldapSync { return &ldapSync{m: m, conf: conf} }
+
+func (*ldapSync) String() string { return "LDAP" }
+
+func (ls *ldapSync) userAttr() string  { return cos.Left(ls.conf.UserAttr, ldapDfltUserAttr) }
+func (ls *ldapSync) groupAttr() string { return cos.Left(ls.conf.GroupAttr, ldapDfltGroupAttr) }
+
+// NOTE: `uname` must be escaped (see ldapEscape) unless it's the "*" wildcard
+func (ls *ldapSync) userFilter(uname string) string {
+	filter := ls.conf.UserFilter
+	if filter == "" {
+		filter = "(&(objectClass=person)(" + ls.userAttr() + "=" + ldapUsernamePlaceholder + "))"
+	}
+	return strings.ReplaceAll(filter, ldapUsernamePlaceholder, uname)
+}
+
+func (ls *ldapSync) connect() (*ldapConn, error) {
+	timeout := time.Duration(Conf.Timeout.Default)
+	if timeout == 0 {
+		timeout = ldapDfltTimeout
+	}
+	tlsConf := &tls.Config{InsecureSkipVerify: ls.conf.SkipVerify} //nolint:gosec // (configurable)
+	lc, err := ldapDial(ls.conf.URL, ls.conf.StartTLS, tlsConf, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("LDAP: failed to connect to %s: %w", ls.conf.URL, err)
+	}
+	if ls.conf.BindDN != "" {
+		if err := lc.bind(ls.conf.BindDN, ls.conf.BindPassword); err != nil {
+			lc.close()
+			return nil, fmt.Errorf("LDAP: service account bind failed: %w", err)
+		}
+	}
+	return lc, nil
+}
+
+// authenticate user via LDAP bind; update user's roles in the DB
+func (ls *ldapSync) authenticate(uid, pwd string) (*authn.User, error) {
+	if pwd == "" { // unauthenticated bind always succeeds
+		return nil, errInvalidCredentials
+	}
+	lc, err := ls.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer lc.close()
+
+	entries, err := lc.search(ls.conf.UserBase, ls.userFilter(ldapEscape(uid)), []string{ls.userAttr(), ls.groupAttr()})
+	if err != nil {
+		return nil, fmt.Errorf("LDAP: failed to search user %q: %w", uid, err)
+	}
+	switch len(entries) {
+	case 0:
+		return nil, errInvalidCredentials
+	case 1:
+	default:
+		return nil, fmt.Errorf("LDAP: user %q is ambiguous (%d entries)", uid, len(entries))
+	}
+	entry := entries[0]
+	if err := lc.bind(entry.dn, pwd); err != nil {
+		if errors.Is(err, errLDAPInvalidCredentials) {
+			return nil, errInvalidCredentials
+		}
+		return nil, fmt.Errorf("LDAP: user %q bind failed: %w", uid, err)
+	}
+
+	uInfo := ls.toUser(entry, uid)
+	if len(uInfo.Roles) == 0 {
+		return nil, errLDAPNoRoles
+	}
+	ls.mu.Lock()
+	_, err = ls.save(uInfo)
+	ls.mu.Unlock()
+	return uInfo, err
+}
+
+// synchronize all (mapped) directory users into the DB
+func (ls *ldapSync) sync() error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	lc, err := ls.connect()
+	if err != nil {
+		return err
+	}
+	entries, err := lc.search(ls.conf.UserBase, ls.userFilter("*"), []string{ls.userAttr(), ls.groupAttr()})
+	lc.close()
+	if err != nil {
+		return fmt.Errorf("LDAP: failed to search users: %w", err)
+	}
+
+	var (
+		seen             = make(map[string]struct{}, len(entries))
+		updated, removed int
+	)
+	for _, entry := range entries {
+		uInfo := ls.toUser(entry, "")
+		if uInfo.ID == "" || len(uInfo.Roles) == 0 {
+			continue
+		}
+		seen[uInfo.ID] = struct{}{}
+		changed, err := ls.save(uInfo)
+		if err != nil {
+			nlog.Warningln(err)
+			continue
+		}
+		if changed {
+			updated++
+		}
+	}
+
+	users, err := ls.m.userList()
+	if err != nil {
+		return err
+	}
+	for uid, uInfo := range users {
+		if _, ok := seen[uid]; ok || uInfo.Source != authn.UserLDAP {
+			continue
+		}
+		if err := ls.m.db.Delete(usersCollection, uid); err != nil {
+			nlog.Errorf("LDAP: failed to remove user %q: %v", uid, err)
+			continue
+		}
+		removed++
+	}
+	if updated > 0 || removed > 0 || Conf.Verbose() {
+		nlog.Infof("LDAP sync: %d user(s), %d added or updated, %d removed", len(seen), updated, removed)
+	}
+	return nil
+}
+
+func (ls *ldapSync) run() {
+	if ls.conf.SyncInterval == 0 {
+		return
+	}
+	ticker := time.NewTicker(time.Duration(ls.conf.SyncInterval))
+	defer ticker.Stop()
+	for {
+		if err := ls.sync(); err != nil {
+			nlog.Errorln(err)
+		}
+		<-ticker.C
+	}
+}
+
+func (ls *ldapSync) toUser(entry *ldapEntry, dfltID string) *authn.User {
+	uid := cos.Left(entry.get(ls.userAttr()), dfltID)
+	return &authn.User{
+		ID:     uid,
+		Source: authn.UserLDAP,
+		Roles:  ls.mapRoles(uid, entry.attrs[strings.ToLower(ls.groupAttr())]),
+	}
+}
+
+// store LDAP user unless a local user with the same name exists; return true if changed
+func (ls *ldapSync) save(uInfo *authn.User) (bool, error) {
+	if prev, err := ls.m.lookupUser(uInfo.ID); err == nil {
+		if prev.Source != authn.UserLDAP {
+			return false, fmt.Errorf("LDAP: user %q is registered locally - skipping", uInfo.ID)
+		}
+		uInfo.Quota = prev.Quota // (managed locally)
+		if string(cos.MustMarshal(prev.Roles)) == string(cos.MustMarshal(uInfo.Roles)) {
+			return false, nil
+		}
+	}
+	return true, ls.m.db.Set(usersCollection, uInfo.ID, uInfo)
+}
+
+// roles (unique, in order) mapped to a given set of groups (full DNs)
+func (ls *ldapSync) mapRoles(uid string, groups []string) (roles []*authn.Role) {
+	var (
+		rmap  = make(map[string][]string, len(ls.conf.RoleMap))
+		names = make([]string, 0, len(ls.conf.DefaultRoles)+len(groups))
+		seen  = make(map[string]struct{}, 4)
+	)
+	for key, val := range ls.conf.RoleMap {
+		rmap[strings.ToLower(key)] = val
+	}
+	names = append(names, ls.conf.DefaultRoles...)
+	for _, group := range groups {
+		names = append(names, rmap[strings.ToLower(group)]...)
+		if cn := groupCN(group); cn != "" {
+			names = append(names, rmap[strings.ToLower(cn)]...)
+		}
+	}
+	for _, name := range names {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		role, err := ls.m.lookupRole(name)
+		if err != nil {
+			nlog.Warningf("LDAP: user %q: role %q not found (%v)", uid, name, err)
+			continue
+		}
+		roles = append(roles, role)
+	}
+	return roles
+}
+
+// value of the first RDN, e.g. "admins" for "CN=admins,OU=Groups,DC=example,DC=com"
+func groupCN(dn string) string {
+	rdn := dn
+	for i := 0; i < len(dn); i++ {
+		if dn[i] == '\\' {
+			i++
+			continue
+		}
+		if dn[i] == ',' {
+			rdn = dn[:i]
+			break
+		}
+	}
+	_, val, ok := strings.Cut(rdn, "=")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(val)
+}
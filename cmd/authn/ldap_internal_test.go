@@ -0,0 +1,331 @@
+//go:build debug
+
+// Package authn
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package main
+
+// NOTE go:build debug (above) =====================================
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/api/authn"
+	"github.com/NVIDIA/aistore/cmd/authn/tok"
+	"github.com/NVIDIA/aistore/core/mock"
+	"github.com/NVIDIA/aistore/tools/tassert"
+	ber "github.com/go-asn1-ber/asn1-ber"
+)
+
+const (
+	testUserBase = "ou=people,dc=example,dc=com"
+	testSvcDN    = "cn=svc,dc=example,dc=com"
+	testSvcPass  = "svc-secret"
+	testGroupsOU = ",ou=groups,dc=example,dc=com"
+)
+
+type (
+	// in-memory directory that speaks just enough LDAP: bind, (paged) search, unbind
+	testDir struct {
+		ln      net.Listener
+		entries map[string]*ldapEntry // DN => entry
+		passwds map[string]string     // DN => password
+		mu      sync.Mutex
+	}
+)
+
+func newTestDir(t *testing.T) *testDir {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	tassert.CheckFatal(t, err)
+	dir := &testDir{ln: ln, entries: make(map[string]*ldapEntry), passwds: map[string]string{testSvcDN: testSvcPass}}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go dir.serve(conn)
+		}
+	}()
+	return dir
+}
+
+func (dir *testDir) url() string { return "ldap://" + dir.ln.Addr().String() }
+
+func (dir *testDir) addUser(uid, pass string, groups ...string) {
+	dn := "uid=" + uid + "," + testUserBase
+	for i := range groups {
+		groups[i] = "cn=" + groups[i] + testGroupsOU
+	}
+	dir.mu.Lock()
+	dir.entries[dn] = &ldapEntry{dn: dn, attrs: map[string][]string{
+		"objectclass": {"top", "person"}, "uid": {uid}, "memberof": groups,
+	}}
+	dir.passwds[dn] = pass
+	dir.mu.Unlock()
+}
+
+func (dir *testDir) serve(conn net.Conn) {
+	defer conn.Close()
+	br := bufio.NewReader(conn)
+	authenticated := false
+	for {
+		req, err := ber.ReadPacket(br)
+		if err != nil {
+			return
+		}
+		id, op := req.Children[0].Value.(int64), req.Children[1]
+		switch op.Tag {
+		case ldapAppBindRequest:
+			dn, pass := op.Children[1].Data.String(), op.Children[2].Data.String()
+			dir.mu.Lock()
+			p, ok := dir.passwds[dn]
+			dir.mu.Unlock()
+			code := int64(ldapResultSuccess)
+			if !ok || p != pass || pass == "" {
+				code = ldapResultInvalidCredentials
+			}
+			authenticated = code == ldapResultSuccess
+			conn.Write(testResult(id, ldapAppBindResponse, code, nil).Bytes())
+		case ldapAppSearchRequest:
+			if !authenticated {
+				conn.Write(testResult(id, ldapAppSearchDone, 50 /*insufficient access*/, nil).Bytes())
+				continue
+			}
+			dir.search(conn, id, op, req)
+		case ldapAppUnbindRequest:
+			return
+		}
+	}
+}
+
+// returns 1 entry per page to exercise paging
+func (dir *testDir) search(conn net.Conn, id int64, op, req *ber.Packet) {
+	var (
+		offset  int
+		matched []*ldapEntry
+	)
+	if len(req.Children) > 2 {
+		offset, _ = strconv.Atoi(ldapPagingCookie(req.Children[2]))
+	}
+	dir.mu.Lock()
+	for _, e := range dir.entries {
+		if strings.HasSuffix(e.dn, op.Children[0].Data.String()) && testMatch(e, op.Children[6]) {
+			matched = append(matched, e)
+		}
+	}
+	dir.mu.Unlock()
+	sortEntries(matched)
+	if offset < len(matched) {
+		e := matched[offset]
+		resp := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldapAppSearchEntry, nil, "entry")
+		resp.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "dn"))
+		attrs := ber.NewSequence("attributes")
+		for name, vals := range e.attrs {
+			attr := ber.NewSequence("attribute")
+			attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+			set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "values")
+			for _, v := range vals {
+				set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "value"))
+			}
+			attr.AppendChild(set)
+			attrs.AppendChild(attr)
+		}
+		resp.AppendChild(attrs)
+		conn.Write(ldapMessage(id, resp).Bytes())
+	}
+	cookie := ""
+	if offset+1 < len(matched) {
+		cookie = strconv.Itoa(offset + 1)
+	}
+	conn.Write(testResult(id, ldapAppSearchDone, ldapResultSuccess, ldapPagingControl(cookie)).Bytes())
+}
+
+func testResult(id int64, tag ber.Tag, code int64, ctrls *ber.Packet) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "result")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "code"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matched DN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "diag", "message"))
+	msg := ldapMessage(id, op)
+	if ctrls != nil {
+		msg.AppendChild(ctrls)
+	}
+	return msg
+}
+
+// evaluate (a subset of) filters
+func testMatch(e *ldapEntry, f *ber.Packet) bool {
+	switch f.Tag {
+	case ldapFilterAnd:
+		for _, c := range f.Children {
+			if !testMatch(e, c) {
+				return false
+			}
+		}
+		return true
+	case ldapFilterOr:
+		for _, c := range f.Children {
+			if testMatch(e, c) {
+				return true
+			}
+		}
+		return false
+	case ldapFilterNot:
+		return !testMatch(e, f.Children[0])
+	case ldapFilterPresent:
+		return len(e.attrs[strings.ToLower(f.Data.String())]) > 0
+	case ldapFilterEquality:
+		for _, v := range e.attrs[strings.ToLower(f.Children[0].Data.String())] {
+			if strings.EqualFold(v, f.Children[1].Data.String()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sortEntries(entries []*ldapEntry) {
+	for i := 1; i < len(entries); i++ {
+		for j := i; j > 0 && entries[j].dn < entries[j-1].dn; j-- {
+			entries[j], entries[j-1] = entries[j-1], entries[j]
+		}
+	}
+}
+
+func newTestLDAP(t *testing.T, dir *testDir) *mgr {
+	if Conf.Log.Level == "" {
+		Conf.Log.Level = "3" // see Conf.Verbose()
+	}
+	Conf.LDAP = authn.LDAPConf{
+		URL:          dir.url(),
+		BindDN:       testSvcDN,
+		BindPassword: testSvcPass,
+		UserBase:     testUserBase,
+		RoleMap: map[string][]string{
+			"storage-users":                         {GuestRole},
+			"CN=admins,OU=groups,DC=example,DC=com": {authn.AdminRole},
+		},
+	}
+	t.Cleanup(func() { Conf.LDAP = authn.LDAPConf{} })
+	m, err := newMgr(mock.NewDBDriver())
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, m.ldap != nil, "expecting LDAP connector")
+	tassert.CheckFatal(t, m.addRole(guestRole))
+	return m
+}
+
+func TestLDAPLogin(t *testing.T) {
+	dir := newTestDir(t)
+	defer dir.ln.Close()
+	dir.addUser("alice", "alice-pass", "storage-users", "unrelated")
+	dir.addUser("bob", "bob-pass", "admins")
+	dir.addUser("carol", "carol-pass", "unrelated")
+	m := newTestLDAP(t, dir)
+
+	token, err := m.issueToken("alice", "alice-pass", &authn.LoginMsg{})
+	tassert.CheckFatal(t, err)
+	tk, err := tok.DecryptToken(token, Conf.Secret())
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, tk.UserID == "alice" && !tk.IsAdmin, "unexpected %s", tk)
+	tassert.Errorf(t, len(tk.ClusterACLs) == 1 && tk.ClusterACLs[0].Access == apc.AccessRO, "unexpected ACLs %v", tk.ClusterACLs)
+
+	// stored
+	uInfo, err := m.lookupUser("alice")
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, uInfo.Source == authn.UserLDAP && uInfo.Password == "", "unexpected user %+v", uInfo)
+	err = m.updateUser("alice", &authn.User{Password: "new-pass"})
+	tassert.Errorf(t, err != nil, "expected error: LDAP user updated locally")
+
+	token, err = m.issueToken("bob", "bob-pass", &authn.LoginMsg{})
+	tassert.CheckFatal(t, err)
+	tk, err = tok.DecryptToken(token, Conf.Secret())
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, tk.IsAdmin, "expecting admin: %s", tk)
+
+	// negative
+	_, err = m.issueToken("alice", "wrong", &authn.LoginMsg{})
+	tassert.Errorf(t, errors.Is(err, errInvalidCredentials), "expected invalid credentials, got %v", err)
+	_, err = m.issueToken("alice", "", &authn.LoginMsg{})
+	tassert.Errorf(t, errors.Is(err, errInvalidCredentials), "expected invalid credentials (empty password), got %v", err)
+	_, err = m.issueToken("nobody", "pass", &authn.LoginMsg{})
+	tassert.Errorf(t, errors.Is(err, errInvalidCredentials), "expected invalid credentials, got %v", err)
+	_, err = m.issueToken("*", "alice-pass", &authn.LoginMsg{})
+	tassert.Errorf(t, errors.Is(err, errInvalidCredentials), "expected invalid credentials (wildcard), got %v", err)
+	_, err = m.issueToken("carol", "carol-pass", &authn.LoginMsg{})
+	tassert.Errorf(t, errors.Is(err, errLDAPNoRoles), "expected no roles, got %v", err)
+
+	// local users take precedence
+	tassert.CheckFatal(t, m.addUser(&authn.User{ID: "dave", Password: "local-pass", Roles: []*authn.Role{guestRole}}))
+	dir.addUser("dave", "ldap-pass", "admins")
+	_, err = m.issueToken("dave", "ldap-pass", &authn.LoginMsg{})
+	tassert.Errorf(t, errors.Is(err, errInvalidCredentials), "expected invalid credentials, got %v", err)
+	_, err = m.issueToken("dave", "local-pass", &authn.LoginMsg{})
+	tassert.CheckError(t, err)
+}
+
+func TestLDAPSync(t *testing.T) {
+	dir := newTestDir(t)
+	defer dir.ln.Close()
+	dir.addUser("alice", "alice-pass", "storage-users")
+	dir.addUser("bob", "bob-pass", "storage-users")
+	dir.addUser("carol", "carol-pass", "unrelated")
+	m := newTestLDAP(t, dir)
+
+	roles := func(uid string) string {
+		uInfo, err := m.lookupUser(uid)
+		if err != nil {
+			return ""
+		}
+		names := make([]string, 0, len(uInfo.Roles))
+		for _, r := range uInfo.Roles {
+			names = append(names, r.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	tassert.CheckFatal(t, m.ldap.sync())
+	tassert.Errorf(t, roles("alice") == GuestRole && roles("bob") == GuestRole, "%q, %q", roles("alice"), roles("bob"))
+	tassert.Errorf(t, roles("carol") == "", "carol must not be synchronized: %q", roles("carol"))
+
+	// group membership changes
+	dir.addUser("alice", "alice-pass", "storage-users", "admins")
+	dir.addUser("bob", "bob-pass")
+	dir.addUser("carol", "carol-pass", "storage-users")
+	tassert.CheckFatal(t, m.ldap.sync())
+	tassert.Errorf(t, roles("alice") == GuestRole+","+authn.AdminRole, "alice: %q", roles("alice"))
+	tassert.Errorf(t, roles("bob") == "", "bob must be removed: %q", roles("bob"))
+	tassert.Errorf(t, roles("carol") == GuestRole, "carol: %q", roles("carol"))
+
+	// directory errors do not remove anybody
+	Conf.LDAP.BindPassword = "wrong"
+	tassert.Errorf(t, m.ldap.sync() != nil, "expected sync error")
+	tassert.Errorf(t, roles("alice") != "" && roles("carol") != "", "users removed upon error")
+}
+
+func TestLDAPFilter(t *testing.T) {
+	for _, f := range []string{
+		"(uid=alice)",
+		"(&(objectClass=person)(|(uid=a*b*c)(cn~=x)(age>=3)(age<=5))(!(disabled=*)))",
+		"(memberOf:1.2.840.113556.1.4.1941:=cn=admins,dc=example,dc=com)",
+		"(cn=" + ldapEscape("a*(b)\\") + ")",
+	} {
+		_, err := ldapFilter(f)
+		tassert.CheckError(t, err)
+	}
+	for _, f := range []string{"", "uid=alice", "(uid=alice", "(&(uid=a)", "(=x)", "(uid=\\zz)", "(uid=a))"} {
+		_, err := ldapFilter(f)
+		tassert.Errorf(t, err != nil, "expected error parsing %q", f)
+	}
+	v, err := ldapUnescape(ldapEscape("a*(b)\\"))
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, v == "a*(b)\\", "escape round-trip: %q", v)
+	tassert.Errorf(t, groupCN("CN=ops\\, eng,OU=groups,DC=example") == "ops\\, eng", "groupCN: %q", groupCN("CN=ops\\, eng,OU=x"))
+}
@@ -0,0 +1,538 @@
+// Package authn is authentication server for AIStore.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+)
+
+// Minimal LDAPv3 client (RFC 4511): simple bind, StartTLS, and paged subtree search -
+// everything AuthN needs to authenticate users and read their group memberships.
+
+const (
+	ldapAppBindRequest     = 0
+	ldapAppBindResponse    = 1
+	ldapAppUnbindRequest   = 2
+	ldapAppSearchRequest   = 3
+	ldapAppSearchEntry     = 4
+	ldapAppSearchDone      = 5
+	ldapAppSearchReference = 19
+	ldapAppExtendedRequest = 23
+	ldapAppExtendedResp    = 24
+
+	ldapResultSuccess            = 0
+	ldapResultInvalidCredentials = 49
+
+	ldapScopeSubtree = 2
+	ldapDerefNever   = 0
+
+	ldapOIDStartTLS     = "1.3.6.1.4.1.1466.20037"
+	ldapOIDPagedResults = "1.2.840.113556.1.4.319"
+	ldapPageSize        = 500
+)
+
+type (
+	ldapConn struct {
+		conn    net.Conn
+		br      *bufio.Reader
+		timeout time.Duration
+		msgID   int64
+	}
+	ldapEntry struct {
+		attrs map[string][]string // lowercase attribute name => values
+		dn    string
+	}
+	ldapError struct {
+		msg  string
+		code int64
+	}
+)
+
+var errLDAPInvalidCredentials = &ldapError{code: ldapResultInvalidCredentials, msg: "invalid credentials"}
+
+func (e *ldapError) Error() string { return fmt.Sprintf("LDAP result code %d: %s", e.code, e.msg) }
+
+func (e *ldapError) Is(target error) bool {
+	t, ok := target.(*ldapError)
+	return ok && t.code == e.code
+}
+
+// "ldap://host[:389]" or "ldaps://host[:636]"
+func ldapDial(rawURL string, startTLS bool, tlsConf *tls.Config, timeout time.Duration) (*ldapConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LDAP URL %q: %v", rawURL, err)
+	}
+	host, port := u.Hostname(), u.Port()
+	if tlsConf.ServerName == "" {
+		tlsConf.ServerName = host
+	}
+	var conn net.Conn
+	dialer := &net.Dialer{Timeout: timeout}
+	switch u.Scheme {
+	case "ldap":
+		conn, err = dialer.Dial("tcp", net.JoinHostPort(host, ldapPort(port, "389")))
+	case "ldaps":
+		conn, err = tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(host, ldapPort(port, "636")), tlsConf)
+	default:
+		return nil, fmt.Errorf("invalid LDAP URL %q: expecting ldap:// or ldaps:// scheme", rawURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	lc := &ldapConn{conn: conn, br: bufio.NewReader(conn), timeout: timeout}
+	if startTLS && u.Scheme == "ldap" {
+		if err := lc.startTLS(tlsConf); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return lc, nil
+}
+
+func ldapPort(port, dflt string) string {
+	if port == "" {
+		return dflt
+	}
+	return port
+}
+
+func (lc *ldapConn) close() {
+	lc.msgID++
+	msg := ldapMessage(lc.msgID, ber.Encode(ber.ClassApplication, ber.TypePrimitive, ldapAppUnbindRequest, nil, "unbind"))
+	lc.conn.SetDeadline(time.Now().Add(lc.timeout))
+	lc.conn.Write(msg.Bytes())
+	lc.conn.Close()
+}
+
+func (lc *ldapConn) startTLS(tlsConf *tls.Config) error {
+	req := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldapAppExtendedRequest, nil, "extended")
+	req.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 0, ldapOIDStartTLS, "name"))
+	resp, err := lc.roundTrip(req, ldapAppExtendedResp)
+	if err != nil {
+		return fmt.Errorf("LDAP StartTLS: %w", err)
+	}
+	if err := ldapResult(resp); err != nil {
+		return fmt.Errorf("LDAP StartTLS: %w", err)
+	}
+	tconn := tls.Client(lc.conn, tlsConf)
+	tconn.SetDeadline(time.Now().Add(lc.timeout))
+	if err := tconn.Handshake(); err != nil {
+		return fmt.Errorf("LDAP StartTLS: %w", err)
+	}
+	lc.conn, lc.br = tconn, bufio.NewReader(tconn)
+	return nil
+}
+
+// simple bind; note that empty password means "unauthenticated bind" - must be checked by the caller
+func (lc *ldapConn) bind(dn, password string) error {
+	req := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldapAppBindRequest, nil, "bind")
+	req.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, 3, "version"))
+	req.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, "name"))
+	req.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 0, password, "simple"))
+	resp, err := lc.roundTrip(req, ldapAppBindResponse)
+	if err != nil {
+		return err
+	}
+	return ldapResult(resp)
+}
+
+// subtree search, all pages
+func (lc *ldapConn) search(base, filter string, attrs []string) ([]*ldapEntry, error) {
+	fpkt, err := ldapFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		entries []*ldapEntry
+		cookie  string
+	)
+	for {
+		req := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldapAppSearchRequest, nil, "search")
+		req.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, base, "base"))
+		req.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, ldapScopeSubtree, "scope"))
+		req.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, ldapDerefNever, "deref"))
+		req.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, 0, "size limit"))
+		req.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, 0, "time limit"))
+		req.AppendChild(ber.NewLDAPBoolean(ber.ClassUniversal, ber.TypePrimitive, ber.TagBoolean, false, "types only"))
+		req.AppendChild(fpkt)
+		attrsPkt := ber.NewSequence("attributes")
+		for _, a := range attrs {
+			attrsPkt.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, a, "attribute"))
+		}
+		req.AppendChild(attrsPkt)
+
+		lc.msgID++
+		msg := ldapMessage(lc.msgID, req)
+		msg.AppendChild(ldapPagingControl(cookie))
+		if err := lc.send(msg); err != nil {
+			return nil, err
+		}
+		cookie = ""
+	recv:
+		for {
+			resp, err := lc.recv()
+			if err != nil {
+				return nil, err
+			}
+			op := resp.Children[1]
+			switch op.Tag {
+			case ldapAppSearchEntry:
+				entries = append(entries, ldapParseEntry(op))
+			case ldapAppSearchReference:
+				// not following referrals
+			case ldapAppSearchDone:
+				if err := ldapResult(op); err != nil {
+					return nil, err
+				}
+				if len(resp.Children) > 2 {
+					cookie = ldapPagingCookie(resp.Children[2])
+				}
+				break recv
+			default:
+				return nil, fmt.Errorf("LDAP search: unexpected response (tag %d)", op.Tag)
+			}
+		}
+		if cookie == "" {
+			return entries, nil
+		}
+	}
+}
+
+//
+// messages ============================================================
+//
+
+func ldapMessage(id int64, op *ber.Packet) *ber.Packet {
+	msg := ber.NewSequence("LDAP message")
+	msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "message ID"))
+	msg.AppendChild(op)
+	return msg
+}
+
+func (lc *ldapConn) roundTrip(op *ber.Packet, respTag ber.Tag) (*ber.Packet, error) {
+	lc.msgID++
+	if err := lc.send(ldapMessage(lc.msgID, op)); err != nil {
+		return nil, err
+	}
+	resp, err := lc.recv()
+	if err != nil {
+		return nil, err
+	}
+	if resp.Children[1].Tag != respTag {
+		return nil, fmt.Errorf("LDAP: unexpected response (tag %d, expecting %d)", resp.Children[1].Tag, respTag)
+	}
+	return resp.Children[1], nil
+}
+
+func (lc *ldapConn) send(msg *ber.Packet) error {
+	lc.conn.SetDeadline(time.Now().Add(lc.timeout))
+	_, err := lc.conn.Write(msg.Bytes())
+	return err
+}
+
+func (lc *ldapConn) recv() (*ber.Packet, error) {
+	lc.conn.SetDeadline(time.Now().Add(lc.timeout))
+	resp, err := ber.ReadPacket(lc.br)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Children) < 2 {
+		return nil, errors.New("LDAP: malformed response")
+	}
+	if id, _ := resp.Children[0].Value.(int64); id != lc.msgID {
+		return nil, fmt.Errorf("LDAP: unexpected message ID %d (expecting %d)", id, lc.msgID)
+	}
+	return resp, nil
+}
+
+// LDAPResult ::= resultCode, matchedDN, diagnosticMessage, ...
+func ldapResult(op *ber.Packet) error {
+	if len(op.Children) < 3 {
+		return errors.New("LDAP: malformed result")
+	}
+	code, _ := op.Children[0].Value.(int64)
+	if code == ldapResultSuccess {
+		return nil
+	}
+	return &ldapError{code: code, msg: op.Children[2].Data.String()}
+}
+
+func ldapParseEntry(op *ber.Packet) *ldapEntry {
+	e := &ldapEntry{attrs: make(map[string][]string, 2)}
+	if len(op.Children) < 2 {
+		return e
+	}
+	e.dn = op.Children[0].Data.String()
+	for _, attr := range op.Children[1].Children {
+		if len(attr.Children) < 2 {
+			continue
+		}
+		name := strings.ToLower(attr.Children[0].Data.String())
+		for _, v := range attr.Children[1].Children {
+			e.attrs[name] = append(e.attrs[name], v.Data.String())
+		}
+	}
+	return e
+}
+
+func (e *ldapEntry) get(attr string) string {
+	if vals := e.attrs[strings.ToLower(attr)]; len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+// Simple Paged Results control (RFC 2696)
+func ldapPagingControl(cookie string) *ber.Packet {
+	val := ber.NewSequence("paging")
+	val.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, ldapPageSize, "size"))
+	val.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, cookie, "cookie"))
+	ctrl := ber.NewSequence("control")
+	ctrl.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, ldapOIDPagedResults, "type"))
+	ctrl.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, string(val.Bytes()), "value"))
+	ctrls := ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "controls")
+	ctrls.AppendChild(ctrl)
+	return ctrls
+}
+
+func ldapPagingCookie(ctrls *ber.Packet) string {
+	for _, ctrl := range ctrls.Children {
+		if len(ctrl.Children) < 2 || ctrl.Children[0].Data.String() != ldapOIDPagedResults {
+			continue
+		}
+		val := ber.DecodePacket(ctrl.Children[len(ctrl.Children)-1].Data.Bytes())
+		if val != nil && len(val.Children) == 2 {
+			return val.Children[1].Data.String()
+		}
+	}
+	return ""
+}
+
+//
+// filters (RFC 4515) ============================================================
+//
+
+const (
+	ldapFilterAnd        = 0
+	ldapFilterOr         = 1
+	ldapFilterNot        = 2
+	ldapFilterEquality   = 3
+	ldapFilterSubstrings = 4
+	ldapFilterGE         = 5
+	ldapFilterLE         = 6
+	ldapFilterPresent    = 7
+	ldapFilterApprox     = 8
+	ldapFilterExtensible = 9
+)
+
+// escape a value to be substituted into a filter
+func ldapEscape(s string) string {
+	var sb strings.Builder
+	for i := range len(s) {
+		c := s[i]
+		if c == '*' || c == '(' || c == ')' || c == '\\' || c == 0 {
+			fmt.Fprintf(&sb, "\\%02x", c)
+		} else {
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+func ldapFilter(filter string) (*ber.Packet, error) {
+	pkt, rest, err := _filter(filter)
+	if err == nil && rest != "" {
+		err = errors.New("unexpected trailing characters")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid LDAP filter %q: %v", filter, err)
+	}
+	return pkt, nil
+}
+
+// parse "(...)" and return the remainder
+func _filter(s string) (*ber.Packet, string, error) {
+	if s == "" || s[0] != '(' {
+		return nil, s, errors.New("expecting '('")
+	}
+	s = s[1:]
+	if s == "" {
+		return nil, s, errors.New("unexpected end")
+	}
+	switch s[0] {
+	case '&', '|':
+		tag := ber.Tag(ldapFilterAnd)
+		if s[0] == '|' {
+			tag = ldapFilterOr
+		}
+		pkt := ber.Encode(ber.ClassContext, ber.TypeConstructed, tag, nil, "and/or")
+		s = s[1:]
+		for s != "" && s[0] == '(' {
+			child, rest, err := _filter(s)
+			if err != nil {
+				return nil, rest, err
+			}
+			pkt.AppendChild(child)
+			s = rest
+		}
+		if s == "" || s[0] != ')' {
+			return nil, s, errors.New("expecting ')'")
+		}
+		return pkt, s[1:], nil
+	case '!':
+		pkt := ber.Encode(ber.ClassContext, ber.TypeConstructed, ldapFilterNot, nil, "not")
+		child, rest, err := _filter(s[1:])
+		if err != nil {
+			return nil, rest, err
+		}
+		pkt.AppendChild(child)
+		if rest == "" || rest[0] != ')' {
+			return nil, rest, errors.New("expecting ')'")
+		}
+		return pkt, rest[1:], nil
+	}
+	end := strings.IndexByte(s, ')')
+	if end < 0 {
+		return nil, s, errors.New("expecting ')'")
+	}
+	pkt, err := _item(s[:end])
+	return pkt, s[end+1:], err
+}
+
+// attr=value, attr=*, attr=*sub*strings*, attr>=value, attr<=value, attr~=value, attr:rule:=value
+func _item(s string) (*ber.Packet, error) {
+	eq := strings.IndexByte(s, '=')
+	if eq <= 0 {
+		return nil, fmt.Errorf("invalid item %q", s)
+	}
+	var (
+		attr, raw = s[:eq], s[eq+1:]
+		tag       = ber.Tag(ldapFilterEquality)
+	)
+	switch attr[len(attr)-1] {
+	case '>':
+		tag, attr = ldapFilterGE, attr[:len(attr)-1]
+	case '<':
+		tag, attr = ldapFilterLE, attr[:len(attr)-1]
+	case '~':
+		tag, attr = ldapFilterApprox, attr[:len(attr)-1]
+	case ':':
+		return _extensible(attr[:len(attr)-1], raw)
+	}
+	if attr == "" {
+		return nil, fmt.Errorf("invalid item %q", s)
+	}
+	if tag == ldapFilterEquality {
+		if raw == "*" {
+			return ber.NewString(ber.ClassContext, ber.TypePrimitive, ldapFilterPresent, attr, "present"), nil
+		}
+		if strings.Contains(raw, "*") {
+			return _substrings(attr, raw)
+		}
+	}
+	val, err := ldapUnescape(raw)
+	if err != nil {
+		return nil, err
+	}
+	pkt := ber.Encode(ber.ClassContext, ber.TypeConstructed, tag, nil, "assertion")
+	pkt.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attr, "attribute"))
+	pkt.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, val, "value"))
+	return pkt, nil
+}
+
+func _substrings(attr, raw string) (*ber.Packet, error) {
+	pkt := ber.Encode(ber.ClassContext, ber.TypeConstructed, ldapFilterSubstrings, nil, "substrings")
+	pkt.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attr, "attribute"))
+	subs := ber.NewSequence("substrings")
+	parts := strings.Split(raw, "*")
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		val, err := ldapUnescape(part)
+		if err != nil {
+			return nil, err
+		}
+		var tag ber.Tag = 1 // any
+		switch i {
+		case 0:
+			tag = 0 // initial
+		case len(parts) - 1:
+			tag = 2 // final
+		}
+		subs.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, tag, val, "substring"))
+	}
+	pkt.AppendChild(subs)
+	return pkt, nil
+}
+
+// attr[:dn][:rule]:=value (e.g., AD nested groups: "memberOf:1.2.840.113556.1.4.1941:=<group DN>")
+func _extensible(desc, raw string) (*ber.Packet, error) {
+	parts := strings.Split(desc, ":")
+	var (
+		attr, rule = parts[0], ""
+		dnAttrs    bool
+	)
+	for _, p := range parts[1:] {
+		if strings.EqualFold(p, "dn") {
+			dnAttrs = true
+		} else {
+			rule = p
+		}
+	}
+	if attr == "" && rule == "" {
+		return nil, fmt.Errorf("invalid extensible match %q", desc)
+	}
+	val, err := ldapUnescape(raw)
+	if err != nil {
+		return nil, err
+	}
+	pkt := ber.Encode(ber.ClassContext, ber.TypeConstructed, ldapFilterExtensible, nil, "extensible")
+	if rule != "" {
+		pkt.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 1, rule, "rule"))
+	}
+	if attr != "" {
+		pkt.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 2, attr, "type"))
+	}
+	pkt.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 3, val, "value"))
+	if dnAttrs {
+		pkt.AppendChild(ber.NewLDAPBoolean(ber.ClassContext, ber.TypePrimitive, 4, true, "dn"))
+	}
+	return pkt, nil
+}
+
+func ldapUnescape(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			sb.WriteByte(s[i])
+			continue
+		}
+		if i+3 > len(s) {
+			return "", fmt.Errorf("invalid escape in %q", s)
+		}
+		b, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("invalid escape in %q", s)
+		}
+		sb.WriteByte(byte(b))
+		i += 2
+	}
+	return sb.String(), nil
+}
@@ -74,6 +74,17 @@ func main() {
 	if val := os.Getenv(env.AuthN.SecretKey); val != "" {
 		Conf.SetSecret(&val)
 	}
+	if val := os.Getenv(env.AuthN.OIDCSecret); val != "" {
+		Conf.OIDC.ClientSecret = val
+	}
+	if val := os.Getenv(env.AuthN.LDAPPassword); val != "" {
+		Conf.LDAP.BindPassword = val
+	}
+	if keyID := os.Getenv(env.AuthN.SecretKMSKey); keyID != "" {
+		if err := initSecretKMS(keyID); err != nil {
+			cos.ExitLogf("Failed to decrypt secret: %v", err)
+		}
+	}
 	if err := updateLogOptions(); err != nil {
 		cos.ExitLogf("Failed to set up logger: %v", err)
 	}
@@ -94,6 +105,9 @@ func main() {
 	nlog.Infof("Version %s (build %s)\n", cmn.VersionAuthN+"."+build, buildtime)
 
 	go logFlush()
+	if mgr.ldap != nil {
+		go mgr.ldap.run()
+	}
 
 	srv := newServer(mgr)
 	err = srv.Run()
@@ -29,6 +29,7 @@ type mgr struct {
 	clientH   *http.Client
 	clientTLS *http.Client
 	db        kvdb.Driver
+	ldap      *ldapSync // nil when LDAP is not configured
 }
 
 var (
@@ -51,6 +52,9 @@ func newMgr(driver kvdb.Driver) (m *mgr, err error) {
 		db: driver,
 	}
 	m.clientH, m.clientTLS = cmn.NewDefaultClients(time.Duration(Conf.Timeout.Default))
+	if Conf.LDAP.Enabled() {
+		m.ldap = newLDAP(m, &Conf.LDAP)
+	}
 	err = initializeDB(driver)
 	return
 }
@@ -72,6 +76,11 @@ func (m *mgr) addUser(info *authn.User) error {
 	if err == nil {
 		return fmt.Errorf("user %q already registered", info.ID)
 	}
+	if info.Quota != nil {
+		if err := info.Quota.ValidateAsProps(); err != nil {
+			return err
+		}
+	}
 	info.Password = encryptPassword(info.Password)
 	return m.db.Set(usersCollection, info.ID, info)
 }
@@ -95,6 +104,14 @@ func (m *mgr) updateUser(userID string, updateReq *authn.User) error {
 	if userID == adminUserID && len(updateReq.Roles) != 0 {
 		return errors.New("cannot change administrator's role")
 	}
+	if uInfo.Source == authn.UserLDAP && (updateReq.Password != "" || len(updateReq.Roles) != 0) {
+		return fmt.Errorf("user %q is managed by LDAP (credentials and roles cannot be changed)", userID)
+	}
+	if updateReq.Quota != nil {
+		if err := updateReq.Quota.ValidateAsProps(); err != nil {
+			return err
+		}
+	}
 
 	if updateReq.Password != "" {
 		uInfo.Password = encryptPassword(updateReq.Password)
@@ -102,6 +119,12 @@ func (m *mgr) updateUser(userID string, updateReq *authn.User) error {
 	if len(updateReq.Roles) != 0 {
 		uInfo.Roles = updateReq.Roles
 	}
+	if updateReq.Quota != nil {
+		uInfo.Quota = updateReq.Quota
+		if !uInfo.Quota.IsSet() { // all zeros: remove the limits
+			uInfo.Quota = nil
+		}
+	}
 	return m.db.Set(usersCollection, userID, uInfo)
 }
 
@@ -357,17 +380,25 @@ func (m *mgr) issueToken(uid, pwd string, msg *authn.LoginMsg) (token string, er
 		bckACLs []*authn.BckACL
 	)
 	err = m.db.Get(usersCollection, uid, uInfo)
-	if err != nil {
+	switch {
+	case err == nil && uInfo.Source != authn.UserLDAP:
+		debug.Assert(uid == uInfo.ID, uid, " vs ", uInfo.ID)
+		if !isSamePassword(pwd, uInfo.Password) {
+			return "", errInvalidCredentials
+		}
+	case m.ldap != nil:
+		// not registered locally or synchronized from LDAP
+		if uInfo, err = m.ldap.authenticate(uid, pwd); err != nil {
+			return "", err
+		}
+	default:
+		if err == nil {
+			err = fmt.Errorf("user %q: LDAP is not configured", uid)
+		}
 		nlog.Errorln(err)
 		return "", errInvalidCredentials
 	}
 
-	debug.Assert(uid == uInfo.ID, uid, " vs ", uInfo.ID)
-
-	if !isSamePassword(pwd, uInfo.Password) {
-		return "", errInvalidCredentials
-	}
-
 	// update ACLs with roles' ones
 	for _, role := range uInfo.Roles {
 		cluACLs = mergeClusterACLs(cluACLs, role.ClusterACLs, cid)
@@ -397,7 +428,7 @@ func (m *mgr) _token(msg *authn.LoginMsg, uInfo *authn.User, cluACLs []*authn.Cl
 		token, err = tok.AdminJWT(expires, uid, Conf.Secret())
 	} else {
 		m.fixClusterIDs(cluACLs)
-		token, err = tok.JWT(expires, uid, bckACLs, cluACLs, Conf.Secret())
+		token, err = tok.JWT(expires, uid, bckACLs, cluACLs, uInfo.Quota, Conf.Secret())
 	}
 	return token, err
 }
@@ -421,6 +452,40 @@ func (m *mgr) fixClusterIDs(lst []*authn.CluACL) {
 	}
 }
 
+// Derive scoped token from a valid (non-expired and non-revoked) parent token.
+func (m *mgr) issueScopedToken(parent string, msg *authn.ScopedTokenMsg) (string, error) {
+	secret := Conf.Secret()
+	tk, err := tok.DecryptToken(parent, secret)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	if tk.Expires.Before(now) {
+		return "", fmt.Errorf("%v: %s", tok.ErrTokenExpired, tk)
+	}
+	var s string
+	if err := m.db.Get(revokedCollection, parent, &s); err == nil {
+		return "", fmt.Errorf("%v: %s", tok.ErrTokenRevoked, tk)
+	}
+
+	scope := msg.Scope
+	if scope.Access == 0 {
+		scope.Access = apc.AccessRO
+	}
+	expDelta := authn.ScopedTokenDfltExpire
+	if msg.ExpiresIn != nil {
+		if *msg.ExpiresIn <= 0 {
+			return "", fmt.Errorf("invalid scoped token expiration %v", *msg.ExpiresIn)
+		}
+		expDelta = *msg.ExpiresIn
+	}
+	token, err := tok.ScopedJWT(tk, &scope, now.Add(expDelta), secret)
+	if err == nil && Conf.Verbose() {
+		nlog.Infof("user %q: scoped token [%s]", tk.UserID, scope.String())
+	}
+	return token, err
+}
+
 // Delete existing token, a.k.a log out
 // If the token was removed successfully then it sends the proxy a new valid token list
 func (m *mgr) revokeToken(token string) error {
@@ -0,0 +1,566 @@
+// Package authn is authentication server for AIStore.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/api/authn"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/golang-jwt/jwt/v4"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// OpenID Connect relying party:
+// - browser login (authorization code flow with PKCE):
+//   GET /v1/oidc/login => provider's login page => GET /v1/oidc/callback => AuthN token;
+// - programmatic login: POST /v1/oidc/token with the ID token obtained by the client
+//   (e.g., via device flow) from the same provider for the same client ID;
+// in both cases, the ID token is validated (signature, issuer, audience, expiration), and
+// its claims are mapped to AuthN roles (see authn.OIDCConf) to issue the usual cluster JWT.
+
+const (
+	oidcDfltUserClaim   = "email"
+	oidcDfltGroupsClaim = "groups"
+
+	oidcStateTTL    = 10 * time.Minute
+	oidcMaxPending  = 4096
+	oidcJWKSRefresh = time.Minute // min interval between (unknown key ID triggered) JWKS refreshes
+)
+
+var oidcDfltScopes = []string{"openid", "email", "profile"}
+
+type (
+	oidcRP struct {
+		m       *mgr
+		conf    *authn.OIDCConf
+		client  *http.Client
+		disc    *oidcDiscovery
+		keys    map[string]any // key ID => public key
+		keysAt  time.Time
+		pending map[string]*oidcAuthReq // state => login in progress
+		mu      sync.Mutex
+	}
+	oidcDiscovery struct {
+		Issuer   string `json:"issuer"`
+		AuthURL  string `json:"authorization_endpoint"`
+		TokenURL string `json:"token_endpoint"`
+		JWKSURL  string `json:"jwks_uri"`
+	}
+	oidcAuthReq struct {
+		expires   time.Time
+		expiresIn *time.Duration // requested AuthN token expiration
+		nonce     string
+		verifier  string // PKCE code verifier
+	}
+	jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	jwk struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+	oidcTokenResp struct {
+		IDToken   string `json:"id_token"`
+		Error     string `json:"error"`
+		ErrorDesc string `json:"error_description"`
+	}
+)
+
+var errOIDCNoRoles = errors.New("OIDC: no AuthN roles mapped to the user")
+
+func newOIDC(m *mgr, conf *authn.OIDCConf) *oidcRP {
+	return &oidcRP{
+		m:       m,
+		conf:    conf,
+		client:  m.clientH,
+		pending: make(map[string]*oidcAuthReq, 16),
+	}
+}
+
+func (*oidcRP) String() string { return "OIDC" }
+
+//
+// HTTP handlers ============================================================
+//
+
+func (rp *oidcRP) handler(w http.ResponseWriter, r *http.Request) {
+	apiItems, err := parseURL(w, r, 1, apc.URLPathOIDC.L)
+	if err != nil {
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && apiItems[0] == apc.OIDCLogin:
+		rp.httpLogin(w, r)
+	case r.Method == http.MethodGet && apiItems[0] == apc.OIDCCallback:
+		rp.httpCallback(w, r)
+	case r.Method == http.MethodPost && apiItems[0] == apc.OIDCToken:
+		rp.httpToken(w, r)
+	case apiItems[0] == apc.OIDCLogin || apiItems[0] == apc.OIDCCallback:
+		cmn.WriteErr405(w, r, http.MethodGet)
+	case apiItems[0] == apc.OIDCToken:
+		cmn.WriteErr405(w, r, http.MethodPost)
+	default:
+		cmn.WriteErrMsg(w, r, "invalid OIDC request: "+r.URL.Path, http.StatusNotFound)
+	}
+}
+
+// redirect to the provider's authorization endpoint
+// (optional query `expires_in`: requested AuthN token expiration, e.g. "8h")
+func (rp *oidcRP) httpLogin(w http.ResponseWriter, r *http.Request) {
+	disc, err := rp.discover()
+	if err != nil {
+		cmn.WriteErr(w, r, err, http.StatusBadGateway)
+		return
+	}
+	areq := &oidcAuthReq{expires: time.Now().Add(oidcStateTTL)}
+	if s := r.URL.Query().Get("expires_in"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			cmn.WriteErrMsg(w, r, "invalid expires_in: "+err.Error())
+			return
+		}
+		areq.expiresIn = &d
+	}
+	state := randString()
+	areq.nonce, areq.verifier = randString(), randString()
+	if err := rp.addPending(state, areq); err != nil {
+		cmn.WriteErr(w, r, err, http.StatusServiceUnavailable)
+		return
+	}
+
+	challenge := sha256.Sum256([]byte(areq.verifier))
+	scopes := rp.conf.Scopes
+	if len(scopes) == 0 {
+		scopes = oidcDfltScopes
+	}
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", rp.conf.ClientID)
+	q.Set("redirect_uri", rp.conf.RedirectURL)
+	q.Set("scope", strings.Join(scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", areq.nonce)
+	q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	q.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(disc.AuthURL, "?") {
+		sep = "&"
+	}
+	http.Redirect(w, r, disc.AuthURL+sep+q.Encode(), http.StatusFound)
+}
+
+// exchange authorization code for ID token, validate it, and issue AuthN token
+func (rp *oidcRP) httpCallback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		cmn.WriteErrMsg(w, r, fmt.Sprintf("OIDC login failed: %s %s", e, q.Get("error_description")), http.StatusUnauthorized)
+		return
+	}
+	areq := rp.popPending(q.Get("state"))
+	if areq == nil {
+		cmn.WriteErrMsg(w, r, "OIDC login failed: invalid or expired state", http.StatusUnauthorized)
+		return
+	}
+	code := q.Get("code")
+	if code == "" {
+		cmn.WriteErrMsg(w, r, "OIDC login failed: missing authorization code", http.StatusUnauthorized)
+		return
+	}
+	idToken, err := rp.exchange(code, areq.verifier)
+	if err != nil {
+		nlog.Errorln(err)
+		cmn.WriteErr(w, r, err, http.StatusUnauthorized)
+		return
+	}
+	rp.login(w, r, idToken, areq.nonce, areq.expiresIn)
+}
+
+func (rp *oidcRP) httpToken(w http.ResponseWriter, r *http.Request) {
+	msg := &authn.OIDCLoginMsg{}
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		return
+	}
+	if msg.IDToken == "" {
+		cmn.WriteErrMsg(w, r, "empty ID token", http.StatusUnauthorized)
+		return
+	}
+	rp.login(w, r, msg.IDToken, "", msg.ExpiresIn)
+}
+
+func (rp *oidcRP) login(w http.ResponseWriter, r *http.Request, idToken, nonce string, expiresIn *time.Duration) {
+	claims, err := rp.verify(idToken, nonce)
+	if err != nil {
+		nlog.Errorln(err)
+		cmn.WriteErr(w, r, err, http.StatusUnauthorized)
+		return
+	}
+	uid, token, err := rp.issueToken(claims, expiresIn)
+	if err != nil {
+		nlog.Errorf("OIDC: failed to generate token for user %q: %v", uid, err)
+		cmn.WriteErr(w, r, err, http.StatusUnauthorized)
+		return
+	}
+	if Conf.Verbose() {
+		nlog.Infof("OIDC login %q", uid)
+	}
+	repl := fmt.Sprintf(`{"token": %q}`, token)
+	writeBytes(w, cos.UnsafeB(repl), "oidc-login")
+}
+
+//
+// ID token ============================================================
+//
+
+func (rp *oidcRP) verify(idToken, nonce string) (jwt.MapClaims, error) {
+	disc, err := rp.discover()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		claims = jwt.MapClaims{}
+		parser = jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}))
+	)
+	if _, err := parser.ParseWithClaims(idToken, claims, rp.keyfunc); err != nil {
+		return nil, fmt.Errorf("OIDC: invalid ID token: %w", err)
+	}
+	now := time.Now().Unix()
+	switch {
+	case !claims.VerifyExpiresAt(now, true):
+		return nil, errors.New("OIDC: ID token expired or missing expiration")
+	case !claims.VerifyIssuer(disc.Issuer, true):
+		return nil, fmt.Errorf("OIDC: invalid ID token issuer %v (expecting %q)", claims["iss"], disc.Issuer)
+	case !claims.VerifyAudience(rp.conf.ClientID, true):
+		return nil, fmt.Errorf("OIDC: invalid ID token audience %v (expecting %q)", claims["aud"], rp.conf.ClientID)
+	}
+	if nonce != "" {
+		if v, _ := claims["nonce"].(string); v != nonce {
+			return nil, errors.New("OIDC: ID token nonce mismatch")
+		}
+	}
+	return claims, nil
+}
+
+func (rp *oidcRP) keyfunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	if key := rp.lookupKey(kid, false); key != nil {
+		return key, nil
+	}
+	if key := rp.lookupKey(kid, true); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// when `refresh` is set, (re)load provider's keys unless they've been loaded recently
+func (rp *oidcRP) lookupKey(kid string, refresh bool) any {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	if refresh && time.Since(rp.keysAt) > oidcJWKSRefresh {
+		if err := rp._loadKeys(); err != nil {
+			nlog.Errorln(err)
+		}
+	}
+	if kid == "" && len(rp.keys) == 1 { // single key without ID
+		for _, key := range rp.keys {
+			return key
+		}
+	}
+	return rp.keys[kid]
+}
+
+func (rp *oidcRP) _loadKeys() error {
+	rp.keysAt = time.Now()
+	if rp.disc == nil {
+		return errors.New("OIDC: provider not discovered")
+	}
+	set := &jwks{}
+	if err := rp.getJSON(rp.disc.JWKSURL, set); err != nil {
+		return fmt.Errorf("OIDC: failed to load signing keys: %w", err)
+	}
+	keys := make(map[string]any, len(set.Keys))
+	for i := range set.Keys {
+		k := &set.Keys[i]
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.pubKey()
+		if err != nil {
+			nlog.Warningf("OIDC: skipping signing key %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	rp.keys = keys
+	return nil
+}
+
+func (k *jwk) pubKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		eint := new(big.Int).SetBytes(e)
+		if !eint.IsInt64() || eint.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(eint.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+//
+// claims => roles => token ============================================================
+//
+
+func (rp *oidcRP) issueToken(claims jwt.MapClaims, expiresIn *time.Duration) (uid, token string, err error) {
+	userClaim := cos.Left(rp.conf.UserClaim, oidcDfltUserClaim)
+	uid, _ = claims[userClaim].(string)
+	if uid == "" {
+		return "", "", fmt.Errorf("OIDC: ID token has no %q claim", userClaim)
+	}
+	if userClaim == "email" {
+		if verified, ok := claims["email_verified"].(bool); ok && !verified {
+			return uid, "", fmt.Errorf("OIDC: email %q is not verified", uid)
+		}
+	}
+
+	var (
+		uInfo   = &authn.User{ID: uid}
+		cluACLs []*authn.CluACL
+		bckACLs []*authn.BckACL
+	)
+	for _, name := range rp.mapRoles(claims) {
+		role, err := rp.m.lookupRole(name)
+		if err != nil {
+			nlog.Warningf("OIDC: user %q: role %q not found (%v)", uid, name, err)
+			continue
+		}
+		uInfo.Roles = append(uInfo.Roles, role)
+		cluACLs = mergeClusterACLs(cluACLs, role.ClusterACLs, "")
+		bckACLs = mergeBckACLs(bckACLs, role.BucketACLs, "")
+	}
+	if len(uInfo.Roles) == 0 {
+		return uid, "", errOIDCNoRoles
+	}
+	token, err = rp.m._token(&authn.LoginMsg{ExpiresIn: expiresIn}, uInfo, cluACLs, bckACLs)
+	return uid, token, err
+}
+
+// names of the roles (unique, in order) mapped to a given set of claims
+func (rp *oidcRP) mapRoles(claims jwt.MapClaims) (roles []string) {
+	var (
+		groups = claimStrings(claims[cos.Left(rp.conf.GroupsClaim, oidcDfltGroupsClaim)])
+		seen   = make(map[string]struct{}, 4)
+		add    = func(names []string) {
+			for _, name := range names {
+				if _, ok := seen[name]; !ok {
+					seen[name] = struct{}{}
+					roles = append(roles, name)
+				}
+			}
+		}
+	)
+	add(rp.conf.DefaultRoles)
+	for _, group := range groups {
+		add(rp.conf.RoleMap[group])
+	}
+	for key, names := range rp.conf.RoleMap {
+		claim, val, ok := strings.Cut(key, "=")
+		if !ok {
+			continue
+		}
+		for _, v := range claimStrings(claims[claim]) {
+			if v == val {
+				add(names)
+				break
+			}
+		}
+	}
+	return roles
+}
+
+// string or list of strings
+func claimStrings(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+//
+// provider ============================================================
+//
+
+func (rp *oidcRP) discover() (*oidcDiscovery, error) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	if rp.disc != nil {
+		return rp.disc, nil
+	}
+	var (
+		disc = &oidcDiscovery{}
+		u    = strings.TrimSuffix(rp.conf.Issuer, "/") + "/.well-known/openid-configuration"
+	)
+	if err := rp.getJSON(u, disc); err != nil {
+		return nil, fmt.Errorf("OIDC: discovery failed: %w", err)
+	}
+	if disc.Issuer != rp.conf.Issuer {
+		return nil, fmt.Errorf("OIDC: discovered issuer %q differs from the configured %q", disc.Issuer, rp.conf.Issuer)
+	}
+	if disc.AuthURL == "" || disc.TokenURL == "" || disc.JWKSURL == "" {
+		return nil, fmt.Errorf("OIDC: incomplete provider metadata %+v", disc)
+	}
+	rp.disc = disc
+	if err := rp._loadKeys(); err != nil {
+		nlog.Errorln(err) // will retry upon first login
+	}
+	return disc, nil
+}
+
+func (rp *oidcRP) exchange(code, verifier string) (string, error) {
+	disc, err := rp.discover()
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", rp.conf.RedirectURL)
+	form.Set("client_id", rp.conf.ClientID)
+	form.Set("client_secret", rp.conf.ClientSecret)
+	form.Set("code_verifier", verifier)
+	resp, err := rp.client.PostForm(disc.TokenURL, form)
+	if err != nil {
+		return "", fmt.Errorf("OIDC: token exchange failed: %w", err)
+	}
+	defer resp.Body.Close()
+	tresp := &oidcTokenResp{}
+	if err := jsoniter.NewDecoder(io.LimitReader(resp.Body, cos.MiB)).Decode(tresp); err != nil {
+		return "", fmt.Errorf("OIDC: token exchange failed: status %d: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || tresp.Error != "" {
+		return "", fmt.Errorf("OIDC: token exchange failed: status %d: %s %s", resp.StatusCode, tresp.Error, tresp.ErrorDesc)
+	}
+	if tresp.IDToken == "" {
+		return "", errors.New("OIDC: token exchange failed: no ID token in response")
+	}
+	return tresp.IDToken, nil
+}
+
+func (rp *oidcRP) getJSON(u string, v any) error {
+	resp, err := rp.client.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", u, resp.StatusCode)
+	}
+	return jsoniter.NewDecoder(io.LimitReader(resp.Body, cos.MiB)).Decode(v)
+}
+
+//
+// logins in progress ============================================================
+//
+
+func (rp *oidcRP) addPending(state string, areq *oidcAuthReq) error {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	now := time.Now()
+	for s, a := range rp.pending {
+		if a.expires.Before(now) {
+			delete(rp.pending, s)
+		}
+	}
+	if len(rp.pending) >= oidcMaxPending {
+		return errors.New("OIDC: too many logins in progress, try again later")
+	}
+	rp.pending[state] = areq
+	return nil
+}
+
+func (rp *oidcRP) popPending(state string) *oidcAuthReq {
+	if state == "" {
+		return nil
+	}
+	rp.mu.Lock()
+	areq, ok := rp.pending[state]
+	delete(rp.pending, state)
+	rp.mu.Unlock()
+	if !ok || areq.expires.Before(time.Now()) {
+		return nil
+	}
+	return areq
+}
+
+func randString() string {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		cos.ExitLogf("failed to generate random: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
@@ -0,0 +1,246 @@
+//go:build debug
+
+// Package authn
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package main
+
+// NOTE go:build debug (above) =====================================
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/api/authn"
+	"github.com/NVIDIA/aistore/cmd/authn/tok"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/core/mock"
+	"github.com/NVIDIA/aistore/tools/tassert"
+	"github.com/golang-jwt/jwt/v4"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	testClientID = "ais-authn"
+	testKID      = "kid-1"
+)
+
+// minimal OpenID provider: discovery, JWKS, and token endpoint (that returns `idToken`)
+type testIdP struct {
+	srv     *httptest.Server
+	key     *rsa.PrivateKey
+	mu      sync.Mutex
+	idToken func(nonce string) string
+	nonce   string // as per the last authorization request
+}
+
+func newTestIdP(t *testing.T) *testIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	tassert.CheckFatal(t, err)
+	idp := &testIdP{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		jsoniter.NewEncoder(w).Encode(&oidcDiscovery{
+			Issuer:   idp.srv.URL,
+			AuthURL:  idp.srv.URL + "/authorize",
+			TokenURL: idp.srv.URL + "/token",
+			JWKSURL:  idp.srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		e := big.NewInt(int64(key.E)).Bytes()
+		jsoniter.NewEncoder(w).Encode(&jwks{Keys: []jwk{{
+			Kty: "RSA", Kid: testKID, Use: "sig",
+			N: base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E: base64.RawURLEncoding.EncodeToString(e),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "good-code" || r.FormValue("code_verifier") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		idp.mu.Lock()
+		nonce := idp.nonce
+		idp.mu.Unlock()
+		jsoniter.NewEncoder(w).Encode(&oidcTokenResp{IDToken: idp.idToken(nonce)})
+	})
+	idp.srv = httptest.NewServer(mux)
+	return idp
+}
+
+func (idp *testIdP) sign(t *testing.T, claims jwt.MapClaims, key *rsa.PrivateKey) string {
+	tk := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tk.Header["kid"] = testKID
+	s, err := tk.SignedString(key)
+	tassert.CheckFatal(t, err)
+	return s
+}
+
+func (idp *testIdP) claims(sub string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            idp.srv.URL,
+		"aud":            testClientID,
+		"sub":            sub,
+		"email":          sub + "@example.com",
+		"email_verified": true,
+		"groups":         []string{"storage-users"},
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+	}
+}
+
+func newTestOIDC(t *testing.T, idp *testIdP) *oidcRP {
+	if Conf.Log.Level == "" {
+		Conf.Log.Level = "3" // see Conf.Verbose()
+	}
+	driver := mock.NewDBDriver()
+	m, err := newMgr(driver)
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, m.addRole(guestRole))
+	conf := &authn.OIDCConf{
+		Issuer:      idp.srv.URL,
+		ClientID:    testClientID,
+		RedirectURL: "http://localhost:52001" + apc.URLPathOIDC.Join(apc.OIDCCallback),
+		RoleMap: map[string][]string{
+			"storage-users":    {GuestRole},
+			"hd=other.example": {authn.AdminRole},
+		},
+	}
+	return newOIDC(m, conf)
+}
+
+func oidcLogin(rp *oidcRP, idToken string) *httptest.ResponseRecorder {
+	body := cos.MustMarshal(&authn.OIDCLoginMsg{IDToken: idToken})
+	req := httptest.NewRequest(http.MethodPost, apc.URLPathOIDC.Join(apc.OIDCToken), strings.NewReader(string(body)))
+	w := httptest.NewRecorder()
+	rp.handler(w, req)
+	return w
+}
+
+func checkOIDCToken(t *testing.T, w *httptest.ResponseRecorder, uid string, admin bool) {
+	tassert.Fatalf(t, w.Code == http.StatusOK, "expected 200, got %d: %s", w.Code, w.Body.String())
+	msg := &authn.TokenMsg{}
+	tassert.CheckFatal(t, jsoniter.Unmarshal(w.Body.Bytes(), msg))
+	tk, err := tok.DecryptToken(msg.Token, Conf.Secret())
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, tk.UserID == uid, "user %q vs %q", tk.UserID, uid)
+	tassert.Errorf(t, tk.IsAdmin == admin, "%s: admin %t", tk, tk.IsAdmin)
+	if !admin {
+		tassert.Errorf(t, len(tk.ClusterACLs) == 1 && tk.ClusterACLs[0].Access == apc.AccessRO,
+			"%s: unexpected cluster ACLs %v", tk, tk.ClusterACLs)
+	}
+}
+
+func TestOIDCToken(t *testing.T) {
+	idp := newTestIdP(t)
+	defer idp.srv.Close()
+	rp := newTestOIDC(t, idp)
+
+	// groups claim => Guest
+	checkOIDCToken(t, oidcLogin(rp, idp.sign(t, idp.claims("alice"), idp.key)), "alice@example.com", false)
+
+	// "<claim>=<value>" => Admin; custom user claim
+	rp.conf.UserClaim = "sub"
+	claims := idp.claims("bob")
+	claims["groups"], claims["hd"] = []string{}, "other.example"
+	checkOIDCToken(t, oidcLogin(rp, idp.sign(t, claims, idp.key)), "bob", true)
+	rp.conf.UserClaim = ""
+
+	// negative
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	tassert.CheckFatal(t, err)
+	tests := []struct {
+		name   string
+		mutate func(jwt.MapClaims)
+		key    *rsa.PrivateKey
+	}{
+		{name: "bad-signature", key: otherKey},
+		{name: "expired", mutate: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		{name: "no-expiration", mutate: func(c jwt.MapClaims) { delete(c, "exp") }},
+		{name: "wrong-issuer", mutate: func(c jwt.MapClaims) { c["iss"] = "https://evil.example" }},
+		{name: "wrong-audience", mutate: func(c jwt.MapClaims) { c["aud"] = "another-client" }},
+		{name: "email-not-verified", mutate: func(c jwt.MapClaims) { c["email_verified"] = false }},
+		{name: "no-roles", mutate: func(c jwt.MapClaims) { c["groups"] = []string{"unknown"} }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims := idp.claims("eve")
+			if test.mutate != nil {
+				test.mutate(claims)
+			}
+			key := idp.key
+			if test.key != nil {
+				key = test.key
+			}
+			w := oidcLogin(rp, idp.sign(t, claims, key))
+			tassert.Errorf(t, w.Code == http.StatusUnauthorized, "expected 401, got %d: %s", w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestOIDCCodeFlow(t *testing.T) {
+	idp := newTestIdP(t)
+	defer idp.srv.Close()
+	rp := newTestOIDC(t, idp)
+	idp.idToken = func(nonce string) string {
+		claims := idp.claims("carol")
+		claims["nonce"] = nonce
+		return idp.sign(t, claims, idp.key)
+	}
+
+	// 1. login => redirect to the provider
+	w := httptest.NewRecorder()
+	rp.handler(w, httptest.NewRequest(http.MethodGet, apc.URLPathOIDC.Join(apc.OIDCLogin), http.NoBody))
+	tassert.Fatalf(t, w.Code == http.StatusFound, "expected 302, got %d: %s", w.Code, w.Body.String())
+	loc, err := url.Parse(w.Header().Get("Location"))
+	tassert.CheckFatal(t, err)
+	q := loc.Query()
+	tassert.Fatalf(t, strings.HasPrefix(loc.String(), idp.srv.URL+"/authorize?"), "unexpected redirect %q", loc)
+	tassert.Errorf(t, q.Get("client_id") == testClientID && q.Get("code_challenge_method") == "S256",
+		"unexpected authorization request %v", q)
+	state := q.Get("state")
+
+	// 2. callback with invalid state
+	w = httptest.NewRecorder()
+	rp.handler(w, httptest.NewRequest(http.MethodGet, apc.URLPathOIDC.Join(apc.OIDCCallback)+"?code=good-code&state=xyz", http.NoBody))
+	tassert.Errorf(t, w.Code == http.StatusUnauthorized, "expected 401, got %d", w.Code)
+
+	// 3. callback with wrong nonce (replayed ID token)
+	idp.mu.Lock()
+	idp.nonce = "stale-nonce"
+	idp.mu.Unlock()
+	w = httptest.NewRecorder()
+	rp.handler(w, httptest.NewRequest(http.MethodGet, apc.URLPathOIDC.Join(apc.OIDCCallback)+"?code=good-code&state="+state, http.NoBody))
+	tassert.Errorf(t, w.Code == http.StatusUnauthorized, "expected 401, got %d", w.Code)
+
+	// 4. new login and successful callback; state is single-use
+	w = httptest.NewRecorder()
+	rp.handler(w, httptest.NewRequest(http.MethodGet, apc.URLPathOIDC.Join(apc.OIDCLogin), http.NoBody))
+	loc, err = url.Parse(w.Header().Get("Location"))
+	tassert.CheckFatal(t, err)
+	q = loc.Query()
+	idp.mu.Lock()
+	idp.nonce = q.Get("nonce")
+	idp.mu.Unlock()
+	callback := apc.URLPathOIDC.Join(apc.OIDCCallback) + "?code=good-code&state=" + q.Get("state")
+	w = httptest.NewRecorder()
+	rp.handler(w, httptest.NewRequest(http.MethodGet, callback, http.NoBody))
+	checkOIDCToken(t, w, "carol@example.com", false)
+
+	w = httptest.NewRecorder()
+	rp.handler(w, httptest.NewRequest(http.MethodGet, callback, http.NoBody))
+	tassert.Errorf(t, w.Code == http.StatusUnauthorized, "expected 401 (state reuse), got %d", w.Code)
+}
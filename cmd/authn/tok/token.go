@@ -26,6 +26,14 @@ type Token struct {
 	ClusterACLs []*authn.CluACL `json:"clusters"`
 	BucketACLs  []*authn.BckACL `json:"buckets,omitempty"`
 	IsAdmin     bool            `json:"admin"`
+	// unique token ID (tokens issued by older AuthN versions have none)
+	ID string `json:"jti,omitempty"`
+	// scoped token: ID of the token it was derived from (revoking the latter revokes the former as well)
+	Parent string `json:"parent,omitempty"`
+	// scoped token: single bucket, optional object name prefix (see authn.BckACL.Match), and subset of permissions
+	Scope *authn.BckACL `json:"scope,omitempty"`
+	// user quota (enforced by proxies, see cmn.ErrQuotaExceeded)
+	Quota *cmn.QuotaConf `json:"quota,omitempty"`
 }
 
 var (
@@ -35,6 +43,7 @@ var (
 	ErrNoBearerToken = errors.New("invalid token: no bearer")
 	ErrTokenExpired  = errors.New("token expired")
 	ErrTokenRevoked  = errors.New("token revoked")
+	ErrScopedToken   = errors.New("scoped token")
 )
 
 // TODO: cos.Unsafe* and other micro-optimization and refactoring
@@ -44,18 +53,66 @@ func AdminJWT(expires time.Time, userID, secret string) (string, error) {
 		"expires":  expires,
 		"username": userID,
 		"admin":    true,
+		"jti":      cos.GenUUID(),
 	})
 	return t.SignedString([]byte(secret))
 }
 
 func JWT(expires time.Time, userID string, bucketACLs []*authn.BckACL, clusterACLs []*authn.CluACL,
-	secret string) (string, error) {
-	t := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	quota *cmn.QuotaConf, secret string) (string, error) {
+	claims := jwt.MapClaims{
 		"expires":  expires,
 		"username": userID,
 		"buckets":  bucketACLs,
 		"clusters": clusterACLs,
-	})
+		"jti":      cos.GenUUID(),
+	}
+	if quota.IsSet() {
+		claims["quota"] = quota
+	}
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return t.SignedString([]byte(secret))
+}
+
+// ScopedJWT derives a scoped token from the `parent` one: same user and ACLs
+// further restricted to a single bucket (and object name prefix) and a subset of permissions.
+// Cluster-wide permissions (e.g. LIST-BUCKETS that is part of apc.AccessRO) are dropped from the scope.
+// The scoped token never outlives its parent.
+func ScopedJWT(parent *Token, bckScope *authn.BckACL, expires time.Time, secret string) (string, error) {
+	scope := *bckScope
+	scope.Access &^= accessCluster
+	switch {
+	case parent.Scope != nil:
+		return "", fmt.Errorf("cannot derive from another %v", ErrScopedToken)
+	case parent.ID == "":
+		return "", errors.New("cannot derive scoped token from a token without ID (please log in again)")
+	case scope.Bck.Name == "":
+		return "", errors.New("scoped token: bucket name is required")
+	case scope.Deny:
+		return "", errors.New("scoped token: scope cannot be a deny rule")
+	case scope.Access == 0:
+		return "", errors.New("scoped token: no bucket or object permissions")
+	}
+	if expires.After(parent.Expires) {
+		expires = parent.Expires
+	}
+	claims := jwt.MapClaims{
+		"expires":  expires,
+		"username": parent.UserID,
+		"jti":      cos.GenUUID(),
+		"parent":   parent.ID,
+		"scope":    &scope,
+	}
+	if parent.IsAdmin {
+		claims["admin"] = true
+	} else {
+		claims["buckets"] = parent.BucketACLs
+		claims["clusters"] = parent.ClusterACLs
+	}
+	if parent.Quota.IsSet() {
+		claims["quota"] = parent.Quota
+	}
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return t.SignedString([]byte(secret))
 }
 
@@ -98,6 +155,9 @@ func DecryptToken(tokenStr, secret string) (*Token, error) {
 ///////////
 
 func (tk *Token) String() string {
+	if tk.Scope != nil {
+		return fmt.Sprintf("user %s, scope %s, %s", tk.UserID, tk.Scope, expiresIn(tk.Expires))
+	}
 	return fmt.Sprintf("user %s, %s", tk.UserID, expiresIn(tk.Expires))
 }
 
@@ -109,17 +169,33 @@ func (tk *Token) String() string {
 // Permissions for a cluster with empty ID are used as default ones when
 // a user do not have permissions for the given `clusterID`.
 //
+// In addition, bucket ACLs can be scoped to a subset of objects (see authn.BckACL.Match)
+// and can deny (rather than grant) permissions.
+//
 // ACL rules are checked in the following order (from highest to the lowest priority):
 //  1. A user's role is an admin.
-//  2. User's permissions for the given bucket
-//  3. User's permissions for the given cluster
-//  4. User's default cluster permissions (ACL for a cluster with empty clusterID)
+//  2. User's deny rules for the given bucket (and object, if specified; bucket-level
+//     access is denied when any of the bucket's deny rules covers the requested permissions)
+//  3. User's most specific prefix-scoped permissions for the given object
+//  4. User's permissions for the given bucket
+//  5. User's permissions for the given cluster
+//  6. User's default cluster permissions (ACL for a cluster with empty clusterID)
 //
 // If there are no defined ACL found at any step, any access is denied.
 
 const accessCluster = apc.AceListBuckets | apc.AceCreateBucket | apc.AceDestroyBucket | apc.AceMoveBucket | apc.AceShowCluster | apc.AceAdmin
 
 func (tk *Token) CheckPermissions(clusterID string, bck *cmn.Bck, perms apc.AccessAttrs) error {
+	return tk.CheckObjPermissions(clusterID, bck, "" /*objName*/, perms)
+}
+
+// same as above for a given object (empty `objName` - bucket-level access)
+func (tk *Token) CheckObjPermissions(clusterID string, bck *cmn.Bck, objName string, perms apc.AccessAttrs) error {
+	if tk.Scope != nil {
+		if err := tk.checkScope(clusterID, bck, objName, perms); err != nil {
+			return err
+		}
+	}
 	if tk.IsAdmin {
 		return nil
 	}
@@ -150,6 +226,19 @@ func (tk *Token) CheckPermissions(clusterID string, bck *cmn.Bck, perms apc.Acce
 	if bck == nil {
 		return errors.New("requested bucket permissions without a bucket")
 	}
+	if deny := tk.denyForObject(clusterID, bck, objName, objPerms); deny != nil {
+		return fmt.Errorf("user `%s` has %v: [%s, %s, denied(%s)]", tk.UserID,
+			ErrNoPermissions, tk, bck.Cname(objName), deny.Access.Describe(false /*include all*/))
+	}
+	if objName != "" {
+		if acl := tk.aclForObject(clusterID, bck, objName); acl != nil {
+			if acl.Access.Has(objPerms) {
+				return nil
+			}
+			return fmt.Errorf("user `%s` has %v: [%s, %s, granted(%s)]", tk.UserID,
+				ErrNoPermissions, tk, acl, acl.Access.Describe(false /*include all*/))
+		}
+	}
 	bckACL, bckOk := tk.aclForBucket(clusterID, bck)
 	if bckOk {
 		if bckACL.Has(objPerms) {
@@ -195,18 +284,86 @@ func (tk *Token) aclForCluster(clusterID string) (perms apc.AccessAttrs, ok bool
 	return 0, false
 }
 
+// bucket-wide (allow) ACL
 func (tk *Token) aclForBucket(clusterID string, bck *cmn.Bck) (perms apc.AccessAttrs, ok bool) {
 	for _, b := range tk.BucketACLs {
-		tbBck := b.Bck
-		if tbBck.Ns.UUID != clusterID {
+		if b.Prefix != "" || b.Deny || !sameBucket(b, clusterID, bck) {
 			continue
 		}
-		// For AuthN all buckets are external: they have UUIDs of the respective AIS clusters.
-		// To correctly compare with the caller's `bck` we construct tokenBck from the token.
-		tokenBck := cmn.Bck{Name: tbBck.Name, Provider: tbBck.Provider}
-		if tokenBck.Equal(bck) {
-			return b.Access, true
-		}
+		return b.Access, true
 	}
 	return 0, false
 }
+
+// the most specific prefix-scoped (allow) ACL that matches the object
+func (tk *Token) aclForObject(clusterID string, bck *cmn.Bck, objName string) (acl *authn.BckACL) {
+	for _, b := range tk.BucketACLs {
+		if b.Prefix == "" || b.Deny || !sameBucket(b, clusterID, bck) || !b.Match(objName) {
+			continue
+		}
+		if acl == nil || b.Specificity() > acl.Specificity() {
+			acl = b
+		}
+	}
+	return acl
+}
+
+// the first deny rule that matches the object and denies any of the requested permissions;
+// bucket-level access (empty objName) covers multi-object operations (copy/transform bucket,
+// archive, list/range delete, prefetch, etc.) - any prefix-scoped deny rule for the bucket
+// applies, except for listing (and HEAD) that do not access objects' content
+const accessBckMeta = apc.AceObjLIST | apc.AceBckHEAD | apc.AceListBuckets
+
+func (tk *Token) denyForObject(clusterID string, bck *cmn.Bck, objName string, perms apc.AccessAttrs) *authn.BckACL {
+	for _, b := range tk.BucketACLs {
+		if !b.Deny || b.Access&perms == 0 || !sameBucket(b, clusterID, bck) {
+			continue
+		}
+		switch {
+		case b.Prefix == "":
+			return b
+		case objName != "":
+			if b.Match(objName) {
+				return b
+			}
+		default:
+			if b.Access&perms&^accessBckMeta != 0 {
+				return b
+			}
+		}
+	}
+	return nil
+}
+
+// scoped token: the requested access must be within the scope
+// (in addition to being granted by the token's ACLs)
+func (tk *Token) checkScope(clusterID string, bck *cmn.Bck, objName string, perms apc.AccessAttrs) error {
+	scope := tk.Scope
+	if scope.Bck.Ns.UUID == "" {
+		clusterID = "" // any cluster
+	}
+	switch {
+	case perms&accessCluster != 0:
+		return fmt.Errorf("user `%s` has %v: [%s, cluster-wide access]", tk.UserID, ErrNoPermissions, tk)
+	case bck == nil:
+		return errors.New("requested bucket permissions without a bucket")
+	case !sameBucket(scope, clusterID, bck):
+		return fmt.Errorf("user `%s` has %v: [%s, bucket %s]", tk.UserID, ErrNoPermissions, tk, bck.String())
+	case scope.Prefix != "" && (objName == "" || !scope.Match(objName)):
+		return fmt.Errorf("user `%s` has %v: [%s, %s]", tk.UserID, ErrNoPermissions, tk, bck.Cname(objName))
+	case !scope.Access.Has(perms):
+		return fmt.Errorf("user `%s` has %v: [%s, granted(%s)]", tk.UserID, ErrNoPermissions, tk,
+			scope.Access.Describe(false /*include all*/))
+	}
+	return nil
+}
+
+func sameBucket(acl *authn.BckACL, clusterID string, bck *cmn.Bck) bool {
+	if acl.Bck.Ns.UUID != clusterID {
+		return false
+	}
+	// For AuthN all buckets are external: they have UUIDs of the respective AIS clusters.
+	// To correctly compare with the caller's `bck` we construct tokenBck from the token.
+	tokenBck := cmn.Bck{Name: acl.Bck.Name, Provider: acl.Bck.Provider}
+	return tokenBck.Equal(bck)
+}
@@ -637,3 +637,257 @@ func TestMergeBckACLS(t *testing.T) {
 		}
 	}
 }
+
+func TestPrefixACLs(t *testing.T) {
+	const (
+		cluID = "test-clu-id"
+		uname = "user-x"
+		upass = "pass-x"
+	)
+	var (
+		data = cmn.Bck{Name: "data", Provider: apc.AWS, Ns: cmn.Ns{UUID: cluID}}
+		bck  = &cmn.Bck{Name: "data", Provider: apc.AWS}
+		role = &authn.Role{
+			Name: "data-user",
+			ClusterACLs: []*authn.CluACL{
+				{ID: cluID, Access: apc.AceListBuckets},
+			},
+			BucketACLs: []*authn.BckACL{
+				{Bck: data, Access: apc.AceObjLIST},
+				{Bck: data, Prefix: "raw/*", Access: apc.AccessRO},
+				{Bck: data, Prefix: "user-x/", Access: apc.AccessRW},
+				{Bck: data, Prefix: "user-x/tmp/*", Access: apc.AceGET | apc.AceObjHEAD},
+				{Bck: data, Prefix: "*.key", Access: apc.AccessRW, Deny: true},
+			},
+		}
+	)
+	driver := mock.NewDBDriver()
+	mgr, err := newMgr(driver)
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, mgr.addRole(role))
+	tassert.CheckFatal(t, mgr.addUser(&authn.User{ID: uname, Password: upass, Roles: []*authn.Role{role}}))
+
+	token, err := mgr.issueToken(uname, upass, &authn.LoginMsg{})
+	tassert.CheckFatal(t, err)
+	tk, err := tok.DecryptToken(token, Conf.Secret())
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(tk.BucketACLs) == len(role.BucketACLs), "expected %d bucket ACLs, got %d",
+		len(role.BucketACLs), len(tk.BucketACLs))
+
+	tests := []struct {
+		objName string
+		perms   apc.AccessAttrs
+		allowed bool
+	}{
+		{"", apc.AceObjLIST, true},
+		{"", apc.AceGET, false},
+		{"raw/a/b/c.tar", apc.AceGET, true},
+		{"raw/a/b/c.tar", apc.AcePUT, false},
+		{"raw", apc.AceGET, false},
+		{"user-x/report.csv", apc.AcePUT, true},
+		{"user-x/report.csv", apc.AceObjDELETE, true},
+		{"user-x/tmp/1.txt", apc.AceGET, true},      // the most specific rule applies
+		{"user-x/tmp/1.txt", apc.AcePUT, false},     // ditto
+		{"user-y/report.csv", apc.AceGET, false},    // no matching rule, no bucket (or cluster) read permission
+		{"raw/secret.key", apc.AceGET, false},       // deny takes precedence
+		{"user-x/id.key", apc.AcePUT, false},        // ditto
+		{"user-x/id.key.bak", apc.AcePUT, true},     // pattern must match the entire name
+		{"raw/a.tar", apc.AceListBuckets, true},     // cluster permission
+		{"raw/a.tar", apc.AceCreateBucket, false},   // ditto
+		{"other/a.tar", apc.AceObjLIST, true},       // falls back to the bucket ACL
+		{"user-x/tmp/1.txt", apc.AceObjLIST, false}, // the matching prefix rule overrides the bucket ACL
+	}
+	for _, test := range tests {
+		err := tk.CheckObjPermissions(cluID, bck, test.objName, test.perms)
+		if test.allowed {
+			tassert.Errorf(t, err == nil, "%q [%s]: expected to be allowed, got %v", test.objName, test.perms.Describe(false), err)
+		} else {
+			tassert.Errorf(t, err != nil, "%q [%s]: expected to be denied", test.objName, test.perms.Describe(false))
+		}
+	}
+
+	// bucket-wide deny rule
+	tk.BucketACLs = append(tk.BucketACLs, &authn.BckACL{Bck: data, Access: apc.AceObjDELETE, Deny: true})
+	tassert.Errorf(t, tk.CheckObjPermissions(cluID, bck, "user-x/report.csv", apc.AceObjDELETE) != nil,
+		"expected bucket-wide deny rule to take precedence")
+	tassert.Errorf(t, tk.CheckObjPermissions(cluID, bck, "user-x/report.csv", apc.AcePUT) == nil,
+		"expected bucket-wide deny rule to apply only to the denied permissions")
+
+	// bucket-level (multi-object) access vs prefix-scoped deny rules
+	tk.BucketACLs = []*authn.BckACL{
+		{Bck: data, Access: apc.AccessRW},
+		{Bck: data, Prefix: "*.key", Access: apc.AceGET | apc.AceObjDELETE, Deny: true},
+	}
+	bckTests := []struct {
+		perms   apc.AccessAttrs
+		allowed bool
+	}{
+		{apc.AccessRW, false},     // e.g., copy bucket
+		{apc.AceObjDELETE, false}, // e.g., delete list/range
+		{apc.AcePUT, true},
+		{apc.AceObjLIST, true}, // listing is not affected
+		{apc.AceBckHEAD, true},
+	}
+	for _, test := range bckTests {
+		err := tk.CheckPermissions(cluID, bck, test.perms)
+		if test.allowed {
+			tassert.Errorf(t, err == nil, "bucket [%s]: expected to be allowed, got %v", test.perms.Describe(false), err)
+		} else {
+			tassert.Errorf(t, err != nil, "bucket [%s]: expected to be denied", test.perms.Describe(false))
+		}
+	}
+	tassert.Errorf(t, tk.CheckObjPermissions(cluID, bck, "a.txt", apc.AceGET) == nil,
+		"expected prefix-scoped deny rule to apply only to the matching objects")
+}
+
+func TestWildcardACL(t *testing.T) {
+	tests := []struct {
+		prefix, objName string
+		match           bool
+	}{
+		{"", "any/thing", true},
+		{"raw/", "raw/a", true},
+		{"raw/", "rawdata", false},
+		{"raw", "rawdata", true},
+		{"raw/*", "raw/a/b/c", true},
+		{"raw/*", "raw/", true},
+		{"raw/*", "raw", false},
+		{"*/tmp/*", "user-x/tmp/1", true},
+		{"*/tmp/*", "user-x/tmp", false},
+		{"user-?/*", "user-x/a", true},
+		{"user-?/*", "user-xy/a", false},
+		{"*.tar", "a/b/c.tar", true},
+		{"*.tar", "a/b/c.tar.gz", false},
+		{"a*b*c", "aXbYbZc", true},
+		{"a*b*c", "aXbYbZ", false},
+	}
+	for _, test := range tests {
+		acl := &authn.BckACL{Prefix: test.prefix}
+		tassert.Errorf(t, acl.Match(test.objName) == test.match, "%q vs %q: expected match=%t", test.prefix, test.objName, test.match)
+	}
+}
+
+func TestScopedToken(t *testing.T) {
+	const cluID = "test-clu-id"
+	var (
+		secret = Conf.Secret()
+		data   = &cmn.Bck{Name: "data", Provider: apc.AWS}
+		other  = &cmn.Bck{Name: "other", Provider: apc.AWS}
+		role   = &authn.Role{
+			Name:        "rw-user",
+			ClusterACLs: []*authn.CluACL{{ID: cluID, Access: apc.AccessRW}},
+		}
+	)
+	driver := mock.NewDBDriver()
+	mgr, err := newMgr(driver)
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, mgr.addRole(role))
+	tassert.CheckFatal(t, mgr.addUser(&authn.User{ID: users[0], Password: passs[0], Roles: []*authn.Role{role}}))
+
+	parent, err := mgr.issueToken(users[0], passs[0], &authn.LoginMsg{})
+	tassert.CheckFatal(t, err)
+	ptk, err := tok.DecryptToken(parent, secret)
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, ptk.ID != "", "expected token ID")
+
+	// default: read-only, 15 minutes
+	msg := &authn.ScopedTokenMsg{Scope: authn.BckACL{Bck: *data, Prefix: "raw/*"}}
+	token, err := mgr.issueScopedToken(parent, msg)
+	tassert.CheckFatal(t, err)
+	tk, err := tok.DecryptToken(token, secret)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, tk.Parent == ptk.ID && tk.ID != ptk.ID && tk.ID != "", "unexpected IDs: %q, %q (parent %q)", tk.ID, tk.Parent, ptk.ID)
+	tassert.Errorf(t, time.Until(tk.Expires) <= authn.ScopedTokenDfltExpire, "unexpected expiration %v", tk.Expires)
+
+	tassert.CheckError(t, ptk.CheckObjPermissions(cluID, other, "raw/a", apc.AcePUT))
+	tests := []struct {
+		bck     *cmn.Bck
+		objName string
+		perms   apc.AccessAttrs
+		allowed bool
+	}{
+		{data, "raw/a", apc.AceGET, true},
+		{data, "raw/a", apc.AceObjHEAD, true},
+		{data, "raw/a", apc.AcePUT, false},  // read-only
+		{data, "user/a", apc.AceGET, false}, // outside prefix
+		{data, "", apc.AceObjLIST, false},   // ditto
+		{other, "raw/a", apc.AceGET, false}, // other bucket
+		{nil, "", apc.AceListBuckets, false},
+	}
+	for _, test := range tests {
+		err := tk.CheckObjPermissions(cluID, test.bck, test.objName, test.perms)
+		if test.allowed {
+			tassert.Errorf(t, err == nil, "%v/%q [%s]: expected to be allowed, got %v", test.bck, test.objName, test.perms.Describe(false), err)
+		} else {
+			tassert.Errorf(t, err != nil, "%v/%q [%s]: expected to be denied", test.bck, test.objName, test.perms.Describe(false))
+		}
+	}
+
+	// cannot exceed parent's permissions (nor expiration)
+	long := 1000 * time.Hour
+	msg = &authn.ScopedTokenMsg{Scope: authn.BckACL{Bck: *data, Access: apc.AceAdmin}, ExpiresIn: &long}
+	_, err = mgr.issueScopedToken(parent, msg)
+	tassert.Errorf(t, err != nil, "expected error: cluster-wide permissions only")
+	msg.Scope.Access = apc.AceGET | apc.AceBckSetACL | apc.AceAdmin
+	token, err = mgr.issueScopedToken(parent, msg)
+	tassert.CheckFatal(t, err)
+	tk, err = tok.DecryptToken(token, secret)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, tk.Scope.Access == apc.AceGET|apc.AceBckSetACL, "expected cluster-wide permissions to be dropped, got %s",
+		tk.Scope.Access.Describe(false))
+	tassert.Errorf(t, !tk.Expires.After(ptk.Expires), "scoped token outlives its parent: %v vs %v", tk.Expires, ptk.Expires)
+	tassert.Errorf(t, tk.CheckObjPermissions(cluID, data, "", apc.AceBckSetACL) != nil, "expected parent ACLs to apply")
+
+	// cannot derive from a scoped token nor from a revoked one
+	_, err = mgr.issueScopedToken(token, &authn.ScopedTokenMsg{Scope: authn.BckACL{Bck: *data}})
+	tassert.Errorf(t, err != nil, "expected error: scoped token from a scoped token")
+	tassert.CheckFatal(t, mgr.revokeToken(parent))
+	_, err = mgr.issueScopedToken(parent, &authn.ScopedTokenMsg{Scope: authn.BckACL{Bck: *data}})
+	tassert.Errorf(t, err != nil, "expected error: scoped token from a revoked token")
+}
+
+func TestUserQuota(t *testing.T) {
+	var (
+		secret = Conf.Secret()
+		data   = &cmn.Bck{Name: "data", Provider: apc.AIS}
+		quota  = &cmn.QuotaConf{Size: cos.SizeIEC(cos.GiB), Objects: 1000}
+		role   = &authn.Role{
+			Name:        "rw-user",
+			ClusterACLs: []*authn.CluACL{{ID: "test-clu-id", Access: apc.AccessRW}},
+		}
+	)
+	driver := mock.NewDBDriver()
+	mgr, err := newMgr(driver)
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, mgr.addRole(role))
+	tassert.CheckFatal(t, mgr.addUser(&authn.User{ID: users[0], Password: passs[0], Roles: []*authn.Role{role}}))
+	err = mgr.addUser(&authn.User{ID: users[1], Password: passs[1], Quota: &cmn.QuotaConf{Objects: -1}})
+	tassert.Errorf(t, err != nil, "expected error: negative quota")
+
+	// no quota
+	token, err := mgr.issueToken(users[0], passs[0], &authn.LoginMsg{})
+	tassert.CheckFatal(t, err)
+	tk, err := tok.DecryptToken(token, secret)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, tk.Quota == nil, "expected no quota, got %+v", tk.Quota)
+
+	// set quota: carried by both regular and scoped tokens
+	tassert.CheckFatal(t, mgr.updateUser(users[0], &authn.User{Quota: quota}))
+	token, err = mgr.issueToken(users[0], passs[0], &authn.LoginMsg{})
+	tassert.CheckFatal(t, err)
+	tk, err = tok.DecryptToken(token, secret)
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, tk.Quota != nil && *tk.Quota == *quota, "expected quota %+v, got %+v", quota, tk.Quota)
+
+	scoped, err := mgr.issueScopedToken(token, &authn.ScopedTokenMsg{Scope: authn.BckACL{Bck: *data}})
+	tassert.CheckFatal(t, err)
+	tk, err = tok.DecryptToken(scoped, secret)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, tk.Quota != nil && *tk.Quota == *quota, "scoped token: expected quota %+v, got %+v", quota, tk.Quota)
+
+	// remove quota
+	tassert.CheckFatal(t, mgr.updateUser(users[0], &authn.User{Quota: &cmn.QuotaConf{}}))
+	uInfo, err := mgr.lookupUser(users[0])
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, uInfo.Quota == nil && len(uInfo.Roles) == 1, "unexpected user record %+v", uInfo)
+}
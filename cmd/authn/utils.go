@@ -12,7 +12,8 @@ type bckACLList []*authn.BckACL
 
 func (bckList bckACLList) updated(bckACL *authn.BckACL) bool {
 	for _, acl := range bckList {
-		if acl.Bck.Equal(&bckACL.Bck) {
+		// same bucket, same scope (prefix), and same kind (allow or deny)
+		if acl.Bck.Equal(&bckACL.Bck) && acl.Prefix == bckACL.Prefix && acl.Deny == bckACL.Deny {
 			acl.Access = bckACL.Access
 			return true
 		}
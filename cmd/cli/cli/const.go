@@ -1092,6 +1092,8 @@ var (
 			indent4 + "\t - 'hrev' or 'hrev://' - same, but aistore nodes will reverse-proxy requests to their respective ETL containers)\n" +
 			indent4 + "\t - 'io' or 'io://' - for each request an aistore node will: run ETL container locally, write data\n" +
 			indent4 + "\t   to its standard input and then read transformed data from the standard output\n" +
+			indent4 + "\t - 'grpc' or 'grpc://' - ETL container serves gRPC: one bidirectional stream per object\n" +
+			indent4 + "\t   over a single long-lived connection (init spec only)\n" +
 			indent4 + "\t For more defails, see https://aiatscale.org/docs/etl#communication-mechanisms\n",
 	}
 
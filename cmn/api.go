@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
@@ -18,6 +19,8 @@ import (
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/cmn/feat"
 	"github.com/NVIDIA/aistore/cmn/nlog"
+
+	jsoniter "github.com/json-iterator/go"
 )
 
 // Bprops - manageable, user-configurable, and inheritable (from cluster config).
@@ -39,6 +42,8 @@ const (
 	PropBackendBck         = "backend_bck"
 	PropBackendBckName     = PropBackendBck + ".name"
 	PropBackendBckProvider = PropBackendBck + ".provider"
+	PropLifecycleRules     = "lifecycle.rules" // JSON-formatted list of cmn.LifecycleRule
+	PropReplicationBck     = "replication.bck" // destination bucket, e.g. "ais://@remais-alias/bucket-name"
 )
 
 type (
@@ -57,15 +62,136 @@ type (
 		BID         uint64          `json:"bid,string" list:"omit"`         // unique ID
 		Created     int64           `json:"created,string" list:"readonly"` // creation timestamp
 		Versioning  VersionConf     `json:"versioning"`                     // versioning (see "inherit")
+		Xact        XactLimProps    `json:"xact"`                           // per-bucket xaction limits
+		Lifecycle   LifecycleConf   `json:"lifecycle"`                      // lifecycle rules (expiration, eviction, transition)
+		ObjLock     ObjLockConf     `json:"object_lock"`                    // object lock (WORM)
+		Replication ReplicationConf `json:"replication"`                    // cross-cluster replication
+		Encryption  EncryptionConf  `json:"encryption"`                     // server-side encryption at rest
+		Audit       AuditBckConf    `json:"audit"`                          // audit log (see AuditConf)
+		Quota       QuotaConf       `json:"quota"`                          // capacity and object-count limits
+	}
+
+	// capacity and object-count quota (zero: unlimited)
+	// - enforced by proxies upon PUT, append, and promote (see ErrQuotaExceeded) - except
+	//   overwrites at the object-count limit and objects of unknown size that are enforced by targets;
+	// - current usage is tracked by targets and periodically recomputed by apc.ActQuotaUsage xaction;
+	// - same structure is used for per-user quotas (see api/authn User)
+	QuotaConf struct {
+		Size    cos.SizeIEC `json:"size"`    // total size of the objects
+		Objects int64       `json:"objects"` // number of objects
+	}
+	QuotaConfToSet struct {
+		Size    *cos.SizeIEC `json:"size,omitempty"`
+		Objects *int64       `json:"objects,omitempty"`
+	}
+
+	// record the bucket's data- and control-plane operations in the audit log
+	// (regardless of the cluster-wide audit.enabled)
+	AuditBckConf struct {
+		Enabled bool `json:"enabled"`
+	}
+	AuditBckConfToSet struct {
+		Enabled *bool `json:"enabled,omitempty"`
+	}
+
+	// server-side encryption at rest (ais buckets only; see fs/sse)
+	// - objects are encrypted (AES-256-GCM) when written and decrypted when read,
+	//   with data keys generated and wrapped by the configured KMS (see env.KMS);
+	// - enabling (or disabling) applies to objects written from then on; to (re-)encrypt
+	//   existing objects and rotate data keys, run apc.ActRotateKey xaction
+	EncryptionConf struct {
+		KeyID   string `json:"key_id"` // KMS key: file name (keyfile), transit key name (vault), key ID or ARN (aws)
+		Enabled bool   `json:"enabled"`
+	}
+	EncryptionConfToSet struct {
+		KeyID   *string `json:"key_id,omitempty"`
+		Enabled *bool   `json:"enabled,omitempty"`
+	}
+
+	// cross-cluster asynchronous bucket replication (see xact/xs/replicate)
+	// - new and updated objects are shipped to a bucket in a remote (attached) AIS cluster
+	//   by the on-demand apc.ActReplicate xaction;
+	// - per-object replication state is recorded in the object's metadata (ReplStateObjMD),
+	//   to resume after restarts and retry after failures via periodic resync;
+	// - active-active: both clusters accept writes and replicate to each other (each side
+	//   configured with the other's bucket as destination); updates carry version vectors
+	//   (ReplVVObjMD) and concurrent updates are resolved as per Resolve (in lieu of Conflict);
+	// - deletions are not replicated
+	ReplicationConf struct {
+		Bck          Bck    `json:"bck"`      // destination: remote ais bucket
+		Conflict     string `json:"conflict"` // apc.ReplConflictOverwrite (default) or apc.ReplConflictSkip
+		Resolve      string `json:"resolve"`  // active-active: apc.ReplResolveLWW (default), et al.
+		Prefer       string `json:"prefer"`   // cluster UUID (apc.ReplResolvePrefer only)
+		Enabled      bool   `json:"enabled"`
+		ActiveActive bool   `json:"active_active"` // accept replicated writes from the destination
+	}
+	ReplicationConfToSet struct {
+		Bck          *Bck    `json:"bck,omitempty"`
+		Conflict     *string `json:"conflict,omitempty"`
+		Resolve      *string `json:"resolve,omitempty"`
+		Prefer       *string `json:"prefer,omitempty"`
+		Enabled      *bool   `json:"enabled,omitempty"`
+		ActiveActive *bool   `json:"active_active,omitempty"`
+	}
+
+	// object lock (WORM): objects cannot be overwritten, appended, renamed, or deleted until their
+	// retention (RetainUntilObjMD) expires
+	// - retention is set when objects are written: either explicitly (apc.HdrObjRetainUntil)
+	//   or by default (Retention)
+	// - retention can be extended (apc.ActExtendRetain) but not shortened
+	ObjLockConf struct {
+		Mode      string       `json:"mode"`      // apc.ObjLockGovernance or apc.ObjLockCompliance; empty: disabled
+		Retention cos.Duration `json:"retention"` // default retention of new objects (zero: none)
+	}
+	ObjLockConfToSet struct {
+		Mode      *string       `json:"mode,omitempty"`
+		Retention *cos.Duration `json:"retention,omitempty"`
+	}
+
+	// bucket lifecycle rules (compare with S3 lifecycle configuration)
+	// - enforced by periodic apc.ActLifecycle xaction (see xact/xs/lifecycle)
+	// - each rule applies to objects with a given name prefix (empty prefix: all objects);
+	//   the object's age is the time since it was last written, idle time - since last accessed
+	// - matching rules are evaluated in order, and the first applicable action wins;
+	//   within a rule, expiration takes precedence over transition, and transition over eviction
+	LifecycleConf struct {
+		Rules   []LifecycleRule `json:"rules"`
+		Enabled bool            `json:"enabled"`
+	}
+	LifecycleConfToSet struct {
+		Rules   *[]LifecycleRule `json:"rules,omitempty"`
+		Enabled *bool            `json:"enabled,omitempty"`
+	}
+	LifecycleRule struct {
+		ID             string `json:"id,omitempty"`
+		Prefix         string `json:"prefix,omitempty"`
+		TransitionBck  Bck    `json:"transition_bck,omitempty"`  // destination ("colder") bucket
+		ExpireDays     int    `json:"expire_days,omitempty"`     // remove objects older than
+		EvictDays      int    `json:"evict_days,omitempty"`      // remote buckets only: evict in-cluster copies idle for
+		TransitionDays int    `json:"transition_days,omitempty"` // move objects older than to TransitionBck
+	}
+
+	// per-bucket xaction limits (zero - unlimited), to prevent one bucket's (tenant's) bulk jobs
+	// from starving another's
+	// - enforced by xreg when renewing xactions, and by background copies (see xreg/bcklim)
+	XactLimProps struct {
+		MaxConcurrent int         `json:"max_concurrent"` // running xactions that read or write the bucket
+		Bandwidth     cos.SizeIEC `json:"max_bandwidth"`  // bytes per second: all background copies from or to the bucket
+	}
+	XactLimPropsToSet struct {
+		MaxConcurrent *int         `json:"max_concurrent,omitempty"`
+		Bandwidth     *cos.SizeIEC `json:"max_bandwidth,omitempty"`
 	}
 
 	ExtraProps struct {
 		AWS  ExtraPropsAWS  `json:"aws,omitempty" list:"omitempty"`
+		GCP  ExtraPropsGCP  `json:"gcp,omitempty" list:"omitempty"`
 		HTTP ExtraPropsHTTP `json:"http,omitempty" list:"omitempty"`
 		HDFS ExtraPropsHDFS `json:"hdfs,omitempty" list:"omitempty"` // NOTE: obsolete; rm with meta-version
 	}
 	ExtraToSet struct { // ref. bpropsFilterExtra
 		AWS  *ExtraPropsAWSToSet  `json:"aws"`
+		GCP  *ExtraPropsGCPToSet  `json:"gcp"`
 		HTTP *ExtraPropsHTTPToSet `json:"http"`
 		HDFS *ExtraPropsHDFSToSet `json:"hdfs"` // ditto
 	}
@@ -84,6 +210,10 @@ type (
 		// or AWS_DEFAULT_PROFILE if the Shared Config is enabled)."
 		Profile string `json:"profile,omitempty"`
 
+		// requester (rather than bucket owner) pays for requests and data transfer
+		// ref: https://docs.aws.amazon.com/AmazonS3/latest/userguide/RequesterPaysBuckets.html
+		RequesterPays bool `json:"requester_pays,omitempty"`
+
 		// Amazon S3: 1000
 		// - https://docs.aws.amazon.com/cli/latest/userguide/cli-usage-pagination.html#cli-usage-pagination-serverside
 		// vs OpenStack Swift: 10,000
@@ -91,18 +221,43 @@ type (
 		MaxPageSize int64 `json:"max_pagesize,omitempty"`
 	}
 	ExtraPropsAWSToSet struct {
-		CloudRegion *string `json:"cloud_region"`
-		Endpoint    *string `json:"endpoint"`
-		Profile     *string `json:"profile"`
-		MaxPageSize *int64  `json:"max_pagesize"`
+		CloudRegion   *string `json:"cloud_region"`
+		Endpoint      *string `json:"endpoint"`
+		Profile       *string `json:"profile"`
+		RequesterPays *bool   `json:"requester_pays"`
+		MaxPageSize   *int64  `json:"max_pagesize"`
+	}
+
+	ExtraPropsGCP struct {
+		// Cloud KMS key (CMEK) to encrypt newly written objects, e.g.:
+		// "projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>"
+		// (objects already encrypted with CMEK are decrypted by GCS transparently)
+		// ref: https://cloud.google.com/storage/docs/encryption/customer-managed-keys
+		KMSKey string `json:"kms_key,omitempty"`
+
+		// local (target-side) pathname of the file containing base64-encoded
+		// AES-256 customer-supplied encryption key (CSEK) to read and write objects;
+		// the key itself is never stored in bucket metadata
+		// ref: https://cloud.google.com/storage/docs/encryption/customer-supplied-keys
+		CSEKFile string `json:"csek_file,omitempty"`
+	}
+	ExtraPropsGCPToSet struct {
+		KMSKey   *string `json:"kms_key"`
+		CSEKFile *string `json:"csek_file"`
 	}
 
 	ExtraPropsHTTP struct {
 		// Original URL prior to hashing.
 		OrigURLBck string `json:"original_url,omitempty" list:"readonly"`
+
+		// URL of the JSON or CSV manifest (object names, URLs, sizes, and checksums)
+		// that defines the bucket's contents - in lieu of the original URL
+		// (see docs/providers.md "HTTP(S) dataset with manifest")
+		Manifest string `json:"manifest,omitempty"`
 	}
 	ExtraPropsHTTPToSet struct {
 		OrigURLBck *string `json:"original_url"`
+		Manifest   *string `json:"manifest"`
 	}
 
 	ExtraPropsHDFS struct {
@@ -127,6 +282,13 @@ type (
 		Features    *feat.Flags           `json:"features,string,omitempty"`
 		WritePolicy *WritePolicyConfToSet `json:"write_policy,omitempty"`
 		Extra       *ExtraToSet           `json:"extra,omitempty"`
+		Xact        *XactLimPropsToSet    `json:"xact,omitempty"`
+		Lifecycle   *LifecycleConfToSet   `json:"lifecycle,omitempty"`
+		ObjLock     *ObjLockConfToSet     `json:"object_lock,omitempty"`
+		Replication *ReplicationConfToSet `json:"replication,omitempty"`
+		Encryption  *EncryptionConfToSet  `json:"encryption,omitempty"`
+		Audit       *AuditBckConfToSet    `json:"audit,omitempty"`
+		Quota       *QuotaConfToSet       `json:"quota,omitempty"`
 		Force       bool                  `json:"force,omitempty" copy:"skip" list:"omit"`
 	}
 
@@ -220,7 +382,7 @@ func (bp *Bprops) Validate(targetCnt int) error {
 
 	// run assorted props validators
 	var softErr error
-	for _, pv := range []PropsValidator{&bp.Cksum, &bp.Mirror, &bp.EC, &bp.Extra, &bp.WritePolicy} {
+	for _, pv := range []PropsValidator{&bp.Cksum, &bp.Mirror, &bp.EC, &bp.Extra, &bp.WritePolicy, &bp.Xact, &bp.Lifecycle, &bp.ObjLock, &bp.Replication, &bp.Encryption, &bp.Quota} {
 		var err error
 		switch {
 		case pv == &bp.EC:
@@ -240,6 +402,14 @@ func (bp *Bprops) Validate(targetCnt int) error {
 	if bp.Mirror.Enabled && bp.EC.Enabled {
 		nlog.Warningln("n-way mirroring and EC are both enabled at the same time on the same bucket")
 	}
+	if bp.Encryption.Enabled {
+		if bp.Provider != apc.AIS || bp.BackendBck.Name != "" {
+			return errors.New("server-side encryption at rest is supported only for ais buckets (with no backend)")
+		}
+		if bp.EC.Enabled {
+			return errors.New("server-side encryption at rest and erasure coding cannot be enabled at the same time")
+		}
+	}
 
 	// not inheriting cluster-scope features
 	names := bp.Features.Names()
@@ -265,6 +435,30 @@ func NewBpropsToSet(nvs cos.StrKVs) (props *BpropsToSet, err error) {
 	for key, val := range nvs {
 		name, value := strings.ToLower(key), val
 
+		// (JSON-formatted list of rules)
+		if name == PropLifecycleRules {
+			rules := []LifecycleRule{}
+			if err := jsoniter.Unmarshal(cos.UnsafeB(value), &rules); err != nil {
+				return props, fmt.Errorf("invalid %s value %q: %v", PropLifecycleRules, value, err)
+			}
+			if props.Lifecycle == nil {
+				props.Lifecycle = &LifecycleConfToSet{}
+			}
+			props.Lifecycle.Rules = &rules
+			continue
+		}
+		if name == PropReplicationBck {
+			bck, _, err := ParseBckObjectURI(value, ParseURIOpts{})
+			if err != nil {
+				return props, fmt.Errorf("invalid %s value %q: %v", PropReplicationBck, value, err)
+			}
+			if props.Replication == nil {
+				props.Replication = &ReplicationConfToSet{}
+			}
+			props.Replication.Bck = &bck
+			continue
+		}
+
 		// HACK: Some of the fields are present in `Bprops` and not in `BpropsToSet`.
 		// Thus, if user wants to change such field, `unknown field` will be returned.
 		// To make UX more friendly we attempt to set the value in an empty `Bprops` first.
@@ -279,11 +473,152 @@ func NewBpropsToSet(nvs cos.StrKVs) (props *BpropsToSet, err error) {
 	return
 }
 
+func (c *LifecycleConf) ValidateAsProps(...any) error {
+	for i := range c.Rules {
+		if err := c.Rules[i].validate(); err != nil {
+			return fmt.Errorf("invalid lifecycle rule #%d: %v", i, err)
+		}
+	}
+	if c.Enabled && len(c.Rules) == 0 {
+		return NewErrWarning("lifecycle is enabled but has no rules")
+	}
+	return nil
+}
+
+func (rule *LifecycleRule) validate() error {
+	if rule.ExpireDays < 0 || rule.EvictDays < 0 || rule.TransitionDays < 0 {
+		return errors.New("number of days cannot be negative")
+	}
+	if rule.ExpireDays == 0 && rule.EvictDays == 0 && rule.TransitionDays == 0 {
+		return errors.New("expecting at least one action (expiration, eviction, or transition)")
+	}
+	if rule.TransitionDays > 0 {
+		if rule.TransitionBck.IsEmpty() {
+			return errors.New("transition requires destination bucket")
+		}
+		if err := rule.TransitionBck.Validate(); err != nil {
+			return err
+		}
+	} else if !rule.TransitionBck.IsEmpty() {
+		return errors.New("destination bucket specified without transition_days")
+	}
+	return nil
+}
+
+// returns true if the rule applies to a given object name
+func (rule *LifecycleRule) Match(objName string) bool {
+	return rule.Prefix == "" || strings.HasPrefix(objName, rule.Prefix)
+}
+
+func (c *EncryptionConf) ValidateAsProps(...any) error {
+	if c.Enabled && c.KeyID == "" {
+		return errors.New("encryption.key_id must be set when encryption is enabled")
+	}
+	return nil
+}
+
+func (c *QuotaConf) ValidateAsProps(...any) error {
+	if c.Size < 0 || c.Objects < 0 {
+		return fmt.Errorf("invalid quota (size %d, objects %d): expecting non-negative values", c.Size, c.Objects)
+	}
+	return nil
+}
+
+func (c *QuotaConf) IsSet() bool { return c != nil && (c.Size > 0 || c.Objects > 0) }
+
+func (c *ObjLockConf) ValidateAsProps(...any) error {
+	switch c.Mode {
+	case "", apc.ObjLockGovernance, apc.ObjLockCompliance:
+	default:
+		return fmt.Errorf("invalid object_lock.mode %q (expecting %q, %q, or empty)",
+			c.Mode, apc.ObjLockGovernance, apc.ObjLockCompliance)
+	}
+	if c.Retention < 0 {
+		return fmt.Errorf("invalid object_lock.retention=%v (expecting non-negative)", c.Retention)
+	}
+	if c.Mode == "" && c.Retention > 0 {
+		return fmt.Errorf("object_lock.retention=%v requires object_lock.mode", c.Retention)
+	}
+	return nil
+}
+
+func (c *ReplicationConf) ValidateAsProps(...any) error {
+	switch c.Conflict {
+	case "", apc.ReplConflictOverwrite, apc.ReplConflictSkip:
+	default:
+		return fmt.Errorf("invalid replication.conflict %q (expecting %q, %q, or empty)",
+			c.Conflict, apc.ReplConflictOverwrite, apc.ReplConflictSkip)
+	}
+	switch c.Resolve {
+	case "", apc.ReplResolveLWW, apc.ReplResolveFWW:
+		if c.Prefer != "" {
+			return fmt.Errorf("replication.prefer requires replication.resolve=%q", apc.ReplResolvePrefer)
+		}
+	case apc.ReplResolvePrefer:
+		if c.Prefer == "" {
+			return fmt.Errorf("replication.resolve=%q requires preferred cluster UUID (replication.prefer)",
+				apc.ReplResolvePrefer)
+		}
+	default:
+		return fmt.Errorf("invalid replication.resolve %q (expecting %q, %q, %q, or empty)",
+			c.Resolve, apc.ReplResolveLWW, apc.ReplResolveFWW, apc.ReplResolvePrefer)
+	}
+	if !c.Enabled {
+		if c.ActiveActive {
+			return errors.New("replication.active_active requires replication.enabled")
+		}
+		return nil
+	}
+	if c.Bck.IsEmpty() {
+		return errors.New("replication is enabled but destination bucket is not specified")
+	}
+	if err := c.Bck.Validate(); err != nil {
+		return err
+	}
+	if !c.Bck.IsRemoteAIS() {
+		return fmt.Errorf("replication destination %s must be a bucket in a remote ais cluster", c.Bck.Cname(""))
+	}
+	return nil
+}
+
+func (c *XactLimProps) ValidateAsProps(...any) error {
+	if c.MaxConcurrent < 0 {
+		return fmt.Errorf("invalid xact.max_concurrent=%d (expecting non-negative integer)", c.MaxConcurrent)
+	}
+	if c.Bandwidth < 0 {
+		return fmt.Errorf("invalid xact.max_bandwidth=%d (expecting non-negative)", c.Bandwidth)
+	}
+	return nil
+}
+
 func (c *ExtraProps) ValidateAsProps(arg ...any) error {
 	provider, ok := arg[0].(string)
 	debug.Assert(ok)
-	if provider == apc.HT && c.HTTP.OrigURLBck == "" {
-		return errors.New("original bucket URL must be set for a bucket with HTTP provider")
+	switch provider {
+	case apc.GCP:
+		return c.GCP.validate()
+	case apc.HT:
+	default:
+		return nil
+	}
+	if c.HTTP.OrigURLBck == "" && c.HTTP.Manifest == "" {
+		return errors.New("original bucket URL or manifest must be set for a bucket with HTTP provider")
+	}
+	if c.HTTP.Manifest != "" && !cos.IsHT(c.HTTP.Manifest) && !cos.IsHTTPS(c.HTTP.Manifest) {
+		return fmt.Errorf("invalid HTTP bucket manifest %q (expecting http(s):// URL)", c.HTTP.Manifest)
+	}
+	return nil
+}
+
+func (c *ExtraPropsGCP) validate() error {
+	if c.KMSKey != "" && c.CSEKFile != "" {
+		return errors.New("GCP bucket cannot have both KMS key (CMEK) and customer-supplied encryption key (CSEK)")
+	}
+	if c.KMSKey != "" && (!strings.HasPrefix(c.KMSKey, "projects/") || !strings.Contains(c.KMSKey, "/cryptoKeys/")) {
+		return fmt.Errorf("invalid GCP KMS key name %q (expecting \"projects/.../cryptoKeys/<key>\")", c.KMSKey)
+	}
+	if c.CSEKFile != "" && !filepath.IsAbs(c.CSEKFile) {
+		return fmt.Errorf("invalid GCP CSEK file %q (expecting absolute path)", c.CSEKFile)
 	}
 	return nil
 }
@@ -327,6 +662,9 @@ func aggr(from, to *BsummResult) {
 	to.TotalSize.OnDisk += from.TotalSize.OnDisk
 	to.TotalSize.PresentObjs += from.TotalSize.PresentObjs
 	to.TotalSize.RemoteObjs += from.TotalSize.RemoteObjs
+	// (reported by a single target)
+	to.Quota.Size = max(from.Quota.Size, to.Quota.Size)
+	to.Quota.Objects = max(from.Quota.Objects, to.Quota.Objects)
 }
 
 func (s AllBsummResults) Finalize(dsize map[string]uint64, testingEnv bool) {
@@ -0,0 +1,220 @@
+// Package audit provides structured audit log of data- and control-plane operations:
+// who did what to which bucket/object, and when.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+)
+
+// Each node (proxy or target) records its own events:
+// - to a local file (FileName in the node's log directory) that gets rotated upon
+//   reaching configured size, or to syslog (local or remote) - see cmn.AuditConf;
+// - in addition, the most recent events are kept in memory to be returned via Tail.
+
+const (
+	FileName = "audit.log"
+
+	dfltMaxSize  = 64 * cos.MiB
+	dfltMaxFiles = 8
+
+	ringSize = 1024 // recent events (see Tail)
+
+	syslogTag = "aistore"
+)
+
+// *syslog.Writer (see syslog.go)
+type syslogWriter interface {
+	Info(m string) error
+	Warning(m string) error
+	Close() error
+}
+
+type (
+	Event struct {
+		Time    time.Time `json:"time"`
+		Node    string    `json:"node"`             // ID of the node that recorded the event
+		User    string    `json:"user,omitempty"`   // AuthN user (empty when AuthN is disabled or token is missing)
+		Client  string    `json:"client"`           // client's address
+		Method  string    `json:"method"`           // HTTP method
+		Path    string    `json:"path"`             // URL path
+		Action  string    `json:"action,omitempty"` // apc.ActMsg action, if any
+		Bucket  string    `json:"bucket,omitempty"` // bucket's cname, e.g. "s3://abc"
+		Object  string    `json:"object,omitempty"`
+		Status  int       `json:"status"`         // HTTP status
+		Size    int64     `json:"size,omitempty"` // response size
+		Latency int64     `json:"latency_ns"`
+	}
+
+	Logger struct {
+		dir  string
+		node string
+		// output
+		dest   string // current destination: empty (file) or syslog address (see cmn.AuditConf)
+		file   *os.File
+		size   int64
+		syslog syslogWriter
+		errAt  int64 // last logged error (mono)
+		// recent events
+		ring []Event
+		next int
+		full bool
+		mu   sync.Mutex
+	}
+)
+
+func New(dir, nodeID string) *Logger {
+	return &Logger{dir: dir, node: nodeID, ring: make([]Event, ringSize)}
+}
+
+func (ev *Event) Failed() bool { return ev.Status >= 400 }
+
+// Record formats and writes the event as per the current configuration
+func (l *Logger) Record(ev *Event, conf *cmn.AuditConf) {
+	ev.Node = l.node
+	var (
+		line []byte
+		err  error
+	)
+	if conf.Format == cmn.AuditFormatCEF {
+		line = ev.CEF()
+	} else {
+		line = cos.MustMarshal(ev)
+	}
+
+	l.mu.Lock()
+	l.ring[l.next] = *ev
+	l.next++
+	if l.next == len(l.ring) {
+		l.next, l.full = 0, true
+	}
+	if conf.Syslog != l.dest {
+		l._close()
+		l.dest = conf.Syslog
+	}
+	if l.dest == "" {
+		err = l.writeFile(line, conf)
+	} else {
+		err = l.writeSyslog(line, ev.Failed())
+	}
+	if err != nil {
+		l._close() // to retry next time
+		if now := time.Now().UnixNano(); now-l.errAt > int64(time.Minute) {
+			l.errAt = now
+			nlog.Errorln("audit:", err)
+		}
+	}
+	l.mu.Unlock()
+}
+
+// Tail returns up to `n` most recent events (oldest first), optionally filtered by bucket
+func (l *Logger) Tail(n int, bucket string) []Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	total := l.next
+	if l.full {
+		total = len(l.ring)
+	}
+	if n <= 0 || n > total {
+		n = total
+	}
+	events := make([]Event, 0, n)
+	for i := 1; i <= total && len(events) < n; i++ {
+		ev := &l.ring[(l.next-i+len(l.ring))%len(l.ring)]
+		if bucket == "" || ev.Bucket == bucket {
+			events = append(events, *ev)
+		}
+	}
+	// reverse (oldest first)
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	return events
+}
+
+func (l *Logger) Close() {
+	l.mu.Lock()
+	l._close()
+	l.mu.Unlock()
+}
+
+//
+// private
+//
+
+func (l *Logger) _close() {
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+	if l.syslog != nil {
+		l.syslog.Close()
+		l.syslog = nil
+	}
+}
+
+func (l *Logger) fqn() string { return filepath.Join(l.dir, FileName) }
+
+func (l *Logger) writeFile(line []byte, conf *cmn.AuditConf) error {
+	maxSize := int64(conf.MaxSize)
+	if maxSize == 0 {
+		maxSize = dfltMaxSize
+	}
+	if l.file != nil && l.size+int64(len(line))+1 > maxSize {
+		l._close()
+		l.rotate(conf.MaxFiles)
+	}
+	if l.file == nil {
+		fh, err := os.OpenFile(l.fqn(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, cos.PermRWR)
+		if err != nil {
+			return err
+		}
+		finfo, err := fh.Stat()
+		if err != nil {
+			fh.Close()
+			return err
+		}
+		l.file, l.size = fh, finfo.Size()
+	}
+	line = append(line, '\n')
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+// audit.log => audit.log.1 => ... => audit.log.<max-files> (removed)
+func (l *Logger) rotate(maxFiles int) {
+	if maxFiles == 0 {
+		maxFiles = dfltMaxFiles
+	}
+	fqn := l.fqn()
+	os.Remove(fqn + "." + strconv.Itoa(maxFiles))
+	for i := maxFiles - 1; i > 0; i-- {
+		os.Rename(fqn+"."+strconv.Itoa(i), fqn+"."+strconv.Itoa(i+1))
+	}
+	if err := os.Rename(fqn, fqn+".1"); err != nil && !os.IsNotExist(err) {
+		nlog.Errorln("audit: failed to rotate:", err)
+	}
+}
+
+func (l *Logger) writeSyslog(line []byte, failed bool) (err error) {
+	if l.syslog == nil {
+		if l.syslog, err = dialSyslog(l.dest); err != nil {
+			return err
+		}
+	}
+	if failed {
+		return l.syslog.Warning(string(line))
+	}
+	return l.syslog.Info(string(line))
+}
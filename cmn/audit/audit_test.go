@@ -0,0 +1,150 @@
+// Package audit_test: unit tests
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package audit_test
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/audit"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/tools/tassert"
+	jsoniter "github.com/json-iterator/go"
+)
+
+func newEvent(i int) *audit.Event {
+	return &audit.Event{
+		Time:    time.Now(),
+		User:    "user-" + strconv.Itoa(i%3),
+		Client:  "10.0.0.1:53412",
+		Method:  "GET",
+		Path:    "/v1/objects/abc/obj-" + strconv.Itoa(i),
+		Bucket:  "ais://abc",
+		Object:  "obj-" + strconv.Itoa(i),
+		Status:  200,
+		Size:    1024,
+		Latency: int64(time.Millisecond),
+	}
+}
+
+func readLines(t *testing.T, fqn string) (lines []string) {
+	fh, err := os.Open(fqn)
+	tassert.CheckFatal(t, err)
+	defer fh.Close()
+	scanner := bufio.NewScanner(fh)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	tassert.CheckFatal(t, scanner.Err())
+	return lines
+}
+
+func TestJSON(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		l    = audit.New(dir, "t1")
+		conf = &cmn.AuditConf{Enabled: true, Format: cmn.AuditFormatJSON}
+	)
+	defer l.Close()
+	for i := range 10 {
+		l.Record(newEvent(i), conf)
+	}
+	lines := readLines(t, filepath.Join(dir, audit.FileName))
+	tassert.Fatalf(t, len(lines) == 10, "expected 10 lines, got %d", len(lines))
+	for i, line := range lines {
+		var ev audit.Event
+		tassert.CheckFatal(t, jsoniter.Unmarshal([]byte(line), &ev))
+		tassert.Errorf(t, ev.Node == "t1", "expected node t1, got %q", ev.Node)
+		tassert.Errorf(t, ev.Object == "obj-"+strconv.Itoa(i), "expected obj-%d, got %q", i, ev.Object)
+	}
+}
+
+func TestCEF(t *testing.T) {
+	ev := newEvent(0)
+	ev.Node = "p1"
+	ev.Method, ev.Action = "POST", "rename|obj"
+	ev.Object = "a=b\nc"
+	ev.Status = 403
+
+	cef := string(ev.CEF())
+	tassert.Fatalf(t, strings.HasPrefix(cef, "CEF:0|NVIDIA|AIStore|"+cmn.VersionAIStore+"|rename\\|obj|rename\\|obj|7|"),
+		"unexpected CEF header: %q", cef)
+	for _, s := range []string{"dvchost=p1", "suser=user-0", "src=10.0.0.1 ", "requestMethod=POST",
+		"cs1=ais://abc", `cs2=a\=b\nc`, "cn1=403", "outcome=failure"} {
+		tassert.Errorf(t, strings.Contains(cef, s), "expected %q in %q", s, cef)
+	}
+	tassert.Errorf(t, !strings.Contains(cef, "\n"), "CEF must be a single line: %q", cef)
+
+	ev = &audit.Event{Method: "DELETE", Path: "/v1/buckets/abc", Status: 200}
+	tassert.Errorf(t, ev.Op() == "DELETE /buckets", "unexpected op %q", ev.Op())
+	ev = &audit.Event{Method: "PUT", Path: "/s3/abc/obj", Status: 200}
+	tassert.Errorf(t, ev.Op() == "PUT /s3", "unexpected op %q", ev.Op())
+}
+
+func TestRotate(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		l    = audit.New(dir, "t1")
+		conf = &cmn.AuditConf{Enabled: true, MaxSize: cos.SizeIEC(cos.KiB), MaxFiles: 2}
+		fqn  = filepath.Join(dir, audit.FileName)
+	)
+	defer l.Close()
+	for i := range 100 {
+		l.Record(newEvent(i), conf)
+	}
+	for _, name := range []string{fqn, fqn + ".1", fqn + ".2"} {
+		finfo, err := os.Stat(name)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, finfo.Size() <= cos.KiB, "%s: size %d exceeds max", name, finfo.Size())
+	}
+	_, err := os.Stat(fqn + ".3")
+	tassert.Errorf(t, os.IsNotExist(err), "expected %s.3 to not exist (max files 2)", fqn)
+
+	// the most recent event is last in the current file
+	lines := readLines(t, fqn)
+	tassert.Fatalf(t, len(lines) > 0, "empty %s", fqn)
+	var ev audit.Event
+	tassert.CheckFatal(t, jsoniter.Unmarshal([]byte(lines[len(lines)-1]), &ev))
+	tassert.Errorf(t, ev.Object == "obj-99", "expected obj-99, got %q", ev.Object)
+}
+
+func TestTail(t *testing.T) {
+	var (
+		l    = audit.New(t.TempDir(), "t1")
+		conf = &cmn.AuditConf{Enabled: true}
+	)
+	defer l.Close()
+	tassert.Errorf(t, len(l.Tail(10, "")) == 0, "expected no events")
+
+	const num = 3000 // wraps around
+	for i := range num {
+		ev := newEvent(i)
+		if i%2 == 0 {
+			ev.Bucket = "ais://even"
+		}
+		l.Record(ev, conf)
+	}
+	events := l.Tail(5, "")
+	tassert.Fatalf(t, len(events) == 5, "expected 5 events, got %d", len(events))
+	for i := range events {
+		expected := "obj-" + strconv.Itoa(num-5+i)
+		tassert.Errorf(t, events[i].Object == expected, "expected %s, got %s", expected, events[i].Object)
+	}
+	events = l.Tail(0, "")
+	tassert.Errorf(t, len(events) > 0 && len(events) < num, "expected bounded number of events, got %d", len(events))
+
+	events = l.Tail(4, "ais://even")
+	tassert.Fatalf(t, len(events) == 4, "expected 4 events, got %d", len(events))
+	for _, ev := range events {
+		tassert.Errorf(t, ev.Bucket == "ais://even", "unexpected bucket %s", ev.Bucket)
+	}
+	tassert.Errorf(t, events[3].Object == "obj-"+strconv.Itoa(num-2), "unexpected last event %s", events[3].Object)
+}
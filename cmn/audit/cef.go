@@ -0,0 +1,124 @@
+// Package audit provides structured audit log of data- and control-plane operations:
+// who did what to which bucket/object, and when.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package audit
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// ArcSight Common Event Format (CEF):
+// CEF:Version|Device Vendor|Device Product|Device Version|Signature ID|Name|Severity|Extension
+
+const (
+	cefVendor  = "NVIDIA"
+	cefProduct = "AIStore"
+)
+
+var (
+	cefHdrEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	cefExtEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+)
+
+// signature (and name) of the event: action, if specified, or method and resource, e.g. "GET /objects"
+func (ev *Event) Op() string {
+	if ev.Action != "" {
+		return ev.Action
+	}
+	resource := strings.TrimPrefix(ev.Path, "/")
+	if i := strings.IndexByte(resource, '/'); i >= 0 {
+		if strings.HasPrefix(resource, "v1/") {
+			resource = resource[i+1:]
+			if j := strings.IndexByte(resource, '/'); j >= 0 {
+				resource = resource[:j]
+			}
+		} else {
+			resource = resource[:i]
+		}
+	}
+	return ev.Method + " /" + resource
+}
+
+func (ev *Event) severity() int {
+	switch {
+	case ev.Status == 401 || ev.Status == 403:
+		return 7
+	case ev.Status >= 500:
+		return 5
+	case ev.Status >= 400:
+		return 4
+	default:
+		return 3
+	}
+}
+
+func (ev *Event) CEF() []byte {
+	var (
+		sb strings.Builder
+		op = cefHdrEscaper.Replace(ev.Op())
+	)
+	sb.Grow(256 + len(ev.Path) + len(ev.Object))
+	sb.WriteString("CEF:0|")
+	sb.WriteString(cefVendor)
+	sb.WriteByte('|')
+	sb.WriteString(cefProduct)
+	sb.WriteByte('|')
+	sb.WriteString(cmn.VersionAIStore)
+	sb.WriteByte('|')
+	sb.WriteString(op)
+	sb.WriteByte('|')
+	sb.WriteString(op)
+	sb.WriteByte('|')
+	sb.WriteString(strconv.Itoa(ev.severity()))
+	sb.WriteByte('|')
+
+	sb.WriteString("rt=")
+	sb.WriteString(strconv.FormatInt(ev.Time.UnixMilli(), 10))
+	ext(&sb, "dvchost", ev.Node)
+	ext(&sb, "suser", ev.User)
+	src := ev.Client
+	if host, _, err := net.SplitHostPort(src); err == nil {
+		src = host
+	}
+	ext(&sb, "src", src)
+	ext(&sb, "requestMethod", ev.Method)
+	ext(&sb, "request", ev.Path)
+	ext(&sb, "act", ev.Action)
+	if ev.Bucket != "" {
+		ext(&sb, "cs1Label", "bucket")
+		ext(&sb, "cs1", ev.Bucket)
+	}
+	if ev.Object != "" {
+		ext(&sb, "cs2Label", "object")
+		ext(&sb, "cs2", ev.Object)
+	}
+	ext(&sb, "cn1Label", "status")
+	ext(&sb, "cn1", strconv.Itoa(ev.Status))
+	ext(&sb, "cn2Label", "latencyMs")
+	ext(&sb, "cn2", strconv.FormatInt(ev.Latency/1e6, 10))
+	if ev.Size > 0 {
+		ext(&sb, "out", strconv.FormatInt(ev.Size, 10))
+	}
+	if ev.Failed() {
+		ext(&sb, "outcome", "failure")
+	} else {
+		ext(&sb, "outcome", "success")
+	}
+	return []byte(sb.String())
+}
+
+func ext(sb *strings.Builder, key, value string) {
+	if value == "" {
+		return
+	}
+	sb.WriteByte(' ')
+	sb.WriteString(key)
+	sb.WriteByte('=')
+	sb.WriteString(cefExtEscaper.Replace(value))
+}
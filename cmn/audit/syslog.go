@@ -0,0 +1,35 @@
+// Package audit provides structured audit log of data- and control-plane operations:
+// who did what to which bucket/object, and when.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"log/syslog"
+	"net/url"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// dest: "local" (local syslog daemon) or URL, e.g. "udp://syslog.example.com:514"
+func dialSyslog(dest string) (syslogWriter, error) {
+	const prio = syslog.LOG_AUTH | syslog.LOG_INFO
+	if dest == cmn.AuditSyslogLocal {
+		return syslog.New(prio, syslogTag)
+	}
+	u, err := url.Parse(dest)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, errors.New("invalid syslog address " + dest)
+	}
+	w, err := syslog.Dial(u.Scheme, u.Host, prio, syslogTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog %s: %w", dest, err)
+	}
+	return w, nil
+}
@@ -25,6 +25,12 @@ type backendFuncs struct {
 // not it is depends on how the object was created and how it is encrypted..."
 const AwsMultipartDelim = "-"
 
+// Backblaze B2 SHA1 (see BackendHelpers.B2)
+const (
+	b2Unverified = "unverified:"
+	b2NoCksum    = "none"
+)
+
 func isS3MultipartEtag(etag string) bool {
 	return strings.Contains(etag, AwsMultipartDelim)
 }
@@ -42,6 +48,8 @@ var BackendHelpers = struct {
 	Azure  backendFuncs
 	Google backendFuncs
 	OCI    backendFuncs
+	B2     backendFuncs
+	WebDAV backendFuncs
 	HTTP   backendFuncs
 }{
 	Amazon: backendFuncs{
@@ -176,6 +184,42 @@ var BackendHelpers = struct {
 			}
 		},
 	},
+	B2: backendFuncs{
+		// file ID (changes with every upload)
+		EncodeVersion: func(v any) (string, bool) {
+			switch x := v.(type) {
+			case string:
+				return x, x != ""
+			default:
+				debug.FailTypeCast(v)
+				return "", false
+			}
+		},
+		// SHA1 (hex): "none" for large files, "unverified:" prefix when uploaded with trailing SHA1
+		EncodeCksum: func(v any) (string, bool) {
+			switch x := v.(type) {
+			case string:
+				x = strings.TrimPrefix(x, b2Unverified)
+				return x, x != "" && x != b2NoCksum
+			default:
+				debug.FailTypeCast(v)
+				return "", false
+			}
+		},
+	},
+	WebDAV: backendFuncs{
+		// getetag (PROPFIND) or ETag header: quoted, possibly weak
+		EncodeETag: func(v any) (string, bool) {
+			switch x := v.(type) {
+			case string:
+				x = UnquoteCEV(strings.TrimPrefix(x, "W/"))
+				return x, x != ""
+			default:
+				debug.FailTypeCast(v)
+				return "", false
+			}
+		},
+	},
 	HTTP: backendFuncs{
 		EncodeETag: func(v any) (string, bool) {
 			switch x := v.(type) {
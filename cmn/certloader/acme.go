@@ -0,0 +1,178 @@
+// Package certloader loads and reloads X.509 certs.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package certloader
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/hk"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// ACME (RFC 8555) mode, e.g. Let's Encrypt - in place of the (certificate, key) files:
+// - obtains the certificate for the configured domain(s) and keeps renewing it
+//   before it expires (see ACMEOpts.RenewBefore);
+// - proves control over the domain via TLS-ALPN-01 (RFC 8737), on the same HTTPS port
+//   that, therefore, must be reachable as port 443 of the domain;
+// - the account key and the certificates are cached in the (persistent) cache directory
+//   to survive restarts without re-issuing (and hitting the CA's rate limits);
+// - until the certificate is obtained (first start), TLS handshakes obtain it on demand
+// the installed certificate (the first domain's) is then handled the same way as the one
+// loaded from file: stats, node alerts, Props(), and key policy (see KeyPolicy)
+
+const (
+	dfltACMERenewBefore = 30 * 24 * time.Hour // (autocert default)
+	acmePendingInterval = time.Minute         // hk: retry obtaining
+)
+
+type (
+	ACMEOpts struct {
+		Domains     []string      // the first is the default (clients that don't send SNI)
+		Directory   string        // ACME directory URL; empty - Let's Encrypt (autocert.DefaultACMEDirectory)
+		Email       string        // (optional) contact
+		CacheDir    string        // account key and certificates
+		RenewBefore time.Duration // zero - dfltACMERenewBefore
+	}
+	acmeMgr struct {
+		mgr     *autocert.Manager
+		domains []string
+	}
+)
+
+// (htrun only) in place of Init
+func InitACME(opts *ACMEOpts, policy *KeyPolicy, tstats cos.StatsUpdater) error {
+	am, err := newACME(opts)
+	if err != nil {
+		nlog.Errorln("FATAL:", err)
+		return err
+	}
+
+	debug.Assert(gcl == nil)
+	gcl = &certLoader{certFile: "acme:" + am.domains[0], policy: policy, tstats: tstats, acme: am}
+
+	// obtaining may need the (TLS-ALPN-01) challenge served - i.e., the server listening
+	gcl.doAsync()
+	gcl.startWatch()
+	hk.Reg(name, gcl.hk, gcl.hktime())
+	return nil
+}
+
+func newACME(opts *ACMEOpts) (*acmeMgr, error) {
+	domains := make([]string, 0, len(opts.Domains))
+	for _, domain := range opts.Domains {
+		if domain = strings.TrimSpace(domain); domain == "" {
+			continue
+		}
+		if !strings.Contains(strings.Trim(domain, "."), ".") {
+			return nil, fmt.Errorf("%s: invalid ACME domain %q", name, domain)
+		}
+		domains = append(domains, domain)
+	}
+	if len(domains) == 0 {
+		return nil, errors.New(name + ": ACME requires at least one domain")
+	}
+	if opts.CacheDir == "" {
+		return nil, errors.New(name + ": ACME requires cache directory")
+	}
+	if opts.Directory != "" {
+		if _, err := url.ParseRequestURI(opts.Directory); err != nil {
+			return nil, fmt.Errorf("%s: invalid ACME directory %q: %w", name, opts.Directory, err)
+		}
+	}
+	am := &acmeMgr{
+		mgr: &autocert.Manager{
+			Prompt:      autocert.AcceptTOS,
+			Cache:       autocert.DirCache(opts.CacheDir),
+			HostPolicy:  autocert.HostWhitelist(domains...),
+			RenewBefore: cos.NonZero(opts.RenewBefore, dfltACMERenewBefore),
+			Email:       opts.Email,
+		},
+		domains: domains,
+	}
+	if opts.Directory != "" {
+		am.mgr.Client = &acme.Client{DirectoryURL: opts.Directory}
+	}
+	return am, nil
+}
+
+func (am *acmeMgr) String() string {
+	dir := autocert.DefaultACMEDirectory
+	if am.mgr.Client != nil {
+		dir = am.mgr.Client.DirectoryURL
+	}
+	return strings.Join(am.domains, ",") + " via " + dir
+}
+
+// tls.Config.NextProtos: TLS-ALPN-01 challenge
+func (*acmeMgr) nextProtos() []string { return []string{"http/1.1", acme.ALPNProto} }
+
+func (*acmeMgr) challenge(hello *tls.ClientHelloInfo) bool {
+	return slices.Contains(hello.SupportedProtos, acme.ALPNProto)
+}
+
+// get (from cache) or obtain (from the CA), and install when changed
+// (autocert keeps renewing in the background, hk picks up the renewed one)
+func (am *acmeMgr) do(cl *certLoader, compare bool) (err error) {
+	defer func() {
+		if err != nil {
+			cl.failed.Store(time.Now().UnixNano())
+			cl.tstats.Inc(cos.ErrCertReloadCount)
+		}
+	}()
+	started := mono.NanoTime()
+	cert, err := am.mgr.GetCertificate(am.hello(am.domains[0]))
+	if err != nil {
+		return fmt.Errorf("%s: failed to obtain ACME certificate for %q, err: %w", name, am.domains[0], err)
+	}
+	if compare {
+		if prev := cl.xcert.Load(); prev != nil && bytes.Equal(prev.Certificate.Certificate[0], cert.Certificate[0]) {
+			return nil
+		}
+	}
+	return cl.install(&xcert{Certificate: *cert, parent: cl}, nil, started)
+}
+
+// ECDSA-capable hello: autocert otherwise obtains RSA
+func (*acmeMgr) hello(domain string) *tls.ClientHelloInfo {
+	return &tls.ClientHelloInfo{
+		ServerName:       domain,
+		CipherSuites:     []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+		SupportedCurves:  []tls.CurveID{tls.CurveP256},
+		SignatureSchemes: []tls.SignatureScheme{tls.ECDSAWithP256AndSHA256},
+	}
+}
+
+// tls.Config.GetCertificate: the installed certificate unless
+// - TLS-ALPN-01 challenge, or
+// - another configured domain (SNI), or
+// - none installed yet (obtain on demand)
+func (am *acmeMgr) getCert(cl *certLoader, hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	var (
+		challenge = am.challenge(hello)
+		other     = hello.ServerName != "" && hello.ServerName != am.domains[0] && slices.Contains(am.domains, hello.ServerName)
+	)
+	if !challenge && !other && cl.xcert.Load() != nil {
+		return cl._get(), nil
+	}
+	if hello.ServerName == "" {
+		h := *hello
+		h.ServerName = am.domains[0]
+		hello = &h
+	}
+	return am.mgr.GetCertificate(hello)
+}
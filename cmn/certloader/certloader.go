@@ -5,17 +5,25 @@
 package certloader
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/mono"
 	"github.com/NVIDIA/aistore/cmn/nlog"
 	"github.com/NVIDIA/aistore/hk"
 )
@@ -33,6 +41,34 @@ const (
 
 const fmtErrExpired = "%s: %s expired (valid until %v)"
 
+var errNotLoading = errors.New(name + ": not loading X.509 certificates (HTTPS disabled or not configured)")
+
+// when the generation file is configured (see Init), check it at least this often
+const genCheckInterval = 30 * time.Second
+
+// in addition to polling (hk), watch the files (see watcher) to reload within seconds upon change,
+// e.g., renewed by cert-manager; debounced, to let the (certificate, key) pair get written in full
+const watchDelay = 500 * time.Millisecond
+
+// transient filesystem errors (see isTransient) are retried with (doubling) backoff;
+// meanwhile, the previously loaded certificate continues to serve
+const numRetries = 3
+
+var (
+	retrySleep = 100 * time.Millisecond // initial backoff
+
+	// (unit tests)
+	fstat       = os.Stat
+	loadKeyPair = tls.LoadX509KeyPair
+)
+
+// public key types (see KeyPolicy)
+const (
+	KeyTypeRSA     = "rsa"
+	KeyTypeECDSA   = "ecdsa"
+	KeyTypeEd25519 = "ed25519"
+)
+
 type (
 	xcert struct {
 		tls.Certificate
@@ -40,13 +76,48 @@ type (
 		modTime   time.Time
 		notBefore time.Time
 		notAfter  time.Time
+		gen       string // generation (see Init) at the time of loading
+		keyType   string // KeyTypeRSA, ...
+		curve     string // ECDSA only
 		size      int64
+		keyBits   int
 	}
 	certLoader struct {
 		tstats   cos.StatsUpdater
+		policy   *KeyPolicy
 		certFile string
 		keyFile  string
+		genFile  string // (optional)
 		xcert    atomic.Pointer[xcert]
+		loaded   atomic.Int64  // last successful (re)load (Unix nanoseconds)
+		latency  atomic.Int64  // time it took (reading and parsing), nanoseconds
+		failed   atomic.Int64  // last failed attempt (Unix nanoseconds)
+		cas      []*caPool     // CA pools (see tlsconf.go)
+		crls     []*crlFile    // client CRLs (see revoke.go)
+		staple   *stapler      // OCSP stapling (see StapleOCSP)
+		ocsp     ocspCache     // client certificates' OCSP statuses
+		acme     *acmeMgr      // ACME mode (see InitACME) in place of (certFile, keyFile)
+		vault    *vaultMgr     // ditto, Vault PKI (see InitVault)
+		spiffe   *spiffeMgr    // ditto, SPIFFE Workload API (see InitSPIFFE)
+		busy     atomic.Bool   // (see doAsync)
+		snis     []*certLoader // additional certificates selected by server name (see AddSNI)
+		watcher  *watcher      // (see watchDelay)
+		mu       sync.Mutex    // (see reload)
+		sni      bool          // this one is
+	}
+
+	// leaf certificate's public key policy (see Init); nil - no restrictions
+	KeyPolicy struct {
+		Types      []string // allowed key types (KeyTypeRSA, etc.); empty - any
+		Curves     []string // allowed ECDSA curves (e.g., "P-256"); empty - any
+		MinRSABits int      // minimum RSA key size; zero - any
+	}
+
+	// (see LoadStats)
+	Stats struct {
+		Loaded  time.Time     // last successful (re)load
+		Latency time.Duration // the time it took to read and parse
+		Failed  time.Time     // last failed attempt, zero if none
 	}
 
 	// tls.Config.GetCertificate
@@ -65,26 +136,33 @@ var (
 )
 
 // (htrun only)
-func Init(certFile, keyFile string, tstats cos.StatsUpdater) (err error) {
+// optional `genFile` contains generation (e.g., a counter) that an external agent updates upon rotating
+// the certificate; a changed generation triggers reloading regardless of the certificate's modification time
+// (that remains the fallback)
+func Init(certFile, keyFile, genFile string, policy *KeyPolicy, tstats cos.StatsUpdater) (err error) {
 	if certFile == "" && keyFile == "" {
 		return nil
 	}
 
 	debug.Assert(gcl == nil)
-	gcl = &certLoader{certFile: certFile, keyFile: keyFile, tstats: tstats}
+	gcl = &certLoader{certFile: certFile, keyFile: keyFile, genFile: genFile, policy: policy, tstats: tstats}
 	if err = Load(); err != nil {
 		nlog.Errorln("FATAL:", err)
 		return err
 	}
 
+	gcl.startWatch()
 	hk.Reg(name, gcl.hk, gcl.hktime())
 	return nil
 }
 
-// via (Init, API call)
+// via (Init, API call); unconditionally, CA bundles (see TLSOpts) included
 func Load() (err error) {
+	if gcl == nil {
+		return errNotLoading
+	}
 	if err = gcl.do(false /*compare*/); err == nil {
-		return nil
+		return gcl.reloadCAs(false /*compare*/)
 	}
 	if isExpired(err) {
 		gcl.tstats.SetFlag(cos.NodeAlerts, cos.CertificateExpired)
@@ -95,6 +173,9 @@ func Load() (err error) {
 }
 
 func Props() (out cos.StrKVs) {
+	if gcl == nil {
+		return cos.StrKVs{"error": errNotLoading.Error()}
+	}
 	flags := cos.NodeStateFlags(gcl.tstats.Get(cos.NodeAlerts))
 	if flags.IsAnySet(cos.CertificateInvalid | cos.CertificateExpired) {
 		out = make(cos.StrKVs, 1)
@@ -103,14 +184,40 @@ func Props() (out cos.StrKVs) {
 		return out
 	}
 	xcert := gcl.xcert.Load()
+	if xcert == nil {
+		debug.Assert(gcl.acme != nil)
+		return cos.StrKVs{"acme": gcl.acme.String() + " (pending)"}
+	}
 
-	out = make(cos.StrKVs, 6)
+	out = make(cos.StrKVs, 16)
+	if gcl.acme != nil {
+		out["acme"] = gcl.acme.String()
+	}
+	if gcl.vault != nil {
+		out["vault"] = gcl.vault.String()
+	}
+	if gcl.spiffe != nil {
+		out["spiffe"] = gcl.spiffe.String()
+		if len(xcert.Certificate.Leaf.URIs) > 0 {
+			out["spiffe-id"] = xcert.Certificate.Leaf.URIs[0].String()
+		}
+	}
 	leaf := xcert.Certificate.Leaf
 	{
 		out["version"] = strconv.Itoa(leaf.Version)
+		out["subject"] = leaf.Subject.String()
+		if sans := subjectAltNames(leaf); sans != "" {
+			out["subject-alt-names"] = sans
+		}
+		out["fingerprint (SHA-256)"] = fingerprint(leaf)
 		out["issued-by (CN)"] = leaf.Issuer.CommonName
 		out["signature-algorithm"] = leaf.SignatureAlgorithm.String()
 		out["public-key-algorithm"] = leaf.PublicKeyAlgorithm.String()
+		out["key-type"] = xcert.keyType
+		if xcert.curve != "" {
+			out["key-type"] += " " + xcert.curve
+		}
+		out["key-size"] = strconv.Itoa(xcert.keyBits)
 		if leaf.SerialNumber != nil {
 			out["serial-number"] = leaf.SerialNumber.String()
 		}
@@ -121,26 +228,132 @@ func Props() (out cos.StrKVs) {
 			out["warning"] = cos.CertWillSoonExpire.String()
 		}
 	}
+	if len(gcl.snis) > 0 {
+		out["sni"] = gcl.sniProps()
+	}
+	if cas := gcl.caProps(); cas != "" {
+		out["ca"] = cas
+	}
+	ls := LoadStats()
+	out["loaded"] = fmtTime(ls.Loaded) + " (" + ls.Latency.String() + ")"
+	if !ls.Failed.IsZero() {
+		out["load-failed"] = fmtTime(ls.Failed)
+	}
 
 	return out
 }
 
+// DNS names, IP addresses, URIs, and emails
+func subjectAltNames(leaf *x509.Certificate) string {
+	sans := make([]string, 0, len(leaf.DNSNames)+len(leaf.IPAddresses)+len(leaf.URIs)+len(leaf.EmailAddresses))
+	sans = append(sans, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, u := range leaf.URIs {
+		sans = append(sans, u.String())
+	}
+	sans = append(sans, leaf.EmailAddresses...)
+	return strings.Join(sans, ",")
+}
+
+// e.g. "5E:0F:..." (same as `openssl x509 -fingerprint -sha256`)
+func fingerprint(leaf *x509.Certificate) string {
+	sum := sha256.Sum256(leaf.Raw)
+	var sb strings.Builder
+	sb.Grow(len(sum) * 3)
+	for i, b := range sum {
+		if i > 0 {
+			sb.WriteByte(':')
+		}
+		sb.WriteString(strconv.FormatUint(uint64(b)>>4, 16))
+		sb.WriteString(strconv.FormatUint(uint64(b)&0xf, 16))
+	}
+	return strings.ToUpper(sb.String())
+}
+
+func LoadStats() Stats {
+	debug.Assert(gcl != nil, name, " not initialized")
+	return gcl.stats()
+}
+
 //
 // private methods
 //
 
 func (cl *certLoader) hk(int64) time.Duration {
-	if err := cl.do(true /*compare*/); err != nil {
-		nlog.Errorln(err)
+	if cl.acme != nil || cl.vault != nil {
+		cl.doAsync()
+	}
+	cl.reload()
+	if cl.staple != nil && cl.staple.due() {
+		cl.staple.refresh(cl)
 	}
 	return cl.hktime()
 }
 
+// reload files that have changed: polling (hk) and upon change (watcher)
+func (cl *certLoader) reload() {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if cl.acme == nil && cl.vault == nil {
+		if err := cl.do(true /*compare*/); err != nil {
+			nlog.Errorln(err)
+		}
+	}
+	cl.reloadSNIs()
+	cl.reloadCAs(true /*compare*/)
+	cl.reloadCRLs()
+}
+
+func (cl *certLoader) startWatch() {
+	w, err := newWatcher(cl.reload)
+	if err != nil {
+		nlog.Warningln(name+": not watching (polling only):", err)
+		return
+	}
+	cl.watcher = w
+	if cl.acme != nil || cl.vault != nil || cl.spiffe != nil {
+		return // (CA and CRL files only)
+	}
+	for _, file := range []string{cl.certFile, cl.keyFile, cl.genFile} {
+		if file != "" {
+			w.add(file)
+		}
+	}
+}
+
+// (hk) obtaining over the network may take a while and must not block housekeeping
+func (cl *certLoader) doAsync() {
+	if !cl.busy.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		if err := cl.do(true /*compare*/); err != nil {
+			nlog.Errorln(err)
+		}
+		cl.busy.Store(false)
+	}()
+}
+
 func (cl *certLoader) hktime() (d time.Duration) {
+	if cl.genFile != "" {
+		defer func() { d = min(d, genCheckInterval) }()
+	}
+	if cl.vault != nil {
+		defer func() { d = min(d, cl.vault.next(cl.xcert.Load())) }()
+	}
+	if cl.staple != nil {
+		defer func() { d = min(d, cl.staple.until()) }()
+	}
 	flags := cos.NodeStateFlags(cl.tstats.Get(cos.NodeAlerts))
 	if flags.IsAnySet(cos.CertificateExpired | cos.CertificateInvalid) {
 		return dfltTimeInvalid
 	}
+	if cl.xcert.Load() == nil {
+		debug.Assert(cl.acme != nil)
+		return acmePendingInterval
+	}
 
 	// (still) valid
 	const warn = "X.509 will soon expire - remains:"
@@ -182,9 +395,24 @@ func (cl *certLoader) errorf() error {
 	}
 }
 
-func (cl *certLoader) _get() *tls.Certificate { return &cl.xcert.Load().Certificate }
+func (cl *certLoader) _get() *tls.Certificate {
+	if xcert := cl.xcert.Load(); xcert != nil {
+		return &xcert.Certificate
+	}
+	return &tls.Certificate{} // (ACME pending)
+}
 
-func (cl *certLoader) _hello(*tls.ClientHelloInfo) (*tls.Certificate, error) { return cl._get(), nil }
+func (cl *certLoader) _hello(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if cl.acme != nil {
+		return cl.acme.getCert(cl, hello)
+	}
+	if hello != nil && hello.ServerName != "" {
+		if cert := cl.sniCert(hello.ServerName); cert != nil {
+			return cert, nil
+		}
+	}
+	return cl._get(), nil
+}
 
 func GetCert() (GetCertCB, error) {
 	debug.Assert(gcl != nil, name, " not initialized")
@@ -206,39 +434,99 @@ func GetClientCert() (GetClientCertCB, error) {
 	return gcl._info, nil
 }
 
+func (cl *certLoader) stats() Stats {
+	ls := Stats{Latency: time.Duration(cl.latency.Load())}
+	if n := cl.loaded.Load(); n != 0 {
+		ls.Loaded = time.Unix(0, n)
+	}
+	if n := cl.failed.Load(); n != 0 {
+		ls.Failed = time.Unix(0, n)
+	}
+	return ls
+}
+
 func (cl *certLoader) do(compare bool) (err error) {
+	switch {
+	case cl.acme != nil:
+		return cl.acme.do(cl, compare)
+	case cl.vault != nil:
+		return cl.vault.do(cl, compare)
+	case cl.spiffe != nil:
+		return nil // (pushed by the agent)
+	}
 	var (
 		finfo os.FileInfo
 		xcert = xcert{parent: cl}
 	)
+	defer func() {
+		if err != nil {
+			cl.failed.Store(time.Now().UnixNano())
+			cl.tstats.Inc(cos.ErrCertReloadCount)
+		}
+	}()
+
 	// 1. fstat
-	finfo, err = os.Stat(cl.certFile)
+	err = retry(func() (err error) {
+		finfo, err = fstat(cl.certFile)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("%s: failed to fstat %q, err: %w", name, cl.certFile, err)
 	}
 
-	// 2. updated?
+	// 2. updated? (generation changed, or else the certificate file)
+	xcert.gen = cl.readGen()
 	if compare {
-		xcert := cl.xcert.Load()
-		debug.Assert(xcert != nil, "expecting X.509 loaded at startup: ", cl.certFile, ", ", cl.keyFile)
-		if finfo.ModTime() == xcert.modTime && finfo.Size() == xcert.size {
+		prev := cl.xcert.Load()
+		debug.Assert(prev != nil, "expecting X.509 loaded at startup: ", cl.certFile, ", ", cl.keyFile)
+		if (xcert.gen == "" || xcert.gen == prev.gen) && finfo.ModTime() == prev.modTime && finfo.Size() == prev.size {
 			return nil
 		}
 	}
 
 	// 3. read and parse
-	xcert.Certificate, err = tls.LoadX509KeyPair(cl.certFile, cl.keyFile)
+	started := mono.NanoTime()
+	err = retry(func() (err error) {
+		xcert.Certificate, err = loadKeyPair(cl.certFile, cl.keyFile)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("%s: failed to load (%s, %s), err: %w", name, cl.certFile, cl.keyFile, err)
 	}
+	return cl.install(&xcert, finfo, started)
+}
+
+// validate (see KeyPolicy) and install newly loaded certificate
+func (cl *certLoader) install(xcert *xcert, finfo os.FileInfo, started int64) error {
 	rem, err := xcert.ini(finfo)
 	if err != nil {
 		return err
 	}
+	if cl.policy != nil {
+		if err := cl.policy.check(xcert); err != nil {
+			return fmt.Errorf("%s: %q violates key policy: %w", name, cl.certFile, err)
+		}
+	}
+	latency := mono.SinceNano(started)
 
 	// 4. ok
-	cl.tstats.ClrFlag(cos.NodeAlerts, cos.CertificateExpired|cos.CertificateInvalid|cos.CertWillSoonExpire)
-	cl.xcert.Store(&xcert)
+	cl.loaded.Store(time.Now().UnixNano())
+	cl.latency.Store(latency)
+	cl.tstats.AddWith(
+		cos.NamedVal64{Name: cos.CertReloadCount, Value: 1},
+		cos.NamedVal64{Name: cos.CertReloadLatency, Value: latency},
+	)
+	if cl.sni {
+		cl.xcert.Store(xcert) // (node alerts reflect the primary)
+		nlog.Infoln("SNI:", xcert.String())
+		return nil
+	}
+	cl.tstats.ClrFlag(cos.NodeAlerts, cos.CertificateExpired|cos.CertificateInvalid|cos.CertWillSoonExpire|cos.CertificateRevoked)
+	cl.xcert.Store(xcert)
+	if cl.staple != nil {
+		cl.staple.next.Store(0)
+		cl.staple.refresh(cl)
+	}
 	if rem < warnSoonExpire {
 		cl.tstats.SetFlag(cos.NodeAlerts, cos.CertWillSoonExpire)
 	}
@@ -247,6 +535,19 @@ func (cl *certLoader) do(compare bool) (err error) {
 	return nil
 }
 
+// returns empty when not configured or unavailable (to fall back to fstat comparison)
+func (cl *certLoader) readGen() string {
+	if cl.genFile == "" {
+		return ""
+	}
+	b, err := os.ReadFile(cl.genFile)
+	if err != nil {
+		nlog.Warningln(name+": failed to read generation (falling back to mod-time comparison):", err)
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
 ///////////
 // xcert //
 ///////////
@@ -271,6 +572,7 @@ func (x *xcert) String() string {
 
 // NOTE: second time parsing certificate (first time in tls.LoadX509KeyPair above)
 // to find out valid time bounds
+// (nil finfo - not loaded from file, see acmeMgr)
 func (x *xcert) ini(finfo os.FileInfo) (rem time.Duration, err error) {
 	if x.Certificate.Leaf == nil {
 		x.Certificate.Leaf, err = x509.ParseCertificate(x.Certificate.Certificate[0])
@@ -278,12 +580,25 @@ func (x *xcert) ini(finfo os.FileInfo) (rem time.Duration, err error) {
 			return 0, fmt.Errorf("%s: failed to parse %q, err: %w", name, x.parent.certFile, err)
 		}
 	}
-	{
+	if finfo != nil {
 		x.modTime = finfo.ModTime()
 		x.size = finfo.Size()
+	}
+	{
 		x.notBefore = x.Certificate.Leaf.NotBefore
 		x.notAfter = x.Certificate.Leaf.NotAfter
 	}
+	switch pub := x.Certificate.Leaf.PublicKey.(type) {
+	case *rsa.PublicKey:
+		x.keyType, x.keyBits = KeyTypeRSA, pub.N.BitLen()
+	case *ecdsa.PublicKey:
+		params := pub.Curve.Params()
+		x.keyType, x.curve, x.keyBits = KeyTypeECDSA, params.Name, params.BitSize
+	case ed25519.PublicKey:
+		x.keyType, x.keyBits = KeyTypeEd25519, 8*ed25519.PublicKeySize
+	default:
+		x.keyType = fmt.Sprintf("%T", pub)
+	}
 	now := time.Now()
 	switch {
 	case now.After(x.notAfter):
@@ -297,10 +612,84 @@ func (x *xcert) ini(finfo os.FileInfo) (rem time.Duration, err error) {
 	return rem, err
 }
 
+///////////////
+// KeyPolicy //
+///////////////
+
+// comma-separated lists (see cmn.HTTPConf); returns nil when there are no restrictions
+func NewKeyPolicy(types, curves string, minRSABits int) (*KeyPolicy, error) {
+	policy := &KeyPolicy{Types: splitCSV(types), Curves: splitCSV(curves), MinRSABits: minRSABits}
+	if len(policy.Types) == 0 && len(policy.Curves) == 0 && minRSABits == 0 {
+		return nil, nil
+	}
+	if minRSABits < 0 {
+		return nil, fmt.Errorf("%s: invalid minimum RSA key size %d", name, minRSABits)
+	}
+	for _, ty := range policy.Types {
+		switch ty {
+		case KeyTypeRSA, KeyTypeECDSA, KeyTypeEd25519:
+		default:
+			return nil, fmt.Errorf("%s: invalid key type %q (expecting one of: %q, %q, %q)", name, ty,
+				KeyTypeRSA, KeyTypeECDSA, KeyTypeEd25519)
+		}
+	}
+	return policy, nil
+}
+
+func (p *KeyPolicy) check(x *xcert) error {
+	if len(p.Types) > 0 && !cos.StringInSlice(x.keyType, p.Types) {
+		return fmt.Errorf("key type %q is not allowed (allowed: %v)", x.keyType, p.Types)
+	}
+	switch x.keyType {
+	case KeyTypeRSA:
+		if x.keyBits < p.MinRSABits {
+			return fmt.Errorf("RSA key size %d is less than the required minimum %d", x.keyBits, p.MinRSABits)
+		}
+	case KeyTypeECDSA:
+		if len(p.Curves) > 0 && !cos.StringInSlice(x.curve, p.Curves) {
+			return fmt.Errorf("ECDSA curve %q is not allowed (allowed: %v)", x.curve, p.Curves)
+		}
+	case KeyTypeEd25519:
+	default:
+		return errors.New("unsupported public key type " + x.keyType)
+	}
+	return nil
+}
+
 //
 // other
 //
 
+func splitCSV(s string) (out []string) {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// retry transient filesystem errors, to ride out:
+// - NFS (and other network filesystems) hiccups;
+// - ENOENT while the cert (or key) is being atomically replaced (renamed over);
+// a genuinely missing file remains missing and fails after the last retry
+func retry(fn func() error) (err error) {
+	sleep := retrySleep
+	for i := 0; ; i++ {
+		if err = fn(); err == nil || !isTransient(err) || i >= numRetries {
+			return err
+		}
+		nlog.Warningln(name+": transient error, retrying in", sleep, "-", err)
+		time.Sleep(sleep)
+		sleep <<= 1
+	}
+}
+
+func isTransient(err error) bool {
+	return errors.Is(err, syscall.ENOENT) || errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EINTR) ||
+		errors.Is(err, syscall.ESTALE) || errors.Is(err, syscall.ETIMEDOUT)
+}
+
 func (e *errExpired) Error() string { return e.msg }
 
 func isExpired(err error) bool {
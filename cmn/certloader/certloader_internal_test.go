@@ -0,0 +1,1008 @@
+// Package certloader loads and reloads X.509 certs.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package certloader
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/tools/tassert"
+
+	"golang.org/x/crypto/ocsp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// minimal cos.StatsUpdater
+type tstats struct {
+	m   map[string]int64
+	mtx sync.Mutex
+}
+
+func (s *tstats) Inc(name string) { s.Add(name, 1) }
+
+func (s *tstats) Add(name string, val int64) {
+	s.mtx.Lock()
+	s.m[name] += val
+	s.mtx.Unlock()
+}
+
+func (s *tstats) AddWith(nvs ...cos.NamedVal64) {
+	for _, nv := range nvs {
+		s.Add(nv.Name, nv.Value)
+	}
+}
+
+func (s *tstats) Get(name string) int64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.m[name]
+}
+
+func (s *tstats) SetFlag(name string, set cos.NodeStateFlags) {
+	s.mtx.Lock()
+	s.m[name] |= int64(set)
+	s.mtx.Unlock()
+}
+
+func (s *tstats) ClrFlag(name string, clr cos.NodeStateFlags) {
+	s.mtx.Lock()
+	s.m[name] &^= int64(clr)
+	s.mtx.Unlock()
+}
+
+func (s *tstats) SetClrFlag(name string, set, clr cos.NodeStateFlags) {
+	s.SetFlag(name, set)
+	s.ClrFlag(name, clr)
+}
+
+// write self-signed (cert, key) pair
+func genCert(t *testing.T, certFile, keyFile string, notBefore, notAfter time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tassert.CheckFatal(t, err)
+	genCertKey(t, certFile, keyFile, notBefore, notAfter, key)
+}
+
+func genCertKey(t *testing.T, certFile, keyFile string, notBefore, notAfter time.Time, key crypto.Signer) {
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "certloader-test"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	tassert.CheckFatal(t, err)
+	kder, err := x509.MarshalPKCS8PrivateKey(key)
+	tassert.CheckFatal(t, err)
+
+	tassert.CheckFatal(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	tassert.CheckFatal(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: kder}), 0o600))
+}
+
+func newTestLoader(t *testing.T) (*certLoader, *tstats) {
+	var (
+		dir    = t.TempDir()
+		stats  = &tstats{m: make(map[string]int64, 4)}
+		cl     = &certLoader{certFile: filepath.Join(dir, "cert.pem"), keyFile: filepath.Join(dir, "key.pem"), tstats: stats}
+		now    = time.Now()
+		before = now.Add(-time.Hour)
+	)
+	genCert(t, cl.certFile, cl.keyFile, before, now.Add(30*24*time.Hour))
+	tassert.CheckFatal(t, cl.do(false /*compare*/))
+	return cl, stats
+}
+
+func TestLoadStats(t *testing.T) {
+	cl, stats := newTestLoader(t)
+
+	ls := cl.stats()
+	tassert.Fatalf(t, !ls.Loaded.IsZero(), "expected non-zero load time")
+	tassert.Errorf(t, ls.Latency > 0, "expected positive load latency, got %v", ls.Latency)
+	tassert.Errorf(t, ls.Failed.IsZero(), "expected no failures, got %v", ls.Failed)
+	tassert.Errorf(t, stats.Get(cos.CertReloadCount) == 1, "expected 1 (re)load, got %d", stats.Get(cos.CertReloadCount))
+
+	// unchanged - nothing to do
+	tassert.CheckFatal(t, cl.do(true /*compare*/))
+	tassert.Errorf(t, cl.stats().Loaded.Equal(ls.Loaded), "expected load time unchanged")
+
+	// updated (same validity, different key)
+	time.Sleep(10 * time.Millisecond)
+	now := time.Now()
+	genCert(t, cl.certFile, cl.keyFile, now.Add(-time.Hour), now.Add(30*24*time.Hour))
+	tassert.CheckFatal(t, cl.do(true /*compare*/))
+	ls2 := cl.stats()
+	tassert.Errorf(t, ls2.Loaded.After(ls.Loaded), "expected load time to advance (%v, %v)", ls.Loaded, ls2.Loaded)
+	tassert.Errorf(t, stats.Get(cos.CertReloadCount) == 2, "expected 2 (re)loads, got %d", stats.Get(cos.CertReloadCount))
+	tassert.Errorf(t, stats.Get(cos.CertReloadLatency) > 0, "expected (re)load latency")
+}
+
+func TestProps(t *testing.T) {
+	props := Props()
+	tassert.Errorf(t, props["error"] != "", "expected error when not loading, got %v", props)
+	tassert.Errorf(t, Load() != nil, "expected error when not loading")
+
+	cl, _ := newTestLoader(t)
+	gcl = cl
+	defer func() { gcl = nil }()
+
+	props = Props()
+	leaf := cl.xcert.Load().Leaf
+	tassert.Errorf(t, props["subject"] == "CN=certloader-test", "unexpected subject %q", props["subject"])
+	sum := sha256.Sum256(leaf.Raw)
+	fp := strings.ReplaceAll(props["fingerprint (SHA-256)"], ":", "")
+	tassert.Errorf(t, strings.EqualFold(fp, hex.EncodeToString(sum[:])), "unexpected fingerprint %q", props["fingerprint (SHA-256)"])
+	tassert.Errorf(t, props["valid"] != "", "expected validity bounds")
+}
+
+func TestLoadStatsFailed(t *testing.T) {
+	cl, stats := newTestLoader(t)
+	ls := cl.stats()
+
+	time.Sleep(10 * time.Millisecond)
+	tassert.CheckFatal(t, os.WriteFile(cl.certFile, []byte("not a certificate"), 0o600))
+	err := cl.do(true /*compare*/)
+	tassert.Fatalf(t, err != nil, "expected failure to load invalid cert")
+
+	ls2 := cl.stats()
+	tassert.Fatalf(t, !ls2.Failed.IsZero(), "expected non-zero failed-attempt time")
+	tassert.Errorf(t, ls2.Failed.After(ls.Loaded), "expected failed-attempt time to advance (%v, %v)", ls.Loaded, ls2.Failed)
+	tassert.Errorf(t, ls2.Loaded.Equal(ls.Loaded), "expected last successful load time unchanged")
+	tassert.Errorf(t, stats.Get(cos.ErrCertReloadCount) == 1, "expected 1 failure, got %d", stats.Get(cos.ErrCertReloadCount))
+
+	// and again
+	time.Sleep(10 * time.Millisecond)
+	tassert.CheckFatal(t, os.Remove(cl.certFile))
+	tassert.Fatalf(t, cl.do(true) != nil, "expected failure to fstat")
+	ls3 := cl.stats()
+	tassert.Errorf(t, ls3.Failed.After(ls2.Failed), "expected failed-attempt time to advance (%v, %v)", ls2.Failed, ls3.Failed)
+}
+
+func TestKeyPolicy(t *testing.T) {
+	policy, err := NewKeyPolicy("rsa, ecdsa", "P-256,P-384", 2048)
+	tassert.CheckFatal(t, err)
+
+	var (
+		rsa1024, _ = rsa.GenerateKey(rand.Reader, 1024)
+		rsa2048, _ = rsa.GenerateKey(rand.Reader, 2048)
+		p256, _    = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		p521, _    = ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+		_, ed, _   = ed25519.GenerateKey(rand.Reader)
+	)
+	tests := []struct {
+		key     crypto.Signer
+		name    string
+		keyType string
+		curve   string
+		keyBits int
+		ok      bool
+	}{
+		{name: "allowed-ecdsa", key: p256, keyType: KeyTypeECDSA, curve: "P-256", keyBits: 256, ok: true},
+		{name: "allowed-rsa", key: rsa2048, keyType: KeyTypeRSA, keyBits: 2048, ok: true},
+		{name: "undersized-rsa", key: rsa1024},
+		{name: "disallowed-curve", key: p521},
+		{name: "disallowed-type", key: ed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				dir   = t.TempDir()
+				stats = &tstats{m: make(map[string]int64, 4)}
+				cl    = &certLoader{certFile: filepath.Join(dir, "cert.pem"), keyFile: filepath.Join(dir, "key.pem"),
+					policy: policy, tstats: stats}
+				now = time.Now()
+			)
+			genCertKey(t, cl.certFile, cl.keyFile, now.Add(-time.Hour), now.Add(30*24*time.Hour), test.key)
+			err := cl.do(false /*compare*/)
+			if !test.ok {
+				tassert.Fatalf(t, err != nil, "expected key policy violation")
+				tassert.Errorf(t, cl.xcert.Load() == nil, "expected certificate not to be loaded")
+				tassert.Errorf(t, stats.Get(cos.ErrCertReloadCount) == 1, "expected 1 failure, got %d",
+					stats.Get(cos.ErrCertReloadCount))
+				return
+			}
+			tassert.CheckFatal(t, err)
+			x := cl.xcert.Load()
+			tassert.Errorf(t, x.keyType == test.keyType && x.curve == test.curve && x.keyBits == test.keyBits,
+				"expected (%s, %q, %d), got (%s, %q, %d)", test.keyType, test.curve, test.keyBits, x.keyType, x.curve, x.keyBits)
+		})
+	}
+
+	// no policy - no restrictions
+	policy, err = NewKeyPolicy("", "", 0)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, policy == nil, "expected no policy, got %+v", policy)
+	cl, _ := newTestLoader(t)
+	genCertKey(t, cl.certFile, cl.keyFile, time.Now().Add(-time.Hour), time.Now().Add(time.Hour), rsa1024)
+	tassert.CheckFatal(t, cl.do(false /*compare*/))
+	tassert.Errorf(t, cl.xcert.Load().keyBits == 1024, "expected 1024-bit RSA key, got %d", cl.xcert.Load().keyBits)
+
+	// invalid
+	_, err = NewKeyPolicy("rsa,dsa", "", 0)
+	tassert.Errorf(t, err != nil, "expected invalid key type to fail")
+}
+
+func TestTransientFS(t *testing.T) {
+	cl, stats := newTestLoader(t)
+	var (
+		x0    = cl.xcert.Load()
+		ls    = cl.stats()
+		calls int
+		fail  int // num leading calls to fail
+		errno syscall.Errno
+	)
+	savedSleep := retrySleep
+	retrySleep = time.Millisecond
+	fstat = func(name string) (os.FileInfo, error) {
+		calls++
+		if calls <= fail {
+			return nil, &os.PathError{Op: "stat", Path: name, Err: errno}
+		}
+		return os.Stat(name)
+	}
+	defer func() {
+		retrySleep = savedSleep
+		fstat = os.Stat
+	}()
+
+	// transient failure followed by success
+	time.Sleep(10 * time.Millisecond)
+	now := time.Now()
+	genCert(t, cl.certFile, cl.keyFile, now.Add(-time.Hour), now.Add(30*24*time.Hour))
+	calls, fail, errno = 0, 2, syscall.ESTALE
+	tassert.CheckFatal(t, cl.do(true /*compare*/))
+	tassert.Errorf(t, calls == 3, "expected 3 fstat calls, got %d", calls)
+	tassert.Errorf(t, cl.stats().Loaded.After(ls.Loaded), "expected the updated cert to be loaded")
+	tassert.Errorf(t, stats.Get(cos.ErrCertReloadCount) == 0, "expected no failures, got %d", stats.Get(cos.ErrCertReloadCount))
+	x1 := cl.xcert.Load()
+	tassert.Errorf(t, x1 != x0, "expected the updated cert")
+
+	// persistent - fails after retrying; the previously loaded cert continues to serve
+	calls, fail, errno = 0, 100, syscall.ENOENT
+	tassert.Fatalf(t, cl.do(true) != nil, "expected failure")
+	tassert.Errorf(t, calls == numRetries+1, "expected %d fstat calls, got %d", numRetries+1, calls)
+	tassert.Errorf(t, cl.xcert.Load() == x1, "expected the previously loaded cert to remain")
+
+	// not transient - no retries
+	calls, errno = 0, syscall.EACCES
+	tassert.Fatalf(t, cl.do(true) != nil, "expected failure")
+	tassert.Errorf(t, calls == 1, "expected a single fstat call, got %d", calls)
+	tassert.Errorf(t, stats.Get(cos.ErrCertReloadCount) == 2, "expected 2 failures, got %d", stats.Get(cos.ErrCertReloadCount))
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	cl, _ := newTestLoader(t)
+	gcl = cl
+	defer func() { gcl = nil }()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	genCert(t, caFile, filepath.Join(filepath.Dir(caFile), "ca-key.pem"), time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+	sconf, err := BuildServerTLSConfig(&TLSOpts{CAFile: caFile, ClientAuth: tls.RequireAndVerifyClientCert})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, sconf.MinVersion == tls.VersionTLS12, "expected default min version, got %x", sconf.MinVersion)
+	tassert.Errorf(t, sconf.ClientAuth == tls.RequireAndVerifyClientCert, "expected client auth %v", sconf.ClientAuth)
+	tassert.Fatalf(t, sconf.GetCertificate != nil && sconf.GetConfigForClient != nil, "expected dynamic callbacks")
+
+	cconf, err := BuildClientTLSConfig(&TLSOpts{CAFile: caFile, MinVersion: tls.VersionTLS13})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, cconf.MinVersion == tls.VersionTLS13, "expected min version TLS 1.3, got %x", cconf.MinVersion)
+	tassert.Fatalf(t, cconf.GetClientCertificate != nil && cconf.VerifyConnection != nil, "expected dynamic callbacks")
+
+	var (
+		leaf = func() []byte {
+			cert, err := sconf.GetCertificate(nil)
+			tassert.CheckFatal(t, err)
+			ccert, err := cconf.GetClientCertificate(nil)
+			tassert.CheckFatal(t, err)
+			tassert.Fatalf(t, bytes.Equal(cert.Certificate[0], ccert.Certificate[0]), "expected server and client certs to match")
+			return cert.Certificate[0]
+		}
+		pool = func() *x509.CertPool {
+			c, err := sconf.GetConfigForClient(nil)
+			tassert.CheckFatal(t, err)
+			tassert.Errorf(t, c.GetCertificate != nil && c.ClientAuth == sconf.ClientAuth, "expected per-handshake config to inherit")
+			return c.ClientCAs
+		}
+		leaf1, pool1 = leaf(), pool()
+	)
+	tassert.Fatalf(t, bytes.Equal(leaf1, cl._get().Certificate[0]), "expected current certificate")
+
+	// rotate both
+	time.Sleep(10 * time.Millisecond)
+	now := time.Now()
+	genCert(t, cl.certFile, cl.keyFile, now.Add(-time.Hour), now.Add(30*24*time.Hour))
+	genCert(t, caFile, filepath.Join(filepath.Dir(caFile), "ca-key.pem"), now.Add(-time.Hour), now.Add(time.Hour))
+	cl.hk(0)
+
+	leaf2, pool2 := leaf(), pool()
+	tassert.Errorf(t, !bytes.Equal(leaf1, leaf2), "expected rotated certificate")
+	tassert.Errorf(t, bytes.Equal(leaf2, cl._get().Certificate[0]), "expected current certificate")
+	tassert.Errorf(t, !pool1.Equal(pool2), "expected reloaded CA pool")
+	tassert.Errorf(t, len(cl.cas) == 1, "expected single (shared) CA pool, got %d", len(cl.cas))
+
+	// skip-verify
+	cconf, err = BuildClientTLSConfig(&TLSOpts{CAFile: caFile, SkipVerify: true})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, cconf.InsecureSkipVerify && cconf.VerifyConnection == nil, "expected no verification")
+}
+
+func TestGenReload(t *testing.T) {
+	cl, stats := newTestLoader(t)
+	cl.genFile = filepath.Join(filepath.Dir(cl.certFile), "generation")
+	tassert.CheckFatal(t, os.WriteFile(cl.genFile, []byte("1\n"), 0o600))
+	tassert.CheckFatal(t, cl.do(false /*compare*/))
+	tassert.Fatalf(t, cl.xcert.Load().gen == "1", "expected generation %q, got %q", "1", cl.xcert.Load().gen)
+	tassert.Errorf(t, cl.hktime() <= genCheckInterval, "expected hk interval capped at %v, got %v", genCheckInterval, cl.hktime())
+
+	// unchanged - nothing to do
+	tassert.CheckFatal(t, cl.do(true /*compare*/))
+	tassert.Errorf(t, stats.Get(cos.CertReloadCount) == 2, "expected 2 (re)loads, got %d", stats.Get(cos.CertReloadCount))
+
+	// bump the generation: reload notwithstanding unchanged certificate file
+	finfo, err := os.Stat(cl.certFile)
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, os.WriteFile(cl.genFile, []byte("2\n"), 0o600))
+	tassert.CheckFatal(t, cl.do(true /*compare*/))
+	tassert.Errorf(t, stats.Get(cos.CertReloadCount) == 3, "expected 3 (re)loads, got %d", stats.Get(cos.CertReloadCount))
+	tassert.Errorf(t, cl.xcert.Load().gen == "2", "expected generation %q, got %q", "2", cl.xcert.Load().gen)
+	tassert.Errorf(t, cl.xcert.Load().modTime.Equal(finfo.ModTime()), "expected certificate mod-time unchanged")
+
+	// generation unavailable: fall back to fstat comparison
+	tassert.CheckFatal(t, os.Remove(cl.genFile))
+	tassert.CheckFatal(t, cl.do(true /*compare*/))
+	tassert.Errorf(t, stats.Get(cos.CertReloadCount) == 3, "expected 3 (re)loads, got %d", stats.Get(cos.CertReloadCount))
+	time.Sleep(10 * time.Millisecond)
+	now := time.Now()
+	genCert(t, cl.certFile, cl.keyFile, now.Add(-time.Hour), now.Add(30*24*time.Hour))
+	tassert.CheckFatal(t, cl.do(true /*compare*/))
+	tassert.Errorf(t, stats.Get(cos.CertReloadCount) == 4, "expected 4 (re)loads, got %d", stats.Get(cos.CertReloadCount))
+}
+
+func TestACME(t *testing.T) {
+	const domain = "ais.example.com"
+	var (
+		dir   = t.TempDir()
+		stats = &tstats{m: make(map[string]int64, 4)}
+		opts  = &ACMEOpts{Domains: []string{" " + domain, "b.example.com"}, Directory: "http://127.0.0.1:1/directory", CacheDir: dir}
+	)
+	_, err := newACME(&ACMEOpts{Domains: []string{"localhost"}, CacheDir: dir})
+	tassert.Errorf(t, err != nil, "expected invalid domain error")
+	_, err = newACME(&ACMEOpts{CacheDir: dir})
+	tassert.Errorf(t, err != nil, "expected no-domains error")
+
+	// pending: nothing obtained yet
+	am, err := newACME(opts)
+	tassert.CheckFatal(t, err)
+	cl := &certLoader{certFile: "acme:" + domain, tstats: stats, acme: am}
+	tassert.Errorf(t, cl.hktime() == acmePendingInterval, "expected %v, got %v", acmePendingInterval, cl.hktime())
+	tassert.Errorf(t, len(cl._get().Certificate) == 0, "expected no certificate")
+
+	// cached (see autocert.DirCache): ECDSA key followed by the chain
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tassert.CheckFatal(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	tassert.CheckFatal(t, err)
+	kder, err := x509.MarshalECPrivateKey(key)
+	tassert.CheckFatal(t, err)
+	b := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder})
+	b = append(b, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	tassert.CheckFatal(t, os.WriteFile(filepath.Join(dir, domain), b, 0o600))
+
+	tassert.CheckFatal(t, cl.do(true /*compare*/))
+	xcert := cl.xcert.Load()
+	tassert.Fatalf(t, xcert != nil && bytes.Equal(xcert.Certificate.Certificate[0], der), "expected cached certificate installed")
+	tassert.Errorf(t, xcert.keyType == KeyTypeECDSA, "expected %q key, got %q", KeyTypeECDSA, xcert.keyType)
+	tassert.Errorf(t, stats.Get(cos.CertReloadCount) == 1, "expected 1 load, got %d", stats.Get(cos.CertReloadCount))
+
+	// unchanged
+	tassert.CheckFatal(t, cl.do(true /*compare*/))
+	tassert.Errorf(t, stats.Get(cos.CertReloadCount) == 1, "expected no reload, got %d", stats.Get(cos.CertReloadCount))
+
+	// handshakes: installed (no SNI) vs TLS-ALPN-01 challenge (no token - not ours)
+	cert, err := cl._hello(&tls.ClientHelloInfo{})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bytes.Equal(cert.Certificate[0], der), "expected installed certificate")
+	_, err = cl._hello(&tls.ClientHelloInfo{ServerName: domain, SupportedProtos: []string{"acme-tls/1"}})
+	tassert.Errorf(t, err != nil, "expected challenge handled by ACME manager")
+
+	gcl = cl
+	defer func() { gcl = nil }()
+	sconf, err := BuildServerTLSConfig(&TLSOpts{})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, slices.Contains(sconf.NextProtos, "acme-tls/1"), "expected TLS-ALPN-01 protocol, got %v", sconf.NextProtos)
+	props := Props()
+	tassert.Errorf(t, props["acme"] != "" && props["key-type"] == "ecdsa P-256", "unexpected props %v", props)
+}
+
+func TestVault(t *testing.T) {
+	var (
+		issued, lookups, renewals int
+		stale, failing            bool
+		mtx                       sync.Mutex
+	)
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tassert.CheckFatal(t, err)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "vault-test-ca"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, caKey.Public(), caKey)
+	tassert.CheckFatal(t, err)
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDer}))
+
+	// (minimal) Vault
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/auth/token/lookup-self":
+			lookups++
+			w.Write([]byte(`{"data": {"ttl": 2, "renewable": true}}`))
+		case "/v1/auth/token/renew-self":
+			renewals++
+			w.Write([]byte(`{"auth": {"lease_duration": 3600, "renewable": true}}`))
+		case "/v1/pki/issue/ais":
+			if failing {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"errors": ["internal error"]}`))
+				return
+			}
+			var req map[string]string
+			tassert.CheckFatal(t, json.NewDecoder(r.Body).Decode(&req))
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			tassert.CheckFatal(t, err)
+			tmpl := &x509.Certificate{
+				SerialNumber: big.NewInt(time.Now().UnixNano()),
+				Subject:      pkix.Name{CommonName: req["common_name"]},
+				DNSNames:     strings.Split(req["alt_names"], ","),
+				IPAddresses:  []net.IP{net.ParseIP(req["ip_sans"])},
+				NotBefore:    time.Now().Add(-time.Minute),
+				NotAfter:     time.Now().Add(time.Hour),
+			}
+			if stale {
+				tmpl.NotBefore = time.Now().Add(-2 * time.Hour)
+			}
+			der, err := x509.CreateCertificate(rand.Reader, tmpl, caTmpl, key.Public(), caKey)
+			tassert.CheckFatal(t, err)
+			kder, err := x509.MarshalPKCS8PrivateKey(key)
+			tassert.CheckFatal(t, err)
+			issued++
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+				"certificate": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+				"private_key": string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: kder})),
+				"issuing_ca":  caPEM,
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	tassert.CheckFatal(t, os.WriteFile(tokenFile, []byte("s.token\n"), 0o600))
+	vm, err := newVault(&VaultOpts{Addr: srv.URL, Path: "/pki/issue/ais/", TokenFile: tokenFile, CommonName: "ais", AltNames: "ais.local, 10.0.0.1"})
+	tassert.CheckFatal(t, err)
+	var (
+		stats = &tstats{m: make(map[string]int64, 4)}
+		cl    = &certLoader{certFile: "vault:pki", tstats: stats, vault: vm}
+		check = func(tag string, expIssued, expLookups, expRenewals int) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			tassert.Errorf(t, issued == expIssued && lookups == expLookups && renewals == expRenewals,
+				"%s: expected (issued, lookups, renewals) = (%d, %d, %d), got (%d, %d, %d)", tag,
+				expIssued, expLookups, expRenewals, issued, lookups, renewals)
+		}
+	)
+
+	// issue
+	tassert.CheckFatal(t, cl.do(false /*compare*/))
+	check("issue", 1, 1, 0)
+	leaf := cl.xcert.Load().Certificate.Leaf
+	tassert.Errorf(t, leaf.Subject.CommonName == "ais" && len(leaf.IPAddresses) == 1 && leaf.IPAddresses[0].String() == "10.0.0.1",
+		"unexpected leaf (%s, %v)", leaf.Subject.CommonName, leaf.IPAddresses)
+	tassert.Errorf(t, len(cl.xcert.Load().Certificate.Certificate) == 2, "expected leaf followed by the issuing CA")
+
+	// not due
+	tassert.CheckFatal(t, cl.do(true /*compare*/))
+	check("not due", 1, 1, 0)
+
+	// token: half of the TTL remains
+	vm.mu.Lock()
+	vm.token.exp = time.Now().UnixNano() + vm.token.ttl/2
+	vm.mu.Unlock()
+	tassert.CheckFatal(t, cl.do(true /*compare*/))
+	check("renew", 1, 1, 1)
+	tassert.Errorf(t, vm.token.ttl == int64(time.Hour), "expected renewed token TTL %v, got %v", time.Hour, time.Duration(vm.token.ttl))
+
+	// rotate: two thirds of the lifetime elapsed
+	mtx.Lock()
+	stale = true
+	mtx.Unlock()
+	tassert.CheckFatal(t, cl.do(false /*compare*/))
+	tassert.CheckFatal(t, cl.do(true /*compare*/))
+	check("rotate", 3, 1, 1)
+	tassert.Errorf(t, vm.next(cl.xcert.Load()) == vaultRetryInterval, "expected retry interval, got %v", vm.next(cl.xcert.Load()))
+
+	// failing to rotate: keep serving, raise alert
+	mtx.Lock()
+	failing = true
+	mtx.Unlock()
+	prev := cl.xcert.Load()
+	err = cl.do(true /*compare*/)
+	tassert.Errorf(t, err != nil && strings.Contains(err.Error(), "internal error"), "expected Vault error, got %v", err)
+	tassert.Errorf(t, cl.xcert.Load() == prev, "expected the current certificate to remain")
+	tassert.Errorf(t, cos.NodeStateFlags(stats.Get(cos.NodeAlerts)).IsSet(cos.CertWillSoonExpire), "expected alert")
+	tassert.Errorf(t, stats.Get(cos.ErrCertReloadCount) == 1, "expected 1 error, got %d", stats.Get(cos.ErrCertReloadCount))
+}
+
+// CA and leaf signed by it
+func genCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tassert.CheckFatal(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "certloader-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	tassert.CheckFatal(t, err)
+	ca, err := x509.ParseCertificate(der)
+	tassert.CheckFatal(t, err)
+	return ca, key
+}
+
+func genLeaf(t *testing.T, ca *x509.Certificate, caKey crypto.Signer, serial int64, ocspURL string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tassert.CheckFatal(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "certloader-test-leaf"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	if ocspURL != "" {
+		tmpl.OCSPServer = []string{ocspURL}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, key.Public(), caKey)
+	tassert.CheckFatal(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	tassert.CheckFatal(t, err)
+	return leaf, key
+}
+
+// OCSP responder that revokes the given serial numbers
+func ocspResponder(t *testing.T, ca *x509.Certificate, caKey crypto.Signer, revoked map[int64]bool, cnt *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cnt.Add(1)
+		b, err := io.ReadAll(r.Body)
+		tassert.CheckFatal(t, err)
+		req, err := ocsp.ParseRequest(b)
+		tassert.CheckFatal(t, err)
+		tmpl := ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}
+		if revoked[req.SerialNumber.Int64()] {
+			tmpl.Status, tmpl.RevokedAt = ocsp.Revoked, time.Now().Add(-time.Minute)
+		}
+		resp, err := ocsp.CreateResponse(ca, ca, tmpl, caKey)
+		tassert.CheckFatal(t, err)
+		w.Write(resp)
+	}))
+}
+
+func TestClientCARotation(t *testing.T) {
+	cl, stats := newTestLoader(t)
+	gcl = cl
+	defer func() { gcl = nil }()
+
+	var (
+		caFile       = filepath.Join(t.TempDir(), "ca.pem")
+		caA, _       = genCA(t)
+		caB, keyB    = genCA(t)
+		leaf, leafSK = genLeaf(t, caB, keyB, 7, "")
+		writeCAs     = func(cas ...*x509.Certificate) {
+			var b []byte
+			for _, ca := range cas {
+				b = append(b, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})...)
+			}
+			tassert.CheckFatal(t, os.WriteFile(caFile, b, 0o600))
+		}
+	)
+	writeCAs(caA)
+	sconf, err := BuildServerTLSConfig(&TLSOpts{CAFile: caFile, ClientAuth: tls.RequireAndVerifyClientCert})
+	tassert.CheckFatal(t, err)
+
+	handshake := func() error {
+		c, s := net.Pipe()
+		defer c.Close()
+		defer s.Close()
+		cconf := &tls.Config{
+			InsecureSkipVerify: true, //nolint:gosec // (server verifies client)
+			Certificates:       []tls.Certificate{{Certificate: [][]byte{leaf.Raw}, PrivateKey: leafSK}},
+		}
+		go func() {
+			cc := tls.Client(c, cconf)
+			if cc.Handshake() == nil {
+				cc.Read(make([]byte, 1)) // (consume server's alert, if any)
+			}
+			c.Close()
+		}()
+		return tls.Server(s, sconf).Handshake()
+	}
+	tassert.Fatalf(t, handshake() != nil, "expected client certificate (CA B) rejected")
+
+	// CA rotation: old and new, reloaded upon Load (API)
+	time.Sleep(10 * time.Millisecond)
+	writeCAs(caA, caB)
+	tassert.CheckFatal(t, Load())
+	tassert.CheckFatal(t, handshake())
+	tassert.Errorf(t, strings.Contains(Props()["ca"], "(2 certificates)"), "unexpected CA props %q", Props()["ca"])
+
+	// invalid - keep the current pool
+	time.Sleep(10 * time.Millisecond)
+	tassert.CheckFatal(t, os.WriteFile(caFile, []byte("garbage"), 0o600))
+	cl.reload()
+	tassert.CheckFatal(t, handshake())
+	tassert.Errorf(t, stats.Get(cos.ErrCertReloadCount) == 1, "expected 1 failure, got %d", stats.Get(cos.ErrCertReloadCount))
+}
+
+func TestRevocation(t *testing.T) {
+	var (
+		stats   = &tstats{m: make(map[string]int64, 4)}
+		cl      = &certLoader{tstats: stats}
+		ca, key = genCA(t)
+		crlPath = filepath.Join(t.TempDir(), "ca.crl")
+		cnt     atomic.Int32
+		isAlert = func() bool { return cos.NodeStateFlags(stats.Get(cos.NodeAlerts)).IsSet(cos.CertificateRevoked) }
+	)
+	writeCRL := func(serials ...int64) {
+		tmpl := &x509.RevocationList{Number: big.NewInt(time.Now().UnixNano()), ThisUpdate: time.Now(), NextUpdate: time.Now().Add(time.Hour)}
+		for _, serial := range serials {
+			tmpl.RevokedCertificateEntries = append(tmpl.RevokedCertificateEntries,
+				x509.RevocationListEntry{SerialNumber: big.NewInt(serial), RevocationTime: time.Now()})
+		}
+		der, err := x509.CreateRevocationList(rand.Reader, tmpl, ca, key)
+		tassert.CheckFatal(t, err)
+		tassert.CheckFatal(t, os.WriteFile(crlPath, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), 0o600))
+	}
+
+	// CRL
+	writeCRL(42)
+	crl, err := cl.addCRL(crlPath)
+	tassert.CheckFatal(t, err)
+	verify := cl.verifyClient(crl, false)
+	leaf42, _ := genLeaf(t, ca, key, 42, "")
+	leaf43, _ := genLeaf(t, ca, key, 43, "")
+	tassert.Errorf(t, verify(nil, [][]*x509.Certificate{{leaf43, ca}}) == nil, "expected serial 43 not revoked")
+	tassert.Errorf(t, !isAlert(), "expected no alert")
+	tassert.Errorf(t, verify(nil, [][]*x509.Certificate{{leaf42, ca}}) != nil, "expected serial 42 revoked")
+	tassert.Errorf(t, isAlert(), "expected alert")
+
+	// reloaded when changed
+	time.Sleep(10 * time.Millisecond)
+	writeCRL(42, 43)
+	cl.reloadCRLs()
+	tassert.Errorf(t, verify(nil, [][]*x509.Certificate{{leaf43, ca}}) != nil, "expected serial 43 revoked")
+
+	// OCSP (cached)
+	srv := ocspResponder(t, ca, key, map[int64]bool{7: true}, &cnt)
+	defer srv.Close()
+	verify = cl.verifyClient(nil, true)
+	leaf7, _ := genLeaf(t, ca, key, 7, srv.URL)
+	leaf8, _ := genLeaf(t, ca, key, 8, srv.URL)
+	for range 2 {
+		tassert.Errorf(t, verify(nil, [][]*x509.Certificate{{leaf7, ca}}) != nil, "expected serial 7 revoked")
+		tassert.Errorf(t, verify(nil, [][]*x509.Certificate{{leaf8, ca}}) == nil, "expected serial 8 good")
+	}
+	tassert.Errorf(t, cnt.Load() == 2, "expected 2 OCSP requests (cached thereafter), got %d", cnt.Load())
+
+	// soft-fail
+	srv.Close()
+	leaf9, _ := genLeaf(t, ca, key, 9, srv.URL)
+	tassert.Errorf(t, verify(nil, [][]*x509.Certificate{{leaf9, ca}}) == nil, "expected soft-fail")
+}
+
+func TestStapleOCSP(t *testing.T) {
+	var (
+		stats   = &tstats{m: make(map[string]int64, 4)}
+		dir     = t.TempDir()
+		cl      = &certLoader{certFile: filepath.Join(dir, "cert.pem"), keyFile: filepath.Join(dir, "key.pem"), tstats: stats}
+		ca, key = genCA(t)
+		revoked = map[int64]bool{}
+		cnt     atomic.Int32
+	)
+	srv := ocspResponder(t, ca, key, revoked, &cnt)
+	defer srv.Close()
+
+	// leaf followed by the issuer
+	leaf, lkey := genLeaf(t, ca, key, 100, srv.URL)
+	kder, err := x509.MarshalPKCS8PrivateKey(lkey)
+	tassert.CheckFatal(t, err)
+	chain := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw})
+	chain = append(chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})...)
+	tassert.CheckFatal(t, os.WriteFile(cl.certFile, chain, 0o600))
+	tassert.CheckFatal(t, os.WriteFile(cl.keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: kder}), 0o600))
+	tassert.CheckFatal(t, cl.do(false /*compare*/))
+
+	cl.staple = &stapler{}
+	cl.staple.do(cl)
+	staple := cl.xcert.Load().Certificate.OCSPStaple
+	tassert.Fatalf(t, len(staple) > 0, "expected OCSP staple")
+	resp, err := ocsp.ParseResponse(staple, ca)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, resp.Status == ocsp.Good, "expected good status, got %d", resp.Status)
+	tassert.Errorf(t, !cl.staple.due() && cl.staple.until() > 20*time.Minute, "expected refresh halfway to next update, got %v", cl.staple.until())
+
+	// revoked
+	revoked[100] = true
+	cl.staple.do(cl)
+	flags := cos.NodeStateFlags(stats.Get(cos.NodeAlerts))
+	tassert.Errorf(t, flags.IsSet(cos.CertificateRevoked|cos.CertificateInvalid), "expected alerts, got %s", flags)
+}
+
+func TestSNI(t *testing.T) {
+	cl, _ := newTestLoader(t)
+	gcl = cl
+	defer func() { gcl = nil }()
+
+	dir := t.TempDir()
+	genSNI := func(certFile, keyFile string, names ...string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		tassert.CheckFatal(t, err)
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(time.Now().UnixNano()),
+			Subject:      pkix.Name{CommonName: names[0]},
+			DNSNames:     names,
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(30 * 24 * time.Hour),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+		tassert.CheckFatal(t, err)
+		kder, err := x509.MarshalPKCS8PrivateKey(key)
+		tassert.CheckFatal(t, err)
+		tassert.CheckFatal(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+		tassert.CheckFatal(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: kder}), 0o600))
+	}
+	var (
+		lbCert, lbKey   = filepath.Join(dir, "lb.crt"), filepath.Join(dir, "lb.key")
+		extCert, extKey = filepath.Join(dir, "ext.crt"), filepath.Join(dir, "ext.key")
+	)
+	genSNI(lbCert, lbKey, "lb.example.com")
+	genSNI(extCert, extKey, "*.ext.example.com")
+	tassert.CheckFatal(t, AddSNI(lbCert, lbKey))
+	tassert.CheckFatal(t, AddSNI(extCert, extKey))
+	tassert.Errorf(t, AddSNI(filepath.Join(dir, "none.crt"), lbKey) != nil, "expected error loading non-existing")
+
+	getCert, err := GetCert()
+	tassert.CheckFatal(t, err)
+	served := func(serverName string) []byte {
+		cert, err := getCert(&tls.ClientHelloInfo{ServerName: serverName})
+		tassert.CheckFatal(t, err)
+		return cert.Certificate[0]
+	}
+	primary := cl._get().Certificate[0]
+	tests := []struct {
+		serverName string
+		expected   []byte
+	}{
+		{"", primary},
+		{"lb.example.com", cl.snis[0]._get().Certificate[0]},
+		{"a.ext.example.com", cl.snis[1]._get().Certificate[0]},
+		{"a.b.ext.example.com", primary}, // (wildcard matches single label)
+		{"other.example.com", primary},
+	}
+	for _, test := range tests {
+		tassert.Errorf(t, bytes.Equal(served(test.serverName), test.expected), "%q: unexpected certificate", test.serverName)
+	}
+	props := Props()
+	tassert.Errorf(t, strings.Contains(props["sni"], "lb.example.com") && strings.Contains(props["sni"], "*.ext.example.com"),
+		"unexpected SNI props %q", props["sni"])
+
+	// reloaded along with the primary
+	prev := served("lb.example.com")
+	time.Sleep(10 * time.Millisecond)
+	genSNI(lbCert, lbKey, "lb.example.com")
+	cl.hk(0)
+	tassert.Errorf(t, !bytes.Equal(prev, served("lb.example.com")), "expected rotated SNI certificate")
+	tassert.Errorf(t, bytes.Equal(primary, served("")), "expected the same primary")
+}
+
+// X509SVIDResponse with a single SVID issued by the given CA
+func genSVIDResponse(t *testing.T, ca *x509.Certificate, caKey crypto.Signer, id string) ([]byte, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tassert.CheckFatal(t, err)
+	u, err := url.Parse(id)
+	tassert.CheckFatal(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		URIs:         []*url.URL{u},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, key.Public(), caKey)
+	tassert.CheckFatal(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	tassert.CheckFatal(t, err)
+	kder, err := x509.MarshalPKCS8PrivateKey(key)
+	tassert.CheckFatal(t, err)
+
+	var svid []byte
+	svid = protowire.AppendTag(svid, 1, protowire.BytesType)
+	svid = protowire.AppendString(svid, id)
+	svid = protowire.AppendTag(svid, 2, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, der)
+	svid = protowire.AppendTag(svid, 3, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, kder)
+	svid = protowire.AppendTag(svid, 4, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, ca.Raw)
+	svid = protowire.AppendTag(svid, 5, protowire.BytesType) // hint (ignored)
+	svid = protowire.AppendString(svid, "internal")
+
+	var resp []byte
+	resp = protowire.AppendTag(resp, 1, protowire.BytesType)
+	resp = protowire.AppendBytes(resp, svid)
+	return resp, leaf
+}
+
+func TestSPIFFE(t *testing.T) {
+	var (
+		stats   = &tstats{m: make(map[string]int64, 4)}
+		ca, key = genCA(t)
+		socket  = filepath.Join(t.TempDir(), "agent.sock")
+		pushCh  = make(chan []byte, 4)
+		streams atomic.Int32
+	)
+
+	// (minimal) SPIRE agent: pushes SVID responses until the stream gets closed
+	lis, err := net.Listen("unix", socket)
+	tassert.CheckFatal(t, err)
+	srv := grpc.NewServer(grpc.ForceServerCodec(spiffeCodec{}), grpc.UnknownServiceHandler(func(_ any, st grpc.ServerStream) error {
+		method, _ := grpc.MethodFromServerStream(st)
+		md, _ := metadata.FromIncomingContext(st.Context())
+		if method != spiffeMethod || len(md.Get(spiffeHeader)) == 0 {
+			return errors.New("unexpected request")
+		}
+		streams.Add(1)
+		var req []byte
+		if err := st.RecvMsg(&req); err != nil {
+			return err
+		}
+		for {
+			select {
+			case resp := <-pushCh:
+				if err := st.SendMsg(&resp); err != nil {
+					return err
+				}
+			case <-st.Context().Done():
+				return nil
+			}
+		}
+	}))
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	const id = "spiffe://example.org/ais/target"
+	resp1, leaf1 := genSVIDResponse(t, ca, key, id)
+	pushCh <- resp1
+
+	sm := newSPIFFE(&SPIFFEOpts{Socket: "unix://" + socket})
+	defer sm.cancel()
+	cl := &certLoader{certFile: "spiffe:" + socket, tstats: stats, spiffe: sm}
+	go sm.run(cl)
+	select {
+	case <-sm.ready:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for SVID")
+	}
+	tassert.Errorf(t, bytes.Equal(cl._get().Certificate[0], leaf1.Raw), "expected SVID installed")
+	tassert.Errorf(t, trustDomain(cl._get().Leaf) == "example.org", "expected trust domain, got %q", trustDomain(cl._get().Leaf))
+	tassert.Errorf(t, sm.bundle.get() != nil, "expected trust bundle")
+
+	// rotated (pushed by the agent)
+	resp2, leaf2 := genSVIDResponse(t, ca, key, id)
+	pushCh <- resp2
+	deadline := time.Now().Add(10 * time.Second)
+	for !bytes.Equal(cl._get().Certificate[0], leaf2.Raw) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	tassert.Errorf(t, bytes.Equal(cl._get().Certificate[0], leaf2.Raw), "expected rotated SVID")
+	tassert.Errorf(t, stats.Get(cos.CertReloadCount) == 2, "expected 2 loads, got %d", stats.Get(cos.CertReloadCount))
+
+	// peers: same vs other trust domain
+	_, peer := genSVIDResponse(t, ca, key, "spiffe://example.org/ais/proxy")
+	_, other := genSVIDResponse(t, ca, key, "spiffe://other.org/ais/proxy")
+	tassert.Errorf(t, sm.authorize(cl, peer) == nil, "expected same trust domain authorized")
+	tassert.Errorf(t, sm.authorize(cl, other) != nil, "expected other trust domain rejected")
+
+	// mTLS, end to end
+	gcl = cl
+	defer func() { gcl = nil }()
+	sconf, err := BuildServerTLSConfig(&TLSOpts{ClientAuth: tls.RequireAndVerifyClientCert})
+	tassert.CheckFatal(t, err)
+	cconf, err := BuildClientTLSConfig(&TLSOpts{})
+	tassert.CheckFatal(t, err)
+	tlsLis, err := tls.Listen("tcp", "127.0.0.1:0", sconf)
+	tassert.CheckFatal(t, err)
+	defer tlsLis.Close()
+	done := make(chan error, 1)
+	go func() {
+		conn, err := tlsLis.Accept()
+		if err == nil {
+			err = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+		done <- err
+	}()
+	conn, err := tls.Dial("tcp", tlsLis.Addr().String(), cconf)
+	tassert.CheckFatal(t, err)
+	conn.Close()
+	tassert.CheckFatal(t, <-done)
+
+	// unparsable response is skipped, the stream continues
+	pushCh <- []byte{0xff}
+	resp3, leaf3 := genSVIDResponse(t, ca, key, id)
+	pushCh <- resp3
+	deadline = time.Now().Add(10 * time.Second)
+	for !bytes.Equal(cl._get().Certificate[0], leaf3.Raw) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	tassert.Errorf(t, bytes.Equal(cl._get().Certificate[0], leaf3.Raw), "expected SVID following the invalid response")
+	tassert.Errorf(t, stats.Get(cos.ErrCertReloadCount) == 1 && streams.Load() == 1, "expected (1 error, 1 stream), got (%d, %d)",
+		stats.Get(cos.ErrCertReloadCount), streams.Load())
+}
@@ -0,0 +1,322 @@
+// Package certloader loads and reloads X.509 certs.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package certloader
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// revocation (optional):
+// - OCSP stapling (see StapleOCSP): fetch the OCSP response for the loaded leaf certificate from the
+//   responder listed in the certificate, staple it (tls.Certificate.OCSPStaple), and refresh halfway
+//   to its NextUpdate; "revoked" raises CertificateRevoked and CertificateInvalid alerts;
+// - mTLS (see TLSOpts): check verified client certificates against CRL file (reloaded when changed)
+//   and/or OCSP (responses cached until their NextUpdate); "revoked" fails the handshake and raises
+//   CertificateRevoked alert;
+// - soft-fail: unreachable responder, missing issuer, and such are logged and otherwise ignored
+
+const (
+	ocspTimeout       = 10 * time.Second
+	ocspRetryInterval = 10 * time.Minute
+	ocspMaxResponse   = 64 * cos.KiB
+	ocspMaxCached     = 16 * 1024 // client certificates' statuses
+)
+
+var errNoResponder = errors.New("no OCSP responder")
+
+type (
+	// OCSP staple of the loaded (own) certificate
+	stapler struct {
+		next atomic.Int64 // Unix nanoseconds: refresh when
+		busy atomic.Bool
+	}
+
+	// revoked serial numbers by issuer
+	crlFile struct {
+		revoked atomic.Pointer[map[string]struct{}] // (raw issuer, serial)
+		modTime time.Time
+		file    string
+		size    int64
+	}
+
+	// client certificates' OCSP statuses
+	ocspCache struct {
+		m  map[string]ocspStatus // by (raw issuer, serial)
+		mu sync.Mutex
+	}
+	ocspStatus struct {
+		until   time.Time
+		revoked bool
+	}
+)
+
+var ocspClient = &http.Client{Timeout: ocspTimeout}
+
+//
+// OCSP stapling
+//
+
+// (htrun only) after Init
+func StapleOCSP() {
+	if gcl == nil {
+		return // (not using certloader)
+	}
+	gcl.staple = &stapler{}
+	gcl.staple.refresh(gcl)
+}
+
+func (s *stapler) due() bool { return time.Now().UnixNano() >= s.next.Load() }
+
+// (hktime)
+func (s *stapler) until() time.Duration {
+	return max(time.Until(time.Unix(0, s.next.Load())), time.Second)
+}
+
+func (s *stapler) refresh(cl *certLoader) {
+	if !s.busy.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		s.do(cl)
+		s.busy.Store(false)
+	}()
+}
+
+func (s *stapler) do(cl *certLoader) {
+	cur := cl.xcert.Load()
+	if cur == nil {
+		return // (ACME pending)
+	}
+	resp, raw, err := fetchOCSP(cur.Certificate.Leaf, cur.Certificate.Certificate)
+	if err != nil {
+		if err == errNoResponder {
+			s.next.Store(time.Now().Add(dfltTimeInvalid).UnixNano())
+			return
+		}
+		nlog.Warningln(name+": failed to fetch OCSP staple for", cur.String(), "err:", err)
+		s.next.Store(time.Now().Add(ocspRetryInterval).UnixNano())
+		return
+	}
+	if resp.Status == ocsp.Revoked {
+		nlog.Errorln(name+":", cur.String(), "is revoked at", resp.RevokedAt)
+		cl.tstats.SetFlag(cos.NodeAlerts, cos.CertificateRevoked|cos.CertificateInvalid)
+		s.next.Store(time.Now().Add(dfltTimeInvalid).UnixNano())
+		return
+	}
+	x := *cur
+	x.Certificate.OCSPStaple = raw
+	if !cl.xcert.CompareAndSwap(cur, &x) {
+		s.next.Store(0) // (reloaded meanwhile - refresh again)
+		return
+	}
+	next := time.Now().Add(ocspRetryInterval)
+	if !resp.NextUpdate.IsZero() {
+		next = resp.ThisUpdate.Add(resp.NextUpdate.Sub(resp.ThisUpdate) / 2)
+	}
+	s.next.Store(next.UnixNano())
+}
+
+// returns OCSP response for the (parsed) leaf, given its chain
+func fetchOCSP(leaf *x509.Certificate, chain [][]byte) (*ocsp.Response, []byte, error) {
+	if len(leaf.OCSPServer) == 0 {
+		return nil, nil, errNoResponder
+	}
+	if len(chain) < 2 {
+		return nil, nil, errors.New("no issuer certificate in the chain")
+	}
+	issuer, err := x509.ParseCertificate(chain[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	return queryOCSP(leaf, issuer)
+}
+
+func queryOCSP(leaf, issuer *x509.Certificate) (*ocsp.Response, []byte, error) {
+	if len(leaf.OCSPServer) == 0 {
+		return nil, nil, errNoResponder
+	}
+	req, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := ocspClient.Post(leaf.OCSPServer[0], "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("OCSP responder %s: %s", leaf.OCSPServer[0], resp.Status)
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, ocspMaxResponse))
+	if err != nil {
+		return nil, nil, err
+	}
+	parsed, err := ocsp.ParseResponseForCert(raw, leaf, issuer)
+	return parsed, raw, err
+}
+
+//
+// mTLS: client certificates
+//
+
+// tls.Config.VerifyPeerCertificate
+func (cl *certLoader) verifyClient(crl *crlFile, useOCSP bool) func([][]byte, [][]*x509.Certificate) error {
+	return func(_ [][]byte, chains [][]*x509.Certificate) error {
+		for _, chain := range chains {
+			if len(chain) == 0 {
+				continue
+			}
+			leaf := chain[0]
+			if crl != nil && crl.isRevoked(leaf) {
+				return cl.revoked(leaf, "CRL")
+			}
+			if useOCSP && len(chain) > 1 && cl.ocsp.isRevoked(leaf, chain[1]) {
+				return cl.revoked(leaf, "OCSP")
+			}
+		}
+		return nil
+	}
+}
+
+func (cl *certLoader) revoked(leaf *x509.Certificate, via string) error {
+	cl.tstats.SetFlag(cos.NodeAlerts, cos.CertificateRevoked)
+	err := fmt.Errorf("%s: client certificate (CN %q, serial %s) is revoked (%s)", name, leaf.Subject.CommonName, leaf.SerialNumber, via)
+	nlog.Errorln(err)
+	return err
+}
+
+func revokedKey(rawIssuer []byte, serial fmt.Stringer) string {
+	return string(rawIssuer) + serial.String()
+}
+
+func (oc *ocspCache) isRevoked(leaf, issuer *x509.Certificate) bool {
+	var (
+		key = revokedKey(leaf.RawIssuer, leaf.SerialNumber)
+		now = time.Now()
+	)
+	oc.mu.Lock()
+	st, ok := oc.m[key]
+	oc.mu.Unlock()
+	if ok && now.Before(st.until) {
+		return st.revoked
+	}
+
+	resp, _, err := queryOCSP(leaf, issuer)
+	if err != nil {
+		if err != errNoResponder {
+			nlog.Warningln(name+": failed to check client certificate (OCSP), err:", err)
+		}
+		return false // soft-fail
+	}
+	st = ocspStatus{revoked: resp.Status == ocsp.Revoked, until: resp.NextUpdate}
+	if st.until.IsZero() {
+		st.until = now.Add(ocspRetryInterval)
+	}
+	oc.mu.Lock()
+	if oc.m == nil || len(oc.m) >= ocspMaxCached {
+		oc.m = make(map[string]ocspStatus, 64)
+	}
+	oc.m[key] = st
+	oc.mu.Unlock()
+	return st.revoked
+}
+
+//
+// CRL file
+//
+
+var crlsMu sync.Mutex
+
+func (cl *certLoader) addCRL(file string) (*crlFile, error) {
+	crlsMu.Lock()
+	defer crlsMu.Unlock()
+	for _, crl := range cl.crls {
+		if crl.file == file {
+			return crl, nil
+		}
+	}
+	crl := &crlFile{file: file}
+	if err := crl.load(false); err != nil {
+		return nil, err
+	}
+	cl.crls = append(cl.crls, crl)
+	cl.watcher.add(file)
+	return crl, nil
+}
+
+// (hk) reload CRLs that have changed; keep the current ones upon failure
+func (cl *certLoader) reloadCRLs() {
+	crlsMu.Lock()
+	defer crlsMu.Unlock()
+	for _, crl := range cl.crls {
+		if err := crl.load(true /*compare*/); err != nil {
+			nlog.Errorln(err)
+		}
+	}
+}
+
+func (crl *crlFile) isRevoked(leaf *x509.Certificate) bool {
+	_, ok := (*crl.revoked.Load())[revokedKey(leaf.RawIssuer, leaf.SerialNumber)]
+	return ok
+}
+
+// PEM ("X509 CRL" blocks) or DER
+func (crl *crlFile) load(compare bool) error {
+	finfo, err := fstat(crl.file)
+	if err != nil {
+		return fmt.Errorf("%s: failed to fstat CRL %q, err: %w", name, crl.file, err)
+	}
+	if compare && finfo.ModTime() == crl.modTime && finfo.Size() == crl.size {
+		return nil
+	}
+	b, err := os.ReadFile(crl.file)
+	if err != nil {
+		return fmt.Errorf("%s: failed to read CRL %q, err: %w", name, crl.file, err)
+	}
+	var ders [][]byte
+	for rest := b; ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		if block.Type == "X509 CRL" {
+			ders = append(ders, block.Bytes)
+		}
+	}
+	if len(ders) == 0 {
+		ders = append(ders, b) // (DER)
+	}
+	revoked := make(map[string]struct{}, 64)
+	for _, der := range ders {
+		list, err := x509.ParseRevocationList(der)
+		if err != nil {
+			return fmt.Errorf("%s: failed to parse CRL %q, err: %w", name, crl.file, err)
+		}
+		if !list.NextUpdate.IsZero() && time.Now().After(list.NextUpdate) {
+			nlog.Warningln(name+": CRL", crl.file, "is stale (next update", list.NextUpdate, "has passed)")
+		}
+		for i := range list.RevokedCertificateEntries {
+			revoked[revokedKey(list.RawIssuer, list.RevokedCertificateEntries[i].SerialNumber)] = struct{}{}
+		}
+	}
+	crl.revoked.Store(&revoked)
+	crl.modTime, crl.size = finfo.ModTime(), finfo.Size()
+	return nil
+}
@@ -0,0 +1,79 @@
+// Package certloader loads and reloads X.509 certs.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package certloader
+
+import (
+	"crypto/tls"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+)
+
+// multiple certificates: in addition to the primary one (see Init), serve certificates selected
+// by the server name the TLS client asks for (SNI), e.g.: the cluster's internal DNS name (primary)
+// and the external load balancer's name:
+// - the names are the certificates' DNS SANs, wildcards included (see x509.Certificate.VerifyHostname);
+// - first match wins, in the order added; no match (or no SNI) - the primary;
+// - reloaded along with the primary; node alerts reflect the primary, while the additional
+//   certificates are logged when failing to reload and raise CertWillSoonExpire when about to expire
+
+// (htrun only) after Init, prior to serving
+func AddSNI(certFile, keyFile string) error {
+	if gcl == nil {
+		return errors.New(name + ": SNI certificates require the primary one (server_crt, server_key)")
+	}
+	if gcl.acme != nil {
+		return errors.New(name + ": ACME obtains certificates for all configured domains on its own")
+	}
+	cl := &certLoader{certFile: certFile, keyFile: keyFile, policy: gcl.policy, tstats: gcl.tstats, sni: true}
+	if err := cl.do(false /*compare*/); err != nil {
+		nlog.Errorln("FATAL:", err)
+		return err
+	}
+	gcl.snis = append(gcl.snis, cl)
+	gcl.watcher.add(certFile)
+	gcl.watcher.add(keyFile)
+	return nil
+}
+
+func (cl *certLoader) sniCert(serverName string) *tls.Certificate {
+	for _, sni := range cl.snis {
+		if xcert := sni.xcert.Load(); xcert.Leaf.VerifyHostname(serverName) == nil {
+			return &xcert.Certificate
+		}
+	}
+	return nil
+}
+
+// (hk)
+func (cl *certLoader) reloadSNIs() {
+	for _, sni := range cl.snis {
+		if err := sni.do(true /*compare*/); err != nil {
+			nlog.Errorln("SNI:", err)
+		}
+		if rem := time.Until(sni.xcert.Load().notAfter); rem < warnSoonExpire {
+			nlog.Warningln("SNI:", sni.certFile, "X.509 will soon expire - remains:", rem)
+			cl.tstats.SetFlag(cos.NodeAlerts, cos.CertWillSoonExpire)
+		}
+	}
+}
+
+// (Props) e.g.: "lb.example.com,*.lb.example.com (/etc/ais/lb.crt)"
+func (cl *certLoader) sniProps() string {
+	var sb strings.Builder
+	for i, sni := range cl.snis {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(strings.Join(sni.xcert.Load().Leaf.DNSNames, ","))
+		sb.WriteString(" (")
+		sb.WriteString(sni.certFile)
+		sb.WriteByte(')')
+	}
+	return sb.String()
+}
@@ -0,0 +1,300 @@
+// Package certloader loads and reloads X.509 certs.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package certloader
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/hk"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// SPIFFE mode (e.g., SPIRE agent) - in place of the (certificate, key) files:
+// - X.509 SVIDs stream from the (node-local) agent via the Workload API, see
+//   https://github.com/spiffe/spiffe/blob/main/standards/SPIFFE_Workload_API.md;
+// - the agent pushes rotated SVIDs ahead of expiration - installed (hot-swapped) upon arrival;
+// - the accompanying trust bundle is the CA pool to verify peers (mTLS) when no CA file is configured,
+//   and the peers must then belong to the same trust domain (spiffe://<trust-domain>/...);
+// - broken stream gets re-established with (capped, doubling) backoff while the current SVID keeps serving
+// (no generated stubs: the few Workload API messages are (de)serialized in place - see spiffeCodec)
+
+const (
+	spiffeMethod      = "/SpiffeWorkloadAPI/FetchX509SVID"
+	spiffeHeader      = "workload.spiffe.io"
+	spiffeInitTimeout = 30 * time.Second // first SVID (workload attestation)
+	spiffeBackoffMin  = time.Second
+	spiffeBackoffMax  = 30 * time.Second
+)
+
+type (
+	SPIFFEOpts struct {
+		Socket string // Workload API endpoint, e.g. "unix:///run/spire/sockets/agent.sock"
+		ID     string // SPIFFE ID to use when the workload gets several SVIDs; empty - the first (default)
+	}
+	spiffeMgr struct {
+		ctx    context.Context
+		cancel context.CancelFunc
+		ready  chan struct{} // first SVID installed
+		bundle *caPool       // trust bundle
+		opts   SPIFFEOpts
+		once   sync.Once
+	}
+	spiffeSVID struct {
+		id     string
+		certs  []byte // DER, concatenated: leaf followed by intermediates
+		key    []byte // PKCS#8 DER
+		bundle []byte // DER, concatenated
+	}
+
+	// raw bytes in and out
+	spiffeCodec struct{}
+)
+
+// (htrun only) in place of Init
+func InitSPIFFE(opts *SPIFFEOpts, policy *KeyPolicy, tstats cos.StatsUpdater) error {
+	if opts.Socket == "" {
+		return errors.New(name + ": SPIFFE requires Workload API socket")
+	}
+	debug.Assert(gcl == nil)
+	sm := newSPIFFE(opts)
+	gcl = &certLoader{certFile: "spiffe:" + opts.Socket, policy: policy, tstats: tstats, spiffe: sm}
+	go sm.run(gcl)
+
+	select {
+	case <-sm.ready:
+	case <-time.After(spiffeInitTimeout):
+		sm.cancel()
+		err := fmt.Errorf("%s: timed out waiting for X.509 SVID from %s", name, opts.Socket)
+		nlog.Errorln("FATAL:", err)
+		return err
+	}
+	gcl.startWatch()
+	hk.Reg(name, gcl.hk, gcl.hktime())
+	return nil
+}
+
+func newSPIFFE(opts *SPIFFEOpts) *spiffeMgr {
+	sm := &spiffeMgr{opts: *opts, ready: make(chan struct{}), bundle: &caPool{}}
+	sm.ctx, sm.cancel = context.WithCancel(context.Background())
+	return sm
+}
+
+func (sm *spiffeMgr) String() string {
+	if sm.opts.ID != "" {
+		return sm.opts.ID + " via " + sm.opts.Socket
+	}
+	return sm.opts.Socket
+}
+
+func (sm *spiffeMgr) run(cl *certLoader) {
+	backoff := spiffeBackoffMin
+	for {
+		received, err := sm.stream(cl)
+		if sm.ctx.Err() != nil {
+			return
+		}
+		if received {
+			backoff = spiffeBackoffMin
+		}
+		nlog.Warningln(name+": Workload API stream from", sm.opts.Socket, "broke, retrying in", backoff, "err:", err)
+		select {
+		case <-sm.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff<<1, spiffeBackoffMax)
+	}
+}
+
+// returns when the stream breaks; received - at least one response
+func (sm *spiffeMgr) stream(cl *certLoader) (received bool, _ error) {
+	conn, err := grpc.NewClient(sm.opts.Socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	ctx := metadata.AppendToOutgoingContext(sm.ctx, spiffeHeader, "true")
+	st, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, spiffeMethod, grpc.ForceCodec(spiffeCodec{}))
+	if err != nil {
+		return false, err
+	}
+	req := []byte{} // X509SVIDRequest{}
+	if err := st.SendMsg(&req); err != nil {
+		return false, err
+	}
+	if err := st.CloseSend(); err != nil {
+		return false, err
+	}
+	for {
+		var resp []byte
+		if err := st.RecvMsg(&resp); err != nil {
+			return received, err
+		}
+		received = true
+		started := mono.NanoTime()
+		if err := sm.install(cl, resp, started); err != nil {
+			cl.failed.Store(time.Now().UnixNano())
+			cl.tstats.Inc(cos.ErrCertReloadCount)
+			nlog.Errorln(err)
+		}
+	}
+}
+
+func (sm *spiffeMgr) install(cl *certLoader, resp []byte, started int64) error {
+	svid, err := sm.parse(resp)
+	if err != nil {
+		return fmt.Errorf("%s: invalid X.509 SVID response: %w", name, err)
+	}
+	var cert tls.Certificate
+	if cert.Certificate, err = splitDER(svid.certs); err != nil {
+		return fmt.Errorf("%s: invalid X.509 SVID %q: %w", name, svid.id, err)
+	}
+	if cert.PrivateKey, err = x509.ParsePKCS8PrivateKey(svid.key); err != nil {
+		return fmt.Errorf("%s: invalid X.509 SVID %q key: %w", name, svid.id, err)
+	}
+	bundle, err := x509.ParseCertificates(svid.bundle)
+	if err != nil {
+		return fmt.Errorf("%s: invalid X.509 SVID %q bundle: %w", name, svid.id, err)
+	}
+	if err := cl.install(&xcert{Certificate: cert, parent: cl}, nil, started); err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	for _, ca := range bundle {
+		pool.AddCert(ca)
+	}
+	sm.bundle.pool.Store(pool)
+	sm.once.Do(func() { close(sm.ready) })
+	return nil
+}
+
+// X509SVIDResponse: the requested (or the first) SVID
+func (sm *spiffeMgr) parse(resp []byte) (*spiffeSVID, error) {
+	var found *spiffeSVID
+	err := protoBytes(resp, func(num protowire.Number, v []byte) error {
+		if num != 1 || found != nil { // repeated X509SVID svids = 1
+			return nil
+		}
+		svid := &spiffeSVID{}
+		err := protoBytes(v, func(num protowire.Number, v []byte) error {
+			switch num {
+			case 1:
+				svid.id = string(v)
+			case 2:
+				svid.certs = v
+			case 3:
+				svid.key = v
+			case 4:
+				svid.bundle = v
+			}
+			return nil
+		})
+		if err == nil && (sm.opts.ID == "" || sm.opts.ID == svid.id) {
+			found = svid
+		}
+		return err
+	})
+	if err == nil && found == nil {
+		err = fmt.Errorf("no SVID (%q)", sm.opts.ID)
+	}
+	return found, err
+}
+
+// peers (mTLS) must belong to the same trust domain
+func (sm *spiffeMgr) authorize(cl *certLoader, peer *x509.Certificate) error {
+	td := trustDomain(cl._get().Leaf)
+	if td != "" && trustDomain(peer) == td {
+		return nil
+	}
+	return fmt.Errorf("%s: peer (%v) does not belong to the trust domain %q", name, peer.URIs, td)
+}
+
+func trustDomain(cert *x509.Certificate) string {
+	if cert == nil {
+		return ""
+	}
+	for _, u := range cert.URIs {
+		if u.Scheme == "spiffe" {
+			return u.Host
+		}
+	}
+	return ""
+}
+
+//
+// protobuf
+//
+
+// invoke callback for each length-delimited field, skip all others
+func protoBytes(b []byte, cb func(protowire.Number, []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, b); n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := cb(num, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// concatenated DER certificates
+func splitDER(b []byte) ([][]byte, error) {
+	certs, err := x509.ParseCertificates(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("empty certificate chain")
+	}
+	ders := make([][]byte, len(certs))
+	for i, cert := range certs {
+		ders[i] = cert.Raw
+	}
+	return ders, nil
+}
+
+/////////////////
+// spiffeCodec //
+/////////////////
+
+func (spiffeCodec) Marshal(v any) ([]byte, error) { return *(v.(*[]byte)), nil }
+
+func (spiffeCodec) Unmarshal(data []byte, v any) error {
+	*(v.(*[]byte)) = append([]byte(nil), data...)
+	return nil
+}
+
+// (content-subtype: application/grpc+proto)
+func (spiffeCodec) Name() string { return "proto" }
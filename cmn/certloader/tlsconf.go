@@ -0,0 +1,293 @@
+// Package certloader loads and reloads X.509 certs.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package certloader
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+)
+
+// tls.Config builders: pre-wired with the dynamic (reloadable) certificate callbacks
+// and the current CA pool, with consistent defaults
+
+const dfltMinVersion = tls.VersionTLS12
+
+type (
+	TLSOpts struct {
+		// PEM file with CA certificate(s):
+		// - server: to verify client certificates (tls.Config.ClientCAs);
+		// - client: to verify server certificates (tls.Config.RootCAs); empty - use system pool
+		// when the loader is initialized (see Init), the file is reloaded along with the certificate
+		CAFile       string
+		CipherSuites []uint16           // empty - Go defaults
+		MinVersion   uint16             // zero - dfltMinVersion
+		ClientAuth   tls.ClientAuthType // server only
+		// server only, when verifying client certificates (see revoke.go):
+		CRLFile    string // PEM or DER file with CRL(s); reloaded along with the certificate
+		OCSP       bool   // query OCSP responders listed in client certificates
+		SkipVerify bool   // client only: do not verify server certificates
+	}
+
+	// CA pool that gets reloaded (hk, watcher) when the file changes, and upon Load;
+	// rotating CA: the file is expected to contain both old and new CAs for the duration
+	// (the new pool applies to new handshakes only)
+	caPool struct {
+		pool    atomic.Pointer[x509.CertPool]
+		modTime time.Time
+		file    string
+		size    int64
+		num     int // certificates in the pool
+	}
+)
+
+func (opts *TLSOpts) base() *tls.Config {
+	conf := &tls.Config{MinVersion: opts.MinVersion, CipherSuites: opts.CipherSuites}
+	if conf.MinVersion == 0 {
+		conf.MinVersion = dfltMinVersion
+	}
+	return conf
+}
+
+// BuildServerTLSConfig returns server config that serves the current certificate
+// and, if configured, verifies clients against the current CA pool
+func BuildServerTLSConfig(opts *TLSOpts) (*tls.Config, error) {
+	getCert, err := GetCert()
+	if err != nil {
+		return nil, err
+	}
+	conf := opts.base()
+	conf.ClientAuth = opts.ClientAuth
+	conf.GetCertificate = getCert
+	if gcl.acme != nil {
+		conf.NextProtos = gcl.acme.nextProtos()
+	}
+	if opts.ClientAuth >= tls.VerifyClientCertIfGiven && (opts.CRLFile != "" || opts.OCSP) {
+		var crl *crlFile
+		if opts.CRLFile != "" {
+			if crl, err = gcl.addCRL(opts.CRLFile); err != nil {
+				return nil, err
+			}
+		}
+		conf.VerifyPeerCertificate = gcl.verifyClient(crl, opts.OCSP)
+	}
+	var ca *caPool
+	switch {
+	case opts.CAFile != "":
+		if ca, err = gcl.addCA(opts.CAFile); err != nil {
+			return nil, err
+		}
+	case gcl.spiffe != nil && opts.ClientAuth >= tls.VerifyClientCertIfGiven:
+		ca = gcl.spiffe.bundle
+		conf.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return nil // (not required)
+			}
+			return gcl.spiffe.authorize(gcl, cs.PeerCertificates[0])
+		}
+	default:
+		return conf, nil
+	}
+	conf.ClientCAs = ca.get()
+
+	// tls.Config.ClientCAs is static - hence, per-handshake config with the current pool
+	base := conf.Clone()
+	conf.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		c := base.Clone()
+		c.ClientCAs = ca.get()
+		if gcl.acme != nil && gcl.acme.challenge(hello) {
+			c.ClientAuth = tls.NoClientCert // (the CA's validator won't present one)
+		}
+		return c, nil
+	}
+	return conf, nil
+}
+
+// BuildClientTLSConfig returns client config that presents the current certificate (if any)
+// and, if configured, verifies servers against the current CA pool
+func BuildClientTLSConfig(opts *TLSOpts) (conf *tls.Config, err error) {
+	conf = opts.base()
+	conf.InsecureSkipVerify = opts.SkipVerify
+	if gcl != nil {
+		if conf.GetClientCertificate, err = GetClientCert(); err != nil {
+			return nil, err
+		}
+	}
+	if opts.SkipVerify {
+		return conf, nil
+	}
+	var (
+		ca     *caPool
+		spiffe = gcl != nil && gcl.spiffe != nil && opts.CAFile == ""
+	)
+	switch {
+	case spiffe:
+		ca = gcl.spiffe.bundle
+	case opts.CAFile == "":
+		return conf, nil
+	case gcl != nil:
+		ca, err = gcl.addCA(opts.CAFile)
+	default:
+		ca, err = newCA(opts.CAFile) // (not reloaded)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// tls.Config.RootCAs is static - hence, skipping built-in verification
+	// in favor of verifying against the current pool
+	conf.InsecureSkipVerify = true
+	conf.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New(name + ": no server certificate")
+		}
+		vopts := x509.VerifyOptions{
+			DNSName:       cs.ServerName,
+			Roots:         ca.get(),
+			Intermediates: x509.NewCertPool(),
+		}
+		if spiffe {
+			vopts.DNSName = "" // SVIDs identify workloads (URI SAN), not hosts
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			vopts.Intermediates.AddCert(cert)
+		}
+		if _, err := cs.PeerCertificates[0].Verify(vopts); err != nil {
+			return err
+		}
+		if spiffe {
+			return gcl.spiffe.authorize(gcl, cs.PeerCertificates[0])
+		}
+		return nil
+	}
+	return conf, nil
+}
+
+//
+// CA pools
+//
+
+var casMu sync.Mutex
+
+func (cl *certLoader) addCA(file string) (*caPool, error) {
+	casMu.Lock()
+	defer casMu.Unlock()
+	for _, ca := range cl.cas {
+		if ca.file == file {
+			return ca, nil
+		}
+	}
+	ca, err := newCA(file)
+	if err != nil {
+		return nil, err
+	}
+	cl.cas = append(cl.cas, ca)
+	cl.watcher.add(file)
+	return ca, nil
+}
+
+// reload CA pools that have changed or, when not comparing, all of them (see Load);
+// keep the current pool upon failure; return the first error
+func (cl *certLoader) reloadCAs(compare bool) (err error) {
+	casMu.Lock()
+	defer casMu.Unlock()
+	for _, ca := range cl.cas {
+		loaded, errV := ca.load(compare)
+		switch {
+		case errV != nil:
+			nlog.Errorln(errV)
+			cl.tstats.Inc(cos.ErrCertReloadCount)
+			if err == nil {
+				err = errV
+			}
+		case loaded && compare:
+			nlog.Infoln(name+": reloaded CA", ca.file, "("+strconv.Itoa(ca.num), "certificates)")
+		}
+	}
+	return err
+}
+
+// (Props) e.g.: "/etc/ais/ca.pem (2 certificates)"
+func (cl *certLoader) caProps() string {
+	casMu.Lock()
+	defer casMu.Unlock()
+	var sb strings.Builder
+	for i, ca := range cl.cas {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(ca.file)
+		sb.WriteString(" (")
+		sb.WriteString(strconv.Itoa(ca.num))
+		sb.WriteString(" certificates)")
+	}
+	return sb.String()
+}
+
+func newCA(file string) (*caPool, error) {
+	ca := &caPool{file: file}
+	_, err := ca.load(false)
+	return ca, err
+}
+
+func (ca *caPool) get() *x509.CertPool { return ca.pool.Load() }
+
+func (ca *caPool) load(compare bool) (bool, error) {
+	var finfo os.FileInfo
+	err := retry(func() (err error) {
+		finfo, err = fstat(ca.file)
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to fstat CA %q, err: %w", name, ca.file, err)
+	}
+	if compare && finfo.ModTime() == ca.modTime && finfo.Size() == ca.size {
+		return false, nil
+	}
+	var b []byte
+	err = retry(func() (err error) {
+		b, err = os.ReadFile(ca.file)
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to read CA %q, err: %w", name, ca.file, err)
+	}
+	var (
+		pool = x509.NewCertPool()
+		num  int
+	)
+	for rest := b; ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" || len(block.Headers) != 0 {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return false, fmt.Errorf("%s: failed to parse CA %q, err: %w", name, ca.file, err)
+		}
+		pool.AddCert(cert)
+		num++
+	}
+	if num == 0 {
+		return false, fmt.Errorf("%s: failed to append CA certs from PEM %q", name, ca.file)
+	}
+	ca.pool.Store(pool)
+	ca.modTime, ca.size, ca.num = finfo.ModTime(), finfo.Size(), num
+	return true, nil
+}
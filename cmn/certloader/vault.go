@@ -0,0 +1,324 @@
+// Package certloader loads and reloads X.509 certs.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package certloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/hk"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Vault PKI secrets engine mode - in place of the (certificate, key) files:
+// - issues (short-lived) certificate at startup, and then again (rotates) on the hk schedule
+//   when two thirds of the current certificate's lifetime have elapsed;
+// - the private key never touches the disk;
+// - the token is read from file (see VaultOpts.TokenFile) upon every request - an external agent
+//   may rotate it; renewable token gets also renewed (renew-self) when half of its TTL remains;
+// - failure to rotate raises CertWillSoonExpire alert (and, eventually, CertificateExpired)
+// see https://developer.hashicorp.com/vault/api-docs/secret/pki#generate-certificate-and-key
+
+const (
+	vaultTimeout       = 30 * time.Second
+	vaultRetryInterval = time.Minute // (see next)
+	vaultTokenEnv      = "VAULT_TOKEN"
+)
+
+type (
+	VaultOpts struct {
+		Addr       string        // e.g. "https://vault.example.com:8200"
+		Path       string        // PKI issue endpoint: <mount>/issue/<role>, e.g. "pki/issue/ais"
+		TokenFile  string        // empty - VAULT_TOKEN environment
+		CommonName string        // requested CN
+		AltNames   string        // comma-separated DNS names and/or IP addresses
+		CAFile     string        // to verify Vault server; empty - system pool
+		TTL        time.Duration // zero - the role's default
+	}
+	vaultMgr struct {
+		client *http.Client
+		opts   VaultOpts
+		mu     sync.Mutex
+		token  struct {
+			last string // to notice rotated token
+			exp  int64  // Unix nanoseconds; zero - non-renewable (or unknown)
+			ttl  int64
+		}
+	}
+
+	// (subset of) responses
+	vaultIssued struct {
+		Data struct {
+			Certificate string   `json:"certificate"`
+			PrivateKey  string   `json:"private_key"`
+			IssuingCA   string   `json:"issuing_ca"`
+			CAChain     []string `json:"ca_chain"`
+		} `json:"data"`
+	}
+	vaultToken struct {
+		Data struct { // lookup-self
+			TTL       int64 `json:"ttl"`
+			Renewable bool  `json:"renewable"`
+		} `json:"data"`
+		Auth struct { // renew-self
+			LeaseDuration int64 `json:"lease_duration"`
+			Renewable     bool  `json:"renewable"`
+		} `json:"auth"`
+	}
+	vaultErrs struct {
+		Errors []string `json:"errors"`
+	}
+)
+
+// (htrun only) in place of Init
+func InitVault(opts *VaultOpts, policy *KeyPolicy, tstats cos.StatsUpdater) (err error) {
+	vm, err := newVault(opts)
+	if err != nil {
+		nlog.Errorln("FATAL:", err)
+		return err
+	}
+
+	debug.Assert(gcl == nil)
+	gcl = &certLoader{certFile: "vault:" + opts.Path, policy: policy, tstats: tstats, vault: vm}
+	if err = Load(); err != nil {
+		nlog.Errorln("FATAL:", err)
+		return err
+	}
+
+	gcl.startWatch()
+	hk.Reg(name, gcl.hk, gcl.hktime())
+	return nil
+}
+
+func newVault(opts *VaultOpts) (*vaultMgr, error) {
+	if opts.Path == "" || opts.CommonName == "" {
+		return nil, fmt.Errorf("%s: Vault PKI requires (issue path, common name), have (%q, %q)", name, opts.Path, opts.CommonName)
+	}
+	if _, err := url.ParseRequestURI(opts.Addr); err != nil {
+		return nil, fmt.Errorf("%s: invalid Vault address %q: %w", name, opts.Addr, err)
+	}
+	conf := &tls.Config{MinVersion: dfltMinVersion}
+	if opts.CAFile != "" {
+		ca, err := newCA(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		conf.RootCAs = ca.get()
+	}
+	vm := &vaultMgr{
+		opts:   *opts,
+		client: &http.Client{Timeout: vaultTimeout, Transport: &http.Transport{TLSClientConfig: conf}},
+	}
+	vm.opts.Addr = strings.TrimSuffix(opts.Addr, "/")
+	vm.opts.Path = strings.Trim(opts.Path, "/")
+	return vm, nil
+}
+
+func (vm *vaultMgr) String() string { return vm.opts.Addr + "/v1/" + vm.opts.Path }
+
+// issue (rotate) when due, or unconditionally when not comparing (see Load)
+func (vm *vaultMgr) do(cl *certLoader, compare bool) (err error) {
+	defer func() {
+		if err == nil {
+			return
+		}
+		cl.failed.Store(time.Now().UnixNano())
+		cl.tstats.Inc(cos.ErrCertReloadCount)
+		if cl.xcert.Load() != nil {
+			cl.tstats.SetFlag(cos.NodeAlerts, cos.CertWillSoonExpire) // still serving the current one
+		}
+	}()
+
+	vm.renewToken()
+	if compare {
+		if prev := cl.xcert.Load(); prev != nil && time.Now().Before(rotateAt(prev)) {
+			return nil
+		}
+	}
+	started := mono.NanoTime()
+	cert, err := vm.issue()
+	if err != nil {
+		return fmt.Errorf("%s: failed to issue certificate via %s, err: %w", name, vm, err)
+	}
+	return cl.install(&xcert{Certificate: cert, parent: cl}, nil, started)
+}
+
+func (vm *vaultMgr) issue() (tls.Certificate, error) {
+	var (
+		issued vaultIssued
+		req    = map[string]string{"common_name": vm.opts.CommonName}
+		dns    []string
+		ips    []string
+	)
+	for _, san := range splitCSV(vm.opts.AltNames) {
+		if net.ParseIP(san) != nil {
+			ips = append(ips, san)
+		} else {
+			dns = append(dns, san)
+		}
+	}
+	if len(dns) > 0 {
+		req["alt_names"] = strings.Join(dns, ",")
+	}
+	if len(ips) > 0 {
+		req["ip_sans"] = strings.Join(ips, ",")
+	}
+	if vm.opts.TTL > 0 {
+		req["ttl"] = vm.opts.TTL.String()
+	}
+	if err := vm.call(http.MethodPost, vm.opts.Path, req, &issued); err != nil {
+		return tls.Certificate{}, err
+	}
+
+	// leaf followed by the chain (the issuing CA when there's no chain)
+	d := &issued.Data
+	chain := d.CAChain
+	if len(chain) == 0 && d.IssuingCA != "" {
+		chain = []string{d.IssuingCA}
+	}
+	pemCert := d.Certificate
+	for _, ca := range chain {
+		pemCert += "\n" + ca
+	}
+	return tls.X509KeyPair([]byte(pemCert), []byte(d.PrivateKey))
+}
+
+// rotate when two thirds of the lifetime have elapsed
+func rotateAt(x *xcert) time.Time {
+	return x.notBefore.Add(x.notAfter.Sub(x.notBefore) * 2 / 3)
+}
+
+// (hktime) until the next rotation or token renewal, whichever comes first
+func (vm *vaultMgr) next(x *xcert) time.Duration {
+	at := time.Now().Add(vaultRetryInterval)
+	if x != nil {
+		at = rotateAt(x)
+	}
+	vm.mu.Lock()
+	if vm.token.exp != 0 {
+		if renewAt := time.Unix(0, vm.token.exp-vm.token.ttl/2); renewAt.Before(at) {
+			at = renewAt
+		}
+	}
+	vm.mu.Unlock()
+	return max(time.Until(at), vaultRetryInterval)
+}
+
+//
+// token
+//
+
+func (vm *vaultMgr) readToken() (string, error) {
+	if vm.opts.TokenFile == "" {
+		if token := os.Getenv(vaultTokenEnv); token != "" {
+			return token, nil
+		}
+		return "", errors.New("no Vault token (" + vaultTokenEnv + " not set)")
+	}
+	b, err := os.ReadFile(vm.opts.TokenFile)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// look up new (or rotated) token; renew renewable token when half of its TTL remains
+func (vm *vaultMgr) renewToken() {
+	token, err := vm.readToken()
+	if err != nil {
+		return // (will fail to issue)
+	}
+	var (
+		vt  vaultToken
+		now = time.Now().UnixNano()
+	)
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	switch {
+	case token != vm.token.last:
+		if err := vm.call(http.MethodGet, "auth/token/lookup-self", nil, &vt); err != nil {
+			nlog.Warningln(name+": failed to look up Vault token:", err)
+			return
+		}
+		vm.token.last = token
+		vm.setToken(vt.Data.TTL, vt.Data.Renewable)
+	case vm.token.exp != 0 && now >= vm.token.exp-vm.token.ttl/2:
+		if err := vm.call(http.MethodPost, "auth/token/renew-self", nil, &vt); err != nil {
+			nlog.Errorln(name+": failed to renew Vault token:", err)
+			return
+		}
+		vm.setToken(vt.Auth.LeaseDuration, vt.Auth.Renewable)
+		nlog.Infoln(name+": renewed Vault token, TTL", time.Duration(vm.token.ttl))
+	}
+}
+
+// (under lock) zero TTL - non-expiring (e.g., root) token
+func (vm *vaultMgr) setToken(ttl int64, renewable bool) {
+	if !renewable || ttl <= 0 {
+		vm.token.exp, vm.token.ttl = 0, 0
+		return
+	}
+	vm.token.ttl = int64(time.Duration(ttl) * time.Second)
+	vm.token.exp = time.Now().UnixNano() + vm.token.ttl
+}
+
+//
+// http
+//
+
+func (vm *vaultMgr) call(method, path string, in, out any) error {
+	token, err := vm.readToken()
+	if err != nil {
+		return err
+	}
+	var body io.Reader
+	if in != nil {
+		b, err := jsoniter.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), vaultTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, vm.opts.Addr+"/v1/"+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	resp, err := vm.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var verrs vaultErrs
+		if jsoniter.Unmarshal(b, &verrs) == nil && len(verrs.Errors) > 0 {
+			return fmt.Errorf("%s %s: %s %v", method, path, resp.Status, verrs.Errors)
+		}
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	return jsoniter.Unmarshal(b, out)
+}
@@ -0,0 +1,77 @@
+// Package certloader loads and reloads X.509 certs.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package certloader
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/nlog"
+
+	"golang.org/x/sys/unix"
+)
+
+// inotify: watching the directories (rather than the files themselves) to also catch
+// atomic replacement (rename) and symlink swap (e.g., Kubernetes secret volume's "..data")
+
+const watchMask = unix.IN_CLOSE_WRITE | unix.IN_CREATE | unix.IN_MOVED_TO | unix.IN_DELETE | unix.IN_ATTRIB
+
+type watcher struct {
+	f      *os.File
+	timer  *time.Timer // debounce
+	dirs   map[string]struct{}
+	reload func()
+	mu     sync.Mutex
+}
+
+func newWatcher(reload func()) (*watcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	w := &watcher{f: os.NewFile(uintptr(fd), "inotify"), dirs: make(map[string]struct{}, 2), reload: reload}
+	w.timer = time.AfterFunc(time.Hour, w.reload)
+	w.timer.Stop()
+	go w.run()
+	return w, nil
+}
+
+func (w *watcher) add(file string) {
+	if w == nil {
+		return
+	}
+	dir := filepath.Dir(file)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.dirs[dir]; ok {
+		return
+	}
+	if _, err := unix.InotifyAddWatch(int(w.f.Fd()), dir, watchMask); err != nil {
+		nlog.Warningln(name+": failed to watch", dir, "(polling only), err:", err)
+		return
+	}
+	w.dirs[dir] = struct{}{}
+}
+
+func (w *watcher) run() {
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		if _, err := w.f.Read(buf); err != nil {
+			return // (closed)
+		}
+		// any change in the watched directories; reload compares (mod-time, size) anyway
+		w.timer.Reset(watchDelay)
+	}
+}
+
+func (w *watcher) close() {
+	if w == nil {
+		return
+	}
+	w.f.Close()
+	w.timer.Stop()
+}
@@ -0,0 +1,50 @@
+// Package certloader loads and reloads X.509 certs.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package certloader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/tools/tassert"
+)
+
+func TestWatch(t *testing.T) {
+	cl, stats := newTestLoader(t)
+	cl.startWatch()
+	tassert.Fatalf(t, cl.watcher != nil, "expected inotify watcher")
+	defer cl.watcher.close()
+
+	prev := cl.xcert.Load()
+
+	// renewed in place (no hk)
+	time.Sleep(10 * time.Millisecond)
+	now := time.Now()
+	genCert(t, cl.certFile, cl.keyFile, now.Add(-time.Hour), now.Add(30*24*time.Hour))
+	waitReloaded(t, cl, prev)
+	tassert.Errorf(t, stats.Get(cos.CertReloadCount) == 2, "expected 2 (re)loads, got %d", stats.Get(cos.CertReloadCount))
+
+	// atomically replaced (rename), e.g. cert-manager
+	prev = cl.xcert.Load()
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	genCert(t, certFile, keyFile, now.Add(-time.Hour), now.Add(60*24*time.Hour))
+	tassert.CheckFatal(t, os.Rename(keyFile, cl.keyFile))
+	tassert.CheckFatal(t, os.Rename(certFile, cl.certFile))
+	waitReloaded(t, cl, prev)
+}
+
+func waitReloaded(t *testing.T, cl *certLoader, prev *xcert) {
+	deadline := time.Now().Add(5 * time.Second)
+	for cl.xcert.Load() == prev {
+		if time.Now().After(deadline) {
+			t.Fatal("certificate change not picked up")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+// Package certloader loads and reloads X.509 certs.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package certloader
+
+import "errors"
+
+type watcher struct{}
+
+func newWatcher(func()) (*watcher, error) { return nil, errors.New("not supported on this platform") }
+
+func (*watcher) add(string) {}
+func (*watcher) close()     {}
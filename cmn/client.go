@@ -62,6 +62,7 @@ type (
 		Certificate string
 		Key         string
 		SkipVerify  bool
+		SPIFFE      bool // intra-cluster: X.509 SVID and trust bundle (see certloader.InitSPIFFE)
 	}
 )
 
@@ -102,6 +103,9 @@ func NewTransport(cargs TransportArgs) *http.Transport {
 }
 
 func NewTLS(sargs TLSArgs, intra bool) (tlsConf *tls.Config, err error) {
+	if intra && sargs.SPIFFE {
+		return certloader.BuildClientTLSConfig(&certloader.TLSOpts{CAFile: sargs.ClientCA, SkipVerify: sargs.SkipVerify})
+	}
 	var pool *x509.CertPool
 	if sargs.ClientCA != "" {
 		cert, err := os.ReadFile(sargs.ClientCA)
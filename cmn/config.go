@@ -105,6 +105,7 @@ type (
 		Keepalive   KeepaliveConf   `json:"keepalivetracker"`
 		Rebalance   RebalanceConf   `json:"rebalance" allow:"cluster"`
 		Log         LogConf         `json:"log"`
+		Audit       AuditConf       `json:"audit"`
 		EC          ECConf          `json:"ec" allow:"cluster"`
 		Net         NetConf         `json:"net"`
 		Timeout     TimeoutConf     `json:"timeout"`
@@ -126,6 +127,8 @@ type (
 		Version    int64        `json:"config_version,string"`
 		Versioning VersionConf  `json:"versioning" allow:"cluster"`
 		Resilver   ResilverConf `json:"resilver"`
+		Sched      SchedConf    `json:"sched"`
+		Governor   GovernorConf `json:"governor"`
 	}
 	ConfigToSet struct {
 		// ClusterConfig
@@ -133,6 +136,7 @@ type (
 		Mirror      *MirrorConfToSet      `json:"mirror,omitempty"`
 		EC          *ECConfToSet          `json:"ec,omitempty"`
 		Log         *LogConfToSet         `json:"log,omitempty"`
+		Audit       *AuditConfToSet       `json:"audit,omitempty"`
 		Periodic    *PeriodConfToSet      `json:"periodic,omitempty"`
 		Tracing     *TracingConfToSet     `json:"tracing,omitempty"`
 		Timeout     *TimeoutConfToSet     `json:"timeout,omitempty"`
@@ -142,6 +146,8 @@ type (
 		Disk        *DiskConfToSet        `json:"disk,omitempty"`
 		Rebalance   *RebalanceConfToSet   `json:"rebalance,omitempty"`
 		Resilver    *ResilverConfToSet    `json:"resilver,omitempty"`
+		Sched       *SchedConfToSet       `json:"sched,omitempty"`
+		Governor    *GovernorConfToSet    `json:"governor,omitempty"`
 		Cksum       *CksumConfToSet       `json:"checksum,omitempty"`
 		Versioning  *VersionConfToSet     `json:"versioning,omitempty"`
 		Net         *NetConfToSet         `json:"net,omitempty"`
@@ -179,7 +185,8 @@ type (
 	}
 
 	ECConf struct {
-		Compression string `json:"compression"` // enum { CompressAlways, ... } in api/apc/compression.go
+		Compression string `json:"compression"`          // enum { CompressAlways, ... } in api/apc/compression.go
+		ZstdLevel   int    `json:"zstd_level,omitempty"` // when compressing: 0 - lz4, [1, 22] - zstd (see apc.MaxZstdLevel)
 
 		// ObjSizeLimit is object size threshold _separating_ intra-cluster mirroring from
 		// erasure coding.
@@ -214,6 +221,7 @@ type (
 	ECConfToSet struct {
 		ObjSizeLimit *int64  `json:"objsize_limit,omitempty"`
 		Compression  *string `json:"compression,omitempty"`
+		ZstdLevel    *int    `json:"zstd_level,omitempty"`
 		SbundleMult  *int    `json:"bundle_multiplier,omitempty"`
 		DataSlices   *int    `json:"data_slices,omitempty"`
 		ParitySlices *int    `json:"parity_slices,omitempty"`
@@ -238,6 +246,25 @@ type (
 		StatsTime *cos.Duration `json:"stats_time,omitempty"`
 	}
 
+	// audit log of data- and control-plane operations (see cmn/audit)
+	// - enabled: all operations; otherwise, only operations on the buckets with `audit.enabled` property;
+	// - events are written to the local file (audit.log in the node's log directory) that gets
+	//   rotated upon reaching max_size, or to syslog
+	AuditConf struct {
+		Format   string      `json:"format"`    // "json" (JSON lines) or "cef" (Common Event Format); default: json
+		Syslog   string      `json:"syslog"`    // "local", "udp://host:port", or "tcp://host:port"; empty: local file
+		MaxSize  cos.SizeIEC `json:"max_size"`  // file: exceeding this size triggers rotation; default: 64MiB
+		MaxFiles int         `json:"max_files"` // file: number of rotated files to keep; default: 8
+		Enabled  bool        `json:"enabled"`
+	}
+	AuditConfToSet struct {
+		Format   *string      `json:"format,omitempty"`
+		Syslog   *string      `json:"syslog,omitempty"`
+		MaxSize  *cos.SizeIEC `json:"max_size,omitempty"`
+		MaxFiles *int         `json:"max_files,omitempty"`
+		Enabled  *bool        `json:"enabled,omitempty"`
+	}
+
 	// TracingConf defines the configuration used for the OpenTelemetry (OTEL) trace exporter.
 	// It includes settings for enabling tracing, sampling ratio, exporter endpoint, and other
 	// parameters necessary for distributed tracing in AIStore.
@@ -354,12 +381,30 @@ type (
 		// Out-of-Space: if exceeded, the target starts failing new PUTs and keeps
 		// failing them until its local used-cap gets back below HighWM (see above)
 		OOS int64 `json:"out_of_space"`
+
+		// ExpireTime: interval between periodic scans that remove expired objects (see cmn.ExpiresObjMD)
+		// and enforce bucket lifecycle rules (see cmn.LifecycleConf);
+		// zero disables periodic scans (apc.ActExpireObjs and apc.ActLifecycle can still be started via API)
+		ExpireTime cos.Duration `json:"expire_time,omitempty"`
+
+		// TrashTime: soft-delete retention - deleted objects remain restorable (apc.ActUndelete)
+		// for the specified time and get purged afterwards (see fs.TrashType);
+		// zero: delete objects right away
+		TrashTime cos.Duration `json:"trash_time,omitempty"`
+
+		// QuotaTime: interval between periodic recomputations of the bucket and user usage
+		// that proxies check against quotas (see QuotaConf and apc.ActQuotaUsage);
+		// zero disables periodic recomputation
+		QuotaTime cos.Duration `json:"quota_time,omitempty"`
 	}
 	SpaceConfToSet struct {
-		CleanupWM *int64 `json:"cleanupwm,omitempty"`
-		LowWM     *int64 `json:"lowwm,omitempty"`
-		HighWM    *int64 `json:"highwm,omitempty"`
-		OOS       *int64 `json:"out_of_space,omitempty"`
+		CleanupWM  *int64        `json:"cleanupwm,omitempty"`
+		LowWM      *int64        `json:"lowwm,omitempty"`
+		HighWM     *int64        `json:"highwm,omitempty"`
+		OOS        *int64        `json:"out_of_space,omitempty"`
+		ExpireTime *cos.Duration `json:"expire_time,omitempty"`
+		TrashTime  *cos.Duration `json:"trash_time,omitempty"`
+		QuotaTime  *cos.Duration `json:"quota_time,omitempty"`
 	}
 
 	LRUConf struct {
@@ -395,16 +440,20 @@ type (
 	}
 
 	RebalanceConf struct {
-		Compression   string       `json:"compression"`       // enum { CompressAlways, ... } in api/apc/compression.go
-		DestRetryTime cos.Duration `json:"dest_retry_time"`   // max wait for ACKs & neighbors to complete
-		SbundleMult   int          `json:"bundle_multiplier"` // stream-bundle multiplier: num streams to destination
-		Enabled       bool         `json:"enabled"`           // true=auto-rebalance | manual rebalancing
+		Compression   string       `json:"compression"`          // enum { CompressAlways, ... } in api/apc/compression.go
+		DestRetryTime cos.Duration `json:"dest_retry_time"`      // max wait for ACKs & neighbors to complete
+		SbundleMult   int          `json:"bundle_multiplier"`    // stream-bundle multiplier: num streams to destination
+		Enabled       bool         `json:"enabled"`              // true=auto-rebalance | manual rebalancing
+		QUIC          bool         `json:"quic,omitempty"`       // QUIC (HTTP/3) streams (requires transport.quic)
+		ZstdLevel     int          `json:"zstd_level,omitempty"` // when compressing: 0 - lz4, [1, 22] - zstd
 	}
 	RebalanceConfToSet struct {
 		DestRetryTime *cos.Duration `json:"dest_retry_time,omitempty"`
 		Compression   *string       `json:"compression,omitempty"`
 		SbundleMult   *int          `json:"bundle_multiplier"`
 		Enabled       *bool         `json:"enabled,omitempty"`
+		QUIC          *bool         `json:"quic,omitempty"`
+		ZstdLevel     *int          `json:"zstd_level,omitempty"`
 	}
 
 	ResilverConf struct {
@@ -414,6 +463,32 @@ type (
 		Enabled *bool `json:"enabled,omitempty"`
 	}
 
+	// time windows for background xactions (see xreg/sched.go)
+	SchedConf struct {
+		Windows []SchedWindow `json:"windows,omitempty"`
+	}
+	SchedConfToSet struct {
+		Windows *[]SchedWindow `json:"windows,omitempty"`
+	}
+	// per-target resource governor for background xactions (see fs/mpather/governor.go)
+	GovernorConf struct {
+		Weights    map[string]int `json:"weights,omitempty"`        // xaction kind => relative weight (default 1)
+		MaxJoggers int            `json:"max_joggers,omitempty"`    // aggregate number of visiting joggers; zero - unlimited
+		Bandwidth  cos.SizeIEC    `json:"disk_bandwidth,omitempty"` // aggregate disk bandwidth (bytes per second); zero - unlimited
+	}
+	GovernorConfToSet struct {
+		Weights    *map[string]int `json:"weights,omitempty"`
+		MaxJoggers *int            `json:"max_joggers,omitempty"`
+		Bandwidth  *cos.SizeIEC    `json:"disk_bandwidth,omitempty"`
+	}
+	SchedWindow struct {
+		Kind  string `json:"kind"`            // xaction kind or display name, e.g. "resilver", "copy-bucket"
+		Begin string `json:"begin"`           // local time of day "HH:MM" (evaluated by each target in its own time zone)
+		End   string `json:"end"`             // ditto; wraps around midnight when less than Begin; same as Begin - entire day
+		Days  string `json:"days,omitempty"`  // e.g. "mon-fri", "sat,sun"; empty - every day
+		Start bool   `json:"start,omitempty"` // start upon entering the window (otherwise, only pause outside and resume inside)
+	}
+
 	CksumConf struct {
 		// (note that `ChecksumNone` ("none") disables checksumming)
 		Type string `json:"type"`
@@ -502,6 +577,43 @@ type (
 		UseHTTPS        bool `json:"use_https"`         // use HTTPS
 		SkipVerifyCrt   bool `json:"skip_verify"`       // skip X.509 cert verification (used with self-signed certs)
 		Chunked         bool `json:"chunked_transfer"`  // (https://tools.ietf.org/html/rfc7230#page-36; not used since 02/23)
+		// X.509 key policy: reject (at load and reload time) certificates with weak or non-approved keys
+		// (see certloader.KeyPolicy); empty/zero - no restrictions
+		KeyTypes   string `json:"key_types,omitempty"`    // comma-separated allowed key types, e.g. "rsa,ecdsa"
+		KeyCurves  string `json:"key_curves,omitempty"`   // comma-separated allowed ECDSA curves, e.g. "P-256,P-384"
+		MinRSABits int    `json:"min_rsa_bits,omitempty"` // minimum RSA key size, e.g. 2048
+		// (optional) file with the certificate's generation (e.g., a counter) that an external agent updates
+		// upon rotating the certificate - to trigger reloading regardless of the certificate file's mod-time
+		CertGenFile string `json:"server_crt_gen,omitempty"`
+		// ACME (e.g., Let's Encrypt): automatic certificate provisioning and renewal
+		// in place of (server_crt, server_key) - see certloader.ACMEOpts
+		ACMEDomains   string `json:"acme_domains,omitempty"`   // comma-separated; the first is the default (no SNI)
+		ACMEDirectory string `json:"acme_directory,omitempty"` // directory URL; empty - Let's Encrypt
+		ACMEEmail     string `json:"acme_email,omitempty"`     // (optional) contact
+		ACMECacheDir  string `json:"acme_cache_dir,omitempty"` // account key and certificates; empty - <confdir>/acme
+		// Vault PKI secrets engine: short-lived certificates issued (and rotated) by Vault
+		// in place of (server_crt, server_key) - see certloader.VaultOpts
+		VaultAddr       string       `json:"vault_addr,omitempty"`        // e.g. "https://vault.example.com:8200"
+		VaultPKIPath    string       `json:"vault_pki_path,omitempty"`    // <mount>/issue/<role>
+		VaultTokenFile  string       `json:"vault_token_file,omitempty"`  // empty - VAULT_TOKEN environment
+		VaultCommonName string       `json:"vault_common_name,omitempty"` // requested CN
+		VaultAltNames   string       `json:"vault_alt_names,omitempty"`   // comma-separated DNS names and/or IPs
+		VaultCACert     string       `json:"vault_ca_crt,omitempty"`      // to verify Vault server; empty - system pool
+		VaultTTL        cos.Duration `json:"vault_ttl,omitempty"`         // zero - the role's default
+		// revocation (see certloader/revoke.go)
+		OCSPStaple bool   `json:"ocsp_staple,omitempty"` // staple OCSP response for the server certificate
+		ClientCRL  string `json:"client_crl,omitempty"`  // mTLS: CRL file to check client certificates against
+		ClientOCSP bool   `json:"client_ocsp,omitempty"` // mTLS: check client certificates via OCSP
+		// additional certificates selected by the server name (SNI) clients ask for (see certloader.AddSNI)
+		SNICerts []SNICertConf `json:"sni_certs,omitempty"`
+		// SPIFFE: X.509 SVIDs from the (SPIRE) agent via Workload API in place of (server_crt, server_key);
+		// the trust bundle verifies peers unless client_ca_tls is configured (see certloader.SPIFFEOpts)
+		SPIFFESocket string `json:"spiffe_socket,omitempty"` // e.g. "unix:///run/spire/sockets/agent.sock"
+		SPIFFEID     string `json:"spiffe_id,omitempty"`     // when the workload gets several SVIDs; empty - the first
+	}
+	SNICertConf struct {
+		Certificate string `json:"server_crt"`
+		CertKey     string `json:"server_key"`
 	}
 	HTTPConfToSet struct {
 		Certificate   *string `json:"server_crt,omitempty"`
@@ -519,6 +631,34 @@ type (
 		UseHTTPS        *bool `json:"use_https,omitempty"`
 		SkipVerifyCrt   *bool `json:"skip_verify,omitempty"`
 		Chunked         *bool `json:"chunked_transfer,omitempty"`
+		// key policy
+		KeyTypes   *string `json:"key_types,omitempty" list:"readonly"`
+		KeyCurves  *string `json:"key_curves,omitempty" list:"readonly"`
+		MinRSABits *int    `json:"min_rsa_bits,omitempty" list:"readonly"`
+		// generation file
+		CertGenFile *string `json:"server_crt_gen,omitempty" list:"readonly"`
+		// ACME
+		ACMEDomains   *string `json:"acme_domains,omitempty" list:"readonly"`
+		ACMEDirectory *string `json:"acme_directory,omitempty" list:"readonly"`
+		ACMEEmail     *string `json:"acme_email,omitempty" list:"readonly"`
+		ACMECacheDir  *string `json:"acme_cache_dir,omitempty" list:"readonly"`
+		// Vault
+		VaultAddr       *string       `json:"vault_addr,omitempty" list:"readonly"`
+		VaultPKIPath    *string       `json:"vault_pki_path,omitempty" list:"readonly"`
+		VaultTokenFile  *string       `json:"vault_token_file,omitempty" list:"readonly"`
+		VaultCommonName *string       `json:"vault_common_name,omitempty" list:"readonly"`
+		VaultAltNames   *string       `json:"vault_alt_names,omitempty" list:"readonly"`
+		VaultCACert     *string       `json:"vault_ca_crt,omitempty" list:"readonly"`
+		VaultTTL        *cos.Duration `json:"vault_ttl,omitempty" list:"readonly"`
+		// revocation
+		OCSPStaple *bool   `json:"ocsp_staple,omitempty" list:"readonly"`
+		ClientCRL  *string `json:"client_crl,omitempty" list:"readonly"`
+		ClientOCSP *bool   `json:"client_ocsp,omitempty" list:"readonly"`
+		// SNI
+		SNICerts *[]SNICertConf `json:"sni_certs,omitempty" list:"readonly"`
+		// SPIFFE
+		SPIFFESocket *string `json:"spiffe_socket,omitempty" list:"readonly"`
+		SPIFFEID     *string `json:"spiffe_id,omitempty" list:"readonly"`
 	}
 
 	FSHCConf struct {
@@ -626,6 +766,24 @@ type (
 		// fastcompression.blogspot.com/2013/04/lz4-streaming-format-final.html
 		LZ4BlockMaxSize  cos.SizeIEC `json:"lz4_block"`
 		LZ4FrameChecksum bool        `json:"lz4_frame_checksum"`
+		// targets listen (UDP, intra-cluster data port) and accept QUIC (HTTP/3) streams -
+		// to be used by the stream bundles configured to do so (e.g., rebalance.quic, tcb.quic)
+		QUIC bool `json:"quic,omitempty"`
+		// adaptive stream multiplier: when greater than a given bundle's multiplier (e.g., rebalance.bundle_multiplier),
+		// the number of streams per destination grows and shrinks between the two based on observed throughput
+		// and latency; 0 (zero) - disabled
+		MaxSbundleMult int `json:"max_bundle_multiplier,omitempty"`
+		// cluster-shared secret to encrypt (AES-256-GCM) all intra-cluster streams - rebalance, copy-bucket,
+		// EC, etc. - independently of net.http.use_https; requires node clocks synchronized within 5 minutes
+		// (anti-replay); empty - disabled (see also: transport/crypt.go)
+		Secret string `json:"secret,omitempty"`
+		// checksum (xxhash) the payload of each PDU - all intra-cluster streams become PDU-based;
+		// receivers skip objects with corrupted PDUs and request retransmission (see also: bundle.DataMover)
+		CksumPDU bool `json:"pdu_checksum,omitempty"`
+		// QoS: the node's egress budget (bytes/s) for all intra-cluster streams; when non-zero, streams of
+		// lower priority (e.g., rebalance, copy-bucket) yield to higher-priority ones (e.g., GET-triggered);
+		// 0 (zero) - disabled
+		QoSBandwidth cos.SizeIEC `json:"qos_bandwidth,omitempty"`
 	}
 	TransportConfToSet struct {
 		MaxHeaderSize    *int          `json:"max_header,omitempty"`
@@ -634,6 +792,11 @@ type (
 		QuiesceTime      *cos.Duration `json:"quiescent,omitempty"`
 		LZ4BlockMaxSize  *cos.SizeIEC  `json:"lz4_block,omitempty"`
 		LZ4FrameChecksum *bool         `json:"lz4_frame_checksum,omitempty"`
+		QUIC             *bool         `json:"quic,omitempty"`
+		MaxSbundleMult   *int          `json:"max_bundle_multiplier,omitempty"`
+		Secret           *string       `json:"secret,omitempty"`
+		CksumPDU         *bool         `json:"pdu_checksum,omitempty"`
+		QoSBandwidth     *cos.SizeIEC  `json:"qos_bandwidth,omitempty"`
 	}
 
 	MemsysConf struct {
@@ -654,12 +817,60 @@ type (
 	}
 
 	TCBConf struct {
-		Compression string `json:"compression"`       // enum { CompressAlways, ... } in api/apc/compression.go
-		SbundleMult int    `json:"bundle_multiplier"` // stream-bundle multiplier: num streams to destination
+		Compression string `json:"compression"`          // enum { CompressAlways, ... } in api/apc/compression.go
+		SbundleMult int    `json:"bundle_multiplier"`    // stream-bundle multiplier: num streams to destination
+		QUIC        bool   `json:"quic,omitempty"`       // QUIC (HTTP/3) streams (requires transport.quic)
+		ZstdLevel   int    `json:"zstd_level,omitempty"` // when compressing: 0 - lz4, [1, 22] - zstd
+
+		// transform (ETL) bucket to bucket: separately configurable compression and stream-bundle multiplier;
+		// empty (zero) - same as copying (above); see also apc.CopyBckMsg (per-request overrides)
+		ETLCompression string `json:"etl_compression,omitempty"`
+		ETLSbundleMult int    `json:"etl_bundle_multiplier,omitempty"`
+
+		// transform (ETL) bucket to bucket: bounds of the auto-tuned number of objects transformed in parallel
+		// (per mountpath), depending on transformer latency, disk utilization, and destination backpressure
+		// (0 - use defaults, see xs/tcb)
+		ETLMinParallel int `json:"etl_min_parallel,omitempty"`
+		ETLMaxParallel int `json:"etl_max_parallel,omitempty"`
+
+		// per-mountpath read buffer size, depending on the underlying media:
+		// spinning disks benefit from larger sequential reads (0 - use defaults, see xs/tcb)
+		BufSizeHDD cos.SizeIEC `json:"buf_size_hdd,omitempty"`
+		BufSizeSSD cos.SizeIEC `json:"buf_size_ssd,omitempty"`
+
+		// total size of the read buffers that all bucket copies (and transformations) running on a given
+		// target may draw from memsys at any point in time; copies queue when the budget is exhausted
+		// (0 - unlimited)
+		BufBudget cos.SizeIEC `json:"buf_budget,omitempty"`
+
+		// throughput window to compute adaptive estimated time remaining (0 - use default, see xs/tcb)
+		EtaWindow cos.Duration `json:"eta_window,omitempty"`
+
+		// per-object retries upon transient (e.g., remote backend) errors: max number of retries (0 - no retries),
+		// and the backoff that doubles with each retry, up to the max (0 - use defaults, see xs/tcb)
+		Retries         int          `json:"retries,omitempty"`
+		RetryBackoff    cos.Duration `json:"retry_backoff,omitempty"`
+		RetryMaxBackoff cos.Duration `json:"retry_max_backoff,omitempty"`
 	}
 	TCBConfToSet struct {
-		Compression *string `json:"compression,omitempty"`
-		SbundleMult *int    `json:"bundle_multiplier,omitempty"`
+		Compression *string      `json:"compression,omitempty"`
+		SbundleMult *int         `json:"bundle_multiplier,omitempty"`
+		BufSizeHDD  *cos.SizeIEC `json:"buf_size_hdd,omitempty"`
+		BufSizeSSD  *cos.SizeIEC `json:"buf_size_ssd,omitempty"`
+		BufBudget   *cos.SizeIEC `json:"buf_budget,omitempty"`
+		QUIC        *bool        `json:"quic,omitempty"`
+		ZstdLevel   *int         `json:"zstd_level,omitempty"`
+
+		ETLCompression *string `json:"etl_compression,omitempty"`
+		ETLSbundleMult *int    `json:"etl_bundle_multiplier,omitempty"`
+		ETLMinParallel *int    `json:"etl_min_parallel,omitempty"`
+		ETLMaxParallel *int    `json:"etl_max_parallel,omitempty"`
+
+		EtaWindow *cos.Duration `json:"eta_window,omitempty"`
+
+		Retries         *int          `json:"retries,omitempty"`
+		RetryBackoff    *cos.Duration `json:"retry_backoff,omitempty"`
+		RetryMaxBackoff *cos.Duration `json:"retry_max_backoff,omitempty"`
 	}
 
 	WritePolicyConf struct {
@@ -674,7 +885,7 @@ type (
 
 // assorted named fields that require (cluster | node) restart for changes to make an effect
 // (used by CLI)
-var ConfigRestartRequired = [...]string{"auth.secret", "memsys", "net"}
+var ConfigRestartRequired = [...]string{"auth.secret", "memsys", "net", "transport.quic", "transport.secret"}
 
 //
 // config meta-versioning & serialization
@@ -712,6 +923,8 @@ var (
 	_ Validator = (*ClientConf)(nil)
 	_ Validator = (*RebalanceConf)(nil)
 	_ Validator = (*ResilverConf)(nil)
+	_ Validator = (*SchedConf)(nil)
+	_ Validator = (*GovernorConf)(nil)
 	_ Validator = (*NetConf)(nil)
 	_ Validator = (*FSHCConf)(nil)
 	_ Validator = (*HTTPConf)(nil)
@@ -722,6 +935,7 @@ var (
 	_ Validator = (*TCBConf)(nil)
 	_ Validator = (*WritePolicyConf)(nil)
 	_ Validator = (*TracingConf)(nil)
+	_ Validator = (*AuditConf)(nil)
 
 	_ PropsValidator = (*CksumConf)(nil)
 	_ PropsValidator = (*SpaceConf)(nil)
@@ -854,6 +1068,38 @@ func (c *PeriodConf) Validate() error {
 // LogConf //
 /////////////
 
+///////////////
+// AuditConf //
+///////////////
+
+const (
+	AuditFormatJSON  = "json"
+	AuditFormatCEF   = "cef"
+	AuditSyslogLocal = "local"
+)
+
+func (c *AuditConf) Validate() error {
+	switch c.Format {
+	case "", AuditFormatJSON, AuditFormatCEF:
+	default:
+		return fmt.Errorf("invalid audit.format %q (expecting %q or %q)", c.Format, AuditFormatJSON, AuditFormatCEF)
+	}
+	if c.Syslog != "" && c.Syslog != AuditSyslogLocal {
+		u, err := url.Parse(c.Syslog)
+		if err != nil || (u.Scheme != "udp" && u.Scheme != "tcp") || u.Host == "" {
+			return fmt.Errorf("invalid audit.syslog %q (expecting %q, \"udp://host:port\", or \"tcp://host:port\")",
+				c.Syslog, AuditSyslogLocal)
+		}
+	}
+	if c.MaxSize != 0 && (c.MaxSize < cos.KiB || c.MaxSize > 10*cos.GiB) {
+		return fmt.Errorf("invalid audit.max_size=%s (expected range [1KB, 10GB])", c.MaxSize)
+	}
+	if c.MaxFiles < 0 || c.MaxFiles > 1000 {
+		return fmt.Errorf("invalid audit.max_files=%d (expected range [0, 1000])", c.MaxFiles)
+	}
+	return nil
+}
+
 func (c *LogConf) Validate() error {
 	if err := c.Level.Validate(); err != nil {
 		return err
@@ -949,7 +1195,7 @@ func (c *BackendConf) Validate() (err error) {
 func (c *BackendConf) setProvider(provider string) {
 	var ns Ns
 	switch provider {
-	case apc.AWS, apc.Azure, apc.GCP, apc.OCI, apc.HT:
+	case apc.AWS, apc.Azure, apc.GCP, apc.OCI, apc.B2, apc.WebDAV, apc.FTP, apc.POSIX, apc.HT:
 		ns = NsGlobal
 	default:
 		debug.Assert(false, "unknown backend provider "+provider)
@@ -1039,14 +1285,23 @@ func (c *SpaceConf) Validate() (err error) {
 	if c.CleanupWM <= 0 || c.LowWM < c.CleanupWM || c.HighWM < c.LowWM || c.OOS < c.HighWM || c.OOS > 100 {
 		err = fmt.Errorf("invalid %s (expecting: 0 < cleanup < low < high < OOS < 100)", c)
 	}
+	if err == nil && c.ExpireTime != 0 && c.ExpireTime.D() < time.Minute {
+		err = fmt.Errorf("invalid %s (expecting: expire_time >= 1m or zero (disabled))", c)
+	}
+	if err == nil && c.TrashTime != 0 && c.TrashTime.D() < time.Minute {
+		err = fmt.Errorf("invalid %s (expecting: trash_time >= 1m or zero (disabled))", c)
+	}
+	if err == nil && c.QuotaTime != 0 && c.QuotaTime.D() < time.Minute {
+		err = fmt.Errorf("invalid %s (expecting: quota_time >= 1m or zero (disabled))", c)
+	}
 	return
 }
 
 func (c *SpaceConf) ValidateAsProps(...any) error { return c.Validate() }
 
 func (c *SpaceConf) String() string {
-	return fmt.Sprintf("space config: cleanup=%d%%, low=%d%%, high=%d%%, OOS=%d%%",
-		c.CleanupWM, c.LowWM, c.HighWM, c.OOS)
+	return fmt.Sprintf("space config: cleanup=%d%%, low=%d%%, high=%d%%, OOS=%d%%, expire=%v, trash=%v, quota=%v",
+		c.CleanupWM, c.LowWM, c.HighWM, c.OOS, c.ExpireTime, c.TrashTime, c.QuotaTime)
 }
 
 /////////////
@@ -1188,6 +1443,9 @@ func (c *ECConf) Validate() error {
 	if !apc.IsValidCompression(c.Compression) {
 		return fmt.Errorf("invalid ec.compression: %q (expecting one of: %v)", c.Compression, apc.SupportedCompression)
 	}
+	if !apc.IsValidZstdLevel(c.ZstdLevel) {
+		return fmt.Errorf("invalid ec.zstd_level: %d (expected range [0, %d])", c.ZstdLevel, apc.MaxZstdLevel)
+	}
 	return nil
 }
 
@@ -1317,6 +1575,38 @@ func (c *HTTPConf) Validate() error {
 	if n := c.MaxIdleConns; n < 0 || n > 1000 {
 		return fmt.Errorf("invalid idle_conns %d (expecting range [0 - %d])", n, 1000)
 	}
+	if c.MinRSABits < 0 {
+		return fmt.Errorf("invalid min_rsa_bits %d (expecting non-negative)", c.MinRSABits)
+	}
+	if c.ACMEDomains != "" && !c.UseHTTPS {
+		return fmt.Errorf("invalid acme_domains %q: requires use_https", c.ACMEDomains)
+	}
+	if c.VaultAddr != "" {
+		switch {
+		case !c.UseHTTPS:
+			return fmt.Errorf("invalid vault_addr %q: requires use_https", c.VaultAddr)
+		case c.ACMEDomains != "":
+			return errors.New("vault_addr and acme_domains are mutually exclusive")
+		case c.VaultPKIPath == "" || c.VaultCommonName == "":
+			return fmt.Errorf("vault_addr %q requires vault_pki_path and vault_common_name", c.VaultAddr)
+		}
+	}
+	if c.VaultTTL < 0 {
+		return fmt.Errorf("invalid vault_ttl %v (expecting non-negative)", c.VaultTTL)
+	}
+	if c.SPIFFESocket != "" {
+		switch {
+		case !c.UseHTTPS:
+			return fmt.Errorf("invalid spiffe_socket %q: requires use_https", c.SPIFFESocket)
+		case c.ACMEDomains != "" || c.VaultAddr != "":
+			return errors.New("spiffe_socket, vault_addr, and acme_domains are mutually exclusive")
+		}
+	}
+	for i := range c.SNICerts {
+		if sni := &c.SNICerts[i]; sni.Certificate == "" || sni.CertKey == "" {
+			return fmt.Errorf("invalid sni_certs[%d]: (%q, %q) - expecting both server_crt and server_key", i, sni.Certificate, sni.CertKey)
+		}
+	}
 	return nil
 }
 
@@ -1327,6 +1617,7 @@ func (c *HTTPConf) ToTLS() TLSArgs {
 		Key:         c.CertKey,
 		ClientCA:    c.ClientCA,
 		SkipVerify:  c.SkipVerifyCrt,
+		SPIFFE:      c.SPIFFESocket != "",
 	}
 }
 
@@ -1688,6 +1979,8 @@ const (
 
 	DfltTransportBurst = 256
 	MaxTransportBurst  = 4096
+
+	MinTransportSecret = 16
 )
 
 // NOTE: uncompressed block sizes - the enum currently supported by the github.com/pierrec/lz4
@@ -1713,6 +2006,15 @@ func (c *TransportConf) Validate() (err error) {
 	if c.QuiesceTime.D() < 8*time.Second {
 		return fmt.Errorf("invalid transport.quiescent: %v (expecting >= 8s)", c.QuiesceTime)
 	}
+	if c.MaxSbundleMult < 0 || c.MaxSbundleMult > apc.MaxSbundleMult {
+		return fmt.Errorf("invalid transport.max_bundle_multiplier: %d (expected range [0, %d])", c.MaxSbundleMult, apc.MaxSbundleMult)
+	}
+	if c.QoSBandwidth < 0 {
+		return fmt.Errorf("invalid transport.qos_bandwidth: %d (expecting non-negative)", c.QoSBandwidth)
+	}
+	if c.Secret != "" && len(c.Secret) < MinTransportSecret {
+		return fmt.Errorf("invalid transport.secret: expecting at least %d characters", MinTransportSecret)
+	}
 	return nil
 }
 
@@ -1728,6 +2030,46 @@ func (c *TCBConf) Validate() error {
 		return fmt.Errorf("invalid tcb.compression: %q (expecting one of: %v)",
 			c.Compression, apc.SupportedCompression)
 	}
+	if !apc.IsValidZstdLevel(c.ZstdLevel) {
+		return fmt.Errorf("invalid tcb.zstd_level: %d (expected range [0, %d])", c.ZstdLevel, apc.MaxZstdLevel)
+	}
+	if c.ETLSbundleMult < 0 || c.ETLSbundleMult > 16 {
+		return fmt.Errorf("invalid tcb.etl_bundle_multiplier: %v (expected range [0, 16])", c.ETLSbundleMult)
+	}
+	if !apc.IsValidCompression(c.ETLCompression) {
+		return fmt.Errorf("invalid tcb.etl_compression: %q (expecting one of: %v)",
+			c.ETLCompression, apc.SupportedCompression)
+	}
+	const maxParallel = 64
+	if c.ETLMinParallel < 0 || c.ETLMinParallel > maxParallel {
+		return fmt.Errorf("invalid tcb.etl_min_parallel: %d (expected range [0, %d])", c.ETLMinParallel, maxParallel)
+	}
+	if c.ETLMaxParallel < 0 || c.ETLMaxParallel > maxParallel {
+		return fmt.Errorf("invalid tcb.etl_max_parallel: %d (expected range [0, %d])", c.ETLMaxParallel, maxParallel)
+	}
+	if c.ETLMinParallel > 0 && c.ETLMaxParallel > 0 && c.ETLMinParallel > c.ETLMaxParallel {
+		return fmt.Errorf("invalid tcb.etl_min_parallel %d > tcb.etl_max_parallel %d", c.ETLMinParallel, c.ETLMaxParallel)
+	}
+	const maxBufSize = 16 * cos.MiB
+	if c.BufSizeHDD < 0 || c.BufSizeHDD > maxBufSize {
+		return fmt.Errorf("invalid tcb.buf_size_hdd: %s (expected range [0, %s])", c.BufSizeHDD, cos.ToSizeIEC(maxBufSize, 0))
+	}
+	if c.BufSizeSSD < 0 || c.BufSizeSSD > maxBufSize {
+		return fmt.Errorf("invalid tcb.buf_size_ssd: %s (expected range [0, %s])", c.BufSizeSSD, cos.ToSizeIEC(maxBufSize, 0))
+	}
+	if c.BufBudget < 0 {
+		return fmt.Errorf("invalid tcb.buf_budget: %s (expecting non-negative)", c.BufBudget)
+	}
+	if c.EtaWindow < 0 {
+		return fmt.Errorf("invalid tcb.eta_window: %s (expecting non-negative)", c.EtaWindow)
+	}
+	if c.Retries < 0 || c.Retries > 16 {
+		return fmt.Errorf("invalid tcb.retries: %d (expected range [0, 16])", c.Retries)
+	}
+	if c.RetryBackoff < 0 || c.RetryMaxBackoff < 0 {
+		return fmt.Errorf("invalid tcb.retry_backoff %s, tcb.retry_max_backoff %s (expecting non-negative)",
+			c.RetryBackoff, c.RetryMaxBackoff)
+	}
 	return nil
 }
 
@@ -1802,6 +2144,9 @@ func (c *RebalanceConf) Validate() error {
 		return fmt.Errorf("invalid rebalance.compression: %q (expecting one of: %v)",
 			c.Compression, apc.SupportedCompression)
 	}
+	if !apc.IsValidZstdLevel(c.ZstdLevel) {
+		return fmt.Errorf("invalid rebalance.zstd_level: %d (expected range [0, %d])", c.ZstdLevel, apc.MaxZstdLevel)
+	}
 	return nil
 }
 
@@ -1821,6 +2166,136 @@ func (c *ResilverConf) String() string {
 	return "Disabled"
 }
 
+///////////////
+// SchedConf //
+///////////////
+
+var weekdays = [...]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+func (c *SchedConf) Validate() error {
+	for i := range c.Windows {
+		w := &c.Windows[i]
+		if w.Kind == "" {
+			return fmt.Errorf("invalid sched.windows[%d]: missing xaction kind", i)
+		}
+		if _, err := parseHHMM(w.Begin); err != nil {
+			return fmt.Errorf("invalid sched.windows[%d] begin: %q (expecting HH:MM)", i, w.Begin)
+		}
+		if _, err := parseHHMM(w.End); err != nil {
+			return fmt.Errorf("invalid sched.windows[%d] end: %q (expecting HH:MM)", i, w.End)
+		}
+		if _, err := parseWeekdays(w.Days); err != nil {
+			return fmt.Errorf("invalid sched.windows[%d] days: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func (w *SchedWindow) String() string {
+	s := w.Kind + "[" + w.Begin + "-" + w.End
+	if w.Days != "" {
+		s += " " + w.Days
+	}
+	return s + "]"
+}
+
+// NOTE: expecting validated window; for a window that wraps around midnight,
+// the days (if specified) refer to the day the window begins
+func (w *SchedWindow) Contains(now time.Time) bool {
+	var (
+		in          bool
+		begin, _    = parseHHMM(w.Begin)
+		end, _      = parseHHMM(w.End)
+		days, _     = parseWeekdays(w.Days)
+		day         = now.Weekday()
+		minOfTheDay = now.Hour()*60 + now.Minute()
+	)
+	switch {
+	case begin == end:
+		in = true
+	case begin < end:
+		in = minOfTheDay >= begin && minOfTheDay < end
+	case minOfTheDay < end:
+		in, day = true, (day+6)%7 // (yesterday's)
+	default:
+		in = minOfTheDay >= begin
+	}
+	return in && (days == 0 || days&(1<<day) != 0)
+}
+
+// minutes since midnight
+func parseHHMM(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// comma-separated days and/or ranges of days (e.g. "mon-fri,sun") => bitmask
+func parseWeekdays(s string) (mask uint8, _ error) {
+	if s == "" {
+		return 0, nil
+	}
+	day := func(d string) (int, error) {
+		d = strings.ToLower(strings.TrimSpace(d))
+		for i, wd := range weekdays {
+			if d == wd {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("invalid day %q (expecting one of: %v)", d, weekdays)
+	}
+	for _, part := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		i, err := day(from)
+		if err != nil {
+			return 0, err
+		}
+		j := i
+		if isRange {
+			if j, err = day(to); err != nil {
+				return 0, err
+			}
+		}
+		for k := i; ; k = (k + 1) % len(weekdays) {
+			mask |= 1 << k
+			if k == j {
+				break
+			}
+		}
+	}
+	return mask, nil
+}
+
+//////////////////
+// GovernorConf //
+//////////////////
+
+const MaxGovernorWeight = 100
+
+func (c *GovernorConf) Validate() error {
+	if c.MaxJoggers < 0 {
+		return fmt.Errorf("invalid governor.max_joggers: %d (expecting non-negative)", c.MaxJoggers)
+	}
+	if c.Bandwidth < 0 {
+		return fmt.Errorf("invalid governor.disk_bandwidth: %s (expecting non-negative)", c.Bandwidth)
+	}
+	for kind, w := range c.Weights {
+		if w < 1 || w > MaxGovernorWeight {
+			return fmt.Errorf("invalid governor.weights[%s]: %d (expected range [1, %d])", kind, w, MaxGovernorWeight)
+		}
+	}
+	return nil
+}
+
+func (c *GovernorConf) Weight(kind string) int {
+	if w, ok := c.Weights[kind]; ok {
+		return w
+	}
+	return 1
+}
+
 ///////////////////
 // Tracing Conf //
 /////////////////
@@ -26,3 +26,12 @@ func Stat(path string) error {
 	var sys syscall.Stat_t
 	return syscall.Stat(path, &sys)
 }
+
+// number of hard links to a given file
+func NumLinks(path string) (uint64, error) {
+	var sys syscall.Stat_t
+	if err := syscall.Stat(path, &sys); err != nil {
+		return 0, err
+	}
+	return uint64(sys.Nlink), nil
+}
@@ -78,10 +78,19 @@ const (
 	S3ChecksumSHA1   = "x-amz-checksum-sha1"
 	S3ChecksumSHA256 = "x-amz-checksum-sha256"
 
+	// https://docs.aws.amazon.com/AmazonS3/latest/userguide/checking-object-integrity.html
+	S3HdrChecksumAlgo    = "x-amz-checksum-algorithm"     // CreateMultipartUpload
+	S3HdrSdkChecksumAlgo = "x-amz-sdk-checksum-algorithm" // UploadPart (SDK-computed, trailing)
+
 	S3MetadataChecksumType = "x-amz-meta-ais-cksum-type"
 	S3MetadataChecksumVal  = "x-amz-meta-ais-cksum-val"
 
 	S3LastModified = "Last-Modified"
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/userguide/ServerSideEncryptionCustomerKeys.html
+	S3HdrSSECustomerAlgo   = "x-amz-server-side-encryption-customer-algorithm"
+	S3HdrSSECustomerKey    = "x-amz-server-side-encryption-customer-key"
+	S3HdrSSECustomerKeyMD5 = "x-amz-server-side-encryption-customer-key-MD5"
 )
 
 const (
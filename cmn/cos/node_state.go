@@ -38,6 +38,7 @@ const (
 	KeepAliveErrors                                  // warning (new keep-alive errors during the last 5m)
 	OOCPU                                            // out of CPU; red
 	LowCPU                                           // warning
+	CertificateRevoked                               // warning X.509: own (see also CertificateInvalid) or mTLS client's
 )
 
 func (f NodeStateFlags) IsOK() bool { return f == NodeStarted|ClusterStarted }
@@ -48,7 +49,7 @@ func (f NodeStateFlags) IsRed() bool {
 
 func (f NodeStateFlags) IsWarn() bool {
 	return f.IsAnySet(Rebalancing | RebalanceInterrupted | Resilvering | ResilverInterrupted | NodeRestarted | MaintenanceMode |
-		LowCapacity | LowMemory | LowCPU | CertWillSoonExpire)
+		LowCapacity | LowMemory | LowCPU | CertWillSoonExpire | CertificateRevoked)
 }
 
 func (f NodeStateFlags) IsSet(flag NodeStateFlags) bool { return BitFlags(f).IsSet(BitFlags(flag)) }
@@ -141,6 +142,9 @@ func (f NodeStateFlags) String() string {
 	if f&LowCPU == LowCPU {
 		sb = append(sb, "low-cpu")
 	}
+	if f&CertificateRevoked == CertificateRevoked {
+		sb = append(sb, "tls-cert-revoked")
+	}
 
 	l := len(sb)
 	switch l {
@@ -12,6 +12,22 @@ const (
 	StreamsOutObjSize  = "stream.out.size"
 	StreamsInObjCount  = "stream.in.n"
 	StreamsInObjSize   = "stream.in.size"
+
+	// adaptive stream multiplier (see transport/bundle/adapt.go)
+	StreamsAdaptGrowCount   = "stream.adapt.grow.n"
+	StreamsAdaptShrinkCount = "stream.adapt.shrink.n"
+	StreamsAdaptActive      = "stream.adapt.active" // gauge: streams above configured multipliers
+
+	// PDU checksumming and retransmission (see transport.pdu_checksum)
+	ErrStreamsPDUCksumCount = "err.stream.pdu.cksum.n"
+	StreamsRetransmitCount  = "stream.retransmit.n"
+)
+
+// TLS certificate (re)loading (see cmn/certloader)
+const (
+	CertReloadCount    = "tls.cert.reload.n"
+	ErrCertReloadCount = "err.tls.cert.reload.n"
+	CertReloadLatency  = "tls.cert.reload.ns"
 )
 
 type (
@@ -166,6 +166,46 @@ func (pt *ParsedTemplate) Next() (string, bool) {
 	return pt.buf.String(), true
 }
 
+// returns true if the template generates (see Next) the name;
+// no ranges ("pure" prefix) - true if the name has the prefix
+func (pt *ParsedTemplate) Match(name string) bool {
+	if !strings.HasPrefix(name, pt.Prefix) {
+		return false
+	}
+	if len(pt.Ranges) == 0 {
+		return true
+	}
+	return pt.match(name[len(pt.Prefix):], 0)
+}
+
+func (pt *ParsedTemplate) match(s string, i int) bool {
+	if i == len(pt.Ranges) {
+		return s == ""
+	}
+	tr := &pt.Ranges[i]
+	var l int
+	for l < len(s) && s[l] >= '0' && s[l] <= '9' {
+		l++
+	}
+	// backtracking: the gap may start with digits
+	for ; l > 0; l-- {
+		if !strings.HasPrefix(s[l:], tr.Gap) {
+			continue
+		}
+		n, err := strconv.ParseInt(s[:l], 10, 64)
+		if err != nil || n < tr.Start || n > tr.End || (n-tr.Start)%tr.Step != 0 {
+			continue
+		}
+		if fmt.Sprintf("%0*d", tr.DigitCount, n) != s[:l] {
+			continue // padding
+		}
+		if pt.match(s[l+len(tr.Gap):], i+1) {
+			return true
+		}
+	}
+	return false
+}
+
 //
 // parsing --- parsing --- parsing
 //
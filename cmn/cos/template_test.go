@@ -319,5 +319,24 @@ var _ = Describe("Template", func() {
 				"prefix-0010-gap-1-suffix", "prefix-0012-gap-1-suffix",
 			),
 		)
+
+		DescribeTable("match method",
+			func(template, name string, expected bool) {
+				pt, err := cos.NewParsedTemplate(template)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pt.Match(name)).To(Equal(expected))
+			},
+			Entry("bash", "prefix-{0010..0013..2}-suffix", "prefix-0012-suffix", true),
+			Entry("bash: step", "prefix-{0010..0013..2}-suffix", "prefix-0011-suffix", false),
+			Entry("bash: out of range", "prefix-{0010..0013..2}-suffix", "prefix-0014-suffix", false),
+			Entry("bash: padding", "prefix-{0010..0013..2}-suffix", "prefix-12-suffix", false),
+			Entry("bash: suffix", "prefix-{0010..0013..2}-suffix", "prefix-0012-suffix.tar", false),
+			Entry("multi-range", "a-{1..3}-gap-{10..20}", "a-2-gap-15", true),
+			Entry("gap starting with digit", "a{1..20}1{0..9}", "a1115", true),
+			Entry("at", "prefix-@00100-suffix", "prefix-00042-suffix", true),
+			Entry("fmt", "prefix-%06d-suffix", "prefix-000042-suffix", true),
+			Entry("pure prefix", "prefix-", "prefix-anything", true),
+			Entry("pure prefix: no match", "prefix-", "other", false),
+		)
 	})
 })
@@ -115,6 +115,21 @@ func HashK8sProxyID(nodeName string) (pid string) {
 	return pid
 }
 
+// deterministic l-character digest of the name in a given alphabet
+// (e.g., to spread names across hashed prefixes)
+func HashPrefix(name string, l int, abc string) string {
+	var (
+		b      = make([]byte, l)
+		n      = uint64(len(abc))
+		digest = xxhash.Checksum64S(UnsafeB(name), MLCG32)
+	)
+	for i := range l {
+		b[i] = abc[digest%n]
+		digest /= n
+	}
+	return UnsafeS(b)
+}
+
 // (when config.TestingEnv)
 func GenTestingDaemonID(suffix string) string {
 	l := max(lenDaemonID-len(suffix), 3)
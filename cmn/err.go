@@ -16,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn/cos"
@@ -112,6 +113,14 @@ type (
 	ErrGetCap struct {
 		err error
 	}
+	// bucket (StatusInsufficientStorage) or user (StatusForbidden) quota; see QuotaConf
+	ErrQuotaExceeded struct {
+		Bucket string `json:"bucket,omitempty"` // bucket's cname (bucket quota)
+		User   string `json:"user,omitempty"`   // user ID (user quota)
+		What   string `json:"what"`             // QuotaSize or QuotaObjects
+		Limit  int64  `json:"limit"`
+		Usage  int64  `json:"usage"`
+	}
 
 	ErrBucketAccessDenied struct{ errAccessDenied }
 	ErrObjectAccessDenied struct{ errAccessDenied }
@@ -212,6 +221,16 @@ type (
 		action  string
 		detail  string
 	}
+	ErrBckXactLimit struct {
+		bck   string
+		kind  string
+		limit int
+	}
+	ErrObjLocked struct {
+		cname string
+		mode  string
+		until int64 // Unix seconds
+	}
 	ErrXactUsePrev struct { // equivalent to xreg.WprUse
 		xaction string
 	}
@@ -259,8 +278,9 @@ var (
 
 	// aborts
 	ErrXactRenewAbort   = errors.New("renewal abort")
-	ErrXactUserAbort    = errors.New("user abort")              // via apc.ActXactStop
-	ErrXactICNotifAbort = errors.New("IC(notifications) abort") // ditto
+	ErrXactUserAbort    = errors.New("user abort")                 // via apc.ActXactStop
+	ErrXactICNotifAbort = errors.New("IC(notifications) abort")    // ditto
+	ErrXactOrphaned     = errors.New("orphaned: target restarted") // ditto (see xreg/journal)
 )
 
 // ErrFailedTo
@@ -468,6 +488,47 @@ func NewObjectAccessDenied(object, oper string, aattrs apc.AccessAttrs) *ErrObje
 	return &ErrObjectAccessDenied{errAccessDenied{object, oper, aattrs}}
 }
 
+// ErrQuotaExceeded
+
+const (
+	QuotaSize    = "size"
+	QuotaObjects = "objects"
+)
+
+func NewErrBckQuotaExceeded(cname, what string, limit, usage int64) *ErrQuotaExceeded {
+	return &ErrQuotaExceeded{Bucket: cname, What: what, Limit: limit, Usage: usage}
+}
+
+func NewErrUserQuotaExceeded(user, what string, limit, usage int64) *ErrQuotaExceeded {
+	return &ErrQuotaExceeded{User: user, What: what, Limit: limit, Usage: usage}
+}
+
+func (e *ErrQuotaExceeded) Error() string {
+	var s string
+	if e.Bucket != "" {
+		s = "bucket " + e.Bucket
+	} else {
+		s = fmt.Sprintf("user %q", e.User)
+	}
+	if e.What == QuotaSize {
+		return fmt.Sprintf("%s: size quota exceeded (limit %s, usage %s)", s,
+			cos.ToSizeIEC(e.Limit, 2), cos.ToSizeIEC(e.Usage, 2))
+	}
+	return fmt.Sprintf("%s: %s quota exceeded (limit %d, usage %d)", s, e.What, e.Limit, e.Usage)
+}
+
+func (e *ErrQuotaExceeded) Status() int {
+	if e.Bucket != "" {
+		return http.StatusInsufficientStorage
+	}
+	return http.StatusForbidden
+}
+
+func IsErrQuotaExceeded(err error) bool {
+	_, ok := err.(*ErrQuotaExceeded)
+	return ok
+}
+
 // ErrCapExceeded
 
 func NewErrCapExceeded(totalBytesUsed, totalBytes uint64, highWM, cleanupWM int64, usedPct int32, oos bool) *ErrCapExceeded {
@@ -870,6 +931,38 @@ func (e *ErrLimitedCoexistence) Error() string {
 		e.node, e.xaction, e.action, e.detail)
 }
 
+// ErrBckXactLimit
+
+func NewErrBckXactLimit(bck, kind string, limit int) *ErrBckXactLimit {
+	return &ErrBckXactLimit{bck, kind, limit}
+}
+
+func (e *ErrBckXactLimit) Error() string {
+	return fmt.Sprintf("cannot run %q: bucket %s has reached its limit of %d concurrent xactions (xact.max_concurrent)",
+		e.kind, e.bck, e.limit)
+}
+
+func IsErrBckXactLimit(err error) bool {
+	_, ok := err.(*ErrBckXactLimit)
+	return ok
+}
+
+// ErrObjLocked
+
+func NewErrObjLocked(cname, mode string, until int64) *ErrObjLocked {
+	return &ErrObjLocked{cname, mode, until}
+}
+
+func (e *ErrObjLocked) Error() string {
+	return fmt.Sprintf("object %s is locked (%s mode) until %s", e.cname, e.mode,
+		time.Unix(e.until, 0).UTC().Format(time.RFC3339))
+}
+
+func IsErrObjLocked(err error) bool {
+	_, ok := err.(*ErrObjLocked)
+	return ok
+}
+
 // ErrXactUsePrev
 
 func NewErrXactUsePrev(xaction string) *ErrXactUsePrev {
@@ -1247,6 +1340,8 @@ func WriteErr(w http.ResponseWriter, r *http.Request, err error, opts ...int /*[
 			status = http.StatusInsufficientStorage
 		case IsErrRangeNotSatisfiable(err):
 			status = http.StatusRequestedRangeNotSatisfiable
+		case IsErrObjLocked(err):
+			status = http.StatusForbidden
 		case isErrUnsupp(err), isErrNotImpl(err):
 			status = http.StatusNotImplemented
 		}
@@ -49,4 +49,10 @@ const (
 	RebalanceMarker     = "rebalance"
 	NodeRestartedMarker = "node_restarted"
 	NodeRestartedPrev   = "node_restarted.prev"
+
+	// copy-bucket checkpoints: per mountpath (see xs/tcb)
+	TCBCheckpointDir = ".ais.tcb"
+
+	// in-progress multi-target xactions: target's config dir (see xreg/journal)
+	XactJournal = ".ais.xjournal"
 )
@@ -0,0 +1,54 @@
+// Package kms provides pluggable key management (KMS) for envelope encryption:
+// server-side encryption at rest, AuthN secret, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package kms
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/NVIDIA/aistore/api/env"
+)
+
+// Envelope encryption: data (e.g., object content) gets encrypted with a data key (DEK)
+// that, in turn, is encrypted ("wrapped") by KMS with a named KMS key - the latter
+// never leaves the KMS. Callers store wrapped DEKs along with the data and ask KMS
+// to decrypt them when needed.
+
+const (
+	Keyfile = "keyfile"
+	Vault   = "vault"
+	AWS     = "aws"
+)
+
+const KeyLen = 32 // AES-256 data key
+
+type KMS interface {
+	Name() string
+	// encrypt a (small) payload, e.g. a secret, with the KMS key `keyID`
+	Encrypt(keyID string, plaintext []byte) ([]byte, error)
+	// decrypt what was encrypted with the same KMS key (via Encrypt or GenerateDataKey)
+	Decrypt(keyID string, ciphertext []byte) ([]byte, error)
+	// new AES-256 data key: plaintext and encrypted with the KMS key `keyID`
+	GenerateDataKey(keyID string) (plain, wrapped []byte, err error)
+}
+
+// New returns KMS configured via environment (see env.KMS), or nil if none is
+func New() (k KMS, err error) {
+	provider := os.Getenv(env.KMS.Provider)
+	switch provider {
+	case "":
+		return nil, nil
+	case Keyfile:
+		k, err = newKeyfile()
+	case Vault:
+		k, err = newVault()
+	case AWS:
+		k, err = newAWS()
+	default:
+		err = fmt.Errorf("invalid %s=%q (expecting %q, %q, or %q)", env.KMS.Provider, provider, Keyfile, Vault, AWS)
+	}
+	return k, err
+}
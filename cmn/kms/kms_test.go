@@ -0,0 +1,144 @@
+// Package kms_test: unit tests
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package kms_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/aistore/api/env"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/kms"
+	"github.com/NVIDIA/aistore/tools/tassert"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const testKeyID = "test-key"
+
+func roundTrip(t *testing.T, k kms.KMS) {
+	secret := []byte("the quick brown fox")
+	ct, err := k.Encrypt(testKeyID, secret)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, !bytes.Contains(ct, secret), "%s: ciphertext contains plaintext", k.Name())
+	pt, err := k.Decrypt(testKeyID, ct)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bytes.Equal(pt, secret), "%s: decrypted %q vs %q", k.Name(), pt, secret)
+
+	plain, wrapped, err := k.GenerateDataKey(testKeyID)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(plain) == kms.KeyLen, "%s: data key length %d", k.Name(), len(plain))
+	unwrapped, err := k.Decrypt(testKeyID, wrapped)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bytes.Equal(unwrapped, plain), "%s: data key mismatch", k.Name())
+}
+
+func TestKeyfile(t *testing.T) {
+	dir := t.TempDir()
+	key := make([]byte, kms.KeyLen)
+	_, err := rand.Read(key)
+	tassert.CheckFatal(t, err)
+	err = os.WriteFile(filepath.Join(dir, testKeyID), []byte(base64.StdEncoding.EncodeToString(key)+"\n"), cos.PermRWR)
+	tassert.CheckFatal(t, err)
+
+	t.Setenv(env.KMS.Provider, kms.Keyfile)
+	t.Setenv(env.KMS.KeyDir, dir)
+	k, err := kms.New()
+	tassert.CheckFatal(t, err)
+	roundTrip(t, k)
+
+	// wrong key ID, tampered ciphertext
+	ct, err := k.Encrypt(testKeyID, []byte("data"))
+	tassert.CheckFatal(t, err)
+	_, err = k.Decrypt("no-such-key", ct)
+	tassert.Errorf(t, err != nil, "expected error (missing key)")
+	ct[len(ct)-1] ^= 0xff
+	_, err = k.Decrypt(testKeyID, ct)
+	tassert.Errorf(t, err != nil, "expected error (tampered ciphertext)")
+}
+
+// minimal Vault transit engine: "ciphertext" is a base64 of the reversed plaintext
+func TestVault(t *testing.T) {
+	const token = "s.test-token"
+	reverse := func(b []byte) []byte {
+		r := make([]byte, len(b))
+		for i := range b {
+			r[len(b)-1-i] = b[i]
+		}
+		return r
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != token {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		var (
+			in struct {
+				Plaintext  string `json:"plaintext"`
+				Ciphertext string `json:"ciphertext"`
+				Bits       int    `json:"bits"`
+			}
+			out struct {
+				Data map[string]string `json:"data"`
+			}
+		)
+		if err := jsoniter.NewDecoder(r.Body).Decode(&in); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		out.Data = make(map[string]string, 2)
+		switch {
+		case r.URL.Path == "/v1/transit/encrypt/"+testKeyID:
+			pt, _ := base64.StdEncoding.DecodeString(in.Plaintext)
+			out.Data["ciphertext"] = "vault:v1:" + base64.StdEncoding.EncodeToString(reverse(pt))
+		case r.URL.Path == "/v1/transit/decrypt/"+testKeyID:
+			ct, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(in.Ciphertext, "vault:v1:"))
+			out.Data["plaintext"] = base64.StdEncoding.EncodeToString(reverse(ct))
+		case r.URL.Path == "/v1/transit/datakey/plaintext/"+testKeyID:
+			pt := make([]byte, in.Bits/8)
+			_, _ = rand.Read(pt)
+			out.Data["plaintext"] = base64.StdEncoding.EncodeToString(pt)
+			out.Data["ciphertext"] = "vault:v1:" + base64.StdEncoding.EncodeToString(reverse(pt))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":["no handler for route"]}`))
+			return
+		}
+		jsoniter.NewEncoder(w).Encode(&out)
+	}))
+	defer srv.Close()
+
+	t.Setenv(env.KMS.Provider, kms.Vault)
+	t.Setenv(env.KMS.VaultAddr, srv.URL)
+	t.Setenv(env.KMS.VaultToken, token)
+	k, err := kms.New()
+	tassert.CheckFatal(t, err)
+	roundTrip(t, k)
+
+	_, err = k.Encrypt("no-such-key", []byte("data"))
+	tassert.Errorf(t, err != nil && strings.Contains(err.Error(), "no handler"), "expected vault error, got %v", err)
+}
+
+func TestNew(t *testing.T) {
+	t.Setenv(env.KMS.Provider, "")
+	k, err := kms.New()
+	tassert.Errorf(t, k == nil && err == nil, "expecting no KMS (%v)", err)
+
+	t.Setenv(env.KMS.Provider, "no-such-kms")
+	_, err = kms.New()
+	tassert.Errorf(t, err != nil, "expected error (invalid provider)")
+
+	t.Setenv(env.KMS.Provider, kms.Vault)
+	t.Setenv(env.KMS.VaultAddr, "")
+	_, err = kms.New()
+	tassert.Errorf(t, err != nil, "expected error (vault address not set)")
+}
@@ -0,0 +1,14 @@
+//go:build !aws
+
+// Package kms provides pluggable key management (KMS) for envelope encryption:
+// server-side encryption at rest, AuthN secret, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package kms
+
+import "fmt"
+
+func newAWS() (KMS, error) {
+	return nil, fmt.Errorf("%s KMS: not supported (build without 'aws' tag)", AWS)
+}
@@ -0,0 +1,134 @@
+// Package kms provides pluggable key management (KMS) for envelope encryption:
+// server-side encryption at rest, AuthN secret, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package kms
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/env"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// HashiCorp Vault transit secrets engine: named transit keys (key ID = transit key name);
+// ciphertexts are Vault's own ("vault:v<N>:..."), so that rotating transit key in Vault
+// keeps existing ciphertexts decryptable
+// ref: https://developer.hashicorp.com/vault/api-docs/secret/transit
+
+const (
+	vaultTimeout = 30 * time.Second
+	vaultMount   = "transit"
+
+	vaultHdrToken = "X-Vault-Token"
+)
+
+type (
+	vault struct {
+		client *http.Client
+		addr   string
+		token  string
+		mount  string
+	}
+	vaultReq struct {
+		Plaintext  string `json:"plaintext,omitempty"`
+		Ciphertext string `json:"ciphertext,omitempty"`
+		Bits       int    `json:"bits,omitempty"`
+	}
+	vaultResp struct {
+		Data struct {
+			Plaintext  string `json:"plaintext"`
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+		Errors []string `json:"errors"`
+	}
+)
+
+// interface guard
+var _ KMS = (*vault)(nil)
+
+func newVault() (KMS, error) {
+	v := &vault{
+		addr:   strings.TrimSuffix(os.Getenv(env.KMS.VaultAddr), "/"),
+		token:  os.Getenv(env.KMS.VaultToken),
+		mount:  strings.Trim(os.Getenv(env.KMS.VaultMount), "/"),
+		client: cmn.NewClient(cmn.TransportArgs{Timeout: vaultTimeout}),
+	}
+	if v.addr == "" || v.token == "" {
+		return nil, fmt.Errorf("%s KMS: both %s and %s must be set", Vault, env.KMS.VaultAddr, env.KMS.VaultToken)
+	}
+	if _, err := url.ParseRequestURI(v.addr); err != nil {
+		return nil, fmt.Errorf("%s KMS: invalid %s=%q: %v", Vault, env.KMS.VaultAddr, v.addr, err)
+	}
+	if v.mount == "" {
+		v.mount = vaultMount
+	}
+	return v, nil
+}
+
+func (*vault) Name() string { return Vault }
+
+func (v *vault) Encrypt(keyID string, plaintext []byte) ([]byte, error) {
+	var resp vaultResp
+	in := &vaultReq{Plaintext: base64.StdEncoding.EncodeToString(plaintext)}
+	if err := v.do("encrypt/"+url.PathEscape(keyID), in, &resp); err != nil {
+		return nil, err
+	}
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+func (v *vault) Decrypt(keyID string, ciphertext []byte) ([]byte, error) {
+	var resp vaultResp
+	if err := v.do("decrypt/"+url.PathEscape(keyID), &vaultReq{Ciphertext: string(ciphertext)}, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+}
+
+func (v *vault) GenerateDataKey(keyID string) (plain, wrapped []byte, err error) {
+	var resp vaultResp
+	if err = v.do("datakey/plaintext/"+url.PathEscape(keyID), &vaultReq{Bits: KeyLen * 8}, &resp); err != nil {
+		return nil, nil, err
+	}
+	if plain, err = base64.StdEncoding.DecodeString(resp.Data.Plaintext); err != nil {
+		return nil, nil, err
+	}
+	return plain, []byte(resp.Data.Ciphertext), nil
+}
+
+func (v *vault) do(path string, in *vaultReq, out *vaultResp) error {
+	body, err := jsoniter.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, v.addr+"/v1/"+v.mount+"/"+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(vaultHdrToken, v.token)
+	req.Header.Set(cos.HdrContentType, cos.ContentJSON)
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, cos.KiB))
+		if jsoniter.Unmarshal(b, out) == nil && len(out.Errors) > 0 {
+			return fmt.Errorf("vault: %s (status %d)", strings.Join(out.Errors, "; "), resp.StatusCode)
+		}
+		return fmt.Errorf("vault: %s (status %d)", strings.TrimSpace(string(b)), resp.StatusCode)
+	}
+	return jsoniter.NewDecoder(resp.Body).Decode(out)
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn/cos"
@@ -32,10 +33,55 @@ const (
 	VersionObjMD = "version" // "generation" for GCP, "version" for AWS but only if the bucket is versioned, etc.
 	CRC32CObjMD  = cos.ChecksumCRC32C
 	MD5ObjMD     = cos.ChecksumMD5
+	SHA1ObjMD    = "sha1" // Backblaze B2 (not an AIS checksum type)
 	ETag         = cos.HdrETag
 
 	OrigURLObjMD = "orig_url"
 
+	// remote storage tier, e.g. Azure blob access tier (Hot, Cool, Cold, Archive); setting it
+	// (see api.SetObjectCustomProps) changes the tier of the remote object (see core.TierBackend)
+	AccessTierObjMD = "access_tier"
+	// rehydration (from archive tier) status, e.g. "rehydrate-pending-to-hot"
+	ArchiveStatusObjMD = "archive_status"
+
+	// creation order (sequence marker) carried by bucket-to-bucket copy (see apc.CopyBckMsg.PreserveOrder)
+	SeqObjMD = "seq"
+
+	// source version and modification time recorded by conditional (incremental) bucket-to-bucket copy
+	// (see apc.CopyBckMsg.IfNoneMatch)
+	SrcVerObjMD   = "src.ver"
+	SrcMtimeObjMD = "src.mtime"
+
+	// digest of the source content and the transformation that produced a given (ETL-bucket) destination
+	// (see apc.Transform.Cache)
+	ETLCacheObjMD = "etl.cache"
+
+	// (serialized) state of the object's checksum, to continue computing it upon append (see apc.ExtendOp)
+	AppendCksumObjMD = "append.cksum"
+
+	// expiration time: Unix seconds or RFC3339; expired objects get removed by apc.ActExpireObjs
+	// (set directly or via apc.HdrObjTTL)
+	ExpiresObjMD = "expires"
+
+	// object lock (WORM) retention: Unix seconds; see ObjLockConf
+	RetainUntilObjMD = "retain-until"
+
+	// cross-cluster replication state: source modification time (Unix nanoseconds) and destination
+	// version (or checksum) as of the last successful replication; see ReplicationConf
+	ReplStateObjMD = "repl.state"
+
+	// active-active replication: version vector (cluster UUID => number of updates) and the
+	// object's origin write (time and cluster); see VersionVector and ReplWrite
+	ReplVVObjMD    = "repl.vv"
+	ReplWriteObjMD = "repl.write"
+
+	// in-cluster migration (rebalance, decommission) of objects encrypted with client-supplied keys:
+	// the content gets transferred as is (ciphertext) along with its key reference (see sse.IsCustomer)
+	SSECustomerObjMD = "sse-c"
+
+	// AuthN user that created the object (when AuthN is enabled); used to account per-user quotas (see QuotaConf)
+	OwnerObjMD = "owner"
+
 	// additional backend
 	LastModified = "LastModified"
 )
@@ -150,6 +196,38 @@ func (oa *ObjAttrs) SetCustomKey(k, v string) {
 	oa.CustomMD[k] = v
 }
 
+// returns expiration time in Unix seconds, zero if not set (see ExpiresObjMD)
+func (oa *ObjAttrs) Expires() (int64, error) {
+	v, ok := oa.CustomMD[ExpiresObjMD]
+	if !ok || v == "" {
+		return 0, nil
+	}
+	return ParseExpires(v)
+}
+
+// returns retention time in Unix seconds, zero if not set (see RetainUntilObjMD)
+func (oa *ObjAttrs) RetainUntil() (int64, error) {
+	v, ok := oa.CustomMD[RetainUntilObjMD]
+	if !ok || v == "" {
+		return 0, nil
+	}
+	return ParseRetainUntil(v)
+}
+
+func ParseExpires(v string) (int64, error)     { return parseUnixTime(ExpiresObjMD, v) }
+func ParseRetainUntil(v string) (int64, error) { return parseUnixTime(RetainUntilObjMD, v) }
+
+func parseUnixTime(name, v string) (int64, error) {
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return n, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %q value %q (expecting Unix seconds or RFC3339)", name, v)
+	}
+	return t.Unix(), nil
+}
+
 func (oa *ObjAttrs) DelStdCustom() {
 	for _, key := range stdCustomProps {
 		delete(oa.CustomMD, key)
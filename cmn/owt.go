@@ -38,6 +38,10 @@ const (
 	//
 	OwtCopySameBucket
 	//
+	// copy into remote bucket without writing through to its backend (see apc.CopyBckMsg.LocalOnly)
+	//
+	OwtCopyLocal
+	//
 	// None of the above
 	//
 	OwtNone
@@ -73,6 +77,10 @@ func (owt OWT) String() (s string) {
 		s = "owt-get"
 	case OwtGetPrefetchLock:
 		s = "owt-prefetch-lock"
+	case OwtCopySameBucket:
+		s = "owt-copy-same-bucket"
+	case OwtCopyLocal:
+		s = "owt-copy-local"
 	case OwtNone:
 		s = "owt-none"
 	default:
@@ -0,0 +1,35 @@
+// Package cmn provides common constants, types, and utilities for AIS clients
+// and AIStore.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+// bucket snapshot (see apc.ActCreateSnapshot)
+type (
+	SnapshotInfo struct {
+		Props   *Bprops `json:"props,omitempty"` // bucket props at the time of snapshot
+		Name    string  `json:"name"`
+		Created int64   `json:"created,string"`  // Unix nanoseconds
+		NumObjs int64   `json:"num_objs,string"` // number of snapshotted objects
+		Size    int64   `json:"size,string"`     // their total size (NOTE: not the space consumed - see fs.SnapType)
+	}
+	Snapshots []*SnapshotInfo
+)
+
+// accumulate (e.g., per-mountpath or per-target) snapshot info
+func (snaps Snapshots) Add(info *SnapshotInfo) Snapshots {
+	for _, snap := range snaps {
+		if snap.Name != info.Name {
+			continue
+		}
+		snap.NumObjs += info.NumObjs
+		snap.Size += info.Size
+		snap.Created = min(snap.Created, info.Created)
+		if snap.Props == nil {
+			snap.Props = info.Props
+		}
+		return snaps
+	}
+	return append(snaps, info)
+}
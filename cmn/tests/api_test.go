@@ -5,8 +5,11 @@
 package tests_test
 
 import (
+	"time"
+
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -182,6 +185,242 @@ var _ = Describe("API", func() {
 					},
 				},
 			),
+			Entry("lifecycle rules",
+				cmn.Bprops{
+					Lifecycle: cmn.LifecycleConf{
+						Rules: []cmn.LifecycleRule{{ExpireDays: 1}},
+					},
+				},
+				cmn.BpropsToSet{
+					Lifecycle: &cmn.LifecycleConfToSet{
+						Enabled: apc.Ptr(true),
+						Rules:   &[]cmn.LifecycleRule{{Prefix: "tmp/", ExpireDays: 7}, {EvictDays: 30}},
+					},
+				},
+				cmn.Bprops{
+					Lifecycle: cmn.LifecycleConf{
+						Enabled: true,
+						Rules:   []cmn.LifecycleRule{{Prefix: "tmp/", ExpireDays: 7}, {EvictDays: 30}},
+					},
+				},
+			),
+		)
+	})
+
+	Describe("Lifecycle", func() {
+		It("should parse rules", func() {
+			props, err := cmn.NewBpropsToSet(cos.StrKVs{
+				"lifecycle.enabled": "true",
+				cmn.PropLifecycleRules: `[{"prefix": "tmp/", "expire_days": 7},` +
+					`{"transition_days": 30, "transition_bck": {"name": "cold", "provider": "ais"}}]`,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*props.Lifecycle.Enabled).To(BeTrue())
+			Expect(*props.Lifecycle.Rules).To(Equal([]cmn.LifecycleRule{
+				{Prefix: "tmp/", ExpireDays: 7},
+				{TransitionDays: 30, TransitionBck: cmn.Bck{Name: "cold", Provider: apc.AIS}},
+			}))
+
+			_, err = cmn.NewBpropsToSet(cos.StrKVs{cmn.PropLifecycleRules: "expire_days=7"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		DescribeTable("should validate rules",
+			func(rule cmn.LifecycleRule, valid bool) {
+				conf := cmn.LifecycleConf{Enabled: true, Rules: []cmn.LifecycleRule{rule}}
+				err := conf.ValidateAsProps()
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+			Entry("expiration", cmn.LifecycleRule{ExpireDays: 1}, true),
+			Entry("eviction", cmn.LifecycleRule{Prefix: "a/", EvictDays: 1}, true),
+			Entry("transition", cmn.LifecycleRule{TransitionDays: 1, TransitionBck: cmn.Bck{Name: "cold", Provider: apc.AIS}}, true),
+			Entry("no action", cmn.LifecycleRule{Prefix: "a/"}, false),
+			Entry("negative days", cmn.LifecycleRule{ExpireDays: -1}, false),
+			Entry("transition without bucket", cmn.LifecycleRule{TransitionDays: 1}, false),
+			Entry("bucket without transition", cmn.LifecycleRule{ExpireDays: 1, TransitionBck: cmn.Bck{Name: "cold", Provider: apc.AIS}}, false),
+		)
+	})
+
+	Describe("ObjLock", func() {
+		DescribeTable("should validate",
+			func(conf cmn.ObjLockConf, valid bool) {
+				err := conf.ValidateAsProps()
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+			Entry("disabled", cmn.ObjLockConf{}, true),
+			Entry("governance", cmn.ObjLockConf{Mode: apc.ObjLockGovernance}, true),
+			Entry("compliance w/ retention", cmn.ObjLockConf{Mode: apc.ObjLockCompliance, Retention: cos.Duration(time.Hour)}, true),
+			Entry("invalid mode", cmn.ObjLockConf{Mode: "worm"}, false),
+			Entry("negative retention", cmn.ObjLockConf{Mode: apc.ObjLockGovernance, Retention: -1}, false),
+			Entry("retention w/o mode", cmn.ObjLockConf{Retention: cos.Duration(time.Hour)}, false),
+		)
+
+		It("should parse retain-until", func() {
+			oa := cmn.ObjAttrs{}
+			until, err := oa.RetainUntil()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(until).To(BeZero())
+
+			oa.SetCustomKey(cmn.RetainUntilObjMD, "2030-01-02T03:04:05Z")
+			until, err = oa.RetainUntil()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(until).To(Equal(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC).Unix()))
+
+			_, err = cmn.ParseRetainUntil("tomorrow")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Replication", func() {
+		remais := cmn.Bck{Name: "dst", Provider: apc.AIS, Ns: cmn.Ns{UUID: "remais"}}
+
+		It("should parse destination bucket", func() {
+			props, err := cmn.NewBpropsToSet(cos.StrKVs{
+				"replication.enabled":  "true",
+				"replication.conflict": apc.ReplConflictSkip,
+				cmn.PropReplicationBck: "ais://@remais/dst",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*props.Replication.Enabled).To(BeTrue())
+			Expect(*props.Replication.Conflict).To(Equal(apc.ReplConflictSkip))
+			Expect(props.Replication.Bck.Equal(&remais)).To(BeTrue())
+		})
+
+		DescribeTable("should validate",
+			func(conf cmn.ReplicationConf, valid bool) {
+				err := conf.ValidateAsProps()
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+			Entry("disabled", cmn.ReplicationConf{}, true),
+			Entry("remote ais", cmn.ReplicationConf{Enabled: true, Bck: remais}, true),
+			Entry("skip conflicts", cmn.ReplicationConf{Enabled: true, Bck: remais, Conflict: apc.ReplConflictSkip}, true),
+			Entry("no destination", cmn.ReplicationConf{Enabled: true}, false),
+			Entry("local destination", cmn.ReplicationConf{Enabled: true, Bck: cmn.Bck{Name: "dst", Provider: apc.AIS}}, false),
+			Entry("cloud destination", cmn.ReplicationConf{Enabled: true, Bck: cmn.Bck{Name: "dst", Provider: apc.AWS}}, false),
+			Entry("invalid conflict policy", cmn.ReplicationConf{Enabled: true, Bck: remais, Conflict: "merge"}, false),
+			Entry("active-active", cmn.ReplicationConf{Enabled: true, ActiveActive: true, Bck: remais, Resolve: apc.ReplResolveFWW}, true),
+			Entry("prefer cluster", cmn.ReplicationConf{Enabled: true, Bck: remais, Resolve: apc.ReplResolvePrefer, Prefer: "uuid"}, true),
+			Entry("prefer without cluster", cmn.ReplicationConf{Enabled: true, Bck: remais, Resolve: apc.ReplResolvePrefer}, false),
+			Entry("cluster without prefer", cmn.ReplicationConf{Enabled: true, Bck: remais, Prefer: "uuid"}, false),
+			Entry("active-active disabled", cmn.ReplicationConf{ActiveActive: true, Bck: remais}, false),
+			Entry("invalid resolve policy", cmn.ReplicationConf{Enabled: true, Bck: remais, Resolve: "merge"}, false),
+		)
+	})
+
+	Describe("GCP encryption keys", func() {
+		const kms = "projects/p1/locations/us/keyRings/r1/cryptoKeys/k1"
+		DescribeTable("should validate",
+			func(conf cmn.ExtraPropsGCP, valid bool) {
+				extra := cmn.ExtraProps{GCP: conf}
+				err := extra.ValidateAsProps(apc.GCP)
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+			Entry("none", cmn.ExtraPropsGCP{}, true),
+			Entry("CMEK", cmn.ExtraPropsGCP{KMSKey: kms}, true),
+			Entry("CSEK", cmn.ExtraPropsGCP{CSEKFile: "/etc/ais/keys/k1.b64"}, true),
+			Entry("both", cmn.ExtraPropsGCP{KMSKey: kms, CSEKFile: "/etc/ais/keys/k1.b64"}, false),
+			Entry("invalid KMS key name", cmn.ExtraPropsGCP{KMSKey: "k1"}, false),
+			Entry("relative CSEK path", cmn.ExtraPropsGCP{CSEKFile: "keys/k1.b64"}, false),
+		)
+	})
+
+	Describe("Encryption", func() {
+		DescribeTable("should validate",
+			func(conf cmn.EncryptionConf, valid bool) {
+				err := conf.ValidateAsProps()
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+			Entry("disabled", cmn.EncryptionConf{}, true),
+			Entry("disabled with key ID", cmn.EncryptionConf{KeyID: "k1"}, true),
+			Entry("enabled", cmn.EncryptionConf{Enabled: true, KeyID: "k1"}, true),
+			Entry("enabled without key ID", cmn.EncryptionConf{Enabled: true}, false),
 		)
 	})
+
+	Describe("VersionVector", func() {
+		It("should parse and format", func() {
+			vv, err := cmn.ParseVV("B:1,A:3")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vv).To(Equal(cmn.VersionVector{"A": 3, "B": 1}))
+			Expect(vv.String()).To(Equal("A:3,B:1"))
+
+			vv, err = cmn.ParseVV("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vv).To(BeEmpty())
+
+			for _, s := range []string{"A", "A:", ":1", "A:0", "A:x", "A:1,A:2"} {
+				_, err = cmn.ParseVV(s)
+				Expect(err).To(HaveOccurred(), s)
+			}
+		})
+
+		DescribeTable("should compare",
+			func(a, b cmn.VersionVector, expected int) {
+				Expect(a.Compare(b)).To(Equal(expected))
+			},
+			Entry("empty", cmn.VersionVector{}, cmn.VersionVector{}, cmn.VVEqual),
+			Entry("equal", cmn.VersionVector{"A": 2, "B": 1}, cmn.VersionVector{"A": 2, "B": 1}, cmn.VVEqual),
+			Entry("newer", cmn.VersionVector{"A": 3, "B": 1}, cmn.VersionVector{"A": 2, "B": 1}, cmn.VVNewer),
+			Entry("newer (superset)", cmn.VersionVector{"A": 1, "B": 1}, cmn.VersionVector{"A": 1}, cmn.VVNewer),
+			Entry("older", cmn.VersionVector{"A": 1}, cmn.VersionVector{"A": 1, "B": 1}, cmn.VVOlder),
+			Entry("concurrent", cmn.VersionVector{"A": 2, "B": 1}, cmn.VersionVector{"A": 1, "B": 2}, cmn.VVConcurrent),
+			Entry("concurrent (disjoint)", cmn.VersionVector{"A": 1}, cmn.VersionVector{"B": 1}, cmn.VVConcurrent),
+		)
+
+		It("should merge", func() {
+			a, b := cmn.VersionVector{"A": 2, "B": 1}, cmn.VersionVector{"B": 3, "C": 1}
+			merged := a.Merge(b)
+			Expect(merged).To(Equal(cmn.VersionVector{"A": 2, "B": 3, "C": 1}))
+			Expect(merged.Compare(a)).To(Equal(cmn.VVNewer))
+			Expect(merged.Compare(b)).To(Equal(cmn.VVNewer))
+			Expect(a).To(Equal(cmn.VersionVector{"A": 2, "B": 1})) // unmodified
+		})
+
+		DescribeTable("should resolve concurrent writes",
+			func(conf cmn.ReplicationConf, local, incoming cmn.ReplWrite, expected bool) {
+				Expect(conf.IncomingWins(local, incoming)).To(Equal(expected))
+				// deterministic: the other side comes to the same conclusion
+				Expect(conf.IncomingWins(incoming, local)).To(Equal(!expected))
+			},
+			Entry("last writer wins", cmn.ReplicationConf{},
+				cmn.ReplWrite{Origin: "A", Time: 1}, cmn.ReplWrite{Origin: "B", Time: 2}, true),
+			Entry("first writer wins", cmn.ReplicationConf{Resolve: apc.ReplResolveFWW},
+				cmn.ReplWrite{Origin: "A", Time: 1}, cmn.ReplWrite{Origin: "B", Time: 2}, false),
+			Entry("tie", cmn.ReplicationConf{},
+				cmn.ReplWrite{Origin: "A", Time: 1}, cmn.ReplWrite{Origin: "B", Time: 1}, true),
+			Entry("prefer cluster", cmn.ReplicationConf{Resolve: apc.ReplResolvePrefer, Prefer: "A"},
+				cmn.ReplWrite{Origin: "A", Time: 1}, cmn.ReplWrite{Origin: "B", Time: 2}, false),
+		)
+
+		It("should parse replicated write", func() {
+			w, err := cmn.ParseReplWrite("123,A")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(w).To(Equal(cmn.ReplWrite{Origin: "A", Time: 123}))
+			Expect(w.String()).To(Equal("123,A"))
+			_, err = cmn.ParseReplWrite("123")
+			Expect(err).To(HaveOccurred())
+			_, err = cmn.ParseReplWrite("x,A")
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn"
@@ -72,3 +73,48 @@ func TestValidateMpath(t *testing.T) {
 		}
 	}
 }
+
+func TestSchedWindow(t *testing.T) {
+	// Wednesday
+	at := func(hhmm string) time.Time {
+		tm, err := time.ParseInLocation("2006-01-02 15:04", "2024-05-15 "+hhmm, time.Local)
+		tassert.CheckFatal(t, err)
+		return tm
+	}
+	tests := []struct {
+		w   cmn.SchedWindow
+		now string
+		in  bool
+	}{
+		{cmn.SchedWindow{Begin: "01:00", End: "05:00"}, "00:59", false},
+		{cmn.SchedWindow{Begin: "01:00", End: "05:00"}, "01:00", true},
+		{cmn.SchedWindow{Begin: "01:00", End: "05:00"}, "05:00", false},
+		{cmn.SchedWindow{Begin: "22:00", End: "06:00"}, "23:30", true},
+		{cmn.SchedWindow{Begin: "22:00", End: "06:00"}, "05:59", true},
+		{cmn.SchedWindow{Begin: "22:00", End: "06:00"}, "12:00", false},
+		{cmn.SchedWindow{Begin: "00:00", End: "00:00"}, "12:00", true},
+		{cmn.SchedWindow{Begin: "01:00", End: "05:00", Days: "mon-fri"}, "02:00", true},
+		{cmn.SchedWindow{Begin: "01:00", End: "05:00", Days: "sat,sun"}, "02:00", false},
+		{cmn.SchedWindow{Begin: "22:00", End: "06:00", Days: "tue"}, "02:00", true}, // began on Tuesday
+		{cmn.SchedWindow{Begin: "22:00", End: "06:00", Days: "wed"}, "02:00", false},
+		{cmn.SchedWindow{Begin: "22:00", End: "06:00", Days: "fri-wed"}, "23:00", true},
+	}
+	for _, test := range tests {
+		conf := cmn.SchedConf{Windows: []cmn.SchedWindow{test.w}}
+		conf.Windows[0].Kind = apc.ActResilver
+		tassert.CheckFatal(t, conf.Validate())
+		in := test.w.Contains(at(test.now))
+		tassert.Errorf(t, in == test.in, "%s at %s: expected inside=%t", test.w.String(), test.now, test.in)
+	}
+
+	invalid := []cmn.SchedWindow{
+		{Begin: "01:00", End: "05:00"}, // no kind
+		{Kind: apc.ActResilver, Begin: "1am", End: "05:00"},
+		{Kind: apc.ActResilver, Begin: "01:00", End: "25:00"},
+		{Kind: apc.ActResilver, Begin: "01:00", End: "05:00", Days: "mon-friday"},
+	}
+	for _, w := range invalid {
+		conf := cmn.SchedConf{Windows: []cmn.SchedWindow{w}}
+		tassert.Errorf(t, conf.Validate() != nil, "expected %s to fail validation", w.String())
+	}
+}
@@ -74,6 +74,7 @@ var _ = Describe("IterFields", func() {
 					"ec.data_slices":       0,
 					"ec.objsize_limit":     int64(0),
 					"ec.compression":       "",
+					"ec.zstd_level":        0,
 					"ec.bundle_multiplier": 0,
 					"ec.disk_only":         false,
 
@@ -91,10 +92,11 @@ var _ = Describe("IterFields", func() {
 					"lru.dont_evict_time":   cos.Duration(0),
 					"lru.capacity_upd_time": cos.Duration(0),
 
-					"extra.aws.cloud_region": "us-central",
-					"extra.aws.endpoint":     "",
-					"extra.aws.profile":      "",
-					"extra.aws.max_pagesize": int64(0),
+					"extra.aws.cloud_region":   "us-central",
+					"extra.aws.endpoint":       "",
+					"extra.aws.profile":        "",
+					"extra.aws.requester_pays": false,
+					"extra.aws.max_pagesize":   int64(0),
 
 					"access":   apc.AccessAttrs(0),
 					"features": feat.Flags(0),
@@ -102,6 +104,31 @@ var _ = Describe("IterFields", func() {
 
 					"write_policy.data": apc.WritePolicy(""),
 					"write_policy.md":   apc.WritePolicy(""),
+
+					"xact.max_concurrent": 0,
+					"xact.max_bandwidth":  cos.SizeIEC(0),
+
+					"lifecycle.enabled": false,
+					"lifecycle.rules":   []cmn.LifecycleRule(nil),
+
+					"encryption.key_id":  "",
+					"encryption.enabled": false,
+
+					"audit.enabled": false,
+
+					"quota.size":    cos.SizeIEC(0),
+					"quota.objects": int64(0),
+
+					"object_lock.mode":      "",
+					"object_lock.retention": cos.Duration(0),
+
+					"replication.bck.name":      "",
+					"replication.bck.provider":  "",
+					"replication.conflict":      "",
+					"replication.resolve":       "",
+					"replication.prefer":        "",
+					"replication.enabled":       false,
+					"replication.active_active": false,
 				},
 			),
 			Entry("list BpropsToSet fields",
@@ -133,6 +160,7 @@ var _ = Describe("IterFields", func() {
 					"ec.data_slices":       (*int)(nil),
 					"ec.objsize_limit":     (*int64)(nil),
 					"ec.compression":       (*string)(nil),
+					"ec.zstd_level":        (*int)(nil),
 					"ec.bundle_multiplier": (*int)(nil),
 					"ec.disk_only":         (*bool)(nil),
 
@@ -156,12 +184,41 @@ var _ = Describe("IterFields", func() {
 					"write_policy.data": (*apc.WritePolicy)(nil),
 					"write_policy.md":   apc.Ptr(apc.WriteDelayed),
 
+					"xact.max_concurrent": (*int)(nil),
+					"xact.max_bandwidth":  (*cos.SizeIEC)(nil),
+
+					"lifecycle.enabled": (*bool)(nil),
+					"lifecycle.rules":   (*[]cmn.LifecycleRule)(nil),
+
+					"encryption.key_id":  (*string)(nil),
+					"encryption.enabled": (*bool)(nil),
+
+					"audit.enabled": (*bool)(nil),
+
+					"quota.size":    (*cos.SizeIEC)(nil),
+					"quota.objects": (*int64)(nil),
+
+					"object_lock.mode":      (*string)(nil),
+					"object_lock.retention": (*cos.Duration)(nil),
+
+					"replication.bck.name":      "",
+					"replication.bck.provider":  "",
+					"replication.conflict":      (*string)(nil),
+					"replication.resolve":       (*string)(nil),
+					"replication.prefer":        (*string)(nil),
+					"replication.enabled":       (*bool)(nil),
+					"replication.active_active": (*bool)(nil),
+
 					"extra.hdfs.ref_directory": (*string)(nil),
 					"extra.aws.cloud_region":   (*string)(nil),
 					"extra.aws.endpoint":       (*string)(nil),
 					"extra.aws.profile":        (*string)(nil),
+					"extra.aws.requester_pays": (*bool)(nil),
 					"extra.aws.max_pagesize":   (*int64)(nil),
+					"extra.gcp.kms_key":        (*string)(nil),
+					"extra.gcp.csek_file":      (*string)(nil),
 					"extra.http.original_url":  (*string)(nil),
+					"extra.http.manifest":      (*string)(nil),
 				},
 			),
 			Entry("check for omit tag",
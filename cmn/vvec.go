@@ -0,0 +1,200 @@
+// Package cmn provides common constants, types, and utilities for AIS clients
+// and AIStore.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/api/apc"
+)
+
+// Active-active replication (see ReplicationConf):
+// - version vector: cluster UUID => number of updates of a given object that originated
+//   in (or were resolved by) the cluster;
+// - a vector that dominates another describes a newer version; neither dominating
+//   means concurrent (conflicting) updates, to be resolved as per ReplicationConf.Resolve;
+// - ReplWrite identifies the object's content: time of the original write and its cluster.
+
+const (
+	VVEqual = iota
+	VVNewer
+	VVOlder
+	VVConcurrent
+)
+
+const (
+	vvSepa  = ","
+	vvKsepa = ":"
+)
+
+type (
+	VersionVector map[string]int64
+
+	ReplWrite struct {
+		Origin string // cluster UUID
+		Time   int64  // Unix nanoseconds
+	}
+)
+
+///////////////////
+// VersionVector //
+///////////////////
+
+// format: "uuid-A:3,uuid-B:1" (empty string: empty vector)
+func ParseVV(s string) (VersionVector, error) {
+	vv := make(VersionVector, 2)
+	if s == "" {
+		return vv, nil
+	}
+	for _, kv := range strings.Split(s, vvSepa) {
+		uuid, v, ok := strings.Cut(kv, vvKsepa)
+		if !ok || uuid == "" {
+			return nil, fmt.Errorf("invalid version vector %q", s)
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid version vector %q: bad counter %q", s, v)
+		}
+		if _, dup := vv[uuid]; dup {
+			return nil, fmt.Errorf("invalid version vector %q: duplicate %q", s, uuid)
+		}
+		vv[uuid] = n
+	}
+	return vv, nil
+}
+
+// (sorted by UUID)
+func (vv VersionVector) String() string {
+	if len(vv) == 0 {
+		return ""
+	}
+	uuids := make([]string, 0, len(vv))
+	for uuid := range vv {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+	var sb strings.Builder
+	for i, uuid := range uuids {
+		if i > 0 {
+			sb.WriteString(vvSepa)
+		}
+		sb.WriteString(uuid)
+		sb.WriteString(vvKsepa)
+		sb.WriteString(strconv.FormatInt(vv[uuid], 10))
+	}
+	return sb.String()
+}
+
+func (vv VersionVector) Clone() VersionVector {
+	clone := make(VersionVector, len(vv)+1)
+	for uuid, n := range vv {
+		clone[uuid] = n
+	}
+	return clone
+}
+
+func (vv VersionVector) Inc(uuid string) { vv[uuid]++ }
+
+// returns VVEqual, VVNewer (vv dominates other), VVOlder, or VVConcurrent
+func (vv VersionVector) Compare(other VersionVector) int {
+	var newer, older bool
+	for uuid, n := range vv {
+		switch m := other[uuid]; {
+		case n > m:
+			newer = true
+		case n < m:
+			older = true
+		}
+	}
+	for uuid, m := range other {
+		if _, ok := vv[uuid]; !ok && m > 0 {
+			older = true
+		}
+	}
+	switch {
+	case newer && older:
+		return VVConcurrent
+	case newer:
+		return VVNewer
+	case older:
+		return VVOlder
+	default:
+		return VVEqual
+	}
+}
+
+// element-wise max
+func (vv VersionVector) Merge(other VersionVector) VersionVector {
+	merged := vv.Clone()
+	for uuid, m := range other {
+		if m > merged[uuid] {
+			merged[uuid] = m
+		}
+	}
+	return merged
+}
+
+///////////////
+// ReplWrite //
+///////////////
+
+// format: "<Unix nanoseconds>,<cluster UUID>"
+func ParseReplWrite(s string) (w ReplWrite, err error) {
+	t, origin, ok := strings.Cut(s, vvSepa)
+	if !ok || origin == "" {
+		return w, fmt.Errorf("invalid replicated write %q", s)
+	}
+	if w.Time, err = strconv.ParseInt(t, 10, 64); err != nil {
+		return w, fmt.Errorf("invalid replicated write %q: %v", s, err)
+	}
+	w.Origin = origin
+	return w, nil
+}
+
+func (w ReplWrite) String() string {
+	if w.Origin == "" {
+		return ""
+	}
+	return strconv.FormatInt(w.Time, 10) + vvSepa + w.Origin
+}
+
+// the two writes are concurrent; returns true if the incoming one wins
+// (the outcome is deterministic and does not depend on which side resolves)
+func (c *ReplicationConf) IncomingWins(local, incoming ReplWrite) bool {
+	if c.Resolve == apc.ReplResolvePrefer {
+		switch {
+		case incoming.Origin == c.Prefer && local.Origin != c.Prefer:
+			return true
+		case local.Origin == c.Prefer && incoming.Origin != c.Prefer:
+			return false
+		}
+	}
+	if incoming.Time == local.Time {
+		return incoming.Origin > local.Origin // (tie-breaker)
+	}
+	if c.Resolve == apc.ReplResolveFWW {
+		return incoming.Time < local.Time
+	}
+	return incoming.Time > local.Time
+}
+
+// parse incoming replicated write (apc.HdrObjReplVV and apc.HdrObjReplWrite)
+func ParseReplHdr(vvs, ws string) (VersionVector, ReplWrite, error) {
+	var w ReplWrite
+	vv, err := ParseVV(vvs)
+	if err != nil {
+		return nil, w, err
+	}
+	if ws == "" {
+		return nil, w, errors.New("active-active replication: missing origin write")
+	}
+	w, err = ParseReplWrite(ws)
+	return vv, w, err
+}
@@ -57,4 +57,40 @@ type (
 		GetBucketInv(bck *meta.Bck, ctx *LsoInvCtx) (ecode int, err error)
 		ListObjectsInv(bck *meta.Bck, msg *apc.LsoMsg, lst *cmn.LsoRes, ctx *LsoInvCtx) error
 	}
+
+	// optional (type-asserted) Backend extension: object tags - key/value labels that are separate
+	// from user metadata (and are used, e.g., by lifecycle policies)
+	// per-provider limits:
+	// - aws: up to 10 tags per object; keys up to 128 and values up to 256 Unicode characters;
+	// - azure: blob index tags (same limits as above) - not implemented yet;
+	// - gcp: not supported (GCS has no object tags)
+	TagBackend interface {
+		GetObjTags(lom *LOM) (tags cos.StrKVs, ecode int, err error)
+		PutObjTags(lom *LOM, tags cos.StrKVs) (ecode int, err error)
+	}
+
+	// optional (type-asserted) Backend extension: PUT with server-side encryption (at rest)
+	// of the object stored by the backend (see apc.SSEMsg)
+	SSEBackend interface {
+		PutObjSSE(r io.ReadCloser, lom *LOM, sse *apc.SSEMsg, origReq *http.Request) (ecode int, err error)
+	}
+
+	// optional (type-asserted) Backend extension: remote storage tiers
+	// - azure: blob access tiers (Hot, Cool, Cold, Archive); see also cmn.AccessTierObjMD
+	TierBackend interface {
+		SetObjTier(lom *LOM, tier string) (ecode int, err error)
+	}
+
+	// optional (type-asserted) Backend extension: bucket usage and quota as reported by the remote
+	// storage itself (e.g., Ceph RGW admin-ops) - in lieu of listing all remote objects (see bucket summary);
+	// nil usage (with no error) means "not available for this bucket"
+	UsageBackend interface {
+		BucketUsage(bck *meta.Bck) (usage *BckUsage, ecode int, err error)
+	}
+	BckUsage struct {
+		ObjCount  uint64
+		Size      uint64
+		QuotaSize uint64 // zero: no quota
+		QuotaObjs uint64 // ditto
+	}
 )
@@ -6,6 +6,7 @@ package core
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/NVIDIA/aistore/cmn/cos"
@@ -112,6 +113,56 @@ func (lom *LOM) DelAllCopies() (err error) {
 	return lom.DelCopies(copiesFQN...)
 }
 
+// AppendToCopies extends existing copies, if any, with the content the main replica has
+// beyond `off` (i.e., the previous size) - compare with Copy() below;
+// copies that fail to get appended are removed
+// NOTE: the caller must w-lock
+func (lom *LOM) AppendToCopies(off int64, buf []byte) error {
+	if !lom.HasCopies() {
+		return nil
+	}
+	debug.Assert(lom.isLockedExcl(), lom.Cname())
+	src, err := os.Open(lom.FQN)
+	if err != nil {
+		return err
+	}
+	var failed []string
+	for copyFQN := range lom.md.copies {
+		if copyFQN == lom.FQN {
+			continue
+		}
+		if err := _appendCopy(src, copyFQN, off, lom.Lsize()-off, buf); err != nil {
+			nlog.Errorln("failed to append", lom.Cname(), "copy:", err, "- removing", copyFQN)
+			failed = append(failed, copyFQN)
+		}
+	}
+	cos.Close(src)
+	if len(failed) > 0 {
+		return lom.DelCopies(failed...) // (syncs metadata)
+	}
+	return lom.syncMetaWithCopies()
+}
+
+func _appendCopy(src *os.File, copyFQN string, off, size int64, buf []byte) error {
+	fh, err := os.OpenFile(copyFQN, os.O_WRONLY, cos.PermRWR)
+	if err != nil {
+		return err
+	}
+	var finfo os.FileInfo
+	if finfo, err = fh.Stat(); err == nil {
+		if finfo.Size() != off {
+			err = fmt.Errorf("copy %s size mismatch: %d vs %d", copyFQN, finfo.Size(), off)
+		} else if _, err = fh.Seek(off, io.SeekStart); err == nil {
+			_, err = io.CopyBuffer(fh, io.NewSectionReader(src, off, size), buf)
+		}
+	}
+	if err != nil {
+		fh.Close()
+		return err
+	}
+	return cos.FlushClose(fh)
+}
+
 // DelExtraCopies deletes obj replicas that are not part of the lom.md.copies metadata
 // (cleanup)
 func (lom *LOM) DelExtraCopies(fqn ...string) (removed bool, err error) {
@@ -285,10 +336,20 @@ func (lom *LOM) copy2fqn(dst *LOM, buf []byte) (err error) {
 		dstFQN    = dst.FQN
 		srcCksum  = lom.Checksum()
 		cksumType = cos.ChecksumNone
+		recompute bool
 	)
 	if !srcCksum.IsEmpty() {
 		cksumType = srcCksum.Ty()
 	}
+	// destination bucket with a different checksum type: compute the latter
+	// while copying and, separately, the source's - to validate it (`vsrc`)
+	var vsrc *cos.CksumHash
+	if ty := dst.CksumType(); ty != "" && ty != cos.ChecksumNone && ty != cksumType {
+		if cksumType != cos.ChecksumNone {
+			vsrc = cos.NewCksumHash(cksumType)
+		}
+		cksumType, recompute = ty, true
+	}
 	if dst.isMirror(lom) && lom.md.copies != nil {
 		dst.md.copies = make(fs.MPI, len(lom.md.copies)+1)
 		for fqn, mpi := range lom.md.copies {
@@ -301,10 +362,31 @@ func (lom *LOM) copy2fqn(dst *LOM, buf []byte) (err error) {
 	}
 
 	workFQN := fs.CSM.Gen(dst, fs.WorkfileType, fs.WorkfileCopy)
-	_, dstCksum, err = cos.CopyFile(lom.FQN, workFQN, buf, cksumType)
+	switch {
+	case lom.md.sse != "" && lom.ObjName == dst.ObjName && lom.Bck().Equal(dst.Bck(), true, true):
+		// encrypted mirror or the same object elsewhere (e.g., restore): copy as is (same data key,
+		// including client-supplied one - see sse.IsCustomer)
+		_, _, err = cos.CopyFile(lom.FQN, workFQN, buf, cos.ChecksumNone)
+		cksumType, vsrc = cos.ChecksumNone, nil
+	case lom.md.sse != "" || dst.EncryptionConf().Enabled:
+		dstCksum, err = lom.copyPlain(dst, workFQN, buf, cksumType, vsrc)
+	case vsrc != nil:
+		dstCksum, err = lom.copyValidate(workFQN, buf, cksumType, vsrc)
+	default:
+		_, dstCksum, err = cos.CopyFile(lom.FQN, workFQN, buf, cksumType)
+	}
 	if err != nil {
 		return
 	}
+	if vsrc != nil {
+		vsrc.Finalize()
+		if !vsrc.Equal(srcCksum) {
+			if errRemove := cos.RemoveFile(workFQN); errRemove != nil && !os.IsNotExist(errRemove) {
+				nlog.Errorln("nested err:", errRemove)
+			}
+			return cos.NewErrDataCksum(&vsrc.Cksum, srcCksum, lom.Cname())
+		}
+	}
 
 	if err = cos.Rename(workFQN, dstFQN); err != nil {
 		if errRemove := cos.RemoveFile(workFQN); errRemove != nil && !os.IsNotExist(errRemove) {
@@ -314,7 +396,7 @@ func (lom *LOM) copy2fqn(dst *LOM, buf []byte) (err error) {
 	}
 
 	if cksumType != cos.ChecksumNone {
-		if !dstCksum.Equal(lom.Checksum()) {
+		if !recompute && !dstCksum.Equal(lom.Checksum()) {
 			return cos.NewErrDataCksum(&dstCksum.Cksum, lom.Checksum())
 		}
 		dst.SetCksum(dstCksum.Clone())
@@ -349,6 +431,67 @@ func (lom *LOM) copy2fqn(dst *LOM, buf []byte) (err error) {
 	return
 }
 
+// decrypt and/or encrypt while copying (see fs/sse)
+// - vsrc (optional): source checksum to compute on the fly
+func (lom *LOM) copyPlain(dst *LOM, workFQN string, buf []byte, cksumType string, vsrc *cos.CksumHash) (cksum *cos.CksumHash, err error) {
+	var (
+		lmfh cos.LomReader
+		wfh  cos.LomWriter
+	)
+	if lmfh, err = lom.Open(); err != nil {
+		return nil, err
+	}
+	if wfh, err = dst.CreateWork(workFQN); err != nil {
+		cos.Close(lmfh)
+		return nil, err
+	}
+	var r io.Reader = lmfh
+	if vsrc != nil {
+		r = io.TeeReader(lmfh, vsrc.H)
+	}
+	_, cksum, err = cos.CopyAndChecksum(wfh, r, buf, cksumType)
+	cos.Close(lmfh)
+	if errC := wfh.Close(); err == nil {
+		err = errC
+	}
+	if err != nil {
+		if errRemove := cos.RemoveFile(workFQN); errRemove != nil && !os.IsNotExist(errRemove) {
+			nlog.Errorln("nested err:", errRemove)
+		}
+	}
+	return cksum, err
+}
+
+// copy and compute destination checksum while also computing the source's (of a different type)
+func (lom *LOM) copyValidate(workFQN string, buf []byte, cksumType string, vsrc *cos.CksumHash) (cksum *cos.CksumHash, err error) {
+	var (
+		fh  *os.File
+		wfh *os.File
+	)
+	if fh, err = os.Open(lom.FQN); err != nil {
+		return nil, err
+	}
+	if wfh, err = cos.CreateFile(workFQN); err != nil {
+		cos.Close(fh)
+		return nil, err
+	}
+	_, cksum, err = cos.CopyAndChecksum(wfh, io.TeeReader(fh, vsrc.H), buf, cksumType)
+	cos.Close(fh)
+	if err == nil {
+		if err = cos.FlushClose(wfh); err != nil {
+			wfh.Close()
+		}
+	} else {
+		cos.Close(wfh)
+	}
+	if err != nil {
+		if errRemove := cos.RemoveFile(workFQN); errRemove != nil && !os.IsNotExist(errRemove) {
+			nlog.Errorln("nested err:", errRemove)
+		}
+	}
+	return cksum, err
+}
+
 // load-balanced GET
 func (lom *LOM) LBGet() (fqn string) {
 	if !lom.HasCopies() {
@@ -13,6 +13,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/cmn/feat"
+	"github.com/NVIDIA/aistore/fs/sse"
 )
 
 // NOTE: compare with ext/etl/dp.go
@@ -50,6 +51,21 @@ func (r *deferROC) Close() (err error) {
 
 // is called under rlock; unlocks on fail
 func (lom *LOM) NewDeferROC() (cos.ReadOpenCloser, error) {
+	fh, err := lom.NewHandle(lom.FQN)
+	if err == nil {
+		return &deferROC{fh, lom.LIF()}, nil
+	}
+	lom.Unlock(false)
+	return nil, cmn.NewErrFailedTo(T, "open", lom.Cname(), err)
+}
+
+// same as NewDeferROC except for objects encrypted with client-supplied keys that cannot
+// be decrypted in the cluster and are therefore migrated (rebalance, decommission) as is;
+// the caller must then set the corresponding attributes (see SetMigrateAttrs)
+func (lom *LOM) NewMigrateROC() (cos.ReadOpenCloser, error) {
+	if !sse.IsCustomer(lom.md.sse) {
+		return lom.NewDeferROC()
+	}
 	fh, err := cos.NewFileHandle(lom.FQN)
 	if err == nil {
 		return &deferROC{fh, lom.LIF()}, nil
@@ -58,6 +74,15 @@ func (lom *LOM) NewDeferROC() (cos.ReadOpenCloser, error) {
 	return nil, cmn.NewErrFailedTo(T, "open", lom.Cname(), err)
 }
 
+// on-disk size and data key reference of the object that is being migrated as is
+// (see NewMigrateROC and, on the receiving side, cmn.SSECustomerObjMD)
+func (lom *LOM) SetMigrateAttrs(oa *cmn.ObjAttrs) {
+	if sse.IsCustomer(lom.md.sse) {
+		oa.Size = sse.FileSize(lom.md.Size)
+		oa.SetCustomKey(cmn.SSECustomerObjMD, lom.md.sse)
+	}
+}
+
 // (compare with ext/etl/dp.go)
 func (*LDP) Reader(lom *LOM, latestVer, sync bool) (cos.ReadOpenCloser, cos.OAH, error) {
 	lom.Lock(false)
@@ -13,6 +13,8 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/fs/sse"
 )
 
 const (
@@ -36,10 +38,13 @@ func (e *errBdir) Error() string {
 // open
 //
 
-func (lom *LOM) Open() (fh cos.LomReader, err error) {
-	fh, err = os.Open(lom.FQN)
-	if err == nil || !os.IsNotExist(err) {
-		return fh, err
+func (lom *LOM) Open() (cos.LomReader, error) {
+	fh, err := os.Open(lom.FQN)
+	if err == nil {
+		return lom.decrypt(fh, lom.FQN)
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
 	}
 	if e := lom._checkBdir(); e != nil {
 		return nil, e
@@ -47,18 +52,130 @@ func (lom *LOM) Open() (fh cos.LomReader, err error) {
 	return nil, err
 }
 
+// open a given copy (see LBGet), or the main replica
+func (lom *LOM) OpenCopy(fqn string) (cos.LomReader, error) {
+	fh, err := os.Open(fqn)
+	if err != nil {
+		return nil, err
+	}
+	return lom.decrypt(fh, fqn)
+}
+
+// (compare with OpenCopy above)
+func (lom *LOM) NewHandle(fqn string) (cos.ReadOpenCloser, error) {
+	if lom.md.sse == "" {
+		return cos.NewFileHandle(fqn)
+	}
+	fh, err := os.Open(fqn)
+	if err != nil {
+		return nil, err
+	}
+	r, err := lom.decrypt(fh, fqn)
+	if err != nil {
+		return nil, err
+	}
+	return r.(cos.ReadOpenCloser), nil
+}
+
+// server-side encryption at rest (see fs/sse)
+func (lom *LOM) decrypt(fh *os.File, fqn string) (cos.LomReader, error) {
+	if lom.md.sse == "" {
+		return fh, nil
+	}
+	dk, err := sse.Lookup(lom.md.sse)
+	return lom._decrypt(fh, fqn, dk, err)
+}
+
+func (lom *LOM) _decrypt(fh *os.File, fqn string, dk *sse.DataKey, err error) (cos.LomReader, error) {
+	if err == nil {
+		var r *sse.Reader
+		if r, err = sse.NewReader(fh, fqn, lom.ObjName, dk); err == nil {
+			return r, nil
+		}
+	}
+	fh.Close()
+	return nil, fmt.Errorf("%s: %w", lom.Cname(), err)
+}
+
+// open with the client-supplied key (validating the latter) - see sse.CustomerKey
+func (lom *LOM) OpenSSEC(fqn string, key []byte) (cos.LomReader, error) {
+	dk, err := sse.CustomerKey(lom.md.sse, key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", lom.Cname(), err)
+	}
+	fh, err := os.Open(fqn)
+	if err != nil {
+		return nil, err
+	}
+	return lom._decrypt(fh, fqn, dk, nil)
+}
+
+func (lom *LOM) encrypt(fh *os.File, fqn string) (cos.LomWriter, error) {
+	conf := lom.EncryptionConf()
+	if !conf.Enabled {
+		lom.md.sse = ""
+		return fh, nil
+	}
+	dk, err := sse.Current(lom.Bprops().BID, conf.KeyID)
+	return lom._encrypt(fh, fqn, dk, err)
+}
+
+func (lom *LOM) _encrypt(fh *os.File, fqn string, dk *sse.DataKey, err error) (cos.LomWriter, error) {
+	if err == nil {
+		var w *sse.Writer
+		if w, err = sse.NewWriter(fh, dk, lom.ObjName); err == nil {
+			lom.md.sse = dk.Ref
+			return w, nil
+		}
+	}
+	fh.Close()
+	if errRemove := cos.RemoveFile(fqn); errRemove != nil {
+		nlog.Errorln("nested err:", errRemove)
+	}
+	return nil, fmt.Errorf("%s: %w", lom.Cname(), err)
+}
+
 //
 // create
 //
 
 func (lom *LOM) Create() (cos.LomWriter, error) {
 	debug.Assert(lom.isLockedExcl(), lom.Cname()) // caller must wlock
-	return lom._cf(lom.FQN)
+	return lom.CreateWork(lom.FQN)
+}
+
+// NOTE: encrypted buckets: creating (and writing) sets the object's data key reference
+// (lom metadata) - the caller must eventually persist it
+func (lom *LOM) CreateWork(wfqn string) (cos.LomWriter, error) { // -> lom
+	fh, err := lom._cf(wfqn)
+	if err != nil {
+		return nil, err
+	}
+	return lom.encrypt(fh, wfqn)
+}
+
+// encrypt with the client-supplied key that is never stored - only its HMAC (see sse.NewCustomerKey)
+func (lom *LOM) CreateWorkSSEC(wfqn string, key []byte) (cos.LomWriter, error) {
+	fh, err := lom._cf(wfqn)
+	if err != nil {
+		return nil, err
+	}
+	dk, err := sse.NewCustomerKey(key)
+	return lom._encrypt(fh, wfqn, dk, err)
+}
+
+// write already encrypted content as is, with its data key reference (see NewMigrateROC)
+func (lom *LOM) CreateWorkRaw(wfqn, ref string) (cos.LomWriter, error) {
+	fh, err := lom._cf(wfqn)
+	if err != nil {
+		return nil, err
+	}
+	lom.md.sse = ref
+	return fh, nil
 }
 
-func (lom *LOM) CreateWork(wfqn string) (cos.LomWriter, error) { return lom._cf(wfqn) } // -> lom
-func (lom *LOM) CreatePart(wfqn string) (*os.File, error)      { return lom._cf(wfqn) } // TODO: differentiate
-func (lom *LOM) CreateSlice(wfqn string) (*os.File, error)     { return lom._cf(wfqn) } // TODO: ditto
+func (lom *LOM) CreatePart(wfqn string) (*os.File, error)  { return lom._cf(wfqn) } // TODO: differentiate
+func (lom *LOM) CreateSlice(wfqn string) (*os.File, error) { return lom._cf(wfqn) } // TODO: ditto
 
 func (lom *LOM) _cf(fqn string) (fh *os.File, err error) {
 	fh, err = os.OpenFile(fqn, _openFlags, cos.PermRWR)
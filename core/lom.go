@@ -23,6 +23,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn/nlog"
 	"github.com/NVIDIA/aistore/core/meta"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/sse"
 	"github.com/NVIDIA/aistore/ios"
 	"github.com/NVIDIA/aistore/memsys"
 )
@@ -48,9 +49,10 @@ const (
 )
 
 type (
-	lmeta struct { // sizeof = 72
+	lmeta struct { // sizeof = 88
 		copies fs.MPI
 		uname  *string
+		sse    string // encrypted at rest: data key reference (see fs/sse)
 		cmn.ObjAttrs
 		atimefs uint64 // (high bit `lomDirtyMask` | int64: atime)
 		lid     lomBID
@@ -199,12 +201,13 @@ func (lom *LOM) IsHRW() bool {
 func (lom *LOM) Bprops() *cmn.Bprops { return lom.bck.Props }
 
 // bprops accessors for convenience
-func (lom *LOM) ECEnabled() bool                { return lom.Bprops().EC.Enabled }
-func (lom *LOM) IsFeatureSet(f feat.Flags) bool { return lom.Bprops().Features.IsSet(f) }
-func (lom *LOM) MirrorConf() *cmn.MirrorConf    { return &lom.Bprops().Mirror }
-func (lom *LOM) CksumConf() *cmn.CksumConf      { return lom.bck.CksumConf() }
-func (lom *LOM) CksumType() string              { return lom.bck.CksumConf().Type }
-func (lom *LOM) VersionConf() cmn.VersionConf   { return lom.bck.VersionConf() }
+func (lom *LOM) ECEnabled() bool                     { return lom.Bprops().EC.Enabled }
+func (lom *LOM) EncryptionConf() *cmn.EncryptionConf { return &lom.Bprops().Encryption }
+func (lom *LOM) IsFeatureSet(f feat.Flags) bool      { return lom.Bprops().Features.IsSet(f) }
+func (lom *LOM) MirrorConf() *cmn.MirrorConf         { return &lom.Bprops().Mirror }
+func (lom *LOM) CksumConf() *cmn.CksumConf           { return lom.bck.CksumConf() }
+func (lom *LOM) CksumType() string                   { return lom.bck.CksumConf().Type }
+func (lom *LOM) VersionConf() cmn.VersionConf        { return lom.bck.VersionConf() }
 
 // as fs.PartsFQN
 func (lom *LOM) ObjectName() string       { return lom.ObjName }
@@ -213,6 +216,9 @@ func (lom *LOM) Bucket() *cmn.Bck         { return (*cmn.Bck)(&lom.bck) }
 func (lom *LOM) Mountpath() *fs.Mountpath { return lom.mi }
 func (lom *LOM) Location() string         { return T.String() + apc.LocationPropSepa + lom.mi.String() }
 
+// server-side encryption at rest: reference to the data key that encrypted the object (empty - plaintext)
+func (lom *LOM) SSERef() string { return lom.md.sse }
+
 // chunks vs whole // TODO -- FIXME: NIY
 func (lom *LOM) IsChunked(special ...bool) bool {
 	debug.Assert(len(special) > 0 || lom.loaded())
@@ -583,6 +589,9 @@ func (lom *LOM) FromFS() error {
 		return err
 	}
 	// fstat & atime
+	if lom.md.sse != "" {
+		size = lom.md.Size + size - sse.FileSize(lom.md.Size) // (plaintext)
+	}
 	if lom.md.Size != size { // corruption or tampering
 		return cmn.NewErrLmetaCorrupted(lom.whingeSize(size))
 	}
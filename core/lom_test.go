@@ -33,6 +33,8 @@ var _ = Describe("LOM", func() {
 		bucketLocalA = "LOM_TEST_Local_A"
 		bucketLocalB = "LOM_TEST_Local_B"
 		bucketLocalC = "LOM_TEST_Local_C"
+		bucketLocalD = "LOM_TEST_Local_D"
+		bucketLocalE = "LOM_TEST_Local_E"
 
 		bucketCloudA = "LOM_TEST_Cloud_A"
 		bucketCloudB = "LOM_TEST_Cloud_B"
@@ -90,6 +92,14 @@ var _ = Describe("LOM", func() {
 				BID:    3,
 			},
 		),
+		meta.NewBck(
+			bucketLocalD, apc.AIS, cmn.NsGlobal,
+			&cmn.Bprops{Cksum: cmn.CksumConf{Type: cos.ChecksumSHA256}, BID: 8},
+		),
+		meta.NewBck(
+			bucketLocalE, apc.AIS, cmn.NsGlobal,
+			&cmn.Bprops{Cksum: cmn.CksumConf{Type: cos.ChecksumMD5}, BID: 9},
+		),
 		meta.NewBck(sameBucketName, apc.AIS, cmn.NsGlobal, &cmn.Bprops{BID: 4}),
 		meta.NewBck(bucketCloudA, apc.AWS, cmn.NsGlobal, &cmn.Bprops{BID: 5}),
 		meta.NewBck(bucketCloudB, apc.AWS, cmn.NsGlobal, &cmn.Bprops{BID: 6}),
@@ -659,6 +669,10 @@ var _ = Describe("LOM", func() {
 			findMpath(testObjectName, bucketLocalB, false /*defaultLoc*/),
 		}
 
+		// Buckets with different checksum types
+		otherCksumFQN := findMpath(testObjectName, bucketLocalD, true /*defaultLoc*/)
+		thirdCksumFQN := findMpath(testObjectName, bucketLocalE, true /*defaultLoc*/)
+
 		prepareLOM := func(fqn string) (lom *core.LOM) {
 			// Prepares a basic lom with a copy
 			createTestFile(fqn, testFileSize)
@@ -921,6 +935,49 @@ var _ = Describe("LOM", func() {
 				Expect(copyLOM.NumCopies()).To(Equal(lom.NumCopies()))
 				Expect(copyLOM.GetCopies()).To(Equal(lom.GetCopies()))
 			})
+
+			It("should compute checksum of the destination bucket's type", func() {
+				lom := prepareLOM(mirrorFQNs[0])
+				copyLOM := prepareCopy(lom, otherCksumFQN)
+
+				Expect(lom.Checksum().Ty()).To(Equal(cos.ChecksumXXHash))
+				Expect(copyLOM.Checksum().Ty()).To(Equal(cos.ChecksumSHA256))
+				cksum, err := copyLOM.ComputeCksum(cos.ChecksumSHA256)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(copyLOM.Checksum().Equal(cksum.Clone())).To(BeTrue())
+				Expect(copyLOM.IsCopy()).To(BeFalse())
+				Expect(copyLOM.HasCopies()).To(BeFalse())
+			})
+
+			It("should compute checksum of each destination bucket's type", func() {
+				lom := prepareLOM(mirrorFQNs[0])
+				for fqn, ty := range map[string]string{otherCksumFQN: cos.ChecksumSHA256, thirdCksumFQN: cos.ChecksumMD5} {
+					copyLOM := prepareCopy(lom, fqn)
+					Expect(copyLOM.Checksum().Ty()).To(Equal(ty))
+					cksum, err := copyLOM.ComputeCksum(ty)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(copyLOM.Checksum().Equal(cksum.Clone())).To(BeTrue())
+				}
+				Expect(lom.Checksum().Ty()).To(Equal(cos.ChecksumXXHash))
+			})
+
+			It("should validate the source while computing destination checksum of a different type", func() {
+				lom := prepareLOM(mirrorFQNs[0])
+
+				// corrupt the source (same size, different content)
+				b, err := os.ReadFile(lom.FQN)
+				Expect(err).NotTo(HaveOccurred())
+				b[0] ^= 0xff
+				Expect(os.WriteFile(lom.FQN, b, cos.PermRWR)).NotTo(HaveOccurred())
+
+				lom.Lock(true)
+				dst, err := lom.Copy2FQN(otherCksumFQN, make([]byte, testFileSize))
+				lom.Unlock(true)
+				Expect(err).To(HaveOccurred())
+				Expect(cos.IsErrBadCksum(err)).To(BeTrue())
+				Expect(dst).To(BeNil())
+				Expect(otherCksumFQN).NotTo(BeAnExistingFile())
+			})
 		})
 
 		Describe("DelCopies", func() {
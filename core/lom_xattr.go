@@ -75,6 +75,7 @@ const (
 	packedCustom
 	packedNum
 	packedChunk
+	packedSSE
 )
 
 // packing format: separators
@@ -316,6 +317,7 @@ func (md *lmeta) unpack(buf []byte) error {
 		return cos.NewErrMetaCksum(expectedCksum, actualCksum, md.String())
 	}
 
+	md.sse = ""
 	for off := 0; !last; {
 		var (
 			record []byte
@@ -389,6 +391,8 @@ func (md *lmeta) unpack(buf []byte) error {
 				custom[entries[i]] = entries[i+1]
 			}
 			md.SetCustomMD(custom)
+		case packedSSE:
+			md.sse = string(record[cos.SizeofI16:])
 		default:
 			return errors.New(badLmeta + " #6")
 		}
@@ -436,6 +440,12 @@ func (md *lmeta) pack(mdSize int64) (buf []byte) {
 		buf = _packCustom(buf, custom)
 	}
 
+	// data key (encrypted at rest)
+	if md.sse != "" {
+		buf = g.smm.Append(buf, recordSepa)
+		buf = _packRecord(buf, packedSSE, md.sse, false)
+	}
+
 	// checksum, prepend, and return
 	buf[0] = cmn.MetaverLOM
 	buf[1] = mdCksumTyXXHash
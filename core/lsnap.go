@@ -0,0 +1,113 @@
+// Package core provides core metadata and in-cluster API
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// bucket snapshots: snapshotted objects are hard links to their respective main replicas (see fs.SnapType)
+
+// add the main replica to a snapshot
+// NOTE: the caller must r-lock
+func (lom *LOM) LinkToSnap(sfqn string) error {
+	if err := cos.CreateDir(filepath.Dir(sfqn)); err != nil {
+		return err
+	}
+	return os.Link(lom.FQN, sfqn)
+}
+
+// restore the object from a given snapshot, replacing its current version, if any
+// NOTE: the caller must w-lock
+func (lom *LOM) RestoreFromSnap(sfqn string, buf []byte) error {
+	debug.Assert(lom.isLockedExcl(), lom.Cname())
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err == nil {
+		if err := lom.DelAllCopies(); err != nil {
+			nlog.Errorln(lom.Cname(), "failed to remove copies:", err)
+		}
+	}
+	lom.Uncache()
+
+	wfqn := fs.CSM.Gen(lom, fs.WorkfileType, fs.WorkfileRestore)
+	err := cos.CreateDir(filepath.Dir(wfqn))
+	if err == nil {
+		err = os.Link(sfqn, wfqn)
+		if errors.Is(err, syscall.EXDEV) {
+			// snapshotted on a different mountpath
+			err = copyCT(sfqn, wfqn, buf)
+		}
+	}
+	if err == nil {
+		err = lom.RenameToMain(wfqn)
+	}
+	if err != nil {
+		if nerr := cos.RemoveFile(wfqn); nerr != nil {
+			nlog.Errorln("nested err:", nerr)
+		}
+		return err
+	}
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		return err
+	}
+	if lom.md.copies != nil {
+		lom.md.copies = nil // (as of the time of snapshot)
+		return lom.PersistMain()
+	}
+	return nil
+}
+
+// copy-on-write: same as RenameMainTo unless the main replica is captured by a snapshot,
+// in which case its content and metadata are copied - to modify the copy in place
+// NOTE: the caller must w-lock
+func (lom *LOM) DetachMainTo(wfqn string, buf []byte) error {
+	n, err := cos.NumLinks(lom.FQN)
+	if err != nil {
+		return err
+	}
+	if n <= 1 {
+		return lom.RenameMainTo(wfqn)
+	}
+	return copyCT(lom.FQN, wfqn, buf)
+}
+
+// object metadata of a snapshotted version
+func SnapAttrs(sfqn string) (*cmn.ObjAttrs, error) {
+	b, err := fs.GetXattr(sfqn, XattrLOM)
+	if err != nil {
+		return nil, err
+	}
+	md := &lmeta{}
+	if err := md.unpack(b); err != nil {
+		return nil, cmn.NewErrLmetaCorrupted(err)
+	}
+	return &md.ObjAttrs, nil
+}
+
+// copy content and metadata
+func copyCT(src, dst string, buf []byte) error {
+	md, err := fs.GetXattr(src, XattrLOM)
+	if err != nil {
+		return err
+	}
+	if _, _, err := cos.CopyFile(src, dst, buf, cos.ChecksumNone); err != nil {
+		return err
+	}
+	if err := fs.SetXattr(dst, XattrLOM, md); err != nil {
+		if nerr := cos.RemoveFile(dst); nerr != nil {
+			nlog.Errorln("nested err:", nerr)
+		}
+		return err
+	}
+	return nil
+}
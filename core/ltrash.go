@@ -0,0 +1,70 @@
+// Package core provides core metadata and in-cluster API
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package core
+
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// soft-delete (compare with RemoveObj): rename the main replica into trash
+// (see fs.TrashType) and remove copies, if any
+// NOTE: the caller must w-lock
+func (lom *LOM) MoveToTrash(deleted int64) error {
+	debug.Assert(lom.isLockedExcl(), lom.Cname())
+	lom.Uncache()
+	tfqn := fs.CSM.Gen(lom, fs.TrashType, fs.TrashPrefix(deleted))
+	if err := cos.Rename(lom.FQN, tfqn); err != nil {
+		if os.IsNotExist(err) {
+			err = nil // (compare with cos.RemoveFile)
+		}
+		return err
+	}
+	for copyFQN := range lom.md.copies {
+		if copyFQN == lom.FQN {
+			continue
+		}
+		if err := cos.RemoveFile(copyFQN); err != nil && !os.IsNotExist(err) {
+			nlog.Errorln(lom.Cname(), "failed to remove copy:", err)
+		}
+	}
+	lom.md.lid = 0
+	return nil
+}
+
+// undelete (compare with MoveToTrash)
+// NOTE: the caller must w-lock
+func (lom *LOM) RestoreFromTrash(tfqn string, buf []byte) error {
+	debug.Assert(lom.isLockedExcl(), lom.Cname())
+	err := cos.Rename(tfqn, lom.FQN)
+	if errors.Is(err, syscall.EXDEV) {
+		// trashed on a different mountpath
+		err = lom.restoreX(tfqn, buf)
+	}
+	if err != nil {
+		return err
+	}
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		return err
+	}
+	if lom.md.copies != nil {
+		lom.md.copies = nil // (removed when trashed)
+		return lom.PersistMain()
+	}
+	return nil
+}
+
+func (lom *LOM) restoreX(tfqn string, buf []byte) error {
+	if err := copyCT(tfqn, lom.FQN, buf); err != nil {
+		return err
+	}
+	return cos.RemoveFile(tfqn)
+}
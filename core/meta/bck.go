@@ -256,6 +256,9 @@ func (b *Bck) MaxPageSize() int64 {
 	case apc.OCI:
 		// ref: https://docs.oracle.com/en-us/iaas/api/#/en/objectstorage/20160918/Object/ListObjects
 		return apc.MaxPageSizeOCI
+	case apc.B2:
+		// ref: https://www.backblaze.com/apidocs/b2-list-file-names
+		return apc.MaxPageSizeB2
 	default:
 		return 1000
 	}
@@ -6,6 +6,7 @@ package meta
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn"
@@ -18,6 +19,23 @@ import (
 // A variant of consistent hash based on rendezvous algorithm by Thaler and Ravishankar,
 // aka highest random weight (HRW)
 // See also: fs/hrw.go
+//
+// Object placement (HrwHash2T and friends) becomes weighted once the per-target rebalance weights
+// are set (see RMD.Weights) - the target's share of the data is then proportional to its weight
+
+// weighted rendezvous: w / -ln(u), with u in (0, 1) derived from the (uniform) hash;
+// (bits of the positive float64s are ordered the same way as the floats themselves)
+func weigh(cs uint64, tid string, weights apc.RebWeights) uint64 {
+	if weights == nil {
+		return cs
+	}
+	w, ok := weights[tid]
+	if !ok {
+		w = apc.MaxRebWeight
+	}
+	u := (float64(cs>>11) + 0.5) / (1 << 53)
+	return math.Float64bits(float64(w) / -math.Log(u))
+}
 
 func (smap *Smap) HrwName2T(uname []byte) (*Snode, error) {
 	digest := xxhash.Checksum64S(uname, cos.MLCG32)
@@ -38,12 +56,15 @@ func (smap *Smap) HrwMultiHome(uname []byte) (si *Snode, netName string, err err
 }
 
 func (smap *Smap) HrwHash2T(digest uint64) (si *Snode, err error) {
-	var maxH uint64
+	var (
+		maxH    uint64
+		weights = loadWeights()
+	)
 	for _, tsi := range smap.Tmap {
 		if tsi.InMaintOrDecomm() { // always skipping targets 'in maintenance mode'
 			continue
 		}
-		cs := xoshiro256.Hash(tsi.Digest() ^ digest)
+		cs := weigh(xoshiro256.Hash(tsi.Digest()^digest), tsi.ID(), weights)
 		if cs >= maxH {
 			maxH = cs
 			si = tsi
@@ -57,9 +78,12 @@ func (smap *Smap) HrwHash2T(digest uint64) (si *Snode, err error) {
 
 // NOTE: including targets 'in maintenance mode', if any
 func (smap *Smap) HrwHash2Tall(digest uint64) (si *Snode, err error) {
-	var maxH uint64
+	var (
+		maxH    uint64
+		weights = loadWeights()
+	)
 	for _, tsi := range smap.Tmap {
-		cs := xoshiro256.Hash(tsi.Digest() ^ digest)
+		cs := weigh(xoshiro256.Hash(tsi.Digest()^digest), tsi.ID(), weights)
 		if cs >= maxH {
 			maxH = cs
 			si = tsi
@@ -162,11 +186,13 @@ func (smap *Smap) HrwTargetList(uname *string, count int) (sis Nodes, err error)
 		return
 	}
 	b := cos.UnsafeBptr(uname)
-	digest := xxhash.Checksum64S(*b, cos.MLCG32)
-	hlist := newHrwList(count)
-
+	var (
+		digest  = xxhash.Checksum64S(*b, cos.MLCG32)
+		hlist   = newHrwList(count)
+		weights = loadWeights()
+	)
 	for _, tsi := range smap.Tmap {
-		cs := xoshiro256.Hash(tsi.Digest() ^ digest)
+		cs := weigh(xoshiro256.Hash(tsi.Digest()^digest), tsi.ID(), weights)
 		if tsi.InMaintOrDecomm() {
 			continue
 		}
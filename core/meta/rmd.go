@@ -4,13 +4,126 @@
  */
 package meta
 
+import (
+	"encoding/json"
+	"sync/atomic"
+
+	"github.com/NVIDIA/aistore/api/apc"
+)
+
+// RMD.Ext keys
+const (
+	RMDExtWeights = "weights" // apc.RebWeights
+)
+
 type (
 	// Rebalance MetaData
 	RMD struct {
-		Ext       any      `json:"ext,omitempty"` // within meta-version extensions
-		CluID     string   `json:"cluster_id"`    // effectively, Smap.UUID
-		Resilver  string   `json:"resilver,omitempty"`
-		TargetIDs []string `json:"target_ids,omitempty"`
-		Version   int64    `json:"version"`
+		// within meta-version extensions
+		// - kept raw (undecoded) so that the nodes that do not (or not yet) understand
+		//   the content would still pass it on verbatim - e.g., during rolling upgrade;
+		// - otherwise, round-tripping through `any` would reorder the keys, lose
+		//   integer precision, and so on
+		Ext       json.RawMessage `json:"ext,omitempty"`
+		CluID     string          `json:"cluster_id"` // effectively, Smap.UUID
+		Resilver  string          `json:"resilver,omitempty"`
+		TargetIDs []string        `json:"target_ids,omitempty"`
+		Version   int64           `json:"version"`
 	}
 )
+
+// AffectedTargets scopes the rebalance (this RMD) to the targets that have joined or left
+// since `prev` - the last RMD whose rebalance has successfully completed (on the calling node).
+//
+// Contract:
+//   - TargetIDs, when not empty, lists _all_ targets whose membership changes in this version;
+//     those present and active in the new `smap` have joined, the rest (absent, or in maintenance
+//     or being decommissioned) are leaving;
+//   - given a single join (or leave), only the objects that map onto (or from) the affected targets
+//     need to move - consumers may skip the rest;
+//   - ok == false means that the rebalance cannot be scoped - consumers must rebalance everything:
+//     no previous (completed) RMD, missed (or interrupted) versions in between, different cluster,
+//     resilvering, or empty TargetIDs - the latter being the case when a producer does not
+//     (or cannot) enumerate the targets in question
+func (r *RMD) AffectedTargets(prev *RMD, smap *Smap) (joined, left []string, ok bool) {
+	switch {
+	case prev == nil || r.Version != prev.Version+1:
+		return nil, nil, false
+	case r.CluID != prev.CluID || r.Resilver != "" || len(r.TargetIDs) == 0:
+		return nil, nil, false
+	}
+	for _, tid := range r.TargetIDs {
+		if tsi := smap.GetTarget(tid); tsi != nil && !tsi.InMaintOrDecomm() {
+			joined = append(joined, tid)
+		} else {
+			left = append(left, tid)
+		}
+	}
+	return joined, left, true
+}
+
+//
+// per-target rebalance weights (apc.RebWeights) within Ext
+//
+
+// Weights returns nil when not set
+func (r *RMD) Weights() (apc.RebWeights, error) {
+	if len(r.Ext) == 0 {
+		return nil, nil
+	}
+	var ext struct {
+		Weights apc.RebWeights `json:"weights"`
+	}
+	if err := json.Unmarshal(r.Ext, &ext); err != nil {
+		return nil, err
+	}
+	return ext.Weights, nil
+}
+
+// SetWeights replaces the weights (empty - removes), while keeping all other
+// extensions verbatim
+func (r *RMD) SetWeights(weights apc.RebWeights) error {
+	ext := make(map[string]json.RawMessage, 1)
+	if len(r.Ext) > 0 {
+		if err := json.Unmarshal(r.Ext, &ext); err != nil {
+			return err
+		}
+	}
+	if len(weights) == 0 {
+		delete(ext, RMDExtWeights)
+	} else {
+		b, err := json.Marshal(weights)
+		if err != nil {
+			return err
+		}
+		ext[RMDExtWeights] = b
+	}
+	if len(ext) == 0 {
+		r.Ext = nil
+		return nil
+	}
+	b, err := json.Marshal(ext)
+	if err == nil {
+		r.Ext = b
+	}
+	return err
+}
+
+// the weights that HRW (object placement) currently applies - installed with each new RMD,
+// on all nodes (see hrw.go)
+var hrwWeights atomic.Pointer[apc.RebWeights]
+
+func InstallWeights(weights apc.RebWeights) {
+	if len(weights) == 0 {
+		hrwWeights.Store(nil)
+	} else {
+		hrwWeights.Store(&weights)
+	}
+}
+
+func loadWeights() apc.RebWeights {
+	if w := hrwWeights.Load(); w != nil {
+		return *w
+	}
+	return nil
+}
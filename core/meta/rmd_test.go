@@ -0,0 +1,179 @@
+// Package meta_test: unit tests for the package
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package meta_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/jsp"
+	"github.com/NVIDIA/aistore/core/meta"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RMD", func() {
+	// produced by a (hypothetical) newer node: unordered keys, integers that don't fit float64, nesting
+	const (
+		ext    = `{"zz":9007199254740993,"aa":{"k":[1,2.50,"x"]},"mm":null}`
+		rmdStr = `{"ext":` + ext + `,"cluster_id":"clu","target_ids":["t1","t2"],"version":7}`
+	)
+
+	It("should preserve unknown Ext verbatim when re-marshaling", func() {
+		rmd := &meta.RMD{}
+		Expect(cos.JSON.Unmarshal([]byte(rmdStr), rmd)).NotTo(HaveOccurred())
+		Expect(string(rmd.Ext)).To(Equal(ext))
+
+		b, err := cos.JSON.Marshal(rmd)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(rmdStr))
+	})
+
+	It("should preserve unknown Ext across save and load", func() {
+		var (
+			rmd   = &meta.RMD{}
+			fpath = filepath.Join(GinkgoT().TempDir(), "rmd")
+		)
+		Expect(cos.JSON.Unmarshal([]byte(rmdStr), rmd)).NotTo(HaveOccurred())
+		Expect(jsp.SaveMeta(fpath, rmd, nil)).NotTo(HaveOccurred())
+
+		loaded := &meta.RMD{}
+		_, err := jsp.LoadMeta(fpath, loaded)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(loaded.Ext)).To(Equal(ext))
+		Expect(loaded.Version).To(Equal(rmd.Version))
+		Expect(os.Remove(fpath)).NotTo(HaveOccurred())
+	})
+
+	It("should omit empty Ext", func() {
+		b, err := cos.JSON.Marshal(&meta.RMD{CluID: "clu", Version: 1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).NotTo(ContainSubstring(`"ext"`))
+	})
+
+	Describe("Weights", func() {
+		It("should set, replace, and reset weights while preserving other extensions", func() {
+			rmd := &meta.RMD{}
+			Expect(cos.JSON.Unmarshal([]byte(rmdStr), rmd)).NotTo(HaveOccurred())
+			w, err := rmd.Weights()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(w).To(BeNil())
+
+			Expect(rmd.SetWeights(apc.RebWeights{"t1": 50})).NotTo(HaveOccurred())
+			Expect(rmd.SetWeights(apc.RebWeights{"t2": 25, "t3": 100})).NotTo(HaveOccurred())
+			w, err = rmd.Weights()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(w).To(Equal(apc.RebWeights{"t2": 25, "t3": 100}))
+			Expect(string(rmd.Ext)).To(ContainSubstring(`"zz":9007199254740993`))
+
+			Expect(rmd.SetWeights(nil)).NotTo(HaveOccurred())
+			w, err = rmd.Weights()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(w).To(BeNil())
+			Expect(string(rmd.Ext)).To(ContainSubstring(`"aa":{"k":[1,2.50,"x"]}`))
+
+			empty := &meta.RMD{}
+			Expect(empty.SetWeights(apc.RebWeights{"t1": 50})).NotTo(HaveOccurred())
+			Expect(empty.SetWeights(nil)).NotTo(HaveOccurred())
+			Expect(empty.Ext).To(BeNil())
+		})
+
+		It("should validate", func() {
+			Expect(apc.RebWeights{"t1": 1, "t2": 100}.Validate()).NotTo(HaveOccurred())
+			Expect(apc.RebWeights{"t1": 0}.Validate()).To(HaveOccurred())
+			Expect(apc.RebWeights{"t1": 101}.Validate()).To(HaveOccurred())
+		})
+
+		It("should place data proportionally to weights", func() {
+			const num = 40000
+			smap := &meta.Smap{Tmap: make(meta.NodeMap, 4)}
+			for _, tid := range []string{"t1", "t2", "t3", "t4"} {
+				tsi := &meta.Snode{}
+				tsi.Init(tid, apc.Target)
+				smap.Tmap[tid] = tsi
+			}
+			place := func() map[string]int {
+				cnt := make(map[string]int, 4)
+				for i := range num {
+					tsi, err := smap.HrwName2T([]byte("obj-" + strconv.Itoa(i)))
+					Expect(err).NotTo(HaveOccurred())
+					cnt[tsi.ID()]++
+				}
+				return cnt
+			}
+			before := place()
+
+			// same (all default) weights - same placement
+			meta.InstallWeights(apc.RebWeights{"t1": 100})
+			defer meta.InstallWeights(nil)
+			Expect(place()).To(Equal(before))
+
+			// t1 gets about half of its (default) share
+			meta.InstallWeights(apc.RebWeights{"t1": 50})
+			after := place()
+			share := float64(after["t1"]) / num
+			Expect(share).To(BeNumerically("~", 50.0/350, 0.01))
+			for _, tid := range []string{"t2", "t3", "t4"} {
+				Expect(after[tid]).To(BeNumerically(">", before[tid]))
+			}
+		})
+	})
+
+	Describe("AffectedTargets", func() {
+		var (
+			smap = &meta.Smap{Tmap: meta.NodeMap{
+				"t1": &meta.Snode{DaeID: "t1"},
+				"t2": &meta.Snode{DaeID: "t2"},
+				"t3": &meta.Snode{DaeID: "t3"},
+				"t4": &meta.Snode{DaeID: "t4", Flags: meta.SnodeMaint},
+			}}
+			prev = &meta.RMD{CluID: "clu", Version: 5}
+		)
+
+		It("should scope single join", func() {
+			rmd := &meta.RMD{CluID: "clu", Version: 6, TargetIDs: []string{"t3"}}
+			joined, left, ok := rmd.AffectedTargets(prev, smap)
+			Expect(ok).To(BeTrue())
+			Expect(joined).To(Equal([]string{"t3"}))
+			Expect(left).To(BeEmpty())
+		})
+
+		It("should scope single leave", func() {
+			for _, tid := range []string{"t4" /*maintenance*/, "t5" /*removed*/} {
+				rmd := &meta.RMD{CluID: "clu", Version: 6, TargetIDs: []string{tid}}
+				joined, left, ok := rmd.AffectedTargets(prev, smap)
+				Expect(ok).To(BeTrue())
+				Expect(joined).To(BeEmpty())
+				Expect(left).To(Equal([]string{tid}))
+			}
+		})
+
+		It("should scope simultaneous changes", func() {
+			rmd := &meta.RMD{CluID: "clu", Version: 6, TargetIDs: []string{"t2", "t4", "t3", "t5"}}
+			joined, left, ok := rmd.AffectedTargets(prev, smap)
+			Expect(ok).To(BeTrue())
+			Expect(joined).To(ConsistOf("t2", "t3"))
+			Expect(left).To(ConsistOf("t4", "t5"))
+		})
+
+		It("should not scope otherwise", func() {
+			for _, rmd := range []*meta.RMD{
+				{CluID: "clu", Version: 6},                                           // targets unknown
+				{CluID: "clu", Version: 7, TargetIDs: []string{"t3"}},                // missed version
+				{CluID: "clu", Version: 5, TargetIDs: []string{"t3"}},                // not newer
+				{CluID: "other", Version: 6, TargetIDs: []string{"t3"}},              // different cluster
+				{CluID: "clu", Version: 6, TargetIDs: []string{"t3"}, Resilver: "x"}, // resilver
+			} {
+				_, _, ok := rmd.AffectedTargets(prev, smap)
+				Expect(ok).To(BeFalse(), "%+v", rmd)
+			}
+			_, _, ok := (&meta.RMD{CluID: "clu", Version: 1, TargetIDs: []string{"t3"}}).AffectedTargets(nil, smap)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
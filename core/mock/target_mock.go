@@ -57,6 +57,7 @@ func (*TargetMock) DeleteObject(*core.LOM, bool) (int, error)
 func (*TargetMock) Promote(*core.PromoteParams) (int, error)                       { return 0, nil }
 func (t *TargetMock) Backend(bck *meta.Bck) core.Backend                           { return t.Backends[bck.Provider] }
 func (*TargetMock) HeadObjT2T(*core.LOM, *meta.Snode) bool                         { return false }
+func (*TargetMock) HeadObjAttrsT2T(*core.LOM, *meta.Snode) *cmn.ObjAttrs           { return nil }
 func (*TargetMock) BMDVersionFixup(*http.Request, ...cmn.Bck)                      {}
 
 func (*TargetMock) SoftFSHC()                         {}
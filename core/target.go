@@ -33,6 +33,8 @@ type (
 		WorkTag string // (=> work fqn)
 		Size    int64
 		OWT     cmn.OWT
+		SSE     *apc.SSEMsg // (optional) server-side encryption by remote backend (see SSEBackend)
+
 		SkipEC  bool // don't erasure-code when finalizing
 		ColdGET bool // this PUT is in fact a cold-GET
 	}
@@ -106,6 +108,7 @@ type (
 
 		Promote(params *PromoteParams) (ecode int, err error)
 		HeadObjT2T(lom *LOM, si *meta.Snode) bool
+		HeadObjAttrsT2T(lom *LOM, si *meta.Snode) *cmn.ObjAttrs // nil when not present
 
 		ECRestoreReq(ct *CT, si *meta.Snode, uuid string) error
 
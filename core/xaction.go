@@ -45,6 +45,9 @@ type (
 		AbortErr() error
 		AbortedAfter(time.Duration) error
 		ChanAbort() <-chan error
+		// pause
+		IsPaused() bool
+		WaitPaused()
 		// err (info)
 		AddErr(error, ...int)
 
@@ -58,6 +61,8 @@ type (
 		// modifiers
 		Finish()
 		Abort(error) bool
+		Pause() bool
+		Resume() bool
 		AddNotif(n Notif)
 
 		// common stats
@@ -79,6 +84,17 @@ type (
 		InObjs   int64 `json:"in-objs,string"`   // receive
 		InBytes  int64 `json:"in-bytes,string"`
 	}
+	// structured progress - reported by xactions that know (or discover) their totals
+	// (e.g., copy-bucket, etl-bucket); processed = locally processed + transmitted
+	Progress struct {
+		TotalObjs  int64         `json:"total.n,string"`    // discovered so far
+		TotalBytes int64         `json:"total.size,string"` // ditto
+		Objs       int64         `json:"n,string"`          // processed
+		Bytes      int64         `json:"size,string"`       // ditto
+		Errs       int64         `json:"err.n,string"`
+		ETA        time.Duration `json:"eta"`   // zero - unknown
+		Final      bool          `json:"final"` // done discovering (the totals won't grow)
+	}
 	Snap struct {
 		// xaction-specific stats counters
 		Ext any `json:"ext"`
@@ -104,6 +120,10 @@ type (
 		Stats    Stats `json:"stats"`
 		AbortedX bool  `json:"aborted"`
 		IdleX    bool  `json:"is_idle"`
+		PausedX  bool  `json:"paused,omitempty"`
+
+		// optional (see Progress above)
+		Progress *Progress `json:"progress,omitempty"`
 	}
 	AllRunningInOut struct {
 		Kind    string
@@ -118,6 +138,46 @@ type (
 
 func (snp *Snap) IsAborted() bool { return snp.AbortedX }
 func (snp *Snap) IsIdle() bool    { return snp.IdleX }
+func (snp *Snap) IsPaused() bool  { return snp.PausedX }
 func (snp *Snap) Started() bool   { return !snp.StartTime.IsZero() }
 func (snp *Snap) Running() bool   { return snp.Started() && !snp.IsAborted() && snp.EndTime.IsZero() }
 func (snp *Snap) Finished() bool  { return snp.Started() && !snp.EndTime.IsZero() }
+
+//////////////
+// Progress //
+//////////////
+
+// percentage of the total - by size when known, otherwise by count
+func (p *Progress) Percent() float64 {
+	var done, total = p.Bytes, p.TotalBytes
+	if total <= 0 {
+		done, total = p.Objs, p.TotalObjs
+	}
+	if total <= 0 {
+		return 0
+	}
+	return min(float64(done)*100/float64(total), 100)
+}
+
+// remaining time at the average rate since the start; zero when unknown
+func (p *Progress) Estimate(elapsed time.Duration) time.Duration {
+	var done, total = p.Bytes, p.TotalBytes
+	if total <= 0 || done <= 0 {
+		done, total = p.Objs, p.TotalObjs
+	}
+	if !p.Final || done <= 0 || done >= total || elapsed <= 0 {
+		return 0
+	}
+	return time.Duration(float64(elapsed) * float64(total-done) / float64(done))
+}
+
+// merge (another target's) progress
+func (p *Progress) Add(o *Progress) {
+	p.TotalObjs += o.TotalObjs
+	p.TotalBytes += o.TotalBytes
+	p.Objs += o.Objs
+	p.Bytes += o.Bytes
+	p.Errs += o.Errs
+	p.ETA = max(p.ETA, o.ETA)
+	p.Final = p.Final && o.Final
+}
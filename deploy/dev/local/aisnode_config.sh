@@ -56,7 +56,9 @@ cat > $AIS_CONF_FILE <<EOL
 		"cleanupwm":         65,
 		"lowwm":             ${AIS_SPACE_LOWWM:-75},
 		"highwm":            ${AIS_SPACE_HIGHWM:-90},
-		"out_of_space":      ${AIS_SPACE_OOS:-95}
+		"out_of_space":      ${AIS_SPACE_OOS:-95},
+		"expire_time":       "1h",
+		"quota_time":        "1h"
 	},
 	"lru": {
 		"dont_evict_time":   "120m",
@@ -138,6 +140,13 @@ cat > $AIS_CONF_FILE <<EOL
 		"secret":      "$AIS_AUTHN_SECRET_KEY",
 		"enabled":     ${AIS_AUTHN_ENABLED:-false}
 	},
+	"audit": {
+		"format":      "json",
+		"syslog":      "",
+		"max_size":    "64MiB",
+		"max_files":   8,
+		"enabled":     false
+	},
 	"keepalivetracker": {
 		"proxy": {
 			"interval": "10s",
@@ -38,7 +38,7 @@ run_cmd() {
 # 3. when adding/deleting backends, update the 3 (three) functions that follow below:
 
 set_env_backends() {
-  known_backends=( aws gcp azure oci ht )
+  known_backends=( aws gcp azure oci b2 webdav ftp posix ht )
   if [[ ! -z $TAGS ]]; then
     ## environment var TAGS may contain any/all build tags, including backends
     for b in "${known_backends[@]}"; do
@@ -64,6 +64,10 @@ set_env_backends() {
         azure) ;;
         gcp)   ;;
         oci)   ;;
+        b2)    ;;
+        webdav) ;;
+        ftp)   ;;
+        posix) ;;
         ht)    ;;
         *)     echo "fatal: unknown backend '$b' in 'AIS_BACKEND_PROVIDERS=${AIS_BACKEND_PROVIDERS}'"; exit 1;;
       esac
@@ -127,6 +131,10 @@ make_backend_conf() {
       azure) backend_conf+=('"azure": {}') ;;
       gcp)   backend_conf+=('"gcp":   {}') ;;
       oci)   backend_conf+=('"oci":   {}') ;;
+      b2)    backend_conf+=('"b2":    {}') ;;
+      webdav) backend_conf+=('"webdav": {}') ;;
+      ftp)   backend_conf+=('"ftp":   {}') ;;
+      posix) backend_conf+=('"posix": {}') ;;
       ht)    backend_conf+=('"ht":    {}') ;;
     esac
   done
@@ -23,6 +23,7 @@ import (
 	"github.com/NVIDIA/aistore/core/meta"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/fs/mpather"
+	"github.com/NVIDIA/aistore/fs/sse"
 	"github.com/NVIDIA/aistore/xact"
 	"github.com/NVIDIA/aistore/xact/xreg"
 )
@@ -145,6 +146,7 @@ func (r *XactBckEncode) Run(gowg *sync.WaitGroup) {
 		CTs:      []string{fs.ObjectType},
 		VisitObj: r.encode,
 		DoLoad:   mpather.LoadUnsafe,
+		Gov:      mpather.RegGov(apc.ActECEncode),
 	}
 	opts.Bck.Copy(r.bck.Bucket())
 
@@ -221,6 +223,10 @@ func (r *XactBckEncode) encode(lom *core.LOM, _ []byte) error {
 	if !local {
 		return nil
 	}
+	// Encrypted with client-supplied key - ditto (see EncodeObject)
+	if sse.IsCustomer(lom.SSERef()) {
+		return nil
+	}
 	mdFQN, _, err := core.HrwFQN(lom.Bck().Bucket(), fs.ECMetaType, lom.ObjName)
 	if err != nil {
 		nlog.Warningln("failed to generate md FQN for", lom.Cname(), "err:", err)
@@ -19,6 +19,7 @@ import (
 	"github.com/NVIDIA/aistore/core"
 	"github.com/NVIDIA/aistore/core/meta"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/sse"
 	"github.com/NVIDIA/aistore/nl"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/transport/bundle"
@@ -101,7 +102,8 @@ func (mgr *Manager) OpenStreams(withRefc bool) {
 		client      = transport.NewIntraDataClient()
 		config      = cmn.GCO.Get()
 		compression = config.EC.Compression
-		extraReq    = transport.Extra{Callback: cbReq, Compression: compression, Config: config}
+		zstdLevel   = config.EC.ZstdLevel
+		extraReq    = transport.Extra{Callback: cbReq, Compression: compression, ZstdLevel: zstdLevel, Config: config}
 	)
 	reqSbArgs := bundle.Args{
 		Multiplier: config.EC.SbundleMult,
@@ -113,7 +115,7 @@ func (mgr *Manager) OpenStreams(withRefc bool) {
 		Multiplier: config.EC.SbundleMult,
 		Trname:     RespStreamName,
 		Net:        mgr.netResp,
-		Extra:      &transport.Extra{Compression: compression, Config: config},
+		Extra:      &transport.Extra{Compression: compression, ZstdLevel: zstdLevel, Config: config, Priority: transport.PrioHigh},
 	}
 
 	mgr.reqBundle.Store(bundle.New(client, reqSbArgs))
@@ -266,6 +268,11 @@ func (mgr *Manager) EncodeObject(lom *core.LOM, cb onFin) error {
 	if spec != nil && !spec.PermToProcess() {
 		return errSkipped
 	}
+	// encrypted with client-supplied key: on-disk ciphertext can be neither
+	// sliced nor replicated (and later decrypted) based on the plaintext size
+	if sse.IsCustomer(lom.SSERef()) {
+		return errSkipped
+	}
 
 	req := allocateReq(ActSplit, lom.LIF())
 	req.IsCopy = IsECCopy(lom.Lsize(), &lom.Bprops().EC)
@@ -25,6 +25,7 @@ const PrefixXactID = "etl-"
 const (
 	Spec = "spec"
 	Code = "code"
+	Wasm = "wasm"
 )
 
 // consistent with rfc2396.txt "Uniform Resource Identifiers (URI): Generic Syntax"
@@ -46,6 +47,12 @@ const (
 	Hrev = "hrev://"
 	// Stdin/stdout communication.
 	HpushStdin = "io://"
+	// ETL container serves gRPC: bidirectional stream per object over a single long-lived
+	// connection (see grpcComm).
+	Grpc = "grpc://"
+	// In-process WebAssembly (WASI) module: object content on stdin, transformed content on stdout
+	// (no ETL container - see InitWasmMsg).
+	WasmStdio = "wasm://"
 )
 
 // enum arg types (`argTypes`)
@@ -55,6 +62,17 @@ const (
 	ArgTypeFQN     = "fqn"
 )
 
+// enum range-read capabilities (`rangeReads`): GET with "Range" header (see TransformRange)
+const (
+	// range reads are not supported (rejected)
+	RangeReadNone = ""
+	// range-preserving transformation (each output byte depends only on the input byte at the same offset,
+	// e.g. byte-wise XOR): the requested range of the object is transformed (and nothing else)
+	RangeReadPass = "passthrough"
+	// the entire object is transformed, the result cached in memory, and the range served from the cache
+	RangeReadCache = "cache"
+)
+
 type (
 	InitMsg interface {
 		Name() string
@@ -71,6 +89,7 @@ type (
 		CommTypeX string       `json:"communication"` // enum commTypes
 		ArgTypeX  string       `json:"argument"`      // enum argTypes
 		Timeout   cos.Duration `json:"timeout"`
+		RangeRead string       `json:"range_read,omitempty"` // enum rangeReads
 	}
 	InitSpecMsg struct {
 		InitMsgBase
@@ -96,6 +115,15 @@ type (
 		// bitwise flags: (streaming | debug | strict | ...) future enhancements
 		Flags int64 `json:"flags"`
 	}
+
+	// InitWasmMsg carries a WebAssembly module that each target compiles once and then executes
+	// in-process, one instance per transformed object (see ext/etl/wasm.go for the contract)
+	InitWasmMsg struct {
+		InitMsgBase
+		Wasm []byte `json:"wasm"` // WASI (preview1) command module, binary format
+		// max linear memory of a single module instance (0 (zero) - DefaultWasmMaxMem)
+		MaxMem cos.SizeIEC `json:"max_mem,omitempty"`
+	}
 )
 
 type (
@@ -129,8 +157,9 @@ type (
 )
 
 var (
-	commTypes = []string{Hpush, Hpull, Hrev, HpushStdin}         // NOTE: must contain all
-	argTypes  = []string{ArgTypeDefault, ArgTypeURL, ArgTypeFQN} // ditto
+	commTypes  = []string{Hpush, Hpull, Hrev, HpushStdin, Grpc}   // NOTE: must contain all (container-based)
+	argTypes   = []string{ArgTypeDefault, ArgTypeURL, ArgTypeFQN} // ditto
+	rangeReads = []string{RangeReadNone, RangeReadPass, RangeReadCache}
 )
 
 ////////////////
@@ -141,6 +170,7 @@ var (
 var (
 	_ InitMsg = (*InitCodeMsg)(nil)
 	_ InitMsg = (*InitSpecMsg)(nil)
+	_ InitMsg = (*InitWasmMsg)(nil)
 )
 
 func (m InitMsgBase) CommType() string { return m.CommTypeX }
@@ -148,6 +178,7 @@ func (m InitMsgBase) ArgType() string  { return m.ArgTypeX }
 func (m InitMsgBase) Name() string     { return m.IDX }
 func (*InitCodeMsg) MsgType() string   { return Code }
 func (*InitSpecMsg) MsgType() string   { return Spec }
+func (*InitWasmMsg) MsgType() string   { return Wasm }
 
 func (m *InitCodeMsg) String() string {
 	return fmt.Sprintf("init-%s[%s-%s-%s-%s]", Code, m.IDX, m.CommTypeX, m.ArgTypeX, m.Runtime)
@@ -157,6 +188,10 @@ func (m *InitSpecMsg) String() string {
 	return fmt.Sprintf("init-%s[%s-%s-%s]", Spec, m.IDX, m.CommTypeX, m.ArgTypeX)
 }
 
+func (m *InitWasmMsg) String() string {
+	return fmt.Sprintf("init-%s[%s-%s]", Wasm, m.IDX, m.CommTypeX)
+}
+
 // TODO: double-take, unmarshaling-wise. To avoid, include (`Spec`, `Code`) in API calls
 func UnmarshalInitMsg(b []byte) (msg InitMsg, err error) {
 	var msgInf map[string]json.RawMessage
@@ -173,6 +208,11 @@ func UnmarshalInitMsg(b []byte) (msg InitMsg, err error) {
 		err = jsoniter.Unmarshal(b, msg)
 		return
 	}
+	if _, ok := msgInf[Wasm]; ok {
+		msg = &InitWasmMsg{}
+		err = jsoniter.Unmarshal(b, msg)
+		return
+	}
 	err = fmt.Errorf("invalid etl.InitMsg: %+v", msgInf)
 	return
 }
@@ -217,6 +257,10 @@ func (m *InitMsgBase) validate(detail string) error {
 		return cmn.NewErrETLf(errCtx, ferr, err, detail)
 	}
 
+	if err := m.validateRangeRead(); err != nil {
+		return cmn.NewErrETLf(errCtx, ferr, err, detail)
+	}
+
 	// NOTE: default comm-type
 	if m.CommType() == "" {
 		cos.Infoln("Warning: empty comm-type, defaulting to", Hpush)
@@ -229,11 +273,30 @@ func (m *InitMsgBase) validate(detail string) error {
 	return nil
 }
 
+// passthrough requires the transformer to receive the content from the target (compare w/ chainable)
+func (m *InitMsgBase) validateRangeRead() error {
+	if !cos.StringInSlice(m.RangeRead, rangeReads) {
+		return fmt.Errorf("unknown range-read %q (expecting one of: %q, %q)", m.RangeRead, RangeReadPass, RangeReadCache)
+	}
+	if m.RangeRead != RangeReadPass {
+		return nil
+	}
+	if m.CommTypeX == Hpull || m.CommTypeX == Hrev || m.ArgTypeX == ArgTypeFQN {
+		return fmt.Errorf("range-read %q is not supported with comm-type %q and arg-type %q",
+			m.RangeRead, m.CommTypeX, m.ArgTypeX)
+	}
+	return nil
+}
+
 func (m *InitCodeMsg) Validate() error {
 	if err := m.InitMsgBase.validate(m.String()); err != nil {
 		return err
 	}
 
+	if m.CommTypeX == Grpc {
+		return fmt.Errorf("comm-type %q requires custom container (init-spec) - not supported by runtimes (%q)",
+			m.CommTypeX, m.Runtime)
+	}
 	if len(m.Code) == 0 {
 		return fmt.Errorf("source code is empty (%q)", m.Runtime)
 	}
@@ -299,6 +362,37 @@ func (m *InitSpecMsg) Validate() (err error) {
 	return nil
 }
 
+func (m *InitWasmMsg) Validate() error {
+	const ferr = "%v [%s]"
+	if err := k8s.ValidateEtlName(m.IDX); err != nil {
+		return fmt.Errorf(ferr, err, m.String())
+	}
+	errCtx := &cmn.ETLErrCtx{ETLName: m.Name()}
+	if m.CommTypeX == "" {
+		m.CommTypeX = WasmStdio
+	}
+	if m.CommTypeX != WasmStdio {
+		return cmn.NewErrETLf(errCtx, "comm-type %q is not supported by %s modules (expecting %q)", m.CommTypeX, Wasm, WasmStdio)
+	}
+	if m.ArgTypeX != ArgTypeDefault {
+		return cmn.NewErrETLf(errCtx, "arg-type %q is not supported by %s modules", m.ArgTypeX, Wasm)
+	}
+	if err := m.validateRangeRead(); err != nil {
+		return cmn.NewErrETL(errCtx, err.Error())
+	}
+	if len(m.Wasm) < len(wasmMagic) || string(m.Wasm[:len(wasmMagic)]) != wasmMagic {
+		return cmn.NewErrETL(errCtx, "invalid or empty WebAssembly module (expecting binary format)")
+	}
+	if m.MaxMem < 0 || m.MaxMem > wasmMaxMem {
+		return cmn.NewErrETLf(errCtx, "max-mem %s is invalid, expecting 0 <= max-mem <= %s",
+			cos.ToSizeIEC(int64(m.MaxMem), 0), cos.ToSizeIEC(wasmMaxMem, 0))
+	}
+	if m.Timeout == 0 {
+		m.Timeout = cos.Duration(DefaultTimeout)
+	}
+	return nil
+}
+
 func ParsePodSpec(errCtx *cmn.ETLErrCtx, spec []byte) (*corev1.Pod, error) {
 	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(spec, nil, nil)
 	if err != nil {
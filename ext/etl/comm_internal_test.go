@@ -7,10 +7,13 @@ package etl
 import (
 	cryptorand "crypto/rand"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -21,8 +24,12 @@ import (
 	"github.com/NVIDIA/aistore/core/meta"
 	"github.com/NVIDIA/aistore/core/mock"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/memsys"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -139,8 +146,297 @@ var _ = Describe("CommunicatorTest", func() {
 			Expect(b).To(Equal(transformData))
 		})
 	}
+
+	It("should stream through chained transformers", func() {
+		// each stage prepends its tag to the content it receives
+		stage := func(tag string) *httptest.Server {
+			return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, err := cos.ReadAll(r.Body)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = w.Write(append([]byte(tag), b...))
+				Expect(err).NotTo(HaveOccurred())
+			}))
+		}
+		newComm := func(name, commType, uri string) Communicator {
+			pod := &corev1.Pod{}
+			pod.SetName(name)
+			boot := &etlBootstrapper{
+				msg:  InitSpecMsg{InitMsgBase: InitMsgBase{CommTypeX: commType}},
+				pod:  pod,
+				uri:  uri,
+				xctn: mock.NewXact(apc.ActETLInline),
+			}
+			return newCommunicator(nil, boot)
+		}
+		first, second := stage("1:"), stage("2:")
+		defer first.Close()
+		defer second.Close()
+
+		Expect(newComm("pull", Hpull, first.URL).chainable()).To(HaveOccurred())
+
+		dp := &OfflineDP{
+			comm:   newComm("first", Hpush, first.URL),
+			chain:  []Communicator{newComm("second", Hpush, second.URL)},
+			tcbmsg: &apc.TCBMsg{Transform: apc.Transform{Name: "first", Chain: []string{"second"}}},
+		}
+		lom := &core.LOM{ObjName: objName}
+		Expect(lom.InitBck(clusterBck.Bucket())).NotTo(HaveOccurred())
+		Expect(lom.Load(false, false)).NotTo(HaveOccurred())
+		orig, err := os.ReadFile(lom.FQN)
+		Expect(err).NotTo(HaveOccurred())
+
+		r, _, err := dp.Reader(lom, false, false)
+		Expect(err).NotTo(HaveOccurred())
+		b, err := cos.ReadAll(r)
+		r.Close()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b).To(Equal(append([]byte("2:1:"), orig...)))
+	})
+
+	It("should transform in-process (wasm)", func() {
+		_, err := newWasmComm(nil, &InitWasmMsg{Wasm: wasmModule(false)})
+		Expect(err).To(HaveOccurred()) // (not a WASI command)
+
+		newComm := func(name string) Communicator {
+			msg := &InitWasmMsg{InitMsgBase: InitMsgBase{IDX: name}, Wasm: wasmModule(true)}
+			Expect(msg.Validate()).NotTo(HaveOccurred())
+			wc, err := newWasmComm(nil, msg)
+			Expect(err).NotTo(HaveOccurred())
+			wc.xctn = mock.NewXact(apc.ActETLInline)
+			return wc
+		}
+		lom := &core.LOM{ObjName: objName}
+		Expect(lom.InitBck(clusterBck.Bucket())).NotTo(HaveOccurred())
+		orig, err := os.ReadFile(lom.FQN)
+		Expect(err).NotTo(HaveOccurred())
+
+		// inline
+		comm = newComm("wasm-echo")
+		resp, err := http.Get(proxyServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+		b, err := cos.ReadAll(resp.Body)
+		resp.Body.Close()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b).To(Equal(orig))
+
+		// offline, chained
+		dp := &OfflineDP{
+			comm:   comm,
+			chain:  []Communicator{newComm("wasm-echo-2")},
+			tcbmsg: &apc.TCBMsg{Transform: apc.Transform{Name: "wasm-echo", Chain: []string{"wasm-echo-2"}}},
+		}
+		r, _, err := dp.Reader(lom, false, false)
+		Expect(err).NotTo(HaveOccurred())
+		b, err = cos.ReadAll(r)
+		r.Close()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b).To(Equal(orig))
+	})
+
+	It("should transform over gRPC", func() {
+		// echo the content back (as it arrives), prefixed with the object name
+		echo := func(_ any, stream grpc.ServerStream) error {
+			method, _ := grpc.MethodFromServerStream(stream)
+			Expect(method).To(Equal(grpcMethod))
+			var msg []byte
+			if err := stream.RecvMsg(&msg); err != nil {
+				return err
+			}
+			if err := stream.SendMsg(append(msg, ':')); err != nil {
+				return err
+			}
+			for {
+				if err := stream.RecvMsg(&msg); err != nil {
+					if err == io.EOF {
+						return nil
+					}
+					return err
+				}
+				if err := stream.SendMsg(msg); err != nil {
+					return err
+				}
+			}
+		}
+		// never responds
+		stuck := func(_ any, stream grpc.ServerStream) error {
+			<-stream.Context().Done()
+			return stream.Context().Err()
+		}
+		serve := func(handler grpc.StreamHandler) (string, *grpc.Server) {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			srv := grpc.NewServer(grpc.UnknownServiceHandler(handler), grpc.ForceServerCodec(grpcCodec{}))
+			go srv.Serve(lis)
+			return "http://" + lis.Addr().String(), srv
+		}
+		newComm := func(name, uri string) Communicator {
+			pod := &corev1.Pod{}
+			pod.SetName(name)
+			boot := &etlBootstrapper{
+				msg:  InitSpecMsg{InitMsgBase: InitMsgBase{CommTypeX: Grpc}},
+				pod:  pod,
+				uri:  uri,
+				xctn: mock.NewXact(apc.ActETLInline),
+			}
+			return newCommunicator(nil, boot)
+		}
+		uri, srv := serve(echo)
+		defer srv.Stop()
+		uriStuck, srvStuck := serve(stuck)
+		defer srvStuck.Stop()
+
+		lom := &core.LOM{ObjName: objName}
+		Expect(lom.InitBck(clusterBck.Bucket())).NotTo(HaveOccurred())
+		orig, err := os.ReadFile(lom.FQN)
+		Expect(err).NotTo(HaveOccurred())
+		prefix := []byte(clusterBck.Name + "/" + objName + ":")
+
+		// inline
+		comm = newComm("grpc-echo", uri)
+		defer comm.(*grpcComm).conn.Close()
+		resp, err := http.Get(proxyServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+		b, err := cos.ReadAll(resp.Body)
+		resp.Body.Close()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b).To(Equal(append(prefix, orig...)))
+
+		// offline, chained (same connection, many streams)
+		dp := &OfflineDP{
+			comm:   comm,
+			chain:  []Communicator{comm},
+			tcbmsg: &apc.TCBMsg{Transform: apc.Transform{Name: "grpc-echo", Chain: []string{"grpc-echo"}}},
+		}
+		for range 3 {
+			r, _, err := dp.Reader(lom, false, false)
+			Expect(err).NotTo(HaveOccurred())
+			b, err = cos.ReadAll(r)
+			r.Close()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(b).To(Equal(append(append(prefix, prefix...), orig...)))
+		}
+
+		// deadline
+		stuckComm := newComm("grpc-stuck", uriStuck)
+		defer stuckComm.(*grpcComm).conn.Close()
+		r, err := stuckComm.OfflineTransform(lom, 100*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cos.ReadAll(r)
+		r.Close()
+		Expect(status.Code(err)).To(Equal(codes.DeadlineExceeded))
+	})
+
+	It("should serve range reads", func() {
+		var calls atomic.Int32
+		// range-preserving (XOR) and not (prefix)
+		xor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, err := cos.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			for i := range b {
+				b[i] ^= 0xff
+			}
+			w.Write(b)
+		}))
+		defer xor.Close()
+		prefix := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			b, err := cos.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			w.Write(append([]byte("T:"), b...))
+		}))
+		defer prefix.Close()
+		newComm := func(name, uri, rangeRead string) Communicator {
+			pod := &corev1.Pod{}
+			pod.SetName(name)
+			msg := InitSpecMsg{InitMsgBase: InitMsgBase{CommTypeX: Hpush, RangeRead: rangeRead}}
+			Expect(msg.validateRangeRead()).NotTo(HaveOccurred())
+			boot := &etlBootstrapper{msg: msg, pod: pod, uri: uri, xctn: mock.NewXact(apc.ActETLInline)}
+			return newCommunicator(nil, boot)
+		}
+		read := func(c Communicator, off, length int64) ([]byte, error) {
+			lom := &core.LOM{ObjName: objName}
+			Expect(lom.InitBck(clusterBck.Bucket())).NotTo(HaveOccurred())
+			r, err := TransformRange(c, lom, func(int64) (int64, int64, error) { return off, length, nil })
+			if err != nil {
+				return nil, err
+			}
+			defer r.Close()
+			Expect(r.Size()).To(BeEquivalentTo(length))
+			return cos.ReadAll(r)
+		}
+		lom := &core.LOM{ObjName: objName}
+		Expect(lom.InitBck(clusterBck.Bucket())).NotTo(HaveOccurred())
+		orig, err := os.ReadFile(lom.FQN)
+		Expect(err).NotTo(HaveOccurred())
+
+		// not capable
+		_, err = read(newComm("none", xor.URL, RangeReadNone), 0, 10)
+		Expect(err).To(HaveOccurred())
+		Expect((&InitMsgBase{CommTypeX: Hpull, RangeRead: RangeReadPass}).validateRangeRead()).To(HaveOccurred())
+
+		// passthrough: transforms the range only
+		b, err := read(newComm("pass", xor.URL, RangeReadPass), 1000, 4096)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b).To(HaveLen(4096))
+		for i := range b {
+			Expect(b[i] ^ 0xff).To(Equal(orig[1000+i]))
+		}
+
+		// cache: transforms once (regardless of the test environment's memory)
+		pressure := rcache.pressure
+		rcache.pressure = func() int { return memsys.PressureLow }
+		defer func() { rcache.pressure = pressure }()
+		full := append([]byte("T:"), orig...)
+		cached := newComm("cache", prefix.URL, RangeReadCache)
+		for _, off := range []int64{0, 12345, int64(len(full)) - 100} {
+			b, err := read(cached, off, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(b).To(Equal(full[off : off+100]))
+		}
+		Expect(calls.Load()).To(BeEquivalentTo(1))
+
+		// purged upon stop
+		rcache.purge(cached.Xact().ID())
+		_, err = read(cached, 0, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls.Load()).To(BeEquivalentTo(2))
+	})
 })
 
+// WASI command that copies stdin to stdout (when start is true)
+func wasmModule(start bool) []byte {
+	section := func(id byte, b ...byte) []byte { return append([]byte{id, byte(len(b))}, b...) }
+	name := func(s string) []byte { return append([]byte{byte(len(s))}, s...) }
+	imp := func(fn string, idx byte) []byte {
+		return append(append(name("wasi_snapshot_preview1"), name(fn)...), 0x00, idx)
+	}
+	body := []byte{
+		0x00,       // no locals
+		0x03, 0x40, // loop
+		0x41, 0x00, 0x41, 0x10, 0x36, 0x02, 0x00, // iov.buf = 16
+		0x41, 0x00, 0x41, 0x80, 0x20, 0x36, 0x02, 0x04, // iov.len = 4096
+		0x41, 0x00, 0x41, 0x00, 0x41, 0x01, 0x41, 0x08, 0x10, 0x00, // fd_read(stdin, iov, 1, &nread)
+		0x04, 0x40, 0x0f, 0x0b, // if errno: return
+		0x41, 0x00, 0x28, 0x02, 0x08, 0x45, 0x04, 0x40, 0x0f, 0x0b, // if nread == 0: return
+		0x41, 0x00, 0x41, 0x00, 0x28, 0x02, 0x08, 0x36, 0x02, 0x04, // iov.len = nread
+		0x41, 0x01, 0x41, 0x00, 0x41, 0x01, 0x41, 0x0c, 0x10, 0x01, 0x1a, // fd_write(stdout, iov, 1, &nwritten)
+		0x0c, 0x00, // br loop
+		0x0b, 0x0b,
+	}
+	export := "_start"
+	if !start {
+		export = "run"
+	}
+	m := []byte("\x00asm\x01\x00\x00\x00")
+	m = append(m, section(1, 0x02, 0x60, 0x04, 0x7f, 0x7f, 0x7f, 0x7f, 0x01, 0x7f, 0x60, 0x00, 0x00)...)
+	m = append(m, section(2, append(append([]byte{0x02}, imp("fd_read", 0)...), imp("fd_write", 0)...)...)...)
+	m = append(m, section(3, 0x01, 0x01)...)
+	m = append(m, section(5, 0x01, 0x00, 0x01)...)
+	m = append(m, section(7, append(append([]byte{0x02}, append(name("memory"), 0x02, 0x00)...), append(name(export), 0x00, 0x02)...)...)...)
+	m = append(m, section(10, append([]byte{0x01, byte(len(body))}, body...)...)...)
+	return m
+}
+
 // Creates a file with random content.
 func createRandomFile(fileName string, size int64) error {
 	b := make([]byte, size)
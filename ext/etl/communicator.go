@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 
@@ -56,6 +57,17 @@ type (
 		// See also, and separately: on-the-fly transformation as part of a user (e.g. training model) GET request handling
 		OfflineTransform(lom *core.LOM, timeout time.Duration) (cos.ReadCloseSizer, error)
 
+		// StreamTransform transforms the (intermediate) content of a given object - one of the subsequent
+		// stages of a pipeline (see apc.Transform.Chain);
+		// supported only by (Hpush | HpushStdin) that receive the content in the request body
+		StreamTransform(r cos.ReadCloseSizer, lom *core.LOM, timeout time.Duration) (cos.ReadCloseSizer, error)
+		chainable() error
+
+		// writes everything that determines transformation results (see OfflineDP.TransformID)
+		digest(w io.Writer)
+		// enum rangeReads (see TransformRange)
+		rangeRead() string
+
 		Stop()
 
 		CommStats
@@ -106,6 +118,8 @@ func newCommunicator(listener meta.Slistener, boot *etlBootstrapper) Communicato
 			pc.command = boot.originalCommand
 		}
 		return pc
+	case Grpc:
+		return newGrpcComm(baseComm{listener: listener, boot: boot})
 	case Hpull:
 		rc := &redirectComm{}
 		rc.listener, rc.boot = listener, boot
@@ -153,6 +167,38 @@ func (c *baseComm) OutBytes() int64 { return c.boot.xctn.OutBytes() }
 
 func (c *baseComm) Stop() { c.boot.xctn.Finish() }
 
+func (c *baseComm) rangeRead() string { return c.boot.msg.RangeRead }
+
+// (Hpull, Hrev: ETL container fetches the object by itself - cannot be given intermediate content)
+func (c *baseComm) StreamTransform(r cos.ReadCloseSizer, _ *core.LOM, _ time.Duration) (cos.ReadCloseSizer, error) {
+	cos.Close(r)
+	return nil, c.chainable()
+}
+
+// name, types, and pod spec - the latter includes user code and dependencies (env) in case of InitCodeMsg
+func (c *baseComm) digest(w io.Writer) {
+	msg := &c.boot.msg
+	for _, s := range []string{msg.IDX, msg.CommTypeX, msg.ArgTypeX} {
+		io.WriteString(w, s)
+		w.Write([]byte{0})
+	}
+	w.Write(msg.Spec)
+	keys := make([]string, 0, len(c.boot.env))
+	for k := range c.boot.env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		io.WriteString(w, k+"="+c.boot.env[k])
+		w.Write([]byte{0})
+	}
+}
+
+func (c *baseComm) chainable() error {
+	return fmt.Errorf("%s: communication type %q with argument type %q cannot be a subsequent stage of a transformer chain",
+		c, c.boot.msg.CommTypeX, c.boot.msg.ArgTypeX)
+}
+
 func (c *baseComm) getWithTimeout(url string, timeout time.Duration) (r cos.ReadCloseSizer, err error) {
 	if err := c.boot.xctn.AbortErr(); err != nil {
 		return nil, err
@@ -219,11 +265,8 @@ func (pc *pushComm) doRequest(lom *core.LOM, timeout time.Duration) (r cos.ReadC
 
 func (pc *pushComm) do(lom *core.LOM, timeout time.Duration) (_ cos.ReadCloseSizer, ecode int, err error) {
 	var (
-		body   io.ReadCloser
-		cancel func()
-		req    *http.Request
-		resp   *http.Response
-		u      string
+		body io.ReadCloser
+		u    string
 	)
 	if err := pc.boot.xctn.AbortErr(); err != nil {
 		return nil, 0, err
@@ -241,7 +284,7 @@ func (pc *pushComm) do(lom *core.LOM, timeout time.Duration) (_ cos.ReadCloseSiz
 		debug.Assert(lom.Bck().Ns.IsGlobal(), lom.Bck().Cname(""), " - bucket with namespace")
 		u = pc.boot.uri + "/" + lom.Bck().Name + "/" + lom.ObjName
 
-		fh, err := cos.NewFileHandle(lom.FQN)
+		fh, err := lom.NewHandle(lom.FQN)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -252,7 +295,15 @@ func (pc *pushComm) do(lom *core.LOM, timeout time.Duration) (_ cos.ReadCloseSiz
 	default:
 		debug.Assert(false, "unexpected msg type:", pc.boot.msg.ArgTypeX) // is validated at construction time
 	}
+	return pc.put(u, body, size, timeout)
+}
 
+func (pc *pushComm) put(u string, body io.ReadCloser, size int64, timeout time.Duration) (_ cos.ReadCloseSizer, ecode int, err error) {
+	var (
+		cancel func()
+		req    *http.Request
+		resp   *http.Response
+	)
 	if timeout != 0 {
 		var ctx context.Context
 		ctx, cancel = context.WithTimeout(context.Background(), timeout)
@@ -322,6 +373,30 @@ func (pc *pushComm) InlineTransform(w http.ResponseWriter, _ *http.Request, lom
 	return err
 }
 
+func (pc *pushComm) chainable() error {
+	if pc.boot.msg.ArgTypeX == ArgTypeFQN {
+		return pc.baseComm.chainable()
+	}
+	return nil
+}
+
+func (pc *pushComm) StreamTransform(r cos.ReadCloseSizer, lom *core.LOM, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	if err := pc.boot.xctn.AbortErr(); err != nil {
+		cos.Close(r)
+		return nil, err
+	}
+	if err := pc.chainable(); err != nil {
+		cos.Close(r)
+		return nil, err
+	}
+	u := pc.boot.uri + "/" + lom.Bck().Name + "/" + lom.ObjName
+	out, _, err := pc.put(u, r, r.Size(), timeout)
+	if cmn.Rom.FastV(5, cos.SmoduleETL) {
+		nlog.Infoln(Hpush, "stream", lom.Cname(), err)
+	}
+	return out, err
+}
+
 func (pc *pushComm) OfflineTransform(lom *core.LOM, timeout time.Duration) (r cos.ReadCloseSizer, err error) {
 	clone := *lom
 	r, err = pc.doRequest(&clone, timeout)
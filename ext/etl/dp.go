@@ -5,6 +5,8 @@
 package etl
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/NVIDIA/aistore/api/apc"
@@ -13,6 +15,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/cmn/nlog"
 	"github.com/NVIDIA/aistore/core"
+	"github.com/OneOfOne/xxhash"
 )
 
 // NOTE: compare with core/ldp.go
@@ -20,8 +23,10 @@ import (
 type (
 	OfflineDP struct {
 		comm           Communicator
+		chain          []Communicator // subsequent stages, if any (see apc.Transform.Chain)
 		tcbmsg         *apc.TCBMsg
 		config         *cmn.Config
+		tid            string
 		requestTimeout time.Duration
 	}
 )
@@ -36,16 +41,49 @@ func NewOfflineDP(msg *apc.TCBMsg, config *cmn.Config) (*OfflineDP, error) {
 	}
 	pr := &OfflineDP{comm: comm, tcbmsg: msg, config: config}
 	pr.requestTimeout = time.Duration(msg.Transform.Timeout)
+	for _, name := range msg.Transform.Chain {
+		c, err := GetCommunicator(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.chainable(); err != nil {
+			return nil, err
+		}
+		pr.chain = append(pr.chain, c)
+	}
+	pr.tid = pr.digest()
 	return pr, nil
 }
 
+// identifies the transformation: all stages, in order, including each ETL's init parameters;
+// same across targets (see apc.Transform.Cache)
+func (dp *OfflineDP) TransformID() string { return dp.tid }
+
+func (dp *OfflineDP) digest() string {
+	h := xxhash.New64()
+	dp.comm.digest(h)
+	for _, c := range dp.chain {
+		h.Write([]byte{'>'})
+		c.digest(h)
+	}
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// e.g. "decode->resize->encode"
+func (dp *OfflineDP) name() string {
+	if len(dp.tcbmsg.Transform.Chain) == 0 {
+		return dp.tcbmsg.Transform.Name
+	}
+	return dp.tcbmsg.Transform.Name + "->" + strings.Join(dp.tcbmsg.Transform.Chain, "->")
+}
+
 // Returns reader resulting from lom ETL transformation.
 // TODO -- FIXME: comm.OfflineTransform to support latestVer and sync
 func (dp *OfflineDP) Reader(lom *core.LOM, latestVer, sync bool) (cos.ReadOpenCloser, cos.OAH, error) {
 	var (
 		r      cos.ReadCloseSizer // note: +sizer
 		err    error
-		action = "read [" + dp.tcbmsg.Transform.Name + "]-transformed " + lom.Cname()
+		action = "read [" + dp.name() + "]-transformed " + lom.Cname()
 	)
 	debug.Assert(!latestVer && !sync, "NIY") // TODO -- FIXME
 	call := func() (int, error) {
@@ -65,6 +103,10 @@ func (dp *OfflineDP) Reader(lom *core.LOM, latestVer, sync bool) (cos.ReadOpenCl
 	if cmn.Rom.FastV(5, cos.SmoduleETL) {
 		nlog.Infoln(action, err)
 	}
+	// stream through the subsequent stages (not retrying: the intermediate content is consumed)
+	for i := 0; err == nil && i < len(dp.chain); i++ {
+		r, err = dp.chain[i].StreamTransform(r, lom, dp.requestTimeout)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
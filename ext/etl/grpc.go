@@ -0,0 +1,228 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/memsys"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// gRPC communication (comm-type Grpc): one long-lived HTTP/2 connection per transformer pod,
+// with each object transformed over its own bidirectional stream (RPC):
+// - method: grpcMethod; messages are raw bytes (no protobuf - see grpcCodec), which is why
+//   the server must use identity (de)serializers;
+// - request: the first message is the object's "bucket/object-name", followed by the object's content
+//   in chunks of up to grpcChunkSize; half-close upon EOF;
+// - response: transformed content in (arbitrarily sized) chunks, up to grpcMaxMsgSize each;
+//   non-OK status fails the transformation;
+// - the transformer may start responding before it has received the entire object (large objects);
+// - offline (TCB) transformations carry the request timeout as the RPC deadline.
+// Compared with HTTP push, small-object workloads avoid per-request connection overhead.
+
+const (
+	grpcMethod     = "/aistore.etl.v1.Transformer/Transform"
+	grpcChunkSize  = memsys.DefaultBufSize
+	grpcMaxMsgSize = 16 * cos.MiB
+)
+
+type (
+	grpcComm struct {
+		baseComm
+		conn *grpc.ClientConn
+	}
+	// raw-bytes codec (named "proto" to interoperate with the servers that expect the default content-subtype)
+	grpcCodec struct{}
+
+	// response messages => io.Reader
+	grpcReader struct {
+		stream grpc.ClientStream
+		msg    []byte
+		off    int
+	}
+)
+
+var grpcDesc = grpc.StreamDesc{StreamName: "Transform", ServerStreams: true, ClientStreams: true}
+
+// interface guard
+var _ Communicator = (*grpcComm)(nil)
+
+func newGrpcComm(comm baseComm) *grpcComm {
+	gc := &grpcComm{baseComm: comm}
+	target := strings.TrimPrefix(comm.boot.uri, "http://")
+	conn, err := grpc.NewClient(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(grpcCodec{}), grpc.MaxCallRecvMsgSize(grpcMaxMsgSize)),
+	)
+	debug.AssertNoErr(err) // (fails only when the target cannot be parsed)
+	gc.conn = conn
+	return gc
+}
+
+func (gc *grpcComm) Stop() {
+	if gc.conn != nil {
+		gc.conn.Close()
+	}
+	gc.baseComm.Stop()
+}
+
+func (*grpcComm) chainable() error { return nil }
+
+func (gc *grpcComm) InlineTransform(w http.ResponseWriter, _ *http.Request, lom *core.LOM) error {
+	if err := gc.boot.xctn.AbortErr(); err != nil {
+		return err
+	}
+	fh, err := openObj(lom)
+	if err != nil {
+		return err
+	}
+	r, err := gc.transform(fh, lom, 0 /*timeout*/)
+	if err != nil {
+		return err
+	}
+	buf, slab := core.T.PageMM().Alloc()
+	_, err = io.CopyBuffer(w, r, buf)
+	slab.Free(buf)
+	r.Close()
+	if cmn.Rom.FastV(5, cos.SmoduleETL) {
+		nlog.Infoln(Grpc, lom.Cname(), err)
+	}
+	return err
+}
+
+func (gc *grpcComm) OfflineTransform(lom *core.LOM, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	if err := gc.boot.xctn.AbortErr(); err != nil {
+		return nil, err
+	}
+	clone := *lom
+	fh, err := openObj(&clone)
+	if err != nil {
+		return nil, err
+	}
+	r, err := gc.transform(fh, &clone, timeout)
+	if cmn.Rom.FastV(5, cos.SmoduleETL) {
+		nlog.Infoln(Grpc, clone.Cname(), err)
+	}
+	return r, err
+}
+
+func (gc *grpcComm) StreamTransform(r cos.ReadCloseSizer, lom *core.LOM, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	if err := gc.boot.xctn.AbortErr(); err != nil {
+		cos.Close(r)
+		return nil, err
+	}
+	out, err := gc.transform(r, lom, timeout)
+	if cmn.Rom.FastV(5, cos.SmoduleETL) {
+		nlog.Infoln(Grpc, "stream", lom.Cname(), err)
+	}
+	return out, err
+}
+
+// start the RPC and send the content in the background; closes `in` in all cases;
+// closing the returned reader cancels the RPC (if still in progress)
+func (gc *grpcComm) transform(in io.ReadCloser, lom *core.LOM, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if timeout != 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	stream, err := gc.conn.NewStream(ctx, &grpcDesc, grpcMethod)
+	if err == nil {
+		err = stream.SendMsg([]byte(lom.Bck().Name + "/" + lom.ObjName))
+	}
+	if err != nil {
+		cancel()
+		cos.Close(in)
+		return nil, gc.rerr(lom, err)
+	}
+	go gc.send(stream, in, cancel)
+	gr := &grpcReader{stream: stream}
+	return cos.NewReaderWithArgs(cos.ReaderArgs{R: gr, Size: -1, DeferCb: cancel}), nil
+}
+
+func (gc *grpcComm) send(stream grpc.ClientStream, in io.ReadCloser, cancel context.CancelFunc) {
+	buf, slab := core.T.PageMM().AllocSize(grpcChunkSize)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			if errS := stream.SendMsg(buf[:n]); errS != nil {
+				break // (the actual error, if any, is returned by RecvMsg)
+			}
+		}
+		if err == nil {
+			continue
+		}
+		if err == io.EOF {
+			stream.CloseSend()
+		} else {
+			nlog.Warningln(gc.String(), "failed to read:", err)
+			cancel()
+		}
+		break
+	}
+	slab.Free(buf)
+	cos.Close(in)
+}
+
+func (gc *grpcComm) rerr(lom *core.LOM, err error) error {
+	return cmn.NewErrETLf(&cmn.ETLErrCtx{TID: core.T.SID(), ETLName: gc.boot.msg.IDX, PodName: gc.PodName()},
+		"failed to transform %s: %v", lom.Cname(), err)
+}
+
+////////////////
+// grpcReader //
+////////////////
+
+func (r *grpcReader) Read(p []byte) (int, error) {
+	for r.off >= len(r.msg) {
+		r.msg, r.off = r.msg[:0], 0
+		if err := r.stream.RecvMsg(&r.msg); err != nil {
+			return 0, err // (io.EOF when done)
+		}
+	}
+	n := copy(p, r.msg[r.off:])
+	r.off += n
+	return n, nil
+}
+
+///////////////
+// grpcCodec //
+///////////////
+
+func (grpcCodec) Name() string { return "proto" }
+
+// (copying: the caller may reuse its buffer as soon as SendMsg returns)
+func (grpcCodec) Marshal(v any) ([]byte, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, errors.New("grpc codec: expecting []byte")
+	}
+	return append([]byte(nil), b...), nil
+}
+
+func (grpcCodec) Unmarshal(data []byte, v any) error {
+	p, ok := v.(*[]byte)
+	if !ok {
+		return errors.New("grpc codec: expecting *[]byte")
+	}
+	*p = append((*p)[:0], data...)
+	return nil
+}
@@ -0,0 +1,235 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/OneOfOne/xxhash"
+)
+
+// Range reads of the inline-transformed content (GET with "Range" header) - per-ETL capability
+// (see InitMsgBase.RangeRead):
+// - RangeReadPass: the requested range of the object is read and transformed (streamed to the transformer,
+//   same as subsequent stages of a chain - see chainable);
+// - RangeReadCache: the entire object is transformed and the result kept in memory (rangeCache), so that
+//   subsequent range reads of the same object (e.g., random access into a shard) do not re-transform it;
+//   cached content is keyed by the ETL's init parameters (see digest) and the source's checksum or,
+//   when not checksummed, size, version, and mtime;
+// - the cache is shared by all ETLs and bounded by rcacheCap (LRU); transformed objects larger than
+//   rcacheMaxObj are served but not cached; entries idle for more than rcacheIdle, and the ETL's entries
+//   upon Stop, are evicted; nothing gets cached under high memory pressure.
+
+const (
+	rcacheCap    = 512 * cos.MiB
+	rcacheMaxObj = rcacheCap / 8
+	rcacheIdle   = 10 * 60 // seconds
+)
+
+type (
+	// RangeFunc selects [off, off+length) given the size of the entire transformed content
+	RangeFunc func(size int64) (off, length int64, err error)
+
+	rcEntry struct {
+		sgl   *memsys.SGL
+		key   string
+		xid   string // ETL xaction
+		atime int64  // mono, seconds
+		refc  int32
+		gone  bool // evicted, to free upon last release
+	}
+	rangeCache struct {
+		m        map[string]*rcEntry
+		pressure func() int // memsys.Pressure*
+		size     int64
+		mu       sync.Mutex
+	}
+)
+
+var rcache = &rangeCache{
+	m:        make(map[string]*rcEntry),
+	pressure: func() int { return core.T.PageMM().Pressure() },
+}
+
+// TransformRange returns the selected range of the (inline) transformed object (see rangeReads)
+func TransformRange(comm Communicator, lom *core.LOM, rng RangeFunc) (cos.ReadCloseSizer, error) {
+	switch comm.rangeRead() {
+	case RangeReadPass:
+		return transformSection(comm, lom, rng)
+	case RangeReadCache:
+		return transformCached(comm, lom, rng)
+	default:
+		return nil, cmn.NewErrUnsupp("range-read", lom.Cname()+" via "+comm.String()+" (no range-read capability)")
+	}
+}
+
+// range-preserving: same offset and length at the source
+func transformSection(comm Communicator, lom *core.LOM, rng RangeFunc) (cos.ReadCloseSizer, error) {
+	if err := comm.Xact().AbortErr(); err != nil {
+		return nil, err
+	}
+	fh, err := openObj(lom)
+	if err != nil {
+		return nil, err
+	}
+	off, length, err := rng(lom.Lsize())
+	if err != nil {
+		cos.Close(fh)
+		return nil, err
+	}
+	sec := cos.NewSectionHandle(fh.(io.ReaderAt), off, length, 0) // (file handle or decrypting reader)
+	r := cos.NewReaderWithArgs(cos.ReaderArgs{R: sec, Size: length, DeferCb: func() { cos.Close(fh) }})
+	out, err := comm.StreamTransform(r, lom, 0 /*timeout*/)
+	if err != nil || out.Size() == length {
+		return out, err
+	}
+	// (range-preserving - the size is known)
+	return cos.NewReaderWithArgs(cos.ReaderArgs{R: out, Size: length}), nil
+}
+
+func transformCached(comm Communicator, lom *core.LOM, rng RangeFunc) (cos.ReadCloseSizer, error) {
+	if err := lomLoad(lom); err != nil {
+		return nil, err
+	}
+	var (
+		key = rcacheKey(comm, lom)
+		e   = rcache.get(key)
+	)
+	if e == nil {
+		r, err := comm.OfflineTransform(lom, 0 /*timeout*/)
+		if err != nil {
+			return nil, err
+		}
+		sgl := core.T.PageMM().NewSGL(max(r.Size(), 0))
+		_, err = io.Copy(sgl, r)
+		r.Close()
+		if err != nil {
+			sgl.Free()
+			return nil, err
+		}
+		e = rcache.put(&rcEntry{sgl: sgl, key: key, xid: comm.Xact().ID(), refc: 1})
+	}
+	size := e.sgl.Size()
+	off, length, err := rng(size)
+	if err != nil {
+		rcache.release(e)
+		return nil, err
+	}
+	sr := memsys.NewReader(e.sgl) // (own offset - concurrent readers ok)
+	sr.Seek(off, io.SeekStart)
+	args := cos.ReaderArgs{R: io.LimitReader(sr, length), Size: length, DeferCb: func() { rcache.release(e) }}
+	return cos.NewReaderWithArgs(args), nil
+}
+
+func rcacheKey(comm Communicator, lom *core.LOM) string {
+	h := xxhash.New64()
+	comm.digest(h)
+	h.WriteString("\x00" + lom.Uname())
+	if cksum := lom.Checksum(); !cksum.IsEmpty() {
+		h.WriteString("\x00" + cksum.Ty() + "\x00" + cksum.Val())
+	} else {
+		h.WriteString("\x00" + strconv.FormatInt(lom.Lsize(), 10))
+		if ver := lom.VersionPtr(); ver != nil {
+			h.WriteString("\x00" + *ver)
+		}
+		if _, _, mtime, err := lom.Fstat(false /*get atime*/); err == nil {
+			h.WriteString("\x00" + strconv.FormatInt(mtime.UnixNano(), 10))
+		}
+	}
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+////////////////
+// rangeCache //
+////////////////
+
+// returns referenced entry, if cached
+func (c *rangeCache) get(key string) (e *rcEntry) {
+	c.mu.Lock()
+	if e = c.m[key]; e != nil {
+		e.refc++
+		e.atime = mono.NanoTime() / 1e9
+	}
+	c.mu.Unlock()
+	return e
+}
+
+// adds new (referenced) entry unless too large or under memory pressure;
+// returns the entry to use (the existing one when another caller got there first)
+func (c *rangeCache) put(e *rcEntry) *rcEntry {
+	size := e.sgl.Size()
+	if size > rcacheMaxObj || c.pressure() >= memsys.PressureHigh {
+		e.gone = true // (not cached - freed upon release)
+		return e
+	}
+	now := mono.NanoTime() / 1e9
+	e.atime = now
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing := c.m[e.key]; existing != nil {
+		e.sgl.Free()
+		existing.refc++
+		existing.atime = now
+		return existing
+	}
+	for k, en := range c.m {
+		if now-en.atime > rcacheIdle {
+			c._evict(k, en)
+		}
+	}
+	for c.size+size > rcacheCap && len(c.m) > 0 {
+		var (
+			lru *rcEntry
+			k   string
+		)
+		for kk, en := range c.m {
+			if lru == nil || en.atime < lru.atime {
+				lru, k = en, kk
+			}
+		}
+		c._evict(k, lru)
+	}
+	c.m[e.key] = e
+	c.size += size
+	return e
+}
+
+func (c *rangeCache) release(e *rcEntry) {
+	c.mu.Lock()
+	e.refc--
+	if e.refc == 0 && e.gone {
+		e.sgl.Free()
+	}
+	c.mu.Unlock()
+}
+
+// upon Stop
+func (c *rangeCache) purge(xid string) {
+	c.mu.Lock()
+	for k, e := range c.m {
+		if e.xid == xid {
+			c._evict(k, e)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// under lock
+func (c *rangeCache) _evict(k string, e *rcEntry) {
+	delete(c.m, k)
+	c.size -= e.sgl.Size()
+	e.gone = true
+	if e.refc == 0 {
+		e.sgl.Free()
+	}
+}
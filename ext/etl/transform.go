@@ -322,15 +322,13 @@ func Stop(id string, errCause error) error {
 	}
 
 	c.Stop()
+	rcache.purge(c.Xact().ID())
 
 	return nil
 }
 
 // StopAll terminates all running ETLs.
 func StopAll() {
-	if !k8s.IsK8s() {
-		return
-	}
 	for _, e := range List() {
 		if err := Stop(e.Name, nil); err != nil {
 			nlog.Errorln(err)
@@ -348,8 +346,20 @@ func GetCommunicator(etlName string) (Communicator, error) {
 
 func List() []Info { return reg.list() }
 
+// (in-process ETLs have no pods - see wasmComm)
+func getPodName(etlName string) (string, error) {
+	c, err := GetCommunicator(etlName)
+	if err != nil {
+		return "", err
+	}
+	if c.PodName() == "" {
+		return "", fmt.Errorf("%s: not supported - no ETL container (comm-type %q)", c, WasmStdio)
+	}
+	return c.PodName(), nil
+}
+
 func PodLogs(transformID string) (logs Logs, err error) {
-	c, err := GetCommunicator(transformID)
+	podName, err := getPodName(transformID)
 	if err != nil {
 		return logs, err
 	}
@@ -357,7 +367,7 @@ func PodLogs(transformID string) (logs Logs, err error) {
 	if err != nil {
 		return logs, err
 	}
-	b, err := client.Logs(c.PodName())
+	b, err := client.Logs(podName)
 	if err != nil {
 		return logs, err
 	}
@@ -368,7 +378,7 @@ func PodLogs(transformID string) (logs Logs, err error) {
 }
 
 func PodHealth(etlName string) (string, error) {
-	c, err := GetCommunicator(etlName)
+	podName, err := getPodName(etlName)
 	if err != nil {
 		return "", err
 	}
@@ -376,11 +386,11 @@ func PodHealth(etlName string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return client.Health(c.PodName())
+	return client.Health(podName)
 }
 
 func PodMetrics(etlName string) (*CPUMemUsed, error) {
-	c, err := GetCommunicator(etlName)
+	podName, err := getPodName(etlName)
 	if err != nil {
 		return nil, err
 	}
@@ -388,7 +398,7 @@ func PodMetrics(etlName string) (*CPUMemUsed, error) {
 	if err != nil {
 		return nil, err
 	}
-	cpuUsed, memUsed, err := k8s.Metrics(c.PodName())
+	cpuUsed, memUsed, err := k8s.Metrics(podName)
 	if err == nil {
 		return &CPUMemUsed{TargetID: core.T.SID(), CPU: cpuUsed, Mem: memUsed}, nil
 	}
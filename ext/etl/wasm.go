@@ -0,0 +1,258 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/xact/xreg"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// In-process WebAssembly runtime (comm-type WasmStdio) - an alternative to ETL containers
+// for lightweight per-object transformations:
+// - upon init, each target compiles the user-supplied module once; there's no pod (and no pod startup);
+// - each transformed object runs in its own (sandboxed) module instance;
+// - the module must be a WASI (preview1) command: `_start` reads the object's content from stdin
+//   and writes the transformed content to stdout; non-zero exit code fails the transformation;
+// - args: [etl-name, bucket, object-name]; stderr (up to wasmMaxStderr) is reported with the error;
+// - instance memory is limited by InitWasmMsg.MaxMem; offline (TCB) transformations are also
+//   limited by the request timeout; no filesystem, network, or environment access.
+
+const (
+	DefaultWasmMaxMem = 256 * cos.MiB
+
+	wasmMagic     = "\x00asm"
+	wasmPageSize  = 64 * cos.KiB
+	wasmMaxMem    = 4 * cos.GiB // (wasm32)
+	wasmMaxStderr = 4 * cos.KiB
+	wasmStart     = "_start"
+)
+
+type (
+	wasmComm struct {
+		listener meta.Slistener
+		xctn     core.Xact
+		msg      *InitWasmMsg
+		rt       wazero.Runtime
+		compiled wazero.CompiledModule
+	}
+	// bounded
+	wasmStderr struct {
+		b []byte
+	}
+)
+
+// interface guard
+var _ Communicator = (*wasmComm)(nil)
+
+// (compare with InitSpec)
+func InitWasm(msg *InitWasmMsg, xid string) error {
+	errCtx := &cmn.ETLErrCtx{TID: core.T.SID(), ETLName: msg.IDX}
+	comm, err := newWasmComm(newAborter(msg.IDX), msg)
+	if err != nil {
+		return cmn.NewErrETL(errCtx, err.Error())
+	}
+	rns := xreg.RenewETL(msg, xid)
+	debug.AssertNoErr(rns.Err)
+	comm.xctn = rns.Entry.Get()
+	debug.Assertf(comm.xctn.ID() == xid, "%s vs %s", comm.xctn.ID(), xid)
+
+	if err := reg.add(msg.IDX, comm); err != nil {
+		comm.Stop()
+		return err
+	}
+	core.T.Sowner().Listeners().Reg(comm)
+	if cmn.Rom.FastV(4, cos.SmoduleETL) {
+		nlog.Infof("started etl[%s], msg %s", msg.IDX, msg)
+	}
+	return nil
+}
+
+func newWasmComm(listener meta.Slistener, msg *InitWasmMsg) (*wasmComm, error) {
+	var (
+		ctx    = context.Background()
+		maxMem = int64(msg.MaxMem)
+	)
+	if maxMem == 0 {
+		maxMem = DefaultWasmMaxMem
+	}
+	config := wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(uint32(maxMem / wasmPageSize))
+	rt := wazero.NewRuntimeWithConfig(ctx, config)
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, rt); err != nil {
+		rt.Close(ctx)
+		return nil, err
+	}
+	compiled, err := rt.CompileModule(ctx, msg.Wasm)
+	if err != nil {
+		rt.Close(ctx)
+		return nil, fmt.Errorf("failed to compile %s module: %v", Wasm, err)
+	}
+	if _, ok := compiled.ExportedFunctions()[wasmStart]; !ok {
+		rt.Close(ctx)
+		return nil, fmt.Errorf("%s module does not export %q (expecting WASI command)", Wasm, wasmStart)
+	}
+	return &wasmComm{listener: listener, msg: msg, rt: rt, compiled: compiled}, nil
+}
+
+func (c *wasmComm) Name() string       { return c.msg.IDX }
+func (*wasmComm) PodName() string      { return "" } // (no pods)
+func (*wasmComm) SvcName() string      { return "" }
+func (c *wasmComm) Xact() core.Xact    { return c.xctn }
+func (c *wasmComm) ObjCount() int64    { return c.xctn.Objs() }
+func (c *wasmComm) InBytes() int64     { return c.xctn.InBytes() }
+func (c *wasmComm) OutBytes() int64    { return c.xctn.OutBytes() }
+func (*wasmComm) chainable() error     { return nil }
+func (c *wasmComm) rangeRead() string  { return c.msg.RangeRead }
+func (c *wasmComm) ListenSmapChanged() { c.listener.ListenSmapChanged() }
+
+// name and module (see OfflineDP.TransformID)
+func (c *wasmComm) digest(w io.Writer) {
+	io.WriteString(w, c.msg.IDX)
+	w.Write([]byte{0})
+	w.Write(c.msg.Wasm)
+}
+
+func (c *wasmComm) String() string {
+	return fmt.Sprintf("%s[%s]-%s", c.msg.IDX, c.xctn.ID(), WasmStdio)
+}
+
+func (c *wasmComm) Stop() {
+	c.rt.Close(context.Background()) // (terminates running instances, if any)
+	c.xctn.Finish()
+}
+
+func (c *wasmComm) InlineTransform(w http.ResponseWriter, _ *http.Request, lom *core.LOM) error {
+	if err := c.xctn.AbortErr(); err != nil {
+		return err
+	}
+	fh, err := openObj(lom)
+	if err != nil {
+		return err
+	}
+	err = c.run(context.Background(), fh, w, lom)
+	cos.Close(fh)
+	if cmn.Rom.FastV(5, cos.SmoduleETL) {
+		nlog.Infoln(WasmStdio, lom.Cname(), err)
+	}
+	return err
+}
+
+func (c *wasmComm) OfflineTransform(lom *core.LOM, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	if err := c.xctn.AbortErr(); err != nil {
+		return nil, err
+	}
+	clone := *lom
+	fh, err := openObj(&clone)
+	if err != nil {
+		return nil, err
+	}
+	return c.stream(fh, &clone, timeout), nil
+}
+
+func (c *wasmComm) StreamTransform(r cos.ReadCloseSizer, lom *core.LOM, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	if err := c.xctn.AbortErr(); err != nil {
+		cos.Close(r)
+		return nil, err
+	}
+	return c.stream(r, lom, timeout), nil
+}
+
+// open the object to transform (in-process or streaming it to the transformer - see grpcComm)
+// (compare with pushComm.doRequest)
+func openObj(lom *core.LOM) (fh cos.ReadOpenCloser, err error) {
+	if err = lom.InitBck(lom.Bucket()); err != nil {
+		return nil, err
+	}
+	fh, err = _open(lom)
+	if err != nil && cos.IsNotExist(err, 0) && lom.Bucket().IsRemote() {
+		if _, err = core.T.GetCold(context.Background(), lom, cmn.OwtGetLock); err != nil {
+			return nil, err
+		}
+		fh, err = _open(lom)
+	}
+	return fh, err
+}
+
+func _open(lom *core.LOM) (cos.ReadOpenCloser, error) {
+	lom.Lock(false)
+	defer lom.Unlock(false)
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		return nil, err
+	}
+	return lom.NewHandle(lom.FQN)
+}
+
+// run the module in the background, with its stdout piped to the returned reader;
+// closing the latter terminates the instance (if still running)
+func (c *wasmComm) stream(in io.ReadCloser, lom *core.LOM, timeout time.Duration) cos.ReadCloseSizer {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		pr, pw = io.Pipe()
+	)
+	if timeout != 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	go func() {
+		err := c.run(ctx, in, pw, lom)
+		cos.Close(in)
+		if cmn.Rom.FastV(5, cos.SmoduleETL) {
+			nlog.Infoln(WasmStdio, lom.Cname(), err)
+		}
+		pw.CloseWithError(err) // (nil => io.EOF)
+	}()
+	return cos.NewReaderWithArgs(cos.ReaderArgs{R: pr, Size: -1, DeferCb: cancel})
+}
+
+// instantiate and run to completion
+func (c *wasmComm) run(ctx context.Context, stdin io.Reader, stdout io.Writer, lom *core.LOM) error {
+	var (
+		stderr wasmStderr
+		config = wazero.NewModuleConfig().
+			WithName(""). // (anonymous - many concurrent instances)
+			WithArgs(c.msg.IDX, lom.Bck().Cname(""), lom.ObjName).
+			WithStdin(stdin).
+			WithStdout(stdout).
+			WithStderr(&stderr)
+	)
+	mod, err := c.rt.InstantiateModule(ctx, c.compiled, config)
+	if mod != nil {
+		mod.Close(ctx)
+	}
+	if err == nil {
+		return nil
+	}
+	if len(stderr.b) > 0 {
+		return fmt.Errorf("%s: failed to transform %s: %v (stderr: %q)", c, lom.Cname(), err, stderr.b)
+	}
+	return fmt.Errorf("%s: failed to transform %s: %v", c, lom.Cname(), err)
+}
+
+////////////////
+// wasmStderr //
+////////////////
+
+func (e *wasmStderr) Write(p []byte) (int, error) {
+	if n := min(len(p), wasmMaxStderr-len(e.b)); n > 0 {
+		e.b = append(e.b, p[:n]...)
+	}
+	return len(p), nil
+}
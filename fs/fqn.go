@@ -35,6 +35,7 @@ const (
 	WorkfileAppend       = "append"         // APPEND to object (as file)
 	WorkfileAppendToArch = "append-to-arch" // APPEND to existing archive
 	WorkfileCreateArch   = "create-arch"    // CREATE multi-object archive
+	WorkfileRestore      = "restore"        // restore object from bucket snapshot
 )
 
 type ParsedFQN struct {
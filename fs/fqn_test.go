@@ -414,3 +414,51 @@ func BenchmarkParseFQN(b *testing.B) {
 		parsed.Init(fqn)
 	}
 }
+
+func TestParseTrash(t *testing.T) {
+	tests := []struct {
+		base        string
+		wantOrig    string
+		wantDeleted int64
+		wantOK      bool
+	}{
+		{"obj.1700000000000000000", "obj", 1700000000000000000, true},
+		{"obj.tar.1700000000000000000", "obj.tar", 1700000000000000000, true},
+		{"obj", "", 0, false},
+		{"obj.tar", "", 0, false},
+		{".1700000000000000000", "", 0, false},
+		{"obj.-1", "", 0, false},
+	}
+	resolver := &fs.TrashContentResolver{}
+	for _, tt := range tests {
+		orig, deleted, ok := fs.ParseTrash(tt.base)
+		if ok != tt.wantOK || orig != tt.wantOrig || deleted != tt.wantDeleted {
+			t.Errorf("ParseTrash(%q) = (%q, %d, %t), want (%q, %d, %t)",
+				tt.base, orig, deleted, ok, tt.wantOrig, tt.wantDeleted, tt.wantOK)
+		}
+	}
+	// round trip
+	ufqn := resolver.GenUniqueFQN("dir/obj.tar", fs.TrashPrefix(42))
+	orig, old, ok := resolver.ParseUniqueFQN(ufqn)
+	tassert.Errorf(t, ok && !old && orig == "dir/obj.tar", "unexpected (%q, %t, %t)", orig, old, ok)
+}
+
+func TestParseSnapMeta(t *testing.T) {
+	tests := []struct {
+		fqn      string
+		wantName string
+		wantOK   bool
+	}{
+		{"/tmp/mp/@ais/#ns/bck/%sn/s1.snap", "s1", true},
+		{"/tmp/mp/@ais/#ns/bck/%sn/daily.2024-10-01.snap", "daily.2024-10-01", true},
+		{"/tmp/mp/@ais/#ns/bck/%sn/.snap", "", false},
+		{"/tmp/mp/@ais/#ns/bck/%sn/s1", "", false},
+		{"/tmp/mp/@ais/#ns/bck/%sn/s1.snap.tmp.abc", "", false},
+	}
+	for _, tt := range tests {
+		name, ok := fs.ParseSnapMeta(tt.fqn)
+		if ok != tt.wantOK || name != tt.wantName {
+			t.Errorf("ParseSnapMeta(%q) = (%q, %t), want (%q, %t)", tt.fqn, name, ok, tt.wantName, tt.wantOK)
+		}
+	}
+}
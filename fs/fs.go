@@ -171,6 +171,16 @@ func (mi *Mountpath) IsAvail() bool {
 	return ok
 }
 
+// true if any of the underlying disks is a spinning one (HDD)
+func (mi *Mountpath) IsRotational() bool {
+	for _, disk := range mi.Disks {
+		if ios.IsRotational(disk) {
+			return true
+		}
+	}
+	return false
+}
+
 func (mi *Mountpath) CreateMissingBckDirs(bck *cmn.Bck) (err error) {
 	for contentType := range CSM.m {
 		dir := mi.MakePathCT(bck, contentType)
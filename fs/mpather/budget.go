@@ -0,0 +1,45 @@
+// Package mpather provides per-mountpath concepts.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package mpather
+
+import (
+	"context"
+
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"golang.org/x/sync/semaphore"
+)
+
+// BufBudget bounds the total size of the buffers allocated by all joggers (and jogger groups)
+// that share it (see JgroupOpts.BufBudget):
+// - each jogger acquires (the total size of) its buffers at startup and releases them when done;
+// - when the budget is exhausted, joggers queue (until aborted);
+// - a jogger that wants more than the entire budget gets the entire budget
+type BufBudget struct {
+	sema *semaphore.Weighted
+	size int64
+	used atomic.Int64
+}
+
+func NewBufBudget(size int64) *BufBudget {
+	return &BufBudget{sema: semaphore.NewWeighted(size), size: size}
+}
+
+func (b *BufBudget) Size() int64 { return b.size }
+func (b *BufBudget) Used() int64 { return b.used.Load() }
+
+// returns the acquired size
+func (b *BufBudget) acquire(ctx context.Context, size int64) (int64, error) {
+	size = min(size, b.size)
+	if err := b.sema.Acquire(ctx, size); err != nil {
+		return 0, err
+	}
+	b.used.Add(size)
+	return size, nil
+}
+
+func (b *BufBudget) release(size int64) {
+	b.used.Sub(size)
+	b.sema.Release(size)
+}
@@ -0,0 +1,197 @@
+// Package mpather provides per-mountpath concepts.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package mpather
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/mono"
+)
+
+// Governor: per-target resource governor for background xactions (rebalance, tcb, ec-encode, lru, etc.)
+// - caps the aggregate number of (concurrently visiting) joggers and the aggregate
+//   disk bandwidth - see cmn.GovernorConf;
+// - each registered client (typically, a running xaction) gets its share of the caps
+//   proportional to its weight (by xaction kind);
+// - joggers may borrow idle share - but not while other clients are waiting within their own;
+// - config changes take effect immediately.
+
+const (
+	govPoll     = 100 * time.Millisecond // re-check while waiting
+	govBurst    = 100 * time.Millisecond // bandwidth bucket capacity, in seconds of the (current) rate
+	govMaxSleep = time.Second            // max bandwidth-throttling sleep per visit
+)
+
+type (
+	governor struct {
+		clients map[*GovClient]struct{}
+		wake    chan struct{} // closed (and renewed) upon release when there are waiters
+		mu      sync.Mutex
+		total   int // joggers in use
+		waiting int
+	}
+	GovClient struct {
+		kind    string
+		inuse   int
+		waiting int
+		tokens  float64 // bandwidth
+		last    int64   // mono
+	}
+)
+
+var gov = governor{clients: make(map[*GovClient]struct{}, 8), wake: make(chan struct{})}
+
+// (the caller must Unreg when done - see also Jgroup)
+func RegGov(kind string) *GovClient {
+	c := &GovClient{kind: kind}
+	gov.mu.Lock()
+	gov.clients[c] = struct{}{}
+	gov.mu.Unlock()
+	return c
+}
+
+func (c *GovClient) Unreg() {
+	gov.mu.Lock()
+	if _, ok := gov.clients[c]; ok {
+		delete(gov.clients, c)
+		gov._wake() // (shares change)
+	}
+	gov.mu.Unlock()
+}
+
+// acquire jogger; block while over the limit; return false when stopped
+func (c *GovClient) Acquire(stopped func() bool) bool {
+	var (
+		timer  *time.Timer
+		waited bool
+	)
+	for {
+		config := cmn.GCO.Get()
+		gov.mu.Lock()
+		if gov.fits(c, &config.Governor) {
+			c.inuse++
+			gov.total++
+			if waited {
+				c.waiting--
+				gov.waiting--
+			}
+			gov.mu.Unlock()
+			if timer != nil {
+				timer.Stop()
+			}
+			return true
+		}
+		if !waited {
+			c.waiting++
+			gov.waiting++
+			waited = true
+		}
+		wake := gov.wake
+		gov.mu.Unlock()
+
+		if timer == nil {
+			timer = time.NewTimer(govPoll)
+		} else {
+			timer.Reset(govPoll)
+		}
+		select {
+		case <-wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+		if stopped() {
+			gov.mu.Lock()
+			c.waiting--
+			gov.waiting--
+			gov.mu.Unlock()
+			timer.Stop()
+			return false
+		}
+	}
+}
+
+func (c *GovClient) Release() {
+	gov.mu.Lock()
+	c.inuse--
+	gov.total--
+	gov._wake()
+	gov.mu.Unlock()
+}
+
+// account for size bytes read from (or written to) disk; throttle when over the client's share
+func (c *GovClient) Charge(size int64) {
+	conf := &cmn.GCO.Get().Governor
+	if conf.Bandwidth <= 0 || size <= 0 {
+		return
+	}
+	gov.mu.Lock()
+	var (
+		rate = float64(conf.Bandwidth) * gov.share(c, conf)
+		now  = mono.NanoTime()
+	)
+	if c.last != 0 {
+		elapsed := time.Duration(now - c.last).Seconds()
+		c.tokens = min(c.tokens+rate*elapsed, rate*govBurst.Seconds())
+	}
+	c.last = now
+	c.tokens -= float64(size)
+	tokens := c.tokens
+	gov.mu.Unlock()
+
+	if tokens < 0 {
+		time.Sleep(min(time.Duration(-tokens/rate*float64(time.Second)), govMaxSleep))
+	}
+}
+
+//////////////
+// governor //
+//////////////
+
+// (under lock)
+func (g *governor) _wake() {
+	if g.waiting > 0 {
+		close(g.wake)
+		g.wake = make(chan struct{})
+	}
+}
+
+// (under lock) the client's fraction of the total
+func (g *governor) share(c *GovClient, conf *cmn.GovernorConf) float64 {
+	var wsum int
+	for o := range g.clients {
+		wsum += conf.Weight(o.kind)
+	}
+	if wsum == 0 { // (unregistered)
+		return 1
+	}
+	return float64(conf.Weight(c.kind)) / float64(wsum)
+}
+
+// (under lock)
+func (g *governor) fits(c *GovClient, conf *cmn.GovernorConf) bool {
+	limit := conf.MaxJoggers
+	if limit <= 0 {
+		return true
+	}
+	if g.total >= limit {
+		return false
+	}
+	if c.inuse < g.slots(c, conf, limit) {
+		return true
+	}
+	// borrow unless others are waiting within their shares
+	for o := range g.clients {
+		if o != c && o.waiting > 0 && o.inuse < g.slots(o, conf, limit) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *governor) slots(c *GovClient, conf *cmn.GovernorConf, limit int) int {
+	return max(1, int(float64(limit)*g.share(c, conf)))
+}
@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
+	ratomic "sync/atomic"
 	"time"
 
 	"github.com/NVIDIA/aistore/cmn"
@@ -34,22 +36,40 @@ const (
 	Load
 )
 
+// jogger priority relative to other (client, rebalance, etc.) disk IO (see JgroupOpts.Priority)
+const (
+	PriorityNormal = iota // throttle when disk utilization is above high watermark
+	PriorityLow           // background: back off more often and whenever the disk is not lightly used
+	PriorityHigh          // expedite: throttle only when the disk is maxed out
+)
+
+// when the num parallel calls is dynamically limited (see JgroupOpts.Width)
+const widthPoll = 100 * time.Millisecond
+
 type (
 	JgroupOpts struct {
 		onFinish              func()
 		VisitObj              func(lom *core.LOM, buf []byte) error
 		VisitCT               func(ct *core.CT, buf []byte) error
+		WaitPaused            func() // when specified, blocks while the (owning) xaction is paused
 		Slab                  *memsys.Slab
+		BufSize               func(mi *fs.Mountpath) int64 // when specified, overrides Slab on a per-mountpath basis (rounded up to memsys slab size)
+		BufBudget             *BufBudget                   // when specified, bounds the total size of buffers (may be shared)
+		Gov                   *GovClient                   // when specified, is subject to the resource governor (and gets unregistered when done)
 		Bck                   cmn.Bck
 		Buckets               cmn.Bcks
 		Prefix                string
 		CTs                   []string
-		DoLoad                LoadType // if specified, lom.Load(lock type)
-		Parallel              int      // num parallel calls
-		IncludeCopy           bool     // visit copies (aka replicas)
-		PerBucket             bool     // num joggers = (num mountpaths) x (num buckets)
-		SkipGloballyMisplaced bool     // skip globally misplaced
-		Throttle              bool     // true: pace itself depending on disk utilization
+		DoLoad                LoadType   // if specified, lom.Load(lock type)
+		Parallel              int        // num parallel calls
+		Width                 func() int // when specified, (dynamically) limits the num parallel calls to at most Parallel
+		IncludeCopy           bool       // visit copies (aka replicas)
+		PerBucket             bool       // num joggers = (num mountpaths) x (num buckets)
+		SkipGloballyMisplaced bool       // skip globally misplaced
+		Throttle              bool       // true: pace itself depending on disk utilization
+		Priority              int        // when throttling: PriorityNormal (default), et al.
+		InFlight              bool       // track names of the objects currently being visited (see Jgroup.InFlight)
+		Sorted                bool       // walk in lexical order, one directory at a time (see fs.WalkOpts.Sorted)
 	}
 
 	// Jgroup runs jogger per mountpath which walk the entire bucket and
@@ -58,6 +78,7 @@ type (
 	Jgroup struct {
 		wg          *errgroup.Group
 		joggers     map[string]*jogger
+		gov         *GovClient
 		finishedCh  cos.StopCh // when all joggers are done
 		finishedCnt atomic.Uint32
 	}
@@ -73,14 +94,20 @@ type (
 		objPrefix string // fully-qualified prefix, as in: join(bdir, opts.Prefix)
 		config    *cmn.Config
 		stopCh    cos.StopCh
+		slab      *memsys.Slab // large slab when bufSize exceeds memsys.MaxPageSlabSize
 		bufs      [][]byte
+		cur       []ratomic.Pointer[string] // in-flight object names: one slot per (parallel) visiting goroutine
+		bufSize   int64
 		num       int64
+		held      atomic.Int64 // acquired from opts.BufBudget
 	}
 
 	joggerSyncGroup struct {
 		sema   chan int // Positional number of a buffer to use by a goroutine.
 		group  *errgroup.Group
 		cancel context.CancelFunc
+		freed  chan struct{} // (Width) signals visiting goroutine done
+		busy   ratomic.Int32 // ditto, num visiting goroutines
 	}
 )
 
@@ -133,6 +160,7 @@ func NewJoggerGroup(opts *JgroupOpts, config *cmn.Config, smi *fs.Mountpath) *Jg
 	}
 
 	jg.joggers = joggers
+	jg.gov = opts.Gov
 	jg.finishedCh.Init()
 
 	return jg
@@ -140,6 +168,41 @@ func NewJoggerGroup(opts *JgroupOpts, config *cmn.Config, smi *fs.Mountpath) *Jg
 
 func (jg *Jgroup) Num() int { return len(jg.joggers) }
 
+// names of the objects currently being visited across all joggers (requires opts.InFlight);
+// bounded by `limit` (when positive)
+func (jg *Jgroup) InFlight(limit int) (names []string) {
+	for _, j := range jg.joggers {
+		for i := range j.cur {
+			if name := j.cur[i].Load(); name != nil {
+				if limit > 0 && len(names) >= limit {
+					goto ret
+				}
+				names = append(names, *name)
+			}
+		}
+	}
+ret:
+	sort.Strings(names)
+	return names
+}
+
+// total size of the buffers currently held by the joggers (see JgroupOpts.BufBudget)
+func (jg *Jgroup) BufUsed() (used int64) {
+	for _, j := range jg.joggers {
+		used += j.held.Load()
+	}
+	return used
+}
+
+// effective (per mountpath) buffer sizes
+func (jg *Jgroup) BufSizes() map[string]int64 {
+	sizes := make(map[string]int64, len(jg.joggers))
+	for _, j := range jg.joggers {
+		sizes[j.mi.Path] = j.bufSize
+	}
+	return sizes
+}
+
 func (jg *Jgroup) Run() {
 	for _, jogger := range jg.joggers {
 		jg.wg.Go(jogger.run)
@@ -150,7 +213,11 @@ func (jg *Jgroup) Stop() error {
 	for _, jogger := range jg.joggers {
 		jogger.abort()
 	}
-	return jg.wg.Wait()
+	err := jg.wg.Wait()
+	if jg.gov != nil {
+		jg.gov.Unreg()
+	}
+	return err
 }
 
 func (jg *Jgroup) ListenFinished() <-chan struct{} {
@@ -159,6 +226,9 @@ func (jg *Jgroup) ListenFinished() <-chan struct{} {
 
 func (jg *Jgroup) markFinished() {
 	if n := jg.finishedCnt.Inc(); n == uint32(len(jg.joggers)) {
+		if jg.gov != nil {
+			jg.gov.Unreg()
+		}
 		jg.finishedCh.Close()
 	}
 }
@@ -177,6 +247,9 @@ func newJogger(ctx context.Context, opts *JgroupOpts, mi *fs.Mountpath, config *
 			group:  group,
 			cancel: cancel,
 		}
+		if opts.Width != nil {
+			syncGroup.freed = make(chan struct{}, 1)
+		}
 		for i := range opts.Parallel {
 			syncGroup.sema <- i
 		}
@@ -188,14 +261,36 @@ func newJogger(ctx context.Context, opts *JgroupOpts, mi *fs.Mountpath, config *
 		config:    config,
 		syncGroup: syncGroup,
 	}
+	if opts.InFlight {
+		j.cur = make([]ratomic.Pointer[string], max(opts.Parallel, 1))
+	}
 	if opts.Prefix != "" {
 		j.bdir = mi.MakePathCT(&j.opts.Bck, fs.ObjectType) // this mountpath's bucket dir that contains objects
 		j.objPrefix = filepath.Join(j.bdir, opts.Prefix)
 	}
+	j.initBuf()
 	j.stopCh.Init()
 	return
 }
 
+func (j *jogger) initBuf() {
+	j.slab = j.opts.Slab
+	if j.opts.BufSize != nil {
+		if size := j.opts.BufSize(j.mi); size > 0 {
+			if size <= memsys.MaxPageSlabSize {
+				_, j.slab = memsys.PageMM().SelectMemAndSlab(size)
+			} else {
+				var err error
+				j.slab, err = memsys.PageMM().LargeSlab(min(size, memsys.MaxLargeSlabSize))
+				debug.AssertNoErr(err)
+			}
+		}
+	}
+	if j.slab != nil {
+		j.bufSize = j.slab.Size()
+	}
+}
+
 func (j *jogger) run() (err error) {
 	if err = j.mi.CheckFS(); err != nil {
 		nlog.Errorln(err)
@@ -203,13 +298,16 @@ func (j *jogger) run() (err error) {
 		goto ex
 	}
 
-	if j.opts.Slab != nil {
+	if j.bufSize > 0 {
+		if j.opts.BufBudget != nil && !j.acquireBufs() {
+			goto ex // aborted while waiting
+		}
 		if j.opts.Parallel <= 1 {
-			j.bufs = [][]byte{j.opts.Slab.Alloc()}
+			j.bufs = [][]byte{j.slab.Alloc()}
 		} else {
 			j.bufs = make([][]byte, j.opts.Parallel)
 			for i := range j.opts.Parallel {
-				j.bufs[i] = j.opts.Slab.Alloc()
+				j.bufs[i] = j.slab.Alloc()
 			}
 		}
 	}
@@ -227,15 +325,37 @@ func (j *jogger) run() (err error) {
 
 ex:
 	// cleanup
-	if j.opts.Slab != nil {
+	if j.slab != nil {
 		for _, buf := range j.bufs {
-			j.opts.Slab.Free(buf)
+			j.slab.Free(buf)
 		}
 	}
+	if held := j.held.Swap(0); held > 0 {
+		j.opts.BufBudget.release(held)
+	}
 	j.opts.onFinish()
 	return err
 }
 
+// wait for the budget to accommodate all (parallel) buffers of this jogger
+func (j *jogger) acquireBufs() bool {
+	ctx, cancel := context.WithCancel(j.ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-j.stopCh.Listen():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	held, err := j.opts.BufBudget.acquire(ctx, j.bufSize*int64(max(j.opts.Parallel, 1)))
+	if err != nil {
+		return false
+	}
+	j.held.Store(held)
+	return true
+}
+
 // run selected buckets, one at a time
 func (j *jogger) runSelected() error {
 	var errs cos.Errs
@@ -282,7 +402,7 @@ func (j *jogger) runBck(bck *cmn.Bck) (aborted bool, err error) {
 		Mi:       j.mi,
 		CTs:      j.opts.CTs,
 		Callback: j.jog,
-		Sorted:   false,
+		Sorted:   j.opts.Sorted,
 	}
 	opts.Bck.Copy(bck)
 
@@ -321,35 +441,55 @@ func (j *jogger) jog(fqn string, de fs.DirEntry) error {
 		return nil
 	}
 
+	if j.opts.WaitPaused != nil {
+		j.opts.WaitPaused()
+	}
 	if err := j.checkStopped(); err != nil {
 		return err
 	}
 
 	var bufPosition int
 	if j.syncGroup == nil {
-		if err := j.visitFQN(fqn, j.getBuf(0)); err != nil {
+		if !j.govAcquire() {
+			return j.checkStopped()
+		}
+		err := j.visitFQN(fqn, 0)
+		j.govRelease()
+		if err != nil {
 			return err
 		}
 	} else {
+		if j.opts.Width != nil {
+			if err := j.waitWidth(); err != nil {
+				return err
+			}
+		}
 		select {
 		case bufPosition = <-j.syncGroup.sema:
 			break
 		case <-j.ctx.Done():
 			return j.ctx.Err()
 		}
+		if !j.govAcquire() {
+			j.syncGroup.sema <- bufPosition
+			return j.checkStopped()
+		}
 
+		j.syncGroup.busy.Add(1)
 		j.syncGroup.group.Go(func() error {
 			defer func() {
+				j.govRelease()
 				// NOTE: There is no need to select j.ctx.Done() as put to this chanel is immediate.
 				j.syncGroup.sema <- bufPosition
+				j.syncGroup.done()
 			}()
-			return j.visitFQN(fqn, j.getBuf(bufPosition))
+			return j.visitFQN(fqn, bufPosition)
 		})
 	}
 
 	if j.opts.Throttle {
 		j.num++
-		if fs.IsThrottle(j.num) {
+		if j.isThrottle() {
 			j.throttle()
 		} else {
 			runtime.Gosched()
@@ -358,7 +498,22 @@ func (j *jogger) jog(fqn string, de fs.DirEntry) error {
 	return nil
 }
 
-func (j *jogger) visitFQN(fqn string, buf []byte) error {
+// (Width) wait until the number of visiting goroutines drops below the current width;
+// poll as well - the width may grow in the meantime
+func (j *jogger) waitWidth() error {
+	for int(j.syncGroup.busy.Load()) >= max(j.opts.Width(), 1) {
+		select {
+		case <-j.syncGroup.freed:
+		case <-time.After(widthPoll):
+		case <-j.ctx.Done():
+			return j.ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (j *jogger) visitFQN(fqn string, position int) error {
+	buf := j.getBuf(position)
 	ct, err := core.NewCTFromFQN(fqn, core.T.Bowner())
 	if err != nil {
 		return err
@@ -379,7 +534,17 @@ func (j *jogger) visitFQN(fqn string, buf []byte) error {
 	case fs.ObjectType:
 		lom := core.AllocLOM("")
 		lom.InitCT(ct)
+		if j.cur != nil {
+			name := lom.ObjName
+			j.cur[position].Store(&name)
+		}
 		err := j.visitObj(lom, buf)
+		if j.cur != nil {
+			j.cur[position].Store(nil)
+		}
+		if j.opts.Gov != nil && err == nil {
+			j.opts.Gov.Charge(lom.Lsize(true))
+		}
 		// NOTE: j.opts.visitObj() callback implementations must either finish
 		// synchronously or pass lom.LIF to another goroutine
 		core.FreeLOM(lom)
@@ -422,6 +587,19 @@ func (j *jogger) getBuf(position int) []byte {
 	return j.bufs[position]
 }
 
+func (j *jogger) govAcquire() bool {
+	if j.opts.Gov == nil {
+		return true
+	}
+	return j.opts.Gov.Acquire(func() bool { return j.checkStopped() != nil })
+}
+
+func (j *jogger) govRelease() {
+	if j.opts.Gov != nil {
+		j.opts.Gov.Release()
+	}
+}
+
 func (j *jogger) checkStopped() error {
 	select {
 	case <-j.ctx.Done(): // Some other worker has exited with error and canceled context.
@@ -433,6 +611,16 @@ func (j *jogger) checkStopped() error {
 	}
 }
 
+func (sg *joggerSyncGroup) done() {
+	sg.busy.Add(-1)
+	if sg.freed != nil {
+		select {
+		case sg.freed <- struct{}{}:
+		default:
+		}
+	}
+}
+
 func (sg *joggerSyncGroup) waitForAsyncTasks() error {
 	return sg.group.Wait()
 }
@@ -442,10 +630,34 @@ func (sg *joggerSyncGroup) abortAsyncTasks() error {
 	return sg.waitForAsyncTasks()
 }
 
+func (j *jogger) isThrottle() bool {
+	if j.opts.Priority == PriorityLow {
+		return fs.IsMiniThrottle(j.num)
+	}
+	return fs.IsThrottle(j.num)
+}
+
 func (j *jogger) throttle() {
-	curUtil := fs.GetMpathUtil(j.mi.Path)
-	if curUtil >= j.config.Disk.DiskUtilHighWM {
-		time.Sleep(fs.Throttle1ms)
+	var (
+		curUtil = fs.GetMpathUtil(j.mi.Path)
+		disk    = &j.config.Disk
+	)
+	switch j.opts.Priority {
+	case PriorityLow:
+		switch {
+		case curUtil >= disk.DiskUtilHighWM:
+			time.Sleep(fs.Throttle10ms)
+		case curUtil >= disk.DiskUtilLowWM:
+			time.Sleep(fs.Throttle1ms)
+		}
+	case PriorityHigh:
+		if curUtil >= disk.DiskUtilMaxWM {
+			time.Sleep(fs.Throttle1ms)
+		}
+	default:
+		if curUtil >= disk.DiskUtilHighWM {
+			time.Sleep(fs.Throttle1ms)
+		}
 	}
 }
 
@@ -310,3 +310,384 @@ func TestJoggerGroupMultiContentTypes(t *testing.T) {
 	err := jg.Stop()
 	tassert.CheckFatal(t, err)
 }
+
+func TestJoggerGroupInFlight(t *testing.T) {
+	const objectsCnt = 10
+	var (
+		desc = tools.ObjectsDesc{
+			CTs: []tools.ContentTypeDesc{
+				{Type: fs.ObjectType, ContentCnt: objectsCnt},
+			},
+			MountpathsCnt: 1, // single jogger, one object at a time
+			ObjectSize:    cos.KiB,
+		}
+		out     = tools.PrepareObjects(t, desc)
+		entered = make(chan string, 1)
+		release = make(chan struct{})
+	)
+	defer os.RemoveAll(out.Dir)
+
+	opts := &mpather.JgroupOpts{
+		Bck:      out.Bck,
+		CTs:      []string{fs.ObjectType},
+		InFlight: true,
+		VisitObj: func(lom *core.LOM, _ []byte) error {
+			entered <- lom.ObjName
+			<-release
+			return nil
+		},
+	}
+	jg := mpather.NewJoggerGroup(opts, cmn.GCO.Get(), nil)
+	tassert.Fatalf(t, len(jg.InFlight(0)) == 0, "expected no in-flight objects prior to running, got %v", jg.InFlight(0))
+	jg.Run()
+
+	visited := make(map[string]struct{}, objectsCnt)
+	for range objectsCnt {
+		name := <-entered
+		_, ok := visited[name]
+		tassert.Fatalf(t, !ok, "object %q visited twice", name)
+		visited[name] = struct{}{}
+
+		// the one being visited (and only it) must be in-flight
+		names := jg.InFlight(0)
+		tassert.Fatalf(t, len(names) == 1 && names[0] == name, "expected in-flight [%s], got %v", name, names)
+		release <- struct{}{}
+	}
+	<-jg.ListenFinished()
+	tassert.CheckFatal(t, jg.Stop())
+
+	names := jg.InFlight(0)
+	tassert.Errorf(t, len(names) == 0, "expected no in-flight objects upon completion, got %v", names)
+}
+
+func TestJoggerGroupInFlightBounded(t *testing.T) {
+	const (
+		parallel  = 4
+		mpathsCnt = 3
+		limit     = 2
+	)
+	var (
+		desc = tools.ObjectsDesc{
+			CTs: []tools.ContentTypeDesc{
+				{Type: fs.ObjectType, ContentCnt: 100},
+			},
+			MountpathsCnt: mpathsCnt,
+			ObjectSize:    cos.KiB,
+		}
+		out     = tools.PrepareObjects(t, desc)
+		entered = atomic.NewInt32(0)
+		release = make(chan struct{})
+	)
+	defer os.RemoveAll(out.Dir)
+
+	opts := &mpather.JgroupOpts{
+		Bck:      out.Bck,
+		CTs:      []string{fs.ObjectType},
+		Parallel: parallel,
+		InFlight: true,
+		VisitObj: func(*core.LOM, []byte) error {
+			entered.Inc()
+			<-release
+			return nil
+		},
+	}
+	jg := mpather.NewJoggerGroup(opts, cmn.GCO.Get(), nil)
+	jg.Run()
+
+	for entered.Load() <= limit {
+		time.Sleep(time.Millisecond)
+	}
+	names := jg.InFlight(limit)
+	tassert.Errorf(t, len(names) == limit, "expected exactly %d in-flight names, got %v", limit, names)
+	names = jg.InFlight(0)
+	tassert.Errorf(t, len(names) > limit && len(names) <= parallel*mpathsCnt,
+		"expected (%d, %d] in-flight names, got %v", limit, parallel*mpathsCnt, names)
+
+	close(release)
+	<-jg.ListenFinished()
+	tassert.CheckFatal(t, jg.Stop())
+	names = jg.InFlight(0)
+	tassert.Errorf(t, len(names) == 0, "expected no in-flight objects upon completion, got %v", names)
+}
+
+// parallel calls dynamically limited by (growing) width
+func TestJoggerGroupWidth(t *testing.T) {
+	const (
+		parallel   = 8
+		objectsCnt = 200
+	)
+	var (
+		desc = tools.ObjectsDesc{
+			CTs: []tools.ContentTypeDesc{
+				{Type: fs.ObjectType, ContentCnt: objectsCnt},
+			},
+			MountpathsCnt: 1,
+			ObjectSize:    cos.KiB,
+		}
+		out     = tools.PrepareObjects(t, desc)
+		width   = atomic.NewInt32(2)
+		busy    = atomic.NewInt32(0)
+		visited = atomic.NewInt32(0)
+		maxBusy [2]atomic.Int32 // before and after growing the width
+	)
+	defer os.RemoveAll(out.Dir)
+
+	opts := &mpather.JgroupOpts{
+		Bck:      out.Bck,
+		CTs:      []string{fs.ObjectType},
+		Parallel: parallel,
+		Width:    func() int { return int(width.Load()) },
+		VisitObj: func(*core.LOM, []byte) error {
+			var (
+				phase = int(width.Load()-2) / 2
+				n     = busy.Inc()
+			)
+			if n > maxBusy[phase].Load() {
+				maxBusy[phase].Store(n)
+			}
+			time.Sleep(time.Millisecond)
+			busy.Dec()
+			if visited.Inc() == objectsCnt/2 {
+				width.Store(4)
+			}
+			return nil
+		},
+	}
+	jg := mpather.NewJoggerGroup(opts, cmn.GCO.Get(), nil)
+	jg.Run()
+	<-jg.ListenFinished()
+	tassert.CheckFatal(t, jg.Stop())
+
+	tassert.Errorf(t, visited.Load() == objectsCnt, "expected %d objects visited, got %d", objectsCnt, visited.Load())
+	tassert.Errorf(t, maxBusy[0].Load() <= 2, "expected at most 2 parallel calls, got %d", maxBusy[0].Load())
+	tassert.Errorf(t, maxBusy[1].Load() > 2 && maxBusy[1].Load() <= 4,
+		"expected (2, 4] parallel calls upon growing the width, got %d", maxBusy[1].Load())
+}
+
+func TestJoggerGroupPriority(t *testing.T) {
+	const (
+		numObjs = 256
+		util    = 90 // simulated contention: above high watermark but below max
+	)
+	var (
+		desc = tools.ObjectsDesc{
+			CTs: []tools.ContentTypeDesc{
+				{Type: fs.ObjectType, ContentCnt: numObjs},
+			},
+			MountpathsCnt: 1,
+			ObjectSize:    cos.KiB,
+		}
+		out    = tools.PrepareObjects(t, desc)
+		config = *cmn.GCO.Get()
+	)
+	defer os.RemoveAll(out.Dir)
+
+	config.Disk.DiskUtilLowWM, config.Disk.DiskUtilHighWM, config.Disk.DiskUtilMaxWM = 20, 80, 95
+	for mpath := range fs.GetAvail() {
+		fs.GetAllMpathUtils().Set(mpath, util)
+	}
+
+	run := func(priority int) time.Duration {
+		opts := &mpather.JgroupOpts{
+			Bck:      out.Bck,
+			CTs:      []string{fs.ObjectType},
+			VisitObj: func(*core.LOM, []byte) error { return nil },
+			Throttle: true,
+			Priority: priority,
+		}
+		started := time.Now()
+		jg := mpather.NewJoggerGroup(opts, &config, nil)
+		jg.Run()
+		<-jg.ListenFinished()
+		tassert.CheckFatal(t, jg.Stop())
+		return time.Since(started)
+	}
+
+	var (
+		low    = run(mpather.PriorityLow)
+		normal = run(mpather.PriorityNormal)
+		high   = run(mpather.PriorityHigh)
+
+		// low priority: sleeping 10ms every 16 objects
+		minLow = time.Duration(numObjs/16) * fs.Throttle10ms
+	)
+	tassert.Errorf(t, low >= minLow, "low priority: expected to back off for at least %v, took %v", minLow, low)
+	tassert.Errorf(t, low > normal && low > high, "low priority (%v) expected to take longer than normal (%v) and high (%v)",
+		low, normal, high)
+}
+
+func TestJoggerGroupBufSize(t *testing.T) {
+	const (
+		largeSize = 2 * cos.MiB // exceeds memsys.MaxPageSlabSize
+		smallSize = 64 * cos.KiB
+	)
+	var (
+		desc = tools.ObjectsDesc{
+			CTs: []tools.ContentTypeDesc{
+				{Type: fs.ObjectType, ContentCnt: 100},
+			},
+			MountpathsCnt: 4,
+			ObjectSize:    cos.KiB,
+		}
+		out     = tools.PrepareObjects(t, desc)
+		counter = atomic.NewInt32(0)
+		slab, _ = memsys.PageMM().GetSlab(memsys.MaxPageSlabSize)
+	)
+	defer os.RemoveAll(out.Dir)
+
+	// simulate "spinning" mountpaths
+	var (
+		avail = fs.GetAvail()
+		hdd   = make(map[string]bool, len(avail))
+		i     int
+	)
+	for mpath := range avail {
+		hdd[mpath] = i%2 == 0
+		i++
+	}
+	bufSize := func(mi *fs.Mountpath) int64 {
+		if hdd[mi.Path] {
+			return largeSize
+		}
+		return smallSize
+	}
+	opts := &mpather.JgroupOpts{
+		Bck:     out.Bck,
+		CTs:     []string{fs.ObjectType},
+		Slab:    slab,
+		BufSize: bufSize,
+		VisitObj: func(lom *core.LOM, buf []byte) error {
+			expected := bufSize(lom.Mountpath())
+			tassert.Errorf(t, int64(len(buf)) == expected, "%s: expected buffer size %d, got %d", lom.Mountpath(), expected, len(buf))
+			counter.Inc()
+			return nil
+		},
+	}
+	jg := mpather.NewJoggerGroup(opts, cmn.GCO.Get(), nil)
+	for mpath, size := range jg.BufSizes() {
+		tassert.Errorf(t, size == bufSize(&fs.Mountpath{Path: mpath}), "%s: unexpected effective buffer size %d", mpath, size)
+	}
+
+	jg.Run()
+	<-jg.ListenFinished()
+
+	tassert.Errorf(
+		t, int(counter.Load()) == len(out.FQNs[fs.ObjectType]),
+		"invalid number of objects visited (%d vs %d)", counter.Load(), len(out.FQNs[fs.ObjectType]),
+	)
+	err := jg.Stop()
+	tassert.CheckFatal(t, err)
+}
+
+func TestJoggerGroupBufBudget(t *testing.T) {
+	const (
+		bufSize   = 64 * cos.KiB
+		mpathsCnt = 4
+		numBufs   = 2 // budget: joggers that can run at the same time
+	)
+	var (
+		desc = tools.ObjectsDesc{
+			CTs: []tools.ContentTypeDesc{
+				{Type: fs.ObjectType, ContentCnt: 40},
+			},
+			MountpathsCnt: mpathsCnt,
+			ObjectSize:    cos.KiB,
+		}
+		out     = tools.PrepareObjects(t, desc)
+		budget  = mpather.NewBufBudget(numBufs * bufSize)
+		counter = atomic.NewInt32(0)
+		active  = atomic.NewInt32(0)
+		maxAct  = atomic.NewInt32(0)
+	)
+	defer os.RemoveAll(out.Dir)
+
+	opts := &mpather.JgroupOpts{
+		Bck:       out.Bck,
+		CTs:       []string{fs.ObjectType},
+		BufSize:   func(*fs.Mountpath) int64 { return bufSize },
+		BufBudget: budget,
+		VisitObj: func(*core.LOM, []byte) error {
+			n := active.Inc()
+			for {
+				m := maxAct.Load()
+				if n <= m || maxAct.CAS(m, n) {
+					break
+				}
+			}
+			used := budget.Used()
+			tassert.Errorf(t, used > 0 && used <= budget.Size(), "budget used %d, size %d", used, budget.Size())
+			time.Sleep(time.Millisecond)
+			counter.Inc()
+			active.Dec()
+			return nil
+		},
+	}
+	jg := mpather.NewJoggerGroup(opts, cmn.GCO.Get(), nil)
+	jg.Run()
+	<-jg.ListenFinished()
+	tassert.CheckFatal(t, jg.Stop())
+
+	tassert.Errorf(t, int(counter.Load()) == len(out.FQNs[fs.ObjectType]), "visited %d, expected %d",
+		counter.Load(), len(out.FQNs[fs.ObjectType]))
+	tassert.Errorf(t, maxAct.Load() <= numBufs, "expected at most %d joggers at a time, got %d", numBufs, maxAct.Load())
+	tassert.Errorf(t, budget.Used() == 0 && jg.BufUsed() == 0, "expected all buffers released, got (%d, %d)",
+		budget.Used(), jg.BufUsed())
+}
+
+func TestJoggerGroupGovernor(t *testing.T) {
+	const (
+		mpathsCnt  = 4
+		maxJoggers = 2
+	)
+	var (
+		desc = tools.ObjectsDesc{
+			CTs: []tools.ContentTypeDesc{
+				{Type: fs.ObjectType, ContentCnt: 40},
+			},
+			MountpathsCnt: mpathsCnt,
+			ObjectSize:    cos.KiB,
+		}
+		out     = tools.PrepareObjects(t, desc)
+		counter = atomic.NewInt32(0)
+		active  = atomic.NewInt32(0)
+		maxAct  = atomic.NewInt32(0)
+	)
+	defer os.RemoveAll(out.Dir)
+
+	oconfig := cmn.GCO.Get()
+	config := cmn.GCO.BeginUpdate()
+	config.Governor.MaxJoggers = maxJoggers
+	cmn.GCO.CommitUpdate(config)
+	defer func() {
+		cmn.GCO.BeginUpdate()
+		cmn.GCO.CommitUpdate(oconfig)
+	}()
+
+	opts := &mpather.JgroupOpts{
+		Bck:      out.Bck,
+		CTs:      []string{fs.ObjectType},
+		Parallel: 2,
+		Gov:      mpather.RegGov("test"),
+		VisitObj: func(*core.LOM, []byte) error {
+			n := active.Inc()
+			for {
+				m := maxAct.Load()
+				if n <= m || maxAct.CAS(m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			counter.Inc()
+			active.Dec()
+			return nil
+		},
+	}
+	jg := mpather.NewJoggerGroup(opts, cmn.GCO.Get(), nil)
+	jg.Run()
+	<-jg.ListenFinished()
+	tassert.CheckFatal(t, jg.Stop())
+
+	tassert.Errorf(t, int(counter.Load()) == len(out.FQNs[fs.ObjectType]), "visited %d, expected %d",
+		counter.Load(), len(out.FQNs[fs.ObjectType]))
+	tassert.Errorf(t, maxAct.Load() <= maxJoggers, "expected at most %d joggers at a time, got %d", maxJoggers, maxAct.Load())
+}
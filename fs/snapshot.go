@@ -0,0 +1,56 @@
+// Package fs provides mountpath and FQN abstractions and methods to resolve/map stored content
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"path/filepath"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Bucket snapshots (see apc.ActCreateSnapshot)
+// - snapshotted objects are hard links to the respective (main) replicas, i.e. copy-on-write:
+//   overwriting or deleting an object does not affect its snapshotted version;
+// - named <snapshot-name>/<object-name> and stored on the same mountpath as the main replica;
+// - per-mountpath snapshot metadata: <snapshot-name>.snap (see SnapMetaFQN);
+// - never moved (rebalance, resilver) or evicted, and get removed only when destroyed (or with the bucket).
+
+const (
+	SnapType = "sn"
+
+	snapMetaExt = ".snap"
+)
+
+type SnapContentResolver struct{}
+
+func (*SnapContentResolver) PermToMove() bool    { return false }
+func (*SnapContentResolver) PermToEvict() bool   { return false }
+func (*SnapContentResolver) PermToProcess() bool { return false }
+
+// prefix: snapshot name
+func (*SnapContentResolver) GenUniqueFQN(base, prefix string) string {
+	return prefix + "/" + base
+}
+
+func (*SnapContentResolver) ParseUniqueFQN(base string) (orig string, old, ok bool) {
+	return base, false, true
+}
+
+func (mi *Mountpath) SnapDir(bck *cmn.Bck, name string) string {
+	return mi.MakePathFQN(bck, SnapType, name)
+}
+
+func (mi *Mountpath) SnapMetaFQN(bck *cmn.Bck, name string) string {
+	return mi.MakePathFQN(bck, SnapType, name+snapMetaExt)
+}
+
+// given snapshot metadata file, returns the snapshot name
+func ParseSnapMeta(fqn string) (name string, ok bool) {
+	base := filepath.Base(fqn)
+	if filepath.Ext(base) != snapMetaExt || len(base) == len(snapMetaExt) {
+		return "", false
+	}
+	return base[:len(base)-len(snapMetaExt)], true
+}
@@ -0,0 +1,123 @@
+// Package sse provides server-side encryption at rest: chunked AES-GCM content
+// encryption with data keys managed by KMS (see cmn/kms).
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package sse
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// Client-supplied keys (compare with Amazon S3 SSE-C):
+// - the client provides a 256-bit key with each PUT and GET request
+//   (see apc.HdrSSECustomerKey and its S3 counterpart);
+// - the object's data key is derived from the client's key and a random per-object salt
+//   (HKDF-SHA256), with no KMS involved;
+// - the key itself is never stored: object's metadata contains only the salt and
+//   HMAC-SHA256(salt, key) to validate the key provided with subsequent requests;
+// - the key must be sent over HTTPS only (see ErrCustomerKeyInsecure).
+
+const CustomerAlgo = "AES256"
+
+const (
+	customerPrefix = "sse-c:"
+	saltLen        = 16
+	hkdfCustInfo   = "ais-sse-c"
+)
+
+var (
+	ErrCustomerKeyRequired      = errors.New("object is encrypted with a client-supplied key: the key is required")
+	ErrCustomerKeyMismatch      = errors.New("client-supplied encryption key does not match the one the object was encrypted with")
+	ErrCustomerKeyNotApplicable = errors.New("object is not encrypted with a client-supplied key")
+	ErrCustomerKeyInsecure      = errors.New("client-supplied encryption key requires secure (HTTPS) connection")
+)
+
+// whether a given data key reference (see DataKey.Ref) belongs to a client-supplied key
+func IsCustomer(ref string) bool { return strings.HasPrefix(ref, customerPrefix) }
+
+// parse and validate client-supplied key headers: algorithm, base64 key, and base64 MD5 of the key;
+// returns (nil, nil) when none is present
+func ParseCustomerKey(algo, b64key, b64md5 string) ([]byte, error) {
+	if algo == "" && b64key == "" && b64md5 == "" {
+		return nil, nil
+	}
+	if algo != CustomerAlgo {
+		return nil, fmt.Errorf("invalid client-supplied key algorithm %q (expecting %q)", algo, CustomerAlgo)
+	}
+	key, err := base64.StdEncoding.DecodeString(b64key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client-supplied key: %v", err)
+	}
+	if len(key) != keyLen {
+		return nil, fmt.Errorf("invalid client-supplied key length %d (expecting %d)", len(key), keyLen)
+	}
+	if b64md5 != "" {
+		sum := md5.Sum(key)
+		if b64md5 != base64.StdEncoding.EncodeToString(sum[:]) {
+			return nil, errors.New("client-supplied key: MD5 mismatch")
+		}
+	}
+	return key, nil
+}
+
+// new data key to encrypt an object with a client-supplied key (and a new salt)
+func NewCustomerKey(key []byte) (*DataKey, error) {
+	var salt [saltLen]byte
+	if _, err := rand.Read(salt[:]); err != nil {
+		return nil, err
+	}
+	return _customer(key, salt[:])
+}
+
+// validate client-supplied key against the reference stored with the object
+func CustomerKey(ref string, key []byte) (*DataKey, error) {
+	if !IsCustomer(ref) {
+		return nil, ErrCustomerKeyNotApplicable
+	}
+	b64salt, b64mac, ok := strings.Cut(ref[len(customerPrefix):], ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid client-supplied key reference %q", ref)
+	}
+	salt, err := base64.StdEncoding.DecodeString(b64salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client-supplied key reference: %v", err)
+	}
+	mac, err := base64.StdEncoding.DecodeString(b64mac)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client-supplied key reference: %v", err)
+	}
+	if !hmac.Equal(mac, _hmac(salt, key)) {
+		return nil, ErrCustomerKeyMismatch
+	}
+	return _customer(key, salt)
+}
+
+func _customer(key, salt []byte) (*DataKey, error) {
+	if len(key) != keyLen {
+		return nil, fmt.Errorf("invalid client-supplied key length %d (expecting %d)", len(key), keyLen)
+	}
+	dkey := make([]byte, keyLen)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, key, salt, []byte(hkdfCustInfo)), dkey); err != nil {
+		return nil, err
+	}
+	ref := customerPrefix + base64.StdEncoding.EncodeToString(salt) + ":" +
+		base64.StdEncoding.EncodeToString(_hmac(salt, key))
+	return &DataKey{key: dkey, Ref: ref}, nil
+}
+
+func _hmac(salt, key []byte) []byte {
+	h := hmac.New(sha256.New, salt)
+	h.Write(key)
+	return h.Sum(nil)
+}
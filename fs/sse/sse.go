@@ -0,0 +1,135 @@
+// Package sse provides server-side encryption at rest: chunked AES-GCM content
+// encryption with data keys managed by KMS (see cmn/kms).
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package sse
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/NVIDIA/aistore/api/env"
+	"github.com/NVIDIA/aistore/cmn/kms"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+)
+
+// Envelope encryption:
+// - each target generates a data key (DEK) per encrypted bucket, via KMS that wraps it
+//   with the bucket's KMS key (see cmn.EncryptionConf.KeyID);
+// - objects are encrypted with the bucket's current DEK, and each object's metadata
+//   references the DEK in its wrapped form (see Ref), so that it can be unwrapped
+//   (and cached) when reading - on any target;
+// - rotation (apc.ActRotateKey) drops the current DEK: new objects are then
+//   encrypted with a new one, while existing objects get re-encrypted by the xaction.
+
+const keyLen = kms.KeyLen
+
+// unwrapped data key (each object is then encrypted with its own derived key - see objAEAD)
+type DataKey struct {
+	key   []byte
+	Ref   string // "<KMS key ID>:<base64(wrapped data key)>"
+	KeyID string
+}
+
+var (
+	gkms kms.KMS
+
+	curr   = make(map[uint64]*DataKey, 4) // bucket ID => current data key
+	currMu sync.Mutex
+
+	dkeys sync.Map // ref => *DataKey
+)
+
+var ErrNoKMS = errors.New("server-side encryption: key management service is not configured (see " + env.KMS.Provider + ")")
+
+// Init is called once upon target startup; without configured KMS encrypted buckets
+// can be neither written nor read
+func Init() (err error) {
+	if gkms, err = kms.New(); err == nil && gkms != nil {
+		nlog.Infoln("server-side encryption: using", gkms.Name(), "KMS")
+	}
+	return err
+}
+
+// current data key to encrypt bucket's objects (generated upon first use)
+func Current(bid uint64, keyID string) (*DataKey, error) {
+	if gkms == nil {
+		return nil, ErrNoKMS
+	}
+	currMu.Lock()
+	defer currMu.Unlock()
+	if dk, ok := curr[bid]; ok && dk.KeyID == keyID {
+		return dk, nil
+	}
+	plain, wrapped, err := gkms.GenerateDataKey(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("%s KMS: failed to generate data key (key ID %q): %w", gkms.Name(), keyID, err)
+	}
+	dk, err := newDataKey(keyID, plain, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	curr[bid] = dk
+	dkeys.Store(dk.Ref, dk)
+	return dk, nil
+}
+
+// data key that encrypted a given object
+func Lookup(ref string) (*DataKey, error) {
+	if v, ok := dkeys.Load(ref); ok {
+		return v.(*DataKey), nil
+	}
+	if IsCustomer(ref) {
+		return nil, ErrCustomerKeyRequired
+	}
+	if gkms == nil {
+		return nil, ErrNoKMS
+	}
+	i := strings.LastIndexByte(ref, ':')
+	if i <= 0 {
+		return nil, fmt.Errorf("invalid data key reference %q", ref)
+	}
+	keyID := ref[:i]
+	wrapped, err := base64.StdEncoding.DecodeString(ref[i+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key reference (key ID %q): %v", keyID, err)
+	}
+	plain, err := gkms.Decrypt(keyID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("%s KMS: failed to unwrap data key (key ID %q): %w", gkms.Name(), keyID, err)
+	}
+	dk, err := newDataKey(keyID, plain, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	dkeys.Store(ref, dk)
+	return dk, nil
+}
+
+// drop bucket's current data key - the next Current() call generates a new one
+func Rotate(bid uint64) {
+	currMu.Lock()
+	delete(curr, bid)
+	currMu.Unlock()
+}
+
+func newDataKey(keyID string, plain, wrapped []byte) (*DataKey, error) {
+	if len(plain) != keyLen {
+		return nil, fmt.Errorf("invalid data key length %d (expecting %d)", len(plain), keyLen)
+	}
+	return &DataKey{key: plain, Ref: keyID + ":" + base64.StdEncoding.EncodeToString(wrapped), KeyID: keyID}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
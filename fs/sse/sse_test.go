@@ -0,0 +1,285 @@
+// Package sse_test: unit tests
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package sse_test
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NVIDIA/aistore/api/env"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/kms"
+	"github.com/NVIDIA/aistore/fs/sse"
+	"github.com/NVIDIA/aistore/tools/tassert"
+)
+
+const (
+	testKeyID   = "test-key"
+	testBID     = 1
+	testObjName = "dir/obj"
+	testHdrLen  = 48
+)
+
+func initKeyfile(t *testing.T) {
+	dir := t.TempDir()
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	tassert.CheckFatal(t, err)
+	err = os.WriteFile(filepath.Join(dir, testKeyID), []byte(base64.StdEncoding.EncodeToString(key)+"\n"), cos.PermRWR)
+	tassert.CheckFatal(t, err)
+
+	t.Setenv(env.KMS.Provider, kms.Keyfile)
+	t.Setenv(env.KMS.KeyDir, dir)
+	tassert.CheckFatal(t, sse.Init())
+}
+
+func encrypt(t *testing.T, fqn string, data []byte) *sse.DataKey {
+	dk, err := sse.Current(testBID, testKeyID)
+	tassert.CheckFatal(t, err)
+	fh, err := os.Create(fqn)
+	tassert.CheckFatal(t, err)
+	w, err := sse.NewWriter(fh, dk, testObjName)
+	tassert.CheckFatal(t, err)
+	_, err = w.Write(data)
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, w.Close())
+	return dk
+}
+
+func decrypt(fqn, ref string, objName ...string) (*sse.Reader, error) {
+	dk, err := sse.Lookup(ref)
+	if err != nil {
+		return nil, err
+	}
+	fh, err := os.Open(fqn)
+	if err != nil {
+		return nil, err
+	}
+	oname := testObjName
+	if len(objName) > 0 {
+		oname = objName[0]
+	}
+	r, err := sse.NewReader(fh, fqn, oname, dk)
+	if err != nil {
+		fh.Close()
+	}
+	return r, err
+}
+
+func TestRoundTrip(t *testing.T) {
+	initKeyfile(t)
+	dir := t.TempDir()
+	for _, size := range []int{0, 1, 64*cos.KiB - 1, 64 * cos.KiB, 64*cos.KiB + 1, 3*64*cos.KiB + 5} {
+		data := make([]byte, size)
+		_, _ = rand.Read(data)
+		fqn := filepath.Join(dir, "obj")
+		dk := encrypt(t, fqn, data)
+
+		finfo, err := os.Stat(fqn)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, finfo.Size() == sse.FileSize(int64(size)), "size %d: file size %d vs %d",
+			size, finfo.Size(), sse.FileSize(int64(size)))
+		tassert.Errorf(t, sse.PlainSize(finfo.Size()) == int64(size), "size %d: plain size %d",
+			size, sse.PlainSize(finfo.Size()))
+
+		r, err := decrypt(fqn, dk.Ref)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, r.Size() == int64(size), "size %d vs %d", r.Size(), size)
+		b, err := io.ReadAll(r)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, bytes.Equal(b, data), "size %d: content mismatch", size)
+
+		// random reads across chunk boundaries
+		if size > 2 {
+			off := int64(size / 3)
+			p := make([]byte, size/2)
+			n, err := r.ReadAt(p, off)
+			tassert.CheckFatal(t, err)
+			tassert.Errorf(t, bytes.Equal(p[:n], data[off:off+int64(n)]), "size %d: range mismatch", size)
+		}
+		r.Close()
+	}
+}
+
+func TestTamperAndTruncate(t *testing.T) {
+	initKeyfile(t)
+	var (
+		fqn  = filepath.Join(t.TempDir(), "obj")
+		data = make([]byte, 2*64*cos.KiB+100)
+	)
+	_, _ = rand.Read(data)
+	dk := encrypt(t, fqn, data)
+
+	// flip a byte in the first chunk
+	b, err := os.ReadFile(fqn)
+	tassert.CheckFatal(t, err)
+	b[100] ^= 0xff
+	tassert.CheckFatal(t, os.WriteFile(fqn, b, cos.PermRWR))
+	r, err := decrypt(fqn, dk.Ref)
+	tassert.CheckFatal(t, err)
+	_, err = io.ReadAll(r)
+	tassert.Fatalf(t, err != nil, "expected error reading tampered content")
+	r.Close()
+
+	// tamper with the header: reserved byte, object salt
+	b[100] ^= 0xff
+	for _, i := range []int{8, testHdrLen - 1} {
+		b[i] ^= 0xff
+		tassert.CheckFatal(t, os.WriteFile(fqn, b, cos.PermRWR))
+		r, err = decrypt(fqn, dk.Ref)
+		tassert.CheckFatal(t, err)
+		_, err = io.ReadAll(r)
+		tassert.Fatalf(t, err != nil, "expected error reading content with tampered header (byte %d)", i)
+		r.Close()
+		b[i] ^= 0xff
+	}
+
+	// intact content, different object name
+	tassert.CheckFatal(t, os.WriteFile(fqn, b, cos.PermRWR))
+	r, err = decrypt(fqn, dk.Ref, testObjName+"x")
+	tassert.CheckFatal(t, err)
+	_, err = io.ReadAll(r)
+	tassert.Fatalf(t, err != nil, "expected error reading content under a different name")
+	r.Close()
+
+	// drop the last chunk (the remaining ones are intact)
+	tassert.CheckFatal(t, os.WriteFile(fqn, b[:testHdrLen+2*(64*cos.KiB+16)], cos.PermRWR))
+	r, err = decrypt(fqn, dk.Ref)
+	tassert.CheckFatal(t, err)
+	_, err = io.ReadAll(r)
+	tassert.Fatalf(t, err != nil, "expected error reading truncated content")
+	r.Close()
+}
+
+// same data key, same content: different (per-object) keys
+func TestObjectKeys(t *testing.T) {
+	initKeyfile(t)
+	var (
+		dir  = t.TempDir()
+		data = make([]byte, 1000)
+		fqns = [2]string{filepath.Join(dir, "obj1"), filepath.Join(dir, "obj2")}
+		b    [2][]byte
+	)
+	for i, fqn := range fqns {
+		encrypt(t, fqn, data)
+		var err error
+		b[i], err = os.ReadFile(fqn)
+		tassert.CheckFatal(t, err)
+	}
+	tassert.Errorf(t, !bytes.Equal(b[0][testHdrLen-32:testHdrLen], b[1][testHdrLen-32:testHdrLen]), "expecting different object salts")
+	tassert.Errorf(t, !bytes.Equal(b[0][testHdrLen:], b[1][testHdrLen:]), "expecting different ciphertexts")
+}
+
+func TestRotateLookup(t *testing.T) {
+	initKeyfile(t)
+	dk1, err := sse.Current(testBID, testKeyID)
+	tassert.CheckFatal(t, err)
+	dk2, err := sse.Current(testBID, testKeyID)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, dk1 == dk2, "expecting the same current data key")
+
+	sse.Rotate(testBID)
+	dk3, err := sse.Current(testBID, testKeyID)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, dk3.Ref != dk1.Ref, "expecting new data key upon rotation")
+
+	// previous key remains available
+	dk, err := sse.Lookup(dk1.Ref)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, dk.Ref == dk1.Ref, "lookup: %q vs %q", dk.Ref, dk1.Ref)
+
+	// unknown KMS key, invalid references
+	_, err = sse.Current(testBID+1, "no-such-key")
+	tassert.Errorf(t, err != nil, "expected error (missing KMS key)")
+	for _, ref := range []string{"", "no-colon", testKeyID + ":!!!", testKeyID + ":" + base64.StdEncoding.EncodeToString([]byte("short"))} {
+		_, err = sse.Lookup(ref)
+		tassert.Errorf(t, err != nil, "expected error (invalid ref %q)", ref)
+	}
+}
+
+func TestCustomerKey(t *testing.T) {
+	var (
+		fqn  = filepath.Join(t.TempDir(), "obj")
+		data = make([]byte, 64*cos.KiB+7)
+		key  = make([]byte, 32)
+	)
+	_, _ = rand.Read(data)
+	_, _ = rand.Read(key)
+	b64key := base64.StdEncoding.EncodeToString(key)
+	sum := md5.Sum(key)
+	b64md5 := base64.StdEncoding.EncodeToString(sum[:])
+
+	// parse
+	k, err := sse.ParseCustomerKey("", "", "")
+	tassert.Errorf(t, k == nil && err == nil, "expecting no key (%v)", err)
+	k, err = sse.ParseCustomerKey(sse.CustomerAlgo, b64key, b64md5)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bytes.Equal(k, key), "parsed key mismatch")
+	for _, args := range [][3]string{
+		{"AES128", b64key, b64md5},
+		{sse.CustomerAlgo, "!!!", ""},
+		{sse.CustomerAlgo, base64.StdEncoding.EncodeToString(key[:16]), ""},
+		{sse.CustomerAlgo, b64key, base64.StdEncoding.EncodeToString(key[:16])},
+	} {
+		_, err = sse.ParseCustomerKey(args[0], args[1], args[2])
+		tassert.Errorf(t, err != nil, "expected error parsing %v", args)
+	}
+
+	// encrypt
+	dk, err := sse.NewCustomerKey(key)
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, sse.IsCustomer(dk.Ref), "expecting client-supplied key reference, got %q", dk.Ref)
+	tassert.Errorf(t, !bytes.Contains([]byte(dk.Ref), []byte(b64key)), "reference must not contain the key")
+	fh, err := os.Create(fqn)
+	tassert.CheckFatal(t, err)
+	w, err := sse.NewWriter(fh, dk, testObjName)
+	tassert.CheckFatal(t, err)
+	_, err = w.Write(data)
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, w.Close())
+
+	// decrypt with the same key
+	dk2, err := sse.CustomerKey(dk.Ref, key)
+	tassert.CheckFatal(t, err)
+	fh, err = os.Open(fqn)
+	tassert.CheckFatal(t, err)
+	r, err := sse.NewReader(fh, fqn, testObjName, dk2)
+	tassert.CheckFatal(t, err)
+	b, err := io.ReadAll(r)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bytes.Equal(b, data), "content mismatch")
+	r.Close()
+
+	// same key, different salt: different data key
+	dk3, err := sse.NewCustomerKey(key)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, dk3.Ref != dk.Ref, "expecting different salt")
+	dk4, err := sse.CustomerKey(dk3.Ref, key)
+	tassert.CheckFatal(t, err)
+	fh, err = os.Open(fqn)
+	tassert.CheckFatal(t, err)
+	r, err = sse.NewReader(fh, fqn, testObjName, dk4)
+	tassert.CheckFatal(t, err)
+	_, err = io.ReadAll(r)
+	tassert.Errorf(t, err != nil, "expecting error decrypting with data key derived from a different salt")
+	r.Close()
+
+	// wrong key, no key, not applicable
+	other := bytes.Clone(key)
+	other[0] ^= 0xff
+	_, err = sse.CustomerKey(dk.Ref, other)
+	tassert.Errorf(t, errors.Is(err, sse.ErrCustomerKeyMismatch), "expecting key mismatch, got %v", err)
+	_, err = sse.Lookup(dk.Ref)
+	tassert.Errorf(t, errors.Is(err, sse.ErrCustomerKeyRequired), "expecting key required, got %v", err)
+	_, err = sse.CustomerKey(testKeyID+":"+b64key, key)
+	tassert.Errorf(t, errors.Is(err, sse.ErrCustomerKeyNotApplicable), "expecting not applicable, got %v", err)
+}
@@ -0,0 +1,321 @@
+// Package sse provides server-side encryption at rest: chunked AES-GCM content
+// encryption with data keys managed by KMS (see cmn/kms).
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package sse
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// On-disk layout of an encrypted object:
+//
+// | ------------------------------ HEADER (48) ------------------------------ | ------ CHUNKS ------ |
+// | magic (4) | version (1) | chunk shift (1) | reserved (10) | object salt (32) | sealed chunk | ... |
+//
+// - each object is sealed with its own key derived from the data key and the object's
+//   random salt (HKDF-SHA256), so that nonces never repeat across objects;
+// - plaintext is split into 64KiB chunks, each sealed (AES-GCM) separately with
+//   nonce = big-endian chunk index, which makes random reads possible;
+// - additional data of each chunk is: the header (which authenticates the latter),
+//   last-chunk flag, and object name (which binds the ciphertext to the object);
+// - each sealed chunk is 16 bytes (GCM tag) larger than its plaintext;
+// - the last chunk (possibly empty, possibly full-size) is sealed with different
+//   additional data to detect truncation;
+// - plaintext size is computed from the file size (and is stored as lom.Lsize() as usual).
+
+const (
+	hdrLen      = 48
+	objSaltLen  = 32
+	chunkShift  = 16
+	chunkSize   = 1 << chunkShift
+	tagLen      = 16
+	sealedLen   = chunkSize + tagLen
+	nonceLen    = 12
+	version     = 1
+	hkdfObjInfo = "ais-sse-object"
+)
+
+var magic = [4]byte{'a', 'i', 's', 'e'}
+
+type (
+	Writer struct {
+		w      cos.LomWriter
+		aead   cipher.AEAD // per-object
+		ad     [2][]byte   // additional data: not-last and last chunk (see chunkAD)
+		buf    []byte      // plaintext
+		sealed []byte
+		nonce  [nonceLen]byte
+		idx    uint32
+		fsync  bool
+	}
+	Reader struct {
+		fh      *os.File
+		dk      *DataKey
+		aead    cipher.AEAD
+		ad      [2][]byte
+		fqn     string
+		objName string
+		chunk   []byte // plaintext of the chunk `cidx`
+		sealed  []byte
+		nonce   [nonceLen]byte
+		size    int64 // plaintext
+		body    int64 // file size less header
+		nchk    int64
+		cidx    int64
+		off     int64 // Read() position
+		mu      sync.Mutex
+	}
+)
+
+// interface guard
+var (
+	_ cos.LomWriter      = (*Writer)(nil)
+	_ cos.LomReader      = (*Reader)(nil)
+	_ cos.ReadOpenCloser = (*Reader)(nil)
+)
+
+// per-object key
+func objAEAD(dk *DataKey, salt []byte) (cipher.AEAD, error) {
+	key := make([]byte, keyLen)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, dk.key, salt, []byte(hkdfObjInfo)), key); err != nil {
+		return nil, err
+	}
+	return newAEAD(key)
+}
+
+func chunkAD(hdr []byte, objName string) (ad [2][]byte) {
+	for i := range ad {
+		ad[i] = make([]byte, 0, hdrLen+1+len(objName))
+		ad[i] = append(ad[i], hdr...)
+		ad[i] = append(ad[i], byte(i))
+		ad[i] = append(ad[i], objName...)
+	}
+	return ad
+}
+
+////////////
+// Writer //
+////////////
+
+func NewWriter(w cos.LomWriter, dk *DataKey, objName string) (*Writer, error) {
+	var hdr [hdrLen]byte
+	copy(hdr[:], magic[:])
+	hdr[4], hdr[5] = version, chunkShift
+	if _, err := rand.Read(hdr[hdrLen-objSaltLen:]); err != nil {
+		return nil, err
+	}
+	aead, err := objAEAD(dk, hdr[hdrLen-objSaltLen:])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(hdr[:]); err != nil {
+		return nil, err
+	}
+	ew := &Writer{
+		w:      w,
+		aead:   aead,
+		ad:     chunkAD(hdr[:], objName),
+		buf:    make([]byte, 0, chunkSize),
+		sealed: make([]byte, 0, sealedLen),
+	}
+	return ew, nil
+}
+
+func (ew *Writer) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		if len(ew.buf) == chunkSize {
+			if err = ew.seal(false); err != nil {
+				return n, err
+			}
+		}
+		k := min(chunkSize-len(ew.buf), len(p))
+		ew.buf = append(ew.buf, p[:k]...)
+		p = p[k:]
+		n += k
+	}
+	return n, nil
+}
+
+// fsync what's been written so far and, again, upon Close()
+func (ew *Writer) Sync() error {
+	ew.fsync = true
+	return ew.w.Sync()
+}
+
+func (ew *Writer) Close() error {
+	err := ew.seal(true)
+	if err == nil && ew.fsync {
+		err = ew.w.Sync()
+	}
+	if errC := ew.w.Close(); err == nil {
+		err = errC
+	}
+	return err
+}
+
+func (ew *Writer) seal(last bool) error {
+	if ew.idx == math.MaxUint32 {
+		return errors.New("server-side encryption: max object size exceeded")
+	}
+	binary.BigEndian.PutUint32(ew.nonce[8:], ew.idx)
+	i := 0
+	if last {
+		i = 1
+	}
+	ew.sealed = ew.aead.Seal(ew.sealed[:0], ew.nonce[:], ew.buf, ew.ad[i])
+	if _, err := ew.w.Write(ew.sealed); err != nil {
+		return err
+	}
+	ew.idx++
+	ew.buf = ew.buf[:0]
+	return nil
+}
+
+////////////
+// Reader //
+////////////
+
+func NewReader(fh *os.File, fqn, objName string, dk *DataKey) (*Reader, error) {
+	var hdr [hdrLen]byte
+	if _, err := fh.ReadAt(hdr[:], 0); err != nil {
+		return nil, _corrupted(fqn, err)
+	}
+	if [4]byte(hdr[:4]) != magic || hdr[4] != version || hdr[5] != chunkShift {
+		return nil, _corrupted(fqn, errors.New("bad header"))
+	}
+	finfo, err := fh.Stat()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := objAEAD(dk, hdr[hdrLen-objSaltLen:])
+	if err != nil {
+		return nil, err
+	}
+	er := &Reader{
+		fh:      fh,
+		fqn:     fqn,
+		objName: objName,
+		dk:      dk,
+		aead:    aead,
+		ad:      chunkAD(hdr[:], objName),
+		body:    finfo.Size() - hdrLen,
+		cidx:    -1,
+	}
+	er.nchk = (er.body + sealedLen - 1) / sealedLen
+	er.size = er.body - er.nchk*tagLen
+	if er.nchk == 0 || er.size < 0 {
+		return nil, _corrupted(fqn, fmt.Errorf("invalid size %d", finfo.Size()))
+	}
+	return er, nil
+}
+
+// on-disk size of the encrypted object given its plaintext size
+func FileSize(size int64) int64 {
+	nchk := max((size+chunkSize-1)>>chunkShift, 1)
+	return hdrLen + size + nchk*tagLen
+}
+
+// plaintext size given the on-disk size (the inverse of FileSize); -1 if invalid
+func PlainSize(fsize int64) int64 {
+	body := fsize - hdrLen
+	nchk := (body + sealedLen - 1) / sealedLen
+	if size := body - nchk*tagLen; nchk > 0 && size >= 0 && FileSize(size) == fsize {
+		return size
+	}
+	return -1
+}
+
+func _corrupted(fqn string, err error) error {
+	return fmt.Errorf("%s: corrupted encrypted content: %w", fqn, err)
+}
+
+func (er *Reader) Size() int64 { return er.size }
+
+func (er *Reader) Read(p []byte) (n int, err error) {
+	n, err = er.ReadAt(p, er.off)
+	er.off += int64(n)
+	if n > 0 && err == io.EOF {
+		err = nil
+	}
+	return n, err
+}
+
+func (er *Reader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	er.mu.Lock()
+	for n < len(p) && off < er.size {
+		idx := off >> chunkShift
+		if idx != er.cidx {
+			if err = er.load(idx); err != nil {
+				er.mu.Unlock()
+				return n, err
+			}
+		}
+		k := copy(p[n:], er.chunk[off-idx<<chunkShift:])
+		n += k
+		off += int64(k)
+	}
+	er.mu.Unlock()
+	if n < len(p) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (er *Reader) load(idx int64) error {
+	if er.sealed == nil {
+		er.sealed = make([]byte, sealedLen)
+		er.chunk = make([]byte, 0, chunkSize)
+	}
+	var (
+		i    int
+		clen = int64(sealedLen)
+	)
+	if idx == er.nchk-1 {
+		clen, i = er.body-idx*sealedLen, 1
+	}
+	if _, err := er.fh.ReadAt(er.sealed[:clen], hdrLen+idx*sealedLen); err != nil {
+		er.cidx = -1
+		return err
+	}
+	binary.BigEndian.PutUint32(er.nonce[8:], uint32(idx))
+	chunk, err := er.aead.Open(er.chunk[:0], er.nonce[:], er.sealed[:clen], er.ad[i])
+	if err != nil {
+		er.cidx = -1
+		return _corrupted(er.fqn, fmt.Errorf("chunk #%d: %w", idx, err))
+	}
+	er.chunk, er.cidx = chunk, idx
+	return nil
+}
+
+func (er *Reader) Close() error { return er.fh.Close() }
+
+func (er *Reader) Open() (cos.ReadOpenCloser, error) {
+	fh, err := os.Open(er.fqn)
+	if err != nil {
+		return nil, err
+	}
+	nr, err := NewReader(fh, er.fqn, er.objName, er.dk)
+	if err != nil {
+		fh.Close()
+		return nil, err
+	}
+	return nr, nil
+}
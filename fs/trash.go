@@ -0,0 +1,48 @@
+// Package fs provides mountpath and FQN abstractions and methods to resolve/map stored content
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Trash: soft-deleted objects (see config.Space.TrashTime)
+// - stored alongside other content in the bucket's (per-mountpath) directory, under TrashType;
+// - named <object-name>.<deletion time in Unix nanoseconds>;
+// - never moved (rebalance, resilver) or evicted, and get purged upon expiration.
+
+const TrashType = "tr"
+
+type TrashContentResolver struct{}
+
+func (*TrashContentResolver) PermToMove() bool    { return false }
+func (*TrashContentResolver) PermToEvict() bool   { return false }
+func (*TrashContentResolver) PermToProcess() bool { return false }
+
+// prefix: deletion time (see TrashPrefix)
+func (*TrashContentResolver) GenUniqueFQN(base, prefix string) string {
+	return base + "." + prefix
+}
+
+func (*TrashContentResolver) ParseUniqueFQN(base string) (orig string, old, ok bool) {
+	orig, _, ok = ParseTrash(base)
+	return
+}
+
+func TrashPrefix(deleted int64) string { return strconv.FormatInt(deleted, 10) }
+
+// returns the original object name (or basename) and the time of deletion
+func ParseTrash(base string) (orig string, deleted int64, ok bool) {
+	i := strings.LastIndexByte(base, '.')
+	if i <= 0 {
+		return "", 0, false
+	}
+	deleted, err := strconv.ParseInt(base[i+1:], 10, 64)
+	if err != nil || deleted <= 0 {
+		return "", 0, false
+	}
+	return base[:i], deleted, true
+}
@@ -29,3 +29,5 @@ func fs2disks(string, string, cos.MountpathLabel, BlockDevices, int, bool) (FsDi
 	drive := driveStats[0]
 	return FsDisks{drive.Name: drive.BlockSize}, nil
 }
+
+func IsRotational(string) bool { return false }
@@ -293,3 +293,13 @@ func _match(blockDevs BlockDevices, device string) bool {
 	}
 	return false
 }
+
+// IsRotational returns true if the disk (e.g., "sda") is a spinning one
+// (see /sys/class/block/<disk>/queue/rotational)
+func IsRotational(disk string) bool {
+	b, err := os.ReadFile(filepath.Join(sysBlockPath, disk, "queue", "rotational"))
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(b)) == "1"
+}
@@ -79,3 +79,27 @@ func TestSGLStressN(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestLargeSlab(t *testing.T) {
+	mem := &memsys.MMSA{Name: "lmem", MinPctFree: 50}
+	mem.Init(0)
+	defer mem.Terminate(false)
+
+	for _, test := range []struct{ size, expected int64 }{
+		{memsys.MaxPageSlabSize + 1, 2 * memsys.MaxPageSlabSize},
+		{cos.MiB, cos.MiB},
+		{3 * cos.MiB, 4 * cos.MiB},
+		{memsys.MaxLargeSlabSize, memsys.MaxLargeSlabSize},
+	} {
+		slab, err := mem.LargeSlab(test.size)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, slab.Size() == test.expected, "size %d: expected large slab %d, got %d", test.size, test.expected, slab.Size())
+		buf := slab.Alloc()
+		tassert.Fatalf(t, int64(len(buf)) == test.expected, "expected buffer size %d, got %d", test.expected, len(buf))
+		mem.Free(buf)
+	}
+	for _, size := range []int64{memsys.MaxPageSlabSize, memsys.MaxLargeSlabSize + 1} {
+		_, err := mem.LargeSlab(size)
+		tassert.Errorf(t, err != nil, "expected size %d to be rejected", size)
+	}
+}
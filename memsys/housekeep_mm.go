@@ -29,7 +29,7 @@ var (
 // API: on-demand memory freeing to the user-provided specification
 func (r *MMSA) FreeSpec(spec FreeSpec) {
 	var freed int64
-	for _, s := range r.rings {
+	for _, s := range r.slabs() {
 		freed += s.cleanup()
 	}
 	if freed > 0 {
@@ -96,11 +96,14 @@ func (r *MMSA) hkcb(now int64) time.Duration {
 		depth = optDepth / 2
 	}
 
-	// 5. reduce
+	// 5. reduce (large slabs: to zero)
 	for _, s := range r.rings {
 		freed := s.reduce(depth)
 		r.toGC.Add(freed)
 	}
+	for _, s := range r.large {
+		r.toGC.Add(s.cleanup())
+	}
 
 	// 6. GC and free mem to OS
 	r.freeMemToOS(mingc, pressure)
@@ -120,7 +123,7 @@ func (r *MMSA) hkIval(pressure int) time.Duration {
 
 // refresh and clone internal hits/idle stats
 func (r *MMSA) refreshStats(now int64) {
-	for i := range r.numSlabs {
+	for i := range r.numSlabs + len(r.large) {
 		hits := r.hits[i].Swap(0)
 		if hits == 0 {
 			if !r.idleTs[i].CAS(0, now) {
@@ -136,19 +139,23 @@ func (r *MMSA) refreshStats(now int64) {
 // freeIdle traverses and deallocates idle slabs- those that were not used for at
 // least the specified duration; returns freed size
 func (r *MMSA) freeIdle() (total int64) {
-	for i, s := range r.rings {
+	for i, s := range r.slabs() {
 		var (
 			freed int64
 			idle  = r.idleDur[i]
+			depth = optDepth
 		)
 		debug.Assert(s.ringIdx() == i)
+		if i >= r.numSlabs {
+			depth = minDepth * 2 // large
+		}
 		switch {
 		case idle > freeIdleZero:
 			freed = s.cleanup()
 		case idle > freeIdleMinDur:
-			freed = s.reduce(optDepth / 4)
+			freed = s.reduce(depth / 4)
 		case idle > freeIdleMinDur/2:
-			freed = s.reduce(optDepth / 2)
+			freed = s.reduce(depth / 2)
 		default:
 			continue
 		}
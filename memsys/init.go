@@ -210,16 +210,41 @@ func (r *MMSA) Init(maxUse int64) (err error) {
 		slab.pMinDepth = &r.optDepth
 		r.rings[i] = slab
 	}
+	if r.isPage() {
+		r.lrgDepth.Store(minDepth)
+		r.large = make([]*Slab, NumLargeSlabs)
+		for i := range NumLargeSlabs {
+			bufSize := r.maxSlabSize << (i + 1)
+			slab := &Slab{
+				m:       r,
+				tag:     r.Name + "." + cos.ToSizeIEC(bufSize, 0),
+				bufSize: bufSize,
+				idx:     r.numSlabs + i,
+				get:     make([][]byte, 0, minDepth),
+				put:     make([][]byte, 0, minDepth),
+			}
+			slab.pMinDepth = &r.lrgDepth
+			r.large[i] = slab
+		}
+	}
 	return
 }
 
+// all slabs including large ones, if any
+func (r *MMSA) slabs() []*Slab {
+	if len(r.large) == 0 {
+		return r.rings
+	}
+	return append(r.rings[:len(r.rings):len(r.rings)], r.large...)
+}
+
 // [tests only] terminate this MMSA instance, run GC
 func (r *MMSA) Terminate(unregHK bool) {
 	var freed int64
 	if unregHK {
 		hk.Unreg(r.Name + hk.NameSuffix)
 	}
-	for _, s := range r.rings {
+	for _, s := range r.slabs() {
 		freed += s.cleanup()
 	}
 	r.toGC.Add(freed)
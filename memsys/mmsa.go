@@ -111,7 +111,15 @@ const (
 	NumSmallSlabs    = MaxSmallSlabSize / SmallSlabIncStep // = 32
 )
 
-const NumStats = NumPageSlabs // NOTE: must be >= NumSmallSlabs
+// large slabs (page MMSA only): powers of two above MaxPageSlabSize, up to MaxLargeSlabSize
+// - intended for long-lived (e.g., per-jogger) buffers; see LargeSlab()
+// - trend to (and, under memory pressure, get reduced below) minDepth
+const (
+	MaxLargeSlabSize = 16 * cos.MiB
+	NumLargeSlabs    = 7 // 256K, 512K, ..., 16M
+)
+
+const NumStats = NumPageSlabs + NumLargeSlabs // NOTE: must be >= NumSmallSlabs
 
 const (
 	optDepth = 128  // ring "depth", i.e., num free bufs we trend to (see grow())
@@ -135,6 +143,7 @@ type (
 		info        string
 		sibling     *MMSA
 		rings       []*Slab
+		large       []*Slab // page MMSA only
 		hits        [NumStats]atomic.Uint64
 		idleTs      [NumStats]atomic.Int64
 		idleDur     [NumStats]time.Duration
@@ -147,6 +156,7 @@ type (
 		// atomic state
 		toGC     atomic.Int64 // accumulates over time and triggers GC upon reaching spec-ed limit
 		optDepth atomic.Int64 // ring "depth", i.e., num free bufs we trend to (see grow())
+		lrgDepth atomic.Int64 // ditto, large slabs
 		swap     struct {
 			size atomic.Uint64 // actual swap size
 			crit atomic.Int32  // tracks increasing swap size up to `swappingMax`
@@ -253,6 +263,23 @@ func (r *MMSA) GetSlab(bufSize int64) (s *Slab, err error) {
 	return
 }
 
+// gets the smallest large slab that fits a given buffer size in the range (MaxPageSlabSize, MaxLargeSlabSize]
+func (r *MMSA) LargeSlab(bufSize int64) (*Slab, error) {
+	if !r.isPage() {
+		return nil, fmt.Errorf("memsys: %s does not have large slabs", r.Name)
+	}
+	if bufSize <= r.maxSlabSize || bufSize > MaxLargeSlabSize {
+		return nil, fmt.Errorf("memsys: large size %d outside valid range (%d, %d]", bufSize, r.maxSlabSize, MaxLargeSlabSize)
+	}
+	for _, s := range r.large {
+		if s.Size() >= bufSize {
+			return s, nil
+		}
+	}
+	debug.Assert(false, bufSize)
+	return r.large[len(r.large)-1], nil
+}
+
 // uses SelectMemAndSlab to select both MMSA (page or small) and its Slab
 func (r *MMSA) AllocSize(size int64) (buf []byte, slab *Slab) {
 	_, slab = r.SelectMemAndSlab(size)
@@ -274,6 +301,9 @@ func (r *MMSA) Free(buf []byte) {
 		r.sibling.Free(buf)
 	case size < r.slabIncStep && r.isPage():
 		r.sibling.Free(buf)
+	case size > r.maxSlabSize && r.isPage() && r._isLarge(size):
+		slab, _ := r.LargeSlab(size)
+		slab.Free(buf)
 	default:
 		slab := r._selectSlab(size)
 		slab.Free(buf)
@@ -325,6 +355,16 @@ func (r *MMSA) Append(buf []byte, bytes string) (nbuf []byte) {
 
 // private
 
+// whether a given size is exactly one of the large slab sizes (see LargeSlab)
+func (r *MMSA) _isLarge(size int64) bool {
+	for _, s := range r.large {
+		if s.Size() == size {
+			return true
+		}
+	}
+	return false
+}
+
 // select slab for SGL given a large immediate size to allocate
 func (r *MMSA) _large2slab(immediateSize int64) *Slab {
 	size := cos.DivCeil(immediateSize, countThreshold)
@@ -27,6 +27,7 @@ import (
 	"github.com/NVIDIA/aistore/core/meta"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/mpather"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/transport/bundle"
 	"github.com/NVIDIA/aistore/xact"
@@ -100,6 +101,7 @@ type (
 	rebJogger struct {
 		joggerBase
 		rargs *rebArgs
+		gov   *mpather.GovClient // nil when retransmitting
 		opts  fs.WalkOpts
 		ver   int64
 	}
@@ -146,6 +148,9 @@ func New(config *cmn.Config) *Reb {
 		Config:      config,
 		Compression: config.Rebalance.Compression,
 		Multiplier:  config.Rebalance.SbundleMult,
+		QUIC:        config.Rebalance.QUIC,
+		ZstdLevel:   config.Rebalance.ZstdLevel,
+		Priority:    transport.PrioLow,
 	}
 	reb.dm = bundle.NewDM(trname, reb.recvObj, cmn.OwtRebalance, dmExtra) // (compare with dm.Renew below)
 
@@ -396,6 +401,9 @@ func (reb *Reb) initRenew(rargs *rebArgs, notif *xact.NotifXact, haveStreams boo
 			Config:      rargs.config,
 			Compression: rargs.config.Rebalance.Compression,
 			Multiplier:  rargs.config.Rebalance.SbundleMult,
+			QUIC:        rargs.config.Rebalance.QUIC,
+			ZstdLevel:   rargs.config.Rebalance.ZstdLevel,
+			Priority:    transport.PrioLow,
 		}
 		if dm := reb.dm.Renew(trname, reb.recvObj, cmn.OwtRebalance, dmExtra); dm != nil {
 			reb.dm = dm
@@ -487,17 +495,20 @@ func (reb *Reb) runNoEC(rargs *rebArgs) error {
 	var (
 		wg  = &sync.WaitGroup{}
 		ver = rargs.smap.Version
+		gov = mpather.RegGov(apc.ActRebalance)
 	)
 	for _, mi := range rargs.apaths {
 		rl := &rebJogger{
 			joggerBase: joggerBase{m: reb, xreb: rargs.xreb, wg: wg},
 			rargs:      rargs,
+			gov:        gov,
 			ver:        ver,
 		}
 		wg.Add(1)
 		go rl.jog(mi)
 	}
 	wg.Wait()
+	gov.Unreg()
 
 	if err := rargs.xreb.AbortErr(); err != nil {
 		nlog.Warningln(rargs.logHdr, "finish no-ec run, abort joggers: [", err, "]")
@@ -782,6 +793,12 @@ func (rj *rebJogger) visitObj(fqn string, de fs.DirEntry) error {
 	if de.IsDir() {
 		return nil
 	}
+	if rj.gov != nil {
+		if !rj.gov.Acquire(rj.xreb.IsAborted) {
+			return rj.xreb.AbortErr()
+		}
+		defer rj.gov.Release()
+	}
 	lom := core.AllocLOM(fqn)
 	err := rj._lwalk(lom, fqn)
 	if err != nil {
@@ -845,6 +862,10 @@ func (rj *rebJogger) _lwalk(lom *core.LOM, fqn string) error {
 		return err
 	}
 
+	if rj.gov != nil {
+		rj.gov.Charge(lom.Lsize())
+	}
+
 	// transmit (unlock via transport completion => roc.Close)
 	rj.m.addLomAck(lom)
 	if err := rj.doSend(lom, tsi, roc); err != nil {
@@ -874,7 +895,7 @@ func _getReader(lom *core.LOM) (roc cos.ReadOpenCloser, err error) {
 		}
 	}
 	debug.Assert(lom.Checksum() != nil, lom.String())
-	return lom.NewDeferROC()
+	return lom.NewMigrateROC()
 }
 
 func (rj *rebJogger) doSend(lom *core.LOM, tsi *meta.Snode, roc cos.ReadOpenCloser) error {
@@ -888,6 +909,7 @@ func (rj *rebJogger) doSend(lom *core.LOM, tsi *meta.Snode, roc cos.ReadOpenClos
 	o.Hdr.ObjName = lom.ObjName
 	o.Hdr.Opaque = opaque
 	o.Hdr.ObjAttrs.CopyFrom(lom.ObjAttrs(), false /*skip cksum*/)
+	lom.SetMigrateAttrs(&o.Hdr.ObjAttrs)
 	o.Callback, o.CmplArg = rj.objSentCallback, lom
 	return rj.m.dm.Send(o, roc, tsi)
 }
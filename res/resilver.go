@@ -106,6 +106,8 @@ func (res *Res) RunResilver(args Args, tstats cos.StatsUpdater) {
 			CTs:                   []string{fs.ObjectType, fs.ECSliceType},
 			VisitObj:              jctx.visitObj,
 			VisitCT:               jctx.visitCT,
+			WaitPaused:            xres.WaitPaused,
+			Gov:                   mpather.RegGov(apc.ActResilver),
 			Slab:                  slab,
 			SkipGloballyMisplaced: args.SkipGlobMisplaced,
 		}
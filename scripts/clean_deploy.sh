@@ -55,6 +55,10 @@ OPTIONS:
   --gcp               Build with Google Cloud Storage backend
   --azure             Build with Azure Blob Storage backend
   --oci               Build with OCI Object Storage backend
+  --b2                Build with Backblaze B2 backend
+  --webdav            Build with WebDAV backend
+  --ftp               Build with SFTP and FTP(S) backend (read-only)
+  --posix             Build with POSIX (NFS, Lustre, GPFS mount) backend (read-only)
   --ht                Build with ht:// backend (experimental)
   --loopback          Loopback device size, e.g. 10G, 100M (default: 0). Zero size means emulated mountpaths (with no loopback devices).
   --dir               The root directory of the aistore repository
@@ -88,6 +92,10 @@ while (( "$#" )); do
     --azure) AIS_BACKEND_PROVIDERS="${AIS_BACKEND_PROVIDERS} azure"; shift;;
     --gcp)   AIS_BACKEND_PROVIDERS="${AIS_BACKEND_PROVIDERS} gcp"; shift;;
     --oci)   AIS_BACKEND_PROVIDERS="${AIS_BACKEND_PROVIDERS} oci"; shift;;
+    --b2)    AIS_BACKEND_PROVIDERS="${AIS_BACKEND_PROVIDERS} b2"; shift;;
+    --webdav) AIS_BACKEND_PROVIDERS="${AIS_BACKEND_PROVIDERS} webdav"; shift;;
+    --ftp)   AIS_BACKEND_PROVIDERS="${AIS_BACKEND_PROVIDERS} ftp"; shift;;
+    --posix) AIS_BACKEND_PROVIDERS="${AIS_BACKEND_PROVIDERS} posix"; shift;;
     --ht)    AIS_BACKEND_PROVIDERS="${AIS_BACKEND_PROVIDERS} ht"; shift;;
     --tracing) tracing="y\n${AIS_TRACING_ENDPOINT}\n${AIS_TRACING_AUTH_TOKEN_HEADER}\n${AIS_TRACING_AUTH_TOKEN_FILE}"; shift;;
 
@@ -369,7 +369,7 @@ func (j *clnJ) jogBck() (size int64, err error) {
 	opts := &fs.WalkOpts{
 		Mi:       j.mi,
 		Bck:      j.bck,
-		CTs:      []string{fs.WorkfileType, fs.ObjectType, fs.ECSliceType, fs.ECMetaType},
+		CTs:      []string{fs.WorkfileType, fs.ObjectType, fs.ECSliceType, fs.ECMetaType, fs.TrashType},
 		Callback: j.walk,
 		Sorted:   false,
 	}
@@ -431,6 +431,13 @@ func (j *clnJ) visitCT(parsedFQN *fs.ParsedFQN, fqn string) {
 			return
 		}
 		j.oldWork = append(j.oldWork, fqn)
+	case fs.TrashType:
+		// soft-deleted objects: remove expired or, when soft-delete is disabled, all
+		// (normally, purged by the target's housekeeper)
+		_, deleted, ok := fs.ParseTrash(filepath.Base(fqn))
+		if !ok || j.config.Space.TrashTime == 0 || deleted+j.config.Space.TrashTime.D().Nanoseconds() < j.now {
+			j.oldWork = append(j.oldWork, fqn)
+		}
 	default:
 		debug.Assert(false, "Unsupported content type: ", parsedFQN.ContentType)
 	}
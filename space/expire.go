@@ -0,0 +1,266 @@
+// Package space provides storage cleanup and eviction functionality (the latter based on the
+// least recently used cache replacement). It also serves as a built-in garbage-collection
+// mechanism for orphaned workfiles.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package space
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/xact"
+	"github.com/NVIDIA/aistore/xact/xreg"
+)
+
+// Object expiration (compare with S3 lifecycle expiration):
+// - an object expires at the time specified by its cmn.ExpiresObjMD custom attribute
+//   (set directly, or on PUT via apc.HdrObjTTL);
+// - expired objects get removed by apc.ActExpireObjs xaction that runs periodically
+//   (config.Space.ExpireTime) and on demand (api.StartXaction);
+// - in remote buckets, only the in-cluster copy gets evicted;
+// - until removed, expired objects remain accessible.
+
+type (
+	XactExp struct {
+		xact.Base
+	}
+	IniExp struct {
+		Xaction *XactExp
+		WG      *sync.WaitGroup
+		Args    *xact.ArgsMsg
+	}
+)
+
+// private
+type (
+	// expJ traverses a single given mountpath
+	expJ struct {
+		bck    cmn.Bck
+		now    int64 // Unix seconds
+		ini    *IniExp
+		mi     *fs.Mountpath
+		stopCh chan struct{}
+		// removed
+		cnt  int64
+		size int64
+	}
+	expFactory struct {
+		xreg.RenewBase
+		xctn *XactExp
+	}
+)
+
+// interface guard
+var (
+	_ xreg.Renewable = (*expFactory)(nil)
+	_ core.Xact      = (*XactExp)(nil)
+)
+
+func (*XactExp) Run(*sync.WaitGroup) { debug.Assert(false) }
+
+func (r *XactExp) Snap() (snap *core.Snap) {
+	snap = &core.Snap{}
+	r.ToSnap(snap)
+
+	snap.IdleX = r.IsIdle()
+	return
+}
+
+////////////////
+// expFactory //
+////////////////
+
+func (*expFactory) New(args xreg.Args, _ *meta.Bck) xreg.Renewable {
+	return &expFactory{RenewBase: xreg.RenewBase{Args: args}}
+}
+
+func (p *expFactory) Start() error {
+	p.xctn = &XactExp{}
+	ctlmsg := p.Args.Custom.(string)
+	p.xctn.InitBase(p.UUID(), apc.ActExpireObjs, ctlmsg, nil)
+	return nil
+}
+
+func (*expFactory) Kind() string     { return apc.ActExpireObjs }
+func (p *expFactory) Get() core.Xact { return p.xctn }
+
+func (*expFactory) WhenPrevIsRunning(prevEntry xreg.Renewable) (wpr xreg.WPR, err error) {
+	return xreg.WprUse, cmn.NewErrXactUsePrev(prevEntry.Get().String())
+}
+
+func RunExpire(ini *IniExp) {
+	var (
+		wg      sync.WaitGroup
+		xexp    = ini.Xaction
+		avail   = fs.GetAvail()
+		joggers = make([]*expJ, 0, len(avail))
+		now     = time.Now().Unix()
+	)
+	defer func() {
+		if ini.WG != nil {
+			ini.WG.Done()
+		}
+	}()
+	if len(avail) == 0 {
+		xexp.AddErr(cmn.ErrNoMountpaths, 0)
+		xexp.Finish()
+		return
+	}
+	providers := apc.Providers.ToSlice()
+	for _, mi := range avail {
+		j := &expJ{mi: mi, ini: ini, now: now, stopCh: make(chan struct{}, 1)}
+		joggers = append(joggers, j)
+		wg.Add(1)
+		go j.run(&wg, providers)
+	}
+	nlog.Infoln(xexp.Name(), "started")
+	if ini.WG != nil {
+		ini.WG.Done()
+		ini.WG = nil
+	}
+	wg.Wait()
+
+	var cnt, size int64
+	for _, j := range joggers {
+		j.stop()
+		cnt += j.cnt
+		size += j.size
+	}
+	xexp.Finish()
+	nlog.Infoln(xexp.Name(), "finished: removed", cnt, "expired object(s),", cos.ToSizeIEC(size, 2))
+}
+
+//////////
+// expJ //
+//////////
+
+func (j *expJ) String() string {
+	return fmt.Sprintf("%s: jog-%s", j.ini.Xaction, j.mi)
+}
+
+func (j *expJ) stop() { j.stopCh <- struct{}{} }
+
+func (j *expJ) run(wg *sync.WaitGroup, providers []string) {
+	var err error
+	defer wg.Done()
+	if len(j.ini.Args.Buckets) != 0 {
+		err = j.jogBcks(j.ini.Args.Buckets)
+	} else {
+		for _, provider := range providers {
+			var (
+				bcks []cmn.Bck
+				opts = fs.WalkOpts{Mi: j.mi, Bck: cmn.Bck{Provider: provider, Ns: cmn.NsGlobal}}
+			)
+			if bcks, err = fs.AllMpathBcks(&opts); err != nil {
+				break
+			}
+			if err = j.jogBcks(bcks); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil && !cmn.IsErrAborted(err) {
+		j.ini.Xaction.AddErr(err)
+		nlog.Errorln(j.String()+":", "exited with err:", err)
+	}
+}
+
+func (j *expJ) jogBcks(bcks []cmn.Bck) error {
+	bowner := core.T.Bowner()
+	for i := range bcks {
+		b := meta.CloneBck(&bcks[i])
+		if err := b.Init(bowner); err != nil {
+			nlog.Warningln(j.String()+":", err, "- skipping", bcks[i].String())
+			continue
+		}
+		j.bck = *b.Bucket()
+		opts := &fs.WalkOpts{
+			Mi:       j.mi,
+			Bck:      j.bck,
+			CTs:      []string{fs.ObjectType},
+			Callback: j.walk,
+			Sorted:   false,
+		}
+		if err := fs.Walk(opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *expJ) walk(fqn string, de fs.DirEntry) error {
+	if de.IsDir() {
+		return nil
+	}
+	if err := j.yieldTerm(); err != nil {
+		return err
+	}
+	var parsed fs.ParsedFQN
+	if _, err := core.ResolveFQN(fqn, &parsed); err != nil || parsed.ContentType != fs.ObjectType {
+		return nil
+	}
+	lom := core.AllocLOM(parsed.ObjName)
+	j.visitObj(lom)
+	core.FreeLOM(lom)
+	return nil
+}
+
+func (j *expJ) visitObj(lom *core.LOM) {
+	if err := lom.InitBck(&j.bck); err != nil {
+		return
+	}
+	if err := lom.Load(false /*cache it*/, false /*locked*/); err != nil {
+		return
+	}
+	if !lom.IsHRW() {
+		return // copies and misplaced (see cleanup)
+	}
+	expires, err := lom.ObjAttrs().Expires()
+	if err != nil {
+		nlog.Warningln(j.String()+":", lom.Cname(), err)
+		return
+	}
+	if expires == 0 || expires > j.now {
+		return
+	}
+	size := lom.Lsize()
+	if ecode, err := core.T.DeleteObject(lom, lom.Bck().IsRemote() /*evict*/); err != nil {
+		if !cos.IsNotExist(err, ecode) && !cmn.IsErrObjLocked(err) {
+			j.ini.Xaction.AddErr(err, 4, cos.SmoduleSpace)
+		}
+		return
+	}
+	if cmn.Rom.FastV(5, cos.SmoduleSpace) {
+		nlog.Infoln(j.String()+":", "removed expired", lom.Cname(), "expired at", time.Unix(expires, 0))
+	}
+	j.cnt++
+	j.size += size
+	j.ini.Xaction.ObjsAdd(1, size)
+}
+
+func (j *expJ) yieldTerm() error {
+	xexp := j.ini.Xaction
+	select {
+	case errCause := <-xexp.ChanAbort():
+		return cmn.NewErrAborted(xexp.Name(), "", errCause)
+	case <-j.stopCh:
+		return cmn.NewErrAborted(xexp.Name(), "", nil)
+	default:
+		break
+	}
+	if xexp.Finished() {
+		return cmn.NewErrAborted(xexp.Name(), "", nil)
+	}
+	return nil
+}
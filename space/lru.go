@@ -21,6 +21,7 @@ import (
 	"github.com/NVIDIA/aistore/core"
 	"github.com/NVIDIA/aistore/core/meta"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/mpather"
 	"github.com/NVIDIA/aistore/ios"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/xact"
@@ -73,6 +74,7 @@ type (
 	lruP struct {
 		wg      sync.WaitGroup
 		joggers map[string]*lruJ
+		gov     *mpather.GovClient
 		ini     IniLRU
 	}
 
@@ -139,8 +141,9 @@ func RunLRU(ini *IniLRU) {
 		avail   = fs.GetAvail()
 		num     = len(avail)
 		joggers = make(map[string]*lruJ, num)
-		parent  = &lruP{joggers: joggers, ini: *ini}
+		parent  = &lruP{joggers: joggers, ini: *ini, gov: mpather.RegGov(apc.ActLRU)}
 	)
+	defer parent.gov.Unreg()
 	defer func() {
 		if ini.WG != nil {
 			ini.WG.Done()
@@ -353,7 +356,11 @@ func (j *lruJ) walk(fqn string, de fs.DirEntry) error {
 		return nil
 	}
 	if parsed.ContentType == fs.ObjectType {
+		if !j.p.gov.Acquire(j.ini.Xaction.IsAborted) {
+			return cmn.NewErrAborted(j.String(), "lru-walk", nil)
+		}
 		j.visitLOM(&parsed)
+		j.p.gov.Release()
 	}
 
 	return nil
@@ -0,0 +1,339 @@
+// Package space provides storage cleanup and eviction functionality (the latter based on the
+// least recently used cache replacement). It also serves as a built-in garbage-collection
+// mechanism for orphaned workfiles.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package space
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/jsp"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Bucket snapshots (see fs.SnapType)
+// - snapshot is a per-target, per-mountpath collection of hard links to the bucket's objects
+//   (main replicas) plus metadata that includes bucket props as of the time of creation;
+// - copy-on-write: objects get overwritten and deleted via rename and unlink, respectively;
+//   in-place modifications (e.g., APPEND) copy the object first (see lom.DetachMainTo);
+// - restoring a snapshot removes objects created after the fact and replaces all others
+//   with their snapshotted versions; the bucket is expected to be quiesced (no writes) for the duration.
+
+// snapshot (metadata) exists on any of the available mountpaths
+func SnapExists(bck *meta.Bck, name string) bool {
+	for _, mi := range fs.GetAvail() {
+		if err := cos.Stat(mi.SnapMetaFQN(bck.Bucket(), name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// create snapshot of the bucket's content stored on this target
+func CreateSnapshot(bck *meta.Bck, name string, created int64) (cmn.Snapshots, error) {
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		snaps = make(cmn.Snapshots, 0, 1)
+		avail = fs.GetAvail()
+		errs  = make(chan error, len(avail))
+	)
+	for _, mi := range avail {
+		wg.Add(1)
+		go func(mi *fs.Mountpath) {
+			defer wg.Done()
+			info := &cmn.SnapshotInfo{Name: name, Created: created, Props: bck.Props}
+			cb := func(fqn string, de fs.DirEntry) error {
+				if de.IsDir() {
+					return nil
+				}
+				lom := core.AllocLOM("")
+				size, err := snapObj(lom, fqn, bck, name)
+				core.FreeLOM(lom)
+				if err != nil {
+					return err
+				}
+				if size >= 0 {
+					info.NumObjs++
+					info.Size += size
+				}
+				return nil
+			}
+			opts := &fs.WalkOpts{Mi: mi, Bck: *bck.Bucket(), CTs: []string{fs.ObjectType}, Callback: cb}
+			err := fs.Walk(opts)
+			if err == nil {
+				err = jsp.Save(mi.SnapMetaFQN(bck.Bucket(), name), info, jsp.Plain(), nil)
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			mu.Lock()
+			snaps = snaps.Add(info)
+			mu.Unlock()
+		}(mi)
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		DestroySnapshot(bck, name) // cleanup
+		return nil, err
+	}
+	return snaps, nil
+}
+
+// returns the size of the snapshotted object, or -1 when skipped
+func snapObj(lom *core.LOM, fqn string, bck *meta.Bck, name string) (int64, error) {
+	if err := lom.InitFQN(fqn, bck.Bucket()); err != nil {
+		return -1, nil
+	}
+	if !lom.IsHRW() {
+		return -1, nil // skip copies
+	}
+	lom.Lock(false)
+	defer lom.Unlock(false)
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		if cos.IsNotExist(err, 0) {
+			return -1, nil // deleted in the meantime
+		}
+		return -1, err
+	}
+	if err := lom.LinkToSnap(fs.CSM.Gen(lom, fs.SnapType, name)); err != nil {
+		return -1, err
+	}
+	return lom.Lsize(), nil
+}
+
+// remove snapshot, if exists, from all available mountpaths
+func DestroySnapshot(bck *meta.Bck, name string) (found bool) {
+	for _, mi := range fs.GetAvail() {
+		mfqn := mi.SnapMetaFQN(bck.Bucket(), name)
+		if err := cos.Stat(mfqn); err == nil {
+			found = true
+		}
+		if err := os.RemoveAll(mi.SnapDir(bck.Bucket(), name)); err != nil {
+			nlog.Errorln(mi.String(), "failed to remove snapshot", name, "of", bck.Cname(""), err)
+		}
+		if err := cos.RemoveFile(mfqn); err != nil {
+			nlog.Errorln(mi.String(), "failed to remove snapshot", name, "metadata:", err)
+		}
+	}
+	return found
+}
+
+// bucket snapshots on this target; all of them when `name` is empty
+func ListSnapshots(bck *meta.Bck, name string) (cmn.Snapshots, error) {
+	snaps := make(cmn.Snapshots, 0, 4)
+	for _, mi := range fs.GetAvail() {
+		dir := mi.MakePathCT(bck.Bucket(), fs.SnapType)
+		dentries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, dent := range dentries {
+			if dent.IsDir() {
+				continue
+			}
+			sname, ok := fs.ParseSnapMeta(dent.Name())
+			if !ok || (name != "" && sname != name) {
+				continue
+			}
+			info := &cmn.SnapshotInfo{}
+			if _, err := jsp.Load(mi.SnapMetaFQN(bck.Bucket(), sname), info, jsp.Plain()); err != nil {
+				nlog.Errorln(mi.String(), "failed to load snapshot", sname, "metadata:", err)
+				continue
+			}
+			snaps = snaps.Add(info)
+		}
+	}
+	return snaps, nil
+}
+
+// list snapshotted objects stored on this target
+func BrowseSnapshot(bck *meta.Bck, name, prefix string) (cmn.LsoEntries, error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		entries = make(cmn.LsoEntries, 0, 64)
+		avail   = fs.GetAvail()
+		errs    = make(chan error, len(avail))
+	)
+	for _, mi := range avail {
+		wg.Add(1)
+		go func(mi *fs.Mountpath) {
+			defer wg.Done()
+			var (
+				dir = mi.SnapDir(bck.Bucket(), name) + cos.PathSeparator
+				loc = core.T.String() + apc.LocationPropSepa + mi.String()
+			)
+			cb := func(fqn string, de fs.DirEntry) error {
+				if de.IsDir() {
+					return nil
+				}
+				objName := strings.TrimPrefix(fqn, dir)
+				if !strings.HasPrefix(objName, prefix) {
+					return nil
+				}
+				oa, err := core.SnapAttrs(fqn)
+				if err != nil {
+					nlog.Errorln(mi.String(), "snapshot", name, "failed to load", objName, "metadata:", err)
+					return nil
+				}
+				e := &cmn.LsoEnt{Name: objName, Size: oa.Size, Version: oa.Version(), Location: loc, Copies: 1}
+				if !oa.Cksum.IsEmpty() {
+					e.Checksum = oa.Cksum.Value()
+				}
+				mu.Lock()
+				entries = append(entries, e)
+				mu.Unlock()
+				return nil
+			}
+			if err := fs.Walk(&fs.WalkOpts{Mi: mi, Dir: dir, Callback: cb}); err != nil {
+				errs <- err
+			}
+		}(mi)
+	}
+	wg.Wait()
+	close(errs)
+	return entries, <-errs
+}
+
+// given lom, find its snapshotted version (the same mountpath first)
+func FindSnap(lom *core.LOM, name string) (sfqn string) {
+	sfqn = fs.CSM.Gen(lom, fs.SnapType, name)
+	if err := cos.Stat(sfqn); err == nil {
+		return sfqn
+	}
+	for _, mi := range fs.GetAvail() {
+		if mi == lom.Mountpath() {
+			continue
+		}
+		sfqn = mi.MakePathFQN(lom.Bucket(), fs.SnapType, name+cos.PathSeparator+lom.ObjName)
+		if err := cos.Stat(sfqn); err == nil {
+			return sfqn
+		}
+	}
+	return ""
+}
+
+// restore the bucket's content on this target:
+// 1. remove objects that are not in the snapshot
+// 2. replace (or recreate) all other objects with their snapshotted versions
+// the callback, if provided, is invoked for each restored object (under w-lock)
+func RestoreSnapshot(bck *meta.Bck, name string, cb func(*core.LOM)) (restored, removed int64, err error) {
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		avail = fs.GetAvail()
+		errs  = make(chan error, 2*len(avail))
+	)
+	// 1.
+	for _, mi := range avail {
+		wg.Add(1)
+		go func(mi *fs.Mountpath) {
+			defer wg.Done()
+			var n int64
+			rmcb := func(fqn string, de fs.DirEntry) error {
+				if de.IsDir() {
+					return nil
+				}
+				lom := core.AllocLOM("")
+				ok, err := rmNotSnap(lom, fqn, bck, name)
+				core.FreeLOM(lom)
+				if ok {
+					n++
+				}
+				return err
+			}
+			opts := &fs.WalkOpts{Mi: mi, Bck: *bck.Bucket(), CTs: []string{fs.ObjectType}, Callback: rmcb}
+			if err := fs.Walk(opts); err != nil {
+				errs <- err
+			}
+			mu.Lock()
+			removed += n
+			mu.Unlock()
+		}(mi)
+	}
+	wg.Wait()
+
+	// 2.
+	for _, mi := range avail {
+		wg.Add(1)
+		go func(mi *fs.Mountpath) {
+			defer wg.Done()
+			var (
+				n         int64
+				dir       = mi.SnapDir(bck.Bucket(), name) + cos.PathSeparator
+				buf, slab = core.T.PageMM().Alloc()
+			)
+			rscb := func(sfqn string, de fs.DirEntry) error {
+				if de.IsDir() {
+					return nil
+				}
+				lom := core.AllocLOM(strings.TrimPrefix(sfqn, dir))
+				err := restoreObj(lom, sfqn, bck, buf, cb)
+				core.FreeLOM(lom)
+				if err == nil {
+					n++
+				}
+				return err
+			}
+			if err := fs.Walk(&fs.WalkOpts{Mi: mi, Dir: dir, Callback: rscb}); err != nil {
+				errs <- err
+			}
+			slab.Free(buf)
+			mu.Lock()
+			restored += n
+			mu.Unlock()
+		}(mi)
+	}
+	wg.Wait()
+	close(errs)
+	return restored, removed, <-errs
+}
+
+func rmNotSnap(lom *core.LOM, fqn string, bck *meta.Bck, name string) (bool, error) {
+	if err := lom.InitFQN(fqn, bck.Bucket()); err != nil || !lom.IsHRW() {
+		return false, nil
+	}
+	if FindSnap(lom, name) != "" {
+		return false, nil
+	}
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		return false, nil
+	}
+	if err := lom.RemoveObj(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func restoreObj(lom *core.LOM, sfqn string, bck *meta.Bck, buf []byte, cb func(*core.LOM)) error {
+	if err := lom.InitBck(bck.Bucket()); err != nil {
+		return err
+	}
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err := lom.RestoreFromSnap(sfqn, buf); err != nil {
+		return err
+	}
+	if cb != nil {
+		cb(lom)
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strconv"
 	"testing"
 	"time"
 
@@ -284,6 +285,202 @@ var _ = Describe("space evict/cleanup tests", func() {
 				Expect(len(files)).To(Equal(0))
 			})
 		})
+
+		Describe("expire objects", func() {
+			var ini *space.IniExp
+			BeforeEach(func() {
+				ini = newIniExp()
+			})
+			It("should remove only expired objects", func() {
+				var (
+					now     = time.Now()
+					expired = []string{
+						strconv.FormatInt(now.Add(-time.Minute).Unix(), 10),
+						now.Add(-time.Hour).UTC().Format(time.RFC3339),
+						strconv.FormatInt(now.Unix(), 10),
+					}
+					notExpired = []string{
+						"",
+						strconv.FormatInt(now.Add(time.Hour).Unix(), 10),
+						now.Add(24 * time.Hour).UTC().Format(time.RFC3339),
+						"not-a-time",
+					}
+				)
+				for i, v := range expired {
+					saveRandomFileExpires(path.Join(filesPath, getRandomFileName(i)), v)
+				}
+				for i, v := range notExpired {
+					saveRandomFileExpires(path.Join(fpAnother, getRandomFileName(i)), v)
+				}
+
+				space.RunExpire(ini)
+
+				snap := ini.Xaction.Snap()
+				Expect(snap.Stats.Objs).To(BeEquivalentTo(len(expired)))
+				Expect(snap.Stats.Bytes).To(BeEquivalentTo(len(expired) * blockSize))
+			})
+		})
+
+		Describe("trash", func() {
+			var (
+				bck       *meta.Bck
+				trashPath string
+				now       = time.Now()
+				old       = now.Add(-2 * time.Hour).UnixNano()
+				recent    = now.Add(-time.Minute).UnixNano()
+			)
+			BeforeEach(func() {
+				bck = meta.NewBck(bucketName, apc.AIS, cmn.NsGlobal)
+				trashPath = fs.GetAvail()[basePath].MakePathCT(bck.Bucket(), fs.TrashType)
+				saveRandomFile(path.Join(filesPath, "restored"), blockSize) // (not in trash)
+				for _, deleted := range []int64{old, recent} {
+					saveTrashFile(path.Join(trashPath, "a/obj."+fs.TrashPrefix(deleted)))
+				}
+				saveTrashFile(path.Join(trashPath, "b."+fs.TrashPrefix(old)))
+			})
+
+			It("should list soft-deleted objects", func() {
+				entries, err := space.ListTrash(bck, "", time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entries).To(HaveLen(3))
+				for _, e := range entries {
+					Expect(e.Name).To(BeElementOf("a/obj", "b"))
+					Expect(e.Size).To(BeEquivalentTo(blockSize))
+					Expect(e.Expires).To(Equal(e.Deleted + time.Hour.Nanoseconds()))
+				}
+
+				entries, err = space.ListTrash(bck, "a/", time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entries).To(HaveLen(2))
+			})
+
+			It("should find the most recently deleted version", func() {
+				lom := core.AllocLOM("a/obj")
+				defer core.FreeLOM(lom)
+				Expect(lom.InitBck(bck.Bucket())).NotTo(HaveOccurred())
+
+				tfqn, deleted := space.FindTrash(lom, 0)
+				Expect(deleted).To(Equal(recent))
+				Expect(tfqn).To(Equal(path.Join(trashPath, "a/obj."+fs.TrashPrefix(recent))))
+
+				_, deleted = space.FindTrash(lom, old)
+				Expect(deleted).To(Equal(old))
+
+				_, deleted = space.FindTrash(lom, 1)
+				Expect(deleted).To(BeZero())
+			})
+
+			It("should purge expired", func() {
+				cnt, size := space.PurgeTrash(now.Add(-time.Hour).UnixNano())
+				Expect(cnt).To(BeEquivalentTo(2))
+				Expect(size).To(BeEquivalentTo(2 * blockSize))
+
+				entries, err := space.ListTrash(bck, "", time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entries).To(HaveLen(1))
+				Expect(entries[0].Deleted).To(Equal(recent))
+			})
+
+			It("should be removed by cleanup when disabled", func() {
+				space.RunCleanup(newInitStoreCln())
+
+				entries, err := space.ListTrash(bck, "", 0)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entries).To(BeEmpty())
+				Expect(path.Join(filesPath, "restored")).To(BeARegularFile())
+			})
+		})
+
+		Describe("snapshot", func() {
+			var (
+				bck   *meta.Bck
+				names = []string{"a/obj1", "a/obj2", "b"}
+			)
+			BeforeEach(func() {
+				bck = meta.NewBck(bucketName, apc.AIS, cmn.NsGlobal)
+				for _, name := range names {
+					saveRandomFile(path.Join(filesPath, name), blockSize)
+				}
+			})
+
+			It("should create, list, and browse", func() {
+				snaps, err := space.CreateSnapshot(bck, "s1", time.Now().UnixNano())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(snaps).To(HaveLen(1))
+				Expect(snaps[0].NumObjs).To(BeEquivalentTo(len(names)))
+				Expect(snaps[0].Size).To(BeEquivalentTo(len(names) * blockSize))
+				Expect(space.SnapExists(bck, "s1")).To(BeTrue())
+
+				snaps, err = space.ListSnapshots(bck, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(snaps).To(HaveLen(1))
+				Expect(snaps[0].Name).To(Equal("s1"))
+
+				entries, err := space.BrowseSnapshot(bck, "s1", "a/")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entries).To(HaveLen(2))
+				for _, e := range entries {
+					Expect(e.Name).To(BeElementOf("a/obj1", "a/obj2"))
+					Expect(e.Size).To(BeEquivalentTo(blockSize))
+				}
+			})
+
+			It("should restore", func() {
+				orig, err := os.ReadFile(path.Join(filesPath, "b"))
+				Expect(err).NotTo(HaveOccurred())
+				_, err = space.CreateSnapshot(bck, "s1", time.Now().UnixNano())
+				Expect(err).NotTo(HaveOccurred())
+
+				// overwrite (via rename), delete, and create
+				saveRandomFile(path.Join(filesPath, "b.tmp"), blockSize)
+				Expect(os.Rename(path.Join(filesPath, "b.tmp"), path.Join(filesPath, "b"))).NotTo(HaveOccurred())
+				Expect(os.Remove(path.Join(filesPath, "a/obj1"))).NotTo(HaveOccurred())
+				saveRandomFile(path.Join(filesPath, "c"), blockSize)
+
+				restored, removed, err := space.RestoreSnapshot(bck, "s1", nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(restored).To(BeEquivalentTo(len(names)))
+				Expect(removed).To(BeEquivalentTo(1))
+
+				Expect(path.Join(filesPath, "a/obj1")).To(BeARegularFile())
+				Expect(path.Join(filesPath, "c")).NotTo(BeAnExistingFile())
+				b, err := os.ReadFile(path.Join(filesPath, "b"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(b).To(Equal(orig))
+			})
+
+			It("should copy on write", func() {
+				_, err := space.CreateSnapshot(bck, "s1", time.Now().UnixNano())
+				Expect(err).NotTo(HaveOccurred())
+
+				lom := core.AllocLOM("b")
+				defer core.FreeLOM(lom)
+				Expect(lom.InitBck(bck.Bucket())).NotTo(HaveOccurred())
+				sfqn := space.FindSnap(lom, "s1")
+				Expect(sfqn).To(BeARegularFile())
+
+				wfqn := path.Join(filesPath, "b.work")
+				Expect(lom.DetachMainTo(wfqn, nil)).NotTo(HaveOccurred())
+				Expect(lom.FQN).To(BeARegularFile())
+				n, err := cos.NumLinks(wfqn)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(n).To(BeEquivalentTo(1))
+				oa, err := core.SnapAttrs(sfqn)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(oa.Size).To(BeEquivalentTo(blockSize))
+			})
+
+			It("should destroy", func() {
+				_, err := space.CreateSnapshot(bck, "s1", time.Now().UnixNano())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(space.DestroySnapshot(bck, "s1")).To(BeTrue())
+				Expect(space.SnapExists(bck, "s1")).To(BeFalse())
+				Expect(space.DestroySnapshot(bck, "s1")).To(BeFalse())
+				for _, name := range names {
+					Expect(path.Join(filesPath, name)).To(BeARegularFile())
+				}
+			})
+		})
 	})
 })
 
@@ -365,6 +562,15 @@ func newInitStoreCln() *space.IniCln {
 	}
 }
 
+func newIniExp() *space.IniExp {
+	xexp := &space.XactExp{}
+	xexp.InitBase(cos.GenUUID(), apc.ActExpireObjs, "" /*ctlmsg*/, nil)
+	return &space.IniExp{
+		Xaction: xexp,
+		Args:    &xact.ArgsMsg{},
+	}
+}
+
 func initConfig() {
 	config := cmn.GCO.BeginUpdate()
 	config.LRU.DontEvictTime = 0
@@ -382,6 +588,8 @@ func createAndAddMountpath(path string) {
 
 	fs.CSM.Reg(fs.ObjectType, &fs.ObjectContentResolver{}, true)
 	fs.CSM.Reg(fs.WorkfileType, &fs.WorkfileContentResolver{}, true)
+	fs.CSM.Reg(fs.TrashType, &fs.TrashContentResolver{}, true)
+	fs.CSM.Reg(fs.SnapType, &fs.SnapContentResolver{}, true)
 }
 
 func getRandomFileName(fileCounter int) string {
@@ -401,6 +609,28 @@ func saveRandomFile(filename string, size int64) {
 	Expect(lom.Persist()).NotTo(HaveOccurred())
 }
 
+func saveRandomFileExpires(filename, expires string) {
+	buff := make([]byte, blockSize)
+	_, err := cos.SaveReader(filename, rand.Reader, buff, cos.ChecksumNone, blockSize)
+	Expect(err).NotTo(HaveOccurred())
+	lom := &core.LOM{}
+	err = lom.InitFQN(filename, nil)
+	Expect(err).NotTo(HaveOccurred())
+	lom.SetSize(blockSize)
+	lom.IncVersion()
+	lom.SetAtimeUnix(time.Now().UnixNano())
+	if expires != "" {
+		lom.SetCustomKey(cmn.ExpiresObjMD, expires)
+	}
+	Expect(lom.Persist()).NotTo(HaveOccurred())
+}
+
+func saveTrashFile(fqn string) {
+	buff := make([]byte, blockSize)
+	_, err := cos.SaveReader(fqn, rand.Reader, buff, cos.ChecksumNone, blockSize)
+	Expect(err).NotTo(HaveOccurred())
+}
+
 func saveRandomFilesWithMetadata(filesPath string, files []fileMetadata) {
 	for _, file := range files {
 		saveRandomFile(path.Join(filesPath, file.name), file.size)
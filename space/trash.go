@@ -0,0 +1,161 @@
+// Package space provides storage cleanup and eviction functionality (the latter based on the
+// least recently used cache replacement). It also serves as a built-in garbage-collection
+// mechanism for orphaned workfiles.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package space
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Trash: soft-deleted objects (see fs.TrashType)
+// - objects deleted from ais buckets (config.Space.TrashTime > 0) get moved into trash
+//   on their respective mountpaths instead of being removed right away;
+// - can be listed and restored (undeleted) until purged;
+// - purged (for real) by the target's housekeeping janitor once older than TrashTime.
+
+// soft-deleted objects of a given bucket stored on this target
+func ListTrash(bck *meta.Bck, prefix string, trashTime time.Duration) (apc.TrashEntries, error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		entries = make(apc.TrashEntries, 0, 16)
+		avail   = fs.GetAvail()
+		errs    = make(chan error, len(avail))
+		tid     = core.T.SID()
+	)
+	for _, mi := range avail {
+		wg.Add(1)
+		go func(mi *fs.Mountpath) {
+			defer wg.Done()
+			cb := func(fqn string, de fs.DirEntry) error {
+				if de.IsDir() {
+					return nil
+				}
+				var parsed fs.ParsedFQN
+				if err := parsed.Init(fqn); err != nil || parsed.ContentType != fs.TrashType {
+					return nil
+				}
+				objName, deleted, ok := fs.ParseTrash(parsed.ObjName)
+				if !ok || !strings.HasPrefix(objName, prefix) {
+					return nil
+				}
+				finfo, err := os.Lstat(fqn)
+				if err != nil {
+					return nil // (purged in the meantime)
+				}
+				e := &apc.TrashEntry{
+					Name:    objName,
+					Target:  tid,
+					Size:    finfo.Size(),
+					Deleted: deleted,
+					Expires: deleted + trashTime.Nanoseconds(),
+				}
+				mu.Lock()
+				entries = append(entries, e)
+				mu.Unlock()
+				return nil
+			}
+			opts := &fs.WalkOpts{Mi: mi, Bck: *bck.Bucket(), CTs: []string{fs.TrashType}, Callback: cb}
+			if err := fs.Walk(opts); err != nil {
+				errs <- err
+			}
+		}(mi)
+	}
+	wg.Wait()
+	close(errs)
+	return entries, <-errs
+}
+
+// given (w-locked) lom, find its soft-deleted version: the one deleted at a given time,
+// or the most recent one when `deleted` is zero
+func FindTrash(lom *core.LOM, deleted int64) (tfqn string, found int64) {
+	base := filepath.Base(lom.ObjName)
+	for _, mi := range fs.GetAvail() {
+		dir := filepath.Dir(mi.MakePathFQN(lom.Bucket(), fs.TrashType, lom.ObjName))
+		dentries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, dent := range dentries {
+			if dent.IsDir() || !strings.HasPrefix(dent.Name(), base+".") {
+				continue
+			}
+			orig, t, ok := fs.ParseTrash(dent.Name())
+			if !ok || orig != base {
+				continue
+			}
+			if (deleted == 0 && t > found) || t == deleted {
+				tfqn, found = filepath.Join(dir, dent.Name()), t
+			}
+		}
+	}
+	return tfqn, found
+}
+
+// remove (for real) soft-deleted objects that were deleted before `olderThan` (Unix nanoseconds)
+func PurgeTrash(olderThan int64) (cnt, size int64) {
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		providers = apc.Providers.ToSlice()
+	)
+	for _, mi := range fs.GetAvail() {
+		wg.Add(1)
+		go func(mi *fs.Mountpath) {
+			defer wg.Done()
+			var n, sz int64
+			cb := func(fqn string, de fs.DirEntry) error {
+				if de.IsDir() {
+					return nil
+				}
+				_, deleted, ok := fs.ParseTrash(filepath.Base(fqn))
+				if !ok || deleted >= olderThan {
+					return nil
+				}
+				finfo, err := os.Lstat(fqn)
+				if err != nil {
+					return nil
+				}
+				if err := cos.RemoveFile(fqn); err != nil {
+					nlog.Errorln("failed to purge", fqn, "from trash:", err)
+					return nil
+				}
+				n++
+				sz += finfo.Size()
+				return nil
+			}
+			for _, provider := range providers {
+				opts := &fs.WalkOpts{
+					Mi:       mi,
+					Bck:      cmn.Bck{Provider: provider, Ns: cmn.NsGlobal},
+					CTs:      []string{fs.TrashType},
+					Callback: cb,
+				}
+				if err := fs.Walk(opts); err != nil {
+					nlog.Errorln(mi.String(), "failed to purge trash:", err)
+				}
+			}
+			mu.Lock()
+			cnt += n
+			size += sz
+			mu.Unlock()
+		}(mi)
+	}
+	wg.Wait()
+	return cnt, size
+}
@@ -174,6 +174,8 @@ func LatencyToCounter(latName string) string {
 		return ListCount
 	case AppendLatency:
 		return AppendCount
+	case ReplLag:
+		return ReplCount
 	}
 	// 2. filter out
 	if !strings.Contains(latName, "get.") && !strings.Contains(latName, "put.") {
@@ -305,6 +305,23 @@ func (r *runner) regCommon(snode *meta.Snode) {
 		},
 	)
 
+	// X.509 (re)loading (see cmn/certloader)
+	r.reg(snode, cos.CertReloadCount, KindCounter,
+		&Extra{
+			Help: "number of times X.509 certificate was successfully (re)loaded",
+		},
+	)
+	r.reg(snode, cos.ErrCertReloadCount, KindCounter,
+		&Extra{
+			Help: "number of failed attempts to (re)load X.509 certificate",
+		},
+	)
+	r.reg(snode, cos.CertReloadLatency, KindLatency,
+		&Extra{
+			Help: "X.509 certificate (re)loading (reading and parsing): average time (milliseconds) over the last periodic.stats_time interval",
+		},
+	)
+
 	// snode state flags
 	r.reg(snode, NodeAlerts, KindGauge,
 		&Extra{
@@ -47,6 +47,12 @@ const (
 	VerChangeCount = "ver.change.n"
 	VerChangeSize  = "ver.change.size"
 
+	// cross-cluster bucket replication (see cmn.ReplicationConf)
+	ReplCount         = "repl.n"
+	ReplSize          = "repl.size"
+	ReplConflictCount = "repl.conflict.n"
+	ErrReplCount      = errPrefix + "repl.n"
+
 	// errors
 	ErrPutCksumCount = errPrefix + "put.cksum.n"
 
@@ -62,6 +68,7 @@ const (
 	PutLatencyTotal    = "put.ns.total"
 	PutE2ELatencyTotal = "e2e.put.ns.total" // end to end (e2e) write-through PUT latency
 	AppendLatency      = "append.ns"
+	ReplLag            = "repl.lag.ns" // time between object's last modification and its replication
 	GetRedirLatency    = "get.redir.ns"
 	PutRedirLatency    = "put.redir.ns"
 	DloadLatencyTotal  = "dl.ns.total"
@@ -317,6 +324,12 @@ func (r *Trunner) RegMetrics(snode *meta.Snode) {
 			VarLabs: BckVarlabs,
 		},
 	)
+	r.reg(snode, ReplLag, KindLatency,
+		&Extra{
+			Help:    "replication lag: average time (milliseconds) between object's last modification and its replication to the remote cluster",
+			VarLabs: BckVarlabs,
+		},
+	)
 	r.reg(snode, GetRedirLatency, KindLatency,
 		&Extra{
 			Help: "GET: average gateway-to-target HTTP redirect latency (milliseconds) over the last periodic.stats_time interval",
@@ -355,6 +368,32 @@ func (r *Trunner) RegMetrics(snode *meta.Snode) {
 		},
 	)
 
+	// replication
+	r.reg(snode, ReplCount, KindCounter,
+		&Extra{
+			Help:    "number of objects replicated to the remote cluster",
+			VarLabs: BckVarlabs,
+		},
+	)
+	r.reg(snode, ReplSize, KindSize,
+		&Extra{
+			Help:    "total cumulative size (bytes) of objects replicated to the remote cluster",
+			VarLabs: BckVarlabs,
+		},
+	)
+	r.reg(snode, ReplConflictCount, KindCounter,
+		&Extra{
+			Help:    "replication: number of destination objects created or modified in the remote cluster since last replicated",
+			VarLabs: BckVarlabs,
+		},
+	)
+	r.reg(snode, ErrReplCount, KindCounter,
+		&Extra{
+			Help:    "number of replication errors",
+			VarLabs: BckVarlabs,
+		},
+	)
+
 	// errors
 	r.reg(snode, ErrPutCksumCount, KindCounter,
 		&Extra{
@@ -415,6 +454,31 @@ func (r *Trunner) RegMetrics(snode *meta.Snode) {
 			Help: "intra-cluster streaming communications: total cumulative size (bytes) of all received objects",
 		},
 	)
+	r.reg(snode, cos.StreamsAdaptGrowCount, KindCounter,
+		&Extra{
+			Help: "adaptive stream multiplier: number of times a stream was added to a given destination",
+		},
+	)
+	r.reg(snode, cos.StreamsAdaptShrinkCount, KindCounter,
+		&Extra{
+			Help: "adaptive stream multiplier: number of times a stream was removed from a given destination",
+		},
+	)
+	r.reg(snode, cos.StreamsAdaptActive, KindGauge,
+		&Extra{
+			Help: "adaptive stream multiplier: number of active streams in excess of the configured multipliers (all destinations)",
+		},
+	)
+	r.reg(snode, cos.ErrStreamsPDUCksumCount, KindCounter,
+		&Extra{
+			Help: "number of received objects with corrupted (PDU checksum mismatch) payload",
+		},
+	)
+	r.reg(snode, cos.StreamsRetransmitCount, KindCounter,
+		&Extra{
+			Help: "number of objects retransmitted upon receiver's request (due to PDU checksum mismatch)",
+		},
+	)
 
 	// download
 	r.reg(snode, DloadSize, KindSize,
@@ -17,6 +17,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn/atomic"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/mono"
 	"github.com/NVIDIA/aistore/core"
 	"github.com/NVIDIA/aistore/hk"
 	"github.com/NVIDIA/aistore/memsys"
@@ -31,6 +32,8 @@ import (
 const (
 	opcFin = iota + math.MaxUint16 - 16
 	opcIdleTick
+	OpcRetransmit // receiver => sender: retransmit corrupted object (see bundle.DataMover)
+	OpcSizeAck    // receiver => sender: actual size of the object transmitted unsized (ditto)
 )
 
 func ReservedOpcode(opc int) bool { return opc >= opcFin }
@@ -57,6 +60,9 @@ type (
 		SizePDU      int32         // NOTE: 0(zero): no PDUs; must be below maxSizePDU; unknown size _requires_ PDUs
 		MaxHdrSize   int32         // overrides config.Transport.MaxHeaderSize
 		ChanBurst    int           // overrides config.Transport.Burst
+		QUIC         bool          // HTTP/3 over QUIC in place of HTTP/1.1 over TCP (see quic.go)
+		ZstdLevel    int           // when compressed: 0 (zero) - lz4, otherwise zstd at the given level
+		Priority     Prio          // QoS class (see qos.go)
 	}
 
 	// receive-side session stats indexed by session ID (see recv.go for "uid")
@@ -79,6 +85,7 @@ type (
 		Callback ObjSentCB     // called when the last byte is sent _or_ when the stream terminates (see term.reason)
 		prc      *atomic.Int64 // private; if present, ref-counts so that we call ObjSentCB only once
 		Hdr      ObjHdr
+		enqueued int64 // private; mono-time upon Send (see Stats.Latency)
 	}
 
 	// object-sent callback that has the following signature can optionally be defined on a:
@@ -159,6 +166,7 @@ func (s *Stream) Send(obj *Obj) (err error) {
 		return
 	}
 
+	obj.enqueued = mono.NanoTime()
 	s.workCh <- obj
 	if l, c := len(s.workCh), cap(s.workCh); l > (c - c>>2) {
 		runtime.Gosched() // poor man's throttle
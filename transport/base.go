@@ -8,6 +8,7 @@ package transport
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"os"
 	"path"
@@ -69,6 +70,9 @@ type (
 	streamBase struct {
 		streamer streamer
 		client   Client        // stream's http client
+		h3       *http.Client  // non-nil when using QUIC (see quic.go)
+		encr     *encReader    // non-nil when encrypting (see crypt.go)
+		tb       *tbucket      // QoS class (see qos.go)
 		xctn     core.Xact     // xaction
 		stopCh   cos.StopCh    // stop/abort stream
 		lastCh   cos.StopCh    // end-of-stream
@@ -114,6 +118,14 @@ func newBase(client Client, dstURL, dstID string, extra *Extra) (s *streamBase)
 	debug.AssertNoErr(err)
 
 	s = &streamBase{client: client, dstURL: dstURL, dstID: dstID}
+	if extra.QUIC {
+		s.h3 = quicClient()
+	}
+	if secret := extra.Config.Transport.Secret; secret != "" {
+		s.encr = newEncReader(secret)
+	}
+
+	s.tb = bucket(extra.Priority)
 
 	s.sessID = nextSessionID.Inc()
 	s.trname = path.Base(u.Path)
@@ -128,13 +140,17 @@ func newBase(client Client, dstURL, dstID string, extra *Extra) (s *streamBase)
 		sid = "-" + extra.Xact.ID()
 	}
 	// NOTE: PDU-based traffic - a MUST-have for "unsized" transmissions
+	// (and for checksummed PDUs - see config.Transport.CksumPDU)
+	if extra.Config.Transport.CksumPDU && !extra.UsePDU() {
+		extra.SizePDU = dfltSizePDU
+	}
 	if extra.UsePDU() {
 		if extra.SizePDU > maxSizePDU {
 			debug.Assert(false)
 			extra.SizePDU = maxSizePDU
 		}
 		buf, _ := g.mm.AllocSize(int64(extra.SizePDU))
-		s.pdu = newSendPDU(buf)
+		s.pdu = newSendPDU(buf, extra.Config.Transport.CksumPDU)
 	}
 	if extra.IdleTeardown > 0 {
 		s.time.idleTeardown = extra.IdleTeardown
@@ -234,9 +250,13 @@ func (s *streamBase) GetStats() (stats Stats) {
 	stats.Offset.Store(s.stats.Offset.Load())
 	stats.Size.Store(s.stats.Size.Load())
 	stats.CompressedSize.Store(s.stats.CompressedSize.Load())
+	stats.Latency.Store(s.stats.Latency.Load())
 	return
 }
 
+// number of times the send queue (SQ) was found full - backpressure
+func (s *streamBase) ChanFull() int64 { return s.chanFull.Load() }
+
 func (s *streamBase) isNextReq() (reason string) {
 	for {
 		select {
@@ -345,7 +365,24 @@ func (extra *Extra) Compressed() bool {
 func (extra *Extra) Lid(sb *strings.Builder) {
 	if extra.Compressed() {
 		sb.WriteByte('[')
-		sb.WriteString(cos.ToSizeIEC(int64(extra.Config.Transport.LZ4BlockMaxSize), 0))
+		if extra.ZstdLevel > 0 {
+			sb.WriteString(apc.ZstdCompression)
+			sb.WriteByte(':')
+			sb.WriteString(strconv.Itoa(extra.ZstdLevel))
+		} else {
+			sb.WriteString(cos.ToSizeIEC(int64(extra.Config.Transport.LZ4BlockMaxSize), 0))
+		}
+		sb.WriteByte(']')
+	}
+	if extra.QUIC {
+		sb.WriteString("[h3]")
+	}
+	if extra.Config.Transport.Secret != "" {
+		sb.WriteString("[enc]")
+	}
+	if extra.Priority != PrioNormal {
+		sb.WriteString("[prio-")
+		sb.WriteString(extra.Priority.String())
 		sb.WriteByte(']')
 	}
 }
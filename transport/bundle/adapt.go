@@ -0,0 +1,149 @@
+// Package bundle provides multi-streaming transport with the functionality
+// to dynamically (un)register receive endpoints, establish long-lived flows, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package bundle
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/transport"
+)
+
+// Adaptive stream multiplier: the number of (round-robin) streams to a given destination
+// varies between the configured multiplier and max-multiplier (see Args.MaxMultiplier).
+// Every adaptInterval, and separately for each destination:
+// - sample aggregated throughput, average Send-to-completion latency (queuing plus transmission,
+//   a proxy for RTT), and the number of times send queues were found full (backpressure);
+// - grow by one stream upon backpressure, or when the latency exceeds adaptQueuing times its observed
+//   minimum (the latter being the baseline, when there's no queuing);
+// - keep the added stream only if the throughput improves by at least (adaptGain - 1); otherwise,
+//   shrink back and hold for adaptHold intervals;
+// - when idle, shrink - one stream at a time - back to the configured multiplier.
+// Streams in excess of the currently active ones stay idle (and get torn down due to inactivity).
+
+const (
+	adaptInterval = 5 * time.Second
+	adaptQueuing  = 2
+	adaptGain     = 1.1
+	adaptHold     = 6
+)
+
+type (
+	adaptSample struct {
+		size int64 // bytes
+		num  int64 // objects
+		lat  int64 // cumulative latency, ns
+		full int64 // send queue full
+	}
+	adapt struct {
+		prev   adaptSample
+		tput   float64 // previous interval, bytes/s
+		minLat int64   // baseline, ns
+		hold   int     // intervals to hold
+		grown  bool    // previous interval added a stream (to be evaluated)
+	}
+)
+
+func (sb *Streams) adaptive() bool { return sb.maxmult > sb.multiplier }
+func (sb *Streams) hkName() string { return "sb-adapt-" + sb.network + "-" + sb.trname }
+
+func (sb *Streams) adaptTick(int64) time.Duration {
+	sb.smaplock.Lock()
+	for id, robin := range sb.get() {
+		if robin.adapt == nil {
+			continue
+		}
+		var (
+			n   = int(robin.n.Load())
+			cur = sample(robin.stsdest)
+			m   = robin.adapt.next(cur, adaptInterval, n, sb.multiplier, sb.maxmult)
+		)
+		if m == n {
+			continue
+		}
+		robin.n.Store(int64(m))
+		tstats := transport.StatsUpdater()
+		if m > n {
+			tstats.Inc(cos.StreamsAdaptGrowCount)
+		} else {
+			tstats.Inc(cos.StreamsAdaptShrinkCount)
+		}
+		tstats.Add(cos.StreamsAdaptActive, int64(m-n))
+		if cmn.Rom.FastV(4, cos.SmoduleTransport) {
+			nlog.Infoln(sb.String(), "=>", meta.Tname(id), "streams:", n, "=>", m)
+		}
+	}
+	sb.smaplock.Unlock()
+	return adaptInterval
+}
+
+// destination removed or bundle closed (under lock)
+func (sb *Streams) adaptDone(robin *robin) {
+	if robin == nil || robin.adapt == nil {
+		return
+	}
+	if extra := robin.n.Load() - int64(sb.multiplier); extra > 0 {
+		transport.StatsUpdater().Add(cos.StreamsAdaptActive, -extra)
+	}
+	robin.n.Store(int64(sb.multiplier))
+}
+
+// (all streams, including inactive - their stats remain unchanged)
+func sample(stsdest stsdest) (cur adaptSample) {
+	for _, s := range stsdest {
+		stats := s.GetStats()
+		cur.size += stats.Size.Load()
+		cur.num += stats.Num.Load()
+		cur.lat += stats.Latency.Load()
+		cur.full += s.ChanFull()
+	}
+	return cur
+}
+
+// given cumulative sample and current number of streams, return the next number of streams
+func (a *adapt) next(cur adaptSample, elapsed time.Duration, n, minN, maxN int) int {
+	d := adaptSample{size: cur.size - a.prev.size, num: cur.num - a.prev.num, lat: cur.lat - a.prev.lat, full: cur.full - a.prev.full}
+	a.prev = cur
+
+	// idle
+	if d.num <= 0 {
+		a.grown, a.hold, a.tput = false, 0, 0
+		if n > minN {
+			return n - 1
+		}
+		return n
+	}
+
+	var (
+		tput = float64(d.size) / elapsed.Seconds()
+		lat  = d.lat / d.num
+	)
+	if a.minLat == 0 || lat < a.minLat {
+		a.minLat = lat
+	}
+	prev := a.tput
+	a.tput = tput
+
+	switch {
+	case a.grown:
+		a.grown = false
+		if tput < prev*adaptGain {
+			a.hold = adaptHold
+			return max(n-1, minN)
+		}
+	case a.hold > 0:
+		a.hold--
+		return n
+	}
+	if n < maxN && (d.full > 0 || lat > a.minLat*adaptQueuing) {
+		a.grown = true
+		return n + 1
+	}
+	return n
+}
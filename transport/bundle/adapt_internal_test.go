@@ -0,0 +1,58 @@
+// Package bundle provides multi-streaming transport with the functionality
+// to dynamically (un)register receive endpoints, establish long-lived flows, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package bundle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/tools/tassert"
+)
+
+func TestAdaptNext(t *testing.T) {
+	const (
+		minN, maxN = 1, 4
+		elapsed    = time.Second
+		mib        = int64(1 << 20)
+	)
+	var (
+		a   adapt
+		cur adaptSample
+		n   = minN
+	)
+	tick := func(size, num, lat, full int64) int {
+		cur.size += size
+		cur.num += num
+		cur.lat += lat * num
+		cur.full += full
+		n = a.next(cur, elapsed, n, minN, maxN)
+		return n
+	}
+
+	// baseline: no queuing
+	tassert.Fatalf(t, tick(100*mib, 100, 1000, 0) == 1, "expected 1 stream, got %d", n)
+
+	// queuing => grow; throughput improves => keep growing
+	tassert.Fatalf(t, tick(100*mib, 100, 5000, 0) == 2, "expected 2 streams, got %d", n)
+	tassert.Fatalf(t, tick(150*mib, 150, 5000, 0) == 3, "expected 3 streams, got %d", n)
+
+	// no gain => shrink back and hold (despite queuing)
+	tassert.Fatalf(t, tick(150*mib, 150, 5000, 0) == 2, "expected 2 streams, got %d", n)
+	for range adaptHold {
+		tassert.Fatalf(t, tick(150*mib, 150, 5000, 1) == 2, "expected 2 streams (hold), got %d", n)
+	}
+
+	// backpressure => grow, never exceeding max
+	tassert.Fatalf(t, tick(150*mib, 150, 1000, 1) == 3, "expected 3 streams, got %d", n)
+	tassert.Fatalf(t, tick(200*mib, 200, 1000, 1) == 4, "expected 4 streams, got %d", n)
+	tassert.Fatalf(t, tick(300*mib, 300, 1000, 1) == 4, "expected max (4) streams, got %d", n)
+
+	// idle => shrink, one at a time, down to min
+	for want := maxN - 1; want >= minN; want-- {
+		tassert.Fatalf(t, tick(0, 0, 0, 0) == want, "expected %d streams, got %d", want, n)
+	}
+	tassert.Fatalf(t, tick(0, 0, 0, 0) == minN, "expected min (%d) streams, got %d", minN, n)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn/nlog"
 	"github.com/NVIDIA/aistore/core"
 	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/hk"
 	"github.com/NVIDIA/aistore/transport"
 )
 
@@ -32,7 +33,9 @@ type (
 	stsdest []*transport.Stream
 	robin   struct {
 		stsdest stsdest
-		i       atomic.Int64
+		adapt   *adapt       // adaptive mode only (see adapt.go)
+		i       atomic.Int64 // round-robin
+		n       atomic.Int64 // active streams: stsdest[:n]
 	}
 	bundle map[string]*robin // stream "bundle" indexed by node ID
 )
@@ -49,17 +52,19 @@ type (
 		extra        transport.Extra
 		rxNodeType   int // receiving nodes: [Targets, ..., AllNodes ] enum above
 		multiplier   int // optionally: multiple streams per destination (round-robin)
+		maxmult      int // adaptive mode when greater than multiplier (see adapt.go)
 		manualResync bool
 	}
 	Stats map[string]*transport.Stats // by DaemonID
 
 	Args struct {
-		Extra        *transport.Extra // additional parameters
-		Net          string           // one of cmn.KnownNetworks, empty defaults to cmn.NetIntraData
-		Trname       string           // transport endpoint name
-		Ntype        int              // core.Target (0) by default
-		Multiplier   int              // so-many TCP connections per Rx endpoint, with round-robin
-		ManualResync bool             // auto-resync by default
+		Extra         *transport.Extra // additional parameters
+		Net           string           // one of cmn.KnownNetworks, empty defaults to cmn.NetIntraData
+		Trname        string           // transport endpoint name
+		Ntype         int              // core.Target (0) by default
+		Multiplier    int              // so-many TCP connections per Rx endpoint, with round-robin
+		MaxMultiplier int              // adaptive: grow and shrink the number of streams in [Multiplier, MaxMultiplier]
+		ManualResync  bool             // auto-resync by default
 	}
 
 	ErrDestinationMissing struct {
@@ -79,6 +84,14 @@ var _ meta.Slistener = (*Streams)(nil)
 func (sb *Streams) UsePDU() bool   { return sb.extra.UsePDU() }
 func (sb *Streams) Trname() string { return sb.trname }
 
+// cumulative number of times send queues were found full (backpressure) - all destinations
+func (sb *Streams) ChanFull() (n int64) {
+	for _, robin := range sb.get() {
+		n += sample(robin.stsdest).full
+	}
+	return n
+}
+
 func New(cl transport.Client, args Args) (sb *Streams) {
 	if args.Net == "" {
 		args.Net = cmn.NetIntraData // intra-cluster default
@@ -94,6 +107,7 @@ func New(cl transport.Client, args Args) (sb *Streams) {
 	}
 	sb.extra = *args.Extra
 	sb.multiplier = cos.NonZero(args.Multiplier, int(1))
+	sb.maxmult = max(args.MaxMultiplier, sb.multiplier)
 	if sb.extra.Config == nil {
 		sb.extra.Config = cmn.GCO.Get()
 	}
@@ -111,6 +125,9 @@ func New(cl transport.Client, args Args) (sb *Streams) {
 		listeners := core.T.Sowner().Listeners()
 		listeners.Reg(sb)
 	}
+	if sb.adaptive() {
+		hk.Reg(sb.hkName()+hk.NameSuffix, sb.adaptTick, adaptInterval)
+	}
 	return
 }
 
@@ -149,12 +166,20 @@ func (sb *Streams) Close(gracefully bool) {
 		listeners := core.T.Sowner().Listeners()
 		listeners.Unreg(sb)
 	}
+	if sb.adaptive() {
+		hk.Unreg(sb.hkName() + hk.NameSuffix)
+		sb.smaplock.Lock()
+		for _, robin := range sb.get() {
+			sb.adaptDone(robin)
+		}
+		sb.smaplock.Unlock()
+	}
 }
 
 // when (nodes == nil) transmit via all established streams in a bundle
 // otherwise, restrict to the specified subset (nodes)
 func (sb *Streams) Send(obj *transport.Obj, roc cos.ReadOpenCloser, nodes ...*meta.Snode) (err error) {
-	debug.Assert(!transport.ReservedOpcode(obj.Hdr.Opcode))
+	debug.Assert(!transport.ReservedOpcode(obj.Hdr.Opcode) || obj.Hdr.Opcode == transport.OpcRetransmit)
 	streams := sb.get()
 	// validate
 	switch {
@@ -289,8 +314,8 @@ func (sb *Streams) sendOne(obj *transport.Obj, roc cos.ReadOpenCloser, robin *ro
 	}
 snd:
 	i := 0
-	if sb.multiplier > 1 {
-		i = int(robin.i.Inc()) % len(robin.stsdest)
+	if n := robin.n.Load(); n > 1 {
+		i = int(robin.i.Inc() % n)
 	}
 	s := robin.stsdest[i]
 	return s.Send(one)
@@ -386,11 +411,17 @@ func (sb *Streams) Resync() {
 		}
 
 		dstURL := si.URL(sb.network) + transport.ObjURLPath(sb.trname) // direct destination URL
-		nrobin := &robin{stsdest: make(stsdest, sb.multiplier)}
-		for k := range sb.multiplier {
+		// adaptive mode: all streams are created upfront (note that streams connect upon first send),
+		// only the first n are used
+		nrobin := &robin{stsdest: make(stsdest, sb.maxmult)}
+		for k := range sb.maxmult {
 			ns := transport.NewObjStream(sb.client, dstURL, id /*dstID*/, &sb.extra)
 			nrobin.stsdest[k] = ns
 		}
+		nrobin.n.Store(int64(sb.multiplier))
+		if sb.adaptive() {
+			nrobin.adapt = &adapt{}
+		}
 		nbundle[id] = nrobin
 	}
 	for id := range removed {
@@ -398,8 +429,8 @@ func (sb *Streams) Resync() {
 			continue
 		}
 		orobin := nbundle[id]
-		for k := range sb.multiplier {
-			os := orobin.stsdest[k]
+		sb.adaptDone(orobin)
+		for _, os := range orobin.stsdest {
 			if !os.IsTerminated() {
 				os.Stop() // the node is gone but the stream appears to be still active - stop it
 			}
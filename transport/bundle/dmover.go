@@ -8,6 +8,7 @@ package bundle
 import (
 	"errors"
 	"io"
+	"strconv"
 	"time"
 
 	"github.com/NVIDIA/aistore/api/apc"
@@ -43,8 +44,14 @@ type (
 			opened atomic.Bool
 			laterx atomic.Bool
 		}
+		retransmit func(hdr *transport.ObjHdr, tsi *meta.Snode) error
+		sizeAck    func(hdr *transport.ObjHdr, size int64)
+		rexmit     rexmit // receive side: retransmission requests (see retransmit.go)
 		sizePDU    int32
 		maxHdrSize int32
+		zstdLevel  int
+		prio       transport.Prio
+		quic       bool
 	}
 	// additional (and optional) params for new data mover instance
 	Extra struct {
@@ -54,6 +61,16 @@ type (
 		Multiplier  int
 		SizePDU     int32
 		MaxHdrSize  int32
+		QUIC        bool           // data streams only; requires config.Transport.QUIC (receive side)
+		ZstdLevel   int            // when compressing: 0 - lz4, otherwise zstd at the given level
+		Priority    transport.Prio // data streams only (see transport/qos.go)
+		// re-send the object (named by hdr) to tsi upon receiver's request - when the latter detects
+		// corrupted payload (see config.Transport.CksumPDU); nil - no retransmission
+		// (e.g., rebalance that re-sends unacknowledged objects on its own)
+		Retransmit func(hdr *transport.ObjHdr, tsi *meta.Snode) error
+		// sending side: the actual size of the object that was transmitted unsized (e.g., transformed)
+		// as reported by the receiver (see DataMover.AckSize); optional
+		SizeAck func(hdr *transport.ObjHdr, size int64)
 	}
 )
 
@@ -68,12 +85,17 @@ func NewDM(trname string, recvCB transport.RecvObj, owt cmn.OWT, extra Extra) *D
 	dm.owt = owt
 	dm.multiplier = extra.Multiplier
 	dm.sizePDU, dm.maxHdrSize = extra.SizePDU, extra.MaxHdrSize
+	dm.quic = extra.QUIC && extra.Config.Transport.QUIC
+	dm.retransmit = extra.Retransmit
+	dm.sizeAck = extra.SizeAck
+	dm.prio = extra.Priority
 	dm.stage.regout.Store(true)
 
 	if extra.Compression == "" {
 		extra.Compression = apc.CompressNever
 	}
 	dm.compression = extra.Compression
+	dm.zstdLevel = extra.ZstdLevel
 
 	dm.data.trname, dm.data.recv = trname, recvCB
 	if dm.data.net == "" {
@@ -109,7 +131,7 @@ func (dm *DataMover) Renew(trname string, recvCB transport.RecvObj, owt cmn.OWT,
 		extra.Compression = apc.CompressNever
 	}
 	debug.Assert(owt == dm.owt)
-	if dm.multiplier == extra.Multiplier && dm.compression == extra.Compression && dm.sizePDU == extra.SizePDU && dm.maxHdrSize == extra.MaxHdrSize {
+	if dm.multiplier == extra.Multiplier && dm.compression == extra.Compression && dm.zstdLevel == extra.ZstdLevel && dm.sizePDU == extra.SizePDU && dm.maxHdrSize == extra.MaxHdrSize {
 		return nil
 	}
 	nlog.Infoln("renew DM", dm.String(), "=> [", extra.Compression, extra.Multiplier, "]")
@@ -179,10 +201,14 @@ func (dm *DataMover) Open() {
 			Config:      dm.config,
 			SizePDU:     dm.sizePDU,
 			MaxHdrSize:  dm.maxHdrSize,
+			QUIC:        dm.quic,
+			ZstdLevel:   dm.zstdLevel,
+			Priority:    dm.prio,
 		},
-		Ntype:        core.Targets,
-		Multiplier:   dm.multiplier,
-		ManualResync: true,
+		Ntype:         core.Targets,
+		Multiplier:    dm.multiplier,
+		MaxMultiplier: dm.config.Transport.MaxSbundleMult, // adaptive when greater than the multiplier
+		ManualResync:  true,
 	}
 	dataArgs.Extra.Xact = dm.xctn
 	dm.data.streams = New(dm.data.client, dataArgs)
@@ -262,9 +288,17 @@ func (dm *DataMover) Abort() {
 }
 
 func (dm *DataMover) Send(obj *transport.Obj, roc cos.ReadOpenCloser, tsi *meta.Snode) (err error) {
+	if dm.xctn.IsPaused() {
+		dm.xctn.WaitPaused()
+		if dm.xctn.IsAborted() {
+			err = dm.xctn.AbortErr()
+			_doCmpl(obj, roc, err)
+			return err
+		}
+	}
 	err = dm.data.streams.Send(obj, roc, tsi)
 	if err == nil && !transport.ReservedOpcode(obj.Hdr.Opcode) {
-		dm.xctn.OutObjsAdd(1, obj.Size())
+		dm.xctn.OutObjsAdd(1, max(obj.Size(), 0)) // (unsized: see onSizeAck)
 	}
 	return
 }
@@ -277,6 +311,14 @@ func (dm *DataMover) Notif(hdr *transport.ObjHdr) error {
 	return dm.ack.streams.Send(&transport.Obj{Hdr: *hdr}, nil)
 }
 
+// data streams backpressure (see Streams.ChanFull)
+func (dm *DataMover) ChanFull() int64 {
+	if dm.data.streams == nil {
+		return 0
+	}
+	return dm.data.streams.ChanFull()
+}
+
 func (dm *DataMover) Bcast(obj *transport.Obj, roc cos.ReadOpenCloser) error {
 	return dm.data.streams.Send(obj, roc)
 }
@@ -297,16 +339,68 @@ func (dm *DataMover) quicb(time.Duration /*total*/) core.QuiRes {
 }
 
 func (dm *DataMover) wrapRecvData(hdr *transport.ObjHdr, reader io.Reader, err error) error {
+	switch {
+	case hdr.Opcode == transport.OpcRetransmit:
+		dm.stage.laterx.Store(true)
+		dm.onRetransmit(hdr)
+		return nil
+	case err != nil && transport.IsErrPDUCksum(err) && dm.reqRetransmit(hdr, err):
+		dm.stage.laterx.Store(true)
+		return nil
+	case hdr.Opcode == transport.OpcSizeAck:
+		dm.onSizeAck(hdr)
+		return nil
+	}
 	if hdr.Bck.Name != "" && hdr.ObjName != "" && hdr.ObjAttrs.Size >= 0 {
 		dm.xctn.InObjsAdd(1, hdr.ObjAttrs.Size)
 	}
-	// NOTE: in re (hdr.ObjAttrs.Size < 0) see transport.UsePDU()
+	// NOTE: in re (hdr.ObjAttrs.Size < 0) see transport.UsePDU() and AckSize below
 
 	dm.stage.laterx.Store(true)
-	return dm.data.recv(hdr, reader, err)
+	err = dm.data.recv(hdr, reader, err)
+	if err == nil && dm.rexmit.n.Load() > 0 {
+		dm.rexmit.done(hdr)
+	}
+	return err
 }
 
 func (dm *DataMover) wrapRecvACK(hdr *transport.ObjHdr, reader io.Reader, err error) error {
 	dm.stage.laterx.Store(true)
 	return dm.ack.recv(hdr, reader, err)
 }
+
+//
+// post-transform sizes: objects transmitted unsized (transport.SizeUnknown)
+//
+
+// receiving side: count the object upon receiving it in full and report its actual size back
+// to the sender via header-only transport.OpcSizeAck (compare with reqRetransmit)
+func (dm *DataMover) AckSize(hdr *transport.ObjHdr, size int64) {
+	debug.Assert(hdr.IsUnsized())
+	dm.xctn.InObjsAdd(1, size)
+
+	smap := core.T.Sowner().Get()
+	tsi := smap.GetTarget(hdr.SID)
+	if tsi == nil {
+		return
+	}
+	o := transport.AllocSend()
+	o.Hdr = transport.ObjHdr{Bck: hdr.Bck, ObjName: hdr.ObjName, Opcode: transport.OpcSizeAck}
+	o.Hdr.Opaque = strconv.AppendInt(nil, size, 10)
+	if err := dm.data.streams.Send(o, nil, tsi); err != nil {
+		nlog.Errorln(dm.String(), "failed to ack size of", hdr.Cname(), "to", tsi.StringEx(), "err:", err)
+	}
+}
+
+// sending side
+func (dm *DataMover) onSizeAck(hdr *transport.ObjHdr) {
+	size, err := strconv.ParseInt(cos.UnsafeS(hdr.Opaque), 10, 64)
+	if err != nil || size < 0 {
+		nlog.Errorln(dm.String(), "invalid size ack for", hdr.Cname(), "from", meta.Tname(hdr.SID), "err:", err)
+		return
+	}
+	dm.xctn.OutObjsAdd(0, size) // (counted upon sending - see Send)
+	if dm.sizeAck != nil {
+		dm.sizeAck(hdr, size)
+	}
+}
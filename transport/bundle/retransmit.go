@@ -0,0 +1,118 @@
+// Package bundle provides multi-streaming transport with the functionality
+// to dynamically (un)register receive endpoints, establish long-lived flows, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package bundle
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/transport"
+)
+
+// Retransmission of objects with corrupted payload (see config.Transport.CksumPDU):
+// - transport skips the object and notifies the (receiving) data mover with transport.IsErrPDUCksum error;
+// - the latter sends header-only transport.OpcRetransmit request back to the sender, via its own data streams;
+// - the sending data mover re-sends the object (see Extra.Retransmit);
+// - up to maxRetransmit times per object, after which the object is considered failed.
+
+const maxRetransmit = 3
+
+type rexmit struct {
+	m  map[string]int // cname => number of requests
+	mu sync.Mutex
+	n  atomic.Int64 // len(m)
+}
+
+// receiving side: request retransmission; return false when not supported
+func (dm *DataMover) reqRetransmit(hdr *transport.ObjHdr, err error) bool {
+	if dm.retransmit == nil {
+		return false
+	}
+	smap := core.T.Sowner().Get()
+	tsi := smap.GetTarget(hdr.SID)
+	if tsi == nil {
+		return false
+	}
+	cname := hdr.Cname()
+	if cnt := dm.rexmit.inc(cname); cnt > maxRetransmit {
+		dm.rexmit.done(hdr)
+		dm.xctn.AddErr(fmt.Errorf("%s: giving up on %s after %d retransmissions: %w", dm, cname, maxRetransmit, err))
+		return true
+	}
+
+	o := transport.AllocSend()
+	o.Hdr = transport.ObjHdr{Bck: hdr.Bck, ObjName: hdr.ObjName, Opaque: hdr.Opaque, Opcode: transport.OpcRetransmit}
+	if errS := dm.data.streams.Send(o, nil, tsi); errS != nil {
+		nlog.Errorln(dm.String(), "failed to request retransmission of", cname, "from", tsi.StringEx(), "err:", errS)
+		return false
+	}
+	// un-count (see wrapRecvData)
+	if hdr.ObjAttrs.Size > 0 {
+		dm.xctn.InObjsAdd(-1, -hdr.ObjAttrs.Size)
+	}
+	if cmn.Rom.FastV(4, cos.SmoduleTransport) {
+		nlog.Infoln(dm.String(), "requested retransmission of", cname, "from", tsi.StringEx())
+	}
+	return true
+}
+
+// sending side
+func (dm *DataMover) onRetransmit(hdr *transport.ObjHdr) {
+	if dm.retransmit == nil {
+		nlog.Warningln(dm.String(), "retransmission not supported - dropping request for", hdr.Cname())
+		return
+	}
+	smap := core.T.Sowner().Get()
+	tsi := smap.GetTarget(hdr.SID)
+	if tsi == nil {
+		nlog.Warningln(dm.String(), "requesting node", meta.Tname(hdr.SID), "not present in", smap.StringEx())
+		return
+	}
+	// NOTE: not to send (and possibly block) from inside Rx callback
+	rhdr := transport.ObjHdr{Bck: hdr.Bck, ObjName: hdr.ObjName, Opaque: hdr.Opaque}
+	go dm._retransmit(&rhdr, tsi)
+}
+
+func (dm *DataMover) _retransmit(hdr *transport.ObjHdr, tsi *meta.Snode) {
+	if err := dm.retransmit(hdr, tsi); err != nil {
+		dm.xctn.AddErr(fmt.Errorf("%s: failed to retransmit %s => %s: %w", dm, hdr.Cname(), tsi.StringEx(), err))
+		return
+	}
+	transport.StatsUpdater().Inc(cos.StreamsRetransmitCount)
+	if cmn.Rom.FastV(4, cos.SmoduleTransport) {
+		nlog.Infoln(dm.String(), "retransmitted", hdr.Cname(), "=>", tsi.StringEx())
+	}
+}
+
+////////////
+// rexmit //
+////////////
+
+func (r *rexmit) inc(cname string) (cnt int) {
+	r.mu.Lock()
+	if r.m == nil {
+		r.m = make(map[string]int, 4)
+	}
+	cnt = r.m[cname] + 1
+	r.m[cname] = cnt
+	r.n.Store(int64(len(r.m)))
+	r.mu.Unlock()
+	return cnt
+}
+
+// received (or given up)
+func (r *rexmit) done(hdr *transport.ObjHdr) {
+	r.mu.Lock()
+	delete(r.m, hdr.Cname())
+	r.n.Store(int64(len(r.m)))
+	r.mu.Unlock()
+}
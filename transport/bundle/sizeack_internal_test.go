@@ -0,0 +1,38 @@
+// Package bundle provides multi-streaming transport with the functionality
+// to dynamically (un)register receive endpoints, establish long-lived flows, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package bundle
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/core/mock"
+	"github.com/NVIDIA/aistore/tools/tassert"
+	"github.com/NVIDIA/aistore/transport"
+)
+
+func TestSizeAck(t *testing.T) {
+	var (
+		acked int64
+		xctn  = mock.NewXact(apc.ActETLBck)
+		dm    = NewDM("size-ack", nil, cmn.OwtTransform, Extra{
+			Config:  &cmn.Config{},
+			SizeAck: func(_ *transport.ObjHdr, size int64) { acked += size },
+		})
+	)
+	dm.SetXact(xctn)
+	hdr := &transport.ObjHdr{ObjName: "o", Opcode: transport.OpcSizeAck, Opaque: []byte("1234")}
+
+	tassert.CheckFatal(t, dm.wrapRecvData(hdr, nil, nil))
+	tassert.Errorf(t, acked == 1234, "expected 1234 acked bytes, got %d", acked)
+	tassert.Errorf(t, xctn.OutBytes() == 1234, "expected 1234 out bytes, got %d", xctn.OutBytes())
+	tassert.Errorf(t, xctn.InObjs() == 0, "size ack must not be counted as received object")
+
+	hdr.Opaque = []byte("-1")
+	tassert.CheckFatal(t, dm.wrapRecvData(hdr, nil, nil))
+	tassert.Errorf(t, acked == 1234, "invalid size ack must be ignored, got %d", acked)
+}
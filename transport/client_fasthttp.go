@@ -64,12 +64,12 @@ func (s *streamBase) doPlain(body io.Reader) (err error) {
 	return err
 }
 
-func (s *streamBase) doCmpr(body io.Reader) (err error) {
+func (s *streamBase) doCmpr(body io.Reader, algo string) (err error) {
 	var (
 		req  = fasthttp.AcquireRequest()
 		resp = fasthttp.AcquireResponse()
 	)
-	req.Header.Set(apc.HdrCompress, apc.LZ4Compression)
+	req.Header.Set(apc.HdrCompress, algo)
 
 	err = s._do(body, req, resp)
 
@@ -85,6 +85,9 @@ func (s *streamBase) _do(body io.Reader, req *fasthttp.Request, resp *fasthttp.R
 	req.SetBodyStream(body, -1)
 	req.Header.Set(apc.HdrSessID, strconv.FormatInt(s.sessID, 10))
 	req.Header.Set(cos.HdrUserAgent, ua)
+	if s.encr != nil {
+		req.Header.Set(apc.HdrEncrypt, apc.AES256GCM)
+	}
 
 	// do
 	err = s.client.Do(req, resp)
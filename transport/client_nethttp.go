@@ -52,12 +52,12 @@ func (s *streamBase) doPlain(body io.Reader) error {
 	return s._do(req)
 }
 
-func (s *streamBase) doCmpr(body io.Reader) error {
+func (s *streamBase) doCmpr(body io.Reader, algo string) error {
 	req, err := http.NewRequest(http.MethodPut, s.dstURL, body)
 	if err != nil {
 		return err
 	}
-	req.Header.Set(apc.HdrCompress, apc.LZ4Compression)
+	req.Header.Set(apc.HdrCompress, algo)
 	err = s._do(req)
 	s.streamer.resetCompression()
 	return err
@@ -66,6 +66,9 @@ func (s *streamBase) doCmpr(body io.Reader) error {
 func (s *streamBase) _do(req *http.Request) error {
 	req.Header.Set(apc.HdrSessID, strconv.FormatInt(s.sessID, 10))
 	req.Header.Set(cos.HdrUserAgent, ua)
+	if s.encr != nil {
+		req.Header.Set(apc.HdrEncrypt, apc.AES256GCM)
+	}
 
 	resp, err := s.client.Do(req)
 	if err != nil {
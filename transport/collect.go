@@ -9,6 +9,7 @@ import (
 	"container/heap"
 	"time"
 
+	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/atomic"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
@@ -68,6 +69,7 @@ func (gc *collector) run() {
 		select {
 		case <-gc.ticker.C:
 			gc.do()
+			qos.tick(cmn.GCO.Get())
 
 			// periodic log
 			if !gc.none.Load() {
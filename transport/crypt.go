@@ -0,0 +1,302 @@
+// Package transport provides long-lived http/tcp connections for
+// intra-cluster communications (see README for details and usage example).
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package transport
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+)
+
+// Encrypted streams - independent of (and in addition to) HTTPS - when config.Transport.Secret is set:
+// - each session (HTTP request) starts with a random salt and the sender's time;
+//   the session key is HMAC-SHA256(secret, salt | time);
+// - the rest of the (possibly, compressed) body is a sequence of AES-256-GCM sealed frames:
+//   [4-byte plaintext length | ciphertext | 16-byte tag], with the length authenticated as well,
+//   and the nonce being the frame's sequence number within the session;
+// - the session ends with an (empty) end-of-session frame - a session that ends otherwise
+//   (e.g., truncated at a frame boundary) is an error;
+// - anti-replay: receivers reject sessions older than cryptMaxSkew, and the ones
+//   they have already seen (by salt) - hence, node clocks must be synchronized;
+// - receivers reject unencrypted sessions (and vice versa) - hence, the secret must be the same
+//   cluster-wide (and changing it requires restart).
+
+const (
+	cryptSaltLen   = 16
+	cryptHdrLen    = cryptSaltLen + 8 // salt | time (Unix nanoseconds)
+	cryptLenSize   = 4
+	cryptFrameSize = 64 * 1024 // max plaintext per frame
+	cryptTagSize   = 16
+	cryptKeyInfo   = "aisnode/streams"
+
+	cryptEOS = 1 << 31 // length flag: end of session
+
+	cryptMaxSkew = 5 * time.Minute // max session age (and clock skew) upon receiving
+)
+
+var (
+	errDecrypt     = errors.New("failed to decrypt (authenticate) stream")
+	errCryptTrunc  = fmt.Errorf("%w: truncated session (no end-of-session frame)", errDecrypt)
+	errCryptReplay = fmt.Errorf("%w: replayed session", errDecrypt)
+)
+
+// (receive side) sessions seen within the last cryptMaxSkew: salt => session time
+var cryptSeen struct {
+	m      map[[cryptSaltLen]byte]int64
+	pruned int64
+	mu     sync.Mutex
+}
+
+type (
+	cryptBase struct {
+		aead  cipher.AEAD
+		nonce [12]byte
+		seq   uint64
+	}
+	// Tx: io.Reader that seals the underlying body
+	encReader struct {
+		src    io.Reader
+		secret []byte
+		plain  []byte
+		out    []byte // sealed and pending
+		off    int
+		err    error
+		eos    bool // end-of-session frame sealed
+		cryptBase
+	}
+	// Rx
+	decReader struct {
+		src    io.Reader
+		secret []byte
+		buf    []byte
+		plain  []byte // opened and pending
+		salt   [cryptSaltLen]byte
+		stime  int64 // session time
+		seen   bool  // recorded (upon the first authenticated frame)
+		eos    bool  // end-of-session frame received
+		cryptBase
+	}
+)
+
+func cryptSecret(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+// hdr: salt | time
+func (cb *cryptBase) init(secret, hdr []byte) error {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(cryptKeyInfo))
+	mac.Write(hdr)
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return err
+	}
+	cb.aead, err = cipher.NewGCM(block)
+	cb.seq = 0
+	return err
+}
+
+func (cb *cryptBase) next() []byte {
+	binary.BigEndian.PutUint64(cb.nonce[4:], cb.seq)
+	cb.seq++
+	return cb.nonce[:]
+}
+
+///////////////
+// encReader //
+///////////////
+
+func newEncReader(secret string) *encReader {
+	return &encReader{
+		secret: cryptSecret(secret),
+		plain:  make([]byte, cryptFrameSize),
+		out:    make([]byte, 0, cryptHdrLen+cryptLenSize+cryptFrameSize+cryptTagSize),
+	}
+}
+
+// new session: new salt and time (sent first) and key
+func (e *encReader) reset(src io.Reader) error {
+	e.src, e.err, e.off, e.eos = src, nil, 0, false
+	e.out = e.out[:cryptHdrLen]
+	if _, err := rand.Read(e.out[:cryptSaltLen]); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint64(e.out[cryptSaltLen:], uint64(time.Now().UnixNano()))
+	return e.init(e.secret, e.out)
+}
+
+func (e *encReader) Read(b []byte) (int, error) {
+	for e.off >= len(e.out) {
+		switch {
+		case e.eos:
+			return 0, e.err
+		case e.err == io.EOF:
+			e.seal(nil, true)
+		case e.err != nil:
+			return 0, e.err // (no end-of-session frame)
+		default:
+			n, err := e.src.Read(e.plain)
+			e.err = err
+			if n > 0 {
+				e.seal(e.plain[:n], false)
+			} else if err == nil {
+				return 0, nil
+			}
+		}
+	}
+	n := copy(b, e.out[e.off:])
+	e.off += n
+	return n, nil
+}
+
+func (e *encReader) seal(plain []byte, eos bool) {
+	hdr := uint32(len(plain))
+	if eos {
+		hdr |= cryptEOS
+	}
+	e.out = e.out[:cryptLenSize]
+	binary.BigEndian.PutUint32(e.out, hdr)
+	e.out = e.aead.Seal(e.out, e.next(), plain, e.out[:cryptLenSize])
+	e.off, e.eos = 0, eos
+}
+
+///////////////
+// decReader //
+///////////////
+
+// buf: at least cryptFrameSize + tag
+func newDecReader(src io.Reader, secret string, buf []byte) *decReader {
+	return &decReader{src: src, secret: cryptSecret(secret), buf: buf}
+}
+
+func (d *decReader) Read(b []byte) (int, error) {
+	if len(d.plain) == 0 {
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(b, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decReader) open() error {
+	if d.eos {
+		return io.EOF
+	}
+	if d.aead == nil {
+		if err := d.session(); err != nil {
+			return err
+		}
+	}
+	var hlen [cryptLenSize]byte
+	if _, err := io.ReadFull(d.src, hlen[:]); err != nil {
+		return _truncated(err)
+	}
+	var (
+		hdr  = binary.BigEndian.Uint32(hlen[:])
+		eos  = hdr&cryptEOS != 0
+		size = int(hdr &^ cryptEOS)
+	)
+	if (size == 0) != eos || size > cryptFrameSize {
+		return fmt.Errorf("%w: invalid frame size %d (eos %t)", errDecrypt, size, eos)
+	}
+	sealed := d.buf[:size+d.aead.Overhead()]
+	if _, err := io.ReadFull(d.src, sealed); err != nil {
+		return _truncated(err)
+	}
+	plain, err := d.aead.Open(sealed[:0], d.next(), sealed, hlen[:])
+	if err != nil {
+		return errDecrypt
+	}
+	if !d.seen {
+		// authenticated: salt and time are genuine
+		if err := cryptRecord(d.salt, d.stime); err != nil {
+			return err
+		}
+		d.seen = true
+	}
+	if eos {
+		d.eos = true
+		return io.EOF
+	}
+	d.plain = plain
+	return nil
+}
+
+// new session: salt, time, and key
+func (d *decReader) session() error {
+	hdr := d.buf[:cryptHdrLen]
+	if _, err := io.ReadFull(d.src, hdr); err != nil {
+		return _truncated(err)
+	}
+	copy(d.salt[:], hdr)
+	d.stime = int64(binary.BigEndian.Uint64(hdr[cryptSaltLen:]))
+	if age := time.Duration(time.Now().UnixNano() - d.stime); age > cryptMaxSkew || age < -cryptMaxSkew {
+		return fmt.Errorf("%w: session time out of range (%v)", errDecrypt, age)
+	}
+	return d.init(d.secret, hdr)
+}
+
+// reject replays; forget sessions that are too old to pass the time check anyway
+func cryptRecord(salt [cryptSaltLen]byte, stime int64) error {
+	now := time.Now().UnixNano()
+	cryptSeen.mu.Lock()
+	defer cryptSeen.mu.Unlock()
+	if cryptSeen.m == nil {
+		cryptSeen.m = make(map[[cryptSaltLen]byte]int64, 64)
+	}
+	if time.Duration(now-cryptSeen.pruned) > cryptMaxSkew {
+		for k, t := range cryptSeen.m {
+			if time.Duration(now-t) > cryptMaxSkew {
+				delete(cryptSeen.m, k)
+			}
+		}
+		cryptSeen.pruned = now
+	}
+	if _, ok := cryptSeen.m[salt]; ok {
+		return errCryptReplay
+	}
+	cryptSeen.m[salt] = stime
+	return nil
+}
+
+// (not to be mistaken for the normal end of stream - see cos.IsEOF)
+func _truncated(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errCryptTrunc
+	}
+	return err
+}
+
+// (receive side) encrypted iff the cluster is configured to do so
+func rxCrypt(hdr, secret string) error {
+	switch {
+	case secret != "" && hdr != apc.AES256GCM:
+		return errors.New("transport: expecting encrypted stream (" + apc.AES256GCM + "), got " + _algo(hdr))
+	case secret == "" && hdr != "":
+		return errors.New("transport: received encrypted stream (" + hdr + ") while transport.secret is not configured")
+	}
+	return nil
+}
+
+func _algo(hdr string) string {
+	if hdr == "" {
+		return "plain"
+	}
+	return hdr
+}
@@ -0,0 +1,111 @@
+// Package transport provides long-lived http/tcp connections for
+// intra-cluster communications (see README for details and usage example).
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package transport
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/tools/tassert"
+)
+
+const cryptTestSecret = "intra-cluster-streams-secret"
+
+// sealed session: header, frames, and end-of-session frame
+func cryptSeal(t *testing.T, plain []byte, stime time.Time) []byte {
+	e := newEncReader(cryptTestSecret)
+	tassert.CheckFatal(t, e.reset(bytes.NewReader(plain)))
+	if !stime.IsZero() {
+		binary.BigEndian.PutUint64(e.out[cryptSaltLen:], uint64(stime.UnixNano()))
+		tassert.CheckFatal(t, e.init(e.secret, e.out[:cryptHdrLen]))
+	}
+	sealed, err := io.ReadAll(e)
+	tassert.CheckFatal(t, err)
+	return sealed
+}
+
+func cryptOpen(sealed []byte) ([]byte, error) {
+	d := newDecReader(bytes.NewReader(sealed), cryptTestSecret, make([]byte, cryptFrameSize+cryptTagSize))
+	return io.ReadAll(d)
+}
+
+func TestCryptSession(t *testing.T) {
+	plain := make([]byte, 3*cryptFrameSize+100)
+	_, _ = rand.Read(plain)
+	sealed := cryptSeal(t, plain, time.Time{})
+
+	frame := func(n int) int { return cryptLenSize + n + cryptTagSize }
+	size := cryptHdrLen + 3*frame(cryptFrameSize) + frame(100) + frame(0)
+	tassert.Fatalf(t, len(sealed) == size, "expected %d sealed bytes, got %d", size, len(sealed))
+
+	opened, err := cryptOpen(sealed)
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, bytes.Equal(opened, plain), "opened session differs")
+
+	// replay
+	_, err = cryptOpen(sealed)
+	tassert.Errorf(t, errors.Is(err, errCryptReplay), "expected replay to be rejected, got %v", err)
+
+	// empty session
+	opened, err = cryptOpen(cryptSeal(t, nil, time.Time{}))
+	tassert.Errorf(t, err == nil && len(opened) == 0, "expected empty session, got %d bytes, err %v", len(opened), err)
+}
+
+func TestCryptTruncated(t *testing.T) {
+	plain := make([]byte, 2*cryptFrameSize)
+	_, _ = rand.Read(plain)
+	sealed := cryptSeal(t, plain, time.Time{})
+	eos := len(sealed) - cryptLenSize - cryptTagSize
+
+	for _, n := range []int{eos, eos - cryptLenSize - cryptFrameSize - cryptTagSize, cryptHdrLen} {
+		sealed = cryptSeal(t, plain, time.Time{}) // (a new session each time - not a replay)
+		_, err := cryptOpen(sealed[:n])
+		tassert.Errorf(t, errors.Is(err, errCryptTrunc), "truncated at %d (frame boundary): expected %v, got %v", n, errCryptTrunc, err)
+	}
+	sealed = cryptSeal(t, plain, time.Time{})
+	_, err := cryptOpen(sealed[:eos-1])
+	tassert.Errorf(t, errors.Is(err, errCryptTrunc) && !cos.IsEOF(err), "truncated mid-frame: expected %v, got %v", errCryptTrunc, err)
+}
+
+func TestCryptTampered(t *testing.T) {
+	plain := make([]byte, cryptFrameSize+1)
+	_, _ = rand.Read(plain)
+
+	// data frame marked as the last one
+	sealed := cryptSeal(t, plain, time.Time{})
+	sealed[cryptHdrLen] |= 0x80
+	_, err := cryptOpen(sealed)
+	tassert.Errorf(t, errors.Is(err, errDecrypt), "expected %v, got %v", errDecrypt, err)
+
+	// end-of-session frame dropped, along with the last data frame
+	sealed = cryptSeal(t, plain, time.Time{})
+	eos := len(sealed) - cryptLenSize - cryptTagSize
+	sealed = append(sealed[:eos-cryptLenSize-1-cryptTagSize], sealed[eos:]...)
+	_, err = cryptOpen(sealed)
+	tassert.Errorf(t, errors.Is(err, errDecrypt), "expected %v, got %v", errDecrypt, err)
+
+	// session time
+	sealed = cryptSeal(t, plain, time.Time{})
+	binary.BigEndian.PutUint64(sealed[cryptSaltLen:], uint64(time.Now().Add(-time.Second).UnixNano()))
+	_, err = cryptOpen(sealed)
+	tassert.Errorf(t, errors.Is(err, errDecrypt), "expected %v, got %v", errDecrypt, err)
+}
+
+func TestCryptStale(t *testing.T) {
+	for _, d := range []time.Duration{-cryptMaxSkew - time.Minute, cryptMaxSkew + time.Minute} {
+		sealed := cryptSeal(t, []byte("stale"), time.Now().Add(d))
+		_, err := cryptOpen(sealed)
+		tassert.Errorf(t, errors.Is(err, errDecrypt) && !errors.Is(err, errCryptReplay), "%v: expected session to be rejected, got %v", d, err)
+	}
+	opened, err := cryptOpen(cryptSeal(t, []byte("skewed"), time.Now().Add(time.Minute)))
+	tassert.Errorf(t, err == nil && string(opened) == "skewed", "expected skew within range to be tolerated, got %v", err)
+}
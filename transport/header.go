@@ -15,6 +15,8 @@ import (
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/cmn/xoshiro256"
+
+	"github.com/OneOfOne/xxhash"
 )
 
 // proto header
@@ -24,12 +26,16 @@ const (
 	pduFl                                  // is PDU
 	pduLastFl                              // is last PDU
 	pduStreamFl                            // PDU-based stream
+	pduCksumFl                             // PDU payload checksum follows (see sizePDUCksum)
 
 	// NOTE: update when adding/changing flags :NOTE
-	allFlags = msgFl | pduFl | pduLastFl | pduStreamFl
+	allFlags = msgFl | pduFl | pduLastFl | pduStreamFl | pduCksumFl
 
 	// all 3 headers
 	sizeProtoHdr = cos.SizeofI64 * 2
+
+	// (optional) xxhash of the PDU payload
+	sizePDUCksum = cos.SizeofI64
 )
 
 ////////////////////////////////
@@ -64,6 +70,10 @@ func (pdu *spdu) insHeader() {
 	if pdu.last {
 		word1 |= pduLastFl
 	}
+	if pdu.hsize > sizeProtoHdr {
+		word1 |= pduCksumFl
+		insUint64(sizeProtoHdr, buf, xxhash.Checksum64S(buf[pdu.hsize:pdu.woff], cos.MLCG32))
+	}
 	insUint64(0, buf, word1)
 	checksum := xoshiro256.Hash(word1)
 	insUint64(cos.SizeofI64, buf, checksum)
@@ -14,6 +14,7 @@ package transport_test
 // go test -v -run=Multi -tags=debug
 
 import (
+	"bytes"
 	cryptorand "crypto/rand"
 	"encoding/binary"
 	"flag"
@@ -21,6 +22,7 @@ import (
 	"io"
 	"math"
 	"math/rand/v2"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -368,6 +370,42 @@ func TestSendCallback(t *testing.T) {
 	}
 }
 
+func TestQUIC(t *testing.T) {
+	objectCnt := 1000
+	if testing.Short() {
+		objectCnt = 100
+	}
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	tassert.CheckFatal(t, err)
+	addr := pc.LocalAddr().String()
+	pc.Close()
+
+	go transport.ListenQUIC(addr, nil)
+	defer transport.ShutdownQUIC()
+	time.Sleep(200 * time.Millisecond)
+
+	totalRecv, recvFunc := makeRecvFunc(t)
+	trname := "quic"
+	err = transport.Handle(trname, recvFunc)
+	tassert.CheckFatal(t, err)
+	defer transport.Unhandle(trname)
+
+	url := "http://" + addr + transport.ObjURLPath(trname) // (scheme gets replaced)
+	stream := transport.NewObjStream(transport.NewIntraDataClient(), url, cos.GenTie(), &transport.Extra{QUIC: true})
+
+	var totalSend int64
+	random := newRand(mono.NanoTime())
+	for range objectCnt {
+		hdr, rr := makeRandReader(random, false)
+		totalSend += hdr.ObjAttrs.Size
+		stream.Send(&transport.Obj{Hdr: hdr, Reader: rr})
+	}
+	stream.Fin()
+	if *totalRecv != totalSend {
+		t.Fatalf("total received bytes %d is different from expected: %d", *totalRecv, totalSend)
+	}
+}
+
 func _ptrstr(s string) *string { return &s }
 
 func TestObjAttrs(t *testing.T) {
@@ -448,6 +486,161 @@ func TestObjAttrs(t *testing.T) {
 	}
 }
 
+func TestCompressedZstd(t *testing.T) {
+	objectCnt := 1000
+	if testing.Short() {
+		objectCnt = 100
+	}
+	ts := httptest.NewServer(objmux)
+	defer ts.Close()
+
+	totalRecv, recvFunc := makeRecvFunc(t)
+	trname := "cmpr-zstd"
+	err := transport.Handle(trname, recvFunc)
+	tassert.CheckFatal(t, err)
+	defer transport.Unhandle(trname)
+
+	url := ts.URL + transport.ObjURLPath(trname)
+	extra := &transport.Extra{Compression: apc.CompressAlways, ZstdLevel: 3}
+	stream := transport.NewObjStream(transport.NewIntraDataClient(), url, cos.GenTie(), extra)
+
+	var totalSend int64
+	random := newRand(mono.NanoTime())
+	for range objectCnt {
+		hdr, rr := makeRandReader(random, false)
+		totalSend += hdr.ObjAttrs.Size
+		stream.Send(&transport.Obj{Hdr: hdr, Reader: rr})
+	}
+	stream.Fin()
+	if *totalRecv != totalSend {
+		t.Fatalf("total received bytes %d is different from expected: %d", *totalRecv, totalSend)
+	}
+	stats := stream.GetStats()
+	tlog.Logf("zstd: sent %s, compression ratio %.2f\n", cos.ToSizeIEC(totalSend, 1), stats.CompressionRatio())
+}
+
+func TestEncrypted(t *testing.T) {
+	objectCnt := 1000
+	if testing.Short() {
+		objectCnt = 100
+	}
+	config := cmn.GCO.BeginUpdate()
+	config.Transport.Secret = "intra-cluster-streams-secret"
+	cmn.GCO.CommitUpdate(config)
+	defer func() {
+		config := cmn.GCO.BeginUpdate()
+		config.Transport.Secret = ""
+		cmn.GCO.CommitUpdate(config)
+	}()
+
+	ts := httptest.NewServer(objmux)
+	defer ts.Close()
+
+	for _, extra := range []*transport.Extra{{}, {Compression: apc.CompressAlways, ZstdLevel: 3}} {
+		totalRecv, recvFunc := makeRecvFunc(t)
+		trname := "encrypted"
+		if extra.Compressed() {
+			trname += "-zstd"
+		}
+		err := transport.Handle(trname, recvFunc)
+		tassert.CheckFatal(t, err)
+
+		url := ts.URL + transport.ObjURLPath(trname)
+		stream := transport.NewObjStream(transport.NewIntraDataClient(), url, cos.GenTie(), extra)
+
+		var totalSend int64
+		random := newRand(mono.NanoTime())
+		for range objectCnt {
+			hdr, rr := makeRandReader(random, false)
+			totalSend += hdr.ObjAttrs.Size
+			stream.Send(&transport.Obj{Hdr: hdr, Reader: rr})
+		}
+		stream.Fin()
+		transport.Unhandle(trname)
+		if *totalRecv != totalSend {
+			t.Fatalf("%s: total received bytes %d is different from expected: %d", stream, *totalRecv, totalSend)
+		}
+	}
+}
+
+// flips one bit at a given offset of the (first) request body
+type flipper struct {
+	io.ReadCloser
+	off, at int64
+	done    *atomic.Bool
+}
+
+func (f *flipper) Read(b []byte) (n int, err error) {
+	n, err = f.ReadCloser.Read(b)
+	if f.at >= f.off && f.at < f.off+int64(n) && f.done.CAS(false, true) {
+		b[f.at-f.off] ^= 0x10
+	}
+	f.off += int64(n)
+	return n, err
+}
+
+func TestCksumPDU(t *testing.T) {
+	const (
+		objSize = cos.MiB
+		objCnt  = 8
+		flipAt  = 200_000 // within the first object's payload (and not PDU header)
+	)
+	config := cmn.GCO.BeginUpdate()
+	config.Transport.CksumPDU = true
+	cmn.GCO.CommitUpdate(config)
+	defer func() {
+		config := cmn.GCO.BeginUpdate()
+		config.Transport.CksumPDU = false
+		cmn.GCO.CommitUpdate(config)
+	}()
+
+	var flipped atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = &flipper{ReadCloser: r.Body, at: flipAt, done: &flipped}
+		objmux.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	var numOK, numBad atomic.Int64
+	recvFunc := func(hdr *transport.ObjHdr, objReader io.Reader, err error) error {
+		switch {
+		case transport.IsErrPDUCksum(err):
+			tassert.Errorf(t, objReader == nil && hdr.ObjName == "o-0", "unexpected corrupted %s", hdr.Cname())
+			numBad.Inc()
+			return nil
+		case err != nil:
+			return err
+		}
+		if n, err := io.Copy(io.Discard, objReader); err == nil && n == hdr.ObjAttrs.Size {
+			numOK.Inc()
+		}
+		return nil
+	}
+	trname := "cksum-pdu"
+	err := transport.Handle(trname, recvFunc)
+	tassert.CheckFatal(t, err)
+	defer transport.Unhandle(trname)
+
+	url := ts.URL + transport.ObjURLPath(trname)
+	stream := transport.NewObjStream(transport.NewIntraDataClient(), url, cos.GenTie(), nil)
+
+	data := make([]byte, objSize)
+	_, _ = cryptorand.Read(data)
+	for i := range objCnt {
+		hdr := transport.ObjHdr{
+			Bck:      cmn.Bck{Name: "cksum-pdu", Provider: apc.AIS},
+			ObjName:  "o-" + strconv.Itoa(i),
+			ObjAttrs: cmn.ObjAttrs{Size: objSize},
+		}
+		stream.Send(&transport.Obj{Hdr: hdr, Reader: io.NopCloser(bytes.NewReader(data))})
+	}
+	stream.Fin()
+
+	tassert.Fatalf(t, flipped.Load(), "expected corrupted payload")
+	tassert.Errorf(t, numBad.Load() == 1, "expected exactly one corrupted object, got %d", numBad.Load())
+	tassert.Errorf(t, numOK.Load() == objCnt-1, "expected %d objects received intact, got %d", objCnt-1, numOK.Load())
+}
+
 func receive10G(hdr *transport.ObjHdr, objReader io.Reader, err error) error {
 	cos.Assert(err == nil || cos.IsEOF(err))
 	written, _ := io.Copy(io.Discard, objReader)
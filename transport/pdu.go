@@ -6,40 +6,50 @@
 package transport
 
 import (
+	"errors"
 	"fmt"
 	"io"
 
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/memsys"
+
+	"github.com/OneOfOne/xxhash"
 )
 
 type (
 	pdu struct {
-		buf  []byte
-		roff int
-		woff int
-		done bool
-		last bool
+		buf   []byte
+		roff  int
+		woff  int
+		hsize int // sizeProtoHdr, plus sizePDUCksum when checksummed
+		done  bool
+		last  bool
 	}
 	spdu struct {
 		pdu
 	}
 	rpdu struct {
 		body io.Reader
+		bad  *ObjHdr // object with corrupted (and discarded) payload - to skip (see iterator.rxBad)
 		pdu
 		flags uint64
+		cksum uint64
 		plen  int
 	}
 )
 
+var errPDUCksum = errors.New("PDU checksum mismatch")
+
+func IsErrPDUCksum(err error) bool { return errors.Is(err, errPDUCksum) }
+
 /////////
 // pdu //
 /////////
 
-func (pdu *pdu) plength() int { return pdu.woff - sizeProtoHdr } // just the payload
-func (pdu *pdu) slength() int { return pdu.roff - sizeProtoHdr } // payload transmitted/received so far
-func (pdu *pdu) rlength() int { return pdu.woff - pdu.roff }     // not yet sent/received part of the PDU
+func (pdu *pdu) plength() int { return pdu.woff - pdu.hsize } // just the payload
+func (pdu *pdu) slength() int { return pdu.roff - pdu.hsize } // payload transmitted/received so far
+func (pdu *pdu) rlength() int { return pdu.woff - pdu.roff }  // not yet sent/received part of the PDU
 
 func (pdu *pdu) read(b []byte) (n int) {
 	n = copy(b, pdu.buf[pdu.roff:pdu.woff])
@@ -57,9 +67,12 @@ func (pdu *pdu) free(mm *memsys.MMSA) {
 // spdu //
 //////////
 
-func newSendPDU(buf []byte) (p *spdu) {
+func newSendPDU(buf []byte, cksum bool) (p *spdu) {
 	debug.Assert(len(buf) >= cos.KiB && len(buf) <= maxSizePDU)
-	p = &spdu{pdu{buf: buf}}
+	p = &spdu{pdu{buf: buf, hsize: sizeProtoHdr}}
+	if cksum {
+		p.hsize += sizePDUCksum
+	}
 	p.reset()
 	return
 }
@@ -82,7 +95,7 @@ func (pdu *spdu) readFrom(sendoff *sendoff) (err error) {
 }
 
 func (pdu *spdu) reset() {
-	pdu.roff, pdu.woff = 0, sizeProtoHdr
+	pdu.roff, pdu.woff = 0, pdu.hsize
 	pdu.done, pdu.last = false, false
 }
 
@@ -111,32 +124,69 @@ func (pdu *rpdu) readHdr(loghdr string) (err error) {
 	if err != nil {
 		return
 	}
-	if pdu.flags&pduFl == 0 || pdu.plen > maxSizePDU || pdu.plen < 0 {
+	if pdu.flags&pduCksumFl != 0 {
+		pdu.hsize = sizeProtoHdr + sizePDUCksum
+	}
+	if pdu.flags&pduFl == 0 || pdu.hsize+pdu.plen > maxSizePDU || pdu.plen < 0 {
 		err = fmt.Errorf(fmterr, loghdr, pdu.plen, fl2s(pdu.flags))
 		debug.AssertNoErr(err)
 		return
 	}
-	pdu.woff = sizeProtoHdr
+	if pdu.hsize > sizeProtoHdr {
+		if _, err = io.ReadFull(pdu.body, pdu.buf[sizeProtoHdr:pdu.hsize]); err != nil {
+			return fmt.Errorf("sbrk %s: failed to receive PDU checksum: %w", loghdr, err)
+		}
+		_, pdu.cksum = extUint64(sizeProtoHdr, pdu.buf)
+	}
+	pdu.woff, pdu.roff = pdu.hsize, pdu.hsize
 	pdu.last = pdu.flags&pduLastFl != 0
 	debug.Assertf(pdu.plen > 0 || (pdu.plen == 0 && pdu.last), fmterr, loghdr, pdu.plen, fl2s(pdu.flags))
 	return
 }
 
 func (pdu *rpdu) reset() {
-	pdu.roff, pdu.woff = sizeProtoHdr, 0
+	pdu.roff, pdu.woff, pdu.hsize = sizeProtoHdr, 0, sizeProtoHdr
 	pdu.done, pdu.last = false, false
 }
 
 func (pdu *rpdu) readFrom() (n int, err error) {
-	n, err = pdu.body.Read(pdu.buf[pdu.woff : sizeProtoHdr+pdu.plen]) // NOTE: maxSizePDU
+	n, err = pdu.body.Read(pdu.buf[pdu.woff : pdu.hsize+pdu.plen]) // NOTE: maxSizePDU
 	pdu.woff += n
 	pdu.done = pdu.plength() == pdu.plen
 	if err != nil {
 		pdu.done, pdu.last = true, true
+		return
+	}
+	// validate and, if corrupted, discard the payload
+	if pdu.done && pdu.flags&pduCksumFl != 0 {
+		if xxhash.Checksum64S(pdu.buf[pdu.hsize:pdu.woff], cos.MLCG32) != pdu.cksum {
+			pdu.roff = pdu.woff
+			err = errPDUCksum
+		}
 	}
 	return
 }
 
+// skip the remaining PDUs of the object that has been (partially) received
+func (pdu *rpdu) skip(loghdr string) error {
+	for {
+		if pdu.woff == 0 {
+			if err := pdu.readHdr(loghdr); err != nil {
+				return err
+			}
+		}
+		for !pdu.done {
+			if _, err := pdu.readFrom(); err != nil && err != io.EOF && err != errPDUCksum {
+				return err
+			}
+		}
+		if pdu.last {
+			return nil
+		}
+		pdu.reset()
+	}
+}
+
 //
 // misc
 //
@@ -153,6 +203,9 @@ func fl2s(flags uint64) (s string) {
 	if flags&pduStreamFl != 0 {
 		s += "[pdu-stream]"
 	}
+	if flags&pduCksumFl != 0 {
+		s += "[cksum]"
+	}
 	if flags&pduLastFl != 0 {
 		s += "[lst]"
 	}
@@ -0,0 +1,169 @@
+// Package transport provides long-lived http/tcp connections for
+// intra-cluster communications (see README for details and usage example).
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package transport
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/mono"
+)
+
+// QoS: sender-side priority classes (see Extra.Priority)
+// - enabled when config.Transport.QoSBandwidth (the node's egress budget for all streams) is non-zero;
+// - each class has its own token bucket; the highest class is never throttled (preempts),
+//   while each lower class gets the budget that remains after the classes above it - as observed
+//   during the previous tick - but not less than qosMinShare of the total (no starvation);
+// - throttling applies to object payload (as it is being read - see Stream.Read).
+
+type Prio int8
+
+const (
+	PrioLow    Prio = -1 // background: rebalance, copy-bucket, etc.
+	PrioNormal Prio = 0  // default
+	PrioHigh   Prio = 1  // user-facing (e.g., GET-triggered)
+
+	numPrio = 3
+)
+
+const (
+	qosMinShare = 0.1
+	qosBurst    = 100 * time.Millisecond // bucket capacity, in seconds of the (current) rate
+	qosMaxSleep = 100 * time.Millisecond // max wait per read (and see Stream.Read)
+)
+
+type (
+	tbucket struct {
+		mu     sync.Mutex
+		rate   float64 // bytes per second; zero - unlimited
+		tokens float64
+		last   int64        // mono
+		sent   atomic.Int64 // cumulative
+		on     atomic.Bool  // rate > 0
+	}
+	qosCtl struct {
+		buckets [numPrio]tbucket
+		prev    [numPrio]int64
+		last    int64
+	}
+)
+
+var qos qosCtl
+
+func (p Prio) String() string {
+	switch p {
+	case PrioLow:
+		return "low"
+	case PrioHigh:
+		return "high"
+	default:
+		return "normal"
+	}
+}
+
+func (p Prio) idx() int { return int(p - PrioLow) }
+
+func bucket(p Prio) *tbucket {
+	p = max(min(p, PrioHigh), PrioLow)
+	return &qos.buckets[p.idx()]
+}
+
+/////////////
+// tbucket //
+/////////////
+
+// consume n bytes; return the time to wait (zero when not throttled)
+func (tb *tbucket) take(n int) time.Duration {
+	tb.sent.Add(int64(n))
+	if !tb.on.Load() {
+		return 0
+	}
+	tb.mu.Lock()
+	if tb.rate == 0 {
+		tb.mu.Unlock()
+		return 0
+	}
+	now := mono.NanoTime()
+	if tb.last != 0 {
+		capacity := tb.rate * qosBurst.Seconds()
+		tb.tokens = min(tb.tokens+tb.rate*time.Duration(now-tb.last).Seconds(), capacity)
+	}
+	tb.last = now
+	tb.tokens -= float64(n)
+	tokens, rate := tb.tokens, tb.rate
+	tb.mu.Unlock()
+
+	if tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-tokens / rate * float64(time.Second))
+}
+
+func (tb *tbucket) setRate(rate float64) {
+	tb.mu.Lock()
+	switch {
+	case tb.rate == rate:
+	case tb.rate == 0 || rate == 0:
+		tb.rate, tb.tokens, tb.last = rate, 0, 0
+		tb.on.Store(rate > 0)
+	default:
+		tb.rate, tb.tokens = rate, min(tb.tokens, rate*qosBurst.Seconds())
+	}
+	tb.mu.Unlock()
+}
+
+////////////
+// qosCtl //
+////////////
+
+// (called by stream collector every tick)
+func (q *qosCtl) tick(config *cmn.Config) {
+	var (
+		now     = mono.NanoTime()
+		elapsed = time.Duration(now - q.last)
+		bw      = float64(config.Transport.QoSBandwidth)
+		tput    [numPrio]float64
+	)
+	q.last = now
+	for i := range q.buckets {
+		sent := q.buckets[i].sent.Load()
+		if elapsed > 0 {
+			tput[i] = float64(sent-q.prev[i]) / elapsed.Seconds()
+		}
+		q.prev[i] = sent
+	}
+	rates := qosRates(bw, tput)
+	for i := range q.buckets {
+		q.buckets[i].setRate(rates[i])
+	}
+}
+
+// given the node's budget and per-class throughput, compute per-class rates (zero - unlimited)
+func qosRates(bw float64, tput [numPrio]float64) (rates [numPrio]float64) {
+	if bw <= 0 {
+		return rates
+	}
+	avail := bw
+	for i := numPrio - 1; i >= 0; i-- {
+		if i < numPrio-1 {
+			rates[i] = max(avail, bw*qosMinShare)
+		}
+		avail -= tput[i]
+	}
+	return rates
+}
+
+// (stream) throttle upon sending n bytes
+func (s *streamBase) throttle(n int) {
+	if n <= 0 {
+		return
+	}
+	if d := s.tb.take(n); d > 0 {
+		time.Sleep(min(d, qosMaxSleep))
+	}
+}
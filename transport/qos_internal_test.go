@@ -0,0 +1,61 @@
+// Package transport provides long-lived http/tcp connections for
+// intra-cluster communications (see README for details and usage example).
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package transport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/tools/tassert"
+)
+
+func TestQoSRates(t *testing.T) {
+	const bw = 1000.0
+	var (
+		low    = PrioLow.idx()
+		normal = PrioNormal.idx()
+		high   = PrioHigh.idx()
+	)
+	// disabled
+	rates := qosRates(0, [numPrio]float64{})
+	tassert.Fatalf(t, rates == [numPrio]float64{}, "expected no limits, got %v", rates)
+
+	// idle: full budget for all but the highest class (unlimited)
+	rates = qosRates(bw, [numPrio]float64{})
+	tassert.Fatalf(t, rates[high] == 0 && rates[normal] == bw && rates[low] == bw, "idle: %v", rates)
+
+	// high-priority traffic preempts
+	tput := [numPrio]float64{}
+	tput[high] = 600
+	rates = qosRates(bw, tput)
+	tassert.Fatalf(t, rates[high] == 0 && rates[normal] == 400 && rates[low] == 400, "high=600: %v", rates)
+
+	tput[normal] = 300
+	rates = qosRates(bw, tput)
+	tassert.Fatalf(t, rates[normal] == 400 && rates[low] == 100, "high=600, normal=300: %v", rates)
+
+	// no starvation
+	tput[high] = 2 * bw
+	rates = qosRates(bw, tput)
+	tassert.Fatalf(t, rates[normal] == bw*qosMinShare && rates[low] == bw*qosMinShare, "high=2*bw: %v", rates)
+}
+
+func TestQoSBucket(t *testing.T) {
+	var tb tbucket
+	tassert.Fatalf(t, tb.take(1<<20) == 0, "expected no throttling when disabled")
+
+	const rate = 1 << 20 // 1MiB/s
+	tb.setRate(rate)
+	// starting with no tokens
+	d := tb.take(rate / 2)
+	tassert.Fatalf(t, d == 500*time.Millisecond, "expected 0.5s wait, got %v", d)
+	d = tb.take(rate / 2)
+	tassert.Fatalf(t, d > 900*time.Millisecond && d <= time.Second, "expected ~1s wait, got %v", d)
+
+	tb.setRate(0)
+	tassert.Fatalf(t, tb.take(rate) == 0, "expected no throttling when disabled")
+	tassert.Fatalf(t, tb.sent.Load() == 1<<20+rate+rate, "sent %d", tb.sent.Load())
+}
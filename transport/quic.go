@@ -0,0 +1,215 @@
+// Package transport provides long-lived http/tcp connections for
+// intra-cluster communications (see README for details and usage example).
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+)
+
+// QUIC (HTTP/3) streams - selectable per stream bundle (see Extra.QUIC), in place of HTTP/1.1 over TCP:
+// - streams to a given destination are multiplexed over a single QUIC connection, each in its own
+//   QUIC stream - a lost packet stalls only the stream it belongs to (no head-of-line blocking);
+// - the receiving side is the same (RxAnyStream) served over UDP on the intra-cluster data port
+//   (see ListenQUIC and config.Transport.QUIC);
+// - TLS is mandatory: the cluster's own when using HTTPS, otherwise ephemeral self-signed
+//   (not verified, same as plain intra-cluster HTTP)
+
+const quicIdleTimeout = time.Minute
+
+var (
+	quicSrv struct {
+		s  *http3.Server
+		mu sync.Mutex
+	}
+	quicCl struct {
+		c    *http.Client
+		once sync.Once
+	}
+)
+
+func quicConfig() *quic.Config {
+	return &quic.Config{
+		MaxIdleTimeout:     quicIdleTimeout,
+		KeepAlivePeriod:    quicIdleTimeout / 4,
+		MaxIncomingStreams: 1024,
+	}
+}
+
+//
+// Tx
+//
+
+// one (shared) client, connections pooled by destination
+func quicClient() *http.Client {
+	quicCl.once.Do(func() {
+		var (
+			config  = cmn.GCO.Get()
+			tlsConf *tls.Config
+		)
+		if config.Net.HTTP.UseHTTPS {
+			var err error
+			if tlsConf, err = cmn.NewTLS(config.Net.HTTP.ToTLS(), true /*intra-cluster*/); err != nil {
+				cos.ExitLog(err)
+			}
+		} else {
+			tlsConf = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // (see above)
+		}
+		tlsConf.NextProtos = []string{http3.NextProtoH3}
+		quicCl.c = &http.Client{Transport: &http3.Transport{TLSClientConfig: tlsConf, QUICConfig: quicConfig()}}
+	})
+	return quicCl.c
+}
+
+// NOTE: unlike HTTP/1.1 clients, http3 returns upon receiving response headers while
+// continuing to send request body asynchronously - hence, waiting for the latter
+// (see quicBody.Close) before resetting compression and/or starting the next session
+func (s *streamBase) doQUIC(body io.Reader, algo string) error {
+	qb := &quicBody{r: body, done: make(chan struct{})}
+	req, err := http.NewRequest(http.MethodPut, quicURL(s.dstURL), qb)
+	if err != nil {
+		return err
+	}
+	if algo != "" {
+		req.Header.Set(apc.HdrCompress, algo)
+	}
+	req.Header.Set(apc.HdrSessID, strconv.FormatInt(s.sessID, 10))
+	req.Header.Set(cos.HdrUserAgent, ua)
+	if s.encr != nil {
+		req.Header.Set(apc.HdrEncrypt, apc.AES256GCM)
+	}
+
+	resp, err := s.h3.Do(req)
+	if err == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		<-qb.done
+	}
+	if algo != "" {
+		s.streamer.resetCompression()
+	}
+	if err != nil {
+		s.yelp(err)
+	}
+	return err
+}
+
+type quicBody struct {
+	r    io.Reader
+	done chan struct{}
+	once sync.Once
+}
+
+func (qb *quicBody) Read(b []byte) (int, error) { return qb.r.Read(b) }
+
+func (qb *quicBody) Close() error {
+	qb.once.Do(func() { close(qb.done) })
+	return nil
+}
+
+// same host:port (UDP), always https
+func quicURL(dstURL string) string {
+	if rest, ok := strings.CutPrefix(dstURL, "http://"); ok {
+		return "https://" + rest
+	}
+	return dstURL
+}
+
+//
+// Rx
+//
+
+// (target only) blocks until ShutdownQUIC; nil tlsConf - ephemeral self-signed
+func ListenQUIC(addr string, tlsConf *tls.Config) error {
+	if tlsConf == nil {
+		cert, err := selfSigned()
+		if err != nil {
+			return err
+		}
+		tlsConf = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+	s := &http3.Server{
+		Addr:       addr,
+		Handler:    http.HandlerFunc(rxQUIC),
+		TLSConfig:  http3.ConfigureTLSConfig(tlsConf),
+		QUICConfig: quicConfig(),
+	}
+	quicSrv.mu.Lock()
+	quicSrv.s = s
+	quicSrv.mu.Unlock()
+
+	nlog.Infoln("QUIC (HTTP/3) streams: listening on", addr)
+	err := s.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) || errors.Is(err, quic.ErrServerClosed) {
+		return nil
+	}
+	nlog.Errorln("QUIC (HTTP/3) terminated with error:", err)
+	return err
+}
+
+func ShutdownQUIC() {
+	quicSrv.mu.Lock()
+	if quicSrv.s != nil {
+		if err := quicSrv.s.Close(); err != nil {
+			nlog.Warningln("QUIC (HTTP/3) server close err:", err)
+		}
+		quicSrv.s = nil
+	}
+	quicSrv.mu.Unlock()
+}
+
+func rxQUIC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut || !strings.HasPrefix(r.URL.Path, ObjURLPath("")+"/") {
+		cmn.WriteErr405(w, r, http.MethodPut)
+		return
+	}
+	RxAnyStream(w, r)
+}
+
+func selfSigned() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "aisnode/streams"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
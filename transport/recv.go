@@ -26,6 +26,7 @@ import (
 	"github.com/NVIDIA/aistore/hk"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/OneOfOne/xxhash"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4/v3"
 )
 
@@ -88,6 +89,7 @@ func RxAnyStream(w http.ResponseWriter, r *http.Request) {
 	var (
 		reader    io.Reader = r.Body
 		lz4Reader *lz4.Reader
+		zreader   *zstd.Decoder
 		trname    = path.Base(r.URL.Path)
 		mm        = memsys.PageMM()
 	)
@@ -111,15 +113,37 @@ func RxAnyStream(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
+	// decryption (cluster-shared key), if configured
+	config := cmn.GCO.Get()
+	if err := rxCrypt(r.Header.Get(apc.HdrEncrypt), config.Transport.Secret); err != nil {
+		cmn.WriteErr(w, r, err, http.StatusUnauthorized)
+		return
+	}
+	if config.Transport.Secret != "" {
+		cbuf, _ := mm.AllocSize(cryptFrameSize + cryptTagSize)
+		defer mm.Free(cbuf)
+		reader = newDecReader(reader, config.Transport.Secret, cbuf)
+	}
+
 	// compression
-	if compressionType := r.Header.Get(apc.HdrCompress); compressionType != "" {
-		debug.Assert(compressionType == apc.LZ4Compression)
-		lz4Reader = lz4.NewReader(r.Body)
+	switch compressionType := r.Header.Get(apc.HdrCompress); compressionType {
+	case "":
+	case apc.LZ4Compression:
+		lz4Reader = lz4.NewReader(reader)
 		reader = lz4Reader
+	case apc.ZstdCompression:
+		zreader, err = zstd.NewReader(reader, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			cmn.WriteErr(w, r, err)
+			return
+		}
+		reader = zreader
+	default:
+		cmn.WriteErr(w, r, cmn.NewErrUnsupp("decompress", compressionType))
+		return
 	}
 
 	var (
-		config             = cmn.GCO.Get()
 		stats, uid, loghdr = h.stats(r, trname)
 		it                 = &iterator{handler: h, body: reader, stats: stats}
 	)
@@ -133,6 +157,9 @@ func RxAnyStream(w http.ResponseWriter, r *http.Request) {
 	if lz4Reader != nil {
 		lz4Reader.Reset(nil)
 	}
+	if zreader != nil {
+		zreader.Close()
+	}
 	if it.pdu != nil {
 		it.pdu.free(mm)
 	}
@@ -282,6 +309,9 @@ func (it *iterator) rxObj(loghdr string, hlen int) (err error) {
 		if errCb := h.recv(&obj.hdr, obj, err); errCb != nil {
 			err = errCb
 		}
+		if it.pdu != nil && it.pdu.bad != nil {
+			return it.rxBad(loghdr)
+		}
 		// stats
 		if err == nil {
 			it.stats.incNum()                   // 1. this stream stats
@@ -302,6 +332,24 @@ func (it *iterator) rxObj(loghdr string, hlen int) (err error) {
 	return
 }
 
+// corrupted PDU (see transport.pdu_checksum): skip the rest of the object, notify Rx callback
+// (which may then request retransmission - see bundle.DataMover), and keep the session going
+func (it *iterator) rxBad(loghdr string) error {
+	hdr := it.pdu.bad
+	it.pdu.bad = nil
+	if err := it.pdu.skip(loghdr); err != nil {
+		return err
+	}
+	g.tstats.Inc(cos.ErrStreamsPDUCksumCount)
+	nlog.Warningln(loghdr, hdr.Cname(), "-", errPDUCksum)
+
+	err := fmt.Errorf("%s %s: %w", loghdr, hdr.Cname(), errPDUCksum)
+	if errCb := it.handler.recv(hdr, nil, err); errCb != nil && cmn.Rom.FastV(4, cos.SmoduleTransport) {
+		nlog.Infoln(loghdr, "Rx callback:", errCb)
+	}
+	return nil
+}
+
 func eofOK(err error) error {
 	if err == io.EOF {
 		err = nil
@@ -413,6 +461,10 @@ func (obj *objReader) readPDU(b []byte) (n int, err error) {
 	}
 	for !pdu.done {
 		if _, err = pdu.readFrom(); err != nil && err != io.EOF {
+			if err == errPDUCksum && pdu.bad == nil {
+				hdr := obj.hdr
+				pdu.bad = &hdr
+			}
 			err = fmt.Errorf("sbr8 %s: failed to receive PDU, err %w, obj %s", obj.loghdr, err, obj)
 			break
 		}
@@ -432,7 +484,7 @@ func (obj *objReader) readPDU(b []byte) (n int, err error) {
 			err = io.EOF
 			if obj.IsUnsized() {
 				obj.hdr.ObjAttrs.Size = obj.off
-			} else if obj.Size() != obj.off {
+			} else if obj.Size() != obj.off && pdu.bad == nil {
 				nlog.Errorf("sbr9 %s: off %d != %s", obj.loghdr, obj.off, obj)
 			}
 		} else {
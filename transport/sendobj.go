@@ -10,11 +10,15 @@ import (
 	"io"
 	"runtime"
 
+	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/mono"
 	"github.com/NVIDIA/aistore/cmn/nlog"
 	"github.com/NVIDIA/aistore/memsys"
+
+	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4/v3"
 )
 
@@ -24,16 +28,25 @@ type (
 		workCh   chan *Obj // aka SQ: next object to stream
 		cmplCh   chan cmpl // aka SCQ; note that SQ and SCQ together form a FIFO
 		callback ObjSentCB // to free SGLs, close files, etc.
-		lz4s     *lz4Stream
+		cmprs    *cmprStream
 		sendoff  sendoff
 		streamBase
 	}
-	lz4Stream struct {
+	// compressed stream: lz4 (default) or zstd
+	cmprStream struct {
 		s             *Stream
-		zw            *lz4.Writer // orig reader => zw
+		zw            cmprWriter  // orig reader => zw
 		sgl           *memsys.SGL // zw => bb => network
-		blockMaxSize  int         // *uncompressed* block max size
-		frameChecksum bool        // true: checksum lz4 frames
+		blockMaxSize  int         // lz4: *uncompressed* block max size
+		zstdLevel     int         // non-zero: zstd
+		frameChecksum bool        // true: checksum compressed frames
+	}
+	// common for lz4.Writer and zstd.Encoder
+	cmprWriter interface {
+		io.Writer
+		Flush() error
+		Close() error
+		Reset(io.Writer)
 	}
 	sendoff struct {
 		obj Obj
@@ -76,32 +89,33 @@ func (s *Stream) terminate(err error, reason string) (actReason string, actErr e
 	gc.remove(&s.streamBase)
 
 	if s.compressed() {
-		s.lz4s.sgl.Free()
-		if s.lz4s.zw != nil {
-			s.lz4s.zw.Reset(nil)
+		s.cmprs.sgl.Free()
+		if s.cmprs.zw != nil {
+			s.cmprs.zw.Reset(nil)
 		}
 	}
 	return
 }
 
 func (s *Stream) initCompression(extra *Extra) {
-	s.lz4s = &lz4Stream{}
-	s.lz4s.s = s
-	s.lz4s.blockMaxSize = int(extra.Config.Transport.LZ4BlockMaxSize)
-	s.lz4s.frameChecksum = extra.Config.Transport.LZ4FrameChecksum
-	if s.lz4s.blockMaxSize >= memsys.MaxPageSlabSize {
-		s.lz4s.sgl = g.mm.NewSGL(memsys.MaxPageSlabSize, memsys.MaxPageSlabSize)
+	s.cmprs = &cmprStream{}
+	s.cmprs.s = s
+	s.cmprs.blockMaxSize = int(extra.Config.Transport.LZ4BlockMaxSize)
+	s.cmprs.zstdLevel = extra.ZstdLevel
+	s.cmprs.frameChecksum = extra.Config.Transport.LZ4FrameChecksum
+	if s.cmprs.blockMaxSize >= memsys.MaxPageSlabSize {
+		s.cmprs.sgl = g.mm.NewSGL(memsys.MaxPageSlabSize, memsys.MaxPageSlabSize)
 	} else {
-		s.lz4s.sgl = g.mm.NewSGL(cos.KiB*64, cos.KiB*64)
+		s.cmprs.sgl = g.mm.NewSGL(cos.KiB*64, cos.KiB*64)
 	}
 }
 
-func (s *Stream) compressed() bool { return s.lz4s != nil }
+func (s *Stream) compressed() bool { return s.cmprs != nil }
 func (s *Stream) usePDU() bool     { return s.pdu != nil }
 
 func (s *Stream) resetCompression() {
-	s.lz4s.sgl.Reset()
-	s.lz4s.zw.Reset(nil)
+	s.cmprs.sgl.Reset()
+	s.cmprs.zw.Reset(nil)
 }
 
 func (s *Stream) cmplLoop() {
@@ -157,21 +171,30 @@ func (s *Stream) doCmpl(obj *Obj, err error) {
 }
 
 func (s *Stream) doRequest() error {
+	var (
+		body io.Reader = s
+		algo string
+	)
 	s.numCur, s.sizeCur = 0, 0
-	if !s.compressed() {
-		return s.doPlain(s)
+	if s.compressed() {
+		s.cmprs.reset()
+		body, algo = s.cmprs, s.cmprs.algo()
 	}
-	s.lz4s.sgl.Reset()
-	if s.lz4s.zw == nil {
-		s.lz4s.zw = lz4.NewWriter(s.lz4s.sgl)
-	} else {
-		s.lz4s.zw.Reset(s.lz4s.sgl)
+	// encrypt (compressed) body
+	if s.encr != nil {
+		if err := s.encr.reset(body); err != nil {
+			return err
+		}
+		body = s.encr
+	}
+	switch {
+	case s.h3 != nil:
+		return s.doQUIC(body, algo)
+	case algo != "":
+		return s.doCmpr(body, algo)
+	default:
+		return s.doPlain(body)
 	}
-	// lz4 framing spec at http://fastcompression.blogspot.com/2013/04/lz4-streaming-format-final.html
-	s.lz4s.zw.Header.BlockChecksum = false
-	s.lz4s.zw.Header.NoChecksum = !s.lz4s.frameChecksum
-	s.lz4s.zw.Header.BlockMaxSize = s.lz4s.blockMaxSize
-	return s.doCmpr(s.lz4s)
 }
 
 // as io.Reader
@@ -196,6 +219,7 @@ func (s *Stream) Read(b []byte) (n int, err error) {
 			err = s.pdu.readFrom(&s.sendoff)
 			if s.pdu.done {
 				s.pdu.insHeader()
+				s.throttle(s.pdu.plength())
 				break
 			}
 		}
@@ -275,6 +299,7 @@ func (s *Stream) sendData(b []byte) (n int, err error) {
 	)
 	n, err = obj.Reader.Read(b)
 	s.sendoff.off += int64(n)
+	s.throttle(n)
 	if err != nil {
 		if err == io.EOF {
 			if s.sendoff.off < objSize {
@@ -317,6 +342,9 @@ func (s *Stream) eoObj(err error) {
 	s.stats.Size.Add(objSize)
 	s.numCur++
 	s.stats.Num.Inc()
+	if obj.enqueued != 0 {
+		s.stats.Latency.Add(mono.SinceNano(obj.enqueued))
+	}
 	if cmn.Rom.FastV(5, cos.SmoduleTransport) && s.numCur&0x3f == 3 {
 		nlog.Infoln(s.String(), obj.Hdr.Cname(), "[", s.numCur, s.stats.Num.Load(), "]")
 	}
@@ -409,48 +437,91 @@ func (stats *Stats) CompressionRatio() float64 {
 	return float64(bytesRead) / float64(bytesSent)
 }
 
-///////////////
-// lz4Stream //
-///////////////
+////////////////
+// cmprStream //
+////////////////
+
+func (cs *cmprStream) algo() string {
+	if cs.zstdLevel > 0 {
+		return apc.ZstdCompression
+	}
+	return apc.LZ4Compression
+}
+
+// (re)initialize compressor at the beginning of each session
+func (cs *cmprStream) reset() {
+	cs.sgl.Reset()
+	switch {
+	case cs.zw != nil:
+		cs.zw.Reset(cs.sgl)
+	case cs.zstdLevel > 0:
+		zw, err := zstd.NewWriter(cs.sgl,
+			zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(cs.zstdLevel)),
+			zstd.WithEncoderConcurrency(1), // synchronous Flush
+			zstd.WithEncoderCRC(cs.frameChecksum),
+		)
+		debug.AssertNoErr(err)
+		cs.zw = zw
+	default:
+		cs.zw = lz4.NewWriter(cs.sgl)
+	}
+	if zw, ok := cs.zw.(*lz4.Writer); ok {
+		// lz4 framing spec at http://fastcompression.blogspot.com/2013/04/lz4-streaming-format-final.html
+		zw.Header.BlockChecksum = false
+		zw.Header.NoChecksum = !cs.frameChecksum
+		zw.Header.BlockMaxSize = cs.blockMaxSize
+	}
+}
+
+// end of session: zstd frame must be closed for the receiver to see clean EOF
+// (lz4 receiver is fine either way)
+func (cs *cmprStream) fin() {
+	if cs.zstdLevel > 0 {
+		cs.zw.Close()
+	} else {
+		cs.zw.Flush()
+	}
+}
 
-func (lz4s *lz4Stream) Read(b []byte) (n int, err error) {
+func (cs *cmprStream) Read(b []byte) (n int, err error) {
 	var (
-		sendoff = &lz4s.s.sendoff
+		sendoff = &cs.s.sendoff
 		last    = sendoff.obj.Hdr.isFin()
-		retry   = maxInReadRetries // insist on returning n > 0 (note that lz4 compresses /blocks/)
+		retry   = maxInReadRetries // insist on returning n > 0 (note that lz4 and zstd compress /blocks/)
 	)
-	if lz4s.sgl.Len() > 0 {
-		lz4s.zw.Flush()
-		n, err = lz4s.sgl.Read(b)
+	if cs.sgl.Len() > 0 {
+		cs.zw.Flush()
+		n, err = cs.sgl.Read(b)
 		if err == io.EOF { // reusing/rewinding this buf multiple times
 			err = nil
 		}
 		goto ex
 	}
 re:
-	n, err = lz4s.s.Read(b)
-	_, _ = lz4s.zw.Write(b[:n])
-	if last {
-		lz4s.zw.Flush()
+	n, err = cs.s.Read(b)
+	_, _ = cs.zw.Write(b[:n])
+	switch {
+	case last || err != nil:
+		cs.fin()
 		retry = 0
-	} else if lz4s.s.sendoff.ins == inEOB || err != nil {
-		lz4s.zw.Flush()
+	case cs.s.sendoff.ins == inEOB:
+		cs.zw.Flush()
 		retry = 0
 	}
-	n, _ = lz4s.sgl.Read(b)
+	n, _ = cs.sgl.Read(b)
 	if n == 0 {
 		if retry > 0 {
 			retry--
 			runtime.Gosched()
 			goto re
 		}
-		lz4s.zw.Flush()
-		n, _ = lz4s.sgl.Read(b)
+		cs.zw.Flush()
+		n, _ = cs.sgl.Read(b)
 	}
 ex:
-	lz4s.s.stats.CompressedSize.Add(int64(n))
-	if lz4s.sgl.Len() == 0 {
-		lz4s.sgl.Reset()
+	cs.s.stats.CompressedSize.Add(int64(n))
+	if cs.sgl.Len() == 0 {
+		cs.sgl.Reset()
 	}
 	if last && err == nil {
 		err = io.EOF
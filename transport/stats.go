@@ -15,6 +15,7 @@ type Stats struct {
 	Size           atomic.Int64 // transferred object size (does not include transport headers)
 	Offset         atomic.Int64 // stream offset, in bytes
 	CompressedSize atomic.Int64 // compressed size (converges to the actual compressed size over time)
+	Latency        atomic.Int64 // Tx only: cumulative Send-to-completion time (ns) - queuing plus transmission
 }
 
 type nopRxStats struct{}
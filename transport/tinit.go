@@ -39,6 +39,9 @@ var (
 	g global
 )
 
+// (used by stream bundles to report their own metrics)
+func StatsUpdater() cos.StatsUpdater { return g.tstats }
+
 func Init(tstats cos.StatsUpdater) *StreamCollector {
 	g.mm = memsys.PageMM()
 	g.tstats = tstats
@@ -69,6 +69,12 @@ type (
 		OnlyRunning bool          // only for running xactions
 	}
 
+	// runtime control of a running x-tcb given its ID (apc.ActXactRateLimit)
+	TCBCtlMsg struct {
+		ArgsMsg
+		RateLimit int64 `json:"rate_limit,omitempty"` // per-target bandwidth limit, bytes per second (zero: unlimited)
+	}
+
 	// simplified JSON-tagged version of the above
 	QueryMsg struct {
 		OnlyRunning *bool     `json:"show_active"`
@@ -105,6 +111,13 @@ type (
 		// xaction returns extended xaction-specific stats
 		// (see related: `Snap.Ext` in core/xaction.go)
 		ExtendedStats bool
+
+		// can be paused and resumed (see Base.Pause, apc.ActXactPause)
+		Pausable bool
+
+		// multi-target xaction that gets journaled and, upon target restart,
+		// aborted cluster-wide (see xreg/journal)
+		Journaled bool
 	}
 )
 
@@ -120,13 +133,14 @@ type (
 var Table = map[string]Descriptor{
 	// bucket-less xactions that will typically have a 'cluster' scope (with resilver being a notable exception)
 	apc.ActElection:  {DisplayName: "elect-primary", Scope: ScopeG, Startable: false},
-	apc.ActRebalance: {Scope: ScopeG, Startable: true, Metasync: true, Rebalance: true},
+	apc.ActRebalance: {Scope: ScopeG, Startable: true, Metasync: true, Rebalance: true, Pausable: true},
 
 	apc.ActETLInline: {Scope: ScopeG, Startable: false, AbortRebRes: true},
 
 	// (one bucket) | (all buckets)
 	apc.ActLRU:          {DisplayName: "lru-eviction", Scope: ScopeGB, Startable: true},
 	apc.ActStoreCleanup: {DisplayName: "cleanup", Scope: ScopeGB, Startable: true},
+	apc.ActExpireObjs:   {DisplayName: "expire", Scope: ScopeGB, Startable: true},
 	apc.ActSummaryBck: {
 		DisplayName: "summary",
 		Scope:       ScopeGB,
@@ -136,7 +150,10 @@ var Table = map[string]Descriptor{
 	},
 
 	// single target (node)
-	apc.ActResilver: {Scope: ScopeT, Startable: true, Resilver: true},
+	apc.ActResilver: {Scope: ScopeT, Startable: true, Resilver: true, Pausable: true},
+
+	// from a single target (in maintenance mode) to all others
+	apc.ActDrain: {DisplayName: "maintenance-drain", Scope: ScopeG, Startable: true, ConflictRebRes: true, RefreshCap: true, Pausable: true},
 
 	// on-demand EC and n-way replication
 	// (non-startable, triggered by PUT => erasure-coded or mirrored bucket)
@@ -145,10 +162,14 @@ var Table = map[string]Descriptor{
 	apc.ActECRespond: {Scope: ScopeB, Startable: false, Idles: true},
 	apc.ActPutCopies: {Scope: ScopeB, Startable: false, RefreshCap: true, Idles: true},
 
+	// on-demand cross-cluster replication
+	// (non-startable, triggered by writes into a replicated bucket and periodic resync)
+	apc.ActReplicate: {Scope: ScopeB, Access: apc.AccessRW, Startable: false, Idles: true},
+
 	//
 	// on-demand multi-object (consider setting ConflictRebRes = true)
 	//
-	apc.ActArchive: {Scope: ScopeB, Access: apc.AccessRW, Startable: false, RefreshCap: true, Idles: true},
+	apc.ActArchive: {Scope: ScopeB, Access: apc.AccessRW, Startable: false, RefreshCap: true, Idles: true, Journaled: true},
 	apc.ActCopyObjects: {
 		DisplayName: "copy-objects",
 		Scope:       ScopeB,
@@ -156,6 +177,7 @@ var Table = map[string]Descriptor{
 		Startable:   false,
 		RefreshCap:  true,
 		Idles:       true,
+		Journaled:   true,
 	},
 	apc.ActETLObjects: {
 		DisplayName: "etl-objects",
@@ -165,6 +187,7 @@ var Table = map[string]Descriptor{
 		RefreshCap:  true,
 		Idles:       true,
 		AbortRebRes: true,
+		Journaled:   true,
 	},
 
 	apc.ActBlobDl: {Access: apc.AccessRW, Scope: ScopeB, Startable: true, AbortRebRes: true, RefreshCap: true},
@@ -222,6 +245,7 @@ var Table = map[string]Descriptor{
 		Metasync:       true,
 		RefreshCap:     true,
 		ConflictRebRes: true,
+		Journaled:      true,
 	},
 	apc.ActMakeNCopies: {
 		DisplayName: "mirror",
@@ -230,6 +254,29 @@ var Table = map[string]Descriptor{
 		Startable:   true,
 		Metasync:    true,
 		RefreshCap:  true,
+		Pausable:    true,
+	},
+	apc.ActLifecycle: {
+		DisplayName: "lifecycle",
+		Scope:       ScopeB,
+		Access:      apc.AccessRW,
+		Startable:   true,
+		RefreshCap:  true,
+		Pausable:    true,
+	},
+	apc.ActRotateKey: {
+		DisplayName: "rotate-key",
+		Scope:       ScopeB,
+		Access:      apc.AccessRW,
+		Startable:   true,
+		RefreshCap:  true,
+		Pausable:    true,
+	},
+	apc.ActQuotaUsage: {
+		DisplayName: "quota-usage",
+		Scope:       ScopeB,
+		Access:      apc.AceObjLIST,
+		Startable:   true,
 	},
 	apc.ActMoveBck: {
 		DisplayName:    "rename-bucket",
@@ -248,6 +295,8 @@ var Table = map[string]Descriptor{
 		Metasync:       true,
 		RefreshCap:     true,
 		ConflictRebRes: true,
+		Pausable:       true,
+		Journaled:      true,
 	},
 	apc.ActETLBck: {
 		DisplayName: "etl-bucket",
@@ -257,12 +306,14 @@ var Table = map[string]Descriptor{
 		Metasync:    true,
 		RefreshCap:  true,
 		AbortRebRes: true,
+		Pausable:    true,
+		Journaled:   true,
 	},
 
 	apc.ActList: {Scope: ScopeB, Access: apc.AceObjLIST, Startable: false, Metasync: false, Idles: true},
 
 	// cache management, internal usage
-	apc.ActLoadLomCache:   {DisplayName: "warm-up-metadata", Scope: ScopeB, Startable: true},
+	apc.ActLoadLomCache:   {DisplayName: "warm-up-metadata", Scope: ScopeB, Startable: true, Pausable: true},
 	apc.ActInvalListCache: {Scope: ScopeB, Access: apc.AceObjLIST, Startable: false},
 }
 
@@ -588,6 +639,25 @@ func (xs MultiSnap) ByteCounts(xid string) (locBytes, outBytes, inBytes int64) {
 	return
 }
 
+// cluster-wide progress (nil when not reported)
+func (xs MultiSnap) Progress(xid string) *core.Progress {
+	var total *core.Progress
+	for _, snaps := range xs {
+		for _, xsnap := range snaps {
+			if xid != xsnap.ID || xsnap.Progress == nil {
+				continue
+			}
+			if total == nil {
+				p := *xsnap.Progress
+				total = &p
+			} else {
+				total.Add(xsnap.Progress)
+			}
+		}
+	}
+	return total
+}
+
 func (xs MultiSnap) TotalRunningTime(xid string) (time.Duration, error) {
 	debug.Assert(IsValidUUID(xid), xid)
 	var (
@@ -33,6 +33,11 @@ type (
 			err  ratomic.Pointer[error]
 			done atomic.Bool
 		}
+		pause struct {
+			ch   chan struct{} // closed upon resume (or abort, or finish)
+			mu   sync.Mutex
+			done atomic.Bool
+		}
 		id     string
 		kind   string
 		_nam   string
@@ -46,6 +51,12 @@ type (
 			inobjs   atomic.Int64 // receive
 			inbytes  atomic.Int64
 		}
+		prog struct {
+			objs  atomic.Int64 // discovered (total) to process
+			bytes atomic.Int64
+			on    atomic.Bool // reporting progress
+			final atomic.Bool // done discovering
+		}
 		sutime atomic.Int64
 		eutime atomic.Int64
 	}
@@ -164,12 +175,71 @@ func (xctn *Base) Abort(err error) bool {
 	xctn.abort.ch <- err
 	close(xctn.abort.ch)
 
+	xctn._resume() // unblock WaitPaused, if any
+
 	if xctn.Kind() != apc.ActList {
 		nlog.InfoDepth(1, xctn.Name(), err)
 	}
 	return true
 }
 
+//
+// pausing: stop making progress without losing state (and without releasing
+// any resources) - until resumed or aborted; supported by xactions that call WaitPaused
+// (see Descriptor.Pausable)
+//
+
+func (xctn *Base) IsPaused() bool { return xctn.pause.done.Load() }
+
+func (xctn *Base) Pause() bool {
+	xctn.pause.mu.Lock()
+	if xctn.pause.ch != nil || xctn.Finished() || xctn.IsAborted() { // (abort and finish resume under lock)
+		xctn.pause.mu.Unlock()
+		return false
+	}
+	xctn.pause.ch = make(chan struct{})
+	xctn.pause.done.Store(true)
+	xctn.pause.mu.Unlock()
+
+	nlog.InfoDepth(1, xctn.Name(), "paused")
+	return true
+}
+
+func (xctn *Base) Resume() bool {
+	if !xctn._resume() {
+		return false
+	}
+	nlog.InfoDepth(1, xctn.Name(), "resumed")
+	return true
+}
+
+func (xctn *Base) _resume() bool {
+	xctn.pause.mu.Lock()
+	ch := xctn.pause.ch
+	if ch == nil {
+		xctn.pause.mu.Unlock()
+		return false
+	}
+	xctn.pause.ch = nil
+	xctn.pause.done.Store(false)
+	close(ch)
+	xctn.pause.mu.Unlock()
+	return true
+}
+
+// block while paused; callers must check IsAborted upon return
+func (xctn *Base) WaitPaused() {
+	if !xctn.IsPaused() {
+		return
+	}
+	xctn.pause.mu.Lock()
+	ch := xctn.pause.ch
+	xctn.pause.mu.Unlock()
+	if ch != nil {
+		<-ch
+	}
+}
+
 //
 // multi-error
 //
@@ -223,6 +293,10 @@ func (xctn *Base) Quiesce(d time.Duration, cb core.QuiCB) core.QuiRes {
 		if xctn.IsAborted() {
 			return core.QuiAborted
 		}
+		if xctn.IsPaused() { // not counting
+			idle = 0
+			continue
+		}
 		total += sleep
 		switch res := cb(total); res {
 		case core.QuiInactiveCB: // NOTE: used by callbacks, converts to one of the returned codes
@@ -322,6 +396,7 @@ func (xctn *Base) Finish() {
 		return
 	}
 	xctn.eutime.Store(time.Now().UnixNano())
+	xctn._resume()
 	if aborted = xctn.IsAborted(); aborted {
 		if perr := xctn.abort.err.Load(); perr != nil {
 			err = *perr
@@ -382,6 +457,43 @@ func (xctn *Base) InObjsAdd(cnt int, size int64) {
 	xctn.stats.inbytes.Add(size)
 }
 
+// progress: totals (see core.Progress)
+// - xactions that traverse (e.g., a bucket) add totals as they go, and call TotalFinal when done;
+// - xactions that know their totals upfront call SetTotal
+
+func (xctn *Base) AddTotal(cnt int, size int64) {
+	xctn.prog.objs.Add(int64(cnt))
+	xctn.prog.bytes.Add(size)
+	xctn.prog.on.Store(true)
+}
+
+func (xctn *Base) SetTotal(cnt, size int64) {
+	xctn.prog.objs.Store(cnt)
+	xctn.prog.bytes.Store(size)
+	xctn.prog.on.Store(true)
+	xctn.prog.final.Store(true)
+}
+
+func (xctn *Base) TotalFinal() {
+	xctn.prog.on.Store(true)
+	xctn.prog.final.Store(true)
+}
+
+func (xctn *Base) toProgress(snap *core.Snap) *core.Progress {
+	p := &core.Progress{
+		TotalObjs:  xctn.prog.objs.Load(),
+		TotalBytes: xctn.prog.bytes.Load(),
+		Objs:       snap.Stats.Objs + snap.Stats.OutObjs,
+		Bytes:      snap.Stats.Bytes + snap.Stats.OutBytes,
+		Errs:       int64(xctn.ErrCnt()),
+		Final:      xctn.prog.final.Load(),
+	}
+	if snap.Running() && !snap.IsPaused() {
+		p.ETA = p.Estimate(time.Since(snap.StartTime))
+	}
+	return p
+}
+
 // provided for external use to fill-in xaction-specific `SnapExt` part
 func (xctn *Base) ToSnap(snap *core.Snap) {
 	snap.ID = xctn.ID()
@@ -393,6 +505,7 @@ func (xctn *Base) ToSnap(snap *core.Snap) {
 		snap.AbortErr = err.Error()
 		snap.AbortedX = true
 	}
+	snap.PausedX = xctn.IsPaused()
 	snap.Err = xctn.err.Error() // TODO: a (verbose) option to respond with xctn.err.JoinErr() :NOTE
 	if b := xctn.Bck(); b != nil {
 		snap.Bck = b.Clone()
@@ -400,6 +513,9 @@ func (xctn *Base) ToSnap(snap *core.Snap) {
 
 	// counters
 	xctn.ToStats(&snap.Stats)
+	if xctn.prog.on.Load() {
+		snap.Progress = xctn.toProgress(snap)
+	}
 }
 
 func (xctn *Base) ToStats(stats *core.Stats) {
@@ -18,12 +18,27 @@ type BckJog struct {
 
 func (r *BckJog) Init(id, kind, ctlmsg string, bck *meta.Bck, opts *mpather.JgroupOpts, config *cmn.Config) {
 	r.InitBase(id, kind, ctlmsg, bck)
+	if opts.WaitPaused == nil {
+		opts.WaitPaused = r.WaitPaused
+	}
+	if opts.Gov == nil {
+		opts.Gov = mpather.RegGov(kind) // (unregistered by the jogger group)
+	}
 	r.joggers = mpather.NewJoggerGroup(opts, config, nil)
 	r.Config = config
 }
 
 func (r *BckJog) Run() { r.joggers.Run() }
 
+// stop visiting (remaining) objects; see also Wait
+func (r *BckJog) StopJoggers() { r.joggers.Stop() }
+
+func (r *BckJog) BufSizes() map[string]int64 { return r.joggers.BufSizes() }
+
+func (r *BckJog) InFlight(limit int) []string { return r.joggers.InFlight(limit) }
+
+func (r *BckJog) BufUsed() int64 { return r.joggers.BufUsed() }
+
 func (r *BckJog) Wait() error {
 	select {
 	case errCause := <-r.ChanAbort():
@@ -0,0 +1,96 @@
+// Package xreg provides registry and (renew, find) functions for AIS eXtended Actions (xactions).
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xreg
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/xact"
+)
+
+// per-bucket xaction limits (this target; see cmn.XactLimProps):
+// - max concurrent: checked upon renewal of a new xaction that reads or writes a given bucket -
+//   on-demand xactions (xact.Descriptor.Idles) are neither checked nor counted;
+// - bandwidth: shared by all background copies from or to a given bucket (see BckThrottle)
+
+type bckThrottle struct {
+	next int64 // mono time: when the bytes charged so far have all "gone"
+	mu   sync.Mutex
+}
+
+var bthrottles sync.Map // bck uname => *bckThrottle
+
+// (under renewal lock)
+func (r *registry) checkBckLimit(kind string, flt *Flt) error {
+	if xact.Table[kind].Idles {
+		return nil
+	}
+	bcks := flt.Buckets
+	if flt.Bck != nil {
+		bcks = append([]*meta.Bck{flt.Bck}, bcks...)
+	}
+	for _, bck := range bcks {
+		if bck == nil || bck.Props == nil || bck.Props.Xact.MaxConcurrent <= 0 {
+			continue
+		}
+		if limit := bck.Props.Xact.MaxConcurrent; r.entries.countRunning(bck) >= limit {
+			return cmn.NewErrBckXactLimit(bck.Cname(""), kind, limit)
+		}
+	}
+	return nil
+}
+
+func (e *entries) countRunning(bck *meta.Bck) (n int) {
+	e.mtx.RLock()
+	for _, entry := range e.active {
+		xctn := entry.Get()
+		if !xctn.Running() || xact.Table[xctn.Kind()].Idles {
+			continue
+		}
+		if touches(bck, xctn.Bck()) {
+			n++
+			continue
+		}
+		if from, to := xctn.FromTo(); touches(bck, from) || touches(bck, to) {
+			n++
+		}
+	}
+	e.mtx.RUnlock()
+	return n
+}
+
+func touches(bck, other *meta.Bck) bool {
+	return bck.Equal(other, false /*same BID*/, true /*same backend*/) // (false when empty)
+}
+
+// charge size bytes copied from and/or to the given buckets;
+// returns the time to wait (zero when not throttled) - the max across buckets
+func BckThrottle(size int64, bcks ...*meta.Bck) (d time.Duration) {
+	if size <= 0 {
+		return 0
+	}
+	for _, bck := range bcks {
+		if bck == nil || bck.Props == nil || bck.Props.Xact.Bandwidth <= 0 {
+			continue
+		}
+		v, _ := bthrottles.LoadOrStore(string(bck.MakeUname("")), &bckThrottle{})
+		d = max(d, v.(*bckThrottle).charge(size, int64(bck.Props.Xact.Bandwidth)))
+	}
+	return d
+}
+
+func (th *bckThrottle) charge(size, bps int64) time.Duration {
+	th.mu.Lock()
+	now := mono.NanoTime()
+	th.next = max(th.next, now-int64(time.Second)) // (up to one second worth of burst)
+	th.next += int64(float64(size) / float64(bps) * float64(time.Second))
+	d := time.Duration(th.next - now)
+	th.mu.Unlock()
+	return max(d, 0)
+}
@@ -0,0 +1,49 @@
+// Package xreg provides registry and (renew, find) functions for AIS eXtended Actions (xactions).
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xreg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/core/mock"
+	"github.com/NVIDIA/aistore/tools/tassert"
+)
+
+func TestBckLimit(t *testing.T) {
+	var (
+		props = &cmn.Bprops{Xact: cmn.XactLimProps{MaxConcurrent: 1, Bandwidth: cos.MiB}}
+		bck   = meta.NewBck("lim", apc.AIS, cmn.NsGlobal, props)
+		other = meta.NewBck("other", apc.AIS, cmn.NsGlobal, &cmn.Bprops{})
+		xctn  = &mock.XactMock{}
+	)
+	TestReset()
+	xctn.InitBase(cos.GenUUID(), apc.ActMakeNCopies, "", bck)
+	dreg.entries.add(&jentry{xctn: xctn})
+
+	// max concurrent
+	err := dreg.checkBckLimit(apc.ActECEncode, &Flt{Bck: bck})
+	tassert.Errorf(t, cmn.IsErrBckXactLimit(err), "expected bucket limit error, got %v", err)
+	err = dreg.checkBckLimit(apc.ActCopyBck, &Flt{Bck: other, Buckets: []*meta.Bck{bck, other}})
+	tassert.Errorf(t, cmn.IsErrBckXactLimit(err), "expected bucket limit error (source), got %v", err)
+	err = dreg.checkBckLimit(apc.ActECEncode, &Flt{Bck: other})
+	tassert.Errorf(t, err == nil, "expected no limit for %s, got %v", other, err)
+	err = dreg.checkBckLimit(apc.ActList, &Flt{Bck: bck})
+	tassert.Errorf(t, err == nil, "on-demand xactions are not limited, got %v", err)
+
+	props.Xact.MaxConcurrent = 2
+	err = dreg.checkBckLimit(apc.ActECEncode, &Flt{Bck: bck})
+	tassert.Errorf(t, err == nil, "expected no error below the limit, got %v", err)
+
+	// bandwidth (up to one second worth of burst)
+	tassert.Errorf(t, BckThrottle(cos.MiB/2, bck, other) == 0, "expected no throttling within the burst")
+	d := BckThrottle(2*cos.MiB, other, bck)
+	tassert.Errorf(t, d > time.Second && d <= 3*time.Second/2, "expected ~1.5s wait, got %v", d)
+	tassert.Errorf(t, BckThrottle(cos.GiB, other) == 0, "expected %s not to be throttled", other)
+}
@@ -9,7 +9,9 @@ import (
 
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/nlog"
 	"github.com/NVIDIA/aistore/core"
 	"github.com/NVIDIA/aistore/core/meta"
 	"github.com/NVIDIA/aistore/xact"
@@ -118,10 +120,54 @@ func RenewBckLoadLomCache(uuid string, bck *meta.Bck) RenewRes {
 	return RenewBucketXact(apc.ActLoadLomCache, bck, Args{UUID: uuid})
 }
 
+func RenewBckLifecycle(uuid string, bck *meta.Bck) RenewRes {
+	return RenewBucketXact(apc.ActLifecycle, bck, Args{UUID: uuid})
+}
+
+func RenewBckRotateKey(uuid string, bck *meta.Bck) RenewRes {
+	return RenewBucketXact(apc.ActRotateKey, bck, Args{UUID: uuid})
+}
+
+func RenewBckQuotaUsage(uuid string, bck *meta.Bck) RenewRes {
+	return RenewBucketXact(apc.ActQuotaUsage, bck, Args{UUID: uuid})
+}
+
+// (periodic) buckets with quota or, when AuthN is enabled (per-user quotas), all present buckets
+func RenewQuotaUsage(config *cmn.Config) {
+	bmd := core.T.Bowner().Get()
+	bmd.Range(nil, nil, func(bck *meta.Bck) bool {
+		if bck.Props.Quota.IsSet() || config.Auth.Enabled {
+			rns := RenewBckQuotaUsage(cos.GenUUID(), bck)
+			if rns.Err != nil {
+				nlog.Warningln("quota-usage", bck.Cname(""), rns.Err)
+			}
+		}
+		return false
+	})
+}
+
+// (periodic) all buckets with enabled lifecycle rules
+func RenewLifecycle() {
+	bmd := core.T.Bowner().Get()
+	bmd.Range(nil, nil, func(bck *meta.Bck) bool {
+		if bck.Props.Lifecycle.Enabled && len(bck.Props.Lifecycle.Rules) > 0 {
+			rns := RenewBckLifecycle(cos.GenUUID(), bck)
+			if rns.Err != nil {
+				nlog.Warningln("lifecycle", bck.Cname(""), rns.Err)
+			}
+		}
+		return false
+	})
+}
+
 func RenewPutMirror(lom *core.LOM) RenewRes {
 	return RenewBucketXact(apc.ActPutCopies, lom.Bck(), Args{Custom: lom})
 }
 
+func RenewReplicate(bck *meta.Bck) RenewRes {
+	return RenewBucketXact(apc.ActReplicate, bck, Args{})
+}
+
 func RenewTCB(uuid, kind string, custom *TCBArgs) RenewRes {
 	return RenewBucketXact(
 		kind,
@@ -0,0 +1,166 @@
+// Package xreg provides registry and (renew, find) functions for AIS eXtended Actions (xactions).
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xreg
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/fname"
+	"github.com/NVIDIA/aistore/cmn/jsp"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/xact"
+)
+
+// Journal: in-progress multi-target xactions (see xact.Descriptor.Journaled) persisted locally
+// - periodically: kind, UUID, buckets, initiating control message, phase, and stats (so far);
+// - upon restart, the journaled entries are orphans: this target no longer runs them while the rest
+//   of the cluster may still be waiting - the target requests cluster-wide abort (see ais/tgtxact);
+// - orphans remain journaled until taken (see TakeOrphans).
+
+const (
+	journalIval = 10 * time.Second
+	journalVer  = 1
+)
+
+const (
+	PhaseRunning = "running"
+	PhasePaused  = "paused"
+	PhaseOrphan  = "orphan" // (upon restart)
+)
+
+type (
+	JournalEntry struct {
+		Started time.Time  `json:"started"`
+		Updated time.Time  `json:"updated"`
+		Bck     cmn.Bck    `json:"bck"`
+		SrcBck  cmn.Bck    `json:"src-bck"`
+		DstBck  cmn.Bck    `json:"dst-bck"`
+		ID      string     `json:"id"`
+		Kind    string     `json:"kind"`
+		CtlMsg  string     `json:"ctlmsg,omitempty"`
+		Phase   string     `json:"phase"`
+		Stats   core.Stats `json:"stats"`
+	}
+	journalMD struct {
+		Entries []JournalEntry `json:"entries"`
+	}
+	journal struct {
+		fpath   string
+		orphans []JournalEntry
+		mu      sync.Mutex
+		empty   bool // as persisted
+	}
+)
+
+var xjnl journal
+
+// (target only) load the previous journal, if any
+func LoadJournal(configDir string) {
+	xjnl.load(filepath.Join(configDir, fname.XactJournal))
+}
+
+// (target only) start journaling
+func RegJournal() {
+	hk.Reg("x-journal"+hk.NameSuffix, xjnl.hk, journalIval)
+}
+
+// returns (and forgets) the orphans
+func TakeOrphans() (orphans []JournalEntry) {
+	xjnl.mu.Lock()
+	orphans, xjnl.orphans = xjnl.orphans, nil
+	xjnl.mu.Unlock()
+	return orphans
+}
+
+func (j *journal) load(fpath string) {
+	var md journalMD
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.fpath, j.orphans, j.empty = fpath, nil, true
+	if _, err := jsp.Load(fpath, &md, jsp.CksumSign(journalVer)); err != nil {
+		if !os.IsNotExist(err) {
+			nlog.Errorln("failed to load xaction journal:", err)
+		}
+		return
+	}
+	for i := range md.Entries {
+		e := &md.Entries[i]
+		e.Phase = PhaseOrphan
+		j.orphans = append(j.orphans, *e)
+		nlog.Warningln("xaction journal: orphaned", e.Kind, e.ID, "as of", e.Updated.Format(time.Stamp))
+	}
+	j.empty = len(j.orphans) == 0
+}
+
+func (j *journal) hk(int64) time.Duration {
+	j.mu.Lock()
+	j.do(time.Now())
+	j.mu.Unlock()
+	return journalIval
+}
+
+// (under lock)
+func (j *journal) do(now time.Time) {
+	var (
+		md      journalMD
+		xctns   []core.Xact
+		running = make(map[string]struct{}, 4)
+	)
+	dreg.entries.forEach(func(entry Renewable) bool {
+		xctn := entry.Get()
+		if dtor, ok := xact.Table[xctn.Kind()]; ok && dtor.Journaled && xctn.Running() {
+			xctns = append(xctns, xctn)
+		}
+		return true
+	})
+	for _, xctn := range xctns {
+		snap := xctn.Snap()
+		e := JournalEntry{
+			Started: snap.StartTime,
+			Updated: now,
+			Bck:     snap.Bck,
+			SrcBck:  snap.SrcBck,
+			DstBck:  snap.DstBck,
+			ID:      snap.ID,
+			Kind:    snap.Kind,
+			CtlMsg:  snap.CtlMsg,
+			Phase:   PhaseRunning,
+			Stats:   snap.Stats,
+		}
+		if snap.IsPaused() {
+			e.Phase = PhasePaused
+		}
+		md.Entries = append(md.Entries, e)
+		running[e.ID] = struct{}{}
+	}
+	for i := range j.orphans {
+		if _, ok := running[j.orphans[i].ID]; !ok {
+			md.Entries = append(md.Entries, j.orphans[i])
+		}
+	}
+
+	switch {
+	case len(md.Entries) > 0:
+		if err := jsp.Save(j.fpath, &md, jsp.CksumSign(journalVer), nil); err != nil {
+			nlog.Errorln("failed to persist xaction journal:", err)
+			return
+		}
+		j.empty = false
+	case !j.empty:
+		if err := cos.RemoveFile(j.fpath); err != nil {
+			nlog.Errorln("failed to remove xaction journal:", err)
+			return
+		}
+		j.empty = true
+	}
+}
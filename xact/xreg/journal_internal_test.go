@@ -0,0 +1,49 @@
+// Package xreg provides registry and (renew, find) functions for AIS eXtended Actions (xactions).
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xreg
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/fname"
+	"github.com/NVIDIA/aistore/core/mock"
+	"github.com/NVIDIA/aistore/tools/tassert"
+)
+
+func TestJournal(t *testing.T) {
+	var (
+		dir   = t.TempDir()
+		fpath = filepath.Join(dir, fname.XactJournal)
+		tcb   = mock.NewXact(apc.ActCopyBck)
+		lru   = mock.NewXact(apc.ActLRU) // not journaled
+	)
+	TestReset()
+	LoadJournal(dir)
+	tassert.Errorf(t, len(TakeOrphans()) == 0, "expected no orphans")
+
+	dreg.entries.add(&jentry{xctn: tcb})
+	dreg.entries.add(&jentry{xctn: lru})
+	tcb.ObjsAdd(3, 3000)
+	xjnl.do(time.Now())
+	tassert.Fatalf(t, cos.Stat(fpath) == nil, "expected %q to exist", fpath)
+
+	// restart
+	TestReset()
+	LoadJournal(dir)
+	xjnl.do(time.Now()) // (orphans remain journaled until taken)
+	LoadJournal(dir)
+	orphans := TakeOrphans()
+	tassert.Fatalf(t, len(orphans) == 1, "expected one orphan, got %d", len(orphans))
+	e := &orphans[0]
+	tassert.Errorf(t, e.ID == tcb.ID() && e.Kind == apc.ActCopyBck && e.Phase == PhaseOrphan, "unexpected %+v", e)
+	tassert.Errorf(t, e.Stats.Objs == 3 && e.Stats.Bytes == 3000, "unexpected stats %+v", e.Stats)
+
+	xjnl.do(time.Now())
+	tassert.Errorf(t, cos.Stat(fpath) != nil, "expected %q to be removed", fpath)
+}
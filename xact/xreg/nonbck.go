@@ -34,6 +34,12 @@ func RenewElection() RenewRes {
 	return dreg.renew(e, nil)
 }
 
+// tid: the target that drains (see xs.XactDrain)
+func RenewDrain(id, tid string) RenewRes {
+	e := dreg.nonbckXacts[apc.ActDrain].New(Args{UUID: id, Custom: tid}, nil)
+	return dreg.renew(e, nil)
+}
+
 func RenewLRU(id, ctlmsg string) RenewRes {
 	e := dreg.nonbckXacts[apc.ActLRU].New(Args{UUID: id, Custom: ctlmsg}, nil)
 	return dreg.renew(e, nil)
@@ -44,6 +50,11 @@ func RenewStoreCleanup(id, ctlmsg string) RenewRes {
 	return dreg.renew(e, nil)
 }
 
+func RenewExpireObjs(id, ctlmsg string) RenewRes {
+	e := dreg.nonbckXacts[apc.ActExpireObjs].New(Args{UUID: id, Custom: ctlmsg}, nil)
+	return dreg.renew(e, nil)
+}
+
 func RenewDownloader(xid string, bck *meta.Bck) RenewRes {
 	e := dreg.nonbckXacts[apc.ActDownload].New(Args{UUID: xid, Custom: bck}, nil)
 	return dreg.renew(e, nil)
@@ -0,0 +1,156 @@
+// Package xreg provides registry and (renew, find) functions for AIS eXtended Actions (xactions).
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xreg
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/xact"
+)
+
+// Scheduler: cluster-wide time windows for background xactions (see cmn.SchedConf)
+// - each target periodically checks config.Sched and, outside all of its windows,
+//   pauses running xaction of a given kind (see xact.Descriptor.Pausable);
+// - upon entering a window, the xaction gets resumed - and, if configured, started
+//   (target-local startable xactions only: resilver, lru, store-cleanup);
+// - xactions paused by the user are left alone (and vice versa).
+//
+// Limitation: there's no cluster-wide coordination - each target evaluates the windows
+// on its own, in its local time zone, on ticks aligned to multiples of schedIval
+// (wall clock). Targets with synchronized clocks (NTP) and the same time zone transition
+// within the same tick; otherwise, a job may remain paused on some targets while
+// running on others for up to the clock difference (plus one tick).
+
+const schedIval = 30 * time.Second
+
+type (
+	SchedStart func(kind string) error
+
+	sched struct {
+		start  SchedStart
+		paused map[string]string // xaction ID => kind (paused by the scheduler)
+		in     map[string]bool   // window => inside (as of the previous tick)
+		mu     sync.Mutex
+		primed bool
+	}
+)
+
+var schd sched
+
+// (target only)
+func RegSched(start SchedStart) {
+	schd.start = start
+	schd.paused = make(map[string]string, 4)
+	hk.Reg("x-sched"+hk.NameSuffix, schd.hk, schedNext(time.Now()))
+}
+
+// validate config.Sched in re: xaction kinds (compare w/ cmn.SchedConf.Validate)
+func CheckSched(conf *cmn.SchedConf) error {
+	for i := range conf.Windows {
+		w := &conf.Windows[i]
+		kind, dtor, err := xact.GetDescriptor(w.Kind)
+		if err != nil {
+			return fmt.Errorf("invalid sched.windows[%d]: %v", i, err)
+		}
+		if w.Start && !schedStartable(&dtor) {
+			return fmt.Errorf("invalid sched.windows[%d]: %q cannot be started by the scheduler", i, kind)
+		}
+		if !w.Start && !dtor.Pausable {
+			return fmt.Errorf("invalid sched.windows[%d]: %q is not pausable (and not configured to start)", i, kind)
+		}
+	}
+	return nil
+}
+
+func schedStartable(dtor *xact.Descriptor) bool {
+	return dtor.Startable && (dtor.Scope == xact.ScopeGB || dtor.Scope == xact.ScopeT)
+}
+
+func (s *sched) hk(int64) time.Duration {
+	s.mu.Lock()
+	now := time.Now()
+	s.do(cmn.GCO.Get(), now)
+	s.mu.Unlock()
+	return schedNext(now)
+}
+
+// next tick at the wall-clock multiple of schedIval (so that all targets tick together)
+func schedNext(now time.Time) time.Duration {
+	d := schedIval - time.Duration(now.UnixNano()%int64(schedIval))
+	if d < time.Second {
+		d += schedIval
+	}
+	return d
+}
+
+func (s *sched) do(config *cmn.Config, now time.Time) {
+	var (
+		windows = config.Sched.Windows
+		allowed = make(map[string]bool, len(windows)) // kind => inside any of its windows
+		starts  []string
+		in      = make(map[string]bool, len(windows))
+	)
+	for i := range windows {
+		w := &windows[i]
+		kind, dtor, err := xact.GetDescriptor(w.Kind)
+		if err != nil {
+			continue // (validated)
+		}
+		inside := w.Contains(now)
+		if dtor.Pausable {
+			allowed[kind] = allowed[kind] || inside
+		}
+		key := w.String()
+		in[key] = inside
+		// upon entering (but not when the node starts up inside the window)
+		if w.Start && inside && s.primed && !s.in[key] && schedStartable(&dtor) {
+			starts = append(starts, kind)
+		}
+	}
+	s.in, s.primed = in, true
+
+	// resume
+	for id, kind := range s.paused {
+		if inside, ok := allowed[kind]; ok && !inside {
+			continue
+		}
+		if xctn, _ := dreg.getXact(id); xctn != nil && xctn.Resume() {
+			nlog.Infoln("sched: resumed", xctn.Name())
+		}
+		delete(s.paused, id)
+	}
+
+	// pause
+	for kind, inside := range allowed {
+		if inside {
+			continue
+		}
+		dreg.entries.forEach(func(entry Renewable) bool {
+			xctn := entry.Get()
+			if xctn.Kind() == kind && xctn.Running() && xctn.Pause() {
+				s.paused[xctn.ID()] = kind
+				nlog.Infoln("sched: paused", xctn.Name(), "- outside its time window(s)")
+			}
+			return true
+		})
+	}
+
+	// start
+	for _, kind := range starts {
+		if running := dreg.getRunning(Flt{Kind: kind}); running != nil {
+			continue
+		}
+		if err := s.start(kind); err != nil {
+			nlog.Errorln("sched: failed to start", kind, "err:", err)
+		} else {
+			nlog.Infoln("sched: started", kind)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Package xreg provides registry and (renew, find) functions for AIS eXtended Actions (xactions).
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xreg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/core/mock"
+	"github.com/NVIDIA/aistore/tools/tassert"
+)
+
+// registry entry of a given (mock) xaction
+type jentry struct {
+	RenewBase
+	xctn core.Xact
+}
+
+func (e *jentry) New(Args, *meta.Bck) Renewable          { return e }
+func (*jentry) Start() error                             { return nil }
+func (e *jentry) Kind() string                           { return e.xctn.Kind() }
+func (e *jentry) Get() core.Xact                         { return e.xctn }
+func (*jentry) WhenPrevIsRunning(Renewable) (WPR, error) { return WprKeepAndStartNew, nil }
+
+func TestSchedWindows(t *testing.T) {
+	var (
+		started []string
+		s       = &sched{
+			start:  func(kind string) error { started = append(started, kind); return nil },
+			paused: make(map[string]string, 4),
+		}
+		config = &cmn.Config{}
+		day    = time.Date(2024, time.June, 3, 0, 0, 0, 0, time.Local) // Monday
+		at     = func(hh, mm int) time.Time {
+			return day.Add(time.Duration(hh)*time.Hour + time.Duration(mm)*time.Minute)
+		}
+		reb  = mock.NewXact(apc.ActRebalance)
+		user = mock.NewXact(apc.ActRebalance)
+	)
+	TestReset()
+	config.Sched.Windows = []cmn.SchedWindow{
+		{Kind: apc.ActRebalance, Begin: "22:00", End: "06:00"},
+		{Kind: apc.ActResilver, Begin: "01:00", End: "02:00", Start: true},
+	}
+	dreg.entries.add(&jentry{xctn: reb})
+	dreg.entries.add(&jentry{xctn: user})
+	tassert.Fatalf(t, user.Pause(), "failed to pause %s", user)
+
+	// startup outside the window: pause, do not start
+	s.do(config, at(12, 0))
+	tassert.Errorf(t, reb.IsPaused(), "expected %s to be paused outside its window", reb)
+	tassert.Errorf(t, len(s.paused) == 1, "expected user-paused xaction not to be tracked, got %v", s.paused)
+	s.do(config, at(21, 59))
+	tassert.Errorf(t, reb.IsPaused(), "expected %s to remain paused", reb)
+
+	// entering: resume (but only those paused by the scheduler)
+	s.do(config, at(22, 0))
+	tassert.Errorf(t, !reb.IsPaused(), "expected %s to be resumed inside its window", reb)
+	tassert.Errorf(t, user.IsPaused(), "expected user-paused %s to be left alone", user)
+	tassert.Errorf(t, len(s.paused) == 0, "expected nothing paused by the scheduler, got %v", s.paused)
+
+	// wrap around midnight
+	s.do(config, at(23, 59).Add(2*time.Minute))
+	tassert.Errorf(t, !reb.IsPaused(), "expected %s to keep running past midnight", reb)
+
+	// entering resilver's window: start once
+	s.do(config, at(25, 0))
+	s.do(config, at(25, 30))
+	tassert.Errorf(t, len(started) == 1 && started[0] == apc.ActResilver, "expected resilver started once, got %v", started)
+
+	// leaving: pause again
+	s.do(config, at(30, 0))
+	tassert.Errorf(t, reb.IsPaused(), "expected %s to be paused upon leaving its window", reb)
+
+	// window removed from config: resume
+	config.Sched.Windows = config.Sched.Windows[1:]
+	s.do(config, at(30, 1))
+	tassert.Errorf(t, !reb.IsPaused(), "expected %s to be resumed when no longer scheduled", reb)
+	tassert.Errorf(t, user.IsPaused(), "expected user-paused %s to be left alone", user)
+}
+
+func TestSchedStartup(t *testing.T) {
+	var (
+		started []string
+		s       = &sched{
+			start:  func(kind string) error { started = append(started, kind); return nil },
+			paused: make(map[string]string, 4),
+		}
+		config = &cmn.Config{}
+		now    = time.Date(2024, time.June, 3, 1, 30, 0, 0, time.Local)
+		res    = mock.NewXact(apc.ActResilver)
+	)
+	TestReset()
+	config.Sched.Windows = []cmn.SchedWindow{{Kind: apc.ActResilver, Begin: "01:00", End: "02:00", Start: true}}
+
+	// node starting up inside the window: do not start
+	s.do(config, now)
+	tassert.Errorf(t, len(started) == 0, "expected no start upon startup, got %v", started)
+
+	// re-entering on the next day, with resilver already running: do not start
+	dreg.entries.add(&jentry{xctn: res})
+	s.do(config, now.Add(time.Hour))
+	s.do(config, now.Add(24*time.Hour))
+	tassert.Errorf(t, len(started) == 0, "expected no start when already running, got %v", started)
+}
+
+func TestSchedNext(t *testing.T) {
+	for _, now := range []time.Time{
+		time.Date(2024, time.June, 3, 1, 0, 0, 0, time.UTC),
+		time.Date(2024, time.June, 3, 1, 0, 29, 500*int(time.Millisecond), time.UTC),
+		time.Date(2024, time.June, 3, 1, 0, 17, 0, time.UTC),
+	} {
+		d := schedNext(now)
+		next := now.Add(d)
+		tassert.Errorf(t, next.UnixNano()%int64(schedIval) == 0, "%v + %v: not aligned", now, d)
+		tassert.Errorf(t, d >= time.Second && d <= schedIval+time.Second, "%v: unexpected %v", now, d)
+	}
+}
@@ -263,6 +263,51 @@ func DoAbort(flt Flt, err error) {
 	}
 }
 
+// pause (resume) running xaction(s) that match the filter and are pausable (see xact.Descriptor);
+// return the number of xactions that have changed their state
+func DoPause(flt Flt) (int, error)  { return dreg.pause(flt, true) }
+func DoResume(flt Flt) (int, error) { return dreg.pause(flt, false) }
+
+func (r *registry) pause(flt Flt, pause bool) (n int, _ error) {
+	if flt.ID != "" {
+		xctn, err := r.getXact(flt.ID)
+		if err != nil {
+			return 0, err
+		}
+		if xctn == nil || xctn.Finished() {
+			return 0, cmn.NewErrXactNotFoundError("[" + flt.ID + "]")
+		}
+		if !xact.Table[xctn.Kind()].Pausable {
+			return 0, fmt.Errorf("%s cannot be paused (resumed)", xctn.Name())
+		}
+		if _pause(xctn, pause) {
+			n = 1
+		}
+		return n, nil
+	}
+	if flt.Kind != "" && !xact.Table[flt.Kind].Pausable {
+		return 0, fmt.Errorf("xaction kind %q cannot be paused (resumed)", flt.Kind)
+	}
+	r.entries.forEach(func(entry Renewable) bool {
+		xctn := entry.Get()
+		if xctn.Finished() || !xact.Table[xctn.Kind()].Pausable || !flt.Matches(xctn) {
+			return true
+		}
+		if _pause(xctn, pause) {
+			n++
+		}
+		return true
+	})
+	return n, nil
+}
+
+func _pause(xctn core.Xact, pause bool) bool {
+	if pause {
+		return xctn.Pause()
+	}
+	return xctn.Resume()
+}
+
 func GetSnap(flt Flt) ([]*core.Snap, error) {
 	var onlyRunning bool
 	if flt.OnlyRunning != nil {
@@ -575,6 +620,9 @@ func (r *registry) renewLocked(entry Renewable, flt Flt) (rns RenewRes) {
 			time.Sleep(waitPrevAborted)
 		}
 	}
+	if err = r.checkBckLimit(entry.Kind(), &flt); err != nil {
+		return RenewRes{Err: err}
+	}
 	if err = entry.Start(); err != nil {
 		return RenewRes{Err: err}
 	}
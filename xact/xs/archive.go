@@ -536,7 +536,7 @@ func (j *jogger) run() {
 
 func (wi *archwi) beginAppend() (lmfh cos.LomReader, err error) {
 	msg := wi.msg
-	if msg.Mime == archive.ExtTar {
+	if msg.Mime == archive.ExtTar && wi.archlom.SSERef() == "" { // (encrypted: can only copy and append)
 		err = wi.openTarForAppend()
 		if err == nil /*can append*/ || err != archive.ErrTarIsEmpty /*fail XactArch.Begin*/ {
 			return nil, err
@@ -558,7 +558,7 @@ func (wi *archwi) beginAppend() (lmfh cos.LomReader, err error) {
 }
 
 func (wi *archwi) openTarForAppend() (err error) {
-	if err = wi.archlom.RenameMainTo(wi.fqn); err != nil {
+	if err = wi.archlom.DetachMainTo(wi.fqn, nil); err != nil {
 		return err
 	}
 	// open (rw) lom itself
@@ -605,7 +605,7 @@ func (wi *archwi) do(lom *core.LOM, lrit *lrit) {
 		}
 	}
 
-	fh, err := cos.NewFileHandle(lom.FQN)
+	fh, err := lom.NewHandle(lom.FQN)
 	if err != nil {
 		wi.r.AddErr(err, 5, cos.SmoduleXs)
 		return
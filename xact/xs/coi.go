@@ -6,7 +6,11 @@
 package xs
 
 import (
+	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/mono"
 	"github.com/NVIDIA/aistore/core"
 	"github.com/NVIDIA/aistore/core/meta"
 	"github.com/NVIDIA/aistore/transport/bundle"
@@ -18,16 +22,61 @@ type (
 		Xact      core.Xact
 		Config    *cmn.Config
 		BckTo     *meta.Bck
+		SSE       *apc.SSEMsg // (optional) server-side encryption by remote destination backend (see core.SSEBackend)
 		ObjnameTo string
+		CustomMD  cos.StrKVs // (optional) custom metadata to add to the destination (e.g., sequence marker)
+		RStats    *CoiRStats // (optional) account for the time spent reading the source (except local fast path)
 		Buf       []byte
+		Opaque    []byte // (optional) passed to the receiving target via transport header (e.g., object tags)
 		OWT       cmn.OWT
 		Finalize  bool // copies and EC (as in poi.finalize())
 		DryRun    bool
 		LatestVer bool // can be used without changing bucket's 'versioning.validate_warm_get'; see also: QparamLatestVer
 		Sync      bool // ditto -  bucket's 'versioning.synchronize'
+		Flush     bool // write through to remote backend when copying remote bucket onto itself (see apc.CopyBckMsg.Flush)
 	}
 
 	COI interface {
 		CopyObject(lom *core.LOM, dm *bundle.DataMover, coi *CoiParams) (int64, error)
 	}
 )
+
+//
+// source reading stats (see CoiParams.RStats)
+//
+
+type (
+	// cumulative, across all source readers that share it
+	CoiRStats struct {
+		Ns   atomic.Int64 // time spent reading the source
+		Size atomic.Int64 // bytes read
+	}
+	rstatsROC struct {
+		cos.ReadOpenCloser
+		rs *CoiRStats
+	}
+)
+
+// interface guard
+var _ cos.ReadOpenCloser = (*rstatsROC)(nil)
+
+// wrap source reader to account for the time spent reading
+func (rs *CoiRStats) Reader(roc cos.ReadOpenCloser) cos.ReadOpenCloser {
+	return &rstatsROC{roc, rs}
+}
+
+func (r *rstatsROC) Read(p []byte) (n int, err error) {
+	started := mono.NanoTime()
+	n, err = r.ReadOpenCloser.Read(p)
+	r.rs.Ns.Add(mono.SinceNano(started))
+	r.rs.Size.Add(int64(n))
+	return n, err
+}
+
+func (r *rstatsROC) Open() (cos.ReadOpenCloser, error) {
+	roc, err := r.ReadOpenCloser.Open()
+	if err != nil {
+		return roc, err
+	}
+	return r.rs.Reader(roc), nil
+}
@@ -0,0 +1,591 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/ec"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/transport"
+	"github.com/NVIDIA/aistore/transport/bundle"
+	"github.com/NVIDIA/aistore/xact"
+	"github.com/NVIDIA/aistore/xact/xreg"
+)
+
+// x-drain: proactively migrate the data of the target in maintenance mode
+// (typically, the one recorded in RMD.TargetIDs) to the rest of the cluster:
+// - runs on the draining target and all active targets, with one common UUID;
+// - the draining target sends its primary replicas (mirrored copies excluded) to their
+//   HRW destinations, and its EC slices and replicas to the (HRW-ordered) targets that
+//   have none of the same object;
+// - receivers write objects (and CTs along with updated EC metadata), and then tell
+//   the remaining holders of the object's CTs to update their metafiles;
+// - the draining target sends keepalives while walking, and done-sending (OpcTxnDone) when done;
+// - each receiver, upon done-sending from the draining target, broadcasts its own done-sending
+//   to the other receivers, and terminates when all of them are done (or upon keepalive timeout);
+// - a single stream per destination keeps done-sending ordered after the data and EC metadata
+//   updates sent over the same stream;
+// upon success, the primary marks the target safe to decommission (meta.SnodeMaintPostReb)
+
+const (
+	// update EC metafile (in place): the CT moved from the draining target
+	opcDrainMD = OpcTxnDone + 1
+	// (draining target => receivers) still walking
+	opcDrainKeepalive = OpcTxnDone + 2
+)
+
+type (
+	drainFactory struct {
+		xreg.RenewBase
+		xctn *XactDrain
+	}
+	XactDrain struct {
+		dm     *bundle.DataMover
+		smap   *meta.Smap
+		config *cmn.Config
+		tid    string // draining target
+		// dm.Send (mocked in tests)
+		send func(*transport.Obj, cos.ReadOpenCloser, *meta.Snode) error
+		// (receivers) targets that have signaled OpcTxnDone
+		done struct {
+			sids map[string]struct{}
+			mu   sync.Mutex
+		}
+		rxlast  atomic.Int64 // (receivers) last received
+		refc    atomic.Int32 // (receivers) pending done-sending: the draining target and the other receivers
+		tidDone atomic.Bool  // (receivers) the draining target is done sending
+		fin     atomic.Bool  // (receivers) broadcast own done-sending
+		xact.Base
+	}
+)
+
+// interface guard
+var (
+	_ core.Xact      = (*XactDrain)(nil)
+	_ xreg.Renewable = (*drainFactory)(nil)
+)
+
+//////////////////
+// drainFactory //
+//////////////////
+
+func (*drainFactory) New(args xreg.Args, _ *meta.Bck) xreg.Renewable {
+	return &drainFactory{RenewBase: xreg.RenewBase{Args: args}}
+}
+
+func (p *drainFactory) Start() error {
+	tid, ok := p.Args.Custom.(string)
+	debug.Assert(ok && tid != "")
+
+	var (
+		config = cmn.GCO.Get()
+		smap   = core.T.Sowner().Get()
+		tsi    = smap.GetTarget(tid)
+	)
+	if tsi == nil {
+		return cos.NewErrNotFound(core.T, apc.ActDrain+": target "+meta.Tname(tid))
+	}
+	if !tsi.InMaint() {
+		return fmt.Errorf("%s: %s is not in maintenance mode", apc.ActDrain, tsi.StringEx())
+	}
+	r := &XactDrain{smap: smap, config: config, tid: tid}
+	r.InitBase(p.Args.UUID, p.Kind(), tsi.StringEx(), nil)
+	if !r.sender() {
+		// this target must be active
+		if err := core.InMaintOrDecomm(smap, core.T.Snode(), r); err != nil {
+			return err
+		}
+		r.refc.Store(int32(smap.CountActiveTs())) // the draining target + (active - self)
+	}
+
+	dmExtra := bundle.Extra{
+		RecvAck:     nil, // no ACKs
+		Config:      config,
+		Compression: config.Rebalance.Compression,
+		Multiplier:  1, // (ordering - see above)
+		QUIC:        config.Rebalance.QUIC,
+		ZstdLevel:   config.Rebalance.ZstdLevel,
+		Priority:    transport.PrioLow,
+	}
+	r.dm = bundle.NewDM(apc.ActDrain+"-"+p.Args.UUID, r.recv, cmn.OwtRebalance, dmExtra)
+	if err := r.dm.RegRecv(); err != nil {
+		return err
+	}
+	r.dm.SetXact(r)
+	r.send = r.dm.Send
+	p.xctn = r
+	return nil
+}
+
+func (*drainFactory) Kind() string     { return apc.ActDrain }
+func (p *drainFactory) Get() core.Xact { return p.xctn }
+
+func (p *drainFactory) WhenPrevIsRunning(prevEntry xreg.Renewable) (xreg.WPR, error) {
+	if p.UUID() == prevEntry.UUID() {
+		return xreg.WprUse, nil
+	}
+	return xreg.WprAbort, cmn.NewErrXactUsePrev(prevEntry.Get().String())
+}
+
+///////////////
+// XactDrain //
+///////////////
+
+func (r *XactDrain) sender() bool { return r.tid == core.T.SID() }
+
+func (r *XactDrain) Run(*sync.WaitGroup) {
+	nlog.Infoln(r.Name(), "started: draining", meta.Tname(r.tid))
+	r.dm.Open()
+
+	if r.sender() {
+		stop := make(chan struct{})
+		go r.keepalive(stop)
+		r.walk()
+		close(stop)
+		r.bcast(OpcTxnDone)
+	} else {
+		r.wait()
+	}
+
+	err := r.AbortErr()
+	r.dm.Close(err)
+	r.dm.UnregRecv()
+	r.Finish()
+}
+
+// header-only message to all active targets (not including self and the draining one)
+func (r *XactDrain) bcast(opcode int) {
+	for _, tsi := range r.smap.Tmap.ActiveNodes() {
+		if tsi.ID() == core.T.SID() {
+			continue
+		}
+		o := transport.AllocSend()
+		o.Hdr.Opcode = opcode
+		if err := r.send(o, nil, tsi); err != nil && opcode == OpcTxnDone {
+			r.AddErr(fmt.Errorf("%s: failed to send done to %s: %w", r, tsi.StringEx(), err))
+		}
+	}
+}
+
+func (r *XactDrain) Snap() (snap *core.Snap) {
+	snap = &core.Snap{}
+	r.ToSnap(snap)
+
+	snap.IdleX = r.IsIdle()
+	return
+}
+
+//
+// send (the draining target)
+//
+
+// one jogger per mountpath
+func (r *XactDrain) walk() {
+	var (
+		wg    sync.WaitGroup
+		avail = fs.GetAvail()
+	)
+	for _, mi := range avail {
+		wg.Add(1)
+		go r.jog(mi, &wg)
+	}
+	wg.Wait()
+}
+
+func (r *XactDrain) keepalive(stop <-chan struct{}) {
+	ticker := time.NewTicker(cmn.Rom.MaxKeepalive())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.bcast(opcDrainKeepalive)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (r *XactDrain) jog(mi *fs.Mountpath, wg *sync.WaitGroup) {
+	defer wg.Done()
+	bmd := core.T.Bowner().Get()
+	bmd.Range(nil, nil, func(bck *meta.Bck) bool {
+		opts := &fs.WalkOpts{Mi: mi, Sorted: false}
+		opts.Bck.Copy(bck.Bucket())
+		if bck.Props.EC.Enabled {
+			opts.CTs, opts.Callback = []string{fs.ECMetaType}, r.visitEC
+		} else {
+			opts.CTs, opts.Callback = []string{fs.ObjectType}, r.visitObj
+		}
+		if err := fs.Walk(opts); err != nil {
+			if r.IsAborted() {
+				return true
+			}
+			r.AddErr(fmt.Errorf("failed to traverse %s: %w", mi, err))
+		}
+		return r.IsAborted()
+	})
+}
+
+func (r *XactDrain) visitObj(fqn string, de fs.DirEntry) error {
+	if err := r.AbortErr(); err != nil {
+		return err
+	}
+	if de.IsDir() {
+		return nil
+	}
+	lom := core.AllocLOM(fqn)
+	defer core.FreeLOM(lom)
+	if err := lom.InitFQN(fqn, nil); err != nil {
+		if cmn.IsErrBucketLevel(err) {
+			return err
+		}
+		return nil
+	}
+	if lom.ECEnabled() {
+		return filepath.SkipDir // (EC props changed while walking)
+	}
+	tsi, err := r.smap.HrwHash2T(lom.Digest())
+	if err != nil {
+		return err
+	}
+	lom.Lock(false)
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		lom.Unlock(false)
+		return nil
+	}
+	if lom.IsCopy() {
+		lom.Unlock(false)
+		return nil
+	}
+	roc, err := lom.NewMigrateROC() // + unlock
+	if err != nil {
+		r.AddErr(err)
+		return nil
+	}
+	o := transport.AllocSend()
+	o.Hdr.Bck.Copy(lom.Bucket())
+	o.Hdr.ObjName = lom.ObjName
+	o.Hdr.ObjAttrs.CopyFrom(lom.ObjAttrs(), false /*skip cksum*/)
+	lom.SetMigrateAttrs(&o.Hdr.ObjAttrs)
+	o.Callback = r.sent
+	return r.send(o, roc, tsi)
+}
+
+// CTs (slices and replicas) this target has, along with their metadata
+func (r *XactDrain) visitEC(fqn string, de fs.DirEntry) error {
+	if err := r.AbortErr(); err != nil {
+		return err
+	}
+	if de.IsDir() {
+		return nil
+	}
+	ct, err := core.NewCTFromFQN(fqn, core.T.Bowner())
+	if err != nil {
+		return nil
+	}
+	md, err := ec.LoadMetadata(fqn)
+	if err != nil {
+		nlog.Warningln(r.Name(), err)
+		return nil
+	}
+	if _, ok := md.Daemons[r.tid]; !ok {
+		return nil
+	}
+	tsi, err := r.ecDest(ct, md)
+	if err != nil {
+		r.AddErr(err)
+		return nil
+	}
+
+	var (
+		roc cos.ReadOpenCloser
+		oa  cmn.ObjAttrs
+	)
+	if md.SliceID == 0 {
+		lom := core.AllocLOM(ct.ObjectName())
+		roc, err = r._replica(lom, ct)
+		if err == nil {
+			oa.CopyFrom(lom.ObjAttrs(), false /*skip cksum*/)
+		}
+		core.FreeLOM(lom)
+	} else {
+		roc, err = cos.NewFileHandle(ct.Make(fs.ECSliceType))
+		oa.Size = ec.SliceSize(md.Size, md.Data)
+	}
+	if err != nil {
+		if !os.IsNotExist(err) {
+			r.AddErr(err)
+		}
+		return nil
+	}
+	o := transport.AllocSend()
+	o.Hdr.Bck.Copy(ct.Bucket())
+	o.Hdr.ObjName = ct.ObjectName()
+	o.Hdr.ObjAttrs = oa
+	o.Hdr.Opaque = md.NewPack()
+	o.Callback = r.sent
+	return r.send(o, roc, tsi)
+}
+
+// rlock and keep it while sending
+func (*XactDrain) _replica(lom *core.LOM, ct *core.CT) (cos.ReadOpenCloser, error) {
+	if err := lom.InitBck(ct.Bucket()); err != nil {
+		return nil, err
+	}
+	lom.Lock(false)
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		lom.Unlock(false)
+		return nil, err
+	}
+	return lom.NewDeferROC()
+}
+
+// the first HRW target that has no CTs of the same object
+func (r *XactDrain) ecDest(ct *core.CT, md *ec.Metadata) (*meta.Snode, error) {
+	hlist, err := r.smap.HrwTargetList(ct.UnamePtr(), r.smap.CountActiveTs())
+	if err != nil {
+		return nil, err
+	}
+	for _, tsi := range hlist {
+		if _, ok := md.Daemons[tsi.ID()]; !ok {
+			return tsi, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: no target to receive %s[%d] (all %d have it)", r, ct.Cname(), md.SliceID, len(hlist))
+}
+
+func (r *XactDrain) sent(hdr *transport.ObjHdr, _ io.ReadCloser, _ any, err error) {
+	if err == nil {
+		return
+	}
+	if cmn.IsErrStreamTerminated(err) {
+		r.Abort(err)
+		return
+	}
+	r.AddErr(fmt.Errorf("failed to send %s: %w", hdr.Cname(), err))
+}
+
+//
+// receive (all other targets)
+//
+
+func (r *XactDrain) wait() {
+	r.rxlast.Store(mono.NanoTime())
+
+	// 1. the draining target is done sending, and everything it sent is received
+	if !r.quiesce() {
+		return
+	}
+	// 2. own EC metadata updates (opcDrainMD) precede own done-sending
+	r.bcast(OpcTxnDone)
+	r.fin.Store(true)
+
+	// 3. all the other receivers are done, too
+	r.quiesce()
+}
+
+func (r *XactDrain) quiesce() bool {
+	switch r.Quiesce(cmn.Rom.CplaneOperation(), r.qcb) {
+	case core.QuiAborted:
+		return false
+	case core.QuiTimeout:
+		r.AddErr(fmt.Errorf("%s: %v (pending done-sending: %d)", r, cmn.ErrQuiesceTimeout, r.refc.Load()))
+	}
+	return true
+}
+
+func (r *XactDrain) pending() bool {
+	if r.fin.Load() {
+		return r.refc.Load() > 0
+	}
+	return !r.tidDone.Load()
+}
+
+func (r *XactDrain) qcb(time.Duration) core.QuiRes {
+	since := mono.Since(r.rxlast.Load())
+	if r.pending() {
+		// the draining target sends keepalives while walking;
+		// the other receivers are expected to follow its done-sending shortly
+		if since > max(r.config.Timeout.MaxHostBusy.D(), cmn.Rom.MaxKeepalive()) {
+			return core.QuiTimeout
+		}
+		return core.QuiActive
+	}
+	if since > cmn.Rom.CplaneOperation() {
+		return core.QuiDone
+	}
+	return core.QuiInactiveCB
+}
+
+func (r *XactDrain) recv(hdr *transport.ObjHdr, objReader io.Reader, err error) error {
+	if err != nil && !cos.IsEOF(err) {
+		nlog.Errorln(err)
+		return err
+	}
+	r.rxlast.Store(mono.NanoTime())
+	switch {
+	case hdr.Opcode == OpcTxnDone:
+		r.doneSending(hdr.SID)
+		return nil
+	case hdr.Opcode == opcDrainKeepalive:
+		return nil
+	case hdr.Opcode == opcDrainMD:
+		if err := r.recvMD(hdr); err != nil {
+			r.AddErr(fmt.Errorf("%s: failed to update EC metadata of %s: %w", r, hdr.Cname(), err))
+		}
+		return nil
+	case len(hdr.Opaque) > 0:
+		err = r.recvCT(hdr, objReader)
+	default:
+		err = r.recvObj(hdr, objReader)
+	}
+	transport.DrainAndFreeReader(objReader)
+	r.rxlast.Store(mono.NanoTime())
+	if err != nil {
+		r.AddErr(err)
+	}
+	return err
+}
+
+// idempotent; counting only the draining target and the other receivers
+func (r *XactDrain) doneSending(sid string) {
+	tsi := r.smap.GetTarget(sid)
+	if tsi == nil || (sid != r.tid && tsi.InMaintOrDecomm()) {
+		nlog.Warningln(r.Name(), "unexpected done-sending from", meta.Tname(sid), "- ignoring")
+		return
+	}
+	r.done.mu.Lock()
+	_, dup := r.done.sids[sid]
+	if !dup {
+		if r.done.sids == nil {
+			r.done.sids = make(map[string]struct{}, 4)
+		}
+		r.done.sids[sid] = struct{}{}
+	}
+	r.done.mu.Unlock()
+	if dup {
+		return
+	}
+	if sid == r.tid {
+		r.tidDone.Store(true)
+	}
+	r.refc.Dec()
+}
+
+func (r *XactDrain) recvObj(hdr *transport.ObjHdr, objReader io.Reader) error {
+	lom := core.AllocLOM(hdr.ObjName)
+	defer core.FreeLOM(lom)
+	if err := lom.InitBck(&hdr.Bck); err != nil {
+		return err
+	}
+	if lom.Load(false, false) == nil && lom.CheckEq(&hdr.ObjAttrs) == nil {
+		return nil // already here (e.g., drained before)
+	}
+	lom.CopyAttrs(&hdr.ObjAttrs, true /*skip cksum*/)
+	params := core.AllocPutParams()
+	{
+		params.WorkTag = fs.WorkfilePut
+		params.Reader = io.NopCloser(objReader)
+		params.OWT = cmn.OwtRebalance
+		params.Cksum = hdr.ObjAttrs.Cksum
+		params.Atime = lom.Atime()
+		params.Xact = r
+	}
+	err := core.T.PutObject(lom, params)
+	core.FreePutParams(params)
+	return err
+}
+
+// write CT and its metadata that now points to this target
+// (in place of the draining one), and notify the other holders
+func (r *XactDrain) recvCT(hdr *transport.ObjHdr, objReader io.Reader) (err error) {
+	md := &ec.Metadata{}
+	if err := cos.NewUnpacker(hdr.Opaque).ReadAny(md); err != nil {
+		return fmt.Errorf("%s: invalid EC metadata of %s: %w", r, hdr.Cname(), err)
+	}
+	self := core.T.SID()
+	delete(md.Daemons, hdr.SID)
+	md.Daemons[self] = uint16(md.SliceID)
+	if md.FullReplica == hdr.SID {
+		md.FullReplica = self
+	}
+
+	pack := md.NewPack()
+	args := &ec.WriteArgs{Reader: objReader, MD: pack, Generation: md.Generation, Xact: r}
+	if md.SliceID != 0 {
+		err = ec.WriteSliceAndMeta(hdr, args)
+	} else {
+		var lom *core.LOM
+		if lom, err = ec.AllocLomFromHdr(hdr); err == nil {
+			args.Cksum = hdr.ObjAttrs.Cksum
+			err = ec.WriteReplicaAndMeta(lom, args)
+			core.FreeLOM(lom)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	r.fwdMD(hdr, md, pack)
+	return nil
+}
+
+// tell the other (active) holders; sent prior to this target's done-sending
+func (r *XactDrain) fwdMD(hdr *transport.ObjHdr, md *ec.Metadata, pack []byte) {
+	for _, tsi := range md.RemoteTargets() {
+		if tsi.InMaintOrDecomm() {
+			continue
+		}
+		o := transport.AllocSend()
+		o.Hdr.Bck.Copy(&hdr.Bck)
+		o.Hdr.ObjName = hdr.ObjName
+		o.Hdr.Opcode = opcDrainMD
+		o.Hdr.Opaque = pack
+		if err := r.send(o, nil, tsi); err != nil {
+			r.AddErr(fmt.Errorf("%s: failed to send EC metadata of %s to %s: %w", r, hdr.Cname(), tsi.StringEx(), err))
+		}
+	}
+}
+
+// same generation: update CT locations
+func (*XactDrain) recvMD(hdr *transport.ObjHdr) error {
+	md := &ec.Metadata{}
+	if err := cos.NewUnpacker(hdr.Opaque).ReadAny(md); err != nil {
+		return err
+	}
+	ctMeta, err := core.NewCTFromBO(&hdr.Bck, hdr.ObjName, core.T.Bowner(), fs.ECMetaType)
+	if err != nil {
+		return err
+	}
+	ctMeta.Lock(true)
+	defer ctMeta.Unlock(true)
+	local, err := ec.LoadMetadata(ctMeta.FQN())
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return err
+	}
+	if local.Generation != md.Generation {
+		return nil
+	}
+	local.Daemons, local.FullReplica = md.Daemons, md.FullReplica
+	return ctMeta.Write(cos.NewByteHandle(local.NewPack()), -1, "" /*work fqn*/)
+}
@@ -0,0 +1,233 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/core/mock"
+	"github.com/NVIDIA/aistore/ec"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/tools/tassert"
+	"github.com/NVIDIA/aistore/transport"
+)
+
+type drainSent struct {
+	tid    string
+	opcode int
+}
+
+// self and three other targets ("t1" through "t3"), one of which is draining;
+// all sends are recorded, and the ones to `fail` fail
+func newTestDrain(t *testing.T, tid string, fail ...string) (*XactDrain, *[]drainSent) {
+	var (
+		bck    = meta.NewBck("ec", apc.AIS, cmn.NsGlobal, &cmn.Bprops{EC: cmn.ECConf{Enabled: true}})
+		bmd    = mock.NewBaseBownerMock(bck)
+		tmock  = mock.NewTarget(bmd)
+		sowner = &tcbtSowner{}
+		sent   = &[]drainSent{}
+	)
+	sowner.smap.Tmap = make(meta.NodeMap, 4)
+	for _, id := range []string{tmock.SID(), "t1", "t2", "t3"} {
+		si := &meta.Snode{}
+		si.Init(id, apc.Target)
+		if id == tid {
+			si.Flags = meta.SnodeMaint
+		}
+		sowner.smap.Tmap[id] = si
+	}
+	tmock.SO = sowner
+	core.T = tmock
+
+	fs.TestNew(nil)
+	_, err := fs.Add(t.TempDir(), tmock.SID())
+	tassert.CheckFatal(t, err)
+	fs.CSM.Reg(fs.ECMetaType, &fs.ECMetaContentResolver{}, true)
+	fs.CSM.Reg(fs.WorkfileType, &fs.WorkfileContentResolver{}, true)
+	if errs := fs.CreateBucket(bck.Bucket(), false /*nilbmd*/); len(errs) > 0 {
+		t.Fatal(errs[0])
+	}
+
+	r := &XactDrain{smap: &sowner.smap, config: &cmn.Config{}, tid: tid}
+	r.config.Timeout.MaxHostBusy = cos.Duration(10 * time.Second)
+	r.InitBase(cos.GenUUID(), apc.ActDrain, "", nil)
+	if !r.sender() {
+		r.refc.Store(int32(r.smap.CountActiveTs()))
+	}
+	r.send = func(o *transport.Obj, _ cos.ReadOpenCloser, tsi *meta.Snode) error {
+		*sent = append(*sent, drainSent{tsi.ID(), o.Hdr.Opcode})
+		for _, id := range fail {
+			if id == tsi.ID() {
+				return errors.New("send failed")
+			}
+		}
+		return nil
+	}
+	return r, sent
+}
+
+func (s drainSent) String() string { return s.tid + ":" + strconv.Itoa(s.opcode) }
+
+func TestDrainECDest(t *testing.T) {
+	r, _ := newTestDrain(t, "t1")
+	ct, err := core.NewCTFromBO(&cmn.Bck{Name: "ec", Provider: apc.AIS}, "obj", core.T.Bowner(), fs.ECMetaType)
+	tassert.CheckFatal(t, err)
+	hlist, err := r.smap.HrwTargetList(ct.UnamePtr(), r.smap.CountActiveTs())
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(hlist) == 3, "expected 3 active targets, got %d", len(hlist))
+
+	// the first HRW target that has none of the object's CTs
+	md := ec.NewMetadata()
+	md.Daemons = cos.MapStrUint16{"t1": 1, hlist[0].ID(): 2}
+	tsi, err := r.ecDest(ct, md)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, tsi.ID() == hlist[1].ID(), "expected %s, got %s", hlist[1], tsi)
+
+	md.Daemons[hlist[1].ID()] = 3
+	tsi, err = r.ecDest(ct, md)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, tsi.ID() == hlist[2].ID(), "expected %s, got %s", hlist[2], tsi)
+
+	// never the draining one; none when all active targets have it
+	md.Daemons[hlist[2].ID()] = 4
+	_, err = r.ecDest(ct, md)
+	tassert.Errorf(t, err != nil, "expected no destination when all active targets have the object's CTs")
+}
+
+func TestDrainDoneSending(t *testing.T) {
+	var (
+		r, _ = newTestDrain(t, "t1")
+		done = func(sid string) {
+			tassert.CheckFatal(t, r.recv(&transport.ObjHdr{SID: sid, Opcode: OpcTxnDone}, nil, nil))
+		}
+		idle = func(d time.Duration) { r.rxlast.Store(mono.NanoTime() - int64(d)) }
+	)
+	tassert.Fatalf(t, r.refc.Load() == 3, "expected the draining target and two other receivers, got %d", r.refc.Load())
+
+	// 1. waiting for the draining target (keepalives reset the idle time)
+	done("t2")
+	done("t2")
+	done("unknown")
+	tassert.Errorf(t, r.refc.Load() == 2, "expected refc 2, got %d", r.refc.Load())
+	tassert.Errorf(t, r.qcb(0) == core.QuiActive, "expected to wait for the draining target")
+	idle(time.Minute)
+	tassert.Errorf(t, r.qcb(0) == core.QuiTimeout, "expected keepalive timeout")
+	tassert.CheckFatal(t, r.recv(&transport.ObjHdr{SID: "t1", Opcode: opcDrainKeepalive}, nil, nil))
+	tassert.Errorf(t, r.qcb(0) == core.QuiActive, "expected keepalive to reset the idle time")
+
+	done("t1")
+	done("t1")
+	tassert.Errorf(t, r.tidDone.Load() && r.refc.Load() == 1, "expected draining target done, refc 1, got %d", r.refc.Load())
+	tassert.Errorf(t, r.qcb(0) == core.QuiInactiveCB, "expected to wait for in-flight receives")
+	idle(2 * cmn.Rom.CplaneOperation())
+	tassert.Errorf(t, r.qcb(0) == core.QuiDone, "expected phase 1 done")
+
+	// 2. waiting for the other receivers
+	r.fin.Store(true)
+	tassert.Errorf(t, r.qcb(0) == core.QuiActive, "expected to wait for t3")
+	idle(time.Minute)
+	tassert.Errorf(t, r.qcb(0) == core.QuiTimeout, "expected timeout waiting for t3")
+	done("t3")
+	tassert.Errorf(t, r.refc.Load() == 0, "expected refc 0, got %d", r.refc.Load())
+	tassert.Errorf(t, r.qcb(0) == core.QuiInactiveCB, "expected to wait for in-flight receives")
+	idle(2 * cmn.Rom.CplaneOperation())
+	tassert.Errorf(t, r.qcb(0) == core.QuiDone, "expected done")
+}
+
+func TestDrainBcastDone(t *testing.T) {
+	// the draining target: all active targets get done-sending, despite failures
+	r, sent := newTestDrain(t, mock.NewTarget(nil).SID(), "t1")
+	r.bcast(OpcTxnDone)
+	tids := make([]string, 0, len(*sent))
+	for _, s := range *sent {
+		tassert.Errorf(t, s.opcode == OpcTxnDone, "unexpected %s", s)
+		tids = append(tids, s.tid)
+	}
+	sort.Strings(tids)
+	tassert.Errorf(t, len(tids) == 3 && tids[0] == "t1" && tids[2] == "t3", "expected done-sending to t1..t3, got %v", tids)
+	tassert.Errorf(t, r.ErrCnt() == 1, "expected 1 error, got %d (%v)", r.ErrCnt(), r.Err())
+
+	// a receiver: the other receivers only
+	r, sent = newTestDrain(t, "t1")
+	r.bcast(OpcTxnDone)
+	tids = tids[:0]
+	for _, s := range *sent {
+		tids = append(tids, s.tid)
+	}
+	sort.Strings(tids)
+	tassert.Errorf(t, len(tids) == 2 && tids[0] == "t2" && tids[1] == "t3", "expected done-sending to t2, t3, got %v", tids)
+	tassert.Errorf(t, r.ErrCnt() == 0, "expected no errors, got %v", r.Err())
+}
+
+func TestDrainECMetaFwd(t *testing.T) {
+	var (
+		r, sent = newTestDrain(t, "t1", "t3")
+		self    = core.T.SID()
+		hdr     = &transport.ObjHdr{Bck: cmn.Bck{Name: "ec", Provider: apc.AIS}, ObjName: "obj", SID: "t1"}
+		md      = ec.NewMetadata()
+	)
+	md.Generation, md.SliceID = 1, 2
+	md.Daemons = cos.MapStrUint16{self: 2, "t2": 1, "t3": 3}
+
+	// forwarding: to the other active holders; failures are errors
+	r.fwdMD(hdr, md, md.NewPack())
+	tids := make([]string, 0, 2)
+	for _, s := range *sent {
+		tassert.Errorf(t, s.opcode == opcDrainMD, "unexpected %s", s)
+		tids = append(tids, s.tid)
+	}
+	sort.Strings(tids)
+	tassert.Errorf(t, len(tids) == 2 && tids[0] == "t2" && tids[1] == "t3", "expected EC metadata sent to t2, t3, got %v", tids)
+	tassert.Errorf(t, r.ErrCnt() == 1, "expected 1 error, got %d (%v)", r.ErrCnt(), r.Err())
+
+	// receiving: local metafile of the same generation gets updated
+	ct, err := core.NewCTFromBO(&hdr.Bck, hdr.ObjName, core.T.Bowner(), fs.ECMetaType)
+	tassert.CheckFatal(t, err)
+	local := ec.NewMetadata()
+	local.Generation, local.SliceID = 1, 2
+	local.Daemons = cos.MapStrUint16{self: 2, "t1": 1, "t3": 3}
+	tassert.CheckFatal(t, cos.CreateDir(filepath.Dir(ct.FQN())))
+	tassert.CheckFatal(t, ct.Write(cos.NewByteHandle(local.NewPack()), -1, ""))
+
+	md.Daemons = cos.MapStrUint16{self: 2, "t2": 1, "t3": 3}
+	md.FullReplica = "t2"
+	hdr.Opcode, hdr.Opaque, hdr.SID = opcDrainMD, md.NewPack(), "t2"
+	tassert.CheckFatal(t, r.recv(hdr, nil, nil))
+	updated, err := ec.LoadMetadata(ct.FQN())
+	tassert.CheckFatal(t, err)
+	_, stale := updated.Daemons["t1"]
+	tassert.Errorf(t, !stale && updated.Daemons["t2"] == 1 && updated.FullReplica == "t2", "expected updated locations, got %v", updated.Daemons)
+	tassert.Errorf(t, updated.SliceID == 2, "expected local slice ID to be preserved, got %d", updated.SliceID)
+
+	// different generation: not updated
+	md.Generation = 2
+	md.Daemons = cos.MapStrUint16{self: 2, "t3": 3}
+	hdr.Opaque = md.NewPack()
+	tassert.CheckFatal(t, r.recv(hdr, nil, nil))
+	updated, err = ec.LoadMetadata(ct.FQN())
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, updated.Daemons["t2"] == 1, "expected generation mismatch to be ignored, got %v", updated.Daemons)
+	tassert.Errorf(t, r.ErrCnt() == 1, "expected no new errors, got %d (%v)", r.ErrCnt(), r.Err())
+}
+
+func TestDrainAbort(t *testing.T) {
+	r, sent := newTestDrain(t, "t1")
+	r.Abort(errors.New("test abort"))
+	r.wait()
+	tassert.Errorf(t, len(*sent) == 0, "expected no done-sending upon abort, got %v", *sent)
+	tassert.Errorf(t, r.IsAborted() && r.refc.Load() == 3, "expected aborted with nothing received")
+}
@@ -9,11 +9,13 @@ import (
 	"sync"
 
 	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/xact/xreg"
 )
 
 var (
-	gcoi COI // target
+	gcoi    COI              // target
+	gtstats cos.StatsUpdater // ditto (stats.Trunner)
 
 	coiPool sync.Pool // mem pool
 	coi0    CoiParams
@@ -24,11 +26,12 @@ func Preg() {
 	xreg.RegNonBckXact(&eleFactory{})
 }
 
-func Treg(coi COI) {
+func Treg(coi COI, tstats cos.StatsUpdater) {
 	xreg.RegNonBckXact(&eleFactory{})
 
 	xreg.RegNonBckXact(&resFactory{})
 	xreg.RegNonBckXact(&rebFactory{})
+	xreg.RegNonBckXact(&drainFactory{})
 	xreg.RegNonBckXact(&etlFactory{})
 
 	xreg.RegBckXact(&bmvFactory{})
@@ -40,8 +43,12 @@ func Treg(coi COI) {
 
 	xreg.RegBckXact(&proFactory{})
 	xreg.RegBckXact(&llcFactory{})
+	xreg.RegBckXact(&lcyFactory{})
+	xreg.RegBckXact(&rotkeyFactory{})
+	xreg.RegBckXact(&quotaFactory{})
+	xreg.RegBckXact(&replFactory{})
 
-	gcoi = coi
+	gcoi, gtstats = coi, tstats
 	xreg.RegBckXact(&tcbFactory{kind: apc.ActCopyBck})
 	xreg.RegBckXact(&tcbFactory{kind: apc.ActETLBck})
 	xreg.RegBckXact(&tcoFactory{streamingF: streamingF{kind: apc.ActETLObjects}})
@@ -0,0 +1,222 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/mpather"
+	"github.com/NVIDIA/aistore/xact"
+	"github.com/NVIDIA/aistore/xact/xreg"
+)
+
+// Enforce bucket lifecycle rules (see cmn.LifecycleConf):
+// - runs periodically (see xreg.RenewLifecycle) and on demand (api.StartXaction);
+// - visits all (locally stored) objects in a given bucket and, for each object, applies
+//   the first applicable action of the matching rules:
+//   expiration (remove), transition (copy to the destination bucket, then remove), or
+//   eviction (remote buckets only: remove in-cluster copy).
+
+const lcyDay = 24 * time.Hour
+
+type (
+	lcyFactory struct {
+		xreg.RenewBase
+		xctn *XactLcy
+	}
+	XactLcy struct {
+		now   time.Time
+		rules []cmn.LifecycleRule
+		dsts  []*meta.Bck // transition destinations (nil when not applicable), one per rule
+		xact.BckJog
+		remote bool
+	}
+)
+
+// interface guard
+var (
+	_ core.Xact      = (*XactLcy)(nil)
+	_ xreg.Renewable = (*lcyFactory)(nil)
+)
+
+////////////////
+// lcyFactory //
+////////////////
+
+func (*lcyFactory) New(args xreg.Args, bck *meta.Bck) xreg.Renewable {
+	return &lcyFactory{RenewBase: xreg.RenewBase{Args: args, Bck: bck}}
+}
+
+func (p *lcyFactory) Start() error {
+	p.xctn = newXactLcy(p.UUID(), p.Bck)
+	go p.xctn.Run(nil)
+	return nil
+}
+
+func (*lcyFactory) Kind() string     { return apc.ActLifecycle }
+func (p *lcyFactory) Get() core.Xact { return p.xctn }
+
+func (*lcyFactory) WhenPrevIsRunning(xreg.Renewable) (xreg.WPR, error) { return xreg.WprUse, nil }
+
+/////////////
+// XactLcy //
+/////////////
+
+func newXactLcy(uuid string, bck *meta.Bck) (r *XactLcy) {
+	r = &XactLcy{
+		now:    time.Now(),
+		rules:  bck.Props.Lifecycle.Rules,
+		remote: bck.IsRemote(),
+	}
+	mpopts := &mpather.JgroupOpts{
+		CTs:                   []string{fs.ObjectType},
+		VisitObj:              r.visit,
+		DoLoad:                mpather.Load,
+		SkipGloballyMisplaced: true,
+		Throttle:              true,
+		Priority:              mpather.PriorityLow,
+	}
+	mpopts.Bck.Copy(bck.Bucket())
+	r.BckJog.Init(uuid, apc.ActLifecycle, "" /*ctlmsg*/, bck, mpopts, cmn.GCO.Get())
+	r.initDsts(bck)
+	return
+}
+
+func (r *XactLcy) initDsts(bck *meta.Bck) {
+	r.dsts = make([]*meta.Bck, len(r.rules))
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if rule.TransitionDays == 0 {
+			continue
+		}
+		dst := meta.CloneBck(&rule.TransitionBck)
+		if err := dst.Init(core.T.Bowner()); err != nil {
+			r.AddErr(err)
+			continue
+		}
+		if dst.Equal(bck, true, true) {
+			nlog.Warningln(r.Name(), "rule", i, "- cannot transition", bck.Cname(""), "onto itself")
+			continue
+		}
+		r.dsts[i] = dst
+	}
+}
+
+func (r *XactLcy) Run(*sync.WaitGroup) {
+	r.BckJog.Run()
+	nlog.Infoln(r.Name(), "rules:", len(r.rules))
+	err := r.BckJog.Wait()
+	if err != nil {
+		r.AddErr(err)
+	}
+	r.Finish()
+}
+
+func (r *XactLcy) visit(lom *core.LOM, buf []byte) error {
+	var mtime time.Time
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if !rule.Match(lom.ObjName) {
+			continue
+		}
+		if mtime.IsZero() {
+			_, _, mt, err := lom.Fstat(false /*get atime*/)
+			if err != nil {
+				return nil // (e.g., removed in the meantime)
+			}
+			mtime = mt
+		}
+		age := r.now.Sub(mtime)
+		switch {
+		case rule.ExpireDays > 0 && age >= time.Duration(rule.ExpireDays)*lcyDay:
+			r.expire(lom)
+			return nil
+		case r.dsts[i] != nil && age >= time.Duration(rule.TransitionDays)*lcyDay:
+			r.transition(lom, r.dsts[i], buf)
+			return nil
+		case rule.EvictDays > 0 && r.remote && r.now.Sub(lom.Atime()) >= time.Duration(rule.EvictDays)*lcyDay:
+			r.evict(lom)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *XactLcy) expire(lom *core.LOM) {
+	size := lom.Lsize()
+	if ecode, err := core.T.DeleteObject(lom, false /*evict*/); err != nil {
+		r._err(lom, err, ecode)
+		return
+	}
+	if cmn.Rom.FastV(5, cos.SmoduleXs) {
+		nlog.Infoln(r.Name(), "expired", lom.Cname())
+	}
+	r.ObjsAdd(1, size)
+}
+
+func (r *XactLcy) evict(lom *core.LOM) {
+	size := lom.Lsize()
+	if ecode, err := core.T.EvictObject(lom); err != nil {
+		r._err(lom, err, ecode)
+		return
+	}
+	if cmn.Rom.FastV(5, cos.SmoduleXs) {
+		nlog.Infoln(r.Name(), "evicted", lom.Cname())
+	}
+	r.ObjsAdd(1, size)
+}
+
+// copy, and only then remove the source (counted once, by CopyObject)
+func (r *XactLcy) transition(lom *core.LOM, dst *meta.Bck, buf []byte) {
+	coiParams := AllocCOI()
+	{
+		coiParams.Xact = r
+		coiParams.Config = r.Config
+		coiParams.BckTo = dst
+		coiParams.ObjnameTo = lom.ObjName
+		coiParams.Buf = buf
+		coiParams.OWT = cmn.OwtCopy
+	}
+	_, err := gcoi.CopyObject(lom, nil /*DM*/, coiParams)
+	FreeCOI(coiParams)
+	if err != nil {
+		r._err(lom, err, 0)
+		return
+	}
+	if ecode, err := core.T.DeleteObject(lom, false /*evict*/); err != nil {
+		r._err(lom, err, ecode)
+		return
+	}
+	if cmn.Rom.FastV(5, cos.SmoduleXs) {
+		nlog.Infoln(r.Name(), "transitioned", lom.Cname(), "=>", dst.Cname(lom.ObjName))
+	}
+}
+
+func (r *XactLcy) _err(lom *core.LOM, err error, ecode int) {
+	if cos.IsNotExist(err, ecode) || cmn.IsErrObjNought(err) || cmn.IsErrObjLocked(err) {
+		return // (locked objects are skipped)
+	}
+	r.AddErr(err, 4, cos.SmoduleXs)
+	if cmn.Rom.FastV(4, cos.SmoduleXs) {
+		nlog.Warningln(r.Name(), lom.Cname(), err)
+	}
+}
+
+func (r *XactLcy) Snap() (snap *core.Snap) {
+	snap = &core.Snap{}
+	r.ToSnap(snap)
+
+	snap.IdleX = r.IsIdle()
+	return
+}
@@ -152,7 +152,7 @@ func (p *lsoFactory) beginStreams(r *LsoXact) error {
 		r.remtCh = make(chan *LsoRsp, remtPageChSize) // <= by selected target (selected to page remote bucket)
 	}
 	trname := "lso-" + p.UUID()
-	dmxtra := bundle.Extra{Multiplier: 1, Config: r.config}
+	dmxtra := bundle.Extra{Multiplier: 1, Config: r.config, Priority: transport.PrioHigh} // (user-facing)
 	p.dm = bundle.NewDM(trname, r.recv, cmn.OwtPut, dmxtra)
 
 	if err := p.dm.RegRecv(); err != nil {
@@ -301,6 +301,8 @@ func (r *XactNsumm) cloneRes(dst, src *cmn.BsummResult) {
 	if r.listRemote {
 		dst.ObjCount.Remote = ratomic.LoadUint64(&src.ObjCount.Remote)
 		dst.TotalSize.RemoteObjs = ratomic.LoadUint64(&src.TotalSize.RemoteObjs)
+		dst.Quota.Size = ratomic.LoadUint64(&src.Quota.Size)
+		dst.Quota.Objects = ratomic.LoadUint64(&src.Quota.Objects)
 	}
 
 	dst.ObjSize.Max = ratomic.LoadInt64(&src.ObjSize.Max)
@@ -349,6 +351,10 @@ func (r *XactNsumm) visitObj(lom *core.LOM, _ []byte) error {
 //
 
 func (r *XactNsumm) runCloudBck(bck *meta.Bck, res *cmn.BsummResult) {
+	// remote storage that can tell its own usage (no need to list)
+	if r.p.msg.Prefix == "" && r.backendUsage(bck, res) {
+		return
+	}
 	lsmsg := &apc.LsoMsg{Props: apc.GetPropsSize, Prefix: r.p.msg.Prefix}
 	lsmsg.SetFlag(apc.LsNameSize | apc.LsNoDirs)
 	for !r.IsAborted() {
@@ -369,3 +375,23 @@ func (r *XactNsumm) runCloudBck(bck *meta.Bck, res *cmn.BsummResult) {
 		}
 	}
 }
+
+func (r *XactNsumm) backendUsage(bck *meta.Bck, res *cmn.BsummResult) bool {
+	ub, ok := core.T.Backend(bck).(core.UsageBackend)
+	if !ok {
+		return false
+	}
+	usage, _, err := ub.BucketUsage(bck)
+	if err != nil {
+		nlog.Warningln(r.Name(), "failed to get", bck.Cname(""), "usage from the backend (falling back to listing):", err)
+		return false
+	}
+	if usage == nil {
+		return false
+	}
+	ratomic.StoreUint64(&res.ObjCount.Remote, usage.ObjCount)
+	ratomic.StoreUint64(&res.TotalSize.RemoteObjs, usage.Size)
+	ratomic.StoreUint64(&res.Quota.Size, usage.QuotaSize)
+	ratomic.StoreUint64(&res.Quota.Objects, usage.QuotaObjs)
+	return true
+}
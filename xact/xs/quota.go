@@ -0,0 +1,183 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"sync"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/mpather"
+	"github.com/NVIDIA/aistore/xact"
+	"github.com/NVIDIA/aistore/xact/xreg"
+)
+
+// Usage accounting for bucket and per-user quotas (see cmn.QuotaConf):
+// - each target tracks the usage of its locally stored objects: per bucket and,
+//   within each bucket, per object owner (cmn.OwnerObjMD);
+// - the usage is updated in place upon PUT, append, and delete (see QuotaAdd), and gets
+//   periodically recomputed from scratch (see xreg.RenewQuotaUsage) to correct
+//   for overwrites, rebalance, and other operations that do not update it;
+// - proxies sum up the usage reported by all targets (apc.WhatQuotaUsage).
+
+type (
+	quotaFactory struct {
+		xreg.RenewBase
+		xctn *XactQuota
+	}
+	XactQuota struct {
+		usage *qbck
+		xact.BckJog
+		mu sync.Mutex
+	}
+
+	qbck struct {
+		users map[string]*apc.Usage // by owner
+		apc.Usage
+	}
+	qtracker struct {
+		bcks map[string]*qbck // by bucket cname
+		mu   sync.Mutex
+	}
+)
+
+// interface guard
+var (
+	_ core.Xact      = (*XactQuota)(nil)
+	_ xreg.Renewable = (*quotaFactory)(nil)
+)
+
+var qtrack = qtracker{bcks: make(map[string]*qbck)}
+
+func newQbck() *qbck { return &qbck{users: make(map[string]*apc.Usage)} }
+
+func (q *qbck) add(owner string, size, objects int64) {
+	q.Usage.Add(size, objects)
+	if owner == "" {
+		return
+	}
+	u, ok := q.users[owner]
+	if !ok {
+		u = &apc.Usage{}
+		q.users[owner] = u
+	}
+	u.Add(size, objects)
+}
+
+// update in place: (size, objects) may be negative
+func QuotaAdd(lom *core.LOM, size, objects int64) {
+	owner, _ := lom.GetCustomKey(cmn.OwnerObjMD)
+	cname := lom.Bck().Cname("")
+	qtrack.mu.Lock()
+	q, ok := qtrack.bcks[cname]
+	if !ok {
+		q = newQbck()
+		qtrack.bcks[cname] = q
+	}
+	q.add(owner, size, objects)
+	qtrack.mu.Unlock()
+}
+
+// (destroyed bucket)
+func QuotaDelBck(bck *meta.Bck) {
+	qtrack.mu.Lock()
+	delete(qtrack.bcks, bck.Cname(""))
+	qtrack.mu.Unlock()
+}
+
+// current local usage
+func QuotaUsage() *apc.QuotaUsage {
+	qu := apc.NewQuotaUsage()
+	qtrack.mu.Lock()
+	for cname, q := range qtrack.bcks {
+		qu.Buckets[cname] = &apc.Usage{Size: q.Size, Objects: q.Objects}
+		for owner, u := range q.users {
+			if uu, ok := qu.Users[owner]; ok {
+				uu.Add(u.Size, u.Objects)
+			} else {
+				qu.Users[owner] = &apc.Usage{Size: u.Size, Objects: u.Objects}
+			}
+		}
+	}
+	qtrack.mu.Unlock()
+	return qu
+}
+
+//////////////////
+// quotaFactory //
+//////////////////
+
+func (*quotaFactory) New(args xreg.Args, bck *meta.Bck) xreg.Renewable {
+	return &quotaFactory{RenewBase: xreg.RenewBase{Args: args, Bck: bck}}
+}
+
+func (p *quotaFactory) Start() error {
+	p.xctn = newXactQuota(p.UUID(), p.Bck)
+	go p.xctn.Run(nil)
+	return nil
+}
+
+func (*quotaFactory) Kind() string     { return apc.ActQuotaUsage }
+func (p *quotaFactory) Get() core.Xact { return p.xctn }
+
+func (*quotaFactory) WhenPrevIsRunning(xreg.Renewable) (xreg.WPR, error) { return xreg.WprUse, nil }
+
+///////////////
+// XactQuota //
+///////////////
+
+func newXactQuota(uuid string, bck *meta.Bck) (r *XactQuota) {
+	r = &XactQuota{usage: newQbck()}
+	mpopts := &mpather.JgroupOpts{
+		CTs:                   []string{fs.ObjectType},
+		VisitObj:              r.visit,
+		DoLoad:                mpather.Load,
+		SkipGloballyMisplaced: true,
+		Throttle:              true,
+		Priority:              mpather.PriorityLow,
+	}
+	mpopts.Bck.Copy(bck.Bucket())
+	r.BckJog.Init(uuid, apc.ActQuotaUsage, "" /*ctlmsg*/, bck, mpopts, cmn.GCO.Get())
+	return
+}
+
+func (r *XactQuota) Run(*sync.WaitGroup) {
+	r.BckJog.Run()
+	nlog.Infoln(r.Name())
+	err := r.BckJog.Wait()
+	if err != nil {
+		r.AddErr(err)
+	}
+	if err == nil && !r.IsAborted() {
+		// replace the bucket's (in-place updated) usage
+		qtrack.mu.Lock()
+		qtrack.bcks[r.Bck().Cname("")] = r.usage
+		qtrack.mu.Unlock()
+	}
+	r.Finish()
+}
+
+func (r *XactQuota) visit(lom *core.LOM, _ []byte) error {
+	owner, _ := lom.GetCustomKey(cmn.OwnerObjMD)
+	size := lom.Lsize()
+	r.mu.Lock()
+	r.usage.add(owner, size, 1)
+	r.mu.Unlock()
+	r.ObjsAdd(1, size)
+	return nil
+}
+
+func (r *XactQuota) Snap() (snap *core.Snap) {
+	snap = &core.Snap{}
+	r.ToSnap(snap)
+
+	snap.IdleX = r.IsIdle()
+	return
+}
@@ -0,0 +1,504 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/mpather"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/xact"
+	"github.com/NVIDIA/aistore/xact/xreg"
+)
+
+// Cross-cluster asynchronous bucket replication (see cmn.ReplicationConf):
+// - on-demand xaction that ships new and updated objects to the destination bucket
+//   in a remote AIS cluster via the remote-AIS backend;
+// - driven by writes (PUT, APPEND, copy) and by resync (see Resync below) that walks the bucket
+//   and picks up objects that were not replicated yet or modified since;
+// - per-object replication state (cmn.ReplStateObjMD) records the source modification time
+//   and the destination version, to resume after restarts and to detect conflicts, i.e.,
+//   destination objects created or modified in the remote cluster since last replicated;
+// - one worker per mountpath: updates of a given object are replicated in order;
+// - active-active (cmn.ReplicationConf.ActiveActive): replicated writes carry the object's
+//   version vector (bumped once per local modification) and origin write; the receiving
+//   side accepts, discards, or resolves them (see ais/tgtrepl) and, when resolution
+//   produces a new version vector, forwards the result back.
+
+const (
+	replBurst     = 512 // per-mountpath work queue
+	replStateSepa = ","
+	replFwd       = "fwd-" // active-active: to be replicated as is (see SetReplState)
+)
+
+type (
+	replFactory struct {
+		xreg.RenewBase
+		xctn *XactRepl
+	}
+	// replication result to record (see setState)
+	replRes struct {
+		mtime  time.Time
+		tag    string
+		basevv string            // active-active: version vector prior to replicating
+		vv     cmn.VersionVector // and after
+		w      cmn.ReplWrite
+	}
+	XactRepl struct {
+		dst     *meta.Bck
+		bp      core.Backend
+		workers *mpather.WorkerGroup
+		vlabs   map[string]string
+		conf    cmn.ReplicationConf
+		xact.DemandBase
+		mu       sync.RWMutex // posting vs stopping
+		chanFull atomic.Int64
+		syncing  atomic.Bool
+		stopped  bool
+	}
+)
+
+var errReplStopped = errors.New("replication stopped")
+
+// interface guard
+var (
+	_ core.Xact      = (*XactRepl)(nil)
+	_ xreg.Renewable = (*replFactory)(nil)
+)
+
+/////////////////
+// replFactory //
+/////////////////
+
+func (*replFactory) New(args xreg.Args, bck *meta.Bck) xreg.Renewable {
+	return &replFactory{RenewBase: xreg.RenewBase{Args: args, Bck: bck}}
+}
+
+func (p *replFactory) Start() error {
+	bck := p.Bck
+	conf := bck.Props.Replication
+	if !conf.Enabled {
+		return fmt.Errorf("%s: replication disabled, nothing to do", bck)
+	}
+	dst := meta.CloneBck(&conf.Bck)
+	if err := dst.Init(core.T.Bowner()); err != nil {
+		return err
+	}
+	r := &XactRepl{
+		conf:  conf,
+		dst:   dst,
+		bp:    core.T.Backend(dst),
+		vlabs: map[string]string{stats.VarlabBucket: bck.Cname("")},
+	}
+
+	// target-local generation of a global UUID (compare with x-put-copies)
+	div := uint64(xact.IdleDefault)
+	beid, _, _ := xreg.GenBEID(div, append([]byte(p.Kind()+"|"), bck.MakeUname("")...))
+	if beid == "" {
+		beid = cos.GenUUID()
+	}
+	r.DemandBase.Init(beid, p.Kind(), "" /*ctlmsg*/, bck, xact.IdleDefault)
+
+	r.workers = mpather.NewWorkerGroup(&mpather.WorkerGroupOpts{
+		Callback:  r.do,
+		QueueSize: replBurst,
+	})
+	p.xctn = r
+
+	go r.Run(nil)
+	return nil
+}
+
+func (*replFactory) Kind() string     { return apc.ActReplicate }
+func (p *replFactory) Get() core.Xact { return p.xctn }
+
+func (*replFactory) WhenPrevIsRunning(xreg.Renewable) (xreg.WPR, error) { return xreg.WprUse, nil }
+
+//////////////
+// XactRepl //
+//////////////
+
+func (r *XactRepl) Run(*sync.WaitGroup) {
+	nlog.Infoln(r.Name(), "=>", r.dst.Cname(""))
+	r.workers.Run()
+loop:
+	for {
+		select {
+		case <-r.IdleTimer():
+			break loop
+		case <-r.ChanAbort():
+			break loop
+		}
+	}
+	r.stop()
+	r.Finish()
+}
+
+func (r *XactRepl) stop() {
+	r.mu.Lock()
+	r.stopped = true
+	r.mu.Unlock()
+
+	r.DemandBase.Stop()
+	if n := r.workers.Stop(); n > 0 {
+		r.SubPending(n)
+		nlog.Warningln(r.Name(), "dropped", n, "pending object(s) - to be replicated upon resync")
+	}
+	if cnt := r.chanFull.Load(); cnt > 0 && (cnt <= 20 || cmn.Rom.FastV(5, cos.SmoduleXs)) {
+		nlog.Errorln(cos.ErrWorkChanFull, "(all mp workers)", r.String(), "cnt", cnt)
+	}
+}
+
+// main method: replicate new or updated object
+func (r *XactRepl) Repl(lom *core.LOM) { r.post(lom) }
+
+func (r *XactRepl) post(lom *core.LOM) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.stopped {
+		return false
+	}
+	// ref-count on-demand, decrement via worker.Callback = r.do
+	r.IncPending()
+	chanFull, err := r.workers.PostLIF(lom)
+	if err != nil {
+		r.DecPending()
+		r.Abort(fmt.Errorf("%s: %v", r, err))
+		return false
+	}
+	if chanFull {
+		r.chanFull.Inc()
+	}
+	return true
+}
+
+// Walk the bucket and post objects that were not replicated yet or modified since.
+// Runs in the background - at most one at a time.
+func (r *XactRepl) Resync() {
+	if !r.syncing.CAS(false, true) {
+		return
+	}
+	r.IncPending() // (not to idle in the meantime)
+	go r.resync()
+}
+
+func (r *XactRepl) resync() {
+	var (
+		wg    sync.WaitGroup
+		cnt   atomic.Int64
+		avail = fs.GetAvail()
+	)
+	for _, mi := range avail {
+		wg.Add(1)
+		go func(mi *fs.Mountpath) {
+			defer wg.Done()
+			cb := func(fqn string, de fs.DirEntry) error {
+				if de.IsDir() {
+					return nil
+				}
+				lom := core.AllocLOM("")
+				posted, err := r._resync(lom, fqn)
+				core.FreeLOM(lom)
+				if posted {
+					cnt.Inc()
+				}
+				return err
+			}
+			opts := &fs.WalkOpts{Mi: mi, Bck: *r.Bck().Bucket(), CTs: []string{fs.ObjectType}, Callback: cb}
+			if err := fs.Walk(opts); err != nil && err != errReplStopped {
+				r.AddErr(err, 4, cos.SmoduleXs)
+			}
+		}(mi)
+	}
+	wg.Wait()
+	if n := cnt.Load(); n > 0 {
+		nlog.Infoln(r.Name(), "resync: posted", n, "object(s)")
+	}
+	r.syncing.Store(false)
+	r.DecPending()
+}
+
+func (r *XactRepl) _resync(lom *core.LOM, fqn string) (bool, error) {
+	if err := lom.InitFQN(fqn, r.Bck().Bucket()); err != nil || !lom.IsHRW() {
+		return false, nil
+	}
+	if err := lom.Load(false /*cache it*/, false /*locked*/); err != nil {
+		return false, nil
+	}
+	_, _, mtime, err := lom.Fstat(false /*get atime*/)
+	if err != nil {
+		return false, nil
+	}
+	if stamp, _ := replState(lom); stamp == replStamp(mtime) {
+		return false, nil // up to date
+	}
+	if !r.post(lom) {
+		return false, errReplStopped
+	}
+	return true, nil
+}
+
+// (one worker per mountpath)
+func (r *XactRepl) do(lom *core.LOM, _ []byte) {
+	size, err := r.replicate(lom)
+	switch {
+	case err != nil:
+		r.AddErr(err, 4, cos.SmoduleXs)
+		gtstats.AddWith(cos.NamedVal64{Name: stats.ErrReplCount, Value: 1, VarLabs: r.vlabs})
+	case size >= 0:
+		r.ObjsAdd(1, size)
+	}
+	r.DecPending() // (see IncPending above)
+	core.FreeLOM(lom)
+}
+
+// returns the size of the replicated object, or -1 when there's nothing to do
+func (r *XactRepl) replicate(lom *core.LOM) (int64, error) {
+	var (
+		res  replRes
+		size int64
+		err  error
+	)
+	lom.Lock(false)
+	if r.conf.ActiveActive {
+		size, err = r._replAA(lom, &res)
+	} else {
+		size, err = r._repl(lom, &res)
+	}
+	lom.Unlock(false)
+	if err != nil || res.mtime.IsZero() {
+		return -1, err
+	}
+	if err := r.setState(lom, &res); err != nil {
+		return -1, err
+	}
+	mtime := res.mtime
+	if size < 0 {
+		return -1, nil
+	}
+	gtstats.AddWith(
+		cos.NamedVal64{Name: stats.ReplCount, Value: 1, VarLabs: r.vlabs},
+		cos.NamedVal64{Name: stats.ReplSize, Value: size, VarLabs: r.vlabs},
+		cos.NamedVal64{Name: stats.ReplLag, Value: int64(time.Since(mtime)), VarLabs: r.vlabs},
+	)
+	if cmn.Rom.FastV(5, cos.SmoduleXs) {
+		nlog.Infoln(r.Name(), lom.Cname(), "=>", r.dst.Cname(lom.ObjName))
+	}
+	return size, nil
+}
+
+// under rlock; returns the size of the sent object (-1: not sent) and fills in
+// the object's modification time and destination version to record (zero mtime: nothing to do)
+func (r *XactRepl) _repl(lom *core.LOM, res *replRes) (int64, error) {
+	mtime, err := r.load(lom)
+	if err != nil || mtime.IsZero() {
+		return -1, err
+	}
+	_, rtag := replState(lom)
+
+	dlom := core.AllocLOM(lom.ObjName)
+	defer core.FreeLOM(dlom)
+	if err := dlom.InitBck(r.dst.Bucket()); err != nil {
+		return -1, err
+	}
+	oa, ecode, errH := r.bp.HeadObj(context.Background(), dlom, nil /*origReq*/)
+	switch {
+	case errH == nil:
+		res.mtime, res.tag = mtime, replTag(oa)
+		if lom.EqCksum(oa.Cksum) && lom.Lsize() == oa.Size {
+			return -1, nil // in sync (e.g., replicated prior to restart)
+		}
+		if rtag == "" || rtag != res.tag {
+			gtstats.AddWith(cos.NamedVal64{Name: stats.ReplConflictCount, Value: 1, VarLabs: r.vlabs})
+			if cmn.Rom.FastV(4, cos.SmoduleXs) {
+				nlog.Infoln(r.Name(), "conflict:", r.dst.Cname(lom.ObjName), "[", rtag, res.tag, r.conf.Conflict, "]")
+			}
+			if r.conf.Conflict == apc.ReplConflictSkip {
+				return -1, nil
+			}
+		}
+	case cos.IsNotExist(errH, ecode):
+	default:
+		return -1, errH
+	}
+
+	if err := r.send(lom, dlom, nil /*origReq*/); err != nil {
+		return -1, err
+	}
+	res.mtime, res.tag = mtime, replTag(dlom.ObjAttrs())
+	return lom.Lsize(), nil
+}
+
+// active-active: no conflict detection on this side - the receiving cluster
+// compares version vectors and resolves
+func (r *XactRepl) _replAA(lom *core.LOM, res *replRes) (int64, error) {
+	mtime, err := r.load(lom)
+	if err != nil || mtime.IsZero() {
+		return -1, err
+	}
+	res.basevv, _ = lom.GetCustomKey(cmn.ReplVVObjMD)
+	if res.vv, res.w, err = ReplLocalVV(lom, mtime, core.T.Sowner().Get().UUID); err != nil {
+		return -1, err
+	}
+
+	dlom := core.AllocLOM(lom.ObjName)
+	defer core.FreeLOM(dlom)
+	if err := dlom.InitBck(r.dst.Bucket()); err != nil {
+		return -1, err
+	}
+	origReq := &http.Request{Header: make(http.Header, 2)}
+	origReq.Header.Set(apc.HdrObjReplVV, res.vv.String())
+	origReq.Header.Set(apc.HdrObjReplWrite, res.w.String())
+	if err := r.send(lom, dlom, origReq); err != nil {
+		return -1, err
+	}
+	res.mtime, res.tag = mtime, replTag(dlom.ObjAttrs())
+	return lom.Lsize(), nil
+}
+
+// load the object and return its modification time (zero: nothing to do)
+func (*XactRepl) load(lom *core.LOM) (time.Time, error) {
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		if cos.IsNotExist(err, 0) {
+			err = nil // deleted in the meantime
+		}
+		return time.Time{}, err
+	}
+	_, _, mtime, err := lom.Fstat(false /*get atime*/)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if stamp, _ := replState(lom); stamp == replStamp(mtime) {
+		return time.Time{}, nil // up to date
+	}
+	return mtime, nil
+}
+
+func (r *XactRepl) send(lom, dlom *core.LOM, origReq *http.Request) error {
+	fh, err := lom.NewHandle(lom.FQN)
+	if err != nil {
+		return err
+	}
+	dlom.CopyAttrs(lom.ObjAttrs(), false /*skip cksum*/)
+	_, err = r.bp.PutObj(fh, dlom, origReq)
+	return err
+}
+
+// record replication state unless the object was modified in the meantime;
+// active-active: record the (bumped) version vector unless updated by the peer in the meantime
+func (*XactRepl) setState(lom *core.LOM, res *replRes) error {
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		if cos.IsNotExist(err, 0) {
+			return nil
+		}
+		return err
+	}
+	_, _, mtime, err := lom.Fstat(false /*get atime*/)
+	if err != nil {
+		return nil
+	}
+	modified := !mtime.Equal(res.mtime) // (will be replicated again)
+	if res.vv != nil {
+		if vv, _ := lom.GetCustomKey(cmn.ReplVVObjMD); vv != res.basevv {
+			return nil
+		}
+		lom.SetCustomKey(cmn.ReplVVObjMD, res.vv.String())
+		lom.SetCustomKey(cmn.ReplWriteObjMD, res.w.String())
+	} else if modified {
+		return nil
+	}
+	if !modified {
+		lom.SetCustomKey(cmn.ReplStateObjMD, replStamp(mtime)+replStateSepa+res.tag)
+	}
+	return lom.Persist()
+}
+
+func (r *XactRepl) Snap() (snap *core.Snap) {
+	snap = &core.Snap{}
+	r.ToSnap(snap)
+
+	snap.IdleX = r.IsIdle()
+	return
+}
+
+//
+// replication state
+//
+
+func replStamp(mtime time.Time) string { return strconv.FormatInt(mtime.UnixNano(), 10) }
+
+// destination's version or, if not versioned, checksum
+func replTag(oa *cmn.ObjAttrs) string {
+	if v := oa.Version(); v != "" {
+		return v
+	}
+	if !oa.Cksum.IsEmpty() {
+		return oa.Cksum.Value()
+	}
+	return ""
+}
+
+// active-active: the object's version vector and origin write, given its modification time;
+// a local modification that hasn't been replicated yet increments the local cluster's counter
+// (once - see setState)
+func ReplLocalVV(lom *core.LOM, mtime time.Time, self string) (vv cmn.VersionVector, w cmn.ReplWrite, err error) {
+	v, _ := lom.GetCustomKey(cmn.ReplVVObjMD)
+	if vv, err = cmn.ParseVV(v); err != nil {
+		return nil, w, fmt.Errorf("%s: %v", lom.Cname(), err)
+	}
+	if v, ok := lom.GetCustomKey(cmn.ReplWriteObjMD); ok {
+		if w, err = cmn.ParseReplWrite(v); err != nil {
+			return nil, w, fmt.Errorf("%s: %v", lom.Cname(), err)
+		}
+	}
+	stamp, _ := replState(lom)
+	switch {
+	case stamp == replStamp(mtime) || stamp == replFwd+replStamp(mtime):
+		// received from the peer, replicated, or resolved - no local modification since
+	case w.Origin == self && w.Time == mtime.UnixNano():
+		// already counted
+	default:
+		vv.Inc(self)
+		w = cmn.ReplWrite{Origin: self, Time: mtime.UnixNano()}
+	}
+	return vv, w, nil
+}
+
+// active-active: mark the object modified at `mtime` as either received from the peer
+// (nothing to replicate) or, if fwd, as resolved and pending replication
+func SetReplState(lom *core.LOM, mtime time.Time, fwd bool) {
+	stamp := replStamp(mtime)
+	if fwd {
+		stamp = replFwd + stamp
+	}
+	lom.SetCustomKey(cmn.ReplStateObjMD, stamp+replStateSepa)
+}
+
+func replState(lom *core.LOM) (stamp, tag string) {
+	v, ok := lom.GetCustomKey(cmn.ReplStateObjMD)
+	if !ok {
+		return "", ""
+	}
+	stamp, tag, _ = strings.Cut(v, replStateSepa)
+	return stamp, tag
+}
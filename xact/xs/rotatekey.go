@@ -0,0 +1,209 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"sync"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/mpather"
+	"github.com/NVIDIA/aistore/fs/sse"
+	"github.com/NVIDIA/aistore/xact"
+	"github.com/NVIDIA/aistore/xact/xreg"
+)
+
+// Rotate bucket's data key and re-encrypt its objects (see cmn.EncryptionConf):
+// - upon start, drops the current data key (see sse.Rotate), so that all subsequent
+//   writes get encrypted with a new one;
+// - visits all (locally stored) objects and re-encrypts those that are not encrypted
+//   with the current key - including objects written before the encryption was enabled
+//   and, when encryption is disabled, decrypts all;
+// - mirrored copies (if any) are removed and then re-created from the re-encrypted main replica;
+// - objects encrypted with client-supplied keys (see sse.IsCustomer) are skipped.
+
+const rotkeyWorkfile = "rotate-key"
+
+type (
+	rotkeyFactory struct {
+		xreg.RenewBase
+		xctn *XactRotateKey
+	}
+	XactRotateKey struct {
+		conf cmn.EncryptionConf
+		xact.BckJog
+		bid uint64
+	}
+)
+
+// interface guard
+var (
+	_ core.Xact      = (*XactRotateKey)(nil)
+	_ xreg.Renewable = (*rotkeyFactory)(nil)
+)
+
+///////////////////
+// rotkeyFactory //
+///////////////////
+
+func (*rotkeyFactory) New(args xreg.Args, bck *meta.Bck) xreg.Renewable {
+	return &rotkeyFactory{RenewBase: xreg.RenewBase{Args: args, Bck: bck}}
+}
+
+func (p *rotkeyFactory) Start() error {
+	p.xctn = newXactRotateKey(p.UUID(), p.Bck)
+	go p.xctn.Run(nil)
+	return nil
+}
+
+func (*rotkeyFactory) Kind() string     { return apc.ActRotateKey }
+func (p *rotkeyFactory) Get() core.Xact { return p.xctn }
+
+func (*rotkeyFactory) WhenPrevIsRunning(xreg.Renewable) (xreg.WPR, error) { return xreg.WprUse, nil }
+
+///////////////////
+// XactRotateKey //
+///////////////////
+
+func newXactRotateKey(uuid string, bck *meta.Bck) (r *XactRotateKey) {
+	r = &XactRotateKey{conf: bck.Props.Encryption, bid: bck.Props.BID}
+	mpopts := &mpather.JgroupOpts{
+		CTs:                   []string{fs.ObjectType},
+		VisitObj:              r.visit,
+		DoLoad:                mpather.Load,
+		SkipGloballyMisplaced: true,
+		Throttle:              true,
+		Priority:              mpather.PriorityLow,
+	}
+	mpopts.Bck.Copy(bck.Bucket())
+	r.BckJog.Init(uuid, apc.ActRotateKey, "" /*ctlmsg*/, bck, mpopts, cmn.GCO.Get())
+	if r.conf.Enabled {
+		sse.Rotate(r.bid)
+	}
+	return
+}
+
+func (r *XactRotateKey) Run(*sync.WaitGroup) {
+	// generate the new key (or fail) prior to visiting
+	if r.conf.Enabled {
+		if _, err := sse.Current(r.bid, r.conf.KeyID); err != nil {
+			r.AddErr(err)
+			r.Finish()
+			return
+		}
+	}
+	r.BckJog.Run()
+	nlog.Infoln(r.Name(), "encryption:", r.conf.Enabled, r.conf.KeyID)
+	err := r.BckJog.Wait()
+	if err != nil {
+		r.AddErr(err)
+	}
+	r.Finish()
+}
+
+func (r *XactRotateKey) visit(lom *core.LOM, buf []byte) error {
+	if !lom.IsHRW() {
+		return nil // copies (re-created with the main replica)
+	}
+	if sse.IsCustomer(lom.SSERef()) {
+		return nil // (the key is never stored)
+	}
+	var want string
+	if r.conf.Enabled {
+		dk, err := sse.Current(r.bid, r.conf.KeyID)
+		if err != nil {
+			return err // (fatal)
+		}
+		want = dk.Ref
+	}
+	if lom.SSERef() == want {
+		return nil
+	}
+
+	lom.Lock(true)
+	err := lom.Load(false /*cache it*/, true /*locked*/)
+	if err == nil && lom.SSERef() != want && !sse.IsCustomer(lom.SSERef()) {
+		err = r.do(lom, buf)
+	}
+	lom.Unlock(true)
+
+	if err != nil {
+		if cos.IsNotExist(err, 0) || cmn.IsErrObjNought(err) {
+			return nil
+		}
+		r.AddErr(err, 4, cos.SmoduleXs)
+		return nil
+	}
+	if cmn.Rom.FastV(5, cos.SmoduleXs) {
+		nlog.Infoln(r.Name(), "re-encrypted", lom.Cname())
+	}
+	r.ObjsAdd(1, lom.Lsize())
+	return nil
+}
+
+// under wlock: decrypt (with the object's key) => encrypt (with the current one)
+func (r *XactRotateKey) do(lom *core.LOM, buf []byte) error {
+	var mis []*fs.Mountpath
+	if lom.HasCopies() {
+		for fqn, mi := range lom.GetCopies() {
+			if fqn != lom.FQN {
+				mis = append(mis, mi)
+			}
+		}
+		if err := lom.DelAllCopies(); err != nil {
+			return err
+		}
+	}
+
+	lmfh, err := lom.Open() // (reading with the current data key)
+	if err != nil {
+		return err
+	}
+	workFQN := fs.CSM.Gen(lom, fs.WorkfileType, rotkeyWorkfile)
+	wfh, err := lom.CreateWork(workFQN) // (new data key reference)
+	if err != nil {
+		cos.Close(lmfh)
+		return err
+	}
+	_, err = cos.CopyBuffer(wfh, lmfh, buf)
+	cos.Close(lmfh)
+	if errC := wfh.Close(); err == nil {
+		err = errC
+	}
+	if err == nil {
+		if err = lom.RenameFinalize(workFQN); err == nil {
+			err = lom.PersistMain()
+		}
+	}
+	if err != nil {
+		if errRemove := cos.RemoveFile(workFQN); errRemove != nil {
+			nlog.Errorln("nested err:", errRemove)
+		}
+		lom.Uncache()
+		return err
+	}
+
+	// restore copies
+	for _, mi := range mis {
+		if errC := lom.Copy(mi, buf); errC != nil {
+			r.AddErr(errC, 4, cos.SmoduleXs)
+		}
+	}
+	return nil
+}
+
+func (r *XactRotateKey) Snap() (snap *core.Snap) {
+	snap = &core.Snap{}
+	r.ToSnap(snap)
+
+	snap.IdleX = r.IsIdle()
+	return
+}
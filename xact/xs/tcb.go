@@ -8,6 +8,7 @@ package xs
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -23,6 +24,7 @@ import (
 	"github.com/NVIDIA/aistore/core/meta"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/fs/mpather"
+	"github.com/NVIDIA/aistore/hk"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/transport/bundle"
@@ -42,18 +44,140 @@ type (
 	XactTCB struct {
 		p      *tcbFactory
 		dm     *bundle.DataMover
+		budget *mpather.BufBudget
 		rxlast atomic.Int64 // finishing
 		xact.BckJog
-		prune    prune
-		nam, str string
-		wg       sync.WaitGroup // starting up
-		refc     atomic.Int32   // finishing
+		prune     prune
+		arch      *tcbArch     // when copying into destination archives (see apc.ArchTCBMsg)
+		cond      tcbCond      // conditional copy (see apc.CopyBckMsg.IfNoneMatch)
+		names     *tcbNames    // destination name collisions (see apc.CopyBckMsg.OnCollision)
+		manifest  *tcbManifest // destination manifest (see apc.CopyBckMsg.Manifest)
+		verify    *tcbVerify   // verification pass (see apc.CopyBckMsg.VerifyAfter)
+		pit       *tcbPIT      // point-in-time copy (see apc.CopyBckMsg.Snapshot)
+		ec        *tcbEC       // objects that exist only as EC slices (see tcbEC)
+		hook      *tcbHook     // per-object completion hook (see SetObjHook)
+		diff      *tcbDiff     // differential copy (see apc.CopyBckMsg.Diff)
+		tags      tcbTags      // object tags (see apc.CopyBckMsg.PreserveTags)
+		seq       tcbSeq       // sequence markers (see apc.CopyBckMsg.PreserveOrder)
+		skip      tcbSkip      // operator skip-list (see SkipObjs)
+		tput      tcbTput      // source-read vs destination-write throughput
+		cksum     tcbCksum     // destination checksums (see tcbCksum)
+		sse       tcbSSE       // server-side encryption (see apc.CopyBckMsg.SSE)
+		preds     tcbPreds     // custom skip decisions (see AddSkipPred)
+		dl        tcbDeadline  // (see apc.CopyBckMsg.Deadline)
+		prog      tcbProgress  // progress subscriptions (see SubscribeProgress)
+		throttle  tcbThrottle  // bandwidth limit (see SetRateLimit)
+		ckpt      *tcbCkpt     // when checkpointing (see apc.CopyBckMsg.Checkpoint)
+		filter    *tcbFilter   // source names: regex and/or template
+		est       *tcbEst      // dry-run estimate
+		retry     tcbRetry     // per-object retries
+		eta       tcbETA       // estimated time remaining
+		tune      *tcbTune     // ETL: auto-tuned parallelism
+		cache     *tcbCache    // ETL: transform result caching (see apc.Transform.Cache)
+		srcChange atomic.Int64 // num times source was found modified while being copied (see apc.CopyBckMsg.OnSrcChange)
+		nam, str  string
+		wg        sync.WaitGroup // starting up
+		refc      atomic.Int32   // finishing
+		done      struct {       // senders that have signaled OpcTxnDone
+			sids map[string]struct{}
+			mu   sync.Mutex
+		}
+		dmst struct { // data mover lifecycle: serializes opening (Run) with closing (Run and TxnAbort)
+			mu     sync.Mutex
+			opened bool
+			closed bool
+		}
+	}
+
+	// conditional copy counters
+	tcbCond struct {
+		skipped atomic.Int64 // destination exists and matches (not copied)
+		changed atomic.Int64 // destination exists but differs (copied)
+		absent  atomic.Int64 // destination does not exist (copied)
+	}
+
+	// extended x-tcb statistics
+	ExtTCBStats struct {
+		BufSizes    map[string]int64 `json:"buf-sizes,omitempty"` // effective read buffer size per mountpath
+		CondSkipped int64            `json:"cond.skipped.n,string"`
+		CondChanged int64            `json:"cond.changed.n,string"`
+		CondAbsent  int64            `json:"cond.absent.n,string"`
+		Collisions  int64            `json:"collisions.n,string"`  // destination name collisions
+		SrcChanged  int64            `json:"src-changed.n,string"` // source objects found modified while being copied
+		InFlight    []string         `json:"in-flight,omitempty"`  // names of the objects being copied right now (bounded)
+		DeltaCnt    int64            `json:"delta.n,string"`       // differential copy: num objects to copy
+		DeltaSize   int64            `json:"delta.size,string"`    // ditto, total size
+		Priority    string           `json:"priority"`             // effective (see apc.CopyBckMsg.Priority)
+		TagsCopied  int64            `json:"tags.n,string"`        // objects with their tags copied
+		TagsSkipped int64            `json:"tags.skip.n,string"`   // ditto, not copied (unsupported)
+		// verification pass (see apc.CopyBckMsg.VerifyAfter)
+		MismatchedNames []string `json:"verify.mismatched,omitempty"` // bounded
+		Verified        int64    `json:"verify.n,string"`
+		Mismatched      int64    `json:"verify.mismatch.n,string"`
+		Recopied        int64    `json:"verify.recopy.n,string"`
+		// estimated time remaining: average and adaptive (see cmn.TCBConf.EtaWindow); zero - unknown
+		EtaAvg      time.Duration `json:"eta.avg"`
+		EtaAdaptive time.Duration `json:"eta.adaptive"`
+		// point-in-time copy (see apc.CopyBckMsg.Snapshot)
+		PitSkipped int64 `json:"pit.skip.n,string"` // source objects written after the instant
+		// read buffers (see cmn.TCBConf.BufBudget)
+		BufUsed    int64 `json:"buf.used,string"`        // this x-tcb
+		BudgetUsed int64 `json:"buf.budget.used,string"` // all x-tcb's on this target
+		// per-object completion hook (see XactTCB.SetObjHook)
+		HookCalled  int64 `json:"hook.n,string"`
+		HookErrs    int64 `json:"hook.err.n,string"`
+		HookDropped int64 `json:"hook.drop.n,string"` // queue full
+		// sequence markers (see apc.CopyBckMsg.PreserveOrder)
+		SeqCarried int64 `json:"seq.n,string"`
+		SeqMissing int64 `json:"seq.miss.n,string"` // source not in cluster and has no marker
+		// objects restored from EC slices prior to copying (see tcbEC)
+		ECRecovered int64         `json:"ec.recover.n,string"`
+		ECFailed    int64         `json:"ec.recover.err.n,string"`
+		ECTime      time.Duration `json:"ec.recover.time"` // total time restoring
+		// operator skip-list (see XactTCB.SkipObjs)
+		OpSkippedNames []string `json:"op.skipped,omitempty"` // bounded
+		OpSkipped      int64    `json:"op.skip.n,string"`
+		// source-read vs destination-write throughput, bytes per second; zero - unknown
+		ReadTput  int64 `json:"tput.read,string"`
+		WriteTput int64 `json:"tput.write,string"`
+		// destination checksums computed according to the destination bucket (see tcbCksum)
+		CksumRecomputed int64 `json:"cksum.recompute.n,string"`
+		// server-side encryption by remote destination backend (see apc.CopyBckMsg.SSE)
+		SSEApplied int64 `json:"sse.n,string"`
+		SSESkipped int64 `json:"sse.skip.n,string"` // not supported
+		// custom skip decisions: num skipped objects by reason (see XactTCB.AddSkipPred)
+		SkipReasons map[string]int64 `json:"skip.reasons,omitempty"`
+		// stopped upon reaching deadline (see apc.CopyBckMsg.Deadline)
+		DeadlineReached bool `json:"deadline.reached,omitempty"`
+		// bandwidth limit (bytes per second; zero - unlimited) and total time throttled
+		RateLimit int64         `json:"rate.limit,string"`
+		Throttled time.Duration `json:"rate.throttled"`
+		// checkpoint ID and the number of source objects visited prior to interruption (see apc.CopyBckMsg.Resume)
+		CkptID      string `json:"ckpt.id,omitempty"`
+		CkptSkipped int64  `json:"ckpt.skip.n,string,omitempty"`
+		// dry-run: projected transfer volume and time (see apc.CopyBckMsg.DryRun)
+		DryRun *TCBEstimate `json:"dry-run,omitempty"`
+		// per-object retries upon transient errors (see cmn.TCBConf.Retries)
+		Retries        int64 `json:"retry.n,string"`
+		RetryRecovered int64 `json:"retry.ok.n,string"` // objects copied after retrying
+		// ETL: current (auto-tuned) number of objects transformed in parallel per mountpath (see cmn.TCBConf.ETLMaxParallel)
+		Parallel int `json:"parallel,omitempty"`
+		// ETL: transform result caching (see apc.Transform.Cache)
+		CacheHits   int64 `json:"cache.hit.n,string"`  // destination up to date (skipped)
+		CacheMisses int64 `json:"cache.miss.n,string"` // ditto, absent or outdated (transformed)
 	}
 )
 
 const OpcTxnDone = 27182
 
-const etlBucketParallelCnt = 2
+// max number of in-flight object names in the xaction's snapshot (see XactTCB.InFlight)
+const tcbMaxInFlight = 64
+
+// default read buffer sizes (see also: cmn.TCBConf)
+const (
+	dfltBufSizeHDD = cos.MiB
+	dfltBufSizeSSD = memsys.MaxPageSlabSize
+)
 
 // interface guard
 var (
@@ -77,13 +201,16 @@ func (p *tcbFactory) Start() error {
 	)
 	debug.AssertNoErr(err)
 
-	p.owt = cmn.OwtCopy
-	if p.kind == apc.ActETLBck {
-		p.owt = cmn.OwtTransform
-	}
+	p.owt = tcbOWT(p.kind, p.args)
 
 	smap := core.T.Sowner().Get()
 	p.xctn = newTCB(p, slab, config, smap)
+	if msg := p.args.Msg; msg.Arch != nil {
+		p.xctn.arch = &tcbArch{}
+		if err := p.xctn.arch.init(p.xctn, msg.Arch); err != nil {
+			return err
+		}
+	}
 
 	// refcount OpcTxnDone; this target must ve active (ref: ignoreMaintenance)
 	if err := core.InMaintOrDecomm(smap, core.T.Snode(), p.xctn); err != nil {
@@ -97,20 +224,56 @@ func (p *tcbFactory) Start() error {
 	if p.kind == apc.ActETLBck {
 		sizePDU = memsys.DefaultBufSize
 	}
+	if msg := p.args.Msg; msg.Manifest != "" {
+		p.xctn.manifest = &tcbManifest{}
+		if err := p.xctn.manifest.init(p.xctn, msg.Manifest); err != nil {
+			return err
+		}
+	}
+	if msg := p.args.Msg; msg.Webhook != "" {
+		p.xctn.SetObjHook(tcbWebhook(p.xctn, msg.Webhook), TCBHookOpts{})
+	}
 	if nat <= 1 {
 		return nil
 	}
-	return p.newDM(config, p.UUID(), sizePDU)
+	err = p.newDM(config, p.UUID(), sizePDU)
+	if err != nil {
+		if p.xctn.manifest != nil {
+			p.xctn.manifest.cleanup()
+		}
+		if p.xctn.hook != nil {
+			p.xctn.hook.stop()
+		}
+	}
+	return err
+}
+
+// object write transaction - as far as writing destination objects
+func tcbOWT(kind string, args *xreg.TCBArgs) cmn.OWT {
+	switch {
+	case kind == apc.ActETLBck:
+		return cmn.OwtTransform
+	case args.Msg.LocalOnly && args.BckTo.IsRemote():
+		return cmn.OwtCopyLocal // no write-through (and see apc.CopyBckMsg.Flush)
+	default:
+		return cmn.OwtCopy
+	}
 }
 
 func (p *tcbFactory) newDM(config *cmn.Config, uuid string, sizePDU int32) error {
 	const trname = "tcb"
+	compression, mult := p.streamsConf(config)
 	dmExtra := bundle.Extra{
 		RecvAck:     nil, // no ACKs
 		Config:      config,
-		Compression: config.TCB.Compression,
-		Multiplier:  config.TCB.SbundleMult,
+		Compression: compression,
+		Multiplier:  mult,
 		SizePDU:     sizePDU,
+		QUIC:        config.TCB.QUIC,
+		ZstdLevel:   config.TCB.ZstdLevel,
+		Retransmit:  p.xctn.retransmit,
+		SizeAck:     p.xctn.sizeAck,
+		Priority:    transport.PrioLow,
 	}
 	// in re cmn.OwtPut: see comment inside _recv()
 	dm := bundle.NewDM(trname+"-"+uuid, p.xctn.recv, p.owt, dmExtra)
@@ -122,6 +285,29 @@ func (p *tcbFactory) newDM(config *cmn.Config, uuid string, sizePDU int32) error
 	return nil
 }
 
+// compression and stream-bundle multiplier, in the order of precedence:
+// user request, ETL-specific config (when transforming), tcb config
+func (p *tcbFactory) streamsConf(config *cmn.Config) (compression string, mult int) {
+	compression, mult = config.TCB.Compression, config.TCB.SbundleMult
+	if p.kind == apc.ActETLBck {
+		if config.TCB.ETLCompression != "" {
+			compression = config.TCB.ETLCompression
+		}
+		if config.TCB.ETLSbundleMult > 0 {
+			mult = config.TCB.ETLSbundleMult
+		}
+	}
+	if msg := p.args.Msg; msg != nil {
+		if msg.Compression != "" {
+			compression = msg.Compression
+		}
+		if msg.SbundleMult > 0 {
+			mult = msg.SbundleMult
+		}
+	}
+	return compression, mult
+}
+
 func (p *tcbFactory) Kind() string   { return p.kind }
 func (p *tcbFactory) Get() core.Xact { return p.xctn }
 
@@ -149,12 +335,46 @@ func (p *tcbFactory) WhenPrevIsRunning(prevEntry xreg.Renewable) (wpr xreg.WPR,
 // limited pre-run abort
 func (r *XactTCB) TxnAbort(err error) {
 	err = cmn.NewErrAborted(r.Name(), "tcb: txn-abort", err)
-	r.dm.Close(err)
-	r.dm.UnregRecv()
+	if r.manifest != nil {
+		r.manifest.fin(true /*aborted*/) //nolint:errcheck // cleanup
+	}
+	if r.hook != nil {
+		r.hook.stop()
+	}
+	if r.dm != nil {
+		r.dmClose(err)
+	}
 	r.AddErr(err)
 	r.Base.Finish()
 }
 
+// open data mover unless already closed (by TxnAbort that arrived prior to, or while, starting up)
+func (r *XactTCB) dmOpen() bool {
+	r.dmst.mu.Lock()
+	defer r.dmst.mu.Unlock()
+	if r.dmst.closed {
+		return false
+	}
+	r.dm.SetXact(r)
+	r.dm.Open()
+	r.dmst.opened = true
+	return true
+}
+
+// close (if open) and unregister receive handler - once
+func (r *XactTCB) dmClose(err error) {
+	r.dmst.mu.Lock()
+	defer r.dmst.mu.Unlock()
+	if r.dmst.closed {
+		return
+	}
+	r.dmst.closed = true
+	if r.dmst.opened {
+		r.dm.Close(err)
+	}
+	r.dm.UnregRecv()
+}
+
 func newTCB(p *tcbFactory, slab *memsys.Slab, config *cmn.Config, smap *meta.Smap) (r *XactTCB) {
 	r = &XactTCB{p: p}
 
@@ -164,18 +384,48 @@ func newTCB(p *tcbFactory, slab *memsys.Slab, config *cmn.Config, smap *meta.Sma
 		parallel int
 	)
 	if p.kind == apc.ActETLBck {
-		parallel = etlBucketParallelCnt // TODO: optimize with respect to disk bw and transforming computation
+		parallel = etlBucketParallelCnt
 	}
+	prio := tcbPriority(msg.Priority)
+	switch prio {
+	case mpather.PriorityLow:
+		parallel = 0 // one object at a time (per mountpath)
+	case mpather.PriorityHigh:
+		parallel = max(parallel, 1) << 1
+	}
+	if p.kind == apc.ActETLBck && parallel > 0 && !msg.Checkpoint {
+		r.tune = newTcbTune(r, config, parallel)
+		parallel = r.tune.maxN // (see tcbTune)
+	}
+	r.filter = newTcbFilter(&msg.CopyBckMsg)
 	mpopts := &mpather.JgroupOpts{
 		CTs:      []string{fs.ObjectType},
 		VisitObj: r.do,
-		Prefix:   msg.Prefix,
+		Prefix:   r.filter.prefix(msg.Prefix),
 		Slab:     slab,
+		BufSize:  func(mi *fs.Mountpath) int64 { return tcbBufSize(mi, config) },
 		Parallel: parallel,
 		DoLoad:   mpather.Load,
 		Throttle: true, // always trottling
+		Priority: prio,
+		InFlight: true,
+	}
+	if r.tune != nil {
+		mpopts.Width = r.tune.get
 	}
 	mpopts.Bck.Copy(args.BckFrom.Bucket())
+	r.budget = tcbBufBudget(config) // nil when unlimited
+	mpopts.BufBudget = r.budget
+	if msg.Diff {
+		r.diff = newTcbDiff(r, slab)
+		mpopts.VisitObj = r.diff.collect // list source objects (see tcbDiff.run)
+	}
+	if msg.Checkpoint {
+		r.ckpt = newTcbCkpt(r, cos.Left(msg.Resume, p.UUID()))
+		mpopts.VisitObj = r.ckpt.visit
+		mpopts.Sorted = true
+		mpopts.Parallel = 0
+	}
 
 	{
 		var sb strings.Builder // ctlmsg
@@ -187,6 +437,33 @@ func newTCB(p *tcbFactory, slab *memsys.Slab, config *cmn.Config, smap *meta.Sma
 		r.str = r.Base.String() + "<=" + args.BckFrom.Cname(msg.Prefix)
 	}
 
+	if msg.Transform.Cache && p.kind == apc.ActETLBck {
+		r.cache = newTcbCache(args.DP)
+	}
+	if msg.OnCollision != "" {
+		r.names = newTcbNames(msg.OnCollision)
+	}
+	if msg.VerifyAfter {
+		r.verify = newTcbVerify(r, slab, config)
+	}
+	if msg.Snapshot {
+		r.pit = newTcbPIT(msg.AsOf)
+	}
+	if args.BckTo.Props != nil {
+		r.cksum.dstType = args.BckTo.Props.Cksum.Type
+	}
+	if msg.SSE != nil {
+		r.sse.init(r, msg.SSE)
+	}
+	r.SetRateLimit(msg.RateLimit)
+	r.retry.init(&config.TCB)
+	if msg.DryRun {
+		r.est = newTcbEst()
+	}
+	if args.BckFrom.Props != nil && args.BckFrom.Props.EC.Enabled && !msg.Diff {
+		r.ec = newTcbEC(r, slab, config)
+	}
+
 	if msg.Sync {
 		debug.Assert(msg.Prepend == "", msg.Prepend) // validated (cli, P)
 		{
@@ -202,24 +479,115 @@ func newTCB(p *tcbFactory, slab *memsys.Slab, config *cmn.Config, smap *meta.Sma
 	return r
 }
 
+func tcbPriority(priority string) int {
+	switch priority {
+	case apc.TCBPriorityLow:
+		return mpather.PriorityLow
+	case apc.TCBPriorityHigh:
+		return mpather.PriorityHigh
+	default:
+		return mpather.PriorityNormal
+	}
+}
+
+// read buffers of all x-tcb's running on this target (see cmn.TCBConf.BufBudget)
+var tcbBudget struct {
+	b  *mpather.BufBudget
+	mu sync.Mutex
+}
+
+// nil when not configured; a new budget upon (re)configuration, while copies that are
+// already running keep (releasing to) the previous one
+func tcbBufBudget(config *cmn.Config) *mpather.BufBudget {
+	size := int64(config.TCB.BufBudget)
+	if size <= 0 {
+		return nil
+	}
+	tcbBudget.mu.Lock()
+	if tcbBudget.b == nil || tcbBudget.b.Size() != size {
+		tcbBudget.b = mpather.NewBufBudget(size)
+	}
+	b := tcbBudget.b
+	tcbBudget.mu.Unlock()
+	return b
+}
+
+// (can be replaced to simulate spinning disks - tests only)
+var tcbRotational = (*fs.Mountpath).IsRotational
+
+// per-mountpath read buffer size: configured or the default, depending on the media type
+func tcbBufSize(mi *fs.Mountpath, config *cmn.Config) int64 {
+	if tcbRotational(mi) {
+		return cos.NonZero(int64(config.TCB.BufSizeHDD), int64(dfltBufSizeHDD))
+	}
+	return cos.NonZero(int64(config.TCB.BufSizeSSD), int64(dfltBufSizeSSD))
+}
+
 func (r *XactTCB) WaitRunning() { r.wg.Wait() }
 
 func (r *XactTCB) Run(wg *sync.WaitGroup) {
-	if r.dm != nil {
-		r.dm.SetXact(r)
-		r.dm.Open()
+	if r.dm != nil && !r.dmOpen() {
+		// aborted (see TxnAbort)
+		wg.Done()
+		r.wg.Done()
+		r.prog.fin(r)
+		return
 	}
 	wg.Done()
 
 	r.wg.Done()
 
+	r.eta.init(r.Config.TCB.EtaWindow.D(), mono.NanoTime())
+	if r.diff == nil {
+		// (differential copy - when done computing the delta)
+		go func() {
+			r.eta.total.Store(int64(fs.OnDiskSize(r.p.args.BckFrom.Bucket(), r.p.args.Msg.Prefix)))
+		}()
+	}
+
+	if r.tune != nil {
+		hk.Reg(r.tune.hkName()+hk.NameSuffix, r.tune.tick, tuneInterval)
+	}
 	r.BckJog.Run()
 	if r.p.args.Msg.Sync {
 		r.prune.run() // the 2nd jgroup
 	}
+	if deadline := r.p.args.Msg.Deadline; deadline != 0 {
+		r.dl.start(r, deadline, r.stopJogging)
+	}
 	nlog.Infoln(r.Name())
 
 	err := r.BckJog.Wait()
+	if r.tune != nil {
+		hk.Unreg(r.tune.hkName() + hk.NameSuffix)
+	}
+	if err != nil && r.dl.reached.Load() && !r.IsAborted() {
+		err = nil // stopped (not aborted)
+	}
+
+	if r.ec != nil && err == nil && !r.IsAborted() && !r.dl.reached.Load() {
+		// restore and copy objects that exist only as EC slices
+		if errE := r.ec.run(); errE != nil {
+			r.AddErr(errE)
+		}
+	}
+	if r.diff == nil {
+		r.TotalFinal()
+	} else {
+		// copy the delta _prior_ to broadcasting done-sending
+		if err == nil && !r.IsAborted() && !r.dl.reached.Load() {
+			if errD := r.diff.run(); errD != nil {
+				r.AddErr(errD)
+			}
+		}
+		r.diff.cleanup()
+	}
+	if r.arch != nil {
+		// finalize the last shard _prior_ to broadcasting done-sending
+		if errA := r.arch.finish(r.IsAborted()); errA != nil {
+			r.AddErr(errA)
+		}
+	}
 
 	if r.dm != nil {
 		o := transport.AllocSend()
@@ -232,13 +600,43 @@ func (r *XactTCB) Run(wg *sync.WaitGroup) {
 		}
 
 		// close
-		r.dm.Close(err)
-		r.dm.UnregRecv()
+		r.dmClose(err)
+	}
+	if r.verify != nil && err == nil && !r.IsAborted() && !r.dl.reached.Load() {
+		// all destination objects are in place (copied and received)
+		if errV := r.verify.run(); errV != nil {
+			r.AddErr(errV)
+		}
 	}
 	if r.p.args.Msg.Sync {
 		r.prune.wait()
 	}
+	if r.ckpt != nil {
+		r.ckpt.fin(err == nil && !r.IsAborted() && !r.dl.reached.Load() && r.ErrCnt() == 0)
+	}
+	if r.manifest != nil {
+		if errM := r.manifest.fin(r.IsAborted()); errM != nil {
+			r.AddErr(errM)
+		}
+	}
+	if r.hook != nil {
+		r.hook.stop()
+	}
+	r.dl.stop()
+	if r.dl.reached.Load() {
+		nlog.Warningln(r.Name(), "stopped upon reaching deadline - to resume, re-run with if-none-match")
+	}
 	r.Finish()
+	r.prog.fin(r)
+}
+
+// (deadline)
+func (r *XactTCB) stopJogging() {
+	r.Resume() // (deadline takes precedence)
+	r.BckJog.StopJoggers()
+	if r.p.args.Msg.Sync {
+		r.prune.joggers.Stop()
+	}
 }
 
 func (r *XactTCB) qcb(tot time.Duration) core.QuiRes {
@@ -271,9 +669,85 @@ func (r *XactTCB) do(lom *core.LOM, buf []byte) (err error) {
 		args   = r.p.args // TCBArgs
 		toName = args.Msg.ToName(lom.ObjName)
 	)
+	if r.dl.reached.Load() {
+		return nil // not starting new copies
+	}
+	if r.skipName(lom.ObjName) || r.skip.skip(lom.ObjName) {
+		return nil
+	}
+	if r.pit != nil && r.pit.skip(lom) {
+		return nil
+	}
+	if len(r.preds.preds) > 0 && r.preds.skip(lom) {
+		return nil
+	}
+	if r.names != nil {
+		if toName, err = r.names.resolve(lom.ObjName, toName); err != nil {
+			r.Abort(err)
+			return err
+		}
+		if toName == "" {
+			return nil // skip
+		}
+	}
 	if cmn.Rom.FastV(5, cos.SmoduleXs) {
 		nlog.Infoln(r.Base.Name()+":", lom.Cname(), "=>", args.BckTo.Cname(toName))
 	}
+	if r.diff == nil {
+		r.AddTotal(1, lom.Lsize(true)) // (differential copy: see tcbDiff.run)
+	}
+	if !args.Msg.DryRun {
+		r.throttle.wait(lom.Lsize(true), r.ChanAbort(), args.BckFrom, args.BckTo)
+	}
+	if r.arch != nil {
+		err = r.arch.do(lom, toName)
+	} else {
+		err = r.copyObject(lom, buf, toName)
+	}
+	switch {
+	case err == nil:
+		if args.Msg.Sync {
+			r.prune.filter.Insert(cos.UnsafeB(lom.Uname()))
+		}
+		r.eta.sample(mono.NanoTime(), r.Bytes())
+	case cos.IsNotExist(err, 0):
+		// do nothing
+	case cos.IsErrOOS(err):
+		r.Abort(err)
+	default:
+		r.AddErr(err, 5, cos.SmoduleXs)
+	}
+	return
+}
+
+func (r *XactTCB) copyObject(lom *core.LOM, buf []byte, toName string) error {
+	args := r.p.args
+	if args.Msg.IfNoneMatch {
+		if skip, err := r.condLocal(lom, toName); skip || err != nil {
+			return err
+		}
+	}
+	if r.cache != nil && r.cache.hit(lom, args.BckTo.Bucket(), toName) {
+		return nil
+	}
+	for attempt := 0; ; attempt++ {
+		var err error
+		if args.Msg.OnSrcChange != "" && !args.Msg.DryRun {
+			err = r.copyConsistent(lom, buf, toName)
+		} else {
+			err = r._copy(lom, buf, toName)
+		}
+		if err == nil && attempt > 0 {
+			r.retry.recovered.Inc()
+		}
+		if err == nil || !r.retry.again(r, err, attempt) {
+			return err
+		}
+	}
+}
+
+func (r *XactTCB) newCOI(lom *core.LOM, buf []byte, toName string) *CoiParams {
+	args := r.p.args
 	coiParams := AllocCOI()
 	{
 		coiParams.DP = args.DP
@@ -285,27 +759,204 @@ func (r *XactTCB) do(lom *core.LOM, buf []byte) (err error) {
 		coiParams.DryRun = args.Msg.DryRun
 		coiParams.LatestVer = args.Msg.LatestVer
 		coiParams.Sync = args.Msg.Sync
+		coiParams.Flush = args.Msg.Flush
 		coiParams.OWT = r.p.owt
 		coiParams.Finalize = false
 		if coiParams.ObjnameTo == "" {
 			coiParams.ObjnameTo = lom.ObjName
 		}
 	}
-	_, err = gcoi.CopyObject(lom, r.dm, coiParams)
+	return coiParams
+}
+
+func (r *XactTCB) _copy(lom *core.LOM, buf []byte, toName string) error {
+	var (
+		args      = r.p.args
+		coiParams = r.newCOI(lom, buf, toName)
+	)
+	var (
+		tags  cos.StrKVs
+		local = true
+	)
+	if args.Msg.PreserveTags && !args.Msg.DryRun {
+		tags = r.tags.get(r, lom)
+		if len(tags) > 0 {
+			if local = r.isLocal(coiParams.ObjnameTo); !local {
+				coiParams.Opaque = cos.MustMarshal(tags) // (see _recv)
+			}
+		}
+	}
+	if args.Msg.PreserveOrder && !args.Msg.DryRun {
+		coiParams.CustomMD = r.seq.get(lom)
+	}
+	seq := coiParams.CustomMD != nil
+	if args.Msg.IfNoneMatch && !args.Msg.DryRun {
+		coiParams.CustomMD = srcMD(lom, coiParams.CustomMD)
+	}
+	if r.cache != nil && !args.Msg.DryRun {
+		coiParams.CustomMD = r.cache.md(lom, coiParams.CustomMD)
+	}
+	if !args.Msg.DryRun {
+		coiParams.RStats = &r.tput.rstats
+		coiParams.SSE = r.sse.msg
+	}
+	started := mono.NanoTime()
+	size, err := gcoi.CopyObject(lom, r.dm, coiParams)
+	if err == nil && size > 0 && !args.Msg.DryRun {
+		r.tput.add(mono.SinceNano(started), size)
+	}
+	if err == nil && r.tune != nil {
+		r.tune.num.Inc()
+	}
+	objnameTo := coiParams.ObjnameTo
+	if err == nil && size > 0 && r.est != nil {
+		r.est.add(r, lom, objnameTo, size, mono.SinceNano(started), buf)
+	}
+	if err == nil && seq {
+		r.seq.carried.Inc()
+	}
 	FreeCOI(coiParams)
-	switch {
-	case err == nil:
-		if args.Msg.Sync {
-			r.prune.filter.Insert(cos.UnsafeB(lom.Uname()))
+	if err == nil && size > 0 && !args.Msg.DryRun && (r.sse.requested || r.cksum.differs(lom.Checksum())) && r.isLocal(objnameTo) {
+		// (objects written by other targets are counted by the latter - see _recv)
+		if r.cksum.differs(lom.Checksum()) {
+			r.cksum.recomputed.Inc()
 		}
-	case cos.IsNotExist(err, 0):
-		// do nothing
-	case cos.IsErrOOS(err):
-		r.Abort(err)
-	default:
-		r.AddErr(err, 5, cos.SmoduleXs)
+		r.sse.inc()
 	}
-	return
+
+	// skip-listed while being copied
+	if err == nil && r.skip.has(lom.ObjName) && !args.Msg.DryRun && r.isLocal(objnameTo) {
+		r.cancelDst(lom.ObjName, objnameTo)
+		return nil
+	}
+	if err == nil && len(tags) > 0 && local {
+		r.tags.putName(r, objnameTo, tags)
+	}
+	if err != nil || (r.manifest == nil && r.verify == nil && r.hook == nil) || args.Msg.DryRun || !r.isLocal(objnameTo) {
+		return err
+	}
+	if r.manifest != nil {
+		r.manifest.addName(objnameTo)
+	}
+	if r.verify != nil {
+		r.verify.add(objnameTo)
+	}
+	if r.hook != nil {
+		r.postHook(lom.ObjName, objnameTo)
+	}
+	return nil
+}
+
+func (r *XactTCB) postHook(srcName, objnameTo string) {
+	dst := core.AllocLOM(objnameTo)
+	if dst.InitBck(r.p.args.BckTo.Bucket()) == nil && dst.Load(false /*cache it*/, false /*locked*/) == nil {
+		r.hook.post(srcName, dst)
+	}
+	core.FreeLOM(dst)
+}
+
+// re-copy the source object upon receiver's request (see bundle.Extra.Retransmit)
+func (r *XactTCB) retransmit(hdr *transport.ObjHdr, _ *meta.Snode) error {
+	name := r.srcName(hdr.ObjName)
+	if r.arch != nil || name == "" {
+		return fmt.Errorf("cannot resolve the source of %s", hdr.Cname())
+	}
+	lom := core.AllocLOM(name)
+	defer core.FreeLOM(lom)
+	if err := lom.InitBck(r.p.args.BckFrom.Bucket()); err != nil {
+		return err
+	}
+	buf, slab := core.T.PageMM().AllocSize(memsys.MaxPageSlabSize)
+	err := r.copyObject(lom, buf, hdr.ObjName)
+	slab.Free(buf)
+	return err
+}
+
+// sending side: the object that was transmitted unsized (i.e., transformed) is now
+// written by the receiver - count it (compare with coi.stats)
+func (r *XactTCB) sizeAck(_ *transport.ObjHdr, size int64) { r.ObjsAdd(1, size) }
+
+// the source name, if it can be derived (by reversing apc.TCBMsg.ToName); empty otherwise
+func (r *XactTCB) srcName(dstName string) string {
+	msg := r.p.args.Msg
+	if msg.Ext != nil || msg.HashPrefix != nil || msg.OnCollision != "" || !strings.HasPrefix(dstName, msg.Prepend) {
+		return ""
+	}
+	return dstName[len(msg.Prepend):]
+}
+
+func (r *XactTCB) isLocal(objName string) bool {
+	lom := core.AllocLOM(objName)
+	defer core.FreeLOM(lom)
+	if lom.InitBck(r.p.args.BckTo.Bucket()) != nil {
+		return false
+	}
+	_, local, err := lom.HrwTarget(core.T.Sowner().Get())
+	return err == nil && local
+}
+
+// conditional copy when the destination is local; otherwise, the receiver
+// makes the same determination upon receiving the object (see _recv)
+func (r *XactTCB) condLocal(lom *core.LOM, toName string) (bool /*skip*/, error) {
+	dst := core.AllocLOM(toName)
+	defer core.FreeLOM(dst)
+	if err := dst.InitBck(r.p.args.BckTo.Bucket()); err != nil {
+		return false, err
+	}
+	_, local, err := dst.HrwTarget(core.T.Sowner().Get())
+	if err != nil || !local {
+		return false, err
+	}
+	return r.cond.match(dst, lom.Checksum(), lom.Lsize(true), srcMD(lom, nil)), nil
+}
+
+// returns true if the destination exists and is the same as the source:
+// - same checksum, when comparable (same type); otherwise,
+// - same size and the same source version and mtime as recorded by the previous copy (see srcMD)
+func (c *tcbCond) match(dst *core.LOM, cksum *cos.Cksum, size int64, md cos.StrKVs) bool {
+	if err := dst.Load(false /*cache it*/, false /*locked*/); err != nil {
+		c.absent.Inc() // (any error other than not-found will resurface when copying)
+		return false
+	}
+	var same bool
+	if dstCksum := dst.Checksum(); !cksum.IsEmpty() && !dstCksum.IsEmpty() && dstCksum.Ty() == cksum.Ty() {
+		same = dst.EqCksum(cksum)
+	} else if dst.Lsize() == size {
+		for _, key := range []string{cmn.SrcVerObjMD, cmn.SrcMtimeObjMD} {
+			v, ok := md[key]
+			if !ok {
+				continue
+			}
+			if recorded, _ := dst.GetCustomKey(key); recorded != v {
+				same = false
+				break
+			}
+			same = true
+		}
+	}
+	if same {
+		c.skipped.Inc()
+		return true
+	}
+	c.changed.Inc()
+	return false
+}
+
+// source version and mtime - to record at the destination (and compare next time around)
+func srcMD(lom *core.LOM, md cos.StrKVs) cos.StrKVs {
+	if ver := lom.VersionPtr(); ver != nil && *ver != "" {
+		if md == nil {
+			md = make(cos.StrKVs, 2)
+		}
+		md[cmn.SrcVerObjMD] = *ver
+	}
+	if _, _, mtime, err := lom.Fstat(false /*get atime*/); err == nil {
+		if md == nil {
+			md = make(cos.StrKVs, 1)
+		}
+		md[cmn.SrcMtimeObjMD] = strconv.FormatInt(mtime.UnixNano(), 10)
+	}
+	return md
 }
 
 // NOTE: strict(est) error handling: abort on any of the errors below
@@ -316,8 +967,7 @@ func (r *XactTCB) recv(hdr *transport.ObjHdr, objReader io.Reader, err error) er
 	}
 	// ref-count done-senders
 	if hdr.Opcode == OpcTxnDone {
-		refc := r.refc.Dec()
-		debug.Assert(refc >= 0)
+		r.doneSending(hdr.SID)
 		return nil
 	}
 
@@ -329,11 +979,51 @@ func (r *XactTCB) recv(hdr *transport.ObjHdr, objReader io.Reader, err error) er
 	return err
 }
 
+// idempotent: duplicate OpcTxnDone from the same sender (e.g., retransmitted
+// upon reconnect) must not be counted twice
+func (r *XactTCB) doneSending(sid string) {
+	if sid != "" {
+		r.done.mu.Lock()
+		_, dup := r.done.sids[sid]
+		if !dup {
+			if r.done.sids == nil {
+				r.done.sids = make(map[string]struct{}, 4)
+			}
+			r.done.sids[sid] = struct{}{}
+		}
+		r.done.mu.Unlock()
+		if dup {
+			nlog.Warningln(r.Name(), "duplicate done-sending from", meta.Tname(sid), "- ignoring")
+			return
+		}
+	}
+	refc := r.refc.Dec()
+	debug.Assert(refc >= 0)
+}
+
 func (r *XactTCB) _recv(hdr *transport.ObjHdr, objReader io.Reader, lom *core.LOM) error {
 	if err := lom.InitBck(&hdr.Bck); err != nil {
 		r.AddErr(err, 0)
 		return err
 	}
+	if name := r.srcName(hdr.ObjName); name != "" && r.skip.skip(name) {
+		r.rxlast.Store(mono.NanoTime())
+		return nil
+	}
+	if r.p.args.Msg.IfNoneMatch {
+		var (
+			skip bool
+			dst  = core.AllocLOM(hdr.ObjName)
+		)
+		if dst.InitBck(&hdr.Bck) == nil {
+			skip = r.cond.match(dst, hdr.ObjAttrs.Cksum, hdr.ObjAttrs.Size, hdr.ObjAttrs.CustomMD)
+		}
+		core.FreeLOM(dst)
+		if skip {
+			r.rxlast.Store(mono.NanoTime())
+			return nil
+		}
+	}
 	lom.CopyAttrs(&hdr.ObjAttrs, true /*skip cksum*/)
 	params := core.AllocPutParams()
 	{
@@ -343,6 +1033,14 @@ func (r *XactTCB) _recv(hdr *transport.ObjHdr, objReader io.Reader, lom *core.LO
 		params.Xact = r
 		params.Size = hdr.ObjAttrs.Size
 		params.OWT = r.p.owt
+		params.SSE = r.sse.msg
+	}
+	if r.cksum.differs(params.Cksum) {
+		// compute destination checksum while writing (and also validate the source's iff configured)
+		if !lom.CksumConf().ValidateObjMove {
+			params.Cksum = nil
+		}
+		r.cksum.recomputed.Inc()
 	}
 	if lom.AtimeUnix() == 0 {
 		// TODO: sender must be setting it, remove this `if` when fixed
@@ -353,9 +1051,32 @@ func (r *XactTCB) _recv(hdr *transport.ObjHdr, objReader io.Reader, lom *core.LO
 	erp := core.T.PutObject(lom, params)
 	core.FreePutParams(params)
 	if erp != nil {
-		r.AddErr(erp, 0)
+		if !transport.IsErrPDUCksum(erp) { // (to be retransmitted - see retransmit above)
+			r.AddErr(erp, 0)
+		}
 		return erp // NOTE: non-nil signals transport to terminate
 	}
+	r.sse.inc()
+	if hdr.IsUnsized() {
+		r.dm.AckSize(hdr, lom.Lsize()) // post-transform size
+	}
+	if len(hdr.Opaque) > 0 && r.p.args.Msg.PreserveTags {
+		var tags cos.StrKVs
+		if err := cos.JSON.Unmarshal(hdr.Opaque, &tags); err != nil {
+			r.AddErr(fmt.Errorf("%s: invalid tags (%s): %w", r, lom.Cname(), err), 0)
+		} else {
+			r.tags.put(r, lom, tags)
+		}
+	}
+	if r.manifest != nil {
+		r.manifest.add(lom)
+	}
+	if r.verify != nil {
+		r.verify.add(lom.ObjName)
+	}
+	if r.hook != nil {
+		r.hook.post(r.srcName(lom.ObjName), lom)
+	}
 	r.rxlast.Store(mono.NanoTime())
 	return nil
 }
@@ -369,6 +1090,17 @@ func (r *XactTCB) FromTo() (*meta.Bck, *meta.Bck) {
 	return r.p.args.BckFrom, r.p.args.BckTo
 }
 
+// bounded snapshot of the source object names currently in-flight across all joggers
+func (r *XactTCB) InFlight() []string {
+	names := r.BckJog.InFlight(tcbMaxInFlight)
+	if r.diff != nil {
+		if name := r.diff.cur.Load(); name != nil {
+			names = append(names, *name)
+		}
+	}
+	return names
+}
+
 func (r *XactTCB) Snap() (snap *core.Snap) {
 	snap = &core.Snap{}
 	r.ToSnap(snap)
@@ -376,5 +1108,70 @@ func (r *XactTCB) Snap() (snap *core.Snap) {
 	snap.IdleX = r.IsIdle()
 	f, t := r.FromTo()
 	snap.SrcBck, snap.DstBck = f.Clone(), t.Clone()
+
+	ext := &ExtTCBStats{
+		BufSizes:    r.BufSizes(),
+		BufUsed:     r.BufUsed(),
+		CondSkipped: r.cond.skipped.Load(),
+		CondChanged: r.cond.changed.Load(),
+		CondAbsent:  r.cond.absent.Load(),
+		SrcChanged:  r.srcChange.Load(),
+		InFlight:    r.InFlight(),
+		Priority:    cos.Left(r.p.args.Msg.Priority, apc.TCBPriorityNormal),
+		TagsCopied:  r.tags.copied.Load(),
+		TagsSkipped: r.tags.skipped.Load(),
+		SeqCarried:  r.seq.carried.Load(),
+		SeqMissing:  r.seq.missing.Load(),
+	}
+	ext.CksumRecomputed = r.cksum.recomputed.Load()
+	ext.SSEApplied, ext.SSESkipped = r.sse.applied.Load(), r.sse.skipped.Load()
+	ext.SkipReasons = r.preds.breakdown()
+	ext.DeadlineReached = r.dl.reached.Load()
+	ext.RateLimit, ext.Throttled = r.throttle.bps.Load(), time.Duration(r.throttle.slept.Load())
+	if r.ckpt != nil {
+		ext.CkptID, ext.CkptSkipped = r.ckpt.id, r.ckpt.skipped.Load()
+	}
+	if r.est != nil {
+		ext.DryRun = r.est.get(r.throttle.bps.Load())
+	}
+	ext.Retries, ext.RetryRecovered = r.retry.retries.Load(), r.retry.recovered.Load()
+	if r.tune != nil {
+		ext.Parallel = r.tune.get()
+	}
+	if r.cache != nil {
+		ext.CacheHits, ext.CacheMisses = r.cache.hits.Load(), r.cache.misses.Load()
+	}
+	ext.ReadTput, ext.WriteTput = r.tput.gauges()
+	if n := r.skip.skipped.Load(); n > 0 {
+		ext.OpSkipped, ext.OpSkippedNames = n, r.skip.list()
+	}
+	if r.diff != nil {
+		ext.DeltaCnt, ext.DeltaSize = r.diff.cnt.Load(), r.diff.size.Load()
+	}
+	if r.names != nil {
+		ext.Collisions = r.names.cnt.Load()
+	}
+	if snap.Running() {
+		now, bytes := mono.NanoTime(), r.Bytes()
+		r.eta.sample(now, bytes)
+		ext.EtaAvg, ext.EtaAdaptive = r.eta.estimate(now, bytes)
+	}
+	if r.pit != nil {
+		ext.PitSkipped = r.pit.skipped.Load()
+	}
+	if r.budget != nil {
+		ext.BudgetUsed = r.budget.Used()
+	}
+	if r.hook != nil {
+		ext.HookCalled, ext.HookErrs, ext.HookDropped = r.hook.called.Load(), r.hook.errs.Load(), r.hook.dropped.Load()
+	}
+	if r.ec != nil {
+		ext.ECRecovered, ext.ECFailed, ext.ECTime = r.ec.recovered.Load(), r.ec.failed.Load(), time.Duration(r.ec.ns.Load())
+	}
+	if r.verify != nil {
+		ext.Verified, ext.Mismatched = r.verify.verified.Load(), r.verify.mismatch.Load()
+		ext.Recopied, ext.MismatchedNames = r.verify.recopied.Load(), r.verify.mismatched()
+	}
+	snap.Ext = ext
 	return
 }
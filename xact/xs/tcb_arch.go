@@ -0,0 +1,214 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/archive"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/transport"
+)
+
+// x-tcb destination archives (shards), one shard at a time:
+// - source objects are appended to the current shard (serialized across joggers);
+// - upon reaching configured limits, the current shard gets finalized and the next one started;
+// - the last one is finalized when the jogging is done (see XactTCB.Run)
+// - finalized shard is either stored locally or sent to its (HRW) destination via data mover
+
+type (
+	tcbArch struct {
+		r     *XactTCB
+		msg   *apc.ArchTCBMsg
+		shard *tcbShard
+		mime  string
+		seq   int
+		mu    sync.Mutex
+	}
+	tcbShard struct {
+		lom    *core.LOM
+		writer archive.Writer
+		wfh    cos.LomWriter
+		fqn    string // workfile
+		cksum  cos.CksumHashSize
+		cnt    int   // num entries
+		size   int64 // total size of the entries
+	}
+)
+
+func (a *tcbArch) init(r *XactTCB, msg *apc.ArchTCBMsg) (err error) {
+	a.r, a.msg = r, msg
+	a.mime, err = archive.Mime(msg.Mime, msg.Template)
+	return err
+}
+
+// append one source object (called by joggers)
+func (a *tcbArch) do(lom *core.LOM, toName string) error {
+	dp := a.r.p.args.DP
+	if dp == nil {
+		dp = &core.LDP{}
+	}
+	reader, oah, err := dp.Reader(lom, a.r.p.args.Msg.LatestVer, a.r.p.args.Msg.Sync)
+	if err != nil {
+		return err
+	}
+	defer cos.Close(reader)
+
+	var (
+		size = oah.Lsize()
+		rd   io.Reader
+	)
+	rd = reader
+	if size < 0 {
+		// post-transform size unknown (and required by the archive header)
+		sgl := core.T.PageMM().NewSGL(0)
+		defer sgl.Free()
+		if _, err := io.Copy(sgl, reader); err != nil {
+			return err
+		}
+		size, rd = sgl.Len(), sgl
+		oah = &cmn.ObjAttrs{Size: size, Atime: oah.AtimeUnix()}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.shard == nil {
+		if err := a.open(); err != nil {
+			return err
+		}
+	}
+	shard := a.shard
+	if err := shard.writer.Write(toName, oah, rd); err != nil {
+		return err
+	}
+	shard.cnt++
+	shard.size += size
+	a.r.ObjsAdd(1, size)
+
+	if (a.msg.MaxEntries > 0 && shard.cnt >= a.msg.MaxEntries) || (a.msg.MaxSize > 0 && shard.size >= a.msg.MaxSize) {
+		return a.fin()
+	}
+	return nil
+}
+
+// is called under lock
+func (a *tcbArch) open() (err error) {
+	var (
+		r     = a.r
+		name  = a.msg.ShardName(core.T.SID(), a.seq, a.mime)
+		shard = &tcbShard{lom: core.AllocLOM(name)}
+	)
+	if err = shard.lom.InitBck(r.p.args.BckTo.Bucket()); err != nil {
+		core.FreeLOM(shard.lom)
+		return err
+	}
+	shard.fqn = fs.CSM.Gen(shard.lom, fs.WorkfileType, fs.WorkfileCreateArch)
+	if shard.wfh, err = shard.lom.CreateWork(shard.fqn); err != nil {
+		core.FreeLOM(shard.lom)
+		return err
+	}
+	shard.cksum.Init(shard.lom.CksumType())
+	shard.writer = archive.NewWriter(a.mime, shard.wfh, &shard.cksum, nil /*opts*/)
+
+	a.seq++
+	a.shard = shard
+	return nil
+}
+
+// finalize the current shard (if any); is called under lock
+func (a *tcbArch) fin() error {
+	shard := a.shard
+	if shard == nil {
+		return nil
+	}
+	a.shard = nil
+
+	shard.writer.Fini()
+	err := shard.wfh.Close()
+	shard.wfh = nil
+	if err != nil {
+		shard.cleanup()
+		return err
+	}
+	shard.cksum.Finalize()
+	shard.lom.SetCksum(&shard.cksum.Cksum)
+	shard.lom.SetSize(shard.cksum.Size)
+	shard.lom.SetAtimeUnix(time.Now().UnixNano())
+
+	if cmn.Rom.FastV(5, cos.SmoduleXs) {
+		nlog.Infoln(a.r.Base.Name()+": finalize", shard.lom.Cname(), "[", shard.cnt, shard.size, "]")
+	}
+	return a.r.deliver(shard)
+}
+
+// finish (when done jogging) or cleanup (when aborted)
+func (a *tcbArch) finish(aborted bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if aborted {
+		if a.shard != nil {
+			a.shard.writer.Fini()
+			a.shard.cleanup()
+			a.shard = nil
+		}
+		return nil
+	}
+	return a.fin()
+}
+
+// store finalized shard locally or send it to its (HRW) destination
+func (r *XactTCB) deliver(shard *tcbShard) error {
+	tsi, local, err := shard.lom.HrwTarget(core.T.Sowner().Get())
+	if err != nil {
+		shard.cleanup()
+		return err
+	}
+	if local || r.dm == nil {
+		_, err = core.T.FinalizeObj(shard.lom, shard.fqn, r, cmn.OwtArchive)
+		core.FreeLOM(shard.lom)
+		return err
+	}
+
+	fh, err := shard.lom.NewHandle(shard.fqn)
+	if err != nil {
+		shard.cleanup()
+		return err
+	}
+	o := transport.AllocSend()
+	hdr := &o.Hdr
+	{
+		hdr.Bck.Copy(shard.lom.Bucket())
+		hdr.ObjName = shard.lom.ObjName
+		hdr.ObjAttrs.CopyFrom(shard.lom.ObjAttrs(), false /*skip cksum*/)
+	}
+	o.Callback = func(*transport.ObjHdr, io.ReadCloser, any, error) {
+		shard.cleanup()
+	}
+	return r.dm.Send(o, fh, tsi)
+}
+
+//////////////
+// tcbShard //
+//////////////
+
+func (shard *tcbShard) cleanup() {
+	if shard.wfh != nil {
+		cos.Close(shard.wfh)
+		shard.wfh = nil
+	}
+	if err := cos.RemoveFile(shard.fqn); err != nil {
+		nlog.Errorln(fmt.Errorf("failed to remove %q: %w", shard.fqn, err))
+	}
+	core.FreeLOM(shard.lom)
+}
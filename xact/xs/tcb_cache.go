@@ -0,0 +1,99 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"strconv"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/OneOfOne/xxhash"
+)
+
+// x-tcb (ETL) transform result caching (see apc.Transform.Cache):
+// - key: digest of the transformation (ETL names and init parameters - see etl.OfflineDP.TransformID)
+//   and the source content: checksum or, when not checksummed, size, version, and mtime;
+// - the key gets recorded at the destination (custom metadata, see cmn.ETLCacheObjMD);
+// - prior to transforming, the destination is looked up - locally, or else by asking the designated
+//   target - and the source object is skipped when the two keys match
+
+type (
+	tcbTransformID interface {
+		TransformID() string
+	}
+	tcbCache struct {
+		tid    string
+		hits   atomic.Int64 // skipped
+		misses atomic.Int64 // (re)transformed
+	}
+)
+
+func newTcbCache(dp core.DP) *tcbCache {
+	tx, ok := dp.(tcbTransformID)
+	if !ok {
+		return nil
+	}
+	return &tcbCache{tid: tx.TransformID()}
+}
+
+func (c *tcbCache) key(lom *core.LOM) string {
+	h := xxhash.New64()
+	h.WriteString(c.tid)
+	if cksum := lom.Checksum(); !cksum.IsEmpty() {
+		h.WriteString("\x00" + cksum.Ty() + "\x00" + cksum.Val())
+	} else {
+		h.WriteString("\x00" + strconv.FormatInt(lom.Lsize(), 10))
+		if ver := lom.VersionPtr(); ver != nil {
+			h.WriteString("\x00" + *ver)
+		}
+		if _, _, mtime, err := lom.Fstat(false /*get atime*/); err == nil {
+			h.WriteString("\x00" + strconv.FormatInt(mtime.UnixNano(), 10))
+		}
+	}
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// to record at the destination
+func (c *tcbCache) md(lom *core.LOM, md cos.StrKVs) cos.StrKVs {
+	if md == nil {
+		md = make(cos.StrKVs, 1)
+	}
+	md[cmn.ETLCacheObjMD] = c.key(lom)
+	return md
+}
+
+// returns true if the destination was produced by the same transformation of the same source
+func (c *tcbCache) hit(lom *core.LOM, bckTo *cmn.Bck, toName string) bool {
+	var (
+		recorded string
+		dst      = core.AllocLOM(toName)
+	)
+	defer core.FreeLOM(dst)
+	if dst.InitBck(bckTo) != nil {
+		return false
+	}
+	tsi, local, err := dst.HrwTarget(core.T.Sowner().Get())
+	switch {
+	case err != nil:
+		return false
+	case local:
+		if dst.Load(false /*cache it*/, false /*locked*/) == nil {
+			recorded, _ = dst.GetCustomKey(cmn.ETLCacheObjMD)
+		}
+	default:
+		if oa := core.T.HeadObjAttrsT2T(dst, tsi); oa != nil {
+			recorded, _ = oa.GetCustomKey(cmn.ETLCacheObjMD)
+		}
+	}
+	if recorded != "" && recorded == c.key(lom) {
+		c.hits.Inc()
+		return true
+	}
+	c.misses.Inc()
+	return false
+}
@@ -0,0 +1,155 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/fname"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// x-tcb checkpoints (see apc.CopyBckMsg.Checkpoint and Resume):
+// - each mountpath gets walked in (lexical, one directory at a time) order by a single jogger
+//   visiting one object at a time - the name of the last visited object is the mountpath's progress marker;
+// - a visited object is either copied locally, handed over to the data mover, or skipped
+// - markers are persisted on the respective mountpaths every so often (and upon finishing);
+//   after a crash, the objects visited since the last persisted marker get copied again;
+// - resumed copy skips source objects up to (and including) the respective markers
+
+const (
+	tcbCkptEvery = 1000
+	tcbCkptIval  = 10 * time.Second
+)
+
+type (
+	tcbMark struct {
+		from    string // resuming: skip up to (and including)
+		last    string // last visited
+		fpath   string // mi.Path/TCBCheckpointDir/id
+		cnt     int    // visited since last persisted
+		persist int64  // mono time last persisted
+	}
+	tcbCkpt struct {
+		r       *XactTCB
+		marks   map[string]*tcbMark // by mountpath
+		id      string              // this xaction's or the resumed one's
+		skipped atomic.Int64        // visited prior to interruption
+	}
+)
+
+func newTcbCkpt(r *XactTCB, id string) *tcbCkpt {
+	var (
+		avail = fs.GetAvail()
+		now   = mono.NanoTime()
+		ckpt  = &tcbCkpt{r: r, id: id, marks: make(map[string]*tcbMark, len(avail))}
+	)
+	for _, mi := range avail {
+		mark := &tcbMark{fpath: filepath.Join(mi.Path, fname.TCBCheckpointDir, id), persist: now}
+		if b, err := os.ReadFile(mark.fpath); err == nil {
+			mark.from = string(b)
+			mark.last = mark.from
+		} else if !os.IsNotExist(err) {
+			nlog.Errorln("failed to load x-tcb checkpoint:", err) // (not yet named)
+		}
+		ckpt.marks[mi.Path] = mark
+	}
+	return ckpt
+}
+
+// (jogger) visit in place of XactTCB.do
+func (ckpt *tcbCkpt) visit(lom *core.LOM, buf []byte) error {
+	mark, ok := ckpt.marks[lom.Mountpath().Path]
+	if !ok {
+		return ckpt.r.do(lom, buf) // (mountpath added)
+	}
+	if mark.from != "" && tcbWalkCmp(lom.ObjName, mark.from) <= 0 {
+		ckpt.skipped.Inc()
+		return nil
+	}
+	if err := ckpt.r.do(lom, buf); err != nil {
+		return err
+	}
+	if ckpt.r.dl.reached.Load() {
+		return nil // may or may not have been copied
+	}
+	mark.last = lom.ObjName
+	mark.cnt++
+	if mark.cnt >= tcbCkptEvery || mono.Since(mark.persist) > tcbCkptIval {
+		ckpt.persist(mark)
+	}
+	return nil
+}
+
+func (ckpt *tcbCkpt) persist(mark *tcbMark) {
+	mark.cnt, mark.persist = 0, mono.NanoTime()
+	if mark.last == "" {
+		return
+	}
+	if err := cos.CreateDir(filepath.Dir(mark.fpath)); err != nil {
+		nlog.Errorln(ckpt.r.Name(), "failed to persist checkpoint:", err)
+		return
+	}
+	tmp := mark.fpath + ".tmp"
+	if err := os.WriteFile(tmp, []byte(mark.last), cos.PermRWR); err != nil {
+		nlog.Errorln(ckpt.r.Name(), "failed to persist checkpoint:", err)
+		return
+	}
+	if err := os.Rename(tmp, mark.fpath); err != nil {
+		nlog.Errorln(ckpt.r.Name(), "failed to persist checkpoint:", err)
+	}
+}
+
+// upon finishing: remove checkpoints when done, persist otherwise
+func (ckpt *tcbCkpt) fin(done bool) {
+	for _, mark := range ckpt.marks {
+		if !done {
+			ckpt.persist(mark)
+			continue
+		}
+		if err := cos.RemoveFile(mark.fpath); err != nil {
+			nlog.Errorln(ckpt.r.Name(), "failed to remove checkpoint:", err)
+		}
+	}
+	if !done {
+		nlog.Warningln(ckpt.r.Name(), "checkpoint", ckpt.id, "persisted - to resume, re-run with resume option")
+	}
+}
+
+// compare object names in the order of walking (see fs.WalkOpts.Sorted):
+// lexical, one path component at a time (e.g., "a/b" comes before "a-b")
+func tcbWalkCmp(a, b string) int {
+	for {
+		ia, ib := strings.IndexByte(a, '/'), strings.IndexByte(b, '/')
+		ca, cb := a, b
+		if ia >= 0 {
+			ca = a[:ia]
+		}
+		if ib >= 0 {
+			cb = b[:ib]
+		}
+		if c := strings.Compare(ca, cb); c != 0 {
+			return c
+		}
+		switch {
+		case ia < 0 && ib < 0:
+			return 0
+		case ia < 0:
+			return -1
+		case ib < 0:
+			return 1
+		}
+		a, b = a[ia+1:], b[ib+1:]
+	}
+}
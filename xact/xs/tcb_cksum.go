@@ -0,0 +1,25 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+)
+
+// x-tcb destination checksums:
+// - destination bucket may be configured with a checksum type that differs from the source's;
+// - when it does, the destination object's checksum is computed (according to the destination)
+//   while writing - the source is read only once (see also core.LOM.Copy2FQN)
+
+type tcbCksum struct {
+	dstType    string       // destination bucket's checksum type
+	recomputed atomic.Int64 // num objects with their checksums recomputed
+}
+
+func (c *tcbCksum) differs(cksum *cos.Cksum) bool {
+	return c.dstType != "" && c.dstType != cos.ChecksumNone && !cksum.IsEmpty() && cksum.Ty() != c.dstType
+}
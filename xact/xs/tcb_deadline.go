@@ -0,0 +1,40 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+)
+
+// x-tcb deadline (see apc.CopyBckMsg.Deadline):
+// - upon expiration, joggers stop visiting source objects while the ones being copied complete;
+// - the rest of the finishing sequence runs as usual: the data mover drains (done-sending, quiesce),
+//   the manifest (if any) gets stored, etc. - except for the steps that would copy more
+//   (EC restoration, differential copy, verification pass);
+// - the xaction finishes without error (see ExtTCBStats.DeadlineReached)
+// - to resume, re-run the copy with apc.CopyBckMsg.IfNoneMatch (to skip the objects already copied)
+
+type tcbDeadline struct {
+	timer   *time.Timer
+	reached atomic.Bool
+}
+
+func (dl *tcbDeadline) start(r *XactTCB, deadline int64, stop func()) {
+	dl.timer = time.AfterFunc(time.Until(time.Unix(0, deadline)), func() {
+		dl.reached.Store(true)
+		nlog.Warningln(r.Name(), "deadline reached - stopping")
+		stop()
+	})
+}
+
+func (dl *tcbDeadline) stop() {
+	if dl.timer != nil {
+		dl.timer.Stop()
+	}
+}
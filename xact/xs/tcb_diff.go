@@ -0,0 +1,497 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"bufio"
+	"container/heap"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	ratomic "sync/atomic"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/memsys"
+)
+
+// x-tcb differential copy (see apc.CopyBckMsg.Diff):
+// - joggers list this target's source objects (in no particular order);
+// - listed entries are sorted in memory, up to tcbDiffRunSize at a time, with full runs
+//   getting spilled into workfiles (external sort);
+// - the (remote) destination is listed page by page, sorted by name;
+// - merge join of the source runs with the destination listing yields the delta: source objects
+//   that are missing at the destination or differ in size or checksum;
+// - the delta is recorded into yet another workfile, reported, and then copied (see XactTCB.do)
+// Limitations:
+// - each target lists the entire destination (compare with lrit._prefix);
+// - checksums are compared only when the source is MD5-checksummed (to match remote ETag/MD5);
+//   otherwise, size only
+
+// max number of source entries sorted in memory at any given time
+const tcbDiffRunSize = 64 * 1024
+
+type (
+	// listed entry (also, NDJSON record in the spilled runs and the delta)
+	tcbDent struct {
+		Name  string `json:"n"`
+		Cksum string `json:"c,omitempty"`
+		Size  int64  `json:"s"`
+	}
+	// stream of entries sorted by name; returns nil entry when done
+	tcbDiter interface {
+		next() (*tcbDent, error)
+	}
+
+	tcbDiff struct {
+		r       *XactTCB
+		slab    *memsys.Slab
+		cur     ratomic.Pointer[string] // in-flight (copying) delta object
+		ents    []tcbDent               // current run (unsorted)
+		runs    []string                // spilled sorted runs (workfiles)
+		dfqn    string                  // delta (workfile)
+		runSize int
+		cnt     atomic.Int64 // delta: num objects
+		size    atomic.Int64 // delta: total size
+		mu      sync.Mutex
+	}
+
+	// sorted run: in memory and spilled
+	tcbMemRun struct {
+		ents []tcbDent
+		i    int
+	}
+	tcbFileRun struct {
+		fh *os.File
+		br *bufio.Reader
+	}
+
+	// k-way merge of sorted runs
+	tcbHead struct {
+		ent *tcbDent
+		it  tcbDiter
+	}
+	tcbMerge []*tcbHead
+
+	// remote destination, one page at a time
+	tcbDstList struct {
+		bck     *meta.Bck
+		lsmsg   *apc.LsoMsg
+		lst     cmn.LsoRes
+		prepend string // stripped off destination names
+		i       int
+		done    bool
+	}
+)
+
+// interface guard
+var (
+	_ tcbDiter       = (*tcbMemRun)(nil)
+	_ tcbDiter       = (*tcbFileRun)(nil)
+	_ tcbDiter       = (*tcbMerge)(nil)
+	_ tcbDiter       = (*tcbDstList)(nil)
+	_ heap.Interface = (*tcbMerge)(nil)
+)
+
+func newTcbDiff(r *XactTCB, slab *memsys.Slab) *tcbDiff {
+	return &tcbDiff{r: r, slab: slab, runSize: tcbDiffRunSize}
+}
+
+// list source object (jogger's callback)
+func (d *tcbDiff) collect(lom *core.LOM, _ []byte) (err error) {
+	if d.r.skipName(lom.ObjName) {
+		return nil
+	}
+	if d.r.pit != nil && d.r.pit.skip(lom) {
+		return nil
+	}
+	ent := tcbDent{Name: lom.ObjName, Size: lom.Lsize()}
+	if cksum := lom.Checksum(); cksum != nil && cksum.Ty() == cos.ChecksumMD5 {
+		ent.Cksum = cksum.Value()
+	}
+	d.mu.Lock()
+	d.ents = append(d.ents, ent)
+	if len(d.ents) >= d.runSize {
+		err = d.spill()
+	}
+	d.mu.Unlock()
+	return err
+}
+
+// is called under lock
+func (d *tcbDiff) spill() error {
+	fqn, err := d.workfile("run-" + strconv.Itoa(len(d.runs)))
+	if err != nil {
+		return err
+	}
+	sortDents(d.ents)
+	if err := writeDents(fqn, d.ents); err != nil {
+		return err
+	}
+	d.runs = append(d.runs, fqn)
+	d.ents = d.ents[:0]
+	return nil
+}
+
+func (d *tcbDiff) workfile(tag string) (string, error) {
+	name := "tcb-diff-" + d.r.ID() + "-" + tag
+	mi, _, err := fs.Hrw(cos.UnsafeB(name))
+	if err != nil {
+		return "", err
+	}
+	return mi.MakePathFQN(d.r.p.args.BckFrom.Bucket(), fs.WorkfileType, name), nil
+}
+
+// all listed source entries, sorted
+func (d *tcbDiff) src() (tcbDiter, error) {
+	sortDents(d.ents)
+	mem := &tcbMemRun{ents: d.ents}
+	if len(d.runs) == 0 {
+		return mem, nil
+	}
+	its := make([]tcbDiter, 0, len(d.runs)+1)
+	its = append(its, mem)
+	for _, fqn := range d.runs {
+		run, err := openFileRun(fqn)
+		if err != nil {
+			closeRuns(its)
+			return nil, err
+		}
+		its = append(its, run)
+	}
+	return newTcbMerge(its)
+}
+
+func (d *tcbDiff) dst() tcbDiter {
+	var (
+		msg   = d.r.p.args.Msg
+		lsmsg = &apc.LsoMsg{Prefix: msg.Prepend + msg.Prefix}
+	)
+	lsmsg.AddProps(apc.GetPropsName, apc.GetPropsSize, apc.GetPropsChecksum)
+	lsmsg.SetFlag(apc.LsNoDirs)
+	return &tcbDstList{bck: d.r.p.args.BckTo, lsmsg: lsmsg, prepend: msg.Prepend}
+}
+
+// compute the delta and copy it (is called once the joggers are done)
+func (d *tcbDiff) run() error {
+	src, err := d.src()
+	if err != nil {
+		return err
+	}
+	err = d.compute(src, d.dst())
+	closeRuns([]tcbDiter{src})
+	if err != nil {
+		return err
+	}
+	nlog.Infoln(d.r.Name()+": delta", d.cnt.Load(), "objects,", cos.ToSizeIEC(d.size.Load(), 2))
+	d.r.eta.reset(d.size.Load()+d.r.Bytes(), mono.NanoTime())
+	d.r.AddTotal(int(d.cnt.Load()), d.size.Load())
+	d.r.TotalFinal()
+	return d.copy()
+}
+
+// merge join => delta workfile
+func (d *tcbDiff) compute(src, dst tcbDiter) (err error) {
+	if d.dfqn, err = d.workfile("delta"); err != nil {
+		return err
+	}
+	fh, err := cos.CreateFile(d.dfqn)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(fh)
+	err = tcbJoin(src, dst, func(ent *tcbDent) error {
+		d.cnt.Inc()
+		d.size.Add(ent.Size)
+		return writeDent(bw, ent)
+	})
+	if err == nil {
+		err = bw.Flush()
+	}
+	if errC := fh.Close(); err == nil {
+		err = errC
+	}
+	return err
+}
+
+func (d *tcbDiff) copy() error {
+	run, err := openFileRun(d.dfqn)
+	if err != nil {
+		return err
+	}
+	defer run.close()
+
+	var buf []byte
+	if d.slab != nil {
+		buf = d.slab.Alloc()
+		defer d.slab.Free(buf)
+	}
+	for !d.r.IsAborted() {
+		ent, err := run.next()
+		if err != nil || ent == nil {
+			return err
+		}
+		lom := core.AllocLOM(ent.Name)
+		if err := lom.InitBck(d.r.p.args.BckFrom.Bucket()); err != nil {
+			core.FreeLOM(lom)
+			return err
+		}
+		if err := lom.Load(false /*cache it*/, false /*locked*/); err != nil {
+			core.FreeLOM(lom)
+			if cos.IsNotExist(err, 0) {
+				continue // removed since listed
+			}
+			return err
+		}
+		d.cur.Store(&ent.Name)
+		d.r.do(lom, buf) //nolint:errcheck // handled by r.do (counted or aborted)
+		d.cur.Store(nil)
+		core.FreeLOM(lom)
+	}
+	return nil
+}
+
+func (d *tcbDiff) cleanup() {
+	fqns := d.runs
+	if d.dfqn != "" {
+		fqns = append(fqns, d.dfqn)
+	}
+	for _, fqn := range fqns {
+		if err := cos.RemoveFile(fqn); err != nil {
+			nlog.Errorln(fmt.Errorf("failed to remove %q: %w", fqn, err))
+		}
+	}
+	d.runs, d.ents, d.dfqn = nil, nil, ""
+}
+
+// merge join two sorted streams and call back with each source entry that is
+// either missing in the destination or differs
+func tcbJoin(src, dst tcbDiter, cb func(*tcbDent) error) error {
+	s, err := src.next()
+	if err != nil {
+		return err
+	}
+	t, err := dst.next()
+	if err != nil {
+		return err
+	}
+	for s != nil {
+		switch {
+		case t == nil || s.Name < t.Name: // missing
+			if err := cb(s); err != nil {
+				return err
+			}
+			s, err = src.next()
+		case s.Name > t.Name: // destination only
+			t, err = dst.next()
+		default:
+			if !s.eq(t) {
+				if err := cb(s); err != nil {
+					return err
+				}
+			}
+			if s, err = src.next(); err == nil {
+				t, err = dst.next()
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/////////////
+// tcbDent //
+/////////////
+
+func (s *tcbDent) eq(t *tcbDent) bool {
+	return s.Size == t.Size && (s.Cksum == "" || t.Cksum == "" || s.Cksum == t.Cksum)
+}
+
+func sortDents(ents []tcbDent) {
+	sort.Slice(ents, func(i, j int) bool { return ents[i].Name < ents[j].Name })
+}
+
+func writeDent(bw *bufio.Writer, ent *tcbDent) error {
+	if _, err := bw.Write(cos.MustMarshal(ent)); err != nil {
+		return err
+	}
+	return bw.WriteByte('\n')
+}
+
+func writeDents(fqn string, ents []tcbDent) error {
+	fh, err := cos.CreateFile(fqn)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(fh)
+	for i := range ents {
+		if err = writeDent(bw, &ents[i]); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = bw.Flush()
+	}
+	if errC := fh.Close(); err == nil {
+		err = errC
+	}
+	return err
+}
+
+func closeRuns(its []tcbDiter) {
+	for _, it := range its {
+		switch v := it.(type) {
+		case *tcbFileRun:
+			v.close()
+		case *tcbMerge:
+			for _, h := range *v {
+				closeRuns([]tcbDiter{h.it})
+			}
+		}
+	}
+}
+
+///////////////
+// tcbMemRun //
+///////////////
+
+func (run *tcbMemRun) next() (*tcbDent, error) {
+	if run.i >= len(run.ents) {
+		return nil, nil
+	}
+	run.i++
+	return &run.ents[run.i-1], nil
+}
+
+////////////////
+// tcbFileRun //
+////////////////
+
+func openFileRun(fqn string) (*tcbFileRun, error) {
+	fh, err := os.Open(fqn)
+	if err != nil {
+		return nil, err
+	}
+	return &tcbFileRun{fh: fh, br: bufio.NewReader(fh)}, nil
+}
+
+func (run *tcbFileRun) next() (*tcbDent, error) {
+	line, err := run.br.ReadBytes('\n')
+	if err != nil {
+		if err == io.EOF && len(line) == 0 {
+			return nil, nil
+		}
+		if err != io.EOF {
+			return nil, err
+		}
+	}
+	ent := &tcbDent{}
+	if err := cos.JSON.Unmarshal(line, ent); err != nil {
+		return nil, fmt.Errorf("failed to read tcb-diff entry: %w", err)
+	}
+	return ent, nil
+}
+
+func (run *tcbFileRun) close() {
+	if run.fh != nil {
+		cos.Close(run.fh)
+		run.fh = nil
+	}
+}
+
+//////////////
+// tcbMerge //
+//////////////
+
+func newTcbMerge(its []tcbDiter) (*tcbMerge, error) {
+	m := make(tcbMerge, 0, len(its))
+	for _, it := range its {
+		ent, err := it.next()
+		if err != nil {
+			closeRuns(its)
+			return nil, err
+		}
+		if ent != nil {
+			m = append(m, &tcbHead{ent: ent, it: it})
+		} else {
+			closeRuns([]tcbDiter{it})
+		}
+	}
+	heap.Init(&m)
+	return &m, nil
+}
+
+func (m *tcbMerge) next() (*tcbDent, error) {
+	if len(*m) == 0 {
+		return nil, nil
+	}
+	h := (*m)[0]
+	ent := h.ent
+	next, err := h.it.next()
+	switch {
+	case err != nil:
+		return nil, err
+	case next == nil:
+		closeRuns([]tcbDiter{h.it})
+		heap.Pop(m)
+	default:
+		h.ent = next
+		heap.Fix(m, 0)
+	}
+	return ent, nil
+}
+
+func (m tcbMerge) Len() int           { return len(m) }
+func (m tcbMerge) Less(i, j int) bool { return m[i].ent.Name < m[j].ent.Name }
+func (m tcbMerge) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m *tcbMerge) Push(x any)        { *m = append(*m, x.(*tcbHead)) }
+
+func (m *tcbMerge) Pop() any {
+	old := *m
+	n := len(old)
+	h := old[n-1]
+	*m = old[:n-1]
+	return h
+}
+
+////////////////
+// tcbDstList //
+////////////////
+
+func (l *tcbDstList) next() (*tcbDent, error) {
+	for {
+		for l.i < len(l.lst.Entries) {
+			en := l.lst.Entries[l.i]
+			l.i++
+			if en.IsDir() || !strings.HasPrefix(en.Name, l.prepend) {
+				continue
+			}
+			return &tcbDent{Name: en.Name[len(l.prepend):], Cksum: en.Checksum, Size: en.Size}, nil
+		}
+		if l.done {
+			return nil, nil
+		}
+		l.lst.Entries, l.i = l.lst.Entries[:0], 0
+		if _, err := core.T.Backend(l.bck).ListObjects(l.bck, l.lsmsg, &l.lst); err != nil {
+			return nil, err
+		}
+		l.lsmsg.ContinuationToken = l.lst.ContinuationToken
+		l.done = l.lst.ContinuationToken == ""
+	}
+}
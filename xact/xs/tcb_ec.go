@@ -0,0 +1,112 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/mpather"
+	"github.com/NVIDIA/aistore/memsys"
+)
+
+// x-tcb: source objects that exist only as EC slices (see cmn.ECConf):
+// - when the source bucket is erasure coded, each target also walks its EC metafiles (the 2nd jogger group,
+//   once done walking objects) to find objects that it must store (HRW) but doesn't - e.g., lost replica;
+// - those objects are restored from slices via the target's EC subsystem (see ECR) and then copied as usual;
+// - the restoration is accounted for separately (count and time); failures are counted and reported
+// - not supported with differential copy
+
+type (
+	// restore object from EC slices (implemented by the target, see COI)
+	ECR interface {
+		ECRecover(lom *core.LOM) error
+	}
+	tcbEC struct {
+		r         *XactTCB
+		ecr       ECR
+		joggers   *mpather.Jgroup
+		recovered atomic.Int64
+		failed    atomic.Int64
+		ns        atomic.Int64 // total time restoring
+	}
+)
+
+// nil if the target cannot restore
+func newTcbEC(r *XactTCB, slab *memsys.Slab, config *cmn.Config) *tcbEC {
+	ecr, ok := gcoi.(ECR)
+	if !ok {
+		return nil
+	}
+	var (
+		e    = &tcbEC{r: r, ecr: ecr}
+		opts = &mpather.JgroupOpts{
+			CTs:      []string{fs.ECMetaType},
+			VisitCT:  e.do,
+			Prefix:   r.p.args.Msg.Prefix,
+			Slab:     slab,
+			Parallel: 1,
+			Throttle: true,
+		}
+	)
+	opts.Bck.Copy(r.p.args.BckFrom.Bucket())
+	e.joggers = mpather.NewJoggerGroup(opts, config, nil)
+	return e
+}
+
+// walk local EC metafiles and wait for: joggers || parent-aborted
+func (e *tcbEC) run() error {
+	e.joggers.Run()
+	select {
+	case <-e.r.ChanAbort():
+		e.joggers.Stop()
+		return nil
+	case <-e.joggers.ListenFinished():
+	}
+	return e.joggers.Stop()
+}
+
+func (e *tcbEC) do(ct *core.CT, buf []byte) error {
+	var (
+		r   = e.r
+		lom = core.AllocLOM(ct.ObjectName())
+	)
+	defer core.FreeLOM(lom)
+	if err := lom.InitBck(ct.Bucket()); err != nil {
+		return err
+	}
+	if _, local, err := lom.HrwTarget(core.T.Sowner().Get()); err != nil || !local {
+		return err
+	}
+	if err := lom.Load(false /*cache it*/, false /*locked*/); err == nil || !cos.IsNotExist(err, 0) {
+		return nil // present (and copied), or failing to load for other reasons
+	}
+	if r.skipName(lom.ObjName) || r.skip.has(lom.ObjName) {
+		return nil
+	}
+	if len(r.preds.preds) > 0 && r.preds.skip(lom) {
+		return nil // (not restoring what won't be copied)
+	}
+
+	started := mono.NanoTime()
+	err := e.ecr.ECRecover(lom)
+	e.ns.Add(mono.SinceNano(started))
+	if err == nil {
+		err = lom.Load(false /*cache it*/, false /*locked*/)
+	}
+	if err != nil {
+		e.failed.Inc()
+		r.AddErr(err, 4, cos.SmoduleXs)
+		return nil
+	}
+	e.recovered.Inc()
+
+	r.do(lom, buf) //nolint:errcheck // handled by r.do (counted or aborted)
+	return nil
+}
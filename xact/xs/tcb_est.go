@@ -0,0 +1,114 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/core"
+)
+
+// x-tcb dry-run estimate (see apc.CopyBckMsg.DryRun):
+// - source objects that would be copied and their total size, by destination target;
+// - projected time to copy (this target): the total size at the throughput sampled while estimating -
+//   reading up to tcbEstSample bytes or, when transforming, the time it takes to transform -
+//   or at the bandwidth limit (see SetRateLimit), whichever is slower
+// zero ETA means unknown
+
+const tcbEstSample = 64 * cos.MiB
+
+type (
+	TCBEstimate struct {
+		PerTarget map[string]TCBEstimateCnt `json:"per-target"` // by destination target ID
+		TCBEstimateCnt
+		ETA time.Duration `json:"eta"`
+	}
+	TCBEstimateCnt struct {
+		Objs  int64 `json:"objs,string"`
+		Bytes int64 `json:"bytes,string"`
+	}
+
+	tcbEst struct {
+		per     map[string]*TCBEstimateCnt
+		sampled atomic.Int64 // bytes
+		ns      atomic.Int64 // time it took
+		mu      sync.Mutex
+	}
+)
+
+func newTcbEst() *tcbEst { return &tcbEst{per: make(map[string]*TCBEstimateCnt, 8)} }
+
+// `ns` is the time the dry-run took (non-zero when transforming)
+func (e *tcbEst) add(r *XactTCB, lom *core.LOM, toName string, size, ns int64, buf []byte) {
+	tid := "unknown"
+	dst := core.AllocLOM(toName)
+	if dst.InitBck(r.p.args.BckTo.Bucket()) == nil {
+		if tsi, _, err := dst.HrwTarget(core.T.Sowner().Get()); err == nil {
+			tid = tsi.ID()
+		}
+	}
+	core.FreeLOM(dst)
+
+	e.mu.Lock()
+	cnt, ok := e.per[tid]
+	if !ok {
+		cnt = &TCBEstimateCnt{}
+		e.per[tid] = cnt
+	}
+	cnt.Objs++
+	cnt.Bytes += size
+	e.mu.Unlock()
+
+	if e.sampled.Load() >= tcbEstSample {
+		return
+	}
+	if r.p.args.DP != nil {
+		e.sampled.Add(size)
+		e.ns.Add(ns)
+		return
+	}
+	e.sample(lom, buf)
+}
+
+// read and discard
+func (e *tcbEst) sample(lom *core.LOM, buf []byte) {
+	fh, err := lom.NewHandle(lom.FQN)
+	if err != nil {
+		return
+	}
+	started := mono.NanoTime()
+	n, err := io.CopyBuffer(io.Discard, fh, buf)
+	fh.Close()
+	if err == nil && n > 0 {
+		e.sampled.Add(n)
+		e.ns.Add(mono.SinceNano(started))
+	}
+}
+
+func (e *tcbEst) get(bps int64) *TCBEstimate {
+	est := &TCBEstimate{}
+	e.mu.Lock()
+	est.PerTarget = make(map[string]TCBEstimateCnt, len(e.per))
+	for tid, cnt := range e.per {
+		est.PerTarget[tid] = *cnt
+		est.Objs += cnt.Objs
+		est.Bytes += cnt.Bytes
+	}
+	e.mu.Unlock()
+
+	if tput := _tput(e.sampled.Load(), e.ns.Load()); tput > 0 {
+		est.ETA = _eta(est.Bytes, tput, int64(time.Second))
+	}
+	if bps > 0 {
+		est.ETA = max(est.ETA, _eta(est.Bytes, bps, int64(time.Second)))
+	}
+	return est
+}
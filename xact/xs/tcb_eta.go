@@ -0,0 +1,108 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/atomic"
+)
+
+// x-tcb estimated time remaining (this target):
+// - total: source bytes to copy - the delta when copying differentially (see tcbDiff),
+//   or else the on-disk size of the source bucket (prefix) estimated at startup;
+// - average: remaining bytes at the average throughput since the start;
+// - adaptive: ditto, at the throughput over the last (configurable) window - responds faster
+//   when the copy hits, e.g., a run of large objects
+// zero ETA means unknown
+
+const tcbEtaDfltWindow = 30 * time.Second
+
+type (
+	tcbSample struct {
+		t     int64 // mono time
+		bytes int64 // copied so far
+	}
+	tcbETA struct {
+		samples []tcbSample // (ring) at most one per second, oldest first
+		window  int64
+		started int64
+		last    atomic.Int64 // last sample time
+		total   atomic.Int64
+		mu      sync.Mutex
+	}
+)
+
+func (e *tcbETA) init(window time.Duration, started int64) {
+	if window <= 0 {
+		window = tcbEtaDfltWindow
+	}
+	e.window = int64(window)
+	e.started = started
+	e.samples = make([]tcbSample, 0, int(window/time.Second)+2)
+}
+
+// (re)start the estimation: differential copy starts copying when done listing
+func (e *tcbETA) reset(total, now int64) {
+	e.mu.Lock()
+	e.total.Store(total)
+	e.started = now
+	e.samples = e.samples[:0]
+	e.last.Store(0)
+	e.mu.Unlock()
+}
+
+// record (bytes copied so far) - at most once a second
+func (e *tcbETA) sample(now, bytes int64) {
+	if now-e.last.Load() < int64(time.Second) {
+		return
+	}
+	e.mu.Lock()
+	e.last.Store(now)
+	e.samples = append(e.samples, tcbSample{t: now, bytes: bytes})
+	// keep the oldest sample that is still within (or at the boundary of) the window
+	var i int
+	for i < len(e.samples)-2 && now-e.samples[i+1].t >= e.window {
+		i++
+	}
+	if i > 0 {
+		e.samples = append(e.samples[:0], e.samples[i:]...)
+	}
+	e.mu.Unlock()
+}
+
+// returns (average, adaptive)
+func (e *tcbETA) estimate(now, bytes int64) (avg, adaptive time.Duration) {
+	remaining := e.total.Load() - bytes
+	if remaining <= 0 || bytes <= 0 {
+		return 0, 0
+	}
+	e.mu.Lock()
+	avg = _eta(remaining, bytes, now-e.started)
+	if len(e.samples) > 0 {
+		first := e.samples[0]
+		if now-first.t > e.window {
+			// use the most recent (window) sample
+			for _, s := range e.samples[1:] {
+				if now-s.t < e.window {
+					break
+				}
+				first = s
+			}
+		}
+		adaptive = _eta(remaining, bytes-first.bytes, now-first.t)
+	}
+	e.mu.Unlock()
+	return avg, adaptive
+}
+
+func _eta(remaining, bytes, elapsed int64) time.Duration {
+	if bytes <= 0 || elapsed <= 0 {
+		return 0
+	}
+	return time.Duration(float64(remaining) / float64(bytes) * float64(elapsed))
+}
@@ -0,0 +1,65 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+)
+
+// x-tcb source name filters (see apc.CopyBckMsg.Regex and Template), in addition to the prefix:
+// - the template's prefix, when longer, narrows down the walk (see JgroupOpts.Prefix);
+// - the filters apply to all source objects: copied, collected for differential copy, and EC-restored
+
+type tcbFilter struct {
+	re *regexp.Regexp
+	pt *cos.ParsedTemplate
+}
+
+// nil when not filtering
+func newTcbFilter(msg *apc.CopyBckMsg) *tcbFilter {
+	if msg.Regex == "" && msg.Template == "" {
+		return nil
+	}
+	var (
+		f   = &tcbFilter{}
+		err error
+	)
+	if msg.Regex != "" {
+		f.re, err = regexp.Compile(msg.Regex)
+		debug.AssertNoErr(err) // validated (see apc.TCBMsg.Validate)
+	}
+	if msg.Template != "" {
+		var pt cos.ParsedTemplate
+		pt, err = cos.NewParsedTemplate(msg.Template)
+		debug.AssertNoErr(err)
+		f.pt = &pt
+	}
+	return f
+}
+
+func (f *tcbFilter) prefix(prefix string) string {
+	if f != nil && f.pt != nil && strings.HasPrefix(f.pt.Prefix, prefix) {
+		return f.pt.Prefix
+	}
+	return prefix
+}
+
+func (f *tcbFilter) match(name string) bool {
+	if f.re != nil && !f.re.MatchString(name) {
+		return false
+	}
+	return f.pt == nil || f.pt.Match(name)
+}
+
+// returns true if the (source) object is to be skipped by name
+func (r *XactTCB) skipName(name string) bool {
+	return r.p.args.Msg.Skip(name) || (r.filter != nil && !r.filter.match(name))
+}
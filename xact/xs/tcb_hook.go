@@ -0,0 +1,137 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+)
+
+// x-tcb per-object completion hook:
+// - invoked by the target that stores (writes) a given destination object - upon confirmed write;
+// - runs on a bounded pool of workers fed via bounded queue; when the queue is full
+//   the event is dropped (and counted) - the hook never stalls the copy;
+// - hook errors are logged and counted; optionally (TCBHookOpts.Fatal), the first error aborts the xaction
+// - apc.CopyBckMsg.Webhook: the hook that POSTs each event to a given URL (see tcbWebhook)
+
+type (
+	// srcName is empty when it cannot be determined (see XactTCB.srcName)
+	TCBObjHook func(srcName, dstName string, size int64, cksum *cos.Cksum) error
+
+	TCBHookOpts struct {
+		Workers int  // default: tcbHookWorkers
+		Queue   int  // default: tcbHookQueue
+		Fatal   bool // abort x-tcb upon hook error
+	}
+
+	tcbHookEvent struct {
+		cksum            *cos.Cksum
+		srcName, dstName string
+		size             int64
+	}
+	tcbHook struct {
+		r       *XactTCB
+		fn      TCBObjHook
+		workCh  chan tcbHookEvent
+		wg      sync.WaitGroup
+		called  atomic.Int64
+		errs    atomic.Int64
+		dropped atomic.Int64
+		fatal   bool
+	}
+)
+
+const (
+	tcbHookWorkers = 4
+	tcbHookQueue   = 256
+
+	tcbWebhookTimeout = 10 * time.Second
+)
+
+// register per-object completion hook; must be called prior to running the xaction
+func (r *XactTCB) SetObjHook(fn TCBObjHook, opts TCBHookOpts) {
+	h := &tcbHook{
+		r:      r,
+		fn:     fn,
+		workCh: make(chan tcbHookEvent, cos.NonZero(opts.Queue, tcbHookQueue)),
+		fatal:  opts.Fatal,
+	}
+	for range cos.NonZero(opts.Workers, tcbHookWorkers) {
+		h.wg.Add(1)
+		go h.work()
+	}
+	r.hook = h
+}
+
+// destination object `dst` has been written
+func (h *tcbHook) post(srcName string, dst *core.LOM) {
+	ev := tcbHookEvent{srcName: srcName, dstName: dst.ObjName, size: dst.Lsize(true)}
+	if cksum := dst.Checksum(); cksum != nil {
+		ev.cksum = cksum.Clone()
+	}
+	select {
+	case h.workCh <- ev:
+	default:
+		if n := h.dropped.Inc(); n == 1 || n%1000 == 0 {
+			nlog.Warningln(h.r.Name(), "object hook: queue full, dropped", n, "event(s)")
+		}
+	}
+}
+
+func (h *tcbHook) work() {
+	defer h.wg.Done()
+	for ev := range h.workCh {
+		h.called.Inc()
+		err := h.fn(ev.srcName, ev.dstName, ev.size, ev.cksum)
+		if err == nil {
+			continue
+		}
+		h.errs.Inc()
+		err = fmt.Errorf("%s: object hook (%s): %w", h.r, ev.dstName, err)
+		if h.fatal {
+			h.r.Abort(err)
+		} else {
+			nlog.Warningln(err)
+		}
+	}
+}
+
+// drain pending events and stop the workers
+func (h *tcbHook) stop() {
+	close(h.workCh)
+	h.wg.Wait()
+}
+
+// POST apc.TCBWebhookEvent to the user-specified URL (apc.CopyBckMsg.Webhook)
+func tcbWebhook(r *XactTCB, url string) TCBObjHook {
+	client := cmn.NewClient(cmn.TransportArgs{Timeout: tcbWebhookTimeout})
+	return func(srcName, dstName string, size int64, cksum *cos.Cksum) error {
+		ev := &apc.TCBWebhookEvent{XactID: r.ID(), SrcName: srcName, Name: dstName, Size: size}
+		if cksum != nil {
+			ev.CksumType, ev.CksumValue = cksum.Get()
+		}
+		resp, err := client.Post(url, cos.ContentJSON, bytes.NewReader(cos.MustMarshal(ev)))
+		if err != nil {
+			return err
+		}
+		cos.DrainReader(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("webhook %s: %s", url, resp.Status)
+		}
+		return nil
+	}
+}
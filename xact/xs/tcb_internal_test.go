@@ -0,0 +1,2134 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/core/mock"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/mpather"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/tools/tassert"
+	"github.com/NVIDIA/aistore/transport"
+	"github.com/NVIDIA/aistore/transport/bundle"
+	"github.com/NVIDIA/aistore/xact/xreg"
+)
+
+type (
+	// finalizes (renames) work files - the rest is mocked
+	tcbtTarget struct {
+		*mock.TargetMock
+		puts   []cmn.OWT    // PutObject calls
+		custom []cos.StrKVs // ditto, custom metadata
+		cksums []*cos.Cksum // ditto, checksums to validate
+		sse    []bool       // ditto, with server-side encryption
+	}
+	tcbtSowner struct {
+		smap meta.Smap
+	}
+	tcbtListeners struct{}
+
+	// in-memory data provider
+	tcbtDP struct {
+		objs map[string][]byte
+	}
+
+	// remote backend with object tags (core.TagBackend); the rest is not implemented
+	tcbtBackend struct {
+		core.Backend
+		tags map[string]cos.StrKVs // by uname
+		mu   sync.Mutex
+	}
+)
+
+// remote backend with server-side encryption (core.SSEBackend); the rest is not implemented
+type tcbtSSEBackend struct {
+	core.Backend
+}
+
+func (*tcbtSSEBackend) PutObjSSE(io.ReadCloser, *core.LOM, *apc.SSEMsg, *http.Request) (int, error) {
+	return 0, nil
+}
+
+func (b *tcbtBackend) GetObjTags(lom *core.LOM) (cos.StrKVs, int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tags[lom.Uname()], 0, nil
+}
+
+func (b *tcbtBackend) PutObjTags(lom *core.LOM, tags cos.StrKVs) (int, error) {
+	b.mu.Lock()
+	b.tags[lom.Uname()] = tags
+	b.mu.Unlock()
+	return 0, nil
+}
+
+func (*tcbtTarget) FinalizeObj(lom *core.LOM, workFQN string, _ core.Xact, _ cmn.OWT) (int, error) {
+	if err := cos.CreateDir(filepath.Dir(lom.FQN)); err != nil {
+		return 0, err
+	}
+	return 0, os.Rename(workFQN, lom.FQN)
+}
+
+func (t *tcbtTarget) PutObject(lom *core.LOM, params *core.PutParams) error {
+	t.puts = append(t.puts, params.OWT)
+	t.custom = append(t.custom, lom.GetCustomMD())
+	t.cksums = append(t.cksums, params.Cksum)
+	t.sse = append(t.sse, params.SSE != nil)
+	return nil
+}
+
+func (o *tcbtSowner) Get() *meta.Smap             { return &o.smap }
+func (*tcbtSowner) Listeners() meta.SmapListeners { return &tcbtListeners{} }
+
+func (*tcbtListeners) Reg(meta.Slistener)   {}
+func (*tcbtListeners) Unreg(meta.Slistener) {}
+
+func (dp *tcbtDP) Reader(lom *core.LOM, _, _ bool) (cos.ReadOpenCloser, cos.OAH, error) {
+	b, ok := dp.objs[lom.ObjName]
+	if !ok {
+		return nil, nil, cos.NewErrNotFound(core.T, lom.Cname())
+	}
+	return cos.NopOpener(io.NopCloser(bytes.NewReader(b))), &cmn.ObjAttrs{Size: int64(len(b))}, nil
+}
+
+func newTestTCB(t testing.TB, msg *apc.TCBMsg, dp core.DP) *XactTCB {
+	return newTestTCBProvider(t, msg, dp, apc.AIS, apc.AIS)
+}
+
+func newTestTCBProvider(t testing.TB, msg *apc.TCBMsg, dp core.DP, from, to string) *XactTCB {
+	var (
+		props   = &cmn.Bprops{Cksum: cmn.CksumConf{Type: cos.ChecksumXXHash}}
+		bckFrom = meta.NewBck("src", from, cmn.NsGlobal, props)
+		bckTo   = meta.NewBck("dst", to, cmn.NsGlobal, props)
+		bmd     = mock.NewBaseBownerMock(bckFrom, bckTo)
+		tmock   = &tcbtTarget{TargetMock: mock.NewTarget(bmd)}
+		sowner  = &tcbtSowner{}
+	)
+	sowner.smap.Tmap = meta.NodeMap{tmock.SID(): tmock.Snode()}
+	tmock.SO = sowner
+	core.T = tmock
+
+	fs.TestNew(nil)
+	_, err := fs.Add(t.TempDir(), tmock.SID())
+	tassert.CheckFatal(t, err)
+	fs.CSM.Reg(fs.ObjectType, &fs.ObjectContentResolver{}, true)
+	fs.CSM.Reg(fs.WorkfileType, &fs.WorkfileContentResolver{}, true)
+	for _, bck := range []*meta.Bck{bckFrom, bckTo} {
+		if errs := fs.CreateBucket(bck.Bucket(), false /*nilbmd*/); len(errs) > 0 {
+			t.Fatal(errs[0])
+		}
+	}
+
+	r := &XactTCB{}
+	r.InitBase(cos.GenUUID(), apc.ActCopyBck, "" /*ctlmsg*/, bckTo)
+	r.p = &tcbFactory{args: &xreg.TCBArgs{BckFrom: bckFrom, BckTo: bckTo, DP: dp, Msg: msg}, owt: cmn.OwtCopy}
+	r.Config = cmn.GCO.Get()
+	return r
+}
+
+func tcbtLOM(t testing.TB, r *XactTCB, name string) *core.LOM {
+	lom := core.AllocLOM(name)
+	tassert.CheckFatal(t, lom.InitBck(r.p.args.BckFrom.Bucket()))
+	return lom
+}
+
+func TestTCBArchShards(t *testing.T) {
+	const (
+		numObjs    = 7
+		maxEntries = 3
+	)
+	var (
+		dp  = &tcbtDP{objs: make(map[string][]byte, numObjs)}
+		msg = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{
+			Prepend: "pre/",
+			Arch:    &apc.ArchTCBMsg{Mime: ".tar", MaxEntries: maxEntries},
+		}}
+	)
+	for i := range numObjs {
+		name := "obj-" + strconv.Itoa(i)
+		dp.objs[name] = bytes.Repeat([]byte{byte('a' + i)}, 100+i)
+	}
+	r := newTestTCB(t, msg, dp)
+	r.arch = &tcbArch{}
+	tassert.CheckFatal(t, r.arch.init(r, msg.Arch))
+
+	for i := range numObjs {
+		lom := tcbtLOM(t, r, "obj-"+strconv.Itoa(i))
+		tassert.CheckFatal(t, r.do(lom, nil))
+		core.FreeLOM(lom)
+	}
+	tassert.CheckFatal(t, r.arch.finish(false /*aborted*/))
+	tassert.CheckFatal(t, r.Err())
+
+	// expecting 3 shards: 3 + 3 + 1 entries
+	var (
+		entries   = make(map[string][]byte, numObjs)
+		numShards = (numObjs + maxEntries - 1) / maxEntries
+	)
+	for seq := range numShards {
+		name := msg.Arch.ShardName(core.T.SID(), seq, ".tar")
+		lom := core.AllocLOM(name)
+		tassert.CheckFatal(t, lom.InitBck(r.p.args.BckTo.Bucket()))
+		fh, err := os.Open(lom.FQN)
+		tassert.CheckFatal(t, err)
+
+		var cnt int
+		tr := tar.NewReader(fh)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			tassert.CheckFatal(t, err)
+			b, err := io.ReadAll(tr)
+			tassert.CheckFatal(t, err)
+			entries[hdr.Name] = b
+			cnt++
+		}
+		fh.Close()
+		core.FreeLOM(lom)
+
+		expected := maxEntries
+		if seq == numShards-1 {
+			expected = numObjs - seq*maxEntries
+		}
+		tassert.Errorf(t, cnt == expected, "shard %q: expected %d entries, got %d", name, expected, cnt)
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	tassert.Fatalf(t, len(names) == numObjs, "expected %d archived entries, got %v", numObjs, names)
+	for i, name := range names {
+		src := "obj-" + strconv.Itoa(i)
+		tassert.Errorf(t, name == "pre/"+src, "unexpected entry name %q", name)
+		tassert.Errorf(t, bytes.Equal(entries[name], dp.objs[src]), "entry %q: content mismatch", name)
+	}
+	tassert.Errorf(t, r.Objs() == numObjs, "expected %d archived objects, got %d", numObjs, r.Objs())
+}
+
+// counts (and otherwise ignores) copy requests
+// - when `write` is set, stores the destination (with the source size and checksum, and CoiParams.CustomMD)
+type tcbtCOI struct {
+	hook   func(lom *core.LOM) // (e.g., to modify the source "while" copying)
+	copied []string
+	sse    []*apc.SSEMsg         // CoiParams.SSE
+	custom map[string]cos.StrKVs // CoiParams.CustomMD by destination name
+	slow   time.Duration         // when set, reads the source (see CoiParams.RStats) sleeping prior to each read
+	errs   []error               // when set, returned (and consumed) one per call
+	mu     sync.Mutex
+	write  bool
+}
+
+func (c *tcbtCOI) CopyObject(lom *core.LOM, _ *bundle.DataMover, params *CoiParams) (int64, error) {
+	c.mu.Lock()
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		c.mu.Unlock()
+		return 0, err
+	}
+	c.copied = append(c.copied, params.ObjnameTo)
+	c.sse = append(c.sse, params.SSE)
+	if params.CustomMD != nil {
+		if c.custom == nil {
+			c.custom = make(map[string]cos.StrKVs, 4)
+		}
+		c.custom[params.ObjnameTo] = params.CustomMD
+	}
+	c.mu.Unlock()
+	if c.hook != nil {
+		c.hook(lom)
+	}
+	if c.slow > 0 {
+		fh, err := cos.NewFileHandle(lom.FQN)
+		if err != nil {
+			return 0, err
+		}
+		var reader cos.ReadOpenCloser = &tcbtSlowReader{fh, c.slow}
+		if params.RStats != nil {
+			reader = params.RStats.Reader(reader)
+		}
+		_, err = io.CopyBuffer(tcbtDiscard{}, reader, params.Buf) // (one read per buffer)
+		reader.Close()
+		if err != nil {
+			return 0, err
+		}
+	}
+	if params.DryRun {
+		return lom.Lsize(true), nil
+	}
+	if !c.write {
+		return 0, nil
+	}
+	dst := core.AllocLOM(params.ObjnameTo)
+	defer core.FreeLOM(dst)
+	if err := dst.InitBck(params.BckTo.Bucket()); err != nil {
+		return 0, err
+	}
+	if err := cos.CreateDir(filepath.Dir(dst.FQN)); err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(dst.FQN, make([]byte, lom.Lsize()), cos.PermRWR); err != nil {
+		return 0, err
+	}
+	dst.SetSize(lom.Lsize())
+	dst.SetCksum(lom.Checksum())
+	dst.SetAtimeUnix(time.Now().UnixNano())
+	if params.CustomMD != nil {
+		dst.SetCustomMD(params.CustomMD)
+	}
+	return lom.Lsize(), dst.Persist()
+}
+
+type tcbtSlowReader struct {
+	cos.ReadOpenCloser
+	delay time.Duration
+}
+
+func (r *tcbtSlowReader) Read(p []byte) (int, error) {
+	time.Sleep(r.delay)
+	return r.ReadOpenCloser.Read(p)
+}
+
+type tcbtDiscard struct{} // (unlike io.Discard, not implementing io.ReaderFrom)
+
+func (tcbtDiscard) Write(p []byte) (int, error) { return len(p), nil }
+
+// store (or overwrite) object
+func tcbtPut(t testing.TB, lom *core.LOM, content string) {
+	tassert.CheckFatal(t, cos.CreateDir(filepath.Dir(lom.FQN)))
+	tassert.CheckFatal(t, os.WriteFile(lom.FQN, []byte(content), cos.PermRWR))
+	lom.SetSize(int64(len(content)))
+	ck := cos.NewCksumHash(cos.ChecksumXXHash)
+	ck.H.Write([]byte(content))
+	ck.Finalize()
+	lom.SetCksum(ck.Clone())
+	lom.SetAtimeUnix(time.Now().UnixNano())
+	tassert.CheckFatal(t, lom.Persist())
+}
+
+// read buffer size selection (see tcbBufSize) on a simulated slow disk
+func TestTCBBufSizeSlowDisk(t *testing.T) {
+	var (
+		hdd = tcbtSlowDisk(t, true)
+		ssd = tcbtSlowDisk(t, false)
+	)
+	tassert.Errorf(t, hdd < ssd, "expected spinning-disk default (%s) to read faster than the SSD one (%s): %v vs %v",
+		cos.ToSizeIEC(dfltBufSizeHDD, 0), cos.ToSizeIEC(dfltBufSizeSSD, 0), hdd, ssd)
+}
+
+func BenchmarkTCBBufSizeSlowDisk(b *testing.B) {
+	for _, hdd := range []bool{true, false} {
+		name := "ssd"
+		if hdd {
+			name = "hdd"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(tcbtSlowNum * tcbtSlowSize)
+			for range b.N {
+				tcbtSlowDisk(b, hdd)
+			}
+		})
+	}
+}
+
+const (
+	tcbtSlowNum     = 4
+	tcbtSlowSize    = 4 * cos.MiB
+	tcbtSlowLatency = 500 * time.Microsecond // per read request
+)
+
+// run x-tcb joggers to read (and discard) tcbtSlowNum objects, with all mountpaths
+// being either spinning or not; returns elapsed time
+func tcbtSlowDisk(tb testing.TB, hdd bool) time.Duration {
+	var (
+		r       = newTestTCB(tb, &apc.TCBMsg{}, nil)
+		tcoi    = &tcbtCOI{slow: tcbtSlowLatency}
+		content = strings.Repeat("x", tcbtSlowSize)
+	)
+	savedCOI, savedRot := gcoi, tcbRotational
+	gcoi = tcoi
+	tcbRotational = func(*fs.Mountpath) bool { return hdd }
+	defer func() { gcoi, tcbRotational = savedCOI, savedRot }()
+
+	for i := range tcbtSlowNum {
+		lom := tcbtLOM(tb, r, "obj"+strconv.Itoa(i))
+		tcbtPut(tb, lom, content)
+		core.FreeLOM(lom)
+	}
+	slab, err := memsys.PageMM().GetSlab(memsys.MaxPageSlabSize)
+	tassert.CheckFatal(tb, err)
+	p := r.p
+	p.Args.UUID = cos.GenUUID()
+	r = newTCB(p, slab, r.Config, core.T.Sowner().Get())
+
+	started := time.Now()
+	r.BckJog.Run()
+	tassert.CheckFatal(tb, r.BckJog.Wait())
+	elapsed := time.Since(started)
+
+	expected := int64(dfltBufSizeSSD)
+	if hdd {
+		expected = dfltBufSizeHDD
+	}
+	ext, ok := r.Snap().Ext.(*ExtTCBStats)
+	tassert.Fatalf(tb, ok && len(ext.BufSizes) == len(fs.GetAvail()), "expected effective buffer sizes in %+v", r.Snap().Ext)
+	for mpath, size := range ext.BufSizes {
+		tassert.Errorf(tb, size == expected, "%s: expected effective buffer size %d, got %d", mpath, expected, size)
+	}
+	tassert.Errorf(tb, len(tcoi.copied) == tcbtSlowNum, "expected %d copies, got %d", tcbtSlowNum, len(tcoi.copied))
+	return elapsed
+}
+
+func TestTCBIfNoneMatch(t *testing.T) {
+	var (
+		msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{IfNoneMatch: true}}
+		r    = newTestTCB(t, msg, nil)
+		tcoi = &tcbtCOI{}
+		src  = cos.NewCksum(cos.ChecksumXXHash, "0123456789abcdef")
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	// destination objects: identical ("match") and different ("mismatch"); "absent" is not there
+	for name, val := range map[string]string{"match": src.Value(), "mismatch": "fedcba9876543210"} {
+		dst := core.AllocLOM(name)
+		tassert.CheckFatal(t, dst.InitBck(r.p.args.BckTo.Bucket()))
+		tassert.CheckFatal(t, cos.CreateDir(filepath.Dir(dst.FQN)))
+		tassert.CheckFatal(t, os.WriteFile(dst.FQN, []byte(name), cos.PermRWR))
+		dst.SetSize(int64(len(name)))
+		dst.SetCksum(cos.NewCksum(cos.ChecksumXXHash, val))
+		dst.SetAtimeUnix(time.Now().UnixNano())
+		tassert.CheckFatal(t, dst.Persist())
+		core.FreeLOM(dst)
+	}
+
+	for _, name := range []string{"match", "mismatch", "absent"} {
+		lom := tcbtLOM(t, r, name)
+		lom.SetCksum(src)
+		tassert.CheckFatal(t, r.do(lom, nil))
+		core.FreeLOM(lom)
+	}
+
+	sort.Strings(tcoi.copied)
+	tassert.Errorf(t, len(tcoi.copied) == 2 && tcoi.copied[0] == "absent" && tcoi.copied[1] == "mismatch",
+		"expected (only) absent and mismatched objects to be copied, got %v", tcoi.copied)
+
+	var (
+		skipped = r.cond.skipped.Load()
+		changed = r.cond.changed.Load()
+		absent  = r.cond.absent.Load()
+	)
+	tassert.Errorf(t, skipped == 1, "expected 1 skipped, got %d", skipped)
+	tassert.Errorf(t, changed == 1, "expected 1 changed, got %d", changed)
+	tassert.Errorf(t, absent == 1, "expected 1 absent, got %d", absent)
+}
+
+func TestTCBStartAfter(t *testing.T) {
+	var (
+		msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Prefix: "a/", StartAfter: "a/obj-3"}}
+		r    = newTestTCB(t, msg, nil)
+		tcoi = &tcbtCOI{}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	// visiting in reverse order, to make sure there's no dependency on ordering
+	for i := 5; i >= 0; i-- {
+		lom := tcbtLOM(t, r, "a/obj-"+strconv.Itoa(i))
+		tassert.CheckFatal(t, r.do(lom, nil))
+		core.FreeLOM(lom)
+	}
+	sort.Strings(tcoi.copied)
+	tassert.Errorf(t, len(tcoi.copied) == 2 && tcoi.copied[0] == "a/obj-4" && tcoi.copied[1] == "a/obj-5",
+		"expected (only) objects after %q to be copied, got %v", msg.StartAfter, tcoi.copied)
+}
+
+func TestTCBLocalOnly(t *testing.T) {
+	var (
+		props  = &cmn.Bprops{Cksum: cmn.CksumConf{Type: cos.ChecksumXXHash}}
+		remote = meta.NewBck("dst", apc.AWS, cmn.NsGlobal, props)
+		local  = meta.NewBck("dst", apc.AIS, cmn.NsGlobal, props)
+	)
+	tests := []struct {
+		kind      string
+		bckTo     *meta.Bck
+		localOnly bool
+		owt       cmn.OWT
+	}{
+		{apc.ActCopyBck, remote, false, cmn.OwtCopy},     // write-through (default)
+		{apc.ActCopyBck, remote, true, cmn.OwtCopyLocal}, // local-only
+		{apc.ActCopyBck, local, true, cmn.OwtCopy},       // (not applicable)
+		{apc.ActETLBck, remote, false, cmn.OwtTransform},
+	}
+	for _, test := range tests {
+		args := &xreg.TCBArgs{BckTo: test.bckTo, Msg: &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{LocalOnly: test.localOnly}}}
+		owt := tcbOWT(test.kind, args)
+		tassert.Errorf(t, owt == test.owt, "%s => %s (local-only %t): expected %s, got %s",
+			test.kind, test.bckTo.Cname(""), test.localOnly, test.owt, owt)
+	}
+
+	// receive side: the same OWT is used to write (PUT) objects received from other targets
+	for _, localOnly := range []bool{false, true} {
+		var (
+			msg = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{LocalOnly: localOnly}}
+			r   = newTestTCB(t, msg, nil)
+			hdr = &transport.ObjHdr{ObjName: "obj"}
+		)
+		r.p.args.BckTo = remote // (receiving side does not consult BckTo)
+		r.p.owt = tcbOWT(apc.ActCopyBck, r.p.args)
+		hdr.Bck.Copy(local.Bucket())
+		hdr.ObjAttrs.Size = 3
+
+		lom := core.AllocLOM(hdr.ObjName)
+		tassert.CheckFatal(t, r._recv(hdr, bytes.NewReader([]byte("abc")), lom))
+		core.FreeLOM(lom)
+
+		puts := core.T.(*tcbtTarget).puts
+		tassert.Fatalf(t, len(puts) == 1, "expected a single PUT, got %d", len(puts))
+		tassert.Errorf(t, puts[0] == r.p.owt, "local-only %t: expected %s, got %s", localOnly, r.p.owt, puts[0])
+	}
+}
+
+func TestTCBOnCollision(t *testing.T) {
+	const numPairs = 50
+	for _, policy := range []string{apc.TCBCollisionErr, apc.TCBCollisionSkip, apc.TCBCollisionSuffix} {
+		t.Run(policy, func(t *testing.T) {
+			var (
+				// "x.jpeg" => "x.jpg" collides with "x.jpg"
+				msg = &apc.TCBMsg{
+					Ext:        cos.StrKVs{"jpeg": "jpg"},
+					CopyBckMsg: apc.CopyBckMsg{OnCollision: policy},
+				}
+				r    = newTestTCB(t, msg, nil)
+				tcoi = &tcbtCOI{}
+				wg   sync.WaitGroup
+			)
+			savedCOI := gcoi
+			gcoi = tcoi
+			defer func() { gcoi = savedCOI }()
+
+			r.names = newTcbNames(policy)
+
+			// parallel dispatch (as in: joggers)
+			for _, ext := range []string{".jpg", ".jpeg"} {
+				wg.Add(1)
+				go func(ext string) {
+					defer wg.Done()
+					for i := range numPairs {
+						lom := tcbtLOM(t, r, "img-"+strconv.Itoa(i)+ext)
+						r.do(lom, nil) //nolint:errcheck // checking below
+						core.FreeLOM(lom)
+					}
+				}(ext)
+			}
+			wg.Wait()
+
+			copied := make(map[string]int, 2*numPairs)
+			for _, name := range tcoi.copied {
+				copied[name]++
+			}
+			for name, n := range copied {
+				tassert.Errorf(t, n == 1, "destination %q written %d times", name, n)
+			}
+			cnt := r.names.cnt.Load()
+			switch policy {
+			case apc.TCBCollisionErr:
+				tassert.Errorf(t, r.IsAborted(), "expected abort")
+				tassert.Errorf(t, cnt >= 1, "expected at least one collision")
+			case apc.TCBCollisionSkip:
+				tassert.Errorf(t, len(copied) == numPairs, "expected %d copies, got %d", numPairs, len(copied))
+				tassert.Errorf(t, cnt == numPairs, "expected %d collisions, got %d", numPairs, cnt)
+			case apc.TCBCollisionSuffix:
+				tassert.Errorf(t, len(copied) == 2*numPairs, "expected %d copies, got %d", 2*numPairs, len(copied))
+				tassert.Errorf(t, cnt == numPairs, "expected %d collisions, got %d", numPairs, cnt)
+				for i := range numPairs {
+					base := "img-" + strconv.Itoa(i)
+					_, ok1 := copied[base+".jpg"]
+					_, ok2 := copied[base+"-1.jpg"]
+					tassert.Errorf(t, ok1 && ok2, "expected both %s.jpg and %s-1.jpg", base, base)
+				}
+			}
+		})
+	}
+}
+
+func TestTCBManifest(t *testing.T) {
+	const numObjs = 5
+	var (
+		msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Prepend: "cp/", Manifest: "reports/"}}
+		r    = newTestTCB(t, msg, nil)
+		tcoi = &tcbtCOI{write: true}
+		m    = &tcbManifest{}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	tassert.CheckFatal(t, m.init(r, msg.Manifest))
+	r.manifest = m
+	name := m.lom.ObjName
+
+	expected := make(map[string]apc.TCBManifestEntry, numObjs+1)
+	for i := range numObjs {
+		var (
+			lom   = tcbtLOM(t, r, "obj-"+strconv.Itoa(i))
+			cksum = cos.NewCksum(cos.ChecksumXXHash, strconv.Itoa(1000000+i))
+		)
+		lom.SetSize(int64(10 * (i + 1)))
+		lom.SetCksum(cksum)
+		tassert.CheckFatal(t, r.do(lom, nil))
+		core.FreeLOM(lom)
+
+		e := apc.TCBManifestEntry{Name: "cp/obj-" + strconv.Itoa(i), Size: int64(10 * (i + 1))}
+		e.CksumType, e.CksumValue = cksum.Get()
+		expected[e.Name] = e
+	}
+
+	// received from another target
+	hdr := &transport.ObjHdr{ObjName: "cp/remote"}
+	hdr.Bck.Copy(r.p.args.BckTo.Bucket())
+	hdr.ObjAttrs.Size = 3
+	lom := core.AllocLOM(hdr.ObjName)
+	tassert.CheckFatal(t, r._recv(hdr, bytes.NewReader([]byte("abc")), lom))
+	core.FreeLOM(lom)
+	expected[hdr.ObjName] = apc.TCBManifestEntry{Name: hdr.ObjName, Size: 3}
+
+	tassert.CheckFatal(t, m.fin(false /*aborted*/))
+
+	// read and compare
+	lom = core.AllocLOM(name)
+	tassert.CheckFatal(t, lom.InitBck(r.p.args.BckTo.Bucket()))
+	b, err := os.ReadFile(lom.FQN)
+	core.FreeLOM(lom)
+	tassert.CheckFatal(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(b), []byte{'\n'})
+	tassert.Fatalf(t, len(lines) == len(expected), "expected %d manifest entries, got %d", len(expected), len(lines))
+	for _, line := range lines {
+		var e apc.TCBManifestEntry
+		tassert.CheckFatal(t, cos.JSON.Unmarshal(line, &e))
+		exp, ok := expected[e.Name]
+		tassert.Fatalf(t, ok, "unexpected manifest entry %q", e.Name)
+		tassert.Errorf(t, e.Size == exp.Size && e.CksumType == exp.CksumType && e.CksumValue == exp.CksumValue,
+			"manifest entry mismatch: %+v vs %+v", e, exp)
+		delete(expected, e.Name)
+	}
+}
+
+func TestTCBOnSrcChange(t *testing.T) {
+	tests := []struct {
+		policy    string
+		mutations int // num times to modify the source while copying
+		copies    int
+		changed   int64
+		fail      bool
+	}{
+		{apc.TCBSrcChangeRecord, 1, 1, 1, true},
+		{apc.TCBSrcChangeRetry, 1, 2, 1, false},
+		{apc.TCBSrcChangeRetry, 2, 3, 2, false},
+		{apc.TCBSrcChangeRetry, 100, 1 + tcbSrcChangeRetries, 1 + tcbSrcChangeRetries, true},
+		{apc.TCBSrcChangeRecord, 0, 1, 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.policy+"-"+strconv.Itoa(test.mutations), func(t *testing.T) {
+			var (
+				msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{OnSrcChange: test.policy}}
+				r    = newTestTCB(t, msg, nil)
+				tcoi = &tcbtCOI{}
+				n    int
+			)
+			savedCOI := gcoi
+			gcoi = tcoi
+			defer func() { gcoi = savedCOI }()
+
+			lom := tcbtLOM(t, r, "obj")
+			tcbtPut(t, lom, "original")
+			tassert.CheckFatal(t, lom.Load(false, false))
+
+			tcoi.hook = func(*core.LOM) {
+				if n < test.mutations {
+					n++
+					src := tcbtLOM(t, r, "obj")
+					tcbtPut(t, src, "modified-"+strconv.Itoa(n))
+					core.FreeLOM(src)
+				}
+			}
+			err := r.copyObject(lom, nil, "obj")
+			core.FreeLOM(lom)
+
+			tassert.Errorf(t, (err != nil) == test.fail, "expected failure: %t, got err: %v", test.fail, err)
+			tassert.Errorf(t, len(tcoi.copied) == test.copies, "expected %d copies, got %d", test.copies, len(tcoi.copied))
+			tassert.Errorf(t, r.srcChange.Load() == test.changed, "expected %d detected changes, got %d",
+				test.changed, r.srcChange.Load())
+		})
+	}
+}
+
+func TestTCBHashPrefix(t *testing.T) {
+	const numObjs = 64 * 1024
+	tests := []apc.HashPrefixMsg{
+		{},       // defaults: 2 hex characters
+		{Len: 1}, // 16 buckets
+		{Len: 1, Alphabet: "abcdefgh"},
+		{Len: 3, Alphabet: "xyz"},
+	}
+	for _, hp := range tests {
+		msg := &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Prepend: "pre/", HashPrefix: &hp}}
+		tassert.CheckFatal(t, msg.Validate(false))
+
+		var (
+			l       = cos.NonZero(hp.Len, apc.HashPrefixDefaultLen)
+			abc     = cos.Left(hp.Alphabet, apc.HashPrefixDefaultAlphabet)
+			buckets = make(map[string]int, 256)
+		)
+		for i := range numObjs {
+			src := "dir/obj-" + strconv.Itoa(i)
+			name := msg.ToName(src)
+			tassert.Fatalf(t, name == msg.ToName(src), "%q: non-deterministic mapping", src)
+			tassert.Fatalf(t, len(name) == l+1+len("pre/")+len(src) && strings.HasSuffix(name, "/pre/"+src),
+				"unexpected destination name %q", name)
+			hash := name[:l]
+			for _, c := range hash {
+				tassert.Fatalf(t, strings.ContainsRune(abc, c), "hash %q: unexpected character %q", hash, c)
+			}
+			buckets[hash]++
+		}
+
+		// expecting all hash buckets populated and (roughly) evenly
+		num := 1
+		for range l {
+			num *= len(abc)
+		}
+		tassert.Fatalf(t, len(buckets) == num, "%+v: expected %d hash buckets, got %d", hp, num, len(buckets))
+		var (
+			mean = float64(numObjs) / float64(num)
+			dev  = 5 * math.Sqrt(mean) // (binomial, approx.)
+		)
+		for hash, cnt := range buckets {
+			tassert.Errorf(t, math.Abs(float64(cnt)-mean) < dev, "%+v: hash bucket %q is uneven: %d (mean %.1f)",
+				hp, hash, cnt, mean)
+		}
+	}
+
+	// invalid
+	for _, hp := range []apc.HashPrefixMsg{{Len: -1}, {Len: apc.HashPrefixMaxLen + 1}, {Alphabet: "a"}, {Alphabet: "aba"}, {Alphabet: "a/b"}} {
+		tassert.Errorf(t, hp.Validate() != nil, "expected %+v to fail validation", hp)
+	}
+	msg := &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Sync: true, HashPrefix: &apc.HashPrefixMsg{}}}
+	tassert.Errorf(t, msg.Validate(false) != nil, "expected hash prefix with sync to fail validation")
+}
+
+func TestTCBDiff(t *testing.T) {
+	const (
+		numObjs = 10
+		runSize = 3 // to spill most of the source listing
+	)
+	var (
+		names = make([]string, 0, numObjs)
+		dents = func(names ...string) []tcbDent {
+			ents := make([]tcbDent, 0, len(names))
+			for _, name := range names {
+				ents = append(ents, tcbDent{Name: name, Size: int64(len(name))})
+			}
+			sortDents(ents)
+			return ents
+		}
+	)
+	for i := range numObjs {
+		names = append(names, "obj-"+strconv.Itoa(i))
+	}
+	overlapping := dents(append([]string{"a", "obj-", "zzz"}, names[:numObjs/2]...)...)
+	overlapping[3].Size++ // differs
+
+	tests := []struct {
+		name  string
+		dst   []tcbDent
+		delta []string
+	}{
+		{"identical", dents(names...), nil},
+		{"disjoint", dents("a", "obj-", "obj-10", "zzz"), names},
+		{"overlapping", overlapping, append([]string{overlapping[3].Name}, names[numObjs/2:]...)},
+		{"empty", nil, names},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Diff: true}}
+				r    = newTestTCB(t, msg, nil)
+				tcoi = &tcbtCOI{}
+			)
+			savedCOI := gcoi
+			gcoi = tcoi
+			defer func() { gcoi = savedCOI }()
+
+			d := newTcbDiff(r, nil)
+			d.runSize = runSize
+			r.diff = d
+
+			// list (collect) in reverse order
+			for i := numObjs - 1; i >= 0; i-- {
+				lom := tcbtLOM(t, r, names[i])
+				tcbtPut(t, lom, names[i])
+				tassert.CheckFatal(t, d.collect(lom, nil))
+				core.FreeLOM(lom)
+			}
+			tassert.Fatalf(t, len(d.runs) == numObjs/runSize, "expected %d spilled runs, got %d", numObjs/runSize, len(d.runs))
+
+			src, err := d.src()
+			tassert.CheckFatal(t, err)
+			tassert.CheckFatal(t, d.compute(src, &tcbMemRun{ents: test.dst}))
+			closeRuns([]tcbDiter{src})
+
+			// delta is computed (and reported) prior to copying
+			var size int64
+			for _, name := range test.delta {
+				size += int64(len(name))
+			}
+			tassert.Errorf(t, d.cnt.Load() == int64(len(test.delta)) && d.size.Load() == size,
+				"expected delta (%d, %d), got (%d, %d)", len(test.delta), size, d.cnt.Load(), d.size.Load())
+
+			tassert.CheckFatal(t, d.copy())
+			sort.Strings(tcoi.copied)
+			expected := append([]string(nil), test.delta...)
+			sort.Strings(expected)
+			tassert.Errorf(t, strings.Join(tcoi.copied, ",") == strings.Join(expected, ","),
+				"expected %v to be copied, got %v", expected, tcoi.copied)
+
+			// workfiles removed
+			fqns := append(append([]string(nil), d.runs...), d.dfqn)
+			d.cleanup()
+			for _, fqn := range fqns {
+				_, err := os.Stat(fqn)
+				tassert.Errorf(t, os.IsNotExist(err), "expected %q to be removed, err: %v", fqn, err)
+			}
+		})
+	}
+
+	// checksums are compared only when both sides have them
+	var (
+		a = &tcbDent{Name: "x", Size: 1, Cksum: "aa"}
+		b = &tcbDent{Name: "x", Size: 1}
+		c = &tcbDent{Name: "x", Size: 1, Cksum: "bb"}
+	)
+	tassert.Errorf(t, a.eq(b) && b.eq(a), "expected %+v and %+v to be equal", a, b)
+	tassert.Errorf(t, !a.eq(c), "expected %+v and %+v to differ", a, c)
+
+	// invalid
+	for _, msg := range []*apc.TCBMsg{
+		{CopyBckMsg: apc.CopyBckMsg{Diff: true, Sync: true}},
+		{CopyBckMsg: apc.CopyBckMsg{Diff: true, OnCollision: apc.TCBCollisionSkip}},
+		{CopyBckMsg: apc.CopyBckMsg{Diff: true, Arch: &apc.ArchTCBMsg{}}},
+		{CopyBckMsg: apc.CopyBckMsg{Diff: true}, Ext: cos.StrKVs{"jpg": "png"}},
+	} {
+		tassert.Errorf(t, msg.Validate(false) != nil, "expected %+v to fail validation", msg.CopyBckMsg)
+	}
+}
+
+func TestTCBPreserveTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		copied   int64
+		skipped  int64
+	}{
+		{name: "remote-to-remote", from: apc.AWS, to: apc.AWS, copied: 2},
+		{name: "remote-to-ais", from: apc.AWS, to: apc.AIS, skipped: 2}, // not supported: skipped
+		{name: "ais-to-remote", from: apc.AIS, to: apc.AWS, copied: 1},  // no source tags (only received)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{PreserveTags: true, Prepend: "pre/"}}
+				r    = newTestTCBProvider(t, msg, nil, test.from, test.to)
+				tb   = &tcbtBackend{tags: make(map[string]cos.StrKVs, 4)}
+				tcoi = &tcbtCOI{}
+				tags = cos.StrKVs{"retention": "90d", "team": "ml"}
+			)
+			savedCOI := gcoi
+			gcoi = tcoi
+			defer func() { gcoi = savedCOI }()
+			core.T.(*tcbtTarget).Backends = map[string]core.Backend{apc.AWS: tb}
+
+			// 1. copying local (tagged) source object to local destination
+			lom := tcbtLOM(t, r, "obj")
+			tcbtPut(t, lom, "content")
+			tb.tags[lom.Uname()] = tags
+			tassert.CheckFatal(t, r.do(lom, nil))
+			core.FreeLOM(lom)
+
+			// 2. receiving (tagged) object from another target
+			hdr := &transport.ObjHdr{ObjName: "pre/obj2", Opaque: cos.MustMarshal(tags)}
+			hdr.Bck.Copy(r.p.args.BckTo.Bucket())
+			hdr.ObjAttrs.Size = 7
+			lom = core.AllocLOM(hdr.ObjName)
+			tassert.CheckFatal(t, r._recv(hdr, strings.NewReader("content"), lom))
+			core.FreeLOM(lom)
+
+			tassert.Errorf(t, r.tags.copied.Load() == test.copied && r.tags.skipped.Load() == test.skipped,
+				"expected tags (copied, skipped) = (%d, %d), got (%d, %d)",
+				test.copied, test.skipped, r.tags.copied.Load(), r.tags.skipped.Load())
+			names := []string{"pre/obj", "pre/obj2"}
+			if test.from == apc.AIS {
+				names = names[1:]
+			}
+			for _, name := range names[:test.copied] {
+				dst := core.AllocLOM(name)
+				tassert.CheckFatal(t, dst.InitBck(r.p.args.BckTo.Bucket()))
+				got := tb.tags[dst.Uname()]
+				tassert.Errorf(t, len(got) == len(tags) && got["retention"] == "90d" && got["team"] == "ml",
+					"%s: expected tags %v, got %v", name, tags, got)
+				core.FreeLOM(dst)
+			}
+		})
+	}
+}
+
+func TestTCBVerifyAfter(t *testing.T) {
+	const numObjs = 4
+	var (
+		msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Prepend: "cp/", VerifyAfter: true}}
+		r    = newTestTCB(t, msg, nil)
+		tcoi = &tcbtCOI{}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	v := newTcbVerify(r, nil, r.Config)
+	r.verify = v
+
+	// copied destination objects (and all sources but one)
+	for i := range numObjs {
+		name := "obj-" + strconv.Itoa(i)
+		if i != 2 {
+			lom := tcbtLOM(t, r, name)
+			tcbtPut(t, lom, name)
+			core.FreeLOM(lom)
+		}
+		dst := core.AllocLOM("cp/" + name)
+		tassert.CheckFatal(t, dst.InitBck(r.p.args.BckTo.Bucket()))
+		tcbtPut(t, dst, name)
+		v.add(dst.ObjName)
+		core.FreeLOM(dst)
+	}
+	// not copied by this xaction (not to be verified)
+	dst := core.AllocLOM("cp/other")
+	tassert.CheckFatal(t, dst.InitBck(r.p.args.BckTo.Bucket()))
+	tcbtPut(t, dst, "other")
+
+	// post-write corruption: same size, different content
+	corrupt := []string{"cp/obj-1", "cp/obj-2", dst.ObjName}
+	core.FreeLOM(dst)
+	for _, name := range corrupt {
+		dst := core.AllocLOM(name)
+		tassert.CheckFatal(t, dst.InitBck(r.p.args.BckTo.Bucket()))
+		b, err := os.ReadFile(dst.FQN)
+		tassert.CheckFatal(t, err)
+		b[0] ^= 0xff
+		tassert.CheckFatal(t, os.WriteFile(dst.FQN, b, cos.PermRWR))
+		core.FreeLOM(dst)
+	}
+
+	tassert.CheckFatal(t, v.run())
+
+	var (
+		verified   = v.verified.Load()
+		mismatched = v.mismatch.Load()
+		recopied   = v.recopied.Load()
+		names      = v.mismatched()
+	)
+	tassert.Errorf(t, verified == numObjs, "expected %d verified, got %d", numObjs, verified)
+	tassert.Errorf(t, mismatched == 2, "expected 2 mismatched, got %d", mismatched)
+	tassert.Errorf(t, strings.Join(names, ",") == "cp/obj-1,cp/obj-2", "expected mismatched names, got %v", names)
+
+	// re-copied when the source is there; otherwise, reported as error
+	tassert.Errorf(t, recopied == 1, "expected 1 re-copied, got %d", recopied)
+	tassert.Errorf(t, len(tcoi.copied) == 1 && tcoi.copied[0] == "cp/obj-1", "expected cp/obj-1 re-copied, got %v", tcoi.copied)
+	tassert.Errorf(t, r.ErrCnt() == 1, "expected 1 error, got %d (%v)", r.ErrCnt(), r.Err())
+
+	// invalid
+	for _, msg := range []*apc.TCBMsg{
+		{CopyBckMsg: apc.CopyBckMsg{VerifyAfter: true, DryRun: true}},
+		{CopyBckMsg: apc.CopyBckMsg{VerifyAfter: true, Arch: &apc.ArchTCBMsg{}}},
+	} {
+		tassert.Errorf(t, msg.Validate(false) != nil, "expected %+v to fail validation", msg.CopyBckMsg)
+	}
+}
+
+func TestTCBObjHook(t *testing.T) {
+	const numObjs = 10
+	var (
+		msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Prepend: "cp/"}}
+		r    = newTestTCB(t, msg, nil)
+		tcoi = &tcbtCOI{write: true}
+		mu   sync.Mutex
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	type call struct {
+		src   string
+		size  int64
+		cksum *cos.Cksum
+		cnt   int
+	}
+	calls := make(map[string]*call, numObjs+1)
+	r.SetObjHook(func(srcName, dstName string, size int64, cksum *cos.Cksum) error {
+		mu.Lock()
+		defer mu.Unlock()
+		c, ok := calls[dstName]
+		if !ok {
+			c = &call{src: srcName, size: size, cksum: cksum}
+			calls[dstName] = c
+		}
+		c.cnt++
+		if dstName == "cp/obj-0" {
+			return errors.New("non-fatal")
+		}
+		return nil
+	}, TCBHookOpts{Workers: 2})
+
+	cksums := make(map[string]*cos.Cksum, numObjs)
+	for i := range numObjs {
+		name := "obj-" + strconv.Itoa(i)
+		lom := tcbtLOM(t, r, name)
+		tcbtPut(t, lom, name)
+		cksums["cp/"+name] = lom.Checksum().Clone()
+		tassert.CheckFatal(t, r.do(lom, nil))
+		core.FreeLOM(lom)
+	}
+
+	// received from another target
+	hdr := &transport.ObjHdr{ObjName: "cp/remote"}
+	hdr.Bck.Copy(r.p.args.BckTo.Bucket())
+	hdr.ObjAttrs.Size = 3
+	lom := core.AllocLOM(hdr.ObjName)
+	tassert.CheckFatal(t, r._recv(hdr, bytes.NewReader([]byte("abc")), lom))
+	core.FreeLOM(lom)
+
+	r.hook.stop()
+
+	tassert.Fatalf(t, len(calls) == numObjs+1, "expected %d hook calls, got %d", numObjs+1, len(calls))
+	for dstName, c := range calls {
+		tassert.Errorf(t, c.cnt == 1, "%s: expected the hook to fire once, got %d", dstName, c.cnt)
+		if dstName == hdr.ObjName {
+			tassert.Errorf(t, c.src == "remote" && c.size == 3, "%s: unexpected (%q, %d)", dstName, c.src, c.size)
+			continue
+		}
+		tassert.Errorf(t, "cp/"+c.src == dstName && c.size == int64(len(c.src)) && c.cksum.Equal(cksums[dstName]),
+			"%s: unexpected (%q, %d, %s)", dstName, c.src, c.size, c.cksum)
+	}
+	tassert.Errorf(t, r.hook.errs.Load() == 1 && !r.IsAborted(), "expected 1 (non-fatal) hook error, got %d (aborted %t)",
+		r.hook.errs.Load(), r.IsAborted())
+
+	// fatal
+	r = newTestTCB(t, msg, nil)
+	r.SetObjHook(func(string, string, int64, *cos.Cksum) error { return errors.New("fatal") }, TCBHookOpts{Fatal: true})
+	lom = tcbtLOM(t, r, "obj-0")
+	tcbtPut(t, lom, "obj-0")
+	tassert.CheckFatal(t, r.do(lom, nil))
+	core.FreeLOM(lom)
+	r.hook.stop()
+	tassert.Errorf(t, r.IsAborted(), "expected hook error to abort %s", r)
+
+	// webhook
+	var events []apc.TCBWebhookEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var ev apc.TCBWebhookEvent
+		if err := cos.JSON.NewDecoder(req.Body).Decode(&ev); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		events = append(events, ev)
+		mu.Unlock()
+		if ev.Name == "cp/obj-1" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	msg = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Prepend: "cp/", Webhook: srv.URL}}
+	tassert.CheckFatal(t, msg.Validate(false))
+	r = newTestTCB(t, msg, nil)
+	r.SetObjHook(tcbWebhook(r, msg.Webhook), TCBHookOpts{})
+	for i := range 2 {
+		name := "obj-" + strconv.Itoa(i)
+		lom := tcbtLOM(t, r, name)
+		tcbtPut(t, lom, name)
+		tassert.CheckFatal(t, r.do(lom, nil))
+		core.FreeLOM(lom)
+	}
+	r.hook.stop()
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Name < events[j].Name })
+	tassert.Fatalf(t, len(events) == 2, "expected 2 webhook events, got %d", len(events))
+	for i, ev := range events {
+		name := "obj-" + strconv.Itoa(i)
+		tassert.Errorf(t, ev.XactID == r.ID() && ev.SrcName == name && ev.Name == "cp/"+name &&
+			ev.Size == int64(len(name)) && ev.CksumValue != "", "unexpected webhook event %+v", ev)
+	}
+	tassert.Errorf(t, r.hook.errs.Load() == 1 && !r.IsAborted(), "expected 1 (non-fatal) webhook error, got %d (aborted %t)",
+		r.hook.errs.Load(), r.IsAborted())
+
+	// invalid
+	for _, url := range []string{"ftp://host/path", "localhost:8080", "http://"} {
+		msg := &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Webhook: url}}
+		tassert.Errorf(t, msg.Validate(false) != nil, "expected webhook %q to fail validation", url)
+	}
+}
+
+func TestTCBDoneSending(t *testing.T) {
+	r := newTestTCB(t, &apc.TCBMsg{}, nil)
+	r.refc.Store(2) // two other targets
+
+	done := func(sid string) {
+		hdr := &transport.ObjHdr{SID: sid, Opcode: OpcTxnDone}
+		tassert.CheckFatal(t, r.recv(hdr, nil, nil))
+	}
+
+	// duplicate (e.g., retransmitted upon reconnect) is ignored
+	done("t1")
+	done("t1")
+	tassert.Errorf(t, r.refc.Load() == 1, "expected refc 1, got %d", r.refc.Load())
+	tassert.Errorf(t, r.qcb(0) == core.QuiActive, "expected quiescence to wait for the other sender")
+
+	done("t2")
+	done("t2")
+	tassert.Errorf(t, r.refc.Load() == 0, "expected refc 0, got %d", r.refc.Load())
+	tassert.Errorf(t, r.qcb(0) == core.QuiDone, "expected quiescence to be done")
+}
+
+func TestTCBETA(t *testing.T) {
+	const (
+		fast   = 100 * cos.MiB // bytes per second
+		slow   = 10 * cos.MiB  // ditto, e.g., upon hitting a run of large objects
+		window = 10 * time.Second
+		total  = 100 * cos.GiB
+	)
+	var (
+		e     tcbETA
+		now   int64
+		bytes int64
+		sec   = int64(time.Second)
+	)
+	e.init(window, now)
+	e.total.Store(total)
+
+	// unknown prior to any progress
+	avg, adaptive := e.estimate(now, bytes)
+	tassert.Errorf(t, avg == 0 && adaptive == 0, "expected unknown ETA, got (%v, %v)", avg, adaptive)
+
+	// steady: both estimates agree
+	for range 60 {
+		now += sec
+		bytes += fast
+		e.sample(now, bytes)
+	}
+	avg, adaptive = e.estimate(now, bytes)
+	expected := time.Duration((total - bytes) / fast * sec)
+	tassert.Errorf(t, avg == expected && adaptive == expected, "expected %v, got (%v, %v)", expected, avg, adaptive)
+
+	// slowdown: adaptive responds faster
+	for range int(window / time.Second) {
+		now += sec
+		bytes += slow
+		e.sample(now, bytes)
+	}
+	avg, adaptive = e.estimate(now, bytes)
+	expected = time.Duration((total - bytes) / slow * sec)
+	tassert.Errorf(t, adaptive == expected, "adaptive: expected %v, got %v", expected, adaptive)
+	tassert.Errorf(t, avg < expected/2, "average: expected to lag behind (%v vs %v)", avg, expected)
+
+	// samples are bounded by the window
+	tassert.Errorf(t, len(e.samples) <= int(window/time.Second)+1, "expected bounded samples, got %d", len(e.samples))
+
+	// speedup
+	for range int(window / time.Second) {
+		now += sec
+		bytes += fast
+		e.sample(now, bytes)
+	}
+	avg, adaptive = e.estimate(now, bytes)
+	expected = time.Duration((total - bytes) / fast * sec)
+	tassert.Errorf(t, adaptive == expected, "adaptive: expected %v, got %v", expected, adaptive)
+	tassert.Errorf(t, avg > expected, "average: expected to lag behind (%v vs %v)", avg, expected)
+}
+
+func TestTCBSnapshot(t *testing.T) {
+	var (
+		asOf = time.Now().Add(-time.Minute)
+		msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Snapshot: true, AsOf: asOf.UnixNano()}}
+		r    = newTestTCB(t, msg, nil)
+		tcoi = &tcbtCOI{}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	r.pit = newTcbPIT(msg.AsOf)
+
+	// written before the instant ("old-*") and after ("new", "modified")
+	for name, mtime := range map[string]time.Time{
+		"old-1":    asOf.Add(-time.Hour),
+		"old-2":    asOf,
+		"new":      asOf.Add(time.Second),
+		"modified": time.Now(),
+	} {
+		lom := tcbtLOM(t, r, name)
+		tcbtPut(t, lom, name)
+		tassert.CheckFatal(t, os.Chtimes(lom.FQN, mtime, mtime))
+		core.FreeLOM(lom)
+	}
+	for _, name := range []string{"old-1", "old-2", "new", "modified"} {
+		lom := tcbtLOM(t, r, name)
+		tassert.CheckFatal(t, lom.Load(false, false))
+		tassert.CheckFatal(t, r.do(lom, nil))
+		core.FreeLOM(lom)
+	}
+
+	sort.Strings(tcoi.copied)
+	tassert.Errorf(t, strings.Join(tcoi.copied, ",") == "old-1,old-2", "expected (only) old-* to be copied, got %v", tcoi.copied)
+	tassert.Errorf(t, r.pit.skipped.Load() == 2, "expected 2 skipped, got %d", r.pit.skipped.Load())
+
+	// invalid
+	for _, msg := range []*apc.TCBMsg{
+		{CopyBckMsg: apc.CopyBckMsg{Snapshot: true, LatestVer: true}},
+		{CopyBckMsg: apc.CopyBckMsg{Snapshot: true, Sync: true}},
+		{CopyBckMsg: apc.CopyBckMsg{Snapshot: true, AsOf: -1}},
+		{CopyBckMsg: apc.CopyBckMsg{AsOf: asOf.UnixNano()}},
+	} {
+		tassert.Errorf(t, msg.Validate(false) != nil, "expected %+v to fail validation", msg.CopyBckMsg)
+	}
+}
+
+// sequence marker: source => (copier) => transport header => receiving target's PutObject
+func TestTCBPreserveOrder(t *testing.T) {
+	var (
+		msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{PreserveOrder: true}}
+		r    = newTestTCB(t, msg, nil)
+		tcoi = &tcbtCOI{}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	// source with its own marker ("a"), and without ("b")
+	lom := tcbtLOM(t, r, "a")
+	lom.SetCustomKey(cmn.SeqObjMD, "42")
+	tcbtPut(t, lom, "content")
+	tassert.CheckFatal(t, r.do(lom, nil))
+	core.FreeLOM(lom)
+
+	lom = tcbtLOM(t, r, "b")
+	tcbtPut(t, lom, "content")
+	mtime := time.Now().Add(-time.Hour)
+	tassert.CheckFatal(t, os.Chtimes(lom.FQN, mtime, mtime))
+	tassert.CheckFatal(t, r.do(lom, nil))
+	core.FreeLOM(lom)
+
+	// not in cluster and no marker: copied without
+	lom = tcbtLOM(t, r, "c")
+	tassert.CheckFatal(t, r.do(lom, nil))
+	core.FreeLOM(lom)
+
+	tassert.Fatalf(t, len(tcoi.copied) == 3, "expected 3 copies, got %v", tcoi.copied)
+	tassert.Errorf(t, tcoi.custom["a"][cmn.SeqObjMD] == "42", "a: expected source marker, got %v", tcoi.custom["a"])
+	seq := fmtSeq(mtime.UnixNano())
+	tassert.Fatalf(t, tcoi.custom["b"][cmn.SeqObjMD] == seq, "b: expected %q, got %v", seq, tcoi.custom["b"])
+	tassert.Errorf(t, tcoi.custom["c"] == nil, "c: expected no marker, got %v", tcoi.custom["c"])
+	tassert.Errorf(t, r.seq.carried.Load() == 2 && r.seq.missing.Load() == 1, "expected (carried, missing) = (2, 1), got (%d, %d)",
+		r.seq.carried.Load(), r.seq.missing.Load())
+
+	// the receiving side restores the marker sent via transport header (see coi._dm)
+	hdr := &transport.ObjHdr{ObjName: "b"}
+	hdr.Bck.Copy(r.p.args.BckTo.Bucket())
+	hdr.ObjAttrs.Size = 7
+	for k, v := range tcoi.custom["b"] {
+		hdr.ObjAttrs.SetCustomKey(k, v)
+	}
+	lom = core.AllocLOM(hdr.ObjName)
+	tassert.CheckFatal(t, r._recv(hdr, strings.NewReader("content"), lom))
+	core.FreeLOM(lom)
+	tgt := core.T.(*tcbtTarget)
+	tassert.Fatalf(t, len(tgt.custom) == 1, "expected 1 put, got %d", len(tgt.custom))
+	tassert.Errorf(t, tgt.custom[0][cmn.SeqObjMD] == seq, "expected restored marker %q, got %v", seq, tgt.custom[0])
+
+	// lexicographic order == creation order
+	tassert.Errorf(t, fmtSeq(999) < fmtSeq(1000), "expected zero-padded markers to sort")
+
+	// default off
+	r = newTestTCB(t, &apc.TCBMsg{}, nil)
+	tcoi.custom = nil
+	lom = tcbtLOM(t, r, "a")
+	tcbtPut(t, lom, "content")
+	tassert.CheckFatal(t, r.do(lom, nil))
+	core.FreeLOM(lom)
+	tassert.Errorf(t, tcoi.custom == nil, "expected no markers by default, got %v", tcoi.custom)
+
+	// not supported with destination archives
+	msg.Arch = &apc.ArchTCBMsg{Mime: ".tar"}
+	tassert.Errorf(t, msg.Validate(false) != nil, "expected preserve-order with arch to fail validation")
+}
+
+func TestTCBSkipObjs(t *testing.T) {
+	var (
+		msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Prepend: "pre/"}}
+		r    = newTestTCB(t, msg, nil)
+		tcoi = &tcbtCOI{write: true}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	// "c" gets skip-listed while being copied (in-flight)
+	tcoi.hook = func(lom *core.LOM) {
+		if lom.ObjName == "c" {
+			r.SkipObjs("c")
+		}
+	}
+	r.SkipObjs("b", "unknown")
+
+	for _, name := range []string{"a", "b", "c", "d"} {
+		lom := tcbtLOM(t, r, name)
+		tcbtPut(t, lom, "content")
+		tassert.CheckFatal(t, r.do(lom, nil))
+		core.FreeLOM(lom)
+	}
+	tassert.Errorf(t, strings.Join(tcoi.copied, ",") == "pre/a,pre/c,pre/d", "expected (a, c, d) copy attempts, got %v", tcoi.copied)
+	for name, exists := range map[string]bool{"pre/a": true, "pre/b": false, "pre/c": false, "pre/d": true} {
+		dst := core.AllocLOM(name)
+		tassert.CheckFatal(t, dst.InitBck(r.p.args.BckTo.Bucket()))
+		err := dst.Load(false, false)
+		tassert.Errorf(t, (err == nil) == exists, "%s: expected exists=%t, got err %v", name, exists, err)
+		core.FreeLOM(dst)
+	}
+
+	// received from another target that didn't know (yet)
+	r.SkipObjs("e")
+	hdr := &transport.ObjHdr{ObjName: "pre/e"}
+	hdr.Bck.Copy(r.p.args.BckTo.Bucket())
+	hdr.ObjAttrs.Size = 7
+	lom := core.AllocLOM(hdr.ObjName)
+	tassert.CheckFatal(t, r._recv(hdr, strings.NewReader("content"), lom))
+	core.FreeLOM(lom)
+	tassert.Errorf(t, len(core.T.(*tcbtTarget).puts) == 0, "expected received skip-listed object to be dropped")
+
+	// recorded once
+	lom = tcbtLOM(t, r, "b")
+	tassert.CheckFatal(t, r.do(lom, nil))
+	core.FreeLOM(lom)
+	names := r.skip.list()
+	tassert.Errorf(t, r.skip.skipped.Load() == 3 && strings.Join(names, ",") == "b,c,e",
+		"expected operator-skipped (b, c, e), got %d %v", r.skip.skipped.Load(), names)
+}
+
+func TestTCBThroughput(t *testing.T) {
+	const (
+		num   = 4
+		size  = 1024
+		delay = 10 * time.Millisecond
+	)
+	var (
+		r    = newTestTCB(t, &apc.TCBMsg{}, nil)
+		tcoi = &tcbtCOI{write: true, slow: delay}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	read, write := r.tput.gauges()
+	tassert.Errorf(t, read == 0 && write == 0, "expected unknown (zero) throughput prior to copying, got (%d, %d)", read, write)
+
+	for i := range num {
+		lom := tcbtLOM(t, r, "obj"+strconv.Itoa(i))
+		tcbtPut(t, lom, strings.Repeat("x", size))
+		tassert.CheckFatal(t, r.do(lom, nil))
+		core.FreeLOM(lom)
+	}
+	tassert.Fatalf(t, r.tput.rstats.Size.Load() == num*size, "expected %d bytes read, got %d", num*size, r.tput.rstats.Size.Load())
+
+	// each object: at least two (delayed) reads - the content and EOF
+	read, write = r.tput.gauges()
+	maxRead := int64(float64(num*size) / (2 * num * delay).Seconds())
+	tassert.Errorf(t, read > 0 && read <= maxRead, "expected read throughput in (0, %d], got %d", maxRead, read)
+	tassert.Errorf(t, write > read, "expected slow reading to be the bottleneck (read %d, write %d)", read, write)
+}
+
+// abort that arrives prior to, or right after, opening the data mover
+func TestTCBAbortStartup(t *testing.T) {
+	transport.Init(mock.NewStatsTracker())
+	for _, opened := range []bool{false, true} {
+		r := newTestTCB(t, &apc.TCBMsg{}, nil)
+		r.p.xctn = r
+		tassert.CheckFatal(t, r.p.newDM(r.Config, r.ID(), 0))
+		trname := "tcb-" + r.ID()
+
+		if opened {
+			tassert.Fatalf(t, r.dmOpen(), "expected to open")
+		}
+		r.TxnAbort(errors.New("test abort"))
+		tassert.Errorf(t, r.IsAborted() || r.Finished(), "expected aborted or finished")
+
+		if opened {
+			r.dmClose(nil) // Run's teardown: no-op
+		} else {
+			var wg sync.WaitGroup
+			wg.Add(1)
+			r.wg.Add(1)
+			r.Run(&wg) // returns without opening
+			wg.Wait()
+			r.WaitRunning()
+			tassert.Errorf(t, !r.dmst.opened, "expected data mover not to be opened after abort")
+		}
+		tassert.Errorf(t, r.dmst.closed, "expected data mover to be closed")
+		tassert.Errorf(t, strings.HasPrefix(r.dm.String(), "dm-pre-or-post-") || strings.HasPrefix(r.dm.String(), "dm-nil-pre-or-post-"),
+			"expected data mover to be neither open nor registered, got %s", r.dm.String())
+		tassert.Errorf(t, transport.Unhandle(trname) != nil, "opened=%t: leaked recv handler %q", opened, trname)
+	}
+}
+
+// restores objects from (mock) EC slices
+type tcbtECR struct {
+	*tcbtCOI
+	slices map[string]string // object name => content to restore
+}
+
+func (e *tcbtECR) ECRecover(lom *core.LOM) error {
+	content, ok := e.slices[lom.ObjName]
+	if !ok {
+		return errors.New("not enough slices to restore " + lom.Cname())
+	}
+	if err := cos.CreateDir(filepath.Dir(lom.FQN)); err != nil {
+		return err
+	}
+	if err := os.WriteFile(lom.FQN, []byte(content), cos.PermRWR); err != nil {
+		return err
+	}
+	lom.SetSize(int64(len(content)))
+	lom.SetAtimeUnix(time.Now().UnixNano())
+	return lom.Persist()
+}
+
+func TestTCBECRestore(t *testing.T) {
+	var (
+		r    = newTestTCB(t, &apc.TCBMsg{}, nil)
+		tcoi = &tcbtCOI{}
+		ecr  = &tcbtECR{tcbtCOI: tcoi, slices: map[string]string{"sliced": "restored-content"}}
+		read = make(map[string]string, 2) // source content as seen by the copier
+	)
+	savedCOI := gcoi
+	gcoi = ecr
+	defer func() { gcoi = savedCOI }()
+	fs.CSM.Reg(fs.ECMetaType, &fs.ECMetaContentResolver{}, true)
+	tcoi.hook = func(lom *core.LOM) {
+		b, err := os.ReadFile(lom.FQN)
+		tassert.CheckFatal(t, err)
+		read[lom.ObjName] = string(b)
+	}
+
+	// "full": replica and metafile; "sliced": metafile only (restorable); "lost": ditto, not restorable
+	for _, name := range []string{"full", "sliced", "lost"} {
+		lom := tcbtLOM(t, r, name)
+		if name == "full" {
+			tcbtPut(t, lom, "full-content")
+		}
+		ct := core.NewCTFromLOM(lom, fs.ECMetaType)
+		tassert.CheckFatal(t, cos.CreateDir(filepath.Dir(ct.FQN())))
+		tassert.CheckFatal(t, os.WriteFile(ct.FQN(), []byte("metadata"), cos.PermRWR))
+		core.FreeLOM(lom)
+	}
+
+	r.p.args.BckFrom.Props.EC.Enabled = true
+	r.ec = newTcbEC(r, nil, r.Config)
+	tassert.Fatalf(t, r.ec != nil, "expected EC restoration to be enabled")
+	tassert.CheckFatal(t, r.ec.run())
+
+	tassert.Errorf(t, len(tcoi.copied) == 1 && tcoi.copied[0] == "sliced", "expected only the sliced object to be copied, got %v", tcoi.copied)
+	tassert.Errorf(t, read["sliced"] == "restored-content", "expected restored content, got %q", read["sliced"])
+	tassert.Errorf(t, r.ec.recovered.Load() == 1 && r.ec.failed.Load() == 1, "expected (recovered, failed) = (1, 1), got (%d, %d)",
+		r.ec.recovered.Load(), r.ec.failed.Load())
+	tassert.Errorf(t, r.ec.ns.Load() > 0, "expected restoration time to be accounted for")
+	tassert.Errorf(t, r.ErrCnt() == 1, "expected 1 error, got %d (%v)", r.ErrCnt(), r.Err())
+
+	// not enabled when the target cannot restore
+	gcoi = tcoi
+	tassert.Errorf(t, newTcbEC(r, nil, r.Config) == nil, "expected no EC restoration")
+}
+
+func TestTCBCksumRecompute(t *testing.T) {
+	r := newTestTCB(t, &apc.TCBMsg{}, nil)
+	r.cksum.dstType = cos.ChecksumSHA256 // destination bucket with a different checksum type
+
+	recv := func(name, ty string) {
+		hdr := &transport.ObjHdr{ObjName: name}
+		hdr.Bck.Copy(r.p.args.BckTo.Bucket())
+		hdr.ObjAttrs.Size = 7
+		hdr.ObjAttrs.Cksum = cos.NewCksum(ty, "0123456789abcdef")
+		lom := core.AllocLOM(hdr.ObjName)
+		tassert.CheckFatal(t, r._recv(hdr, strings.NewReader("content"), lom))
+		core.FreeLOM(lom)
+	}
+	recv("a", cos.ChecksumXXHash)
+	recv("b", cos.ChecksumSHA256)
+
+	tgt := core.T.(*tcbtTarget)
+	tassert.Fatalf(t, len(tgt.cksums) == 2, "expected 2 puts, got %d", len(tgt.cksums))
+	tassert.Errorf(t, tgt.cksums[0] == nil, "a: expected checksum to be computed by the destination, got %s", tgt.cksums[0])
+	tassert.Errorf(t, tgt.cksums[1] != nil && tgt.cksums[1].Ty() == cos.ChecksumSHA256,
+		"b: expected source checksum to be used as is, got %s", tgt.cksums[1])
+	tassert.Errorf(t, r.cksum.recomputed.Load() == 1, "expected 1 recomputed, got %d", r.cksum.recomputed.Load())
+}
+
+func TestTCBSSE(t *testing.T) {
+	tests := []struct {
+		backend core.Backend
+		name    string
+		to      string
+		applied int64
+		skipped int64
+	}{
+		{name: "sse-capable", to: apc.AWS, backend: &tcbtSSEBackend{}, applied: 2},
+		{name: "not-supported", to: apc.AWS, backend: &tcbtBackend{}, skipped: 2},
+		{name: "ais-destination", to: apc.AIS, skipped: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				sse  = &apc.SSEMsg{KMSKey: "alias/dst"}
+				msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{SSE: sse}}
+				r    = newTestTCBProvider(t, msg, nil, apc.AIS, test.to)
+				tcoi = &tcbtCOI{write: true}
+			)
+			savedCOI := gcoi
+			gcoi = tcoi
+			defer func() { gcoi = savedCOI }()
+			tgt := core.T.(*tcbtTarget)
+			if test.backend != nil {
+				tgt.Backends = map[string]core.Backend{test.to: test.backend}
+			}
+			r.sse.init(r, msg.SSE)
+			tassert.Fatalf(t, (r.sse.msg != nil) == (test.applied > 0), "unexpected effective SSE %+v", r.sse.msg)
+
+			// 1. local destination
+			lom := tcbtLOM(t, r, "obj")
+			tcbtPut(t, lom, "content")
+			tassert.CheckFatal(t, r.do(lom, nil))
+			core.FreeLOM(lom)
+
+			// 2. received from another target
+			hdr := &transport.ObjHdr{ObjName: "obj2"}
+			hdr.Bck.Copy(r.p.args.BckTo.Bucket())
+			hdr.ObjAttrs.Size = 7
+			lom = core.AllocLOM(hdr.ObjName)
+			tassert.CheckFatal(t, r._recv(hdr, strings.NewReader("content"), lom))
+			core.FreeLOM(lom)
+
+			tassert.Fatalf(t, len(tcoi.sse) == 1 && len(tgt.sse) == 1, "expected one copy and one put, got (%d, %d)",
+				len(tcoi.sse), len(tgt.sse))
+			if test.applied > 0 {
+				tassert.Errorf(t, tcoi.sse[0] == sse && tgt.sse[0], "expected SSE to be passed through, got (%v, %t)",
+					tcoi.sse[0], tgt.sse[0])
+				tassert.Errorf(t, sse.Algorithm() == apc.SSEAlgoKMS, "expected %q, got %q", apc.SSEAlgoKMS, sse.Algorithm())
+			} else {
+				tassert.Errorf(t, tcoi.sse[0] == nil && !tgt.sse[0], "expected no SSE, got (%v, %t)", tcoi.sse[0], tgt.sse[0])
+			}
+			tassert.Errorf(t, r.sse.applied.Load() == test.applied && r.sse.skipped.Load() == test.skipped,
+				"expected SSE (applied, skipped) = (%d, %d), got (%d, %d)",
+				test.applied, test.skipped, r.sse.applied.Load(), r.sse.skipped.Load())
+		})
+	}
+
+	// validation
+	msg := &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{SSE: &apc.SSEMsg{Algo: apc.SSEAlgoAES256, KMSKey: "k"}}}
+	tassert.Errorf(t, msg.Validate(false) != nil, "expected KMS key with %q to fail validation", apc.SSEAlgoAES256)
+	msg.SSE = &apc.SSEMsg{}
+	msg.LocalOnly = true
+	tassert.Errorf(t, msg.Validate(false) != nil, "expected SSE with local-only to fail validation")
+}
+
+func TestTCBSkipPred(t *testing.T) {
+	var (
+		r    = newTestTCB(t, &apc.TCBMsg{}, nil)
+		tcoi = &tcbtCOI{}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	// skip by (computed) shard number, and by extension without giving a reason
+	r.AddSkipPred(func(lom *core.LOM) (bool, string) {
+		return crc32.ChecksumIEEE([]byte(lom.ObjName))%3 != 0, "shard-0"
+	})
+	r.AddSkipPred(func(lom *core.LOM) (bool, string) {
+		return !strings.HasSuffix(lom.ObjName, ".tmp"), ""
+	})
+
+	var shard0, tmp int64
+	for i := range 30 {
+		name := "obj-" + strconv.Itoa(i)
+		if i%5 == 0 {
+			name += ".tmp"
+		}
+		switch {
+		case crc32.ChecksumIEEE([]byte(name))%3 == 0:
+			shard0++
+		case strings.HasSuffix(name, ".tmp"):
+			tmp++
+		}
+		lom := tcbtLOM(t, r, name)
+		tassert.CheckFatal(t, r.do(lom, nil))
+		core.FreeLOM(lom)
+	}
+	tassert.Fatalf(t, shard0 > 0 && tmp > 0, "bad test setup: (%d, %d)", shard0, tmp)
+
+	tassert.Errorf(t, int64(len(tcoi.copied)) == 30-shard0-tmp, "expected %d copies, got %d", 30-shard0-tmp, len(tcoi.copied))
+	for _, name := range tcoi.copied {
+		tassert.Errorf(t, crc32.ChecksumIEEE([]byte(name))%3 != 0 && !strings.HasSuffix(name, ".tmp"), "%q: expected to be skipped", name)
+	}
+	reasons := r.preds.breakdown()
+	tassert.Errorf(t, len(reasons) == 2 && reasons["shard-0"] == shard0 && reasons[tcbDfltSkipReason] == tmp,
+		"expected skip breakdown {shard-0: %d, %s: %d}, got %v", shard0, tcbDfltSkipReason, tmp, reasons)
+}
+
+func TestTCBDeadline(t *testing.T) {
+	var (
+		msg     = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Deadline: time.Now().Add(50 * time.Millisecond).UnixNano()}}
+		r       = newTestTCB(t, msg, nil)
+		tcoi    = &tcbtCOI{write: true}
+		stopped = make(chan struct{})
+		names   = []string{"a", "b", "c", "d"}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	// "b" is in-flight when the deadline expires
+	tcoi.hook = func(lom *core.LOM) {
+		if lom.ObjName == "b" {
+			<-stopped
+		}
+	}
+	r.dl.start(r, msg.Deadline, func() { close(stopped) })
+	defer r.dl.stop()
+
+	for _, name := range names {
+		lom := tcbtLOM(t, r, name)
+		tcbtPut(t, lom, "content-"+name)
+		tassert.CheckFatal(t, r.do(lom, nil))
+		core.FreeLOM(lom)
+	}
+	tassert.Errorf(t, r.dl.reached.Load(), "expected deadline reached")
+	tassert.Errorf(t, strings.Join(tcoi.copied, ",") == "a,b", "expected (a, b) to be copied, got %v", tcoi.copied)
+
+	// resume
+	tcoi.copied, tcoi.hook = nil, nil
+	args := *r.p.args
+	args.Msg = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{IfNoneMatch: true}}
+	r2 := &XactTCB{}
+	r2.InitBase(cos.GenUUID(), apc.ActCopyBck, "" /*ctlmsg*/, args.BckTo)
+	r2.p = &tcbFactory{args: &args, owt: cmn.OwtCopy}
+	r2.Config = r.Config
+	for _, name := range names {
+		lom := tcbtLOM(t, r2, name)
+		tassert.CheckFatal(t, lom.Load(false, false))
+		tassert.CheckFatal(t, r2.do(lom, nil))
+		core.FreeLOM(lom)
+	}
+	tassert.Errorf(t, strings.Join(tcoi.copied, ",") == "c,d", "expected (only) c and d to be copied upon resuming, got %v", tcoi.copied)
+	tassert.Errorf(t, r2.cond.skipped.Load() == 2, "expected 2 already copied, got %d", r2.cond.skipped.Load())
+}
+
+func TestTCBProgress(t *testing.T) {
+	const (
+		numObjs = 8
+		size    = 1000
+	)
+	r := newTestTCB(t, &apc.TCBMsg{}, nil)
+	r.eta.total.Store(numObjs * size)
+
+	var (
+		updates = r.SubscribeProgress(tcbProgressMinIval)
+		slow    = r.SubscribeProgress(tcbProgressMinIval) // not reading until finished
+		copied  = make(chan struct{})
+	)
+	go func() {
+		for range numObjs {
+			time.Sleep(tcbProgressMinIval / 2)
+			r.ObjsAdd(1, size)
+		}
+		close(copied)
+	}()
+
+	var (
+		periodic []TCBProgress
+		final    *TCBProgress
+	)
+	go func() {
+		<-copied
+		r.Finish()
+		r.prog.fin(r)
+	}()
+	for p := range updates {
+		if p.Final {
+			final = &p
+			continue
+		}
+		periodic = append(periodic, p)
+	}
+	tassert.Fatalf(t, len(periodic) >= 2, "expected periodic updates, got %v", periodic)
+	for i := 1; i < len(periodic); i++ {
+		tassert.Errorf(t, periodic[i].Bytes >= periodic[i-1].Bytes, "expected non-decreasing progress, got %v", periodic)
+	}
+	last := periodic[len(periodic)-1]
+	tassert.Errorf(t, last.Percent > 0 && last.Percent <= 100, "expected (0, 100] percent, got %f", last.Percent)
+	tassert.Fatalf(t, final != nil, "expected terminal update")
+	tassert.Errorf(t, final.Objs == numObjs && final.Bytes == numObjs*size && final.Percent == 100 && final.Err == nil,
+		"unexpected terminal update %+v", *final)
+
+	// slow subscriber: pending updates coalesced - the terminal one is delivered regardless
+	var n int
+	for p := range slow {
+		n++
+		tassert.Errorf(t, p.Final && p.Objs == numObjs, "expected (only) terminal update, got %+v", p)
+	}
+	tassert.Errorf(t, n == 1, "expected a single (coalesced) update, got %d", n)
+
+	// subscribing after the fact
+	p, ok := <-r.SubscribeProgress(time.Second)
+	tassert.Errorf(t, ok && p.Final, "expected terminal update, got %+v", p)
+}
+
+func TestTCBRateLimit(t *testing.T) {
+	const (
+		bps     = 100 * cos.KiB
+		size    = 10 * cos.KiB
+		numObjs = 13 // 1.3s worth of bytes, including the (1s) burst
+	)
+	var (
+		msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{RateLimit: bps}}
+		r    = newTestTCB(t, msg, nil)
+		tcoi = &tcbtCOI{}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+	r.SetRateLimit(msg.RateLimit) // (newTestTCB bypasses newTCB)
+
+	content := strings.Repeat("x", size)
+	copyAll := func(prefix string) time.Duration {
+		started := time.Now()
+		for i := range numObjs {
+			lom := tcbtLOM(t, r, prefix+strconv.Itoa(i))
+			tcbtPut(t, lom, content)
+			tassert.CheckFatal(t, r.do(lom, nil))
+			core.FreeLOM(lom)
+		}
+		return time.Since(started)
+	}
+
+	elapsed := copyAll("a/")
+	slept := time.Duration(r.throttle.slept.Load())
+	tassert.Errorf(t, elapsed >= 250*time.Millisecond && slept >= 250*time.Millisecond,
+		"expected copying to be throttled (~300ms), got %v (slept %v)", elapsed, slept)
+
+	// runtime change: unlimited
+	r.SetRateLimit(0)
+	copyAll("b/")
+	tassert.Errorf(t, time.Duration(r.throttle.slept.Load()) == slept, "expected no throttling when unlimited")
+	tassert.Errorf(t, len(tcoi.copied) == 2*numObjs, "expected %d copies, got %d", 2*numObjs, len(tcoi.copied))
+}
+
+func TestTCBCheckpoint(t *testing.T) {
+	var (
+		msg   = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Checkpoint: true}}
+		r     = newTestTCB(t, msg, nil)
+		tcoi  = &tcbtCOI{}
+		names = []string{"b", "a/c", "a-b", "a/b"}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	for _, name := range names {
+		lom := tcbtLOM(t, r, name)
+		tcbtPut(t, lom, "content")
+		core.FreeLOM(lom)
+	}
+	walk := func(r *XactTCB) {
+		opts := &mpather.JgroupOpts{CTs: []string{fs.ObjectType}, VisitObj: r.ckpt.visit, DoLoad: mpather.Load, Sorted: true}
+		opts.Bck.Copy(r.p.args.BckFrom.Bucket())
+		jg := mpather.NewJoggerGroup(opts, r.Config, nil)
+		jg.Run()
+		<-jg.ListenFinished()
+		tassert.CheckFatal(t, jg.Stop())
+	}
+
+	// walking order
+	r.ckpt = newTcbCkpt(r, r.ID())
+	walk(r)
+	tassert.Fatalf(t, strings.Join(tcoi.copied, ",") == "a/b,a/c,a-b,b", "unexpected walking order %v", tcoi.copied)
+	sorted := slices.Clone(names)
+	slices.SortFunc(sorted, tcbWalkCmp)
+	tassert.Errorf(t, slices.Equal(sorted, tcoi.copied), "expected %v to match walking order %v", sorted, tcoi.copied)
+	r.ckpt.fin(true)
+
+	// interrupted after "a/c" (upon reaching deadline while copying "a-b")
+	tcoi.copied = nil
+	tcoi.hook = func(lom *core.LOM) {
+		if lom.ObjName == "a-b" {
+			r.dl.reached.Store(true)
+		}
+	}
+	r.ckpt = newTcbCkpt(r, r.ID())
+	walk(r)
+	r.ckpt.fin(false)
+	for _, mark := range r.ckpt.marks { // (single mountpath)
+		b, err := os.ReadFile(mark.fpath)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, string(b) == "a/c", "expected checkpoint %q, got %q", "a/c", string(b))
+	}
+
+	// resume
+	tcoi.copied, tcoi.hook = nil, nil
+	args := *r.p.args
+	args.Msg = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Checkpoint: true, Resume: r.ID()}}
+	r2 := &XactTCB{}
+	r2.InitBase(cos.GenUUID(), apc.ActCopyBck, "" /*ctlmsg*/, args.BckTo)
+	r2.p = &tcbFactory{args: &args, owt: cmn.OwtCopy}
+	r2.Config = r.Config
+	r2.ckpt = newTcbCkpt(r2, args.Msg.Resume)
+	walk(r2)
+	tassert.Errorf(t, strings.Join(tcoi.copied, ",") == "a-b,b", "expected (a-b, b) upon resuming, got %v", tcoi.copied)
+	tassert.Errorf(t, r2.ckpt.skipped.Load() == 2, "expected 2 skipped, got %d", r2.ckpt.skipped.Load())
+
+	r2.ckpt.fin(true)
+	for _, mark := range r2.ckpt.marks {
+		_, err := os.Stat(mark.fpath)
+		tassert.Errorf(t, os.IsNotExist(err), "expected checkpoint %q to be removed, got %v", mark.fpath, err)
+	}
+}
+
+func TestTCBFilter(t *testing.T) {
+	var (
+		msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Regex: `\.jpg$`, Template: "img-{000..099}.{0..9}"}}
+		r    = newTestTCB(t, msg, nil)
+		tcoi = &tcbtCOI{}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+	r.filter = newTcbFilter(&msg.CopyBckMsg) // (newTestTCB bypasses newTCB)
+
+	for _, name := range []string{"img-005.1.jpg", "img-005.1.png", "img-100.1.jpg", "img-5.1.jpg", "other.jpg", "img-099.9.jpg"} {
+		lom := tcbtLOM(t, r, name)
+		tassert.CheckFatal(t, r.do(lom, nil))
+		core.FreeLOM(lom)
+	}
+	tassert.Errorf(t, len(tcoi.copied) == 0, "expected template to require exact match, got %v", tcoi.copied)
+
+	msg.Template = "img-{000..099}.jpg"
+	r.filter = newTcbFilter(&msg.CopyBckMsg)
+	for _, name := range []string{"img-005.jpg", "img-005.png", "img-100.jpg", "img-5.jpg", "other.jpg", "img-099.jpg"} {
+		lom := tcbtLOM(t, r, name)
+		tassert.CheckFatal(t, r.do(lom, nil))
+		core.FreeLOM(lom)
+	}
+	tassert.Errorf(t, strings.Join(tcoi.copied, ",") == "img-005.jpg,img-099.jpg", "expected (img-005.jpg, img-099.jpg), got %v", tcoi.copied)
+
+	// narrowing down the walk
+	for prefix, expected := range map[string]string{"": "img-", "im": "img-", "img-0": "img-0", "other": "other"} {
+		tassert.Errorf(t, r.filter.prefix(prefix) == expected, "prefix %q: expected %q, got %q", prefix, expected, r.filter.prefix(prefix))
+	}
+}
+
+func TestTCBIncremental(t *testing.T) {
+	var (
+		msg   = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{IfNoneMatch: true}}
+		r     = newTestTCB(t, msg, nil)
+		tcoi  = &tcbtCOI{write: true}
+		names = []string{"a", "b", "c"}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	// no checksums - comparing metadata
+	for _, name := range names {
+		lom := tcbtLOM(t, r, name)
+		tcbtPut(t, lom, "content-"+name)
+		lom.SetCksum(cos.NoneCksum)
+		lom.SetVersion("1")
+		tassert.CheckFatal(t, lom.Persist())
+		core.FreeLOM(lom)
+	}
+	pass := func() []string {
+		tcoi.copied = nil
+		for _, name := range names {
+			lom := tcbtLOM(t, r, name)
+			tassert.CheckFatal(t, lom.Load(false, false))
+			tassert.CheckFatal(t, r.do(lom, nil))
+			core.FreeLOM(lom)
+		}
+		return tcoi.copied
+	}
+
+	copied := pass()
+	tassert.Errorf(t, len(copied) == 3, "expected all to be copied, got %v", copied)
+	copied = pass()
+	tassert.Errorf(t, len(copied) == 0, "expected nothing to be copied (unchanged), got %v", copied)
+
+	// "a": new version; "b": same size, modified
+	lom := tcbtLOM(t, r, "a")
+	tassert.CheckFatal(t, lom.Load(false, false))
+	lom.SetVersion("2")
+	tassert.CheckFatal(t, lom.Persist())
+	core.FreeLOM(lom)
+	lom = tcbtLOM(t, r, "b")
+	future := time.Now().Add(time.Hour)
+	tassert.CheckFatal(t, os.Chtimes(lom.FQN, future, future))
+	core.FreeLOM(lom)
+
+	copied = pass()
+	tassert.Errorf(t, strings.Join(copied, ",") == "a,b", "expected (only) a and b to be copied, got %v", copied)
+	tassert.Errorf(t, r.cond.absent.Load() == 3 && r.cond.skipped.Load() == 4 && r.cond.changed.Load() == 2,
+		"unexpected (absent, skipped, changed) = (%d, %d, %d)", r.cond.absent.Load(), r.cond.skipped.Load(), r.cond.changed.Load())
+}
+
+func TestTCBDryRunEstimate(t *testing.T) {
+	const (
+		numObjs = 5
+		size    = cos.KiB
+	)
+	var (
+		msg  = &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{DryRun: true}}
+		r    = newTestTCB(t, msg, nil)
+		tcoi = &tcbtCOI{}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+	r.est = newTcbEst() // (newTestTCB bypasses newTCB)
+
+	content := strings.Repeat("x", size)
+	for i := range numObjs {
+		lom := tcbtLOM(t, r, "obj-"+strconv.Itoa(i))
+		tcbtPut(t, lom, content)
+		tassert.CheckFatal(t, r.do(lom, nil))
+		core.FreeLOM(lom)
+	}
+
+	est := r.est.get(0)
+	tassert.Errorf(t, est.Objs == numObjs && est.Bytes == numObjs*size, "expected (%d, %d), got (%d, %d)",
+		numObjs, numObjs*size, est.Objs, est.Bytes)
+	cnt, ok := est.PerTarget[core.T.SID()]
+	tassert.Errorf(t, ok && len(est.PerTarget) == 1 && cnt == est.TCBEstimateCnt, "unexpected per-target breakdown %v", est.PerTarget)
+	tassert.Errorf(t, est.ETA > 0, "expected projected time (based on sampled reads), got %v", est.ETA)
+	tassert.Errorf(t, r.est.sampled.Load() == numObjs*size, "expected all (small) objects to be sampled, got %d", r.est.sampled.Load())
+
+	// bandwidth limit
+	est = r.est.get(size)
+	tassert.Errorf(t, est.ETA >= numObjs*time.Second, "expected ETA >= %v at the bandwidth limit, got %v", numObjs*time.Second, est.ETA)
+}
+
+func TestTCBRetry(t *testing.T) {
+	var (
+		r        = newTestTCB(t, &apc.TCBMsg{}, nil)
+		tcoi     = &tcbtCOI{}
+		config   = *r.Config
+		errFlaky = errors.New("flaky")
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	config.TCB.Retries = 3
+	config.TCB.RetryBackoff = cos.Duration(time.Millisecond)
+	r.retry.init(&config.TCB)
+
+	copyObj := func(name string, errs ...error) error {
+		tcoi.errs = errs
+		lom := tcbtLOM(t, r, name)
+		defer core.FreeLOM(lom)
+		return r.copyObject(lom, nil, name)
+	}
+
+	// transient errors, then success
+	err := copyObj("a", syscall.ECONNRESET, &cmn.ErrHTTP{Status: http.StatusServiceUnavailable})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, r.retry.retries.Load() == 2 && r.retry.recovered.Load() == 1, "expected (2 retries, 1 recovered), got (%d, %d)",
+		r.retry.retries.Load(), r.retry.recovered.Load())
+
+	// not retriable
+	err = copyObj("b", errFlaky)
+	tassert.Errorf(t, err == errFlaky && r.retry.retries.Load() == 2, "expected no retries, got %v (%d)", err, r.retry.retries.Load())
+
+	// giving up
+	tooMany := &cmn.ErrHTTP{Status: http.StatusTooManyRequests}
+	err = copyObj("c", tooMany, tooMany, tooMany, tooMany, tooMany)
+	tassert.Errorf(t, err == tooMany && r.retry.retries.Load() == 5, "expected to give up after 3 retries, got %v (%d)", err, r.retry.retries.Load())
+	tassert.Errorf(t, len(tcoi.errs) == 1, "expected %d attempts, got %d", 4, 5-len(tcoi.errs))
+
+	// custom classification
+	r.SetRetriable(func(err error) bool { return err == errFlaky })
+	err = copyObj("d", errFlaky)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, strings.Join(tcoi.copied, ",") == "a,d", "expected (a, d) to be copied, got %v", tcoi.copied)
+}
+
+// compression and stream-bundle multiplier: request overrides ETL config overrides tcb config
+func TestTCBStreamsConf(t *testing.T) {
+	config := &cmn.Config{}
+	config.TCB.Compression, config.TCB.SbundleMult = apc.CompressNever, 1
+	config.TCB.ETLCompression, config.TCB.ETLSbundleMult = apc.CompressAlways, 2
+
+	tests := []struct {
+		kind        string
+		msg         *apc.TCBMsg
+		compression string
+		mult        int
+	}{
+		{apc.ActCopyBck, &apc.TCBMsg{}, apc.CompressNever, 1},
+		{apc.ActETLBck, &apc.TCBMsg{}, apc.CompressAlways, 2},
+		{apc.ActCopyBck, &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Compression: apc.CompressAlways}}, apc.CompressAlways, 1},
+		{apc.ActETLBck, &apc.TCBMsg{CopyBckMsg: apc.CopyBckMsg{Compression: apc.CompressNever, SbundleMult: 4}}, apc.CompressNever, 4},
+	}
+	for _, test := range tests {
+		p := &tcbFactory{kind: test.kind, args: &xreg.TCBArgs{Msg: test.msg}}
+		compression, mult := p.streamsConf(config)
+		tassert.Errorf(t, compression == test.compression && mult == test.mult, "%s %+v: expected (%s, %d), got (%s, %d)",
+			test.kind, test.msg.CopyBckMsg, test.compression, test.mult, compression, mult)
+	}
+}
+
+func TestTCBTune(t *testing.T) {
+	const ms = int64(time.Millisecond)
+	config := &cmn.Config{}
+	config.Disk.DiskUtilHighWM = 90
+	config.TCB.ETLMaxParallel = 4
+
+	var (
+		tune = newTcbTune(nil, config, etlBucketParallelCnt)
+		cur  tuneSample
+		n    = tune.get()
+	)
+	tassert.Fatalf(t, n == etlBucketParallelCnt && tune.minN == etlDfltMinParallel && tune.maxN == 4,
+		"unexpected initial %d [%d, %d]", n, tune.minN, tune.maxN)
+
+	step := func(num, lat int64, util, full int64, expected int, tag string) {
+		cur.num += num
+		cur.lat += num * lat
+		cur.full += full
+		cur.util = util
+		n = tune.next(cur, time.Second, n)
+		tassert.Fatalf(t, n == expected, "%s: expected %d, got %d", tag, expected, n)
+	}
+	step(0, 0, 0, 0, 2, "idle")
+	step(10, ms, 0, 0, 3, "transformer keeps up")
+	step(15, ms+ms/5, 0, 0, 4, "throughput improved")
+	step(16, ms, 0, 0, 3, "no improvement")
+	for range tuneHold {
+		step(20, ms, 0, 0, 3, "hold")
+	}
+	step(20, 5*ms, 0, 0, 3, "transformer latency")
+	step(20, ms, 95, 0, 2, "disk saturated")
+	step(20, ms, 0, 1, 1, "backpressure")
+	step(20, ms, 0, 1, 1, "min bound")
+
+	config.TCB.ETLMinParallel, config.TCB.ETLMaxParallel = 6, 0
+	tune = newTcbTune(nil, config, etlBucketParallelCnt)
+	tassert.Errorf(t, tune.get() == 6 && tune.maxN == etlDfltMaxParallel, "expected min bound, got %d [%d, %d]",
+		tune.get(), tune.minN, tune.maxN)
+}
+
+// (see tcbTransformID)
+type tcbtETL struct {
+	core.LDP
+	tid string
+}
+
+func (dp *tcbtETL) TransformID() string { return dp.tid }
+
+func TestTCBTransformCache(t *testing.T) {
+	var (
+		msg   = &apc.TCBMsg{Transform: apc.Transform{Name: "etl-test", Cache: true}}
+		r     = newTestTCB(t, msg, &tcbtETL{tid: "v1"})
+		tcoi  = &tcbtCOI{write: true}
+		names = []string{"a", "b", "c"}
+	)
+	savedCOI := gcoi
+	gcoi = tcoi
+	defer func() { gcoi = savedCOI }()
+
+	run := func(expected ...string) {
+		tcoi.copied = tcoi.copied[:0]
+		for _, name := range names {
+			lom := tcbtLOM(t, r, name)
+			tassert.CheckFatal(t, lom.Load(false, false))
+			tassert.CheckFatal(t, r.do(lom, nil))
+			core.FreeLOM(lom)
+		}
+		sort.Strings(tcoi.copied)
+		tassert.Fatalf(t, slices.Equal(tcoi.copied, expected), "expected %v transformed, got %v", expected, tcoi.copied)
+	}
+
+	r.cache = newTcbCache(r.p.args.DP)
+	tassert.Fatalf(t, r.cache != nil, "expected transform ID")
+	for _, name := range names {
+		lom := tcbtLOM(t, r, name)
+		tcbtPut(t, lom, "content of "+name)
+		core.FreeLOM(lom)
+	}
+	run(names...)
+	_, ok := tcoi.custom["a"][cmn.ETLCacheObjMD]
+	tassert.Errorf(t, ok, "expected cache key recorded at the destination, got %v", tcoi.custom["a"])
+
+	// nothing changed
+	run()
+	tassert.Errorf(t, r.cache.hits.Load() == 3, "expected 3 hits, got %d", r.cache.hits.Load())
+
+	// source changed
+	lom := tcbtLOM(t, r, "b")
+	tcbtPut(t, lom, "new content of b")
+	core.FreeLOM(lom)
+	run("b")
+
+	// transformation changed (e.g., ETL re-initialized with different code)
+	r.cache = newTcbCache(&tcbtETL{tid: "v2"})
+	run(names...)
+	tassert.Errorf(t, r.cache.misses.Load() == 3, "expected 3 misses, got %d", r.cache.misses.Load())
+}
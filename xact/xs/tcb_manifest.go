@@ -0,0 +1,154 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// x-tcb destination manifest (see apc.CopyBckMsg.Manifest):
+// - records (NDJSON) destination objects as they get stored by this target - locally or
+//   upon receiving from other targets;
+// - the records are written into a work file which, upon completion, becomes the report
+//   object in the destination bucket (compare with tcbShard)
+
+type tcbManifest struct {
+	r     *XactTCB
+	lom   *core.LOM // report object
+	wfh   cos.LomWriter
+	bw    *bufio.Writer
+	fqn   string // workfile
+	cksum cos.CksumHashSize
+	cnt   int64
+	err   error // first write error, if any (stop recording)
+	mu    sync.Mutex
+}
+
+// max attempts (per target) to come up with the report name that maps onto this target
+const manifestNameTries = 64
+
+func (m *tcbManifest) init(r *XactTCB, prefix string) (err error) {
+	m.r = r
+	if m.lom, err = m.local(prefix); err != nil {
+		return err
+	}
+	m.fqn = fs.CSM.Gen(m.lom, fs.WorkfileType, "tcb-manifest")
+	if m.wfh, err = m.lom.CreateWork(m.fqn); err != nil {
+		core.FreeLOM(m.lom)
+		return err
+	}
+	m.cksum.Init(m.lom.CksumType())
+	m.bw = bufio.NewWriter(cos.NewWriterMulti(m.wfh, &m.cksum))
+	return nil
+}
+
+// HRW-wise, the name of the report must map onto this target
+func (m *tcbManifest) local(prefix string) (*core.LOM, error) {
+	var (
+		smap  = core.T.Sowner().Get()
+		tries = manifestNameTries * max(smap.CountActiveTs(), 1)
+	)
+	for i := range tries {
+		lom := core.AllocLOM(prefix + core.T.SID() + "-" + strconv.Itoa(i) + apc.TCBManifestExt)
+		if err := lom.InitBck(m.r.p.args.BckTo.Bucket()); err != nil {
+			core.FreeLOM(lom)
+			return nil, err
+		}
+		_, local, err := lom.HrwTarget(smap)
+		if err != nil {
+			core.FreeLOM(lom)
+			return nil, err
+		}
+		if local {
+			return lom, nil
+		}
+		core.FreeLOM(lom)
+	}
+	return nil, fmt.Errorf("%s: failed to name destination manifest (%q, %d)", m.r, prefix, tries)
+}
+
+// record stored destination object
+func (m *tcbManifest) add(lom *core.LOM) {
+	entry := apc.TCBManifestEntry{Name: lom.ObjName, Size: lom.Lsize(), Custom: lom.GetCustomMD()}
+	if cksum := lom.Checksum(); !cksum.IsEmpty() {
+		entry.CksumType, entry.CksumValue = cksum.Get()
+	}
+	b := cos.MustMarshal(&entry)
+
+	m.mu.Lock()
+	if m.err == nil {
+		if _, m.err = m.bw.Write(b); m.err == nil {
+			m.err = m.bw.WriteByte('\n')
+		}
+		if m.err != nil {
+			nlog.Errorln(m.r.Name()+": failed to write destination manifest:", m.err)
+		} else {
+			m.cnt++
+		}
+	}
+	m.mu.Unlock()
+}
+
+// record stored destination object given its name (the object must be local)
+func (m *tcbManifest) addName(objName string) {
+	lom := core.AllocLOM(objName)
+	if lom.InitBck(m.r.p.args.BckTo.Bucket()) == nil && lom.Load(false /*cache it*/, false /*locked*/) == nil {
+		m.add(lom)
+	}
+	core.FreeLOM(lom)
+}
+
+// store the report (or cleanup when aborted)
+func (m *tcbManifest) fin(aborted bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	err := m.bw.Flush()
+	if errC := m.wfh.Close(); err == nil {
+		err = errC
+	}
+	m.wfh = nil
+	if err == nil {
+		err = m.err
+	}
+	if aborted || err != nil {
+		m.cleanup()
+		return err
+	}
+	m.cksum.Finalize()
+	m.lom.SetCksum(&m.cksum.Cksum)
+	m.lom.SetSize(m.cksum.Size)
+	m.lom.SetAtimeUnix(time.Now().UnixNano())
+
+	_, err = core.T.FinalizeObj(m.lom, m.fqn, m.r, m.r.p.owt)
+	if err != nil {
+		m.cleanup()
+		return err
+	}
+	nlog.Infoln(m.r.Name()+": stored destination manifest", m.lom.Cname(), "[", m.cnt, "]")
+	core.FreeLOM(m.lom)
+	return nil
+}
+
+func (m *tcbManifest) cleanup() {
+	if m.wfh != nil {
+		cos.Close(m.wfh)
+		m.wfh = nil
+	}
+	if err := cos.RemoveFile(m.fqn); err != nil {
+		nlog.Errorln(fmt.Errorf("failed to remove %q: %w", m.fqn, err))
+	}
+	core.FreeLOM(m.lom)
+}
@@ -0,0 +1,59 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+)
+
+// x-tcb destination name collisions (see apc.CopyBckMsg.OnCollision)
+// - keeps all destination names produced by this target (in memory)
+// - concurrency-safe (joggers)
+
+type tcbNames struct {
+	m      map[string]string // dst => src
+	policy string
+	cnt    atomic.Int64 // num collisions
+	mu     sync.Mutex
+}
+
+func newTcbNames(policy string) *tcbNames {
+	return &tcbNames{m: make(map[string]string, 1024), policy: policy}
+}
+
+// returns destination name to use, or empty string to skip
+func (n *tcbNames) resolve(srcName, toName string) (string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	prev, ok := n.m[toName]
+	if !ok {
+		n.m[toName] = srcName
+		return toName, nil
+	}
+	n.cnt.Inc()
+	switch n.policy {
+	case apc.TCBCollisionErr:
+		return "", fmt.Errorf("destination name collision: %q and %q both map to %q", prev, srcName, toName)
+	case apc.TCBCollisionSkip:
+		return "", nil
+	default:
+		ext := filepath.Ext(toName)
+		base := toName[:len(toName)-len(ext)]
+		for i := 1; ; i++ {
+			name := base + "-" + strconv.Itoa(i) + ext
+			if _, ok := n.m[name]; !ok {
+				n.m[name] = srcName
+				return name, nil
+			}
+		}
+	}
+}
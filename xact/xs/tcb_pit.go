@@ -0,0 +1,39 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/core"
+)
+
+// x-tcb point-in-time copy (see apc.CopyBckMsg.Snapshot):
+// - source objects written (created or overwritten) in the cluster after the instant are skipped;
+// - the time an object was written is the modification time of its (local) replica
+// - see also: apc.CopyBckMsg.OnSrcChange to detect sources modified while being copied
+
+type tcbPIT struct {
+	skipped atomic.Int64 // num source objects written after the instant
+	asOf    int64        // Unix nanoseconds
+}
+
+func newTcbPIT(asOf int64) *tcbPIT {
+	if asOf == 0 {
+		asOf = time.Now().UnixNano() // (not expecting: set by the proxy)
+	}
+	return &tcbPIT{asOf: asOf}
+}
+
+func (pit *tcbPIT) skip(lom *core.LOM) bool {
+	_, _, mtime, err := lom.Fstat(false /*get atime*/)
+	if err != nil || mtime.UnixNano() <= pit.asOf {
+		return false // (not-found and other errors will resurface when copying)
+	}
+	pit.skipped.Inc()
+	return true
+}
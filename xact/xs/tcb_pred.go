@@ -0,0 +1,76 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"sync"
+
+	"github.com/NVIDIA/aistore/core"
+)
+
+// x-tcb custom skip decisions:
+// - registered predicates are consulted for each source object (in the order of registration)
+//   after the built-in filters (prefix, start-after, operator skip-list, point-in-time) and prior to copying;
+// - the first predicate that returns false skips the object; the (short) reason it returns is counted
+//   in the skip breakdown (see ExtTCBStats.SkipReasons)
+// - predicates are called concurrently (by all joggers) and must be cheap: the object is not
+//   necessarily loaded, and its content must not be read
+
+type (
+	// returns false (and the reason) to skip the object
+	TCBSkipPred func(lom *core.LOM) (ok bool, reason string)
+
+	tcbPreds struct {
+		reasons map[string]int64 // skip breakdown
+		preds   []TCBSkipPred
+		mu      sync.Mutex
+	}
+)
+
+const (
+	tcbDfltSkipReason = "custom"
+	tcbMaxSkipReasons = 32 // distinct reasons; the rest are counted as tcbDfltSkipReason
+)
+
+// register custom skip predicate; must be called prior to running the xaction
+func (r *XactTCB) AddSkipPred(pred TCBSkipPred) {
+	r.preds.preds = append(r.preds.preds, pred)
+}
+
+// returns true to skip
+func (p *tcbPreds) skip(lom *core.LOM) bool {
+	for _, pred := range p.preds {
+		if ok, reason := pred(lom); !ok {
+			p.add(reason)
+			return true
+		}
+	}
+	return false
+}
+
+func (p *tcbPreds) add(reason string) {
+	p.mu.Lock()
+	if p.reasons == nil {
+		p.reasons = make(map[string]int64, 4)
+	}
+	if _, ok := p.reasons[reason]; !ok && (reason == "" || len(p.reasons) >= tcbMaxSkipReasons) {
+		reason = tcbDfltSkipReason
+	}
+	p.reasons[reason]++
+	p.mu.Unlock()
+}
+
+func (p *tcbPreds) breakdown() (out map[string]int64) {
+	p.mu.Lock()
+	if len(p.reasons) > 0 {
+		out = make(map[string]int64, len(p.reasons))
+		for reason, n := range p.reasons {
+			out[reason] = n
+		}
+	}
+	p.mu.Unlock()
+	return out
+}
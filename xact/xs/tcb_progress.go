@@ -0,0 +1,142 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/mono"
+)
+
+// x-tcb progress subscription (this target):
+// - subscriber receives progress updates at the requested interval and, upon xaction completion,
+//   the terminal update (TCBProgress.Final) - after which the channel gets closed;
+// - the channel buffers a single update: a subscriber that falls behind receives the most recent one
+//   (older pending update gets replaced and counted) - slow subscribers never stall the copy
+
+const tcbProgressMinIval = 100 * time.Millisecond
+
+type (
+	TCBProgress struct {
+		Err     error   // terminal: abort error (if aborted)
+		Objs    int64   // copied so far
+		Bytes   int64   // ditto
+		Percent float64 // of the total bytes to copy (see tcbETA); zero when unknown
+		Tput    int64   // bytes per second since the previous update
+		Final   bool    // terminal update
+	}
+
+	tcbSub struct {
+		ch      chan TCBProgress
+		stopCh  *cos.StopCh
+		ival    time.Duration
+		last    int64 // mono time
+		bytes   int64 // as of the previous update
+		dropped atomic.Int64
+	}
+	tcbProgress struct {
+		subs []*tcbSub
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done bool
+	}
+)
+
+// subscribe to progress updates (see above); interval is rounded up to tcbProgressMinIval
+// - when called after the xaction has finished, delivers the terminal update only
+func (r *XactTCB) SubscribeProgress(ival time.Duration) <-chan TCBProgress {
+	sub := &tcbSub{
+		ch:     make(chan TCBProgress, 1),
+		stopCh: cos.NewStopCh(),
+		ival:   max(ival, tcbProgressMinIval),
+		last:   mono.NanoTime(),
+	}
+	p := &r.prog
+	p.mu.Lock()
+	if p.done {
+		p.mu.Unlock()
+		sub.deliver(r.progress(sub, true))
+		close(sub.ch)
+		return sub.ch
+	}
+	p.subs = append(p.subs, sub)
+	p.wg.Add(1)
+	go sub.run(r, &p.wg)
+	p.mu.Unlock()
+	return sub.ch
+}
+
+func (sub *tcbSub) run(r *XactTCB, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(sub.ival)
+	defer func() {
+		ticker.Stop()
+		wg.Done()
+	}()
+	for {
+		select {
+		case <-ticker.C:
+			sub.deliver(r.progress(sub, false))
+		case <-sub.stopCh.Listen():
+			return
+		}
+	}
+}
+
+// never blocks: replaces the pending (undelivered) update, if any
+func (sub *tcbSub) deliver(p TCBProgress) {
+	for {
+		select {
+		case sub.ch <- p:
+			return
+		default:
+		}
+		select {
+		case <-sub.ch:
+			sub.dropped.Inc()
+		default:
+		}
+	}
+}
+
+func (r *XactTCB) progress(sub *tcbSub, final bool) TCBProgress {
+	var (
+		now = mono.NanoTime()
+		p   = TCBProgress{Objs: r.Objs(), Bytes: r.Bytes(), Final: final}
+	)
+	if total := r.eta.total.Load(); total > 0 {
+		p.Percent = min(float64(p.Bytes)*100/float64(total), 100)
+	}
+	if final {
+		p.Err = r.AbortErr()
+		if p.Err == nil && !r.dl.reached.Load() {
+			p.Percent = 100
+		}
+	}
+	p.Tput = _tput(p.Bytes-sub.bytes, now-sub.last)
+	sub.last, sub.bytes = now, p.Bytes
+	return p
+}
+
+// upon finishing: stop periodic updates, deliver the terminal one, and close
+func (p *tcbProgress) fin(r *XactTCB) {
+	p.mu.Lock()
+	p.done = true
+	subs := p.subs
+	p.subs = nil
+	p.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.stopCh.Close()
+	}
+	p.wg.Wait()
+	for _, sub := range subs {
+		sub.deliver(r.progress(sub, true))
+		close(sub.ch)
+	}
+}
@@ -0,0 +1,79 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+)
+
+// x-tcb per-object retries (see cmn.TCBConf.Retries):
+// - upon transient errors: connection refused or reset, broken pipe, timeouts, and HTTP 408, 429, 5xx
+//   (other than 501) - or else, as classified by the caller (see SetRetriable);
+// - exponential backoff, interrupted by abort
+
+const (
+	tcbRetryDfltBackoff    = 200 * time.Millisecond
+	tcbRetryDfltMaxBackoff = 10 * time.Second
+)
+
+type tcbRetry struct {
+	retriable  func(err error) bool
+	retries    atomic.Int64 // total
+	recovered  atomic.Int64 // objects copied after retrying
+	backoff    time.Duration
+	maxBackoff time.Duration
+	max        int
+}
+
+// custom error classification; must be called prior to running the xaction
+func (r *XactTCB) SetRetriable(fn func(err error) bool) { r.retry.retriable = fn }
+
+func (rt *tcbRetry) init(config *cmn.TCBConf) {
+	rt.max = config.Retries
+	rt.backoff = cos.NonZero(config.RetryBackoff.D(), tcbRetryDfltBackoff)
+	rt.maxBackoff = cos.NonZero(config.RetryMaxBackoff.D(), tcbRetryDfltMaxBackoff)
+	if rt.retriable == nil {
+		rt.retriable = tcbRetriable
+	}
+}
+
+// returns true after backing off - if the `attempt` (zero-based) that failed with `err` is to be retried
+func (rt *tcbRetry) again(r *XactTCB, err error, attempt int) bool {
+	if attempt >= rt.max || r.IsAborted() || cmn.IsErrAborted(err) || !rt.retriable(err) {
+		return false
+	}
+	rt.retries.Inc()
+	timer := time.NewTimer(min(rt.backoff<<attempt, rt.maxBackoff))
+	select {
+	case <-timer.C:
+		return true
+	case <-r.ChanAbort():
+		timer.Stop()
+		return false
+	}
+}
+
+func tcbRetriable(err error) bool {
+	if cos.IsRetriableConnErr(err) || cos.IsClientTimeout(err) || cos.IsErrSyscallTimeout(err) {
+		return true
+	}
+	var herr *cmn.ErrHTTP
+	if !errors.As(err, &herr) {
+		return false
+	}
+	switch herr.Status {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
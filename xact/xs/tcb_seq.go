@@ -0,0 +1,43 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/core"
+)
+
+// x-tcb sequence markers (see apc.CopyBckMsg.PreserveOrder):
+// - the sender reads the source's marker - custom cmn.SeqObjMD or, if absent, the time the object
+//   was written in the cluster - and has the copier set it on the destination (see CoiParams.CustomMD);
+// - remote destination target gets the marker with the object's (transport header) custom metadata,
+//   and stores it via PutObject (see _recv);
+// - the marker survives transformation (ETL) that otherwise does not preserve source metadata;
+// - source that is not present in the cluster (remote, not cached) and has no marker is copied without it (counted)
+
+type tcbSeq struct {
+	carried atomic.Int64 // objects copied with their sequence markers
+	missing atomic.Int64 // ditto, without (no marker and no local replica to derive it from)
+}
+
+func (s *tcbSeq) get(lom *core.LOM) cos.StrKVs {
+	if seq, ok := lom.GetCustomKey(cmn.SeqObjMD); ok && seq != "" {
+		return cos.StrKVs{cmn.SeqObjMD: seq}
+	}
+	_, _, mtime, err := lom.Fstat(false /*get atime*/)
+	if err != nil {
+		s.missing.Inc()
+		return nil
+	}
+	return cos.StrKVs{cmn.SeqObjMD: fmtSeq(mtime.UnixNano())}
+}
+
+// zero-padded to sort lexicographically
+func fmtSeq(n int64) string { return fmt.Sprintf("%020d", n) }
@@ -0,0 +1,108 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+)
+
+// x-tcb operator skip-list (see XactTCB.SkipObjs):
+// - operational escape hatch to skip specific (e.g., poison) source objects without aborting the job;
+// - consulted prior to copying, prior to retrying (see copyConsistent), and upon receiving;
+// - object that gets skip-listed while being copied: its local destination is removed ("cancelled")
+//   once the copy completes; remote destination is dropped by the receiving target (see _recv)
+//   provided the latter has the same skip-list and can derive the source name (see XactTCB.srcName)
+// - the list is per target - the caller submits it to all targets
+
+const tcbMaxOpSkipped = 64 // max number of operator-skipped object names to report
+
+type tcbSkip struct {
+	names   map[string]bool // source name => skipped (true) or listed but not (yet) encountered
+	skipped atomic.Int64
+	n       atomic.Int32 // (fast path)
+	mu      sync.RWMutex
+}
+
+// submit source object names to skip; can be called at any time while the xaction is running
+func (r *XactTCB) SkipObjs(names ...string) {
+	s := &r.skip
+	s.mu.Lock()
+	if s.names == nil {
+		s.names = make(map[string]bool, len(names))
+	}
+	for _, name := range names {
+		if _, ok := s.names[name]; !ok {
+			s.names[name] = false
+		}
+	}
+	s.n.Store(int32(len(s.names)))
+	s.mu.Unlock()
+	nlog.Infoln(r.Name(), "operator skip-list:", names)
+}
+
+func (s *tcbSkip) has(name string) bool {
+	if s.n.Load() == 0 {
+		return false
+	}
+	s.mu.RLock()
+	_, ok := s.names[name]
+	s.mu.RUnlock()
+	return ok
+}
+
+// has and record (once)
+func (s *tcbSkip) skip(name string) bool {
+	if !s.has(name) {
+		return false
+	}
+	s.mu.Lock()
+	if !s.names[name] {
+		s.names[name] = true
+		s.skipped.Inc()
+	}
+	s.mu.Unlock()
+	return true
+}
+
+// cancel (remove) local destination of the object that got skip-listed while being copied
+func (r *XactTCB) cancelDst(srcName, objnameTo string) {
+	r.skip.skip(srcName)
+	dst := core.AllocLOM(objnameTo)
+	if err := dst.InitBck(r.p.args.BckTo.Bucket()); err == nil {
+		dst.Lock(true)
+		err = dst.RemoveObj()
+		dst.Unlock(true)
+		if err != nil {
+			r.AddErr(err, 4, cos.SmoduleXs)
+		}
+	}
+	core.FreeLOM(dst)
+}
+
+// operator-skipped source names (bounded)
+func (s *tcbSkip) list() (names []string) {
+	if s.skipped.Load() == 0 {
+		return nil
+	}
+	s.mu.RLock()
+	for name, skipped := range s.names {
+		if skipped {
+			names = append(names, name)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Strings(names)
+	if len(names) > tcbMaxOpSkipped {
+		names = names[:tcbMaxOpSkipped]
+	}
+	return names
+}
@@ -0,0 +1,96 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/core"
+)
+
+// x-tcb: source objects modified while being copied (see apc.CopyBckMsg.OnSrcChange)
+
+const tcbSrcChangeRetries = 3
+
+type tcbSrcSnap struct {
+	cksum   *cos.Cksum
+	version string
+	size    int64
+	mtime   int64
+}
+
+func (snap *tcbSrcSnap) load(lom *core.LOM) error {
+	finfo, err := os.Stat(lom.FQN)
+	if err != nil {
+		return err
+	}
+	snap.size, snap.mtime = finfo.Size(), finfo.ModTime().UnixNano()
+	snap.version = lom.Version()
+	snap.cksum = lom.Checksum()
+	return nil
+}
+
+func (snap *tcbSrcSnap) eq(other *tcbSrcSnap) bool {
+	if snap.size != other.size || snap.mtime != other.mtime || snap.version != other.version {
+		return false
+	}
+	if snap.cksum.IsEmpty() || other.cksum.IsEmpty() {
+		return snap.cksum.IsEmpty() == other.cksum.IsEmpty()
+	}
+	return snap.cksum.Equal(other.cksum)
+}
+
+// (re)load the source object and compare with the snapshot taken prior to copying
+func (r *XactTCB) srcChanged(lom *core.LOM, before *tcbSrcSnap) bool {
+	src := core.AllocLOM(lom.ObjName)
+	defer core.FreeLOM(src)
+	if src.InitBck(lom.Bucket()) != nil {
+		return false
+	}
+	if err := src.Load(false /*cache it*/, false /*locked*/); err != nil {
+		return cos.IsNotExist(err, 0) // removed in the meantime
+	}
+	var after tcbSrcSnap
+	if after.load(src) != nil {
+		return true
+	}
+	return !before.eq(&after)
+}
+
+// copy, detect concurrent modification, and apply the policy
+func (r *XactTCB) copyConsistent(lom *core.LOM, buf []byte, toName string) error {
+	var (
+		policy = r.p.args.Msg.OnSrcChange
+		tries  = 1
+	)
+	if policy == apc.TCBSrcChangeRetry {
+		tries += tcbSrcChangeRetries
+	}
+	for range tries {
+		if r.skip.skip(lom.ObjName) {
+			return nil // skip-listed in the meantime
+		}
+		var before tcbSrcSnap
+		if err := before.load(lom); err != nil {
+			return err
+		}
+		if err := r._copy(lom, buf, toName); err != nil {
+			return err
+		}
+		if !r.srcChanged(lom, &before) {
+			return nil
+		}
+		r.srcChange.Inc()
+		// reload for the next try
+		if err := lom.Load(false /*cache it*/, false /*locked*/); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("%s: source %s modified while being copied (policy %q)", r, lom.Cname(), policy)
+}
@@ -0,0 +1,51 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/core"
+)
+
+// x-tcb server-side encryption (see apc.CopyBckMsg.SSE):
+// - requested from the destination's remote backend when writing through to it (see core.SSEBackend);
+// - when the destination is not remote or its backend does not support SSE, destination objects
+//   are written as usual (with the backend's defaults) and counted as skipped
+// - in-cluster replicas of destination objects are not affected
+
+type tcbSSE struct {
+	msg       *apc.SSEMsg  // nil when not requested or not supported
+	applied   atomic.Int64 // destination objects written with SSE
+	skipped   atomic.Int64 // ditto, without (not supported)
+	requested bool
+}
+
+func (s *tcbSSE) init(r *XactTCB, msg *apc.SSEMsg) {
+	s.requested = true
+	bck := r.p.args.BckTo
+	if !bck.IsRemote() {
+		nlog.Warningln(r.Name(), "server-side encryption requires remote destination - skipping")
+		return
+	}
+	if _, ok := core.T.Backend(bck).(core.SSEBackend); !ok {
+		nlog.Warningln(r.Name(), "destination backend", bck.Provider, "does not support server-side encryption - skipping")
+		return
+	}
+	s.msg = msg
+}
+
+// count destination object written by this target
+func (s *tcbSSE) inc() {
+	switch {
+	case !s.requested:
+	case s.msg != nil:
+		s.applied.Inc()
+	default:
+		s.skipped.Inc()
+	}
+}
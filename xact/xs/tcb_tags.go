@@ -0,0 +1,70 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/core"
+)
+
+// x-tcb object tags (see apc.CopyBckMsg.PreserveTags):
+// - the sender reads source tags (remote source only) and either applies them locally
+//   or passes them along with the object (transport header's opaque) to be applied by the receiver;
+// - the tags are applied after the destination object is written, and only when the destination is
+//   a remote bucket with tag-capable backend (see core.TagBackend); otherwise, skipped
+// - failures to read or apply the tags are not fatal (counted as xaction errors)
+
+type tcbTags struct {
+	copied  atomic.Int64 // objects with tags applied at the destination
+	skipped atomic.Int64 // objects with tags that could not be applied (not supported)
+}
+
+func (*tcbTags) get(r *XactTCB, lom *core.LOM) cos.StrKVs {
+	bck := lom.Bck()
+	if !bck.IsRemote() {
+		return nil
+	}
+	tb, ok := core.T.Backend(bck).(core.TagBackend)
+	if !ok {
+		return nil
+	}
+	tags, _, err := tb.GetObjTags(lom)
+	if err != nil {
+		r.AddErr(err, 4, cos.SmoduleXs)
+		return nil
+	}
+	return tags
+}
+
+// apply to the (just written) local destination
+func (t *tcbTags) putName(r *XactTCB, objName string, tags cos.StrKVs) {
+	lom := core.AllocLOM(objName)
+	if err := lom.InitBck(r.p.args.BckTo.Bucket()); err != nil {
+		r.AddErr(err, 4, cos.SmoduleXs)
+	} else {
+		t.put(r, lom, tags)
+	}
+	core.FreeLOM(lom)
+}
+
+func (t *tcbTags) put(r *XactTCB, lom *core.LOM, tags cos.StrKVs) {
+	bck := lom.Bck()
+	if !bck.IsRemote() || r.p.args.Msg.LocalOnly {
+		t.skipped.Inc()
+		return
+	}
+	tb, ok := core.T.Backend(bck).(core.TagBackend)
+	if !ok {
+		t.skipped.Inc()
+		return
+	}
+	if _, err := tb.PutObjTags(lom, tags); err != nil {
+		r.AddErr(err, 4, cos.SmoduleXs)
+		return
+	}
+	t.copied.Inc()
+}
@@ -0,0 +1,57 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/core/meta"
+	"github.com/NVIDIA/aistore/xact/xreg"
+)
+
+// x-tcb bandwidth limit (this target; see apc.CopyBckMsg.RateLimit and XactTCB.SetRateLimit):
+// - source objects are charged (by size) prior to being copied; joggers wait when the limit is exceeded;
+// - up to one second worth of bytes may go in a burst after idling
+// - the limit is enforced at object granularity: a single large object may exceed it (but is still charged)
+
+type tcbThrottle struct {
+	bps   atomic.Int64 // bytes per second; zero - unlimited
+	slept atomic.Int64 // total time throttled (ns)
+	next  int64        // mono time: when the bytes charged so far have all "gone"
+	mu    sync.Mutex
+}
+
+// change at runtime; zero or negative - unlimited
+func (r *XactTCB) SetRateLimit(bps int64) { r.throttle.bps.Store(max(bps, 0)) }
+
+// (in addition, the per-bucket bandwidth shared by all copies from or to the given buckets - see xreg.BckThrottle)
+func (th *tcbThrottle) wait(size int64, abortCh <-chan error, bcks ...*meta.Bck) {
+	if size <= 0 {
+		return
+	}
+	d := xreg.BckThrottle(size, bcks...)
+	if bps := th.bps.Load(); bps > 0 {
+		th.mu.Lock()
+		now := mono.NanoTime()
+		th.next = max(th.next, now-int64(time.Second))
+		th.next += int64(float64(size) / float64(bps) * float64(time.Second))
+		d = max(d, time.Duration(th.next-now))
+		th.mu.Unlock()
+	}
+	if d <= 0 {
+		return
+	}
+	th.slept.Add(int64(d))
+	timer := time.NewTimer(d)
+	select {
+	case <-timer.C:
+	case <-abortCh:
+		timer.Stop()
+	}
+}
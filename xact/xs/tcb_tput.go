@@ -0,0 +1,50 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/atomic"
+)
+
+// x-tcb source-read vs destination-write throughput (this target):
+// - read: bytes read from the source over the time spent in the source reader (see CoiRStats);
+// - write: bytes copied over the remaining time it takes to copy - writing local destination
+//   or transferring to the destination target (including backpressure)
+// - local fast path (same target, no transformation) is not split and counts as write;
+// - when sending to other targets, the source is read asynchronously, and the split is approximate
+// zero throughput means unknown (nothing yet to measure)
+
+type tcbTput struct {
+	rstats CoiRStats
+	ns     atomic.Int64 // total time copying
+	size   atomic.Int64 // total bytes copied
+}
+
+func (tp *tcbTput) add(ns, size int64) {
+	tp.ns.Add(ns)
+	tp.size.Add(size)
+}
+
+// bytes per second
+func (tp *tcbTput) gauges() (read, write int64) {
+	var (
+		rns   = tp.rstats.Ns.Load()
+		wns   = tp.ns.Load() - rns
+		rsize = tp.rstats.Size.Load()
+	)
+	read = _tput(rsize, rns)
+	write = _tput(tp.size.Load(), wns)
+	return read, write
+}
+
+func _tput(size, ns int64) int64 {
+	if size <= 0 || ns <= 0 {
+		return 0
+	}
+	return int64(float64(size) / float64(ns) * float64(time.Second))
+}
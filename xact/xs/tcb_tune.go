@@ -0,0 +1,144 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// x-tcb (ETL) auto-tuned parallelism: the number of objects each mountpath jogger transforms
+// in parallel varies between tcb.etl_min_parallel and tcb.etl_max_parallel (see cmn.TCBConf).
+// Every tuneInterval:
+// - sample objects done, average transformer latency (time spent reading the transformed source,
+//   see CoiRStats), max disk utilization, and destination backpressure (send queues found full);
+// - shrink by one and hold for tuneHold intervals when disks are above high watermark or upon backpressure;
+// - otherwise, grow by one while the latency stays within tuneLatency times its observed minimum
+//   (i.e., while the transformer keeps up);
+// - keep the added width only if the throughput (objects per second) improves by at least (tuneGain - 1);
+//   otherwise, shrink back and hold.
+// Note that the jogger allocates read buffers for the max (see mpather.JgroupOpts.Width).
+
+const (
+	tuneInterval = 5 * time.Second
+	tuneLatency  = 2
+	tuneGain     = 1.1
+	tuneHold     = 6
+)
+
+// defaults (see cmn.TCBConf)
+const (
+	etlBucketParallelCnt = 2 // initial
+	etlDfltMinParallel   = 1
+	etlDfltMaxParallel   = 8
+)
+
+type (
+	tuneSample struct {
+		num  int64 // objects
+		lat  int64 // cumulative transformer latency, ns
+		full int64 // send queue full
+		util int64 // max disk utilization, %
+	}
+	tcbTune struct {
+		r      *XactTCB
+		num    atomic.Int64 // objects done
+		width  atomic.Int32 // current
+		prev   tuneSample
+		tput   float64 // previous interval, objects/s
+		minLat int64   // baseline, ns
+		highWM int64   // disk utilization
+		minN   int
+		maxN   int
+		hold   int  // intervals to hold
+		grown  bool // previous interval added width (to be evaluated)
+	}
+)
+
+func newTcbTune(r *XactTCB, config *cmn.Config, initial int) *tcbTune {
+	t := &tcbTune{r: r, highWM: config.Disk.DiskUtilHighWM, minN: etlDfltMinParallel, maxN: etlDfltMaxParallel}
+	if n := config.TCB.ETLMinParallel; n > 0 {
+		t.minN = n
+	}
+	if n := config.TCB.ETLMaxParallel; n > 0 {
+		t.maxN = n
+	}
+	t.maxN = max(t.maxN, t.minN)
+	t.width.Store(int32(min(max(initial, t.minN), t.maxN)))
+	return t
+}
+
+func (t *tcbTune) hkName() string { return "tcb-tune-" + t.r.ID() }
+
+func (t *tcbTune) get() int { return int(t.width.Load()) }
+
+func (t *tcbTune) tick(int64) time.Duration {
+	var (
+		r   = t.r
+		cur = tuneSample{num: t.num.Load(), lat: r.tput.rstats.Ns.Load(), util: fs.GetMaxUtil()}
+	)
+	if r.dm != nil {
+		cur.full = r.dm.ChanFull()
+	}
+	n := t.get()
+	m := t.next(cur, tuneInterval, n)
+	if m != n {
+		t.width.Store(int32(m))
+		if cmn.Rom.FastV(4, cos.SmoduleXs) {
+			nlog.Infoln(r.Name(), "parallel:", n, "=>", m)
+		}
+	}
+	return tuneInterval
+}
+
+// given cumulative sample and current width, return the next width
+func (t *tcbTune) next(cur tuneSample, elapsed time.Duration, n int) int {
+	d := tuneSample{num: cur.num - t.prev.num, lat: cur.lat - t.prev.lat, full: cur.full - t.prev.full}
+	t.prev = cur
+
+	// idle (or else, nothing done yet)
+	if d.num <= 0 {
+		t.grown, t.tput = false, 0
+		return n
+	}
+
+	var (
+		tput = float64(d.num) / elapsed.Seconds()
+		lat  = d.lat / d.num
+	)
+	if t.minLat == 0 || (lat > 0 && lat < t.minLat) {
+		t.minLat = lat
+	}
+	prev := t.tput
+	t.tput = tput
+
+	// saturated
+	if cur.util >= t.highWM || d.full > 0 {
+		t.grown, t.hold = false, tuneHold
+		return max(n-1, t.minN)
+	}
+	switch {
+	case t.grown:
+		t.grown = false
+		if tput < prev*tuneGain {
+			t.hold = tuneHold
+			return max(n-1, t.minN)
+		}
+	case t.hold > 0:
+		t.hold--
+		return n
+	}
+	if n < t.maxN && lat <= t.minLat*tuneLatency {
+		t.grown = true
+		return n + 1
+	}
+	return n
+}
@@ -0,0 +1,175 @@
+// Package xs is a collection of eXtended actions (xactions), including multi-object
+// operations, list-objects, (cluster) rebalance and (target) resilver, ETL, and more.
+/*
+ * Copyright (c) 2024, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/atomic"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/nlog"
+	"github.com/NVIDIA/aistore/cmn/prob"
+	"github.com/NVIDIA/aistore/core"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/mpather"
+	"github.com/NVIDIA/aistore/memsys"
+)
+
+// x-tcb verification pass (see apc.CopyBckMsg.VerifyAfter):
+// - each target records the destination objects it stores (copies and received) in a probabilistic filter;
+// - when all senders are done, the target walks its destination mountpaths (the 2nd jogger group)
+//   and re-reads each recorded object to recompute its checksum and compare it with the stored one;
+// - mismatches are counted and reported by name (bounded), and re-copied when the source object
+//   is local and its name can be derived from the destination name (see XactTCB.srcName);
+// - false positives (filter) only mean that some pre-existing destination objects get verified as well
+
+const tcbMaxMismatched = 64 // max number of mismatched object names to report
+
+type tcbVerify struct {
+	r        *XactTCB
+	joggers  *mpather.Jgroup
+	filter   *prob.Filter
+	names    []string // mismatched (bounded)
+	verified atomic.Int64
+	mismatch atomic.Int64
+	recopied atomic.Int64
+	mu       sync.Mutex
+}
+
+func newTcbVerify(r *XactTCB, slab *memsys.Slab, config *cmn.Config) *tcbVerify {
+	var (
+		v    = &tcbVerify{r: r, filter: prob.NewDefaultFilter()}
+		msg  = r.p.args.Msg
+		opts = &mpather.JgroupOpts{
+			CTs:      []string{fs.ObjectType},
+			VisitObj: v.do,
+			Slab:     slab,
+			Parallel: 1,
+			DoLoad:   mpather.Load,
+			Throttle: true,
+		}
+	)
+	if msg.HashPrefix == nil {
+		opts.Prefix = msg.Prepend + msg.Prefix
+	}
+	opts.Bck.Copy(r.p.args.BckTo.Bucket())
+	v.joggers = mpather.NewJoggerGroup(opts, config, nil)
+	return v
+}
+
+// record destination object stored by this target
+func (v *tcbVerify) add(objName string) {
+	v.filter.Insert(v.r.p.args.BckTo.MakeUname(objName))
+}
+
+// walk local destination and wait for: joggers || parent-aborted
+func (v *tcbVerify) run() error {
+	v.joggers.Run()
+	select {
+	case <-v.r.ChanAbort():
+		v.joggers.Stop()
+		return nil
+	case <-v.joggers.ListenFinished():
+	}
+	err := v.joggers.Stop()
+	if n := v.mismatch.Load(); n > 0 {
+		nlog.Warningln(v.r.Name(), "verification: [ verified", v.verified.Load(), "mismatched", n,
+			"re-copied", v.recopied.Load(), "]", v.mismatched())
+	}
+	v.filter.Reset()
+	return err
+}
+
+func (v *tcbVerify) do(dst *core.LOM, buf []byte) error {
+	if !v.filter.Lookup(cos.UnsafeB(dst.Uname())) {
+		return nil
+	}
+	stored := dst.Checksum()
+	if stored.IsEmpty() || stored.Ty() == cos.ChecksumNone {
+		return nil // nothing to compare with
+	}
+	dst.Lock(false)
+	comp, err := dst.ComputeCksum(stored.Ty())
+	dst.Unlock(false)
+	if err != nil {
+		if cos.IsNotExist(err, 0) {
+			return nil
+		}
+		return err
+	}
+	v.verified.Inc()
+	if comp.Equal(stored) {
+		return nil
+	}
+
+	// mismatch
+	v.mismatch.Inc()
+	v.mu.Lock()
+	if len(v.names) < tcbMaxMismatched {
+		v.names = append(v.names, dst.ObjName)
+	}
+	v.mu.Unlock()
+
+	errCksum := cos.NewErrDataCksum(&comp.Cksum, stored, dst.Cname())
+	if v.recopy(dst, buf) {
+		v.recopied.Inc()
+		return nil
+	}
+	v.r.AddErr(errCksum, 0)
+	return nil
+}
+
+// remove corrupted destination and copy the (local) source again
+func (v *tcbVerify) recopy(dst *core.LOM, buf []byte) bool {
+	var (
+		r    = v.r
+		name = r.srcName(dst.ObjName)
+	)
+	if name == "" {
+		return false
+	}
+	src := core.AllocLOM(name)
+	defer core.FreeLOM(src)
+	if src.InitBck(r.p.args.BckFrom.Bucket()) != nil {
+		return false
+	}
+	if _, local, err := src.HrwTarget(core.T.Sowner().Get()); err != nil || !local {
+		return false
+	}
+	if src.Load(false /*cache it*/, false /*locked*/) != nil {
+		return false
+	}
+
+	// otherwise, the copier would find the (metadata-wise) identical destination and do nothing
+	dst.Lock(true)
+	err := dst.RemoveObj()
+	dst.Unlock(true)
+	if err != nil {
+		return false
+	}
+
+	coiParams := r.newCOI(src, buf, dst.ObjName)
+	_, err = gcoi.CopyObject(src, r.dm, coiParams)
+	FreeCOI(coiParams)
+	if err != nil {
+		r.AddErr(err, 0)
+		return false
+	}
+	if cmn.Rom.FastV(4, cos.SmoduleXs) {
+		nlog.Infoln(r.Name(), "verification: re-copied", src.Cname(), "=>", dst.Cname())
+	}
+	return true
+}
+
+func (v *tcbVerify) mismatched() []string {
+	v.mu.Lock()
+	names := append([]string(nil), v.names...)
+	v.mu.Unlock()
+	sort.Strings(names)
+	return names
+}
@@ -37,7 +37,7 @@ func init() {
 	cmn.GCO.CommitUpdate(config)
 
 	xreg.Init()
-	xs.Treg(nil)
+	xs.Treg(nil, nil)
 	fs.TestNew(nil)
 }
 
@@ -209,6 +209,81 @@ func TestXactionAbortBuckets(t *testing.T) {
 	tassert.Errorf(t, xactBck.IsAborted(), "AbortAllGlobal: expected bucket xaction to be aborted")
 }
 
+func TestXactionPauseResume(t *testing.T) {
+	xctn := mock.NewXact(apc.ActCopyBck)
+	tassert.Fatalf(t, xctn.Pause(), "expected %s to pause", xctn)
+	tassert.Errorf(t, !xctn.Pause(), "expected %s to be already paused", xctn)
+	tassert.Errorf(t, xctn.IsPaused() && xctn.Snap().IsPaused(), "expected %s to be paused", xctn)
+
+	done := make(chan struct{})
+	go func() {
+		xctn.WaitPaused()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("expected WaitPaused to block")
+	case <-time.After(100 * time.Millisecond):
+	}
+	tassert.Fatalf(t, xctn.Resume(), "expected %s to resume", xctn)
+	<-done
+	tassert.Errorf(t, !xctn.IsPaused() && !xctn.Resume(), "expected %s to be running", xctn)
+
+	// abort unblocks, and cannot pause aborted
+	tassert.Fatalf(t, xctn.Pause(), "expected %s to pause", xctn)
+	done = make(chan struct{})
+	go func() {
+		xctn.WaitPaused()
+		close(done)
+	}()
+	xctn.Abort(errors.New("test-abort-paused"))
+	<-done
+	tassert.Errorf(t, !xctn.IsPaused() && !xctn.Pause(), "expected aborted %s not to pause", xctn)
+
+	// not pausable
+	xreg.TestReset()
+	_, err := xreg.DoPause(xreg.Flt{Kind: apc.ActLRU})
+	tassert.Errorf(t, err != nil, "expected %q not to be pausable", apc.ActLRU)
+}
+
+func TestXactionProgress(t *testing.T) {
+	xctn := mock.NewXact(apc.ActCopyBck)
+	tassert.Errorf(t, xctn.Snap().Progress == nil, "expected no progress until reported")
+
+	xctn.AddTotal(4, 4000)
+	xctn.ObjsAdd(1, 1000)
+	xctn.OutObjsAdd(1, 1000)
+	xctn.AddErr(errors.New("test-progress-err"))
+	time.Sleep(10 * time.Millisecond)
+
+	p := xctn.Snap().Progress
+	tassert.Fatalf(t, p != nil, "expected progress")
+	tassert.Errorf(t, p.TotalObjs == 4 && p.Objs == 2 && p.Bytes == 2000 && p.Errs == 1, "unexpected %+v", p)
+	tassert.Errorf(t, p.Percent() == 50, "expected 50%%, got %f", p.Percent())
+	tassert.Errorf(t, !p.Final && p.ETA == 0, "expected unknown ETA while discovering: %+v", p)
+
+	xctn.TotalFinal()
+	p = xctn.Snap().Progress
+	tassert.Errorf(t, p.Final && p.ETA > 0, "expected ETA: %+v", p)
+
+	// cluster-wide
+	other := *p
+	other.ETA, other.Final = time.Hour, false
+	xs := xact.MultiSnap{
+		"t1": []*core.Snap{{ID: xctn.ID(), Progress: p}},
+		"t2": []*core.Snap{{ID: xctn.ID(), Progress: &other}},
+	}
+	total := xs.Progress(xctn.ID())
+	tassert.Fatalf(t, total != nil, "expected cluster-wide progress")
+	tassert.Errorf(t, total.TotalObjs == 8 && total.Bytes == 4000 && total.ETA == time.Hour && !total.Final,
+		"unexpected %+v", total)
+
+	// all processed
+	xctn.ObjsAdd(2, 2000)
+	p = xctn.Snap().Progress
+	tassert.Errorf(t, p.Percent() == 100 && p.ETA == 0, "expected 100%% done: %+v", p)
+}
+
 // TODO: extend this to include all cases of the Query
 func TestXactionQueryFinished(t *testing.T) {
 	type testConfig struct {